@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX idx_changelog_object_type on changelog (store, object_type, ulid);
+
+-- +goose Down
+DROP INDEX idx_changelog_object_type on changelog;
@@ -0,0 +1,8 @@
+-- +goose NO TRANSACTION
+-- The index is built without locking the writes to the changelog, which cannot be done in a transaction.
+
+-- +goose Up
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_changelog_object_type on changelog (store, object_type, ulid);
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_changelog_object_type;
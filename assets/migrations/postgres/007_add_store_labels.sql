@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE store_label (
+    store CHAR(26) NOT NULL,
+    label_key TEXT NOT NULL,
+    label_value TEXT NOT NULL,
+    PRIMARY KEY (store, label_key)
+);
+
+CREATE INDEX idx_store_label_key_value on store_label (label_key, label_value);
+
+-- +goose Down
+DROP TABLE store_label;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE job (
+    store CHAR(26) NOT NULL,
+    id CHAR(26) NOT NULL,
+    type TEXT NOT NULL,
+    params BYTEA,
+    status TEXT NOT NULL,
+    progress BIGINT NOT NULL DEFAULT 0,
+    result BYTEA,
+    error TEXT,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (store, id)
+);
+
+-- +goose Down
+DROP TABLE job;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE job (
+    store CHAR(26) NOT NULL,
+    id CHAR(26) NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    params BLOB,
+    status VARCHAR(16) NOT NULL,
+    progress BIGINT NOT NULL DEFAULT 0,
+    result BLOB,
+    error TEXT,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (store, id)
+);
+
+-- +goose Down
+DROP TABLE job;
@@ -30,5 +30,8 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 
 		util.MustBindPFlag(verboseMigrationFlag, flags.Lookup(verboseMigrationFlag))
 		util.MustBindEnv(verboseMigrationFlag, "OPENFGA_VERBOSE")
+
+		util.MustBindPFlag(tuplePartitionsFlag, flags.Lookup(tuplePartitionsFlag))
+		util.MustBindEnv(tuplePartitionsFlag, "OPENFGA_POSTGRES_TUPLE_PARTITIONS")
 	}
 }
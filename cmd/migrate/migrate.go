@@ -19,6 +19,7 @@ const (
 	versionFlag           = "version"
 	timeoutFlag           = "timeout"
 	verboseMigrationFlag  = "verbose"
+	tuplePartitionsFlag   = "postgres-tuple-partitions"
 )
 
 func NewMigrateCommand() *cobra.Command {
@@ -39,6 +40,7 @@ func NewMigrateCommand() *cobra.Command {
 	flags.Uint(versionFlag, 0, "the version to migrate to (if omitted the latest schema will be used)")
 	flags.Duration(timeoutFlag, 1*time.Minute, "a timeout for the time it takes the migrate process to connect to the database")
 	flags.Bool(verboseMigrationFlag, false, "enable verbose migration logs (default false)")
+	flags.Uint(tuplePartitionsFlag, 0, "(optional) hash-partition the tuple table of a 'postgres' datastore by store into this number of partitions, when migrating it to version 9 or later. Existing tuples are copied into the partitions in batches, and the partitions cannot be changed afterwards. A datastore already migrated to version 9 must be migrated down to version 8 first (default 0, not partitioned)")
 
 	// NOTE: if you add a new flag here, update the function below, too
 
@@ -55,6 +57,7 @@ func runMigration(_ *cobra.Command, _ []string) error {
 	verbose := viper.GetBool(verboseMigrationFlag)
 	username := viper.GetString(datastoreUsernameFlag)
 	password := viper.GetString(datastorePasswordFlag)
+	tuplePartitions := viper.GetUint(tuplePartitionsFlag)
 
 	cfg := migrate.MigrationConfig{
 		Engine:        engine,
@@ -64,6 +67,8 @@ func runMigration(_ *cobra.Command, _ []string) error {
 		Verbose:       verbose,
 		Username:      username,
 		Password:      password,
+
+		PostgresTuplePartitions: tuplePartitions,
 	}
 	return migrate.RunMigrations(cfg)
 }
@@ -25,6 +25,7 @@ func TestMigrateCommandNoConfigDefaultValues(t *testing.T) {
 		require.Equal(t, uint(0), viper.GetUint(versionFlag))
 		require.Equal(t, defaultDuration, viper.GetDuration(timeoutFlag))
 		require.False(t, viper.GetBool(verboseMigrationFlag))
+		require.Zero(t, viper.GetUint(tuplePartitionsFlag))
 		return nil
 	}
 
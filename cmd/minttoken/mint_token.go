@@ -0,0 +1,87 @@
+// Package minttoken contains the command to mint API tokens bound to a store.
+package minttoken
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/openfga/openfga/cmd/util"
+	"github.com/openfga/openfga/internal/authn/storetoken"
+)
+
+const (
+	signingKeyFlag = "signing-key"
+	storeIDFlag    = "store-id"
+	scopesFlag     = "scopes"
+	ttlFlag        = "ttl"
+
+	signingKeysEnvKey = "authn.storetoken.signingKeys"
+)
+
+func NewMintTokenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mint-store-token",
+		Short: "Mint an API token bound to a store",
+		Long: "Mint an API token that is only valid for the given store and scopes (read, write, admin).\n" +
+			"The server must run with '--authn-method=storetoken' and the signing key must be one of its '--authn-storetoken-signing-keys'.",
+		RunE: runMintToken,
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.String(signingKeyFlag, "", "the key used to sign the token. Defaults to the first key of OPENFGA_AUTHN_STORETOKEN_SIGNING_KEYS")
+	flags.String(storeIDFlag, "", "the id of the store the token grants access to")
+	flags.StringSlice(scopesFlag, []string{string(storetoken.ScopeRead)}, "the scopes granted by the token: read, write and/or admin")
+	flags.Duration(ttlFlag, 0, "the time after which the token expires. If 0, the token does not expire")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PreRun = bindRunFlagsFunc(flags)
+
+	return cmd
+}
+
+// bindRunFlagsFunc binds the cobra cmd flags to the equivalent config value being managed
+// by viper. This bridges the config between cobra flags and viper flags.
+func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		util.MustBindPFlag(signingKeyFlag, flags.Lookup(signingKeyFlag))
+		util.MustBindPFlag(storeIDFlag, flags.Lookup(storeIDFlag))
+		util.MustBindPFlag(scopesFlag, flags.Lookup(scopesFlag))
+		util.MustBindPFlag(ttlFlag, flags.Lookup(ttlFlag))
+		util.MustBindEnv(signingKeysEnvKey, "OPENFGA_AUTHN_STORETOKEN_SIGNING_KEYS")
+	}
+}
+
+func runMintToken(cmd *cobra.Command, _ []string) error {
+	signingKey := viper.GetString(signingKeyFlag)
+	if signingKey == "" {
+		if keys := viper.GetStringSlice(signingKeysEnvKey); len(keys) > 0 {
+			signingKey = keys[0]
+		}
+	}
+	if signingKey == "" {
+		return errors.New("missing signing key")
+	}
+
+	var scopes []storetoken.Scope
+	for _, s := range viper.GetStringSlice(scopesFlag) {
+		scope, err := storetoken.ParseScope(s)
+		if err != nil {
+			return err
+		}
+		scopes = append(scopes, scope)
+	}
+
+	token, err := storetoken.Mint(signingKey, viper.GetString(storeIDFlag), scopes, viper.GetDuration(ttlFlag))
+	if err != nil {
+		return fmt.Errorf("failed to mint store token: %w", err)
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), token)
+	return err
+}
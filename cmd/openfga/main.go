@@ -6,7 +6,11 @@ import (
 
 	"github.com/openfga/openfga/cmd"
 	"github.com/openfga/openfga/cmd/migrate"
+	"github.com/openfga/openfga/cmd/minttoken"
+	"github.com/openfga/openfga/cmd/replay"
 	"github.com/openfga/openfga/cmd/run"
+	"github.com/openfga/openfga/cmd/supportbundle"
+	"github.com/openfga/openfga/cmd/syncstore"
 	"github.com/openfga/openfga/cmd/validatemodels"
 )
 
@@ -22,6 +26,18 @@ func main() {
 	validateModelsCmd := validatemodels.NewValidateCommand()
 	rootCmd.AddCommand(validateModelsCmd)
 
+	syncStoreCmd := syncstore.NewSyncStoreCommand()
+	rootCmd.AddCommand(syncStoreCmd)
+
+	mintTokenCmd := minttoken.NewMintTokenCommand()
+	rootCmd.AddCommand(mintTokenCmd)
+
+	supportBundleCmd := supportbundle.NewSupportBundleCommand()
+	rootCmd.AddCommand(supportBundleCmd)
+
+	replayCmd := replay.NewReplayCommand()
+	rootCmd.AddCommand(replayCmd)
+
 	versionCmd := cmd.NewVersionCommand()
 	rootCmd.AddCommand(versionCmd)
 
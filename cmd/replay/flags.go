@@ -0,0 +1,22 @@
+package replay
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openfga/openfga/cmd/util"
+)
+
+// bindRunFlags binds the cobra cmd flags to the equivalent config value being managed
+// by viper. This bridges the config between cobra flags and viper flags.
+func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		util.MustBindPFlag(logFileFlag, flags.Lookup(logFileFlag))
+		util.MustBindPFlag(targetAddrFlag, flags.Lookup(targetAddrFlag))
+		util.MustBindPFlag(targetTLSFlag, flags.Lookup(targetTLSFlag))
+		util.MustBindPFlag(apiTokenFlag, flags.Lookup(apiTokenFlag))
+		util.MustBindPFlag(authorizationModelIDFlag, flags.Lookup(authorizationModelIDFlag))
+		util.MustBindPFlag(rateFlag, flags.Lookup(rateFlag))
+		util.MustBindPFlag(concurrencyFlag, flags.Lookup(concurrencyFlag))
+	}
+}
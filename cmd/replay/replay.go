@@ -0,0 +1,403 @@
+// Package replay contains the command to replay the Check and ListObjects requests of a server log against a server.
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+)
+
+const (
+	logFileFlag              = "log-file"
+	targetAddrFlag           = "target-addr"
+	targetTLSFlag            = "target-tls"
+	apiTokenFlag             = "api-token"
+	authorizationModelIDFlag = "authorization-model-id"
+	rateFlag                 = "rate"
+	concurrencyFlag          = "concurrency"
+
+	// requestCompleteMessage is the message of the log entries written by the logging interceptor once a request
+	// completes, with its request, response and duration.
+	requestCompleteMessage = "grpc_req_complete"
+)
+
+func NewReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay the Check and ListObjects requests of a server log against a server. NOTE: this command is in beta and may be removed in future releases.",
+		Long: "Read the requests logged by a server with --log-format=json and --log-level=info, replay their Check and ListObjects requests " +
+			"against a target server, possibly on another authorization model, at a configurable rate, and report the requests whose " +
+			"result differs from the logged one along with the latency of both runs.\n" +
+			"NOTE: this command is in beta and may be removed in future releases.",
+		RunE: runReplay,
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.String(logFileFlag, "-", "the log file to read the requests from, '-' for the standard input")
+	flags.String(targetAddrFlag, "127.0.0.1:8081", "the gRPC address of the target server")
+	flags.Bool(targetTLSFlag, false, "connect to the target server over TLS")
+	flags.String(apiTokenFlag, "", "the bearer token authenticating the requests to the target server")
+	flags.String(authorizationModelIDFlag, "", "the authorization model id to replay the requests on, instead of the logged one")
+	flags.Float64(rateFlag, 10, "the number of requests replayed per second, 0 for no limit")
+	flags.Int(concurrencyFlag, 10, "the maximum number of requests in flight")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PreRun = bindRunFlagsFunc(flags)
+
+	return cmd
+}
+
+// Options are the options of a replay.
+type Options struct {
+	// AuthorizationModelID, if set, replaces the authorization model id of the requests.
+	AuthorizationModelID string
+	// Rate is the number of requests replayed per second. 0 means no limit.
+	Rate float64
+	// Concurrency is the maximum number of requests in flight.
+	Concurrency int
+}
+
+// Difference is a request whose replayed result differs from the logged one.
+type Difference struct {
+	// Line is the line of the request in the log.
+	Line     int             `json:"line"`
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Logged   json.RawMessage `json:"logged"`
+	Replayed json.RawMessage `json:"replayed,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Latency compares the latency, in milliseconds, of the logged and replayed requests of a method.
+type Latency struct {
+	Count          int     `json:"count"`
+	LoggedMeanMs   float64 `json:"logged_mean_ms"`
+	ReplayedMeanMs float64 `json:"replayed_mean_ms"`
+	LoggedP95Ms    float64 `json:"logged_p95_ms"`
+	ReplayedP95Ms  float64 `json:"replayed_p95_ms"`
+	MeanDeltaMs    float64 `json:"mean_delta_ms"`
+	P95DeltaMs     float64 `json:"p95_delta_ms"`
+}
+
+// Result is the report of a replay.
+type Result struct {
+	Replayed int `json:"replayed"`
+	// Skipped is the number of logged Check and ListObjects requests that could not be replayed, e.g. because
+	// they failed.
+	Skipped     int                 `json:"skipped"`
+	Differences []Difference        `json:"differences"`
+	Latencies   map[string]*Latency `json:"latencies"`
+}
+
+func runReplay(cmd *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	in := cmd.InOrStdin()
+	if logFile := viper.GetString(logFileFlag); logFile != "-" {
+		f, err := os.Open(logFile)
+		if err != nil {
+			return fmt.Errorf("failed to open the log file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	creds := insecure.NewCredentials()
+	if viper.GetBool(targetTLSFlag) {
+		creds = credentials.NewClientTLSFromCert(nil, "")
+	}
+	conn, err := grpc.NewClient(viper.GetString(targetAddrFlag), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return fmt.Errorf("failed to connect to the target server: %w", err)
+	}
+	defer conn.Close()
+
+	if apiToken := viper.GetString(apiTokenFlag); apiToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+apiToken)
+	}
+
+	result, err := Replay(ctx, openfgav1.NewOpenFGAServiceClient(conn), in, Options{
+		AuthorizationModelID: viper.GetString(authorizationModelIDFlag),
+		Rate:                 viper.GetFloat64(rateFlag),
+		Concurrency:          viper.GetInt(concurrencyFlag),
+	})
+	if err != nil {
+		return err
+	}
+
+	marshalled, err := json.MarshalIndent(result, " ", "    ")
+	if err != nil {
+		return fmt.Errorf("error gathering replay results: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(marshalled))
+
+	return nil
+}
+
+// logEntry holds the fields of a request complete log entry.
+type logEntry struct {
+	Message       string          `json:"msg"`
+	Method        string          `json:"grpc_method"`
+	Code          int32           `json:"grpc_code"`
+	RawRequest    json.RawMessage `json:"raw_request"`
+	RawResponse   json.RawMessage `json:"raw_response"`
+	QueryDuration string          `json:"query_duration_ms"`
+}
+
+// loggedRequest is a request to replay.
+type loggedRequest struct {
+	line     int
+	method   string
+	request  json.RawMessage
+	response json.RawMessage
+	duration float64
+}
+
+// Replay replays the Check and ListObjects requests logged in r with client, and compares their results and
+// latency with the logged ones. Other log entries are ignored.
+func Replay(ctx context.Context, client openfgav1.OpenFGAServiceClient, r io.Reader, opts Options) (*Result, error) {
+	limit := rate.Inf
+	if opts.Rate > 0 {
+		limit = rate.Limit(opts.Rate)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+
+	result := &Result{
+		Differences: []Difference{},
+		Latencies:   map[string]*Latency{},
+	}
+	replayedDurations := map[string][]float64{}
+	loggedDurations := map[string][]float64{}
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(opts.Concurrency, 1))
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		req, ok := parseLogEntry(scanner.Bytes(), line)
+		if !ok {
+			continue
+		}
+		if req == nil {
+			mu.Lock()
+			result.Skipped++
+			mu.Unlock()
+			continue
+		}
+
+		if err := limiter.Wait(gctx); err != nil {
+			break
+		}
+
+		g.Go(func() error {
+			start := time.Now()
+			replayed, err := replayRequest(gctx, client, req, opts.AuthorizationModelID)
+			elapsed := float64(time.Since(start).Microseconds()) / 1000
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				result.Differences = append(result.Differences, Difference{
+					Line:    req.line,
+					Method:  req.method,
+					Request: req.request,
+					Logged:  req.response,
+					Error:   err.Error(),
+				})
+				return nil
+			}
+
+			result.Replayed++
+			replayedDurations[req.method] = append(replayedDurations[req.method], elapsed)
+			loggedDurations[req.method] = append(loggedDurations[req.method], req.duration)
+
+			equal, err := sameResult(req.method, req.response, replayed)
+			if err != nil || !equal {
+				diff := Difference{
+					Line:     req.line,
+					Method:   req.method,
+					Request:  req.request,
+					Logged:   req.response,
+					Replayed: replayed,
+				}
+				if err != nil {
+					diff.Error = err.Error()
+				}
+				result.Differences = append(result.Differences, diff)
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the log: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(result.Differences, func(a, b Difference) int {
+		return a.Line - b.Line
+	})
+	for method, replayed := range replayedDurations {
+		result.Latencies[method] = newLatency(loggedDurations[method], replayed)
+	}
+
+	return result, nil
+}
+
+// parseLogEntry returns the request to replay of a log line. It returns false if the line is not the log entry of
+// a Check or ListObjects request, and nil if the request cannot be replayed.
+func parseLogEntry(data []byte, line int) (*loggedRequest, bool) {
+	var entry logEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Message != requestCompleteMessage {
+		return nil, false
+	}
+	if entry.Method != apimethod.Check.String() && entry.Method != apimethod.ListObjects.String() {
+		return nil, false
+	}
+
+	duration, err := strconv.ParseFloat(entry.QueryDuration, 64)
+	if err != nil || entry.Code != 0 || len(entry.RawRequest) == 0 || len(entry.RawResponse) == 0 {
+		return nil, true
+	}
+
+	return &loggedRequest{
+		line:     line,
+		method:   entry.Method,
+		request:  entry.RawRequest,
+		response: entry.RawResponse,
+		duration: duration,
+	}, true
+}
+
+func replayRequest(ctx context.Context, client openfgav1.OpenFGAServiceClient, req *loggedRequest, modelID string) (json.RawMessage, error) {
+	var resp proto.Message
+	switch req.method {
+	case apimethod.Check.String():
+		var checkReq openfgav1.CheckRequest
+		if err := protojson.Unmarshal(req.request, &checkReq); err != nil {
+			return nil, fmt.Errorf("invalid logged request: %w", err)
+		}
+		if modelID != "" {
+			checkReq.AuthorizationModelId = modelID
+		}
+		checkResp, err := client.Check(ctx, &checkReq)
+		if err != nil {
+			return nil, err
+		}
+		resp = checkResp
+	case apimethod.ListObjects.String():
+		var listObjectsReq openfgav1.ListObjectsRequest
+		if err := protojson.Unmarshal(req.request, &listObjectsReq); err != nil {
+			return nil, fmt.Errorf("invalid logged request: %w", err)
+		}
+		if modelID != "" {
+			listObjectsReq.AuthorizationModelId = modelID
+		}
+		listObjectsResp, err := client.ListObjects(ctx, &listObjectsReq)
+		if err != nil {
+			return nil, err
+		}
+		resp = listObjectsResp
+	}
+
+	return protojson.Marshal(resp)
+}
+
+// sameResult returns true if the logged and replayed responses of a method hold the same result. The objects of
+// ListObjects responses are compared regardless of their order.
+func sameResult(method string, logged, replayed json.RawMessage) (bool, error) {
+	switch method {
+	case apimethod.Check.String():
+		var loggedResp, replayedResp openfgav1.CheckResponse
+		if err := protojson.Unmarshal(logged, &loggedResp); err != nil {
+			return false, fmt.Errorf("invalid logged response: %w", err)
+		}
+		if err := protojson.Unmarshal(replayed, &replayedResp); err != nil {
+			return false, err
+		}
+		return loggedResp.GetAllowed() == replayedResp.GetAllowed(), nil
+	case apimethod.ListObjects.String():
+		var loggedResp, replayedResp openfgav1.ListObjectsResponse
+		if err := protojson.Unmarshal(logged, &loggedResp); err != nil {
+			return false, fmt.Errorf("invalid logged response: %w", err)
+		}
+		if err := protojson.Unmarshal(replayed, &replayedResp); err != nil {
+			return false, err
+		}
+		loggedObjects := slices.Sorted(slices.Values(loggedResp.GetObjects()))
+		replayedObjects := slices.Sorted(slices.Values(replayedResp.GetObjects()))
+		return slices.Equal(loggedObjects, replayedObjects), nil
+	default:
+		return false, fmt.Errorf("unsupported method '%s'", method)
+	}
+}
+
+func newLatency(logged, replayed []float64) *Latency {
+	latency := &Latency{
+		Count:          len(replayed),
+		LoggedMeanMs:   mean(logged),
+		ReplayedMeanMs: mean(replayed),
+		LoggedP95Ms:    percentile(logged, 0.95),
+		ReplayedP95Ms:  percentile(replayed, 0.95),
+	}
+	latency.MeanDeltaMs = latency.ReplayedMeanMs - latency.LoggedMeanMs
+	latency.P95DeltaMs = latency.ReplayedP95Ms - latency.LoggedP95Ms
+	return latency
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// percentile returns the p percentile of values, using the nearest rank method.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(values))
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
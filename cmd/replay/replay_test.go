@@ -0,0 +1,91 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+type fakeClient struct {
+	openfgav1.OpenFGAServiceClient
+	allowed map[string]bool
+	objects []string
+	modelID string
+}
+
+func (c *fakeClient) Check(_ context.Context, req *openfgav1.CheckRequest, _ ...grpc.CallOption) (*openfgav1.CheckResponse, error) {
+	c.modelID = req.GetAuthorizationModelId()
+	allowed, ok := c.allowed[req.GetTupleKey().GetObject()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown object")
+	}
+	return &openfgav1.CheckResponse{Allowed: allowed}, nil
+}
+
+func (c *fakeClient) ListObjects(_ context.Context, req *openfgav1.ListObjectsRequest, _ ...grpc.CallOption) (*openfgav1.ListObjectsResponse, error) {
+	c.modelID = req.GetAuthorizationModelId()
+	return &openfgav1.ListObjectsResponse{Objects: c.objects}, nil
+}
+
+func logLine(method string, code int, request, response, duration string) string {
+	return fmt.Sprintf(`{"level":"info","msg":"grpc_req_complete","grpc_method":%q,"grpc_code":%d,"raw_request":%s,"raw_response":%s,"query_duration_ms":%q}`,
+		method, code, request, response, duration)
+}
+
+func TestReplay(t *testing.T) {
+	log := strings.Join([]string{
+		`{"level":"info","msg":"starting openfga service..."}`,
+		logLine("Check", 0, `{"store_id":"01H","authorization_model_id":"01M","tuple_key":{"object":"document:1","relation":"viewer","user":"user:anne"}}`, `{"allowed":true}`, "4"),
+		logLine("Check", 0, `{"store_id":"01H","authorization_model_id":"01M","tuple_key":{"object":"document:2","relation":"viewer","user":"user:anne"}}`, `{"allowed":false}`, "6"),
+		logLine("Check", 0, `{"store_id":"01H","authorization_model_id":"01M","tuple_key":{"object":"document:3","relation":"viewer","user":"user:anne"}}`, `{"allowed":false}`, "6"),
+		logLine("Check", 2000, `{"store_id":"01H","tuple_key":{"object":"document:1","relation":"viewer","user":"user:anne"}}`, `{"code":"validation_error"}`, "1"),
+		logLine("ListObjects", 0, `{"store_id":"01H","type":"document","relation":"viewer","user":"user:anne"}`, `{"objects":["document:2","document:1"]}`, "10"),
+		logLine("Write", 0, `{"store_id":"01H"}`, `{}`, "3"),
+	}, "\n")
+
+	client := &fakeClient{
+		allowed: map[string]bool{"document:1": true, "document:2": true},
+		objects: []string{"document:1", "document:2"},
+	}
+
+	result, err := Replay(context.Background(), client, strings.NewReader(log), Options{
+		AuthorizationModelID: "01N",
+		Concurrency:          1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Replayed)
+	require.Equal(t, 1, result.Skipped)
+	require.Equal(t, "01N", client.modelID)
+
+	require.Len(t, result.Differences, 2)
+	require.Equal(t, 3, result.Differences[0].Line)
+	require.JSONEq(t, `{"allowed":true}`, string(result.Differences[0].Replayed))
+	require.Equal(t, 4, result.Differences[1].Line)
+	require.Contains(t, result.Differences[1].Error, "unknown object")
+
+	require.Len(t, result.Latencies, 2)
+	require.Equal(t, 2, result.Latencies["Check"].Count)
+	require.InDelta(t, 5, result.Latencies["Check"].LoggedMeanMs, 0.001)
+	require.InDelta(t, 6, result.Latencies["Check"].LoggedP95Ms, 0.001)
+	require.Equal(t, 1, result.Latencies["ListObjects"].Count)
+}
+
+func TestPercentile(t *testing.T) {
+	require.InDelta(t, 0, percentile(nil, 0.95), 0)
+	require.InDelta(t, 3, percentile([]float64{3}, 0.95), 0)
+	require.InDelta(t, 95, percentile(func() []float64 {
+		values := make([]float64, 0, 100)
+		for i := 100; i > 0; i-- {
+			values = append(values, float64(i))
+		}
+		return values
+	}(), 0.95), 0)
+}
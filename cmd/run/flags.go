@@ -39,6 +39,33 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 
 		command.MarkFlagsRequiredTogether("grpc-tls-enabled", "grpc-tls-cert", "grpc-tls-key")
 
+		util.MustBindPFlag("grpc.maxRecvMsgSizeBytes", flags.Lookup("grpc-max-recv-msg-size-bytes"))
+		util.MustBindEnv("grpc.maxRecvMsgSizeBytes", "OPENFGA_GRPC_MAX_RECV_MSG_SIZE_BYTES")
+
+		util.MustBindPFlag("grpc.maxSendMsgSizeBytes", flags.Lookup("grpc-max-send-msg-size-bytes"))
+		util.MustBindEnv("grpc.maxSendMsgSizeBytes", "OPENFGA_GRPC_MAX_SEND_MSG_SIZE_BYTES")
+
+		util.MustBindPFlag("grpc.maxConcurrentStreams", flags.Lookup("grpc-max-concurrent-streams"))
+		util.MustBindEnv("grpc.maxConcurrentStreams", "OPENFGA_GRPC_MAX_CONCURRENT_STREAMS")
+
+		util.MustBindPFlag("grpc.keepalive.time", flags.Lookup("grpc-keepalive-time"))
+		util.MustBindEnv("grpc.keepalive.time", "OPENFGA_GRPC_KEEPALIVE_TIME")
+
+		util.MustBindPFlag("grpc.keepalive.timeout", flags.Lookup("grpc-keepalive-timeout"))
+		util.MustBindEnv("grpc.keepalive.timeout", "OPENFGA_GRPC_KEEPALIVE_TIMEOUT")
+
+		util.MustBindPFlag("grpc.keepalive.maxConnectionAge", flags.Lookup("grpc-keepalive-max-connection-age"))
+		util.MustBindEnv("grpc.keepalive.maxConnectionAge", "OPENFGA_GRPC_KEEPALIVE_MAX_CONNECTION_AGE")
+
+		util.MustBindPFlag("grpc.keepalive.maxConnectionAgeGrace", flags.Lookup("grpc-keepalive-max-connection-age-grace"))
+		util.MustBindEnv("grpc.keepalive.maxConnectionAgeGrace", "OPENFGA_GRPC_KEEPALIVE_MAX_CONNECTION_AGE_GRACE")
+
+		util.MustBindPFlag("grpc.keepalive.minTime", flags.Lookup("grpc-keepalive-min-time"))
+		util.MustBindEnv("grpc.keepalive.minTime", "OPENFGA_GRPC_KEEPALIVE_MIN_TIME")
+
+		util.MustBindPFlag("grpc.keepalive.permitWithoutStream", flags.Lookup("grpc-keepalive-permit-without-stream"))
+		util.MustBindEnv("grpc.keepalive.permitWithoutStream", "OPENFGA_GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM")
+
 		util.MustBindPFlag("http.enabled", flags.Lookup("http-enabled"))
 		util.MustBindEnv("http.enabled", "OPENFGA_HTTP_ENABLED")
 
@@ -65,12 +92,24 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("http.corsAllowedHeaders", flags.Lookup("http-cors-allowed-headers"))
 		util.MustBindEnv("http.corsAllowedHeaders", "OPENFGA_HTTP_CORS_ALLOWED_HEADERS", "OPENFGA_HTTP_CORSALLOWEDHEADERS")
 
+		util.MustBindPFlag("http.connectEnabled", flags.Lookup("http-connect-enabled"))
+		util.MustBindEnv("http.connectEnabled", "OPENFGA_HTTP_CONNECT_ENABLED")
+
+		util.MustBindPFlag("http.grpcWebEnabled", flags.Lookup("http-grpc-web-enabled"))
+		util.MustBindEnv("http.grpcWebEnabled", "OPENFGA_HTTP_GRPC_WEB_ENABLED")
+
 		util.MustBindPFlag("authn.method", flags.Lookup("authn-method"))
 		util.MustBindEnv("authn.method", "OPENFGA_AUTHN_METHOD")
 
 		util.MustBindPFlag("authn.preshared.keys", flags.Lookup("authn-preshared-keys"))
 		util.MustBindEnv("authn.preshared.keys", "OPENFGA_AUTHN_PRESHARED_KEYS")
 
+		util.MustBindPFlag("authn.storetoken.signingKeys", flags.Lookup("authn-storetoken-signing-keys"))
+		util.MustBindEnv("authn.storetoken.signingKeys", "OPENFGA_AUTHN_STORETOKEN_SIGNING_KEYS")
+
+		util.MustBindPFlag("authn.mtls.clientCA", flags.Lookup("authn-mtls-client-ca"))
+		util.MustBindEnv("authn.mtls.clientCA", "OPENFGA_AUTHN_MTLS_CLIENT_CA")
+
 		util.MustBindPFlag("authn.oidc.audience", flags.Lookup("authn-oidc-audience"))
 		util.MustBindEnv("authn.oidc.audience", "OPENFGA_AUTHN_OIDC_AUDIENCE")
 
@@ -86,6 +125,15 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("authn.oidc.clientIdClaims", flags.Lookup("authn-oidc-client-id-claims"))
 		util.MustBindEnv("authn.oidc.clientIdClaims", "OPENFGA_AUTHN_OIDC_CLIENT_ID_CLAIMS")
 
+		util.MustBindPFlag("authn.oidc.storeIdClaim", flags.Lookup("authn-oidc-store-id-claim"))
+		util.MustBindEnv("authn.oidc.storeIdClaim", "OPENFGA_AUTHN_OIDC_STORE_ID_CLAIM")
+
+		util.MustBindPFlag("authn.oidc.additionalIssuers", flags.Lookup("authn-oidc-additional-issuers"))
+		util.MustBindEnv("authn.oidc.additionalIssuers", "OPENFGA_AUTHN_OIDC_ADDITIONAL_ISSUERS")
+
+		util.MustBindPFlag("authn.oidc.additionalAudiences", flags.Lookup("authn-oidc-additional-audiences"))
+		util.MustBindEnv("authn.oidc.additionalAudiences", "OPENFGA_AUTHN_OIDC_ADDITIONAL_AUDIENCES")
+
 		util.MustBindPFlag("datastore.engine", flags.Lookup("datastore-engine"))
 		util.MustBindEnv("datastore.engine", "OPENFGA_DATASTORE_ENGINE")
 
@@ -98,6 +146,21 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("datastore.password", flags.Lookup("datastore-password"))
 		util.MustBindEnv("datastore.password", "OPENFGA_DATASTORE_PASSWORD")
 
+		util.MustBindPFlag("datastore.usernameFile", flags.Lookup("datastore-username-file"))
+		util.MustBindEnv("datastore.usernameFile", "OPENFGA_DATASTORE_USERNAME_FILE")
+
+		util.MustBindPFlag("datastore.passwordFile", flags.Lookup("datastore-password-file"))
+		util.MustBindEnv("datastore.passwordFile", "OPENFGA_DATASTORE_PASSWORD_FILE")
+
+		util.MustBindPFlag("datastore.credentialsRefreshInterval", flags.Lookup("datastore-credentials-refresh-interval"))
+		util.MustBindEnv("datastore.credentialsRefreshInterval", "OPENFGA_DATASTORE_CREDENTIALS_REFRESH_INTERVAL")
+
+		util.MustBindPFlag("datastore.iamAuth.enabled", flags.Lookup("datastore-iam-auth-enabled"))
+		util.MustBindEnv("datastore.iamAuth.enabled", "OPENFGA_DATASTORE_IAM_AUTH_ENABLED")
+
+		util.MustBindPFlag("datastore.iamAuth.region", flags.Lookup("datastore-iam-auth-region"))
+		util.MustBindEnv("datastore.iamAuth.region", "OPENFGA_DATASTORE_IAM_AUTH_REGION")
+
 		util.MustBindPFlag("datastore.maxCacheSize", flags.Lookup("datastore-max-cache-size"))
 		util.MustBindEnv("datastore.maxCacheSize", "OPENFGA_DATASTORE_MAX_CACHE_SIZE", "OPENFGA_DATASTORE_MAXCACHESIZE")
 
@@ -113,6 +176,36 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("datastore.connMaxLifetime", flags.Lookup("datastore-conn-max-lifetime"))
 		util.MustBindEnv("datastore.connMaxLifetime", "OPENFGA_DATASTORE_CONN_MAX_LIFETIME", "OPENFGA_DATASTORE_CONNMAXLIFETIME")
 
+		util.MustBindPFlag("datastore.readReplicaURIs", flags.Lookup("datastore-read-replica-uris"))
+		util.MustBindEnv("datastore.readReplicaURIs", "OPENFGA_DATASTORE_READ_REPLICA_URIS")
+
+		util.MustBindPFlag("datastore.readTimeout", flags.Lookup("datastore-read-timeout"))
+		util.MustBindEnv("datastore.readTimeout", "OPENFGA_DATASTORE_READ_TIMEOUT")
+
+		util.MustBindPFlag("datastore.writeTimeout", flags.Lookup("datastore-write-timeout"))
+		util.MustBindEnv("datastore.writeTimeout", "OPENFGA_DATASTORE_WRITE_TIMEOUT")
+
+		util.MustBindPFlag("datastore.slowQueryThreshold", flags.Lookup("datastore-slow-query-threshold"))
+		util.MustBindEnv("datastore.slowQueryThreshold", "OPENFGA_DATASTORE_SLOW_QUERY_THRESHOLD")
+
+		util.MustBindPFlag("datastore.snapshotPath", flags.Lookup("datastore-snapshot-path"))
+		util.MustBindEnv("datastore.snapshotPath", "OPENFGA_DATASTORE_SNAPSHOT_PATH")
+
+		util.MustBindPFlag("datastore.snapshotInterval", flags.Lookup("datastore-snapshot-interval"))
+		util.MustBindEnv("datastore.snapshotInterval", "OPENFGA_DATASTORE_SNAPSHOT_INTERVAL")
+
+		util.MustBindPFlag("datastore.memoryMaxTuplesPerStore", flags.Lookup("datastore-memory-max-tuples-per-store"))
+		util.MustBindEnv("datastore.memoryMaxTuplesPerStore", "OPENFGA_DATASTORE_MEMORY_MAX_TUPLES_PER_STORE")
+
+		util.MustBindPFlag("datastore.memoryMaxBytes", flags.Lookup("datastore-memory-max-bytes"))
+		util.MustBindEnv("datastore.memoryMaxBytes", "OPENFGA_DATASTORE_MEMORY_MAX_BYTES")
+
+		util.MustBindPFlag("datastore.maxConcurrentReads", flags.Lookup("datastore-max-concurrent-reads"))
+		util.MustBindEnv("datastore.maxConcurrentReads", "OPENFGA_DATASTORE_MAX_CONCURRENT_READS")
+
+		util.MustBindPFlag("datastore.hedgeDelay", flags.Lookup("datastore-hedge-delay"))
+		util.MustBindEnv("datastore.hedgeDelay", "OPENFGA_DATASTORE_HEDGE_DELAY")
+
 		util.MustBindPFlag("datastore.metrics.enabled", flags.Lookup("datastore-metrics-enabled"))
 		util.MustBindEnv("datastore.metrics.enabled", "OPENFGA_DATASTORE_METRICS_ENABLED")
 
@@ -137,6 +230,57 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("log.timestampFormat", flags.Lookup("log-timestamp-format"))
 		util.MustBindEnv("log.timestampFormat", "OPENFGA_LOG_TIMESTAMP_FORMAT")
 
+		util.MustBindPFlag("log.sampling.enabled", flags.Lookup("log-sampling-enabled"))
+		util.MustBindEnv("log.sampling.enabled", "OPENFGA_LOG_SAMPLING_ENABLED")
+
+		util.MustBindPFlag("log.sampling.perSecond", flags.Lookup("log-sampling-per-second"))
+		util.MustBindEnv("log.sampling.perSecond", "OPENFGA_LOG_SAMPLING_PER_SECOND")
+
+		util.MustBindPFlag("log.sampling.thereafter", flags.Lookup("log-sampling-thereafter"))
+		util.MustBindEnv("log.sampling.thereafter", "OPENFGA_LOG_SAMPLING_THEREAFTER")
+
+		util.MustBindPFlag("log.outputPaths", flags.Lookup("log-output-paths"))
+		util.MustBindEnv("log.outputPaths", "OPENFGA_LOG_OUTPUT_PATHS")
+
+		util.MustBindPFlag("log.rotation.enabled", flags.Lookup("log-rotation-enabled"))
+		util.MustBindEnv("log.rotation.enabled", "OPENFGA_LOG_ROTATION_ENABLED")
+
+		util.MustBindPFlag("log.rotation.maxSize", flags.Lookup("log-rotation-max-size"))
+		util.MustBindEnv("log.rotation.maxSize", "OPENFGA_LOG_ROTATION_MAX_SIZE")
+
+		util.MustBindPFlag("log.rotation.maxAge", flags.Lookup("log-rotation-max-age"))
+		util.MustBindEnv("log.rotation.maxAge", "OPENFGA_LOG_ROTATION_MAX_AGE")
+
+		util.MustBindPFlag("log.rotation.maxBackups", flags.Lookup("log-rotation-max-backups"))
+		util.MustBindEnv("log.rotation.maxBackups", "OPENFGA_LOG_ROTATION_MAX_BACKUPS")
+
+		util.MustBindPFlag("log.rotation.compress", flags.Lookup("log-rotation-compress"))
+		util.MustBindEnv("log.rotation.compress", "OPENFGA_LOG_ROTATION_COMPRESS")
+
+		util.MustBindPFlag("log.security.enabled", flags.Lookup("log-security-enabled"))
+		util.MustBindEnv("log.security.enabled", "OPENFGA_LOG_SECURITY_ENABLED")
+
+		util.MustBindPFlag("log.security.format", flags.Lookup("log-security-format"))
+		util.MustBindEnv("log.security.format", "OPENFGA_LOG_SECURITY_FORMAT")
+
+		util.MustBindPFlag("log.security.outputPaths", flags.Lookup("log-security-output-paths"))
+		util.MustBindEnv("log.security.outputPaths", "OPENFGA_LOG_SECURITY_OUTPUT_PATHS")
+
+		util.MustBindPFlag("accessLog.enabled", flags.Lookup("access-log-enabled"))
+		util.MustBindEnv("accessLog.enabled", "OPENFGA_ACCESS_LOG_ENABLED")
+
+		util.MustBindPFlag("accessLog.fields", flags.Lookup("access-log-fields"))
+		util.MustBindEnv("accessLog.fields", "OPENFGA_ACCESS_LOG_FIELDS")
+
+		util.MustBindPFlag("accessLog.skippedMethods", flags.Lookup("access-log-skipped-methods"))
+		util.MustBindEnv("accessLog.skippedMethods", "OPENFGA_ACCESS_LOG_SKIPPED_METHODS")
+
+		util.MustBindPFlag("redaction.mode", flags.Lookup("redaction-mode"))
+		util.MustBindEnv("redaction.mode", "OPENFGA_REDACTION_MODE")
+
+		util.MustBindPFlag("redaction.hashKey", flags.Lookup("redaction-hash-key"))
+		util.MustBindEnv("redaction.hashKey", "OPENFGA_REDACTION_HASH_KEY")
+
 		util.MustBindPFlag("trace.enabled", flags.Lookup("trace-enabled"))
 		util.MustBindEnv("trace.enabled", "OPENFGA_TRACE_ENABLED")
 
@@ -149,6 +293,12 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("trace.sampleRatio", flags.Lookup("trace-sample-ratio"))
 		util.MustBindEnv("trace.sampleRatio", "OPENFGA_TRACE_SAMPLE_RATIO")
 
+		util.MustBindPFlag("trace.methodSampleRatios", flags.Lookup("trace-method-sample-ratios"))
+		util.MustBindEnv("trace.methodSampleRatios", "OPENFGA_TRACE_METHOD_SAMPLE_RATIOS")
+
+		util.MustBindPFlag("trace.sampleErrors", flags.Lookup("trace-sample-errors"))
+		util.MustBindEnv("trace.sampleErrors", "OPENFGA_TRACE_SAMPLE_ERRORS")
+
 		util.MustBindPFlag("trace.serviceName", flags.Lookup("trace-service-name"))
 		util.MustBindEnv("trace.serviceName", "OPENFGA_TRACE_SERVICE_NAME")
 
@@ -158,9 +308,45 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("metrics.addr", flags.Lookup("metrics-addr"))
 		util.MustBindEnv("metrics.addr", "OPENFGA_METRICS_ADDR")
 
+		util.MustBindPFlag("admin.enabled", flags.Lookup("admin-enabled"))
+		util.MustBindEnv("admin.enabled", "OPENFGA_ADMIN_ENABLED")
+
+		util.MustBindPFlag("admin.addr", flags.Lookup("admin-addr"))
+		util.MustBindEnv("admin.addr", "OPENFGA_ADMIN_ADDR")
+
+		util.MustBindPFlag("admin.keys", flags.Lookup("admin-keys"))
+		util.MustBindEnv("admin.keys", "OPENFGA_ADMIN_KEYS")
+
 		util.MustBindPFlag("metrics.enableRPCHistograms", flags.Lookup("metrics-enable-rpc-histograms"))
 		util.MustBindEnv("metrics.enableRPCHistograms", "OPENFGA_METRICS_ENABLE_RPC_HISTOGRAMS")
 
+		util.MustBindPFlag("metrics.otlp.enabled", flags.Lookup("metrics-otlp-enabled"))
+		util.MustBindEnv("metrics.otlp.enabled", "OPENFGA_METRICS_OTLP_ENABLED")
+
+		util.MustBindPFlag("metrics.otlp.endpoint", flags.Lookup("metrics-otlp-endpoint"))
+		util.MustBindEnv("metrics.otlp.endpoint", "OPENFGA_METRICS_OTLP_ENDPOINT")
+
+		util.MustBindPFlag("metrics.otlp.protocol", flags.Lookup("metrics-otlp-protocol"))
+		util.MustBindEnv("metrics.otlp.protocol", "OPENFGA_METRICS_OTLP_PROTOCOL")
+
+		util.MustBindPFlag("metrics.otlp.interval", flags.Lookup("metrics-otlp-interval"))
+		util.MustBindEnv("metrics.otlp.interval", "OPENFGA_METRICS_OTLP_INTERVAL")
+
+		util.MustBindPFlag("metrics.otlp.headers", flags.Lookup("metrics-otlp-headers"))
+		util.MustBindEnv("metrics.otlp.headers", "OPENFGA_METRICS_OTLP_HEADERS")
+
+		util.MustBindPFlag("metrics.otlp.tls.enabled", flags.Lookup("metrics-otlp-tls-enabled"))
+		util.MustBindEnv("metrics.otlp.tls.enabled", "OPENFGA_METRICS_OTLP_TLS_ENABLED")
+
+		util.MustBindPFlag("metrics.storeIDLabel.enabled", flags.Lookup("metrics-store-id-label-enabled"))
+		util.MustBindEnv("metrics.storeIDLabel.enabled", "OPENFGA_METRICS_STORE_ID_LABEL_ENABLED")
+
+		util.MustBindPFlag("metrics.storeIDLabel.stores", flags.Lookup("metrics-store-id-label-stores"))
+		util.MustBindEnv("metrics.storeIDLabel.stores", "OPENFGA_METRICS_STORE_ID_LABEL_STORES")
+
+		util.MustBindPFlag("metrics.storeIDLabel.limit", flags.Lookup("metrics-store-id-label-limit"))
+		util.MustBindEnv("metrics.storeIDLabel.limit", "OPENFGA_METRICS_STORE_ID_LABEL_LIMIT")
+
 		util.MustBindPFlag("maxChecksPerBatchCheck", flags.Lookup("max-checks-per-batch-check"))
 		util.MustBindEnv("maxChecksPerBatchCheck", "OPENFGA_MAX_CHECKS_PER_BATCH_CHECK")
 
@@ -170,6 +356,9 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("maxTuplesPerWrite", flags.Lookup("max-tuples-per-write"))
 		util.MustBindEnv("maxTuplesPerWrite", "OPENFGA_MAX_TUPLES_PER_WRITE", "OPENFGA_MAXTUPLESPERWRITE")
 
+		util.MustBindPFlag("maxPageSize", flags.Lookup("max-page-size"))
+		util.MustBindEnv("maxPageSize", "OPENFGA_MAX_PAGE_SIZE")
+
 		util.MustBindPFlag("maxTypesPerAuthorizationModel", flags.Lookup("max-types-per-authorization-model"))
 		util.MustBindEnv("maxTypesPerAuthorizationModel", "OPENFGA_MAX_TYPES_PER_AUTHORIZATION_MODEL", "OPENFGA_MAXTYPESPERAUTHORIZATIONMODEL")
 
@@ -188,21 +377,69 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("maxConditionEvaluationCost", flags.Lookup("max-condition-evaluation-cost"))
 		util.MustBindEnv("maxConditionEvaluationCost", "OPENFGA_MAX_CONDITION_EVALUATION_COST", "OPENFGA_MAXCONDITIONEVALUATIONCOST")
 
+		util.MustBindPFlag("maxContextSizeBytes", flags.Lookup("max-context-size-bytes"))
+		util.MustBindEnv("maxContextSizeBytes", "OPENFGA_MAX_CONTEXT_SIZE_BYTES")
+
+		util.MustBindPFlag("maxContextDepth", flags.Lookup("max-context-depth"))
+		util.MustBindEnv("maxContextDepth", "OPENFGA_MAX_CONTEXT_DEPTH")
+
+		util.MustBindPFlag("maxContextualTuples", flags.Lookup("max-contextual-tuples"))
+		util.MustBindEnv("maxContextualTuples", "OPENFGA_MAX_CONTEXTUAL_TUPLES")
+
 		util.MustBindPFlag("changelogHorizonOffset", flags.Lookup("changelog-horizon-offset"))
 		util.MustBindEnv("changelogHorizonOffset", "OPENFGA_CHANGELOG_HORIZON_OFFSET", "OPENFGA_CHANGELOGHORIZONOFFSET")
 
+		util.MustBindPFlag("continuationTokenTTL", flags.Lookup("continuation-token-ttl"))
+		util.MustBindEnv("continuationTokenTTL", "OPENFGA_CONTINUATION_TOKEN_TTL")
+
+		util.MustBindPFlag("continuationTokenUnversionedWindow", flags.Lookup("continuation-token-unversioned-window"))
+		util.MustBindEnv("continuationTokenUnversionedWindow", "OPENFGA_CONTINUATION_TOKEN_UNVERSIONED_WINDOW")
+
 		util.MustBindPFlag("resolveNodeLimit", flags.Lookup("resolve-node-limit"))
 		util.MustBindEnv("resolveNodeLimit", "OPENFGA_RESOLVE_NODE_LIMIT", "OPENFGA_RESOLVENODELIMIT")
 
 		util.MustBindPFlag("resolveNodeBreadthLimit", flags.Lookup("resolve-node-breadth-limit"))
 		util.MustBindEnv("resolveNodeBreadthLimit", "OPENFGA_RESOLVE_NODE_BREADTH_LIMIT", "OPENFGA_RESOLVENODEBREADTHLIMIT")
 
+		util.MustBindPFlag("checkDirectTupleBatchSize", flags.Lookup("check-direct-tuple-batch-size"))
+		util.MustBindEnv("checkDirectTupleBatchSize", "OPENFGA_CHECK_DIRECT_TUPLE_BATCH_SIZE", "OPENFGA_CHECKDIRECTTUPLEBATCHSIZE")
+
+		util.MustBindPFlag("checkDirectTupleBatchMaxDelay", flags.Lookup("check-direct-tuple-batch-max-delay"))
+		util.MustBindEnv("checkDirectTupleBatchMaxDelay", "OPENFGA_CHECK_DIRECT_TUPLE_BATCH_MAX_DELAY", "OPENFGA_CHECKDIRECTTUPLEBATCHMAXDELAY")
+
+		util.MustBindPFlag("checkDispatchPoolSize", flags.Lookup("check-dispatch-pool-size"))
+		util.MustBindEnv("checkDispatchPoolSize", "OPENFGA_CHECK_DISPATCH_POOL_SIZE")
+
+		util.MustBindPFlag("checkMembershipIndex.relations", flags.Lookup("check-membership-index-relations"))
+		util.MustBindEnv("checkMembershipIndex.relations", "OPENFGA_CHECK_MEMBERSHIP_INDEX_RELATIONS")
+
+		util.MustBindPFlag("checkMembershipIndex.refreshInterval", flags.Lookup("check-membership-index-refresh-interval"))
+		util.MustBindEnv("checkMembershipIndex.refreshInterval", "OPENFGA_CHECK_MEMBERSHIP_INDEX_REFRESH_INTERVAL")
+
+		util.MustBindPFlag("checkMembershipIndex.maxStaleness", flags.Lookup("check-membership-index-max-staleness"))
+		util.MustBindEnv("checkMembershipIndex.maxStaleness", "OPENFGA_CHECK_MEMBERSHIP_INDEX_MAX_STALENESS")
+
+		util.MustBindPFlag("checkMaterializedViews.views", flags.Lookup("check-materialized-views"))
+		util.MustBindEnv("checkMaterializedViews.views", "OPENFGA_CHECK_MATERIALIZED_VIEWS_VIEWS")
+
+		util.MustBindPFlag("checkMaterializedViews.refreshInterval", flags.Lookup("check-materialized-views-refresh-interval"))
+		util.MustBindEnv("checkMaterializedViews.refreshInterval", "OPENFGA_CHECK_MATERIALIZED_VIEWS_REFRESH_INTERVAL")
+
+		util.MustBindPFlag("checkMaterializedViews.maxStaleness", flags.Lookup("check-materialized-views-max-staleness"))
+		util.MustBindEnv("checkMaterializedViews.maxStaleness", "OPENFGA_CHECK_MATERIALIZED_VIEWS_MAX_STALENESS")
+
+		util.MustBindPFlag("checkUnionBranchOrderingEnabled", flags.Lookup("check-union-branch-ordering-enabled"))
+		util.MustBindEnv("checkUnionBranchOrderingEnabled", "OPENFGA_CHECK_UNION_BRANCH_ORDERING_ENABLED")
+
 		util.MustBindPFlag("listObjectsDeadline", flags.Lookup("listObjects-deadline"))
 		util.MustBindEnv("listObjectsDeadline", "OPENFGA_LIST_OBJECTS_DEADLINE", "OPENFGA_LISTOBJECTSDEADLINE")
 
 		util.MustBindPFlag("listObjectsMaxResults", flags.Lookup("listObjects-max-results"))
 		util.MustBindEnv("listObjectsMaxResults", "OPENFGA_LIST_OBJECTS_MAX_RESULTS", "OPENFGA_LISTOBJECTSMAXRESULTS")
 
+		util.MustBindPFlag("listObjectsDatastoreQueryBudget", flags.Lookup("listObjects-datastore-query-budget"))
+		util.MustBindEnv("listObjectsDatastoreQueryBudget", "OPENFGA_LIST_OBJECTS_DATASTORE_QUERY_BUDGET")
+
 		util.MustBindPFlag("listUsersDeadline", flags.Lookup("listUsers-deadline"))
 		util.MustBindEnv("listUsersDeadline", "OPENFGA_LIST_USERS_DEADLINE", "OPENFGA_LISTUSERSDEADLINE")
 
@@ -222,6 +459,9 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("cacheController.ttl", flags.Lookup("cache-controller-ttl"))
 		util.MustBindEnv("cacheController.ttl", "OPENFGA_CACHE_CONTROLLER_TTL")
 
+		util.MustBindPFlag("cacheController.notificationChannel", flags.Lookup("cache-controller-notification-channel"))
+		util.MustBindEnv("cacheController.notificationChannel", "OPENFGA_CACHE_CONTROLLER_NOTIFICATION_CHANNEL")
+
 		util.MustBindPFlag("checkIteratorCache.enabled", flags.Lookup("check-iterator-cache-enabled"))
 		util.MustBindEnv("checkIteratorCache.enabled", "OPENFGA_CHECK_ITERATOR_CACHE_ENABLED")
 
@@ -231,12 +471,24 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("checkIteratorCache.ttl", flags.Lookup("check-iterator-cache-ttl"))
 		util.MustBindEnv("checkIteratorCache.ttl", "OPENFGA_CHECK_ITERATOR_CACHE_TTL")
 
+		util.MustBindPFlag("checkTupleCache.enabled", flags.Lookup("check-tuple-cache-enabled"))
+		util.MustBindEnv("checkTupleCache.enabled", "OPENFGA_CHECK_TUPLE_CACHE_ENABLED")
+
+		util.MustBindPFlag("checkTupleCache.ttl", flags.Lookup("check-tuple-cache-ttl"))
+		util.MustBindEnv("checkTupleCache.ttl", "OPENFGA_CHECK_TUPLE_CACHE_TTL")
+
 		util.MustBindPFlag("checkQueryCache.enabled", flags.Lookup("check-query-cache-enabled"))
 		util.MustBindEnv("checkQueryCache.enabled", "OPENFGA_CHECK_QUERY_CACHE_ENABLED")
 
 		util.MustBindPFlag("checkQueryCache.ttl", flags.Lookup("check-query-cache-ttl"))
 		util.MustBindEnv("checkQueryCache.ttl", "OPENFGA_CHECK_QUERY_CACHE_TTL")
 
+		util.MustBindPFlag("checkQueryCache.revalidationWindow", flags.Lookup("check-query-cache-revalidation-window"))
+		util.MustBindEnv("checkQueryCache.revalidationWindow", "OPENFGA_CHECK_QUERY_CACHE_REVALIDATION_WINDOW")
+
+		util.MustBindPFlag("checkQueryCache.revalidationMinHits", flags.Lookup("check-query-cache-revalidation-min-hits"))
+		util.MustBindEnv("checkQueryCache.revalidationMinHits", "OPENFGA_CHECK_QUERY_CACHE_REVALIDATION_MIN_HITS")
+
 		util.MustBindPFlag("listObjectsIteratorCache.enabled", flags.Lookup("list-objects-iterator-cache-enabled"))
 		util.MustBindEnv("listObjectsIteratorCache.enabled", "OPENFGA_LIST_OBJECTS_ITERATOR_CACHE_ENABLED")
 
@@ -252,6 +504,12 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("sharedIterator.limit", flags.Lookup("shared-iterator-limit"))
 		util.MustBindEnv("sharedIterator.limit", "OPENFGA_SHARED_ITERATOR_LIMIT")
 
+		util.MustBindPFlag("requestIteratorCache.enabled", flags.Lookup("request-iterator-cache-enabled"))
+		util.MustBindEnv("requestIteratorCache.enabled", "OPENFGA_REQUEST_ITERATOR_CACHE_ENABLED")
+
+		util.MustBindPFlag("requestIteratorCache.maxResults", flags.Lookup("request-iterator-cache-max-results"))
+		util.MustBindEnv("requestIteratorCache.maxResults", "OPENFGA_REQUEST_ITERATOR_CACHE_MAX_RESULTS")
+
 		util.MustBindPFlag("requestDurationDatastoreQueryCountBuckets", flags.Lookup("request-duration-datastore-query-count-buckets"))
 		util.MustBindEnv("requestDurationDatastoreQueryCountBuckets", "OPENFGA_REQUEST_DURATION_DATASTORE_QUERY_COUNT_BUCKETS")
 
@@ -261,6 +519,9 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("contextPropagationToDatastore", flags.Lookup("context-propagation-to-datastore"))
 		util.MustBindEnv("contextPropagationToDatastore", "OPENFGA_CONTEXT_PROPAGATION_TO_DATASTORE")
 
+		util.MustBindPFlag("resolutionMetadataHeaders", flags.Lookup("resolution-metadata-headers"))
+		util.MustBindEnv("resolutionMetadataHeaders", "OPENFGA_RESOLUTION_METADATA_HEADERS")
+
 		util.MustBindPFlag("checkDispatchThrottling.enabled", flags.Lookup("check-dispatch-throttling-enabled"))
 		util.MustBindEnv("checkDispatchThrottling.enabled", "OPENFGA_CHECK_DISPATCH_THROTTLING_ENABLED")
 
@@ -325,7 +586,181 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("listUsersDatastoreThrottle.duration", flags.Lookup("listUsers-datastore-throttle-duration"))
 		util.MustBindEnv("listUsersDatastoreThrottle.duration", "OPENFGA_LIST_USERS_DATASTORE_THROTTLE_DURATION")
 
+		util.MustBindPFlag("datastoreLimiterSaturation.threshold", flags.Lookup("datastore-limiter-saturation-threshold"))
+		util.MustBindEnv("datastoreLimiterSaturation.threshold", "OPENFGA_DATASTORE_LIMITER_SATURATION_THRESHOLD")
+
+		util.MustBindPFlag("datastoreLimiterSaturation.period", flags.Lookup("datastore-limiter-saturation-period"))
+		util.MustBindEnv("datastoreLimiterSaturation.period", "OPENFGA_DATASTORE_LIMITER_SATURATION_PERIOD")
+
+		util.MustBindPFlag("datastoreLimiterSaturation.ratio", flags.Lookup("datastore-limiter-saturation-ratio"))
+		util.MustBindEnv("datastoreLimiterSaturation.ratio", "OPENFGA_DATASTORE_LIMITER_SATURATION_RATIO")
+
+		util.MustBindPFlag("datastoreLimiterSaturation.readinessEnabled", flags.Lookup("datastore-limiter-saturation-readiness-enabled"))
+		util.MustBindEnv("datastoreLimiterSaturation.readinessEnabled", "OPENFGA_DATASTORE_LIMITER_SATURATION_READINESS_ENABLED")
+
+		util.MustBindPFlag("datastoreReadPriority.slots", flags.Lookup("datastore-read-priority-slots"))
+		util.MustBindEnv("datastoreReadPriority.slots", "OPENFGA_DATASTORE_READ_PRIORITY_SLOTS")
+
+		util.MustBindPFlag("datastoreReadPriority.weights", flags.Lookup("datastore-read-priority-weights"))
+		util.MustBindEnv("datastoreReadPriority.weights", "OPENFGA_DATASTORE_READ_PRIORITY_WEIGHTS")
+
+		util.MustBindPFlag("readiness.checkCacheEnabled", flags.Lookup("readiness-check-cache-enabled"))
+		util.MustBindEnv("readiness.checkCacheEnabled", "OPENFGA_READINESS_CHECK_CACHE_ENABLED")
+
+		util.MustBindPFlag("readiness.tupleChangeListenerEnabled", flags.Lookup("readiness-tuple-change-listener-enabled"))
+		util.MustBindEnv("readiness.tupleChangeListenerEnabled", "OPENFGA_READINESS_TUPLE_CHANGE_LISTENER_ENABLED")
+
+		util.MustBindPFlag("datastoreCircuitBreaker.failureRateThreshold", flags.Lookup("datastore-circuit-breaker-failure-rate-threshold"))
+		util.MustBindEnv("datastoreCircuitBreaker.failureRateThreshold", "OPENFGA_DATASTORE_CIRCUIT_BREAKER_FAILURE_RATE_THRESHOLD")
+
+		util.MustBindPFlag("datastoreCircuitBreaker.minRequests", flags.Lookup("datastore-circuit-breaker-min-requests"))
+		util.MustBindEnv("datastoreCircuitBreaker.minRequests", "OPENFGA_DATASTORE_CIRCUIT_BREAKER_MIN_REQUESTS")
+
+		util.MustBindPFlag("datastoreCircuitBreaker.window", flags.Lookup("datastore-circuit-breaker-window"))
+		util.MustBindEnv("datastoreCircuitBreaker.window", "OPENFGA_DATASTORE_CIRCUIT_BREAKER_WINDOW")
+
+		util.MustBindPFlag("datastoreCircuitBreaker.openDuration", flags.Lookup("datastore-circuit-breaker-open-duration"))
+		util.MustBindEnv("datastoreCircuitBreaker.openDuration", "OPENFGA_DATASTORE_CIRCUIT_BREAKER_OPEN_DURATION")
+
+		util.MustBindPFlag("datastoreRetry.connectionMaxRetries", flags.Lookup("datastore-retry-connection-max-retries"))
+		util.MustBindEnv("datastoreRetry.connectionMaxRetries", "OPENFGA_DATASTORE_RETRY_CONNECTION_MAX_RETRIES")
+
+		util.MustBindPFlag("datastoreRetry.serializationMaxRetries", flags.Lookup("datastore-retry-serialization-max-retries"))
+		util.MustBindEnv("datastoreRetry.serializationMaxRetries", "OPENFGA_DATASTORE_RETRY_SERIALIZATION_MAX_RETRIES")
+
+		util.MustBindPFlag("datastoreRetry.deadlockMaxRetries", flags.Lookup("datastore-retry-deadlock-max-retries"))
+		util.MustBindEnv("datastoreRetry.deadlockMaxRetries", "OPENFGA_DATASTORE_RETRY_DEADLOCK_MAX_RETRIES")
+
+		util.MustBindPFlag("datastoreRetry.initialInterval", flags.Lookup("datastore-retry-initial-interval"))
+		util.MustBindEnv("datastoreRetry.initialInterval", "OPENFGA_DATASTORE_RETRY_INITIAL_INTERVAL")
+
+		util.MustBindPFlag("datastoreRetry.maxInterval", flags.Lookup("datastore-retry-max-interval"))
+		util.MustBindEnv("datastoreRetry.maxInterval", "OPENFGA_DATASTORE_RETRY_MAX_INTERVAL")
+
+		util.MustBindPFlag("datastoreRetry.writes", flags.Lookup("datastore-retry-writes"))
+		util.MustBindEnv("datastoreRetry.writes", "OPENFGA_DATASTORE_RETRY_WRITES")
+
+		util.MustBindPFlag("datastoreFaultInjection.operations", flags.Lookup("datastore-fault-injection-operations"))
+		util.MustBindEnv("datastoreFaultInjection.operations", "OPENFGA_DATASTORE_FAULT_INJECTION_OPERATIONS")
+
+		util.MustBindPFlag("datastoreFaultInjection.latency", flags.Lookup("datastore-fault-injection-latency"))
+		util.MustBindEnv("datastoreFaultInjection.latency", "OPENFGA_DATASTORE_FAULT_INJECTION_LATENCY")
+
+		util.MustBindPFlag("datastoreFaultInjection.errorRate", flags.Lookup("datastore-fault-injection-error-rate"))
+		util.MustBindEnv("datastoreFaultInjection.errorRate", "OPENFGA_DATASTORE_FAULT_INJECTION_ERROR_RATE")
+
+		util.MustBindPFlag("datastoreFaultInjection.iteratorLatency", flags.Lookup("datastore-fault-injection-iterator-latency"))
+		util.MustBindEnv("datastoreFaultInjection.iteratorLatency", "OPENFGA_DATASTORE_FAULT_INJECTION_ITERATOR_LATENCY")
+
+		util.MustBindPFlag("datastoreFaultInjection.policies", flags.Lookup("datastore-fault-injection-policies"))
+		util.MustBindEnv("datastoreFaultInjection.policies", "OPENFGA_DATASTORE_FAULT_INJECTION_POLICIES")
+
+		util.MustBindPFlag("protectedTuples.patterns", flags.Lookup("protected-tuples-patterns"))
+		util.MustBindEnv("protectedTuples.patterns", "OPENFGA_PROTECTED_TUPLES_PATTERNS")
+
+		util.MustBindPFlag("protectedTuples.privilegedPrincipals", flags.Lookup("protected-tuples-privileged-principals"))
+		util.MustBindEnv("protectedTuples.privilegedPrincipals", "OPENFGA_PROTECTED_TUPLES_PRIVILEGED_PRINCIPALS")
+
+		util.MustBindPFlag("rateLimit.enabled", flags.Lookup("rate-limit-enabled"))
+		util.MustBindEnv("rateLimit.enabled", "OPENFGA_RATE_LIMIT_ENABLED")
+
+		util.MustBindPFlag("rateLimit.key", flags.Lookup("rate-limit-key"))
+		util.MustBindEnv("rateLimit.key", "OPENFGA_RATE_LIMIT_KEY")
+
+		util.MustBindPFlag("rateLimit.rate", flags.Lookup("rate-limit-rate"))
+		util.MustBindEnv("rateLimit.rate", "OPENFGA_RATE_LIMIT_RATE")
+
+		util.MustBindPFlag("rateLimit.burst", flags.Lookup("rate-limit-burst"))
+		util.MustBindEnv("rateLimit.burst", "OPENFGA_RATE_LIMIT_BURST")
+
+		util.MustBindPFlag("rateLimit.methods", flags.Lookup("rate-limit-methods"))
+		util.MustBindEnv("rateLimit.methods", "OPENFGA_RATE_LIMIT_METHODS")
+
+		util.MustBindPFlag("loadShedding.enabled", flags.Lookup("load-shedding-enabled"))
+		util.MustBindEnv("loadShedding.enabled", "OPENFGA_LOAD_SHEDDING_ENABLED")
+
+		util.MustBindPFlag("loadShedding.maxInFlightCost", flags.Lookup("load-shedding-max-in-flight-cost"))
+		util.MustBindEnv("loadShedding.maxInFlightCost", "OPENFGA_LOAD_SHEDDING_MAX_IN_FLIGHT_COST")
+
+		util.MustBindPFlag("limitOverrides.enabled", flags.Lookup("limit-overrides-enabled"))
+		util.MustBindEnv("limitOverrides.enabled", "OPENFGA_LIMIT_OVERRIDES_ENABLED")
+
+		util.MustBindPFlag("limitOverrides.trustedPrincipals", flags.Lookup("limit-overrides-trusted-principals"))
+		util.MustBindEnv("limitOverrides.trustedPrincipals", "OPENFGA_LIMIT_OVERRIDES_TRUSTED_PRINCIPALS")
+
+		util.MustBindPFlag("limitOverrides.maxResolveDepth", flags.Lookup("limit-overrides-max-resolve-depth"))
+		util.MustBindEnv("limitOverrides.maxResolveDepth", "OPENFGA_LIMIT_OVERRIDES_MAX_RESOLVE_DEPTH")
+
+		util.MustBindPFlag("limitOverrides.maxListObjectsDeadline", flags.Lookup("limit-overrides-max-list-objects-deadline"))
+		util.MustBindEnv("limitOverrides.maxListObjectsDeadline", "OPENFGA_LIMIT_OVERRIDES_MAX_LIST_OBJECTS_DEADLINE")
+
+		util.MustBindPFlag("authorizationModelRetention.count", flags.Lookup("authorization-model-retention-count"))
+		util.MustBindEnv("authorizationModelRetention.count", "OPENFGA_AUTHORIZATION_MODEL_RETENTION_COUNT")
+
+		util.MustBindPFlag("authorizationModelRetention.pruneInterval", flags.Lookup("authorization-model-retention-prune-interval"))
+		util.MustBindEnv("authorizationModelRetention.pruneInterval", "OPENFGA_AUTHORIZATION_MODEL_RETENTION_PRUNE_INTERVAL")
+
+		util.MustBindPFlag("modelSync.sources", flags.Lookup("model-sync-sources"))
+		util.MustBindEnv("modelSync.sources", "OPENFGA_MODEL_SYNC_SOURCES")
+
+		util.MustBindPFlag("modelSync.interval", flags.Lookup("model-sync-interval"))
+		util.MustBindEnv("modelSync.interval", "OPENFGA_MODEL_SYNC_INTERVAL")
+
+		util.MustBindPFlag("trustedContext.currentTimeParameter", flags.Lookup("trusted-context-current-time-parameter"))
+		util.MustBindEnv("trustedContext.currentTimeParameter", "OPENFGA_TRUSTED_CONTEXT_CURRENT_TIME_PARAMETER")
+
+		util.MustBindPFlag("trustedContext.currentTimePrecision", flags.Lookup("trusted-context-current-time-precision"))
+		util.MustBindEnv("trustedContext.currentTimePrecision", "OPENFGA_TRUSTED_CONTEXT_CURRENT_TIME_PRECISION")
+
+		util.MustBindPFlag("trustedContext.callerParameter", flags.Lookup("trusted-context-caller-parameter"))
+		util.MustBindEnv("trustedContext.callerParameter", "OPENFGA_TRUSTED_CONTEXT_CALLER_PARAMETER")
+
+		util.MustBindPFlag("typesystemCache.ttl", flags.Lookup("typesystem-cache-ttl"))
+		util.MustBindEnv("typesystemCache.ttl", "OPENFGA_TYPESYSTEM_CACHE_TTL")
+
+		util.MustBindPFlag("typesystemCache.size", flags.Lookup("typesystem-cache-size"))
+		util.MustBindEnv("typesystemCache.size", "OPENFGA_TYPESYSTEM_CACHE_SIZE")
+
+		util.MustBindPFlag("typesystemCache.storeTTLs", flags.Lookup("typesystem-cache-store-ttls"))
+		util.MustBindEnv("typesystemCache.storeTTLs", "OPENFGA_TYPESYSTEM_CACHE_STORE_TTLS")
+
+		util.MustBindPFlag("typesystemCache.warmupStores", flags.Lookup("typesystem-cache-warmup-stores"))
+		util.MustBindEnv("typesystemCache.warmupStores", "OPENFGA_TYPESYSTEM_CACHE_WARMUP_STORES")
+
+		util.MustBindPFlag("typesystemCache.warmupMRUFile", flags.Lookup("typesystem-cache-warmup-mru-file"))
+		util.MustBindEnv("typesystemCache.warmupMRUFile", "OPENFGA_TYPESYSTEM_CACHE_WARMUP_MRU_FILE")
+
+		util.MustBindPFlag("typesystemCache.redis.addr", flags.Lookup("typesystem-cache-redis-addr"))
+		util.MustBindEnv("typesystemCache.redis.addr", "OPENFGA_TYPESYSTEM_CACHE_REDIS_ADDR")
+
+		util.MustBindPFlag("typesystemCache.redis.username", flags.Lookup("typesystem-cache-redis-username"))
+		util.MustBindEnv("typesystemCache.redis.username", "OPENFGA_TYPESYSTEM_CACHE_REDIS_USERNAME")
+
+		util.MustBindPFlag("typesystemCache.redis.password", flags.Lookup("typesystem-cache-redis-password"))
+		util.MustBindEnv("typesystemCache.redis.password", "OPENFGA_TYPESYSTEM_CACHE_REDIS_PASSWORD")
+
+		util.MustBindPFlag("typesystemCache.redis.db", flags.Lookup("typesystem-cache-redis-db"))
+		util.MustBindEnv("typesystemCache.redis.db", "OPENFGA_TYPESYSTEM_CACHE_REDIS_DB")
+
+		util.MustBindPFlag("typesystemCache.redis.tls", flags.Lookup("typesystem-cache-redis-tls"))
+		util.MustBindEnv("typesystemCache.redis.tls", "OPENFGA_TYPESYSTEM_CACHE_REDIS_TLS")
+
+		util.MustBindPFlag("maxConcurrentJobs", flags.Lookup("max-concurrent-jobs"))
+		util.MustBindEnv("maxConcurrentJobs", "OPENFGA_MAX_CONCURRENT_JOBS")
+
+		util.MustBindPFlag("maxJobTuples", flags.Lookup("max-job-tuples"))
+		util.MustBindEnv("maxJobTuples", "OPENFGA_MAX_JOB_TUPLES")
+
 		util.MustBindPFlag("requestTimeout", flags.Lookup("request-timeout"))
 		util.MustBindEnv("requestTimeout", "OPENFGA_REQUEST_TIMEOUT")
+
+		util.MustBindPFlag("shutdownDrainDelay", flags.Lookup("shutdown-drain-delay"))
+		util.MustBindEnv("shutdownDrainDelay", "OPENFGA_SHUTDOWN_DRAIN_DELAY")
+
+		util.MustBindPFlag("shutdownDrainTimeout", flags.Lookup("shutdown-drain-timeout"))
+		util.MustBindEnv("shutdownDrainTimeout", "OPENFGA_SHUTDOWN_DRAIN_TIMEOUT")
+
+		util.MustBindPFlag("methodTimeouts", flags.Lookup("method-timeouts"))
+		util.MustBindEnv("methodTimeouts", "OPENFGA_METHOD_TIMEOUTS")
 	}
 }
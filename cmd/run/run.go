@@ -4,17 +4,22 @@ package run
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"html/template"
+	"maps"
 	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"os/signal"
+	"reflect"
 	goruntime "runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -25,6 +30,7 @@ import (
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	grpc_prometheus "github.com/jon-whit/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	"github.com/spf13/cobra"
@@ -39,6 +45,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	healthv1pb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
@@ -47,32 +54,48 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/assets"
+	"github.com/openfga/openfga/internal/admin"
 	"github.com/openfga/openfga/internal/authn"
+	"github.com/openfga/openfga/internal/authn/mtls"
 	"github.com/openfga/openfga/internal/authn/oidc"
 	"github.com/openfga/openfga/internal/authn/presharedkey"
+	"github.com/openfga/openfga/internal/authn/storetoken"
 	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/loadshedding"
 	authnmw "github.com/openfga/openfga/internal/middleware/authn"
+	authzmw "github.com/openfga/openfga/internal/middleware/authz"
+	"github.com/openfga/openfga/internal/middleware/limitoverrides"
+	"github.com/openfga/openfga/internal/middleware/ratelimit"
+	"github.com/openfga/openfga/internal/redis"
 	"github.com/openfga/openfga/pkg/encoder"
 	"github.com/openfga/openfga/pkg/gateway"
+	"github.com/openfga/openfga/pkg/gateway/connect"
+	"github.com/openfga/openfga/pkg/gateway/grpcweb"
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/middleware"
+	"github.com/openfga/openfga/pkg/middleware/accesslog"
+	"github.com/openfga/openfga/pkg/middleware/errorinfo"
 	httpmiddleware "github.com/openfga/openfga/pkg/middleware/http"
 	"github.com/openfga/openfga/pkg/middleware/logging"
 	"github.com/openfga/openfga/pkg/middleware/recovery"
 	"github.com/openfga/openfga/pkg/middleware/requestid"
 	"github.com/openfga/openfga/pkg/middleware/storeid"
 	"github.com/openfga/openfga/pkg/middleware/validator"
+	"github.com/openfga/openfga/pkg/redact"
 	"github.com/openfga/openfga/pkg/server"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/server/health"
 	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/engine"
 	"github.com/openfga/openfga/pkg/storage/memory"
 	"github.com/openfga/openfga/pkg/storage/mysql"
 	"github.com/openfga/openfga/pkg/storage/postgres"
 	"github.com/openfga/openfga/pkg/storage/sqlcommon"
 	"github.com/openfga/openfga/pkg/storage/sqlite"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
 	"github.com/openfga/openfga/pkg/telemetry"
+	"github.com/openfga/openfga/pkg/typesystem"
 )
 
 const (
@@ -92,7 +115,7 @@ func NewRunCommand() *cobra.Command {
 	defaultConfig := serverconfig.DefaultConfig()
 	flags := cmd.Flags()
 
-	flags.StringSlice("experimentals", defaultConfig.Experimentals, "a list of experimental features to enable. Allowed values: `enable-consistency-params`, `enable-check-optimizations`, `enable-list-objects-optimizations`, `enable-access-control`")
+	flags.StringSlice("experimentals", defaultConfig.Experimentals, "a list of experimental features to enable. Allowed values: `enable-consistency-params`, `enable-check-optimizations`, `enable-list-objects-optimizations`, `enable-access-control`, `enable-simulation`, `enable-fault-injection`, `enable-materialized-views`")
 
 	flags.Bool("access-control-enabled", defaultConfig.AccessControl.Enabled, "enable/disable the access control feature")
 
@@ -112,6 +135,24 @@ func NewRunCommand() *cobra.Command {
 
 	cmd.MarkFlagsRequiredTogether("grpc-tls-enabled", "grpc-tls-cert", "grpc-tls-key")
 
+	flags.Int("grpc-max-recv-msg-size-bytes", defaultConfig.GRPC.MaxRecvMsgSizeBytes, "the maximum size in bytes of the messages the grpc server receives, e.g. of the Write requests")
+
+	flags.Int("grpc-max-send-msg-size-bytes", defaultConfig.GRPC.MaxSendMsgSizeBytes, "the maximum size in bytes of the messages the grpc server sends")
+
+	flags.Uint32("grpc-max-concurrent-streams", defaultConfig.GRPC.MaxConcurrentStreams, "the maximum number of concurrent calls per grpc client connection. 0 means no limit")
+
+	flags.Duration("grpc-keepalive-time", defaultConfig.GRPC.Keepalive.Time, "the duration a grpc connection is idle before the server pings the client")
+
+	flags.Duration("grpc-keepalive-timeout", defaultConfig.GRPC.Keepalive.Timeout, "the duration the grpc server waits for the response to a ping before closing the connection")
+
+	flags.Duration("grpc-keepalive-max-connection-age", defaultConfig.GRPC.Keepalive.MaxConnectionAge, "the duration after which the grpc connections are closed, so that the clients reconnect through the load balancers. 0 means no limit")
+
+	flags.Duration("grpc-keepalive-max-connection-age-grace", defaultConfig.GRPC.Keepalive.MaxConnectionAgeGrace, "the duration the calls in flight are given to complete once the max connection age is reached. 0 means no limit")
+
+	flags.Duration("grpc-keepalive-min-time", defaultConfig.GRPC.Keepalive.MinTime, "the minimum duration between the pings of a grpc client, which is disconnected if it pings more often")
+
+	flags.Bool("grpc-keepalive-permit-without-stream", defaultConfig.GRPC.Keepalive.PermitWithoutStream, "allow the grpc clients to ping when they have no call in flight")
+
 	flags.Bool("http-enabled", defaultConfig.HTTP.Enabled, "enable/disable the OpenFGA HTTP server")
 
 	flags.String("http-addr", defaultConfig.HTTP.Addr, "the host:port address to serve the HTTP server on")
@@ -130,6 +171,10 @@ func NewRunCommand() *cobra.Command {
 
 	flags.StringSlice("http-cors-allowed-headers", defaultConfig.HTTP.CORSAllowedHeaders, "specifies the CORS allowed headers")
 
+	flags.Bool("http-connect-enabled", defaultConfig.HTTP.ConnectEnabled, "serve the OpenFGA service over the Connect protocol on the HTTP server, e.g. at '/openfga.v1.OpenFGAService/Check', alongside the HTTP API")
+
+	flags.Bool("http-grpc-web-enabled", defaultConfig.HTTP.GRPCWebEnabled, "serve the OpenFGA service over the gRPC-Web protocol on the HTTP server, e.g. at '/openfga.v1.OpenFGAService/StreamedListObjects', for the browsers")
+
 	flags.String("authn-method", defaultConfig.Authn.Method, "the authentication method to use")
 
 	flags.StringSlice("authn-preshared-keys", defaultConfig.Authn.Keys, "one or more preshared keys to use for authentication")
@@ -144,7 +189,17 @@ func NewRunCommand() *cobra.Command {
 
 	flags.StringSlice("authn-oidc-client-id-claims", defaultConfig.Authn.ClientIDClaims, "the ClientID claims that will be used to parse the clientID - configure in order of priority (first is highest). Defaults to [`azp`, `client_id`]")
 
-	flags.String("datastore-engine", defaultConfig.Datastore.Engine, "the datastore engine that will be used for persistence")
+	flags.String("authn-oidc-store-id-claim", defaultConfig.Authn.StoreIDClaim, "the claim of the JWTs holding the store id(s) the token is allowed to access (e.g. `fga_store_id`). If set, tokens without it are rejected and requests on other stores are denied")
+
+	flags.StringSlice("authn-oidc-additional-issuers", defaultConfig.Authn.AdditionalIssuers, "other OIDC issuers whose tokens will be accepted. The keys of each issuer are fetched from its discovery document and refreshed independently")
+
+	flags.StringSlice("authn-oidc-additional-audiences", defaultConfig.Authn.AdditionalAudiences, "the audiences of the authn-oidc-additional-issuers, in the same order: the tokens of each additional issuer must have its audience in the `aud` field")
+
+	flags.String("authn-mtls-client-ca", defaultConfig.Authn.ClientCAPath, "the (absolute) file path of the PEM encoded certificate authorities trusted to issue client certificates. The file is reloaded when it changes")
+
+	flags.StringSlice("authn-storetoken-signing-keys", defaultConfig.Authn.SigningKeys, "one or more keys used to verify store tokens. Preshared keys configured with authn-preshared-keys remain valid for every store")
+
+	flags.String("datastore-engine", defaultConfig.Datastore.Engine, "the datastore engine that will be used for persistence: 'memory', 'postgres', 'mysql', 'sqlite', or an engine registered with the 'pkg/storage/engine' package")
 
 	flags.String("datastore-uri", defaultConfig.Datastore.URI, "the connection uri to use to connect to the datastore (for any engine other than 'memory')")
 
@@ -152,6 +207,16 @@ func NewRunCommand() *cobra.Command {
 
 	flags.String("datastore-password", "", "the connection password to use to connect to the datastore (overwrites any password provided in the connection uri)")
 
+	flags.String("datastore-username-file", "", "a file holding the connection username to use to connect to the datastore (for the 'postgres' and 'mysql' engines), taking precedence over the other usernames. It is read again periodically, so that a rotated username is used by new connections")
+
+	flags.String("datastore-password-file", "", "a file holding the connection password to use to connect to the datastore (for the 'postgres' and 'mysql' engines), taking precedence over the other passwords. It is read again periodically, so that a rotated password is used by new connections")
+
+	flags.Duration("datastore-credentials-refresh-interval", defaultConfig.Datastore.CredentialsRefreshInterval, "how often the datastore username and password files are read again")
+
+	flags.Bool("datastore-iam-auth-enabled", defaultConfig.Datastore.IAMAuth.Enabled, "authenticate to an AWS RDS or Aurora datastore (for the 'postgres' and 'mysql' engines) with IAM authentication tokens signed with the credentials of the default AWS credential chain (environment variables, shared configuration files, web identity, ECS task and EC2 instance roles), instead of a password. The connections must use TLS, and MySQL requires the 'allowCleartextPasswords=true' dsn parameter")
+
+	flags.String("datastore-iam-auth-region", defaultConfig.Datastore.IAMAuth.Region, "the AWS region of the datastore authenticated with IAM")
+
 	flags.Int("datastore-max-cache-size", defaultConfig.Datastore.MaxCacheSize, "the maximum number of authorization models that will be cached in memory")
 
 	flags.Int("datastore-max-open-conns", defaultConfig.Datastore.MaxOpenConns, "the maximum number of open connections to the datastore")
@@ -162,7 +227,27 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Duration("datastore-conn-max-lifetime", defaultConfig.Datastore.ConnMaxLifetime, "the maximum amount of time a connection to the datastore may be reused")
 
-	flags.Bool("datastore-metrics-enabled", defaultConfig.Datastore.Metrics.Enabled, "enable/disable sql metrics")
+	flags.StringSlice("datastore-read-replica-uris", defaultConfig.Datastore.ReadReplicaURIs, "the connection uris of read replicas of the datastore (for the 'postgres' and 'mysql' engines). Tuple reads not requiring HIGHER_CONSISTENCY are spread across them")
+
+	flags.Duration("datastore-read-timeout", defaultConfig.Datastore.ReadTimeout, "how long a tuple read may query the datastore before it is cancelled, failing with a datastore deadline exceeded error. Every fetch of an iterator is bounded separately. If 0, reads are not bounded.")
+
+	flags.Duration("datastore-write-timeout", defaultConfig.Datastore.WriteTimeout, "how long a tuple write may take before it is cancelled, failing with a datastore deadline exceeded error. If 0, writes are not bounded.")
+
+	flags.Duration("datastore-slow-query-threshold", defaultConfig.Datastore.SlowQueryThreshold, "how long a tuple read or write may take before it is logged as a slow datastore query, with its operation, store id and the shape of its filter without the object and user ids. The query of an iterator is measured on its first fetch. If 0, slow queries are not logged.")
+
+	flags.String("datastore-snapshot-path", defaultConfig.Datastore.SnapshotPath, "the file the 'memory' engine is persisted to, so that its stores survive restarts. It is loaded on start if it exists, and saved every '--datastore-snapshot-interval' and on graceful shutdown. The writes since the last snapshot are lost if the server does not shut down gracefully. If empty, the 'memory' engine is not persisted.")
+
+	flags.Duration("datastore-snapshot-interval", defaultConfig.Datastore.SnapshotInterval, "how often the 'memory' engine is saved to '--datastore-snapshot-path'. If 0, it is only saved on graceful shutdown.")
+
+	flags.Int("datastore-memory-max-tuples-per-store", defaultConfig.Datastore.MemoryMaxTuplesPerStore, "the maximum number of tuples of each store of the 'memory' engine. A write exceeding it fails with a resource exhausted error. If 0, the number of tuples is not limited.")
+
+	flags.Int64("datastore-memory-max-bytes", defaultConfig.Datastore.MemoryMaxBytes, "the maximum estimated memory, in bytes, held by the tuples and changes of the 'memory' engine. A write exceeding it fails with a resource exhausted error instead of growing the server until it runs out of memory. If 0, the memory is not limited.")
+
+	flags.Uint32("datastore-max-concurrent-reads", defaultConfig.Datastore.MaxConcurrentReads, "the maximum number of concurrent tuple reads of the whole server, whatever the number of requests, under the per-request limits of 'maxConcurrentReadsForCheck', 'maxConcurrentReadsForListObjects' and 'maxConcurrentReadsForListUsers'. If 0, the reads are not limited server-wide.")
+
+	flags.Duration("datastore-hedge-delay", defaultConfig.Datastore.HedgeDelay, "how long a Check datastore read of tuples, including the query of the first tuples of the reads returning an iterator, may take before an identical read is issued, using the result of whichever returns first, if the read concurrency limit allows it. A value around the P95 latency of the reads is a good start. If 0, reads are not hedged.")
+
+	flags.Bool("datastore-metrics-enabled", defaultConfig.Datastore.Metrics.Enabled, "enable/disable sql metrics: the connection pool statistics (open, in use and idle connections, wait count and duration) and the duration of the tuple queries by operation")
 
 	flags.Bool("playground-enabled", defaultConfig.Playground.Enabled, "enable/disable the OpenFGA Playground")
 
@@ -178,6 +263,40 @@ func NewRunCommand() *cobra.Command {
 
 	flags.String("log-timestamp-format", defaultConfig.Log.TimestampFormat, "the timestamp format to use for log messages")
 
+	flags.Bool("log-sampling-enabled", defaultConfig.Log.Sampling.Enabled, "sample the repeated log messages, so that they do not flood the logs. See --log-sampling-per-second and --log-sampling-thereafter.")
+
+	flags.Int("log-sampling-per-second", defaultConfig.Log.Sampling.PerSecond, "the number of entries of a log message written per second before sampling it.")
+
+	flags.Int("log-sampling-thereafter", defaultConfig.Log.Sampling.Thereafter, "once a log message is sampled in a second, write only every Nth entry of it. 0 drops all of them.")
+
+	flags.StringSlice("log-output-paths", defaultConfig.Log.OutputPaths, "the files or URLs to write the logs to, e.g. 'stdout' or '/var/log/openfga.log'.")
+
+	flags.Bool("log-rotation-enabled", defaultConfig.Log.Rotation.Enabled, "rotate the log files of the log output paths. See the --log-rotation-* flags.")
+
+	flags.Int("log-rotation-max-size", defaultConfig.Log.Rotation.MaxSize, "the size in megabytes a log file is rotated at.")
+
+	flags.Duration("log-rotation-max-age", defaultConfig.Log.Rotation.MaxAge, "the age after which a rotated log file is removed. 0 keeps the files regardless of their age.")
+
+	flags.Int("log-rotation-max-backups", defaultConfig.Log.Rotation.MaxBackups, "the number of rotated log files kept. 0 keeps all of them.")
+
+	flags.Bool("log-rotation-compress", defaultConfig.Log.Rotation.Compress, "compress the rotated log files with gzip.")
+
+	flags.Bool("log-security-enabled", defaultConfig.Log.Security.Enabled, "write the security events (authentication failures, authorization denials and store deletions) to their own sink instead of with the other log messages. See --log-security-format and --log-security-output-paths.")
+
+	flags.String("log-security-format", defaultConfig.Log.Security.Format, "the log format to output the security events in ('text' or 'json').")
+
+	flags.StringSlice("log-security-output-paths", defaultConfig.Log.Security.OutputPaths, "the files or URLs to write the security events to, e.g. '/var/log/openfga-security.log'.")
+
+	flags.Bool("access-log-enabled", defaultConfig.AccessLog.Enabled, "log one structured line per request, including the requests of the HTTP gateway.")
+
+	flags.StringSlice("access-log-fields", defaultConfig.AccessLog.Fields, "the fields of the access log lines, or all of them if empty. One or more of 'method', 'request_id', 'store_id', 'authorization_model_id', 'peer', 'latency_ms', 'code', 'datastore_query_count' and 'dispatch_count'.")
+
+	flags.StringSlice("access-log-skipped-methods", defaultConfig.AccessLog.SkippedMethods, "the methods not logged in the access log, e.g. 'Check' or 'grpc.health.v1.Health/Check'.")
+
+	flags.String("redaction-mode", defaultConfig.Redaction.Mode, "redact the ids of the users and objects, and the values of the condition contexts, in the spans, logs and error messages. One of 'off', 'hash' (replace them by their keyed hash, see --redaction-hash-key) or 'drop' (replace them by '?').")
+
+	flags.String("redaction-hash-key", defaultConfig.Redaction.HashKey, "the secret key of the hashes of the 'hash' redaction mode, so that the ids cannot be recovered by hashing guesses.")
+
 	flags.Bool("trace-enabled", defaultConfig.Trace.Enabled, "enable tracing")
 
 	flags.String("trace-otlp-endpoint", defaultConfig.Trace.OTLP.Endpoint, "the endpoint of the trace collector")
@@ -186,20 +305,50 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Float64("trace-sample-ratio", defaultConfig.Trace.SampleRatio, "the fraction of traces to sample. 1 means all, 0 means none.")
 
+	flags.StringSlice("trace-method-sample-ratios", defaultConfig.Trace.MethodSampleRatios, "the fractions of the traces of specific methods to sample, overriding the sample ratio, in the form '<method>:<ratio>', e.g. 'Check:0.01'. The method can be a prefix ending with '*', e.g. 'Write*:1'.")
+
+	flags.Bool("trace-sample-errors", defaultConfig.Trace.SampleErrors, "export the failed spans of the traces that are not sampled. The spans of all the traces are then recorded, which costs some CPU and memory.")
+
 	flags.String("trace-service-name", defaultConfig.Trace.ServiceName, "the service name included in sampled traces.")
 
 	flags.Bool("metrics-enabled", defaultConfig.Metrics.Enabled, "enable/disable prometheus metrics on the '/metrics' endpoint")
 
 	flags.String("metrics-addr", defaultConfig.Metrics.Addr, "the host:port address to serve the prometheus metrics server on")
 
+	flags.Bool("admin-enabled", defaultConfig.Admin.Enabled, "enable/disable the admin server, serving operational actions like flushing the caches on '/admin/v1'")
+
+	flags.String("admin-addr", defaultConfig.Admin.Addr, "the host:port address to serve the admin server on. It is served with TLS, with the certificate of the HTTP server, if '--http-tls-enabled' is set")
+
+	flags.StringSlice("admin-keys", defaultConfig.Admin.Keys, "the preshared keys the requests of the admin server are authenticated with, as bearer tokens")
+
 	flags.Bool("metrics-enable-rpc-histograms", defaultConfig.Metrics.EnableRPCHistograms, "enables prometheus histogram metrics for RPC latency distributions")
 
+	flags.Bool("metrics-otlp-enabled", defaultConfig.Metrics.OTLP.Enabled, "export the metrics to an OTLP receiver, in addition to serving them on the '/metrics' endpoint if '--metrics-enabled' is set")
+
+	flags.String("metrics-otlp-endpoint", defaultConfig.Metrics.OTLP.Endpoint, "the host:port endpoint of the OTLP receiver the metrics are exported to")
+
+	flags.String("metrics-otlp-protocol", defaultConfig.Metrics.OTLP.Protocol, "the protocol the metrics are exported with, 'grpc' or 'http'")
+
+	flags.Duration("metrics-otlp-interval", defaultConfig.Metrics.OTLP.Interval, "how often the metrics are exported to the OTLP receiver")
+
+	flags.StringSlice("metrics-otlp-headers", defaultConfig.Metrics.OTLP.Headers, "the headers sent with the exports of the metrics, in the form '<name>=<value>', e.g. 'Authorization=Bearer <token>' to authenticate with the OTLP receiver")
+
+	flags.Bool("metrics-otlp-tls-enabled", defaultConfig.Metrics.OTLP.TLS.Enabled, "use TLS to export the metrics to the OTLP receiver")
+
+	flags.Bool("metrics-store-id-label-enabled", defaultConfig.Metrics.StoreIDLabel.Enabled, "label the request duration and datastore query count histograms with the store id of the requests, e.g. to measure per tenant SLOs")
+
+	flags.StringSlice("metrics-store-id-label-stores", defaultConfig.Metrics.StoreIDLabel.Stores, "the stores labelled with their id in the request metrics, the others being labelled 'other'. If empty, the '--metrics-store-id-label-limit' stores with the most requests are labelled with their id")
+
+	flags.Int("metrics-store-id-label-limit", defaultConfig.Metrics.StoreIDLabel.Limit, "the number of stores labelled with their id in the request metrics when '--metrics-store-id-label-stores' is empty, bounding the cardinality of the label. The requests of the stores beyond it are labelled 'other'")
+
 	flags.Uint32("max-concurrent-checks-per-batch-check", defaultConfig.MaxConcurrentChecksPerBatchCheck, "the maximum number of checks that can be processed concurrently in a batch check request")
 
 	flags.Uint32("max-checks-per-batch-check", defaultConfig.MaxChecksPerBatchCheck, "the maximum number of tuples allowed in a BatchCheck request")
 
 	flags.Int("max-tuples-per-write", defaultConfig.MaxTuplesPerWrite, "the maximum allowed number of tuples per Write transaction")
 
+	flags.Int32("max-page-size", defaultConfig.MaxPageSize, "the maximum page size of Read, ReadChanges, ReadAuthorizationModels and ListStores requests, between 1 and 100. The default page size is lowered to it if it is smaller.")
+
 	flags.Int("max-types-per-authorization-model", defaultConfig.MaxTypesPerAuthorizationModel, "the maximum allowed number of type definitions per authorization model")
 
 	flags.Int("max-authorization-model-size-in-bytes", defaultConfig.MaxAuthorizationModelSizeInBytes, "the maximum size in bytes allowed for persisting an Authorization Model.")
@@ -212,16 +361,48 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Uint64("max-condition-evaluation-cost", defaultConfig.MaxConditionEvaluationCost, "the maximum cost for CEL condition evaluation before a request returns an error")
 
+	flags.Uint32("max-context-size-bytes", defaultConfig.MaxContextSizeBytes, "the maximum size, in bytes, of the context of Check, BatchCheck, ListObjects and ListUsers requests. If 0, the size is not limited.")
+
+	flags.Uint32("max-context-depth", defaultConfig.MaxContextDepth, "the maximum nesting depth of the context of Check, BatchCheck, ListObjects and ListUsers requests. If 0, the depth is not limited.")
+
+	flags.Uint32("max-contextual-tuples", defaultConfig.MaxContextualTuples, "the maximum number of contextual tuples of Check, BatchCheck, ListObjects, ListUsers and Expand requests, in addition to the limits of the API. If 0, the number is only limited by the API.")
+
 	flags.Int("changelog-horizon-offset", defaultConfig.ChangelogHorizonOffset, "the offset (in minutes) from the current time. Changes that occur after this offset will not be included in the response of ReadChanges")
 
+	flags.Duration("continuation-token-ttl", defaultConfig.ContinuationTokenTTL, "how long the continuation tokens can be used once returned, after which they are rejected as expired. If 0, they never expire.")
+
+	flags.Duration("continuation-token-unversioned-window", defaultConfig.ContinuationTokenUnversionedWindow, "how long the continuation tokens returned before their versioning, which have no expiry, are still accepted once the server started, if the continuation tokens expire. If 0, they are rejected as soon as the continuation tokens expire.")
+
 	flags.Uint32("resolve-node-limit", defaultConfig.ResolveNodeLimit, "maximum resolution depth to attempt before throwing an error (defines how deeply nested an authorization model can be before a query errors out).")
 
 	flags.Uint32("resolve-node-breadth-limit", defaultConfig.ResolveNodeBreadthLimit, "defines how many nodes on a given level can be evaluated concurrently in a Check resolution tree")
 
+	flags.Uint32("check-direct-tuple-batch-size", defaultConfig.CheckDirectTupleBatchSize, "defines how many direct tuples dispatched by a Check are read with a single datastore query. Values lower than 2 disable batching.")
+
+	flags.Duration("check-direct-tuple-batch-max-delay", defaultConfig.CheckDirectTupleBatchMaxDelay, "defines how long a direct tuple dispatched by a Check waits for its batch to fill before the batch is read. If 0, the batch is read once it fills or the dispatches end.")
+
+	flags.Uint32("check-dispatch-pool-size", defaultConfig.CheckDispatchPoolSize, "the number of workers, reused across requests, resolving the subproblems of the set operations of Check and ListObjects rather than a new goroutine each. It is raised to the resolve node breadth limit. If 0, the pool is disabled.")
+
+	flags.StringSlice("check-membership-index-relations", defaultConfig.CheckMembershipIndex.Relations, "the nested relations, in the form 'type#relation', e.g. 'group#member', whose transitive members are indexed in the background from the changelog and allowed by Check without resolving the graph. If empty, the index is disabled.")
+
+	flags.Duration("check-membership-index-refresh-interval", defaultConfig.CheckMembershipIndex.RefreshInterval, "how often the changes of the stores are applied to the membership index.")
+
+	flags.Duration("check-membership-index-max-staleness", defaultConfig.CheckMembershipIndex.MaxStaleness, "how far behind the changes of a store its membership index may be for Check to consult it.")
+
+	flags.StringSlice("check-materialized-views", defaultConfig.CheckMaterializedViews.Views, "the hot relations of some objects, in the form 'object#relation', e.g. 'org:acme#member', whose users are computed in the background and looked up by Check without resolving the graph. If empty, the views are disabled. Requires the 'enable-materialized-views' experimental feature.")
+
+	flags.Duration("check-materialized-views-refresh-interval", defaultConfig.CheckMaterializedViews.RefreshInterval, "how often the changes of the stores are checked, and their materialized views recomputed if needed. Requires the 'enable-materialized-views' experimental feature.")
+
+	flags.Duration("check-materialized-views-max-staleness", defaultConfig.CheckMaterializedViews.MaxStaleness, "how far behind the changes of a store its materialized views may be for Check to consult them. Requires the 'enable-materialized-views' experimental feature.")
+
+	flags.Bool("check-union-branch-ordering-enabled", defaultConfig.CheckUnionBranchOrderingEnabled, "enable resolving the branches of the unions of Check and ListObjects from the cheapest to the most expensive, as estimated from the shape of the model")
+
 	flags.Duration("listObjects-deadline", defaultConfig.ListObjectsDeadline, "the timeout deadline for serving ListObjects and StreamedListObjects requests")
 
 	flags.Uint32("listObjects-max-results", defaultConfig.ListObjectsMaxResults, "the maximum results to return in non-streaming ListObjects API responses. If 0, all results can be returned")
 
+	flags.Uint32("listObjects-datastore-query-budget", defaultConfig.ListObjectsDatastoreQueryBudget, "the number of datastore reads after which ListObjects and StreamedListObjects requests return the results found so far, marked as truncated. If 0, the reads are not limited")
+
 	flags.Duration("listUsers-deadline", defaultConfig.ListUsersDeadline, "the timeout deadline for serving ListUsers requests. If 0, there is no deadline")
 
 	flags.Uint32("listUsers-max-results", defaultConfig.ListUsersMaxResults, "the maximum results to return in ListUsers API responses. If 0, all results can be returned")
@@ -232,12 +413,20 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Uint32("shared-iterator-limit", defaultConfig.SharedIterator.Limit, "if shared-iterator-enabled is enabled, this is the limit of the number of iterators that can be shared.")
 
+	flags.Bool("request-iterator-cache-enabled", defaultConfig.RequestIteratorCache.Enabled, "enable memoizing the results of datastore iterators within a single Check request, so that the branches of the request issuing the same query read the datastore once.")
+
+	flags.Uint32("request-iterator-cache-max-results", defaultConfig.RequestIteratorCache.MaxResults, "if request-iterator-cache-enabled is enabled, this is the limit of tuples to memoize per query.")
+
 	flags.Bool("check-iterator-cache-enabled", defaultConfig.CheckIteratorCache.Enabled, "enable caching of datastore iterators. The key is a string representing a database query, and the value is a list of tuples. Each iterator is the result of a database query, for example usersets related to a specific object, or objects related to a specific user, up to a certain number of tuples per iterator. If the request's consistency is HIGHER_CONSISTENCY, this cache is not used.")
 
 	flags.Uint32("check-iterator-cache-max-results", defaultConfig.CheckIteratorCache.MaxResults, "if caching of datastore iterators of Check requests is enabled, this is the limit of tuples to cache per key.")
 
 	flags.Duration("check-iterator-cache-ttl", defaultConfig.CheckIteratorCache.TTL, "if caching of datastore iterators of Check requests is enabled, this is the TTL of each value")
 
+	flags.Bool("check-tuple-cache-enabled", defaultConfig.CheckTupleCache.Enabled, "enable caching of single tuples read by Check, including tuples which were not found. The key is the tuple, and the value is the tuple read from the datastore. If the request's consistency is HIGHER_CONSISTENCY, this cache is not used.")
+
+	flags.Duration("check-tuple-cache-ttl", defaultConfig.CheckTupleCache.TTL, "if caching of single tuples read by Check is enabled, this is the TTL of each value")
+
 	flags.Bool("list-objects-iterator-cache-enabled", defaultConfig.ListObjectsIteratorCache.Enabled, "enable caching of datastore iterators for ListObjects. The key is a string representing a database query, and the value is a list of tuples. Each iterator is the result of a database query, for example usersets related to a specific object, or objects related to a specific user, up to a certain number of tuples per iterator. If the request's consistency is HIGHER_CONSISTENCY, this cache is not used.")
 
 	flags.Uint32("list-objects-iterator-cache-max-results", defaultConfig.ListObjectsIteratorCache.MaxResults, "if caching of datastore iterators of ListObjects requests is enabled, this is the limit of tuples to cache per key.")
@@ -250,10 +439,16 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Duration("check-query-cache-ttl", defaultConfig.CheckQueryCache.TTL, "if check-query-cache-enabled, this is the TTL of each value")
 
+	flags.Duration("check-query-cache-revalidation-window", defaultConfig.CheckQueryCache.RevalidationWindow, "if check-query-cache-enabled, a hit on a frequently hit value during this period before it expires recomputes it in the background. Must be smaller than check-query-cache-ttl. If 0, background revalidation is disabled.")
+
+	flags.Uint32("check-query-cache-revalidation-min-hits", defaultConfig.CheckQueryCache.RevalidationMinHits, "if check-query-cache-revalidation-window is set, the number of hits after which a value is revalidated in the background")
+
 	flags.Bool("cache-controller-enabled", defaultConfig.CacheController.Enabled, "enabling dynamic invalidation of check query cache and check iterator cache based on whether there are recent tuple writes. If enabled, cache will be invalidated when either 1) there are tuples written to the store OR 2) the check query cache or check iterator cache TTL has expired.")
 
 	flags.Duration("cache-controller-ttl", defaultConfig.CacheController.TTL, "if cache controller is enabled, control how frequent read changes are invoked internally to query for recent tuple writes to the store.")
 
+	flags.String("cache-controller-notification-channel", defaultConfig.CacheController.NotificationChannel, "if cache controller is enabled, the Postgres LISTEN/NOTIFY channel on which every server notifies its tuple writes, so that the other servers invalidate the cached results of the store immediately. Requires the 'postgres' datastore engine. If empty, writes are not notified.")
+
 	// Unfortunately UintSlice/IntSlice does not work well when used as environment variable, we need to stick with string slice and convert back to integer
 	flags.StringSlice("request-duration-datastore-query-count-buckets", defaultConfig.RequestDurationDatastoreQueryCountBuckets, "datastore query count buckets used in labelling request_duration_ms.")
 
@@ -261,6 +456,8 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Bool("context-propagation-to-datastore", defaultConfig.ContextPropagationToDatastore, "enable propagation of a request's context to the datastore")
 
+	flags.Bool("resolution-metadata-headers", defaultConfig.ResolutionMetadataHeaders, "return the number of datastore queries, dispatches and check cache hits of Check, ListObjects and ListUsers requests in the 'openfga-datastore-query-count', 'openfga-dispatch-count' and 'openfga-check-cache-hits' response headers.")
+
 	flags.Bool("check-dispatch-throttling-enabled", defaultConfig.CheckDispatchThrottling.Enabled, "enable throttling for Check requests when the request's number of dispatches is high. Enabling this feature will prioritize dispatched requests requiring less than the configured dispatch threshold over requests whose dispatch count exceeds the configured threshold.")
 
 	flags.Duration("check-dispatch-throttling-frequency", defaultConfig.CheckDispatchThrottling.Frequency, "defines how frequent Check dispatch throttling will be evaluated. This controls how frequently throttled dispatch Check requests are dispatched.")
@@ -303,8 +500,124 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Duration("listUsers-datastore-throttle-duration", defaultConfig.ListUsersDatabaseThrottle.Duration, "defines the time for which the datastore request will be suspended for being throttled.")
 
+	flags.Duration("datastore-limiter-saturation-threshold", defaultConfig.DatastoreLimiterSaturation.Threshold, "the time a datastore read may wait for the read concurrency limiter before it is considered saturated. If 0, saturation detection is disabled.")
+
+	flags.Duration("datastore-limiter-saturation-period", defaultConfig.DatastoreLimiterSaturation.Period, "the window over which the fraction of datastore reads waiting longer than the saturation threshold is computed.")
+
+	flags.Float64("datastore-limiter-saturation-ratio", defaultConfig.DatastoreLimiterSaturation.Ratio, "the fraction of the datastore reads of the saturation period which must wait longer than the saturation threshold for the read concurrency limiter to be reported as saturated. Must be greater than 0 and at most 1.")
+
+	flags.Bool("datastore-limiter-saturation-readiness-enabled", defaultConfig.DatastoreLimiterSaturation.ReadinessEnabled, "report the server as not ready while the datastore read concurrency limiter is saturated, so that load balancers can shed load.")
+
+	flags.Uint32("datastore-read-priority-slots", defaultConfig.DatastoreReadPriority.Slots, "the number of concurrent datastore reads of every request, shared between the methods of the requests by their weights while reads wait. If 0, the reads are not arbitrated by priority.")
+
+	flags.StringSlice("datastore-read-priority-weights", defaultConfig.DatastoreReadPriority.Weights, "the weights of the methods sharing the datastore read priority slots, in the form '<method>:<weight>', e.g. 'Check:8'. If empty, Check and BatchCheck have a weight of 8 and the other methods a weight of 1.")
+
+	flags.Bool("readiness-check-cache-enabled", defaultConfig.Readiness.CheckCacheEnabled, "report the server as not ready while its check cache does not store and return entries.")
+
+	flags.Bool("readiness-tuple-change-listener-enabled", defaultConfig.Readiness.TupleChangeListenerEnabled, "report the server as not ready while the tuple change notifications of the datastore are interrupted.")
+
+	flags.Float64("datastore-circuit-breaker-failure-rate-threshold", defaultConfig.DatastoreCircuitBreaker.FailureRateThreshold, "the rate of failed datastore tuple reads and writes, between 0 and 1, above which the circuit breaker trips and fails them fast. If 0, the circuit breaker is disabled.")
+
+	flags.Int("datastore-circuit-breaker-min-requests", defaultConfig.DatastoreCircuitBreaker.MinRequests, "the number of datastore tuple reads and writes within a window below which the circuit breaker never trips.")
+
+	flags.Duration("datastore-circuit-breaker-window", defaultConfig.DatastoreCircuitBreaker.Window, "the period over which the circuit breaker computes the failure rate of datastore tuple reads and writes.")
+
+	flags.Duration("datastore-circuit-breaker-open-duration", defaultConfig.DatastoreCircuitBreaker.OpenDuration, "how long a tripped circuit breaker fails datastore tuple reads and writes fast before letting a single call probe whether the datastore recovered.")
+
+	flags.Int("datastore-retry-connection-max-retries", defaultConfig.DatastoreRetry.ConnectionMaxRetries, "the number of times datastore tuple reads failing with a connection error, e.g. a connection reset, are retried. If 0, they are not retried.")
+
+	flags.Int("datastore-retry-serialization-max-retries", defaultConfig.DatastoreRetry.SerializationMaxRetries, "the number of times datastore tuple reads failing with a serialization failure are retried. If 0, they are not retried.")
+
+	flags.Int("datastore-retry-deadlock-max-retries", defaultConfig.DatastoreRetry.DeadlockMaxRetries, "the number of times datastore tuple reads failing with a deadlock are retried. If 0, they are not retried.")
+
+	flags.Duration("datastore-retry-initial-interval", defaultConfig.DatastoreRetry.InitialInterval, "the delay before the first retry of a datastore call failing with a transient error. It doubles, with jitter, on each retry.")
+
+	flags.Duration("datastore-retry-max-interval", defaultConfig.DatastoreRetry.MaxInterval, "the maximum delay between retries of a datastore call failing with a transient error.")
+
+	flags.Bool("datastore-retry-writes", defaultConfig.DatastoreRetry.Writes, "also retry datastore tuple writes rolled back by a serialization failure or a deadlock. Writes failing with a connection error are never retried since they may have been committed.")
+
+	flags.StringSlice("datastore-fault-injection-operations", defaultConfig.DatastoreFaultInjection.Operations, "the datastore operations faults are injected into, among 'Read', 'ReadPage', 'ReadUserTuple', 'ReadUserTuples', 'ReadUsersetTuples', 'ReadStartingWithUser' and 'Write'. If empty, faults are injected into all of them. Requires the 'enable-fault-injection' experimental feature.")
+
+	flags.Duration("datastore-fault-injection-latency", defaultConfig.DatastoreFaultInjection.Latency, "the latency added to every datastore call faults are injected into. Requires the 'enable-fault-injection' experimental feature.")
+
+	flags.Float64("datastore-fault-injection-error-rate", defaultConfig.DatastoreFaultInjection.ErrorRate, "the fraction, between 0 and 1, of the datastore calls faults are injected into which fail with an injected connection error. Requires the 'enable-fault-injection' experimental feature.")
+
+	flags.Duration("datastore-fault-injection-iterator-latency", defaultConfig.DatastoreFaultInjection.IteratorLatency, "the latency added to every tuple read from the iterators of the datastore calls faults are injected into. Requires the 'enable-fault-injection' experimental feature.")
+
+	flags.StringSlice("datastore-fault-injection-policies", defaultConfig.DatastoreFaultInjection.Policies, "the faults injected into specific datastore operations, overriding the default faults, in the form '<operation>:<latency>:<error rate>:<iterator latency>', e.g. 'ReadUserTuple:50ms:0.1:0s'. Requires the 'enable-fault-injection' experimental feature.")
+
+	flags.StringSlice("protected-tuples-patterns", defaultConfig.ProtectedTuples.Patterns, "the tuples under legal hold, in the form 'type:id[#relation[@user]]' where the object id may be '*'. Deleting them requires the 'openfga-force-delete' header and a privileged principal.")
+
+	flags.StringSlice("protected-tuples-privileged-principals", defaultConfig.ProtectedTuples.PrivilegedPrincipals, "the subjects or client ids allowed to force the deletion of protected tuples.")
+
+	flags.Bool("rate-limit-enabled", defaultConfig.RateLimit.Enabled, "enable rate limiting of requests. Requests exceeding their limit are rejected with a RESOURCE_EXHAUSTED error and a 'retry-after' header.")
+
+	flags.String("rate-limit-key", defaultConfig.RateLimit.Key, "the dimension requests are rate limited by. One of 'caller', 'store' or 'store_and_caller'. The caller is identified by the client id or subject of its credentials.")
+
+	flags.Float64("rate-limit-rate", defaultConfig.RateLimit.Rate, "the number of requests per second allowed for methods without an override. If 0, these methods are not rate limited.")
+
+	flags.Int("rate-limit-burst", defaultConfig.RateLimit.Burst, "the number of requests allowed above the rate for methods without an override.")
+
+	flags.StringSlice("rate-limit-methods", defaultConfig.RateLimit.Methods, "the limits of specific methods, in the form '<method>:<rate>:<burst>', e.g. 'Check:100:200'.")
+
+	flags.Bool("load-shedding-enabled", defaultConfig.LoadShedding.Enabled, "enable shedding of requests while the server is overloaded. Rejected requests fail with an UNAVAILABLE error. ListObjects, ListUsers, Expand and ReadChanges requests are shed first, then Read and admin requests, and Check, BatchCheck and Write requests last.")
+
+	flags.Int("load-shedding-max-in-flight-cost", defaultConfig.LoadShedding.MaxInFlightCost, "the number of in-flight dispatches and datastore reads at which every request is shed. Lower priority requests are shed from 60% (low) and 85% (normal) of it.")
+
+	flags.Bool("limit-overrides-enabled", defaultConfig.LimitOverrides.Enabled, "enable the overrides of the resolution limits of a request through the 'openfga-max-resolve-depth' and 'openfga-list-objects-deadline' headers, for the trusted principals and within the configured ceilings.")
+
+	flags.StringSlice("limit-overrides-trusted-principals", defaultConfig.LimitOverrides.TrustedPrincipals, "the subjects or client ids allowed to override the resolution limits of their requests.")
+
+	flags.Uint32("limit-overrides-max-resolve-depth", defaultConfig.LimitOverrides.MaxResolveDepth, "the largest resolve node limit a request may ask for with the 'openfga-max-resolve-depth' header. If 0, the override is not allowed.")
+
+	flags.Duration("limit-overrides-max-list-objects-deadline", defaultConfig.LimitOverrides.MaxListObjectsDeadline, "the longest ListObjects deadline a request may ask for with the 'openfga-list-objects-deadline' header. If 0, the override is not allowed.")
+
+	flags.Int("authorization-model-retention-count", defaultConfig.AuthorizationModelRetention.Count, "the number of most recent authorization models kept per store. Older models and their assertions are deleted in the background. If 0, models are kept forever.")
+
+	flags.Duration("authorization-model-retention-prune-interval", defaultConfig.AuthorizationModelRetention.PruneInterval, "how often the authorization models beyond the retention count are deleted.")
+
+	flags.StringSlice("model-sync-sources", defaultConfig.ModelSync.Sources, "the URLs the authorization models of stores are fetched from, in the form '<store id>:<url>'. The models are written as the latest model of their store when they are valid and differ from it. The URL is that of a file served over HTTPS ('https://<host>/<path>'), of a file of a git repository ('git+<repository url>//<path>?ref=<branch or tag>', cloned with the git executable), or of an OCI artifact ('oci://<registry>/<repository>:<tag>//<file>'). The models are in the FGA DSL, or in JSON if the file name ends with '.json'.")
+
+	flags.Duration("model-sync-interval", defaultConfig.ModelSync.Interval, "how often the authorization models of the model sync sources are fetched.")
+
+	flags.String("trusted-context-current-time-parameter", defaultConfig.TrustedContext.CurrentTimeParameter, "the condition parameter the server sets to the current time, as an RFC 3339 timestamp, in the context of every request, overriding the value supplied by the client. If empty, it is not set.")
+
+	flags.Duration("trusted-context-current-time-precision", defaultConfig.TrustedContext.CurrentTimePrecision, "the precision the trusted current time is truncated to, so that the checks evaluated within the same interval share their cache entries. If 0, the full precision is kept.")
+
+	flags.String("trusted-context-caller-parameter", defaultConfig.TrustedContext.CallerParameter, "the condition parameter the server sets to the subject, or else the client id, of the authenticated caller in the context of every request, overriding the value supplied by the client. If empty, it is not set.")
+
+	flags.Duration("typesystem-cache-ttl", defaultConfig.TypesystemCache.TTL, "how long the validated authorization models are cached.")
+
+	flags.Int("typesystem-cache-size", defaultConfig.TypesystemCache.Size, "the maximum number of validated authorization models cached.")
+
+	flags.StringSlice("typesystem-cache-store-ttls", defaultConfig.TypesystemCache.StoreTTLs, "the TTLs of the cached authorization models of specific stores, overriding the typesystem cache TTL, in the form '<store id>:<duration>'.")
+
+	flags.StringSlice("typesystem-cache-warmup-stores", defaultConfig.TypesystemCache.WarmupStores, "the stores whose latest authorization model is cached on startup. The server is not ready until they are.")
+
+	flags.String("typesystem-cache-warmup-mru-file", defaultConfig.TypesystemCache.WarmupMRUFile, "the file the most recently used stores are persisted to, so that their latest authorization model is also cached on the next startup. If empty, they are not persisted.")
+
+	flags.String("typesystem-cache-redis-addr", defaultConfig.TypesystemCache.Redis.Addr, "the 'host:port' address of a Redis server the validated authorization models are also cached in, shared by the servers, so that a model is validated once rather than by every server. If empty, Redis is not used.")
+
+	flags.String("typesystem-cache-redis-username", defaultConfig.TypesystemCache.Redis.Username, "the username authenticating to the Redis server of the typesystem cache.")
+
+	flags.String("typesystem-cache-redis-password", defaultConfig.TypesystemCache.Redis.Password, "the password authenticating to the Redis server of the typesystem cache.")
+
+	flags.Int("typesystem-cache-redis-db", defaultConfig.TypesystemCache.Redis.DB, "the database selected on the Redis server of the typesystem cache.")
+
+	flags.Bool("typesystem-cache-redis-tls", defaultConfig.TypesystemCache.Redis.TLS, "enables TLS on the connections to the Redis server of the typesystem cache.")
+
+	flags.Int("max-concurrent-jobs", defaultConfig.MaxConcurrentJobs, "the maximum number of jobs, e.g. imports, exports and deletes of tuples, run at once by the server. The other jobs wait until one completes.")
+
+	flags.Int("max-job-tuples", defaultConfig.MaxJobTuples, "the maximum number of tuples written by an import or read by an export, both of which persist their tuples in the job. Larger imports are rejected, and larger exports fail.")
+
 	flags.Duration("request-timeout", defaultConfig.RequestTimeout, "configures request timeout.  If both HTTP upstream timeout and request timeout are specified, request timeout will be used.")
 
+	flags.Duration("shutdown-drain-delay", defaultConfig.ShutdownDrainDelay, "the duration the new requests keep being served on shutdown once the server reports itself as not ready, so that the load balancers stop routing them to the server before they are refused")
+
+	flags.Duration("shutdown-drain-timeout", defaultConfig.ShutdownDrainTimeout, "the duration the requests in flight are given to complete on shutdown, while the new requests are refused and the server reports itself as not ready")
+
+	flags.StringSlice("method-timeouts", defaultConfig.MethodTimeouts, "the timeouts of specific methods, overriding the request timeout, in the form '<method>:<duration>', e.g. 'Check:500ms'.")
+
 	// NOTE: if you add a new flag here, update the function below, too
 
 	cmd.PreRun = bindRunFlagsFunc(flags)
@@ -343,7 +656,31 @@ func run(_ *cobra.Command, _ []string) {
 		panic(err)
 	}
 
-	logger := logger.MustNewLogger(config.Log.Format, config.Log.Level, config.Log.TimestampFormat)
+	logOptions := []logger.OptionLogger{
+		logger.WithFormat(config.Log.Format),
+		logger.WithLevel(config.Log.Level),
+		logger.WithTimestampFormat(config.Log.TimestampFormat),
+		logger.WithOutputPaths(config.Log.OutputPaths...),
+	}
+	if config.Log.Rotation.Enabled {
+		logOptions = append(logOptions, logger.WithRotation(logger.Rotation{
+			MaxSizeMB:  config.Log.Rotation.MaxSize,
+			MaxAge:     config.Log.Rotation.MaxAge,
+			MaxBackups: config.Log.Rotation.MaxBackups,
+			Compress:   config.Log.Rotation.Compress,
+		}))
+	}
+	if config.Log.Sampling.Enabled {
+		logOptions = append(logOptions, logger.WithSampling(config.Log.Sampling.PerSecond, config.Log.Sampling.Thereafter))
+	}
+	if config.Log.Security.Enabled {
+		logOptions = append(logOptions, logger.WithSecuritySink(config.Log.Security.Format, config.Log.Security.OutputPaths...))
+	}
+
+	logger, err := logger.NewLogger(logOptions...)
+	if err != nil {
+		panic(err)
+	}
 	serverCtx := &ServerContext{Logger: logger}
 	if err := serverCtx.Run(context.Background(), config); err != nil {
 		panic(err)
@@ -366,6 +703,75 @@ func convertStringArrayToUintArray(stringArray []string) []uint {
 	return uintArray
 }
 
+// datastoreRetryConfig returns the retry policies of the classes of transient datastore errors with retries.
+func datastoreRetryConfig(config serverconfig.DatastoreRetryConfig) storagewrappers.RetryConfig {
+	policies := map[storage.TransientErrorClass]storagewrappers.RetryPolicy{}
+	for class, maxRetries := range map[storage.TransientErrorClass]int{
+		storage.TransientConnection:    config.ConnectionMaxRetries,
+		storage.TransientSerialization: config.SerializationMaxRetries,
+		storage.TransientDeadlock:      config.DeadlockMaxRetries,
+	} {
+		if maxRetries > 0 {
+			policies[class] = storagewrappers.RetryPolicy{
+				MaxRetries:      maxRetries,
+				InitialInterval: config.InitialInterval,
+				MaxInterval:     config.MaxInterval,
+			}
+		}
+	}
+	return storagewrappers.RetryConfig{Policies: policies, RetryWrites: config.Writes}
+}
+
+// tupleChangeListener returns the datastore if it notifies the tuple writes on the configured channel, nil otherwise.
+func tupleChangeListener(config *serverconfig.Config, datastore storage.OpenFGADatastore) storage.TupleChangeListener {
+	if config.CacheController.NotificationChannel == "" {
+		return nil
+	}
+	listener, ok := datastore.(storage.TupleChangeListener)
+	if !ok {
+		return nil
+	}
+	return listener
+}
+
+// datastoreFaultPolicies returns the faults injected into each datastore operation: the policy of the operation if
+// it has one, the default faults otherwise.
+func datastoreFaultPolicies(config serverconfig.DatastoreFaultInjectionConfig) (map[string]storagewrappers.FaultPolicy, error) {
+	if !config.Enabled() {
+		return nil, nil
+	}
+	operationPolicies, err := serverconfig.ParseDatastoreFaultPolicies(config.Policies)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]storagewrappers.FaultPolicy, len(storagewrappers.FaultInjectionOperations))
+	if config.Latency > 0 || config.ErrorRate > 0 || config.IteratorLatency > 0 {
+		operations := config.Operations
+		if len(operations) == 0 {
+			operations = storagewrappers.FaultInjectionOperations
+		}
+		for _, operation := range operations {
+			policies[operation] = storagewrappers.FaultPolicy{
+				Latency:         config.Latency,
+				ErrorRate:       config.ErrorRate,
+				IteratorLatency: config.IteratorLatency,
+			}
+		}
+	}
+	for operation, policy := range operationPolicies {
+		if !slices.Contains(storagewrappers.FaultInjectionOperations, operation) {
+			return nil, fmt.Errorf("invalid datastore fault policy, unknown operation '%s'", operation)
+		}
+		policies[operation] = storagewrappers.FaultPolicy{
+			Latency:         policy.Latency,
+			ErrorRate:       policy.ErrorRate,
+			IteratorLatency: policy.IteratorLatency,
+		}
+	}
+	return policies, nil
+}
+
 // telemetryConfig returns the function that must be called to shut down tracing.
 // The context provided to this function should be error-free, or shut down will be incomplete.
 func (s *ServerContext) telemetryConfig(config *serverconfig.Config) func() error {
@@ -387,6 +793,16 @@ func (s *ServerContext) telemetryConfig(config *serverconfig.Config) func() erro
 			options = append(options, telemetry.WithOTLPInsecure())
 		}
 
+		// the method sample ratios were validated by config.Verify
+		if methodSampleRatios, _ := serverconfig.ParseMethodSampleRatios(config.Trace.MethodSampleRatios); len(methodSampleRatios) > 0 {
+			s.Logger.Info(fmt.Sprintf("🕵 tracing method sampling ratios: %v", config.Trace.MethodSampleRatios))
+			options = append(options, telemetry.WithMethodSamplingRatios(methodSampleRatios))
+		}
+
+		if config.Trace.SampleErrors {
+			options = append(options, telemetry.WithErrorSampling())
+		}
+
 		tp := telemetry.MustNewTracerProvider(options...)
 		return func() error {
 			// can take up to 5 seconds to complete (https://github.com/open-telemetry/opentelemetry-go/blob/aebcbfcbc2962957a578e9cb3e25dc834125e318/sdk/trace/batch_span_processor.go#L97)
@@ -401,7 +817,7 @@ func (s *ServerContext) telemetryConfig(config *serverconfig.Config) func() erro
 	}
 }
 
-func (s *ServerContext) datastoreConfig(config *serverconfig.Config) (storage.OpenFGADatastore, encoder.ContinuationTokenSerializer, error) {
+func (s *ServerContext) datastoreConfig(ctx context.Context, config *serverconfig.Config) (storage.OpenFGADatastore, encoder.ContinuationTokenSerializer, error) {
 	// SQL Token Serializer by default
 	tokenSerializer := sqlcommon.NewSQLContinuationTokenSerializer()
 	datastoreOptions := []sqlcommon.DatastoreOption{
@@ -414,12 +830,31 @@ func (s *ServerContext) datastoreConfig(config *serverconfig.Config) (storage.Op
 		sqlcommon.WithMaxIdleConns(config.Datastore.MaxIdleConns),
 		sqlcommon.WithConnMaxIdleTime(config.Datastore.ConnMaxIdleTime),
 		sqlcommon.WithConnMaxLifetime(config.Datastore.ConnMaxLifetime),
+		sqlcommon.WithReadReplicaURIs(config.Datastore.ReadReplicaURIs...),
+		sqlcommon.WithNotificationChannel(config.CacheController.NotificationChannel),
 	}
 
 	if config.Datastore.Metrics.Enabled {
 		datastoreOptions = append(datastoreOptions, sqlcommon.WithMetrics())
 	}
 
+	if config.Datastore.UsernameFile != "" || config.Datastore.PasswordFile != "" {
+		credentialsProvider, err := sqlcommon.NewFileCredentialsProvider(config.Datastore.UsernameFile, config.Datastore.PasswordFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		go credentialsProvider.Watch(ctx, config.Datastore.CredentialsRefreshInterval, s.Logger)
+		datastoreOptions = append(datastoreOptions, sqlcommon.WithCredentialsProvider(credentialsProvider))
+	}
+
+	if config.Datastore.IAMAuth.Enabled {
+		authTokenProvider, err := sqlcommon.NewRDSIAMAuthTokenProvider(ctx, config.Datastore.IAMAuth.Region)
+		if err != nil {
+			return nil, nil, err
+		}
+		datastoreOptions = append(datastoreOptions, sqlcommon.WithAuthTokenProvider(authTokenProvider))
+	}
+
 	dsCfg := sqlcommon.NewConfig(datastoreOptions...)
 
 	var datastore storage.OpenFGADatastore
@@ -431,8 +866,17 @@ func (s *ServerContext) datastoreConfig(config *serverconfig.Config) (storage.Op
 		opts := []memory.StorageOption{
 			memory.WithMaxTypesPerAuthorizationModel(config.MaxTypesPerAuthorizationModel),
 			memory.WithMaxTuplesPerWrite(config.MaxTuplesPerWrite),
+			memory.WithMaxTuplesPerStore(config.Datastore.MemoryMaxTuplesPerStore),
+			memory.WithMaxEstimatedBytes(config.Datastore.MemoryMaxBytes),
+		}
+		if config.Datastore.SnapshotPath == "" {
+			datastore = memory.New(opts...)
+			break
+		}
+		datastore, err = memory.NewWithSnapshots(config.Datastore.SnapshotPath, config.Datastore.SnapshotInterval, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("initialize memory datastore: %w", err)
 		}
-		datastore = memory.New(opts...)
 	case "mysql":
 		datastore, err = mysql.New(config.Datastore.URI, dsCfg)
 		if err != nil {
@@ -449,7 +893,17 @@ func (s *ServerContext) datastoreConfig(config *serverconfig.Config) (storage.Op
 			return nil, nil, fmt.Errorf("initialize sqlite datastore: %w", err)
 		}
 	default:
-		return nil, nil, fmt.Errorf("storage engine '%s' is unsupported", config.Datastore.Engine)
+		registered, ok := engine.Lookup(config.Datastore.Engine)
+		if !ok {
+			return nil, nil, fmt.Errorf("storage engine '%s' is unsupported", config.Datastore.Engine)
+		}
+		datastore, err = registered.New(config.Datastore.URI, dsCfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("initialize %s datastore: %w", config.Datastore.Engine, err)
+		}
+		if registered.ContinuationTokenSerializer != nil {
+			tokenSerializer = registered.ContinuationTokenSerializer
+		}
 	}
 
 	s.Logger.Info(fmt.Sprintf("using '%v' storage engine", config.Datastore.Engine))
@@ -468,9 +922,17 @@ func (s *ServerContext) authenticatorConfig(config *serverconfig.Config) (authn.
 	case "preshared":
 		s.Logger.Info("using 'preshared' authentication")
 		authenticator, err = presharedkey.NewPresharedKeyAuthenticator(config.Authn.Keys)
+	case "storetoken":
+		s.Logger.Info("using 'storetoken' authentication")
+		authenticator, err = storetoken.NewStoreTokenAuthenticator(config.Authn.SigningKeys, config.Authn.Keys)
+	case "mtls":
+		s.Logger.Info("using 'mtls' authentication")
+		authenticator = mtls.NewMTLSAuthenticator()
 	case "oidc":
 		s.Logger.Info("using 'oidc' authentication")
-		authenticator, err = oidc.NewRemoteOidcAuthenticator(config.Authn.Issuer, config.Authn.IssuerAliases, config.Authn.Audience, config.Authn.Subjects, config.Authn.ClientIDClaims)
+		authenticator, err = oidc.NewRemoteOidcAuthenticator(config.Authn.Issuer, config.Authn.IssuerAliases, config.Authn.Audience, config.Authn.Subjects, config.Authn.ClientIDClaims, config.Authn.StoreIDClaim,
+			oidc.WithAdditionalIssuers(config.Authn.AdditionalIssuers, config.Authn.AdditionalAudiences),
+		)
 	default:
 		return nil, fmt.Errorf("unsupported authentication method '%v'", config.Authn.Method)
 	}
@@ -486,6 +948,10 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, os.Kill, syscall.SIGTERM)
 	defer stop()
 
+	if config.Redaction.Mode == string(redact.ModeHash) && config.Redaction.HashKey == "" {
+		s.Logger.Warn("the redaction hash key is empty, the redacted ids can be recovered by hashing guesses")
+	}
+
 	tracerProviderCloser := s.telemetryConfig(config)
 
 	if len(config.Experimentals) > 0 {
@@ -497,7 +963,7 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		experimentals = append(experimentals, server.ExperimentalFeatureFlag(feature))
 	}
 
-	datastore, continuationTokenSerializer, err := s.datastoreConfig(config)
+	datastore, continuationTokenSerializer, err := s.datastoreConfig(ctx, config)
 	if err != nil {
 		return err
 	}
@@ -509,7 +975,18 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 	}
 
 	serverOpts := []grpc.ServerOption{
-		grpc.MaxRecvMsgSize(serverconfig.DefaultMaxRPCMessageSizeInBytes),
+		grpc.MaxRecvMsgSize(config.GRPC.MaxRecvMsgSizeBytes),
+		grpc.MaxSendMsgSize(config.GRPC.MaxSendMsgSizeBytes),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  config.GRPC.Keepalive.Time,
+			Timeout:               config.GRPC.Keepalive.Timeout,
+			MaxConnectionAge:      config.GRPC.Keepalive.MaxConnectionAge,
+			MaxConnectionAgeGrace: config.GRPC.Keepalive.MaxConnectionAgeGrace,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             config.GRPC.Keepalive.MinTime,
+			PermitWithoutStream: config.GRPC.Keepalive.PermitWithoutStream,
+		}),
 		grpc.ChainUnaryInterceptor(
 			[]grpc.UnaryServerInterceptor{
 				grpc_recovery.UnaryServerInterceptor( // panic middleware must be 1st in chain
@@ -517,6 +994,7 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 						recovery.PanicRecoveryHandler(s.Logger),
 					),
 				),
+				errorinfo.NewUnaryInterceptor(),       // attach the reason to the errors of all the other interceptors
 				grpc_ctxtags.UnaryServerInterceptor(), // needed for logging
 				requestid.NewUnaryInterceptor(),       // add request_id to ctxtags
 			}...,
@@ -528,19 +1006,192 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 						recovery.PanicRecoveryHandler(s.Logger),
 					),
 				),
+				errorinfo.NewStreamingInterceptor(),    // attach the reason to the errors of all the other interceptors
 				grpc_ctxtags.StreamServerInterceptor(), // needed for logging
 				requestid.NewStreamingInterceptor(),    // add request_id to ctxtags
 			}...,
 		),
 	}
+	if config.GRPC.MaxConcurrentStreams > 0 {
+		serverOpts = append(serverOpts, grpc.MaxConcurrentStreams(config.GRPC.MaxConcurrentStreams))
+	}
+
+	methodTimeouts, err := serverconfig.ParseMethodTimeouts(config.MethodTimeouts)
+	if err != nil {
+		return err
+	}
+
+	faultPolicies, err := datastoreFaultPolicies(config.DatastoreFaultInjection)
+	if err != nil {
+		return err
+	}
+
+	typesystemCacheStoreTTLs, err := serverconfig.ParseStoreTTLs(config.TypesystemCache.StoreTTLs)
+	if err != nil {
+		return err
+	}
+
+	modelSyncSources, err := serverconfig.ParseModelSyncSources(config.ModelSync.Sources)
+	if err != nil {
+		return err
+	}
+
+	var typesystemSharedCache typesystem.SharedModelCache
+	if config.TypesystemCache.Redis.Addr != "" {
+		redisClient := redis.New(redis.Config{
+			Addr:     config.TypesystemCache.Redis.Addr,
+			Username: config.TypesystemCache.Redis.Username,
+			Password: config.TypesystemCache.Redis.Password,
+			DB:       config.TypesystemCache.Redis.DB,
+			TLS:      config.TypesystemCache.Redis.TLS,
+		})
+		defer redisClient.Close()
+
+		// the models are still resolved from the datastore if Redis is unreachable
+		if err := redisClient.Ping(ctx); err != nil {
+			s.Logger.Warn("failed to connect to the Redis server of the typesystem cache", zap.Error(err))
+		}
+		typesystemSharedCache = redisClient
+	}
+
+	datastoreReadPriorityWeights, err := serverconfig.ParseMethodWeights(config.DatastoreReadPriority.Weights)
+	if err != nil {
+		return err
+	}
+
+	svr := server.MustNewServerWithOpts(
+		server.WithDatastore(datastore),
+		server.WithContinuationTokenSerializer(continuationTokenSerializer),
+		server.WithAuthorizationModelCacheSize(config.Datastore.MaxCacheSize),
+		server.WithLogger(s.Logger),
+		server.WithTransport(gateway.NewRPCTransport(s.Logger)),
+		server.WithResolveNodeLimit(config.ResolveNodeLimit),
+		server.WithResolveNodeBreadthLimit(config.ResolveNodeBreadthLimit),
+		server.WithCheckDirectTupleBatchSize(config.CheckDirectTupleBatchSize),
+		server.WithCheckDirectTupleBatchMaxDelay(config.CheckDirectTupleBatchMaxDelay),
+		server.WithCheckDispatchPoolSize(config.CheckDispatchPoolSize),
+		server.WithCheckUnionBranchOrdering(config.CheckUnionBranchOrderingEnabled, nil),
+		server.WithCheckMembershipIndex(config.CheckMembershipIndex.Relations, config.CheckMembershipIndex.RefreshInterval, config.CheckMembershipIndex.MaxStaleness),
+		server.WithCheckMaterializedViews(config.CheckMaterializedViews.Views, config.CheckMaterializedViews.RefreshInterval, config.CheckMaterializedViews.MaxStaleness),
+		server.WithChangelogHorizonOffset(config.ChangelogHorizonOffset),
+		server.WithContinuationTokenTTL(config.ContinuationTokenTTL),
+		server.WithUnversionedContinuationTokenWindow(config.ContinuationTokenUnversionedWindow),
+		server.WithMaxTuplesPerWrite(config.MaxTuplesPerWrite),
+		server.WithMaxPageSize(config.MaxPageSize),
+		server.WithListObjectsDeadline(config.ListObjectsDeadline),
+		server.WithListObjectsMaxResults(config.ListObjectsMaxResults),
+		server.WithListObjectsDatastoreQueryBudget(config.ListObjectsDatastoreQueryBudget),
+		server.WithListUsersDeadline(config.ListUsersDeadline),
+		server.WithListUsersMaxResults(config.ListUsersMaxResults),
+		server.WithMaxConcurrentReadsForListObjects(config.MaxConcurrentReadsForListObjects),
+		server.WithMaxConcurrentReadsForCheck(config.MaxConcurrentReadsForCheck),
+		server.WithMaxConcurrentReadsForListUsers(config.MaxConcurrentReadsForListUsers),
+		server.WithCacheControllerEnabled(config.CacheController.Enabled),
+		server.WithCacheControllerTTL(config.CacheController.TTL),
+		server.WithCheckCacheLimit(config.CheckCache.Limit),
+		server.WithCheckIteratorCacheEnabled(config.CheckIteratorCache.Enabled),
+		server.WithCheckIteratorCacheMaxResults(config.CheckIteratorCache.MaxResults),
+		server.WithCheckIteratorCacheTTL(config.CheckIteratorCache.TTL),
+		server.WithCheckTupleCacheEnabled(config.CheckTupleCache.Enabled),
+		server.WithCheckTupleCacheTTL(config.CheckTupleCache.TTL),
+		server.WithCheckQueryCacheEnabled(config.CheckQueryCache.Enabled),
+		server.WithCheckQueryCacheTTL(config.CheckQueryCache.TTL),
+		server.WithCheckQueryCacheRevalidation(config.CheckQueryCache.RevalidationWindow, config.CheckQueryCache.RevalidationMinHits),
+		server.WithRequestDurationByQueryHistogramBuckets(convertStringArrayToUintArray(config.RequestDurationDatastoreQueryCountBuckets)),
+		server.WithRequestDurationByDispatchCountHistogramBuckets(convertStringArrayToUintArray(config.RequestDurationDispatchCountBuckets)),
+		server.WithMaxAuthorizationModelSizeInBytes(config.MaxAuthorizationModelSizeInBytes),
+		server.WithContextPropagationToDatastore(config.ContextPropagationToDatastore),
+		server.WithResolutionMetadataHeaders(config.ResolutionMetadataHeaders),
+		server.WithDispatchThrottlingCheckResolverEnabled(config.CheckDispatchThrottling.Enabled),
+		server.WithDispatchThrottlingCheckResolverFrequency(config.CheckDispatchThrottling.Frequency),
+		server.WithDispatchThrottlingCheckResolverThreshold(config.CheckDispatchThrottling.Threshold),
+		server.WithDispatchThrottlingCheckResolverMaxThreshold(config.CheckDispatchThrottling.MaxThreshold),
+		server.WithListObjectsDispatchThrottlingEnabled(config.ListObjectsDispatchThrottling.Enabled),
+		server.WithListObjectsDispatchThrottlingFrequency(config.ListObjectsDispatchThrottling.Frequency),
+		server.WithListObjectsDispatchThrottlingThreshold(config.ListObjectsDispatchThrottling.Threshold),
+		server.WithListObjectsDispatchThrottlingMaxThreshold(config.ListObjectsDispatchThrottling.MaxThreshold),
+		server.WithListUsersDispatchThrottlingEnabled(config.ListUsersDispatchThrottling.Enabled),
+		server.WithListUsersDispatchThrottlingFrequency(config.ListUsersDispatchThrottling.Frequency),
+		server.WithListUsersDispatchThrottlingThreshold(config.ListUsersDispatchThrottling.Threshold),
+		server.WithListUsersDispatchThrottlingMaxThreshold(config.ListUsersDispatchThrottling.MaxThreshold),
+		server.WithCheckDatabaseThrottle(config.CheckDatabaseThrottle.Threshold, config.CheckDatabaseThrottle.Duration),
+		server.WithListObjectsDatabaseThrottle(config.ListObjectsDatabaseThrottle.Threshold, config.ListObjectsDatabaseThrottle.Duration),
+		server.WithListUsersDatabaseThrottle(config.ListUsersDatabaseThrottle.Threshold, config.ListUsersDatabaseThrottle.Duration),
+		server.WithListObjectsIteratorCacheEnabled(config.ListObjectsIteratorCache.Enabled),
+		server.WithListObjectsIteratorCacheMaxResults(config.ListObjectsIteratorCache.MaxResults),
+		server.WithListObjectsIteratorCacheTTL(config.ListObjectsIteratorCache.TTL),
+		server.WithMaxChecksPerBatchCheck(config.MaxChecksPerBatchCheck),
+		server.WithMaxContextSizeBytes(config.MaxContextSizeBytes),
+		server.WithMaxContextDepth(config.MaxContextDepth),
+		server.WithMaxContextualTuples(config.MaxContextualTuples),
+		server.WithMethodTimeouts(methodTimeouts),
+		server.WithMaxConcurrentChecksPerBatchCheck(config.MaxConcurrentChecksPerBatchCheck),
+		server.WithSharedIteratorEnabled(config.SharedIterator.Enabled),
+		server.WithSharedIteratorLimit(config.SharedIterator.Limit),
+		// The shared iterator watchdog timeout is set to the longest request timeout + 2 seconds
+		// to provide a small buffer for operations that might slightly exceed the request timeout.
+		server.WithSharedIteratorTTL(serverconfig.MaxRequestTimeout(config)+2*time.Second),
+		server.WithRequestIteratorCacheEnabled(config.RequestIteratorCache.Enabled),
+		server.WithRequestIteratorCacheMaxResults(config.RequestIteratorCache.MaxResults),
+		server.WithDatastoreLimiterSaturation(config.DatastoreLimiterSaturation.Threshold, config.DatastoreLimiterSaturation.Period, config.DatastoreLimiterSaturation.Ratio),
+		server.WithDatastoreLimiterSaturationReadinessEnabled(config.DatastoreLimiterSaturation.ReadinessEnabled),
+		server.WithDatastoreReadPriority(config.DatastoreReadPriority.Slots, datastoreReadPriorityWeights),
+		server.WithCheckCacheReadinessEnabled(config.Readiness.CheckCacheEnabled),
+		server.WithTupleChangeListenerReadinessEnabled(config.Readiness.TupleChangeListenerEnabled),
+		server.WithDatastoreHedgeDelay(config.Datastore.HedgeDelay),
+		server.WithDatastoreMaxConcurrentReads(config.Datastore.MaxConcurrentReads),
+		server.WithDatastoreStatementTimeouts(config.Datastore.ReadTimeout, config.Datastore.WriteTimeout),
+		server.WithDatastoreSlowQueryThreshold(config.Datastore.SlowQueryThreshold),
+		server.WithRedaction(redact.Mode(config.Redaction.Mode), config.Redaction.HashKey),
+		server.WithRequestMetricsStoreIDLabel(config.Metrics.StoreIDLabel.Enabled, config.Metrics.StoreIDLabel.Stores, config.Metrics.StoreIDLabel.Limit),
+		server.WithDatastoreRetry(datastoreRetryConfig(config.DatastoreRetry)),
+		server.WithDatastoreFaultInjection(faultPolicies),
+		server.WithTupleChangeListener(tupleChangeListener(config, datastore)),
+		server.WithDatastoreCircuitBreaker(
+			config.DatastoreCircuitBreaker.FailureRateThreshold,
+			config.DatastoreCircuitBreaker.MinRequests,
+			config.DatastoreCircuitBreaker.Window,
+			config.DatastoreCircuitBreaker.OpenDuration,
+		),
+		server.WithProtectedTuples(config.ProtectedTuples.Patterns, config.ProtectedTuples.PrivilegedPrincipals),
+		server.WithAuthorizationModelRetention(config.AuthorizationModelRetention.Count, config.AuthorizationModelRetention.PruneInterval),
+		server.WithModelSync(modelSyncSources, config.ModelSync.Interval),
+		server.WithTrustedContextParameters(config.TrustedContext.CurrentTimeParameter, config.TrustedContext.CallerParameter),
+		server.WithTrustedCurrentTimePrecision(config.TrustedContext.CurrentTimePrecision),
+		server.WithMaxConcurrentJobs(config.MaxConcurrentJobs),
+		server.WithMaxJobTuples(config.MaxJobTuples),
+		server.WithTypesystemCacheTTL(config.TypesystemCache.TTL, typesystemCacheStoreTTLs),
+		server.WithTypesystemCacheSize(config.TypesystemCache.Size),
+		server.WithTypesystemCacheWarmup(config.TypesystemCache.WarmupStores, config.TypesystemCache.WarmupMRUFile),
+		server.WithTypesystemSharedCache(typesystemSharedCache),
+		server.WithExperimentals(experimentals...),
+		server.WithDrainDelay(config.ShutdownDrainDelay),
+		server.WithAccessControlParams(config.AccessControl.Enabled, config.AccessControl.StoreID, config.AccessControl.ModelID, config.Authn.Method),
+		server.WithContext(ctx),
+	)
 
 	if config.RequestTimeout > 0 {
-		timeoutMiddleware := middleware.NewTimeoutInterceptor(config.RequestTimeout, s.Logger)
+		// the handlers of the methods with a timeout of their own enforce it
+		timeoutMiddleware := middleware.NewTimeoutInterceptor(config.RequestTimeout, s.Logger,
+			middleware.WithExcludedMethods(slices.Collect(maps.Keys(methodTimeouts))...),
+		)
 
 		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(timeoutMiddleware.NewUnaryTimeoutInterceptor()))
 		serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(timeoutMiddleware.NewStreamTimeoutInterceptor()))
 	}
 
+	if config.AccessLog.Enabled {
+		accessLogger, err := accesslog.NewAccessLogger(s.Logger, config.AccessLog.Fields, config.AccessLog.SkippedMethods)
+		if err != nil {
+			return err
+		}
+		// the tags of the request are read once it is served, so the access log can precede the interceptors setting them
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(accessLogger.NewUnaryInterceptor()),
+			grpc.ChainStreamInterceptor(accessLogger.NewStreamingInterceptor()),
+		)
+	}
+
 	serverOpts = append(serverOpts,
 		grpc.ChainUnaryInterceptor(
 			[]grpc.UnaryServerInterceptor{
@@ -556,7 +1207,7 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		),
 	)
 
-	if config.Metrics.Enabled {
+	if config.Metrics.Enabled || config.Metrics.OTLP.Enabled {
 		serverOpts = append(serverOpts,
 			grpc.ChainUnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
 			grpc.ChainStreamInterceptor(grpc_prometheus.StreamServerInterceptor))
@@ -570,21 +1221,86 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		serverOpts = append(serverOpts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
 	}
 
+	// the requests are tracked once they are counted, and before they are admitted, authenticated and authorized, so
+	// that the refused requests cost no datastore read
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(server.NewDrainUnaryInterceptor(svr)),
+		grpc.ChainStreamInterceptor(server.NewDrainStreamingInterceptor(svr)),
+	)
+
+	if config.LoadShedding.Enabled {
+		admissionController := loadshedding.NewAdmissionController(int64(config.LoadShedding.MaxInFlightCost))
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(loadshedding.NewUnaryInterceptor(admissionController)),
+			grpc.ChainStreamInterceptor(loadshedding.NewStreamingInterceptor(admissionController)),
+		)
+	}
+
 	serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(
 		[]grpc.UnaryServerInterceptor{
-			grpcauth.UnaryServerInterceptor(authnmw.AuthFunc(authenticator)),
+			grpcauth.UnaryServerInterceptor(authnmw.AuthFunc(authenticator, s.Logger)),
+			authnmw.NewStoreScopeUnaryInterceptor(),
 		}...),
-		grpc.ChainStreamInterceptor(
-			[]grpc.StreamServerInterceptor{
-				grpcauth.StreamServerInterceptor(authnmw.AuthFunc(authenticator)),
-				// The following interceptors wrap the server stream with our own
-				// wrapper and must come last.
-				storeid.NewStreamingInterceptor(),
-				logging.NewStreamingLoggingInterceptor(s.Logger),
-			}...,
-		),
+		grpc.ChainStreamInterceptor(grpcauth.StreamServerInterceptor(authnmw.AuthFunc(authenticator, s.Logger))),
 	)
 
+	if config.RateLimit.Enabled {
+		methodLimits, err := ratelimit.ParseMethodLimits(config.RateLimit.Methods)
+		if err != nil {
+			return err
+		}
+		rateLimiter, err := ratelimit.NewRateLimiter(
+			ratelimit.Key(config.RateLimit.Key),
+			ratelimit.Limit{Rate: config.RateLimit.Rate, Burst: config.RateLimit.Burst},
+			methodLimits,
+		)
+		if err != nil {
+			return err
+		}
+		defer rateLimiter.Close()
+
+		// the caller is only known once the request is authenticated
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(ratelimit.NewUnaryInterceptor(rateLimiter)),
+			grpc.ChainStreamInterceptor(ratelimit.NewStreamingInterceptor(rateLimiter)),
+		)
+	}
+
+	if config.LimitOverrides.Enabled {
+		// the caller is only trusted once the request is authenticated
+		overridesValidator := limitoverrides.NewValidator(limitoverrides.Ceilings{
+			MaxResolveDepth:     config.LimitOverrides.MaxResolveDepth,
+			ListObjectsDeadline: config.LimitOverrides.MaxListObjectsDeadline,
+		}, config.LimitOverrides.TrustedPrincipals)
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(limitoverrides.NewUnaryInterceptor(overridesValidator)),
+			grpc.ChainStreamInterceptor(limitoverrides.NewStreamingInterceptor(overridesValidator)),
+		)
+	}
+
+	if err := svr.ResumeJobs(ctx); err != nil {
+		// The jobs left interrupted are resumed on the next startup.
+		s.Logger.Warn("failed to resume the interrupted jobs", zap.Error(err))
+	}
+
+	if svr.IsAccessControlEnabled() {
+		// Store scoped API calls are authorized against the access control store before reaching the handlers.
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(authzmw.NewUnaryInterceptor(svr.Authorizer(), s.Logger)),
+			grpc.ChainStreamInterceptor(authzmw.NewStreamingInterceptor(svr.Authorizer(), s.Logger)),
+		)
+	}
+
+	serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(
+		[]grpc.StreamServerInterceptor{
+			// The following interceptors wrap the server stream with our own
+			// wrapper and must come last.
+			authnmw.NewStoreScopeStreamingInterceptor(),
+			storeid.NewStreamingInterceptor(),
+			logging.NewStreamingLoggingInterceptor(s.Logger),
+		}...,
+	))
+
 	if config.GRPC.TLS.Enabled {
 		if config.GRPC.TLS.CertPath == "" || config.GRPC.TLS.KeyPath == "" {
 			return errors.New("'grpc.tls.cert' and 'grpc.tls.key' configs must be set")
@@ -593,9 +1309,20 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		if err != nil {
 			return err
 		}
-		creds := credentials.NewTLS(&tls.Config{
+		tlsConfig := &tls.Config{
 			GetCertificate: grpcGetCertificate,
-		})
+		}
+		if config.Authn.Method == "mtls" {
+			clientCAs, err := mtls.NewClientCAs(config.Authn.ClientCAPath, s.Logger)
+			if err != nil {
+				return err
+			}
+			go clientCAs.Watch(ctx)
+
+			tlsConfig = clientCAs.TLSConfig(tlsConfig)
+			s.Logger.Info("gRPC clients must present a certificate issued by the configured client CAs")
+		}
+		creds := credentials.NewTLS(tlsConfig)
 
 		serverOpts = append(serverOpts, grpc.Creds(creds))
 
@@ -630,7 +1357,9 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 	var metricsServer *http.Server
 	if config.Metrics.Enabled {
 		mux := http.NewServeMux()
-		mux.Handle("/metrics", promhttp.Handler())
+		// the OpenMetrics format exposes the exemplars linking the latency histograms to their traces
+		mux.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+			promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})))
 
 		metricsServer = &http.Server{Addr: config.Metrics.Addr, Handler: mux}
 
@@ -645,63 +1374,31 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		}()
 	}
 
-	svr := server.MustNewServerWithOpts(
-		server.WithDatastore(datastore),
-		server.WithContinuationTokenSerializer(continuationTokenSerializer),
-		server.WithAuthorizationModelCacheSize(config.Datastore.MaxCacheSize),
-		server.WithLogger(s.Logger),
-		server.WithTransport(gateway.NewRPCTransport(s.Logger)),
-		server.WithResolveNodeLimit(config.ResolveNodeLimit),
-		server.WithResolveNodeBreadthLimit(config.ResolveNodeBreadthLimit),
-		server.WithChangelogHorizonOffset(config.ChangelogHorizonOffset),
-		server.WithListObjectsDeadline(config.ListObjectsDeadline),
-		server.WithListObjectsMaxResults(config.ListObjectsMaxResults),
-		server.WithListUsersDeadline(config.ListUsersDeadline),
-		server.WithListUsersMaxResults(config.ListUsersMaxResults),
-		server.WithMaxConcurrentReadsForListObjects(config.MaxConcurrentReadsForListObjects),
-		server.WithMaxConcurrentReadsForCheck(config.MaxConcurrentReadsForCheck),
-		server.WithMaxConcurrentReadsForListUsers(config.MaxConcurrentReadsForListUsers),
-		server.WithCacheControllerEnabled(config.CacheController.Enabled),
-		server.WithCacheControllerTTL(config.CacheController.TTL),
-		server.WithCheckCacheLimit(config.CheckCache.Limit),
-		server.WithCheckIteratorCacheEnabled(config.CheckIteratorCache.Enabled),
-		server.WithCheckIteratorCacheMaxResults(config.CheckIteratorCache.MaxResults),
-		server.WithCheckIteratorCacheTTL(config.CheckIteratorCache.TTL),
-		server.WithCheckQueryCacheEnabled(config.CheckQueryCache.Enabled),
-		server.WithCheckQueryCacheTTL(config.CheckQueryCache.TTL),
-		server.WithRequestDurationByQueryHistogramBuckets(convertStringArrayToUintArray(config.RequestDurationDatastoreQueryCountBuckets)),
-		server.WithRequestDurationByDispatchCountHistogramBuckets(convertStringArrayToUintArray(config.RequestDurationDispatchCountBuckets)),
-		server.WithMaxAuthorizationModelSizeInBytes(config.MaxAuthorizationModelSizeInBytes),
-		server.WithContextPropagationToDatastore(config.ContextPropagationToDatastore),
-		server.WithDispatchThrottlingCheckResolverEnabled(config.CheckDispatchThrottling.Enabled),
-		server.WithDispatchThrottlingCheckResolverFrequency(config.CheckDispatchThrottling.Frequency),
-		server.WithDispatchThrottlingCheckResolverThreshold(config.CheckDispatchThrottling.Threshold),
-		server.WithDispatchThrottlingCheckResolverMaxThreshold(config.CheckDispatchThrottling.MaxThreshold),
-		server.WithListObjectsDispatchThrottlingEnabled(config.ListObjectsDispatchThrottling.Enabled),
-		server.WithListObjectsDispatchThrottlingFrequency(config.ListObjectsDispatchThrottling.Frequency),
-		server.WithListObjectsDispatchThrottlingThreshold(config.ListObjectsDispatchThrottling.Threshold),
-		server.WithListObjectsDispatchThrottlingMaxThreshold(config.ListObjectsDispatchThrottling.MaxThreshold),
-		server.WithListUsersDispatchThrottlingEnabled(config.ListUsersDispatchThrottling.Enabled),
-		server.WithListUsersDispatchThrottlingFrequency(config.ListUsersDispatchThrottling.Frequency),
-		server.WithListUsersDispatchThrottlingThreshold(config.ListUsersDispatchThrottling.Threshold),
-		server.WithListUsersDispatchThrottlingMaxThreshold(config.ListUsersDispatchThrottling.MaxThreshold),
-		server.WithCheckDatabaseThrottle(config.CheckDatabaseThrottle.Threshold, config.CheckDatabaseThrottle.Duration),
-		server.WithListObjectsDatabaseThrottle(config.ListObjectsDatabaseThrottle.Threshold, config.ListObjectsDatabaseThrottle.Duration),
-		server.WithListUsersDatabaseThrottle(config.ListUsersDatabaseThrottle.Threshold, config.ListUsersDatabaseThrottle.Duration),
-		server.WithListObjectsIteratorCacheEnabled(config.ListObjectsIteratorCache.Enabled),
-		server.WithListObjectsIteratorCacheMaxResults(config.ListObjectsIteratorCache.MaxResults),
-		server.WithListObjectsIteratorCacheTTL(config.ListObjectsIteratorCache.TTL),
-		server.WithMaxChecksPerBatchCheck(config.MaxChecksPerBatchCheck),
-		server.WithMaxConcurrentChecksPerBatchCheck(config.MaxConcurrentChecksPerBatchCheck),
-		server.WithSharedIteratorEnabled(config.SharedIterator.Enabled),
-		server.WithSharedIteratorLimit(config.SharedIterator.Limit),
-		// The shared iterator watchdog timeout is set to config.RequestTimeout + 2 seconds
-		// to provide a small buffer for operations that might slightly exceed the request timeout.
-		server.WithSharedIteratorTTL(config.RequestTimeout+2*time.Second),
-		server.WithExperimentals(experimentals...),
-		server.WithAccessControlParams(config.AccessControl.Enabled, config.AccessControl.StoreID, config.AccessControl.ModelID, config.Authn.Method),
-		server.WithContext(ctx),
-	)
+	var metricsExporter *telemetry.MetricsExporter
+	if config.Metrics.OTLP.Enabled {
+		// the headers were validated with the config
+		headers, _ := serverconfig.ParseOTLPHeaders(config.Metrics.OTLP.Headers)
+		options := []telemetry.MetricsExporterOption{
+			telemetry.WithMetricsOTLPEndpoint(config.Metrics.OTLP.Endpoint),
+			telemetry.WithMetricsOTLPProtocol(config.Metrics.OTLP.Protocol),
+			telemetry.WithMetricsOTLPHeaders(headers),
+			telemetry.WithMetricsExportInterval(config.Metrics.OTLP.Interval),
+			telemetry.WithMetricsAttributes(
+				semconv.ServiceNameKey.String(config.Trace.ServiceName),
+				semconv.ServiceVersionKey.String(build.Version),
+			),
+			telemetry.WithMetricsLogger(s.Logger),
+		}
+		if !config.Metrics.OTLP.TLS.Enabled {
+			options = append(options, telemetry.WithMetricsOTLPInsecure())
+		}
+
+		metricsExporter, err = telemetry.NewMetricsExporter(options...)
+		if err != nil {
+			return err
+		}
+		s.Logger.Info(fmt.Sprintf("📈 exporting metrics to '%s' with otlp/%s every %s, tls: %t", config.Metrics.OTLP.Endpoint, config.Metrics.OTLP.Protocol, config.Metrics.OTLP.Interval, config.Metrics.OTLP.TLS.Enabled))
+	}
 
 	s.Logger.Info(
 		"starting openfga service...",
@@ -715,7 +1412,25 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 	// nosemgrep: grpc-server-insecure-connection
 	grpcServer := grpc.NewServer(serverOpts...)
 	openfgav1.RegisterOpenFGAServiceServer(grpcServer, svr)
-	healthServer := &health.Checker{TargetService: svr, TargetServiceName: openfgav1.OpenFGAService_ServiceDesc.ServiceName}
+	server.RegisterStreamedReadServiceServer(grpcServer, svr)
+	// the services are NOT_SERVING until all the listeners are started, and again once the server drains
+	var started, adminServing atomic.Bool
+	healthServer := &health.Checker{
+		TargetService: health.TargetServiceFunc(func(ctx context.Context) (bool, error) {
+			if !started.Load() {
+				return false, nil
+			}
+			return svr.IsReady(ctx)
+		}),
+		TargetServiceName: openfgav1.OpenFGAService_ServiceDesc.ServiceName,
+	}
+	if config.Admin.Enabled {
+		healthServer.Services = map[string]health.TargetService{
+			admin.ServiceName: health.TargetServiceFunc(func(context.Context) (bool, error) {
+				return adminServing.Load(), nil
+			}),
+		}
+	}
 	healthv1pb.RegisterHealthServer(grpcServer, healthServer)
 	reflection.Register(grpcServer)
 
@@ -741,16 +1456,13 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		dialOpts := []grpc.DialOption{
 			// nolint:staticcheck // ignoring gRPC deprecations
 			grpc.WithBlock(),
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(config.GRPC.MaxSendMsgSizeBytes)),
 		}
 		if config.Trace.Enabled {
 			dialOpts = append(dialOpts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 		}
 		if config.GRPC.TLS.Enabled {
-			creds, err := credentials.NewClientTLSFromFile(config.GRPC.TLS.CertPath, "")
-			if err != nil {
-				s.Logger.Fatal("", zap.Error(err))
-			}
-			dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+			dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(gatewayTLSConfig(config.GRPC.TLS.CertPath))))
 		} else {
 			dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		}
@@ -768,22 +1480,55 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		muxOpts := []runtime.ServeMuxOption{
 			runtime.WithForwardResponseOption(httpmiddleware.HTTPResponseModifier),
 			runtime.WithErrorHandler(func(c context.Context, sr *runtime.ServeMux, mm runtime.Marshaler, w http.ResponseWriter, r *http.Request, e error) {
-				intCode := serverErrors.ConvertToEncodedErrorCode(status.Convert(e))
-				httpmiddleware.CustomHTTPErrorHandler(c, w, r, serverErrors.NewEncodedError(intCode, e.Error()))
+				httpmiddleware.CustomHTTPErrorHandler(c, w, r, serverErrors.EncodeError(e))
 			}),
 			runtime.WithStreamErrorHandler(func(ctx context.Context, e error) *status.Status {
-				intCode := serverErrors.ConvertToEncodedErrorCode(status.Convert(e))
-				encodedErr := serverErrors.NewEncodedError(intCode, e.Error())
-				return status.Convert(encodedErr)
+				return status.Convert(serverErrors.EncodeError(e))
 			}),
 			runtime.WithHealthzEndpoint(healthv1pb.NewHealthClient(conn)),
+			runtime.WithIncomingHeaderMatcher(httpmiddleware.IncomingHeaderMatcher),
 			runtime.WithOutgoingHeaderMatcher(func(s string) (string, bool) { return s, true }),
 		}
 		mux := runtime.NewServeMux(muxOpts...)
 		if err := openfgav1.RegisterOpenFGAServiceHandler(ctx, mux, conn); err != nil {
 			return err
 		}
-		handler := http.Handler(mux)
+		if err := server.RegisterStreamedReadServiceHandler(ctx, mux, conn); err != nil {
+			return err
+		}
+		// the readiness of each component is served next to the '/healthz' endpoint of the gateway
+		healthMux := http.NewServeMux()
+		healthMux.Handle("GET /healthz/components", health.NewComponentsHandler(svr))
+		healthMux.Handle("/", mux)
+		handler := http.Handler(healthMux)
+
+		allowedHeaders := config.HTTP.CORSAllowedHeaders
+		if config.HTTP.ConnectEnabled {
+			connectHandler, err := connect.NewHandler(conn, openfgav1.OpenFGAService_ServiceDesc.ServiceName,
+				connect.WithDefaultTimeout(serverconfig.DefaultContextTimeout(config)),
+				connect.WithMaxMessageBytes(config.GRPC.MaxRecvMsgSizeBytes),
+				// the methods reading from the stores can be called with GET requests, e.g. to be cached
+				connect.WithGetMethods("Read", "Check", "BatchCheck", "Expand", "ReadAuthorizationModels",
+					"ReadAuthorizationModel", "ReadAssertions", "ReadChanges", "GetStore", "ListStores",
+					"ListObjects", "ListUsers"),
+			)
+			if err != nil {
+				return err
+			}
+			handler = connectHandler.Mount(handler)
+			allowedHeaders = append(slices.Clone(allowedHeaders), connect.AllowedHeaders...)
+			s.Logger.Info("Connect protocol is enabled on the HTTP server")
+		}
+
+		var exposedHeaders []string
+		if config.HTTP.GRPCWebEnabled {
+			handler = grpcweb.NewHandler(conn, openfgav1.OpenFGAService_ServiceDesc.ServiceName,
+				grpcweb.WithDefaultTimeout(serverconfig.DefaultContextTimeout(config)),
+				grpcweb.WithMaxMessageBytes(config.GRPC.MaxRecvMsgSizeBytes)).Mount(handler)
+			exposedHeaders = grpcweb.ExposedHeaders
+			allowedHeaders = append(slices.Clone(allowedHeaders), grpcweb.AllowedHeaders...)
+			s.Logger.Info("gRPC-Web protocol is enabled on the HTTP server")
+		}
 
 		if config.Trace.Enabled {
 			handler = otelhttp.NewHandler(handler, "grpc-gateway")
@@ -794,9 +1539,10 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 			Handler: recovery.HTTPPanicRecoveryHandler(cors.New(cors.Options{
 				AllowedOrigins:   config.HTTP.CORSAllowedOrigins,
 				AllowCredentials: true,
-				AllowedHeaders:   config.HTTP.CORSAllowedHeaders,
+				AllowedHeaders:   allowedHeaders,
 				AllowedMethods: []string{http.MethodGet, http.MethodPost,
 					http.MethodHead, http.MethodPatch, http.MethodDelete, http.MethodPut},
+				ExposedHeaders: exposedHeaders,
 			}).Handler(handler), s.Logger),
 		}
 
@@ -911,10 +1657,85 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		}()
 	}
 
+	// effectiveConfig is the config, with the tunable settings reloaded
+	var effectiveConfig atomic.Pointer[serverconfig.Config]
+	effectiveConfig.Store(config)
+
+	var adminServer *http.Server
+	if config.Admin.Enabled {
+		adminServer = &http.Server{
+			Addr: config.Admin.Addr,
+			Handler: admin.NewHandler(svr, func() any { return effectiveConfig.Load() },
+				config.Admin.Keys, s.Logger),
+		}
+
+		adminListener, err := net.Listen("tcp", config.Admin.Addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on the admin server address: %w", err)
+		}
+
+		// the admin server shares the TLS settings of the HTTP server
+		if config.HTTP.TLS.Enabled {
+			if config.HTTP.TLS.CertPath == "" || config.HTTP.TLS.KeyPath == "" {
+				_ = adminListener.Close()
+				return errors.New("'http.tls.cert' and 'http.tls.key' configs must be set")
+			}
+			adminGetCertificate, err := watchAndLoadCertificateWithCertWatcher(ctx, config.HTTP.TLS.CertPath, config.HTTP.TLS.KeyPath, s.Logger)
+			if err != nil {
+				_ = adminListener.Close()
+				return err
+			}
+			adminListener = tls.NewListener(adminListener, &tls.Config{
+				GetCertificate: adminGetCertificate,
+			})
+		}
+		adminServing.Store(true)
+
+		go func() {
+			s.Logger.Info(fmt.Sprintf("🔧 starting admin server on '%s'", config.Admin.Addr))
+			if err := adminServer.Serve(adminListener); err != nil {
+				if err != http.ErrServerClosed {
+					s.Logger.Fatal("failed to start admin server", zap.Error(err))
+				}
+			}
+			s.Logger.Info("admin server shut down.")
+		}()
+	}
+
+	// reload the tunable settings on SIGHUP
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangups:
+				next, err := reloadConfig(effectiveConfig.Load(), svr)
+				if err != nil {
+					s.Logger.Error("failed to reload the config, keeping the current one", zap.Error(err))
+					continue
+				}
+				effectiveConfig.Store(next)
+				s.Logger.Info("config reloaded")
+			}
+		}
+	}()
+
+	started.Store(true)
+
 	// wait for cancellation signal
 	<-ctx.Done()
 	s.Logger.Info("attempting to shutdown gracefully...")
 
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), config.ShutdownDrainDelay+config.ShutdownDrainTimeout)
+	drainErr := svr.Drain(drainCtx)
+	cancelDrain()
+	if drainErr != nil {
+		s.Logger.Warn("requests still in flight after the drain timeout", zap.Duration("drain_timeout", config.ShutdownDrainTimeout))
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -936,13 +1757,31 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		}
 	}
 
+	if adminServer != nil {
+		adminServing.Store(false)
+		if err := adminServer.Shutdown(ctx); err != nil {
+			s.Logger.Info("failed to shutdown the admin server", zap.Error(err))
+		}
+	}
+
 	if metricsServer != nil {
 		if err := metricsServer.Shutdown(ctx); err != nil {
 			s.Logger.Info("failed to shutdown the prometheus metrics server", zap.Error(err))
 		}
 	}
 
-	grpcServer.GracefulStop()
+	if metricsExporter != nil {
+		if err := metricsExporter.Close(ctx); err != nil {
+			s.Logger.Info("failed to export the metrics on shutdown", zap.Error(err))
+		}
+	}
+
+	if drainErr != nil {
+		// the requests in flight would keep the datastore from closing
+		grpcServer.Stop()
+	} else {
+		grpcServer.GracefulStop()
+	}
 
 	svr.Close()
 
@@ -979,6 +1818,25 @@ func watchAndLoadCertificateWithCertWatcher(ctx context.Context, certPath, keyPa
 		}
 	}()
 
+	// Reload the certificate on SIGHUP too, for the file systems the watcher misses the changes of
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hangups)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangups:
+				if err := watcher.ReadCertificate(); err != nil {
+					logger.Error("failed to reload the TLS certificate", zap.String("certPath", certPath), zap.Error(err))
+					continue
+				}
+				logger.Info("TLS certificate reloaded on SIGHUP.", zap.String("certPath", certPath), zap.String("keyPath", keyPath))
+			}
+		}
+	}()
+
 	// Return a function that retrieves the updated certificate
 	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
 		return watcher.GetCertificate(nil)
@@ -986,3 +1844,133 @@ func watchAndLoadCertificateWithCertWatcher(ctx context.Context, certPath, keyPa
 
 	return getCertificate, nil
 }
+
+// tunableSettings returns the settings of config the server can change while it runs.
+func tunableSettings(config *serverconfig.Config) server.TunableSettings {
+	return server.TunableSettings{
+		ListObjectsDeadline:              config.ListObjectsDeadline,
+		ListObjectsMaxResults:            config.ListObjectsMaxResults,
+		ListUsersDeadline:                config.ListUsersDeadline,
+		ListUsersMaxResults:              config.ListUsersMaxResults,
+		MaxChecksPerBatchCheck:           config.MaxChecksPerBatchCheck,
+		MaxConcurrentReadsForCheck:       config.MaxConcurrentReadsForCheck,
+		MaxConcurrentReadsForListObjects: config.MaxConcurrentReadsForListObjects,
+		MaxConcurrentReadsForListUsers:   config.MaxConcurrentReadsForListUsers,
+		CheckQueryCacheTTL:               config.CheckQueryCache.TTL,
+		CheckIteratorCacheTTL:            config.CheckIteratorCache.TTL,
+		CheckTupleCacheTTL:               config.CheckTupleCache.TTL,
+		ListObjectsIteratorCacheTTL:      config.ListObjectsIteratorCache.TTL,
+	}
+}
+
+// restartRequiredChanges returns the paths of the settings changed from current to next which the server cannot
+// change while it runs, e.g. 'Datastore.Engine'.
+func restartRequiredChanges(current, next *serverconfig.Config) []string {
+	// the tunable settings are ignored
+	untuned := *next
+	untuned.ListObjectsDeadline = current.ListObjectsDeadline
+	untuned.ListObjectsMaxResults = current.ListObjectsMaxResults
+	untuned.ListUsersDeadline = current.ListUsersDeadline
+	untuned.ListUsersMaxResults = current.ListUsersMaxResults
+	untuned.MaxChecksPerBatchCheck = current.MaxChecksPerBatchCheck
+	untuned.MaxConcurrentReadsForCheck = current.MaxConcurrentReadsForCheck
+	untuned.MaxConcurrentReadsForListObjects = current.MaxConcurrentReadsForListObjects
+	untuned.MaxConcurrentReadsForListUsers = current.MaxConcurrentReadsForListUsers
+	untuned.CheckQueryCache.TTL = current.CheckQueryCache.TTL
+	untuned.CheckIteratorCache.TTL = current.CheckIteratorCache.TTL
+	untuned.CheckTupleCache.TTL = current.CheckTupleCache.TTL
+	untuned.ListObjectsIteratorCache.TTL = current.ListObjectsIteratorCache.TTL
+
+	return changedSettings("", reflect.ValueOf(*current), reflect.ValueOf(untuned))
+}
+
+// changedSettings returns the paths of the settings that differ between before and after, comparing the fields of
+// the structs one by one. An empty slice or map is the same setting as a nil one.
+func changedSettings(path string, before, after reflect.Value) []string {
+	switch before.Kind() {
+	case reflect.Struct:
+		var changed []string
+		for i := 0; i < before.NumField(); i++ {
+			field := before.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			changed = append(changed, changedSettings(fieldPath, before.Field(i), after.Field(i))...)
+		}
+		return changed
+	case reflect.Pointer:
+		if before.IsNil() || after.IsNil() {
+			if before.IsNil() != after.IsNil() {
+				return []string{path}
+			}
+			return nil
+		}
+		return changedSettings(path, before.Elem(), after.Elem())
+	case reflect.Slice, reflect.Map:
+		if before.Len() == 0 && after.Len() == 0 {
+			return nil
+		}
+	}
+
+	if !reflect.DeepEqual(before.Interface(), after.Interface()) {
+		return []string{path}
+	}
+	return nil
+}
+
+// reloadConfig reads the config again and applies its tunable settings to svr. The config is rejected if any other
+// setting changed, as it would require a restart.
+func reloadConfig(current *serverconfig.Config, svr *server.Server) (*serverconfig.Config, error) {
+	next, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := next.Verify(); err != nil {
+		return nil, err
+	}
+	if changed := restartRequiredChanges(current, next); len(changed) > 0 {
+		return nil, fmt.Errorf("the changes of %s require a restart", strings.Join(changed, ", "))
+	}
+	if err := svr.UpdateTunableSettings(tunableSettings(next)); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// gatewayTLSConfig returns the TLS config of the connection of the HTTP gateway to the gRPC server, which trusts the
+// certificate in certPath. The file is read at each handshake rather than once, so that the gateway can reconnect
+// once the certificate is rotated.
+func gatewayTLSConfig(certPath string) *tls.Config {
+	return &tls.Config{
+		// the certificate is verified by VerifyConnection instead
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("the server did not present a certificate")
+			}
+			certPEM, err := os.ReadFile(certPath)
+			if err != nil {
+				return fmt.Errorf("failed to read the TLS certificate: %w", err)
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(certPEM) {
+				return fmt.Errorf("no certificate found in %q", certPath)
+			}
+
+			opts := x509.VerifyOptions{
+				Roots:         roots,
+				DNSName:       state.ServerName,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range state.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err = state.PeerCertificates[0].Verify(opts)
+			return err
+		},
+	}
+}
@@ -48,8 +48,10 @@ import (
 	"github.com/openfga/openfga/pkg/server"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage/memory"
 	"github.com/openfga/openfga/pkg/storage/sqlcommon"
 	"github.com/openfga/openfga/pkg/storage/sqlite"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
 	storagefixtures "github.com/openfga/openfga/pkg/testfixtures/storage"
 	"github.com/openfga/openfga/pkg/testutils"
 	"github.com/openfga/openfga/pkg/tuple"
@@ -254,6 +256,7 @@ func TestBuildServiceWithPresharedKeyAuthentication(t *testing.T) {
 		expectedErrorResponse: &serverErrors.ErrorResponse{
 			Code:    "unauthenticated",
 			Message: "unauthenticated",
+			Reason:  "UNAUTHENTICATED",
 		},
 		expectedStatusCode: 401,
 	}, {
@@ -262,6 +265,7 @@ func TestBuildServiceWithPresharedKeyAuthentication(t *testing.T) {
 		expectedErrorResponse: &serverErrors.ErrorResponse{
 			Code:    "bearer_token_missing",
 			Message: "missing bearer token",
+			Reason:  "UNAUTHENTICATED",
 		},
 		expectedStatusCode: 401,
 	}, {
@@ -575,6 +579,7 @@ func TestBuildServerWithOIDCAuthentication(t *testing.T) {
 			expectedErrorResponse: &serverErrors.ErrorResponse{
 				Code:    "invalid_claims",
 				Message: "invalid claims",
+				Reason:  "UNAUTHENTICATED",
 			},
 			expectedStatusCode: 401,
 		},
@@ -584,6 +589,7 @@ func TestBuildServerWithOIDCAuthentication(t *testing.T) {
 			expectedErrorResponse: &serverErrors.ErrorResponse{
 				Code:    "bearer_token_missing",
 				Message: "missing bearer token",
+				Reason:  "UNAUTHENTICATED",
 			},
 			expectedStatusCode: 401,
 		},
@@ -730,6 +736,77 @@ func TestHTTPServingTLS(t *testing.T) {
 	})
 }
 
+func TestAdminServer(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	certsAndKeys := createCertsAndKeys(t)
+	defer certsAndKeys.Clean()
+
+	adminPort, adminPortReleaser := testutils.TCPRandomPort()
+	adminPortReleaser()
+
+	cfg := testutils.MustDefaultConfigWithRandomPorts()
+	cfg.HTTP.TLS = &serverconfig.TLSConfig{
+		Enabled:  true,
+		CertPath: certsAndKeys.serverCertFile,
+		KeyPath:  certsAndKeys.serverKeyFile,
+	}
+	cfg.CheckQueryCache.Enabled = true
+	cfg.Admin.Enabled = true
+	cfg.Admin.Addr = fmt.Sprintf("localhost:%d", adminPort)
+	cfg.Admin.Keys = []string{"admin-key"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := runServer(ctx, cfg); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(certsAndKeys.caCert)
+	client := retryablehttp.NewClient()
+	t.Cleanup(client.HTTPClient.CloseIdleConnections)
+	client.HTTPClient.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{
+			RootCAs: certPool,
+		},
+	}
+
+	do := func(method, path, key string) *http.Response {
+		req, err := retryablehttp.NewRequest(method, fmt.Sprintf("https://%s%s", cfg.Admin.Addr, path), nil)
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/admin/v1/check-cache/flush", "").StatusCode)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/admin/v1/check-cache/flush", "admin-key").StatusCode)
+
+	resp := do(http.MethodGet, "/admin/v1/config", "admin-key")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NotContains(t, string(body), "admin-key")
+
+	// the admin server is not served in plaintext
+	plaintextClient := &http.Client{Transport: &http.Transport{}}
+	t.Cleanup(plaintextClient.CloseIdleConnections)
+	plaintextResp, err := plaintextClient.Get(fmt.Sprintf("http://%s/admin/v1/config", cfg.Admin.Addr))
+	require.NoError(t, err)
+	defer plaintextResp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, plaintextResp.StatusCode)
+}
+
 func TestGRPCServingTLS(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -788,6 +865,115 @@ func TestGRPCServingTLS(t *testing.T) {
 	})
 }
 
+func TestRestartRequiredChanges(t *testing.T) {
+	current := serverconfig.DefaultConfig()
+
+	next := serverconfig.DefaultConfig()
+	next.ListObjectsDeadline = 10 * time.Second
+	next.MaxConcurrentReadsForCheck = 50
+	require.Empty(t, restartRequiredChanges(current, next))
+	require.Equal(t, 10*time.Second, tunableSettings(next).ListObjectsDeadline)
+	require.EqualValues(t, 50, tunableSettings(next).MaxConcurrentReadsForCheck)
+
+	next.CheckQueryCache.TTL = time.Minute
+	require.Empty(t, restartRequiredChanges(current, next))
+	require.Equal(t, time.Minute, tunableSettings(next).CheckQueryCacheTTL)
+
+	// an empty list is the same setting as no list
+	next.HTTP.CORSAllowedOrigins = nil
+	current.HTTP.CORSAllowedOrigins = []string{}
+	require.Empty(t, restartRequiredChanges(current, next))
+
+	next.Datastore.Engine = "postgres"
+	next.GRPC.Addr = "0.0.0.0:9091"
+	next.GRPC.TLS.Enabled = true
+	require.Equal(t, []string{"Datastore.Engine", "GRPC.Addr", "GRPC.TLS.Enabled"}, restartRequiredChanges(current, next))
+}
+
+func TestReloadConfig(t *testing.T) {
+	util.PrepareTempConfigFile(t, `listObjectsDeadline: 3s
+checkQueryCache:
+    enabled: true
+    TTL: 10s
+`)
+	configFile := filepath.Join(os.Getenv("HOME"), ".openfga", "config.yaml")
+	// viper keeps reading the config file it found, which is removed with the test
+	t.Cleanup(viper.Reset)
+
+	// the flags are bound to the config as by the run command
+	runCmd := NewRunCommand()
+	runCmd.RunE = func(cmd *cobra.Command, _ []string) error {
+		return nil
+	}
+	rootCmd := cmd.NewRootCommand()
+	rootCmd.AddCommand(runCmd)
+	rootCmd.SetArgs([]string{"run"})
+	require.NoError(t, rootCmd.Execute())
+
+	current, err := ReadConfig()
+	require.NoError(t, err)
+	require.NoError(t, current.Verify())
+
+	svr := server.MustNewServerWithOpts(
+		server.WithDatastore(memory.New()),
+		server.WithListObjectsDeadline(current.ListObjectsDeadline),
+		server.WithCheckQueryCacheEnabled(current.CheckQueryCache.Enabled),
+		server.WithCheckQueryCacheTTL(current.CheckQueryCache.TTL),
+	)
+	t.Cleanup(svr.Close)
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`listObjectsDeadline: 5s
+checkQueryCache:
+    enabled: true
+    TTL: 1m
+`), 0o600))
+	next, err := reloadConfig(current, svr)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, next.ListObjectsDeadline)
+	require.Equal(t, 5*time.Second, svr.TunableSettings().ListObjectsDeadline)
+	require.Equal(t, time.Minute, svr.TunableSettings().CheckQueryCacheTTL)
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`listObjectsDeadline: 4s
+checkQueryCache:
+    enabled: false
+    TTL: 1m
+`), 0o600))
+	_, err = reloadConfig(next, svr)
+	require.EqualError(t, err, "the changes of CheckQueryCache.Enabled require a restart")
+	require.Equal(t, 5*time.Second, svr.TunableSettings().ListObjectsDeadline)
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`listObjectsDeadline: -1s
+`), 0o600))
+	_, err = reloadConfig(next, svr)
+	require.Error(t, err)
+	require.Equal(t, 5*time.Second, svr.TunableSettings().ListObjectsDeadline)
+}
+
+func TestGatewayTLSConfigReloadsCertificate(t *testing.T) {
+	certsAndKeys := createCertsAndKeys(t)
+	defer certsAndKeys.Clean()
+
+	caCert, _, caKey := genCACert(t)
+	rotatedCert, rotatedPEM, _ := genServerCert(t, caCert, caKey)
+	initialPEM, err := os.ReadFile(certsAndKeys.serverCertFile)
+	require.NoError(t, err)
+	initialBlock, _ := pem.Decode(initialPEM)
+	initialCert, err := x509.ParseCertificate(initialBlock.Bytes)
+	require.NoError(t, err)
+
+	verify := gatewayTLSConfig(certsAndKeys.serverCertFile).VerifyConnection
+	state := func(cert *x509.Certificate) tls.ConnectionState {
+		return tls.ConnectionState{ServerName: "localhost", PeerCertificates: []*x509.Certificate{cert}}
+	}
+
+	require.NoError(t, verify(state(initialCert)))
+	require.Error(t, verify(state(rotatedCert)))
+
+	require.NoError(t, os.WriteFile(certsAndKeys.serverCertFile, rotatedPEM, 0o600))
+	require.NoError(t, verify(state(rotatedCert)))
+	require.Error(t, verify(state(initialCert)))
+}
+
 func TestServerMetricsReporting(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -1031,6 +1217,54 @@ func TestDefaultConfig(t *testing.T) {
 	val = res.Get("properties.datastore.properties.connMaxLifetime.default")
 	require.True(t, val.Exists())
 
+	val = res.Get("properties.datastore.properties.credentialsRefreshInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.CredentialsRefreshInterval.String())
+
+	val = res.Get("properties.datastore.properties.iamAuth.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Datastore.IAMAuth.Enabled)
+
+	val = res.Get("properties.datastore.properties.iamAuth.properties.region.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.IAMAuth.Region)
+
+	val = res.Get("properties.datastore.properties.readTimeout.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.ReadTimeout.String())
+
+	val = res.Get("properties.datastore.properties.writeTimeout.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.WriteTimeout.String())
+
+	val = res.Get("properties.datastore.properties.slowQueryThreshold.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.SlowQueryThreshold.String())
+
+	val = res.Get("properties.datastore.properties.snapshotPath.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.SnapshotPath)
+
+	val = res.Get("properties.datastore.properties.snapshotInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.SnapshotInterval.String())
+
+	val = res.Get("properties.datastore.properties.memoryMaxTuplesPerStore.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Datastore.MemoryMaxTuplesPerStore)
+
+	val = res.Get("properties.datastore.properties.memoryMaxBytes.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Datastore.MemoryMaxBytes)
+
+	val = res.Get("properties.datastore.properties.maxConcurrentReads.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Datastore.MaxConcurrentReads)
+
+	val = res.Get("properties.datastore.properties.hedgeDelay.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.HedgeDelay.String())
+
 	val = res.Get("properties.datastore.properties.metrics.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.False(t, val.Bool())
@@ -1039,6 +1273,42 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.GRPC.Addr)
 
+	val = res.Get("properties.grpc.properties.maxRecvMsgSizeBytes.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.GRPC.MaxRecvMsgSizeBytes)
+
+	val = res.Get("properties.grpc.properties.maxSendMsgSizeBytes.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.GRPC.MaxSendMsgSizeBytes)
+
+	val = res.Get("properties.grpc.properties.maxConcurrentStreams.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.GRPC.MaxConcurrentStreams)
+
+	val = res.Get("properties.grpc.properties.keepalive.properties.time.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.GRPC.Keepalive.Time.String())
+
+	val = res.Get("properties.grpc.properties.keepalive.properties.timeout.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.GRPC.Keepalive.Timeout.String())
+
+	val = res.Get("properties.grpc.properties.keepalive.properties.maxConnectionAge.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.GRPC.Keepalive.MaxConnectionAge.String())
+
+	val = res.Get("properties.grpc.properties.keepalive.properties.maxConnectionAgeGrace.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.GRPC.Keepalive.MaxConnectionAgeGrace.String())
+
+	val = res.Get("properties.grpc.properties.keepalive.properties.minTime.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.GRPC.Keepalive.MinTime.String())
+
+	val = res.Get("properties.grpc.properties.keepalive.properties.permitWithoutStream.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.GRPC.Keepalive.PermitWithoutStream)
+
 	val = res.Get("properties.http.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.HTTP.Enabled)
@@ -1047,6 +1317,14 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.HTTP.Addr)
 
+	val = res.Get("properties.http.properties.connectEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.HTTP.ConnectEnabled)
+
+	val = res.Get("properties.http.properties.grpcWebEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.HTTP.GRPCWebEnabled)
+
 	val = res.Get("properties.playground.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.Playground.Enabled)
@@ -1071,10 +1349,62 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.Log.Format)
 
+	val = res.Get("properties.log.properties.sampling.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Log.Sampling.Enabled)
+
+	val = res.Get("properties.log.properties.sampling.properties.perSecond.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Log.Sampling.PerSecond)
+
+	val = res.Get("properties.log.properties.sampling.properties.thereafter.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Log.Sampling.Thereafter)
+
+	val = res.Get("properties.log.properties.outputPaths.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.Log.OutputPaths, len(val.Array()))
+
+	val = res.Get("properties.log.properties.rotation.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Log.Rotation.Enabled)
+
+	val = res.Get("properties.log.properties.rotation.properties.maxSize.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Log.Rotation.MaxSize)
+
+	val = res.Get("properties.log.properties.rotation.properties.maxAge.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Log.Rotation.MaxAge.String())
+
+	val = res.Get("properties.log.properties.rotation.properties.maxBackups.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Log.Rotation.MaxBackups)
+
+	val = res.Get("properties.log.properties.rotation.properties.compress.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Log.Rotation.Compress)
+
+	val = res.Get("properties.log.properties.security.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Log.Security.Enabled)
+
+	val = res.Get("properties.log.properties.security.properties.format.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Log.Security.Format)
+
+	val = res.Get("properties.log.properties.security.properties.outputPaths.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.Log.Security.OutputPaths, len(val.Array()))
+
 	val = res.Get("properties.maxTuplesPerWrite.default")
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.MaxTuplesPerWrite)
 
+	val = res.Get("properties.maxPageSize.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.MaxPageSize)
+
 	val = res.Get("properties.maxTypesPerAuthorizationModel.default")
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.MaxTypesPerAuthorizationModel)
@@ -1099,6 +1429,18 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.Uint(), cfg.MaxConditionEvaluationCost)
 
+	val = res.Get("properties.maxContextSizeBytes.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Uint(), cfg.MaxContextSizeBytes)
+
+	val = res.Get("properties.maxContextDepth.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Uint(), cfg.MaxContextDepth)
+
+	val = res.Get("properties.maxContextualTuples.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Uint(), cfg.MaxContextualTuples)
+
 	val = res.Get("properties.maxConcurrentReadsForListUsers.default")
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.MaxConcurrentReadsForListUsers)
@@ -1107,10 +1449,58 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.ChangelogHorizonOffset)
 
+	val = res.Get("properties.continuationTokenTTL.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.ContinuationTokenTTL.String())
+
+	val = res.Get("properties.continuationTokenUnversionedWindow.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.ContinuationTokenUnversionedWindow.String())
+
 	val = res.Get("properties.resolveNodeBreadthLimit.default")
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.ResolveNodeBreadthLimit)
 
+	val = res.Get("properties.checkDirectTupleBatchSize.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.CheckDirectTupleBatchSize)
+
+	val = res.Get("properties.checkDirectTupleBatchMaxDelay.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckDirectTupleBatchMaxDelay.String())
+
+	val = res.Get("properties.checkDispatchPoolSize.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.CheckDispatchPoolSize)
+
+	val = res.Get("properties.checkMembershipIndex.properties.relations.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.CheckMembershipIndex.Relations, len(val.Array()))
+
+	val = res.Get("properties.checkMembershipIndex.properties.refreshInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckMembershipIndex.RefreshInterval.String())
+
+	val = res.Get("properties.checkMembershipIndex.properties.maxStaleness.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckMembershipIndex.MaxStaleness.String())
+
+	val = res.Get("properties.checkMaterializedViews.properties.views.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.CheckMaterializedViews.Views, len(val.Array()))
+
+	val = res.Get("properties.checkMaterializedViews.properties.refreshInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckMaterializedViews.RefreshInterval.String())
+
+	val = res.Get("properties.checkMaterializedViews.properties.maxStaleness.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckMaterializedViews.MaxStaleness.String())
+
+	val = res.Get("properties.checkUnionBranchOrderingEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.CheckUnionBranchOrderingEnabled)
+
 	val = res.Get("properties.resolveNodeLimit.default")
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.ResolveNodeLimit)
@@ -1131,6 +1521,10 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.ListObjectsMaxResults)
 
+	val = res.Get("properties.listObjectsDatastoreQueryBudget.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.ListObjectsDatastoreQueryBudget)
+
 	val = res.Get("properties.listUsersDeadline.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.ListUsersDeadline.String())
@@ -1151,14 +1545,86 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.Metrics.Addr)
 
+	val = res.Get("properties.admin.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Admin.Enabled)
+
+	val = res.Get("properties.admin.properties.addr.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Admin.Addr)
+
 	val = res.Get("properties.metrics.properties.enableRPCHistograms.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.Metrics.EnableRPCHistograms)
 
+	val = res.Get("properties.metrics.properties.otlp.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Metrics.OTLP.Enabled)
+
+	val = res.Get("properties.metrics.properties.otlp.properties.endpoint.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Metrics.OTLP.Endpoint)
+
+	val = res.Get("properties.metrics.properties.otlp.properties.protocol.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Metrics.OTLP.Protocol)
+
+	val = res.Get("properties.metrics.properties.otlp.properties.interval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Metrics.OTLP.Interval.String())
+
+	val = res.Get("properties.metrics.properties.otlp.properties.headers.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.Metrics.OTLP.Headers, len(val.Array()))
+
+	val = res.Get("properties.metrics.properties.otlp.properties.tls.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Metrics.OTLP.TLS.Enabled)
+
+	val = res.Get("properties.metrics.properties.storeIDLabel.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Metrics.StoreIDLabel.Enabled)
+
+	val = res.Get("properties.metrics.properties.storeIDLabel.properties.stores.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.Metrics.StoreIDLabel.Stores, len(val.Array()))
+
+	val = res.Get("properties.metrics.properties.storeIDLabel.properties.limit.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Metrics.StoreIDLabel.Limit)
+
+	val = res.Get("properties.accessLog.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.AccessLog.Enabled)
+
+	val = res.Get("properties.accessLog.properties.fields.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.AccessLog.Fields, len(val.Array()))
+
+	val = res.Get("properties.accessLog.properties.skippedMethods.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.AccessLog.SkippedMethods, len(val.Array()))
+
+	val = res.Get("properties.redaction.properties.mode.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Redaction.Mode)
+
+	val = res.Get("properties.redaction.properties.hashKey.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Redaction.HashKey)
+
 	val = res.Get("properties.trace.properties.serviceName.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.Trace.ServiceName)
 
+	val = res.Get("properties.trace.properties.methodSampleRatios.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.Trace.MethodSampleRatios, len(val.Array()))
+
+	val = res.Get("properties.trace.properties.sampleErrors.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Trace.SampleErrors)
+
 	val = res.Get("properties.trace.properties.otlp.properties.endpoint.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.Trace.OTLP.Endpoint)
@@ -1179,6 +1645,14 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.CheckQueryCache.TTL.String())
 
+	val = res.Get("properties.checkQueryCache.properties.revalidationWindow.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckQueryCache.RevalidationWindow.String())
+
+	val = res.Get("properties.checkQueryCache.properties.revalidationMinHits.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.CheckQueryCache.RevalidationMinHits)
+
 	val = res.Get("properties.checkIteratorCache.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.CheckIteratorCache.Enabled)
@@ -1191,6 +1665,14 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.CheckIteratorCache.TTL.String())
 
+	val = res.Get("properties.checkTupleCache.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.CheckTupleCache.Enabled)
+
+	val = res.Get("properties.checkTupleCache.properties.ttl.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckTupleCache.TTL.String())
+
 	val = res.Get("properties.listObjectsIteratorCache.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.ListObjectsIteratorCache.Enabled)
@@ -1211,6 +1693,10 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.CacheController.TTL.String())
 
+	val = res.Get("properties.cacheController.properties.notificationChannel.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CacheController.NotificationChannel)
+
 	val = res.Get("properties.sharedIterator.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.SharedIterator.Enabled)
@@ -1219,6 +1705,226 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.SharedIterator.Limit)
 
+	val = res.Get("properties.requestIteratorCache.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.RequestIteratorCache.Enabled)
+
+	val = res.Get("properties.requestIteratorCache.properties.maxResults.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.RequestIteratorCache.MaxResults)
+
+	val = res.Get("properties.datastoreLimiterSaturation.properties.threshold.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreLimiterSaturation.Threshold.String())
+
+	val = res.Get("properties.datastoreLimiterSaturation.properties.period.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreLimiterSaturation.Period.String())
+
+	val = res.Get("properties.datastoreLimiterSaturation.properties.ratio.default")
+	require.True(t, val.Exists())
+	require.InDelta(t, val.Float(), cfg.DatastoreLimiterSaturation.Ratio, 0)
+
+	val = res.Get("properties.datastoreLimiterSaturation.properties.readinessEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.DatastoreLimiterSaturation.ReadinessEnabled)
+
+	val = res.Get("properties.datastoreReadPriority.properties.slots.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.DatastoreReadPriority.Slots)
+
+	val = res.Get("properties.datastoreReadPriority.properties.weights.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.DatastoreReadPriority.Weights, len(val.Array()))
+
+	val = res.Get("properties.readiness.properties.checkCacheEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Readiness.CheckCacheEnabled)
+
+	val = res.Get("properties.readiness.properties.tupleChangeListenerEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Readiness.TupleChangeListenerEnabled)
+
+	val = res.Get("properties.datastoreCircuitBreaker.properties.failureRateThreshold.default")
+	require.True(t, val.Exists())
+	require.InDelta(t, val.Float(), cfg.DatastoreCircuitBreaker.FailureRateThreshold, 0)
+
+	val = res.Get("properties.datastoreCircuitBreaker.properties.minRequests.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.DatastoreCircuitBreaker.MinRequests)
+
+	val = res.Get("properties.datastoreCircuitBreaker.properties.window.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreCircuitBreaker.Window.String())
+
+	val = res.Get("properties.datastoreCircuitBreaker.properties.openDuration.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreCircuitBreaker.OpenDuration.String())
+
+	val = res.Get("properties.datastoreRetry.properties.connectionMaxRetries.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.DatastoreRetry.ConnectionMaxRetries)
+
+	val = res.Get("properties.datastoreRetry.properties.serializationMaxRetries.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.DatastoreRetry.SerializationMaxRetries)
+
+	val = res.Get("properties.datastoreRetry.properties.deadlockMaxRetries.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.DatastoreRetry.DeadlockMaxRetries)
+
+	val = res.Get("properties.datastoreRetry.properties.initialInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreRetry.InitialInterval.String())
+
+	val = res.Get("properties.datastoreRetry.properties.maxInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreRetry.MaxInterval.String())
+
+	val = res.Get("properties.datastoreRetry.properties.writes.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.DatastoreRetry.Writes)
+
+	val = res.Get("properties.datastoreFaultInjection.properties.operations.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.DatastoreFaultInjection.Operations, len(val.Array()))
+
+	val = res.Get("properties.datastoreFaultInjection.properties.latency.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreFaultInjection.Latency.String())
+
+	val = res.Get("properties.datastoreFaultInjection.properties.errorRate.default")
+	require.True(t, val.Exists())
+	require.InDelta(t, val.Float(), cfg.DatastoreFaultInjection.ErrorRate, 0)
+
+	val = res.Get("properties.datastoreFaultInjection.properties.iteratorLatency.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DatastoreFaultInjection.IteratorLatency.String())
+
+	val = res.Get("properties.datastoreFaultInjection.properties.policies.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.DatastoreFaultInjection.Policies, len(val.Array()))
+
+	val = res.Get("properties.protectedTuples.properties.patterns.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.ProtectedTuples.Patterns, len(val.Array()))
+
+	val = res.Get("properties.protectedTuples.properties.privilegedPrincipals.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.ProtectedTuples.PrivilegedPrincipals, len(val.Array()))
+
+	val = res.Get("properties.rateLimit.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.RateLimit.Enabled)
+
+	val = res.Get("properties.rateLimit.properties.key.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.RateLimit.Key)
+
+	val = res.Get("properties.rateLimit.properties.rate.default")
+	require.True(t, val.Exists())
+	require.InDelta(t, val.Float(), cfg.RateLimit.Rate, 0)
+
+	val = res.Get("properties.rateLimit.properties.burst.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.RateLimit.Burst)
+
+	val = res.Get("properties.rateLimit.properties.methods.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.RateLimit.Methods, len(val.Array()))
+
+	val = res.Get("properties.loadShedding.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.LoadShedding.Enabled)
+
+	val = res.Get("properties.loadShedding.properties.maxInFlightCost.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.LoadShedding.MaxInFlightCost)
+
+	val = res.Get("properties.limitOverrides.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.LimitOverrides.Enabled)
+
+	val = res.Get("properties.limitOverrides.properties.trustedPrincipals.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.LimitOverrides.TrustedPrincipals, len(val.Array()))
+
+	val = res.Get("properties.limitOverrides.properties.maxResolveDepth.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Uint(), cfg.LimitOverrides.MaxResolveDepth)
+
+	val = res.Get("properties.limitOverrides.properties.maxListObjectsDeadline.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.LimitOverrides.MaxListObjectsDeadline.String())
+
+	val = res.Get("properties.authorizationModelRetention.properties.count.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.AuthorizationModelRetention.Count)
+
+	val = res.Get("properties.authorizationModelRetention.properties.pruneInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.AuthorizationModelRetention.PruneInterval.String())
+
+	val = res.Get("properties.modelSync.properties.sources.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.ModelSync.Sources, len(val.Array()))
+
+	val = res.Get("properties.modelSync.properties.interval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.ModelSync.Interval.String())
+
+	val = res.Get("properties.trustedContext.properties.currentTimeParameter.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.TrustedContext.CurrentTimeParameter)
+
+	val = res.Get("properties.trustedContext.properties.currentTimePrecision.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.TrustedContext.CurrentTimePrecision.String())
+
+	val = res.Get("properties.trustedContext.properties.callerParameter.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.TrustedContext.CallerParameter)
+
+	val = res.Get("properties.typesystemCache.properties.ttl.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.TypesystemCache.TTL.String())
+
+	val = res.Get("properties.typesystemCache.properties.size.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.TypesystemCache.Size)
+
+	val = res.Get("properties.typesystemCache.properties.storeTTLs.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.TypesystemCache.StoreTTLs, len(val.Array()))
+
+	val = res.Get("properties.typesystemCache.properties.warmupStores.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.TypesystemCache.WarmupStores, len(val.Array()))
+
+	val = res.Get("properties.typesystemCache.properties.warmupMRUFile.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.TypesystemCache.WarmupMRUFile)
+
+	val = res.Get("properties.typesystemCache.properties.redis.properties.addr.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.TypesystemCache.Redis.Addr)
+
+	val = res.Get("properties.typesystemCache.properties.redis.properties.db.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.TypesystemCache.Redis.DB)
+
+	val = res.Get("properties.typesystemCache.properties.redis.properties.tls.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.TypesystemCache.Redis.TLS)
+
+	val = res.Get("properties.maxConcurrentJobs.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.MaxConcurrentJobs)
+
+	val = res.Get("properties.maxJobTuples.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.MaxJobTuples)
+
 	val = res.Get("properties.requestDurationDatastoreQueryCountBuckets.default")
 	require.True(t, val.Exists())
 	require.Len(t, cfg.RequestDurationDatastoreQueryCountBuckets, len(val.Array()))
@@ -1237,6 +1943,10 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.False(t, val.Bool())
 
+	val = res.Get("properties.resolutionMetadataHeaders.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.ResolutionMetadataHeaders)
+
 	val = res.Get("properties.checkDispatchThrottling.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.CheckDispatchThrottling.Enabled)
@@ -1285,9 +1995,21 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.ListUsersDispatchThrottling.MaxThreshold)
 
+	val = res.Get("properties.shutdownDrainDelay.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.ShutdownDrainDelay.String())
+
+	val = res.Get("properties.shutdownDrainTimeout.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.ShutdownDrainTimeout.String())
+
 	val = res.Get("properties.requestTimeout.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.RequestTimeout.String())
+
+	val = res.Get("properties.methodTimeouts.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.MethodTimeouts, len(val.Array()))
 }
 
 func TestRunCommandNoConfigDefaultValues(t *testing.T) {
@@ -1525,6 +2247,140 @@ func TestHTTPHeaders(t *testing.T) {
 	}
 }
 
+func TestHTTPRequestMetadataHeaders(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	cfg := testutils.MustDefaultConfigWithRandomPorts()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go func() {
+		if err := runServer(ctx, cfg); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	testutils.EnsureServiceHealthy(t, cfg.GRPC.Addr, cfg.HTTP.Addr, nil)
+
+	conn := testutils.CreateGrpcConnection(t, cfg.GRPC.Addr)
+	client := openfgav1.NewOpenFGAServiceClient(conn)
+
+	createStoreResp, err := client.CreateStore(context.Background(), &openfgav1.CreateStoreRequest{
+		Name: "openfga-demo",
+	})
+	require.NoError(t, err)
+	storeID := createStoreResp.GetId()
+
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+		type user
+
+		type document
+			relations
+				define editor: [user]
+				define viewer: [user, user with is_open]
+
+		condition is_open(open: bool) {
+			open
+		}`)
+	_, err = client.WriteAuthorizationModel(context.Background(), &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:         storeID,
+		SchemaVersion:   typesystem.SchemaVersion1_1,
+		TypeDefinitions: model.GetTypeDefinitions(),
+		Conditions:      model.GetConditions(),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Write(context.Background(), &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:1", "editor", "user:bob"),
+			tuple.NewTupleKey("document:2", "viewer", "user:anne"),
+			tuple.NewTupleKeyWithCondition("document:3", "viewer", "user:anne", "is_open", nil),
+		}},
+	})
+	require.NoError(t, err)
+
+	httpClient := retryablehttp.NewClient()
+	t.Cleanup(httpClient.HTTPClient.CloseIdleConnections)
+
+	do := func(t *testing.T, method, path, body string, headers map[string]string) map[string]any {
+		req, err := retryablehttp.NewRequest(method, fmt.Sprintf("http://%s/stores/%s/%s", cfg.HTTP.Addr, storeID, path), strings.NewReader(body))
+		require.NoError(t, err)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		httpResponse, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer httpResponse.Body.Close()
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+		var resp map[string]any
+		require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&resp))
+		return resp
+	}
+
+	t.Run("read_changes", func(t *testing.T) {
+		resp := do(t, http.MethodGet, "changes?type=document", "", map[string]string{
+			"Openfga-Read-Changes-Object-Id": "1",
+			"Openfga-Read-Changes-User":      "user:anne",
+		})
+		require.Len(t, resp["changes"], 1)
+	})
+
+	t.Run("read", func(t *testing.T) {
+		resp := do(t, http.MethodPost, "read", `{"tuple_key": {"object": "document:1"}}`, map[string]string{
+			"Openfga-Read-Relations": "editor",
+			"Openfga-Read-User-Type": "user",
+		})
+		require.Len(t, resp["tuples"], 1)
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		expand := func(context string) any {
+			resp := do(t, http.MethodPost, "expand", `{"tuple_key": {"object": "document:3", "relation": "viewer"}}`, map[string]string{
+				"Openfga-Expand-Context": context,
+			})
+			return resp["tree"].(map[string]any)["root"].(map[string]any)["leaf"].(map[string]any)["users"].(map[string]any)["users"]
+		}
+
+		require.Equal(t, []any{"user:anne"}, expand(`{"open": true}`))
+		require.Empty(t, expand(`{"open": false}`))
+	})
+
+	t.Run("streamed_read", func(t *testing.T) {
+		req, err := retryablehttp.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/stores/%s/streamed-read", cfg.HTTP.Addr, storeID), strings.NewReader(`{"tuple_key": {"object": "document:", "user": "user:anne"}}`))
+		require.NoError(t, err)
+		req.Header.Set("Openfga-Read-Relations", "viewer")
+
+		httpResponse, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer httpResponse.Body.Close()
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+		var objects []string
+		decoder := json.NewDecoder(httpResponse.Body)
+		for decoder.More() {
+			var resp struct {
+				Result *struct {
+					Key struct {
+						Object string `json:"object"`
+					} `json:"key"`
+				} `json:"result"`
+				Error any `json:"error"`
+			}
+			require.NoError(t, decoder.Decode(&resp))
+			require.Nil(t, resp.Error)
+			objects = append(objects, resp.Result.Key.Object)
+		}
+		require.ElementsMatch(t, []string{"document:1", "document:2", "document:3"}, objects)
+	})
+}
+
 func TestServerContext_datastoreConfig(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1601,7 +2457,7 @@ func TestServerContext_datastoreConfig(t *testing.T) {
 			s := &ServerContext{
 				Logger: logger.NewNoopLogger(),
 			}
-			datastore, serializer, err := s.datastoreConfig(tt.config)
+			datastore, serializer, err := s.datastoreConfig(context.Background(), tt.config)
 			if tt.wantErr != nil {
 				require.Error(t, err)
 				assert.Nil(t, datastore)
@@ -1615,3 +2471,42 @@ func TestServerContext_datastoreConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestDatastoreFaultPolicies(t *testing.T) {
+	t.Run("policies_override_the_default_faults", func(t *testing.T) {
+		policies, err := datastoreFaultPolicies(serverconfig.DatastoreFaultInjectionConfig{
+			Operations: []string{"Read", "Write"},
+			Latency:    10 * time.Millisecond,
+			Policies:   []string{"Write:0s:1:0s", "ReadUserTuple:50ms:0:0s"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]storagewrappers.FaultPolicy{
+			"Read":          {Latency: 10 * time.Millisecond},
+			"Write":         {ErrorRate: 1},
+			"ReadUserTuple": {Latency: 50 * time.Millisecond},
+		}, policies)
+	})
+
+	t.Run("policies_without_default_faults", func(t *testing.T) {
+		policies, err := datastoreFaultPolicies(serverconfig.DatastoreFaultInjectionConfig{
+			Policies: []string{"ReadUserTuple:50ms:0:0s"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]storagewrappers.FaultPolicy{
+			"ReadUserTuple": {Latency: 50 * time.Millisecond},
+		}, policies)
+	})
+
+	t.Run("unknown_operation", func(t *testing.T) {
+		_, err := datastoreFaultPolicies(serverconfig.DatastoreFaultInjectionConfig{
+			Policies: []string{"ReadUserTupel:50ms:0:0s"},
+		})
+		require.ErrorContains(t, err, "unknown operation 'ReadUserTupel'")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		policies, err := datastoreFaultPolicies(serverconfig.DatastoreFaultInjectionConfig{Operations: []string{"Read"}})
+		require.NoError(t, err)
+		require.Nil(t, policies)
+	})
+}
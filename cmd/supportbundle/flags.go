@@ -0,0 +1,23 @@
+package supportbundle
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openfga/openfga/cmd/util"
+)
+
+// bindRunFlagsFunc binds the cobra cmd flags to the equivalent config value being managed
+// by viper. This bridges the config between cobra flags and viper flags. bindServerFlags
+// binds the flags of the run command, which the server config is read from.
+func bindRunFlagsFunc(flags *pflag.FlagSet, bindServerFlags func(*cobra.Command, []string)) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		bindServerFlags(cmd, args)
+
+		util.MustBindPFlag(outputFlag, flags.Lookup(outputFlag))
+		util.MustBindPFlag(metricsSnapshotsFlag, flags.Lookup(metricsSnapshotsFlag))
+		util.MustBindPFlag(metricsIntervalFlag, flags.Lookup(metricsIntervalFlag))
+		util.MustBindPFlag(logFileFlag, flags.Lookup(logFileFlag))
+		util.MustBindPFlag(slowQueryThresholdFlag, flags.Lookup(slowQueryThresholdFlag))
+	}
+}
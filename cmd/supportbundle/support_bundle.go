@@ -0,0 +1,363 @@
+// Package supportbundle contains the command to collect the diagnostics of a server into an archive.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // MySQL driver.
+	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver.
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/openfga/openfga/cmd/run"
+	"github.com/openfga/openfga/internal/build"
+	serverconfig "github.com/openfga/openfga/pkg/server/config"
+	"github.com/openfga/openfga/pkg/storage/sqlite"
+)
+
+const (
+	outputFlag             = "output"
+	metricsSnapshotsFlag   = "metrics-snapshots"
+	metricsIntervalFlag    = "metrics-interval"
+	logFileFlag            = "log-file"
+	slowQueryThresholdFlag = "slow-query-threshold"
+
+	// queryDurationKey is the field holding the duration of a request in the logs of the server.
+	queryDurationKey = "query_duration_ms"
+
+	httpTimeout = 30 * time.Second
+)
+
+func NewSupportBundleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "support-bundle",
+		Short: "Collect the diagnostics of a running server into an archive to attach to bug reports",
+		Long: "Collect the redacted config, the datastore schema version, snapshots of the metrics, goroutine and heap " +
+			"profiles and the slow requests from the logs of a running server into a gzipped tar archive.\n" +
+			"The config is resolved from the same flags, environment variables and config file as the run command. " +
+			"Secrets, such as the datastore uri and the authentication keys, are left out.\n" +
+			"Metrics and profiles are only collected if the server exposes them ('--metrics-enabled' and '--profiler-enabled').",
+		RunE: runSupportBundle,
+		Args: cobra.NoArgs,
+	}
+
+	// the flags of the run command are accepted, so that the config is resolved as the server resolves it
+	runCmd := run.NewRunCommand()
+	cmd.Flags().AddFlagSet(runCmd.Flags())
+
+	flags := cmd.Flags()
+	flags.String(outputFlag, "", "the path of the archive to write (default 'openfga-support-bundle-<timestamp>.tar.gz')")
+	flags.Int(metricsSnapshotsFlag, 3, "the number of snapshots of the metrics to collect")
+	flags.Duration(metricsIntervalFlag, 10*time.Second, "the interval between two snapshots of the metrics")
+	flags.String(logFileFlag, "", "the path of a file holding the JSON logs of the server, to collect the slow requests from")
+	flags.Duration(slowQueryThresholdFlag, time.Second, "the duration above which a request logged in '--log-file' is considered slow")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PreRun = bindRunFlagsFunc(flags, runCmd.PreRun)
+
+	return cmd
+}
+
+// Options are the settings of the diagnostics collected by Collect.
+type Options struct {
+	// MetricsSnapshots is the number of snapshots of the metrics endpoint to collect.
+	MetricsSnapshots int
+	// MetricsInterval is the interval between two snapshots.
+	MetricsInterval time.Duration
+	// LogFile is the path of the JSON logs to collect the slow requests from. Empty skips them.
+	LogFile string
+	// SlowQueryThreshold is the duration above which a logged request is considered slow.
+	SlowQueryThreshold time.Duration
+}
+
+func runSupportBundle(cmd *cobra.Command, _ []string) error {
+	config, err := run.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	output := viper.GetString(outputFlag)
+	if output == "" {
+		output = fmt.Sprintf("openfga-support-bundle-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create the support bundle: %w", err)
+	}
+	defer file.Close()
+
+	err = Collect(cmd.Context(), file, config, Options{
+		MetricsSnapshots:   viper.GetInt(metricsSnapshotsFlag),
+		MetricsInterval:    viper.GetDuration(metricsIntervalFlag),
+		LogFile:            viper.GetString(logFileFlag),
+		SlowQueryThreshold: viper.GetDuration(slowQueryThresholdFlag),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "support bundle written to %s\n", output)
+	return nil
+}
+
+// Collect writes the diagnostics of the server configured by config to w as a gzipped tar archive.
+// A diagnostic that cannot be collected, e.g. because the server does not expose it, does not fail
+// the collection: the error is recorded in the 'errors.txt' file of the archive instead.
+func Collect(ctx context.Context, w io.Writer, config *serverconfig.Config, opts Options) error {
+	gz := gzip.NewWriter(w)
+	archive := &bundle{tw: tar.NewWriter(gz), now: time.Now()}
+
+	if err := archive.addJSON("version.json", map[string]string{
+		"version":    build.Version,
+		"commit":     build.Commit,
+		"date":       build.Date,
+		"go_version": runtime.Version(),
+	}); err != nil {
+		return err
+	}
+
+	// the secrets of the config are tagged `json:"-"`, so they are left out
+	if err := archive.addJSON("config.json", config); err != nil {
+		return err
+	}
+
+	var collectErrs []string
+	record := func(diagnostic string, err error) {
+		if err != nil {
+			collectErrs = append(collectErrs, fmt.Sprintf("%s: %v", diagnostic, err))
+		}
+	}
+
+	schemaVersion, err := readSchemaVersion(ctx, config.Datastore)
+	record("schema version", err)
+	if err == nil {
+		record("schema version", archive.addJSON("schema_version.json", schemaVersion))
+	}
+
+	client := &http.Client{Timeout: httpTimeout}
+
+	if config.Metrics.Enabled {
+		metricsURL := localURL(config.Metrics.Addr) + "/metrics"
+		for i := 0; i < opts.MetricsSnapshots; i++ {
+			if i > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(opts.MetricsInterval):
+				}
+			}
+			body, err := fetch(ctx, client, metricsURL)
+			record("metrics", err)
+			if err == nil {
+				record("metrics", archive.add(fmt.Sprintf("metrics/snapshot-%d.txt", i+1), body))
+			}
+		}
+	} else {
+		record("metrics", errors.New("the metrics endpoint is disabled"))
+	}
+
+	if config.Profiler.Enabled {
+		profilerURL := localURL(config.Profiler.Addr) + "/debug/pprof"
+		profiles := map[string]string{
+			"profiles/goroutine.txt": profilerURL + "/goroutine?debug=2",
+			"profiles/heap.pb.gz":    profilerURL + "/heap",
+		}
+		for name, profileURL := range profiles {
+			body, err := fetch(ctx, client, profileURL)
+			record(name, err)
+			if err == nil {
+				record(name, archive.add(name, body))
+			}
+		}
+	} else {
+		record("profiles", errors.New("the profiler is disabled"))
+	}
+
+	if opts.LogFile != "" {
+		slowQueries, err := readSlowQueries(opts.LogFile, opts.SlowQueryThreshold)
+		record("slow queries", err)
+		if err == nil {
+			record("slow queries", archive.add("slow_queries.log", slowQueries))
+		}
+	}
+
+	if len(collectErrs) > 0 {
+		if err := archive.add("errors.txt", []byte(strings.Join(collectErrs, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.tw.Close(); err != nil {
+		return fmt.Errorf("failed to write the support bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write the support bundle: %w", err)
+	}
+
+	return nil
+}
+
+type bundle struct {
+	tw  *tar.Writer
+	now time.Time
+}
+
+func (b *bundle) add(name string, content []byte) error {
+	err := b.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: b.now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s to the support bundle: %w", name, err)
+	}
+	if _, err := b.tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s to the support bundle: %w", name, err)
+	}
+	return nil
+}
+
+func (b *bundle) addJSON(name string, v any) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	return b.add(name, content)
+}
+
+// SchemaVersion is the schema revision of the datastore, and the minimum revision this build requires.
+type SchemaVersion struct {
+	Engine          string `json:"engine"`
+	Revision        int64  `json:"revision"`
+	MinimumRevision int64  `json:"minimum_revision"`
+}
+
+// readSchemaVersion reads the last migration applied to the datastore, without creating the
+// migrations table if it is missing.
+func readSchemaVersion(ctx context.Context, config serverconfig.DatastoreConfig) (*SchemaVersion, error) {
+	var (
+		driver string
+		uri    = config.URI
+	)
+	switch config.Engine {
+	case "mysql":
+		driver = "mysql"
+	case "postgres":
+		driver = "pgx"
+	case "sqlite":
+		driver = "sqlite"
+		var err error
+		if uri, err = sqlite.PrepareDSN(uri); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("the '%s' datastore engine has no schema", config.Engine)
+	}
+
+	db, err := sql.Open(driver, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a connection to the datastore: %w", err)
+	}
+	defer db.Close()
+
+	version := &SchemaVersion{
+		Engine:          config.Engine,
+		MinimumRevision: build.MinimumSupportedDatastoreSchemaRevision,
+	}
+	err = db.QueryRowContext(ctx, "SELECT version_id FROM goose_db_version ORDER BY id DESC LIMIT 1").Scan(&version.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// localURL returns the URL to reach a server listening on addr from the same host.
+func localURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+func fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// readSlowQueries returns the lines of the JSON logs at path that record a request that took at least threshold.
+// Lines that are not JSON, such as the logs of the 'text' format, are skipped.
+func readSlowQueries(path string, threshold time.Duration) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var slow bytes.Buffer
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+
+		// the duration is logged as a string of milliseconds
+		durationMs, ok := entry[queryDurationKey].(string)
+		if !ok {
+			continue
+		}
+		ms, err := strconv.ParseInt(durationMs, 10, 64)
+		if err != nil || time.Duration(ms)*time.Millisecond < threshold {
+			continue
+		}
+
+		slow.Write(line)
+		slow.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return slow.Bytes(), nil
+}
@@ -0,0 +1,22 @@
+package syncstore
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openfga/openfga/cmd/util"
+)
+
+// bindRunFlags binds the cobra cmd flags to the equivalent config value being managed
+// by viper. This bridges the config between cobra flags and viper flags.
+func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		util.MustBindPFlag(sourceDatastoreEngineFlag, flags.Lookup(sourceDatastoreEngineFlag))
+		util.MustBindPFlag(sourceDatastoreURIFlag, flags.Lookup(sourceDatastoreURIFlag))
+		util.MustBindPFlag(sourceStoreIDFlag, flags.Lookup(sourceStoreIDFlag))
+		util.MustBindPFlag(targetDatastoreEngineFlag, flags.Lookup(targetDatastoreEngineFlag))
+		util.MustBindPFlag(targetDatastoreURIFlag, flags.Lookup(targetDatastoreURIFlag))
+		util.MustBindPFlag(targetStoreIDFlag, flags.Lookup(targetStoreIDFlag))
+		util.MustBindPFlag(dryRunFlag, flags.Lookup(dryRunFlag))
+	}
+}
@@ -0,0 +1,313 @@
+// Package syncstore contains the command to bring a store in sync with another one.
+package syncstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/mysql"
+	"github.com/openfga/openfga/pkg/storage/postgres"
+	"github.com/openfga/openfga/pkg/storage/sqlcommon"
+	"github.com/openfga/openfga/pkg/storage/sqlite"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+const (
+	sourceDatastoreEngineFlag = "source-datastore-engine"
+	sourceDatastoreURIFlag    = "source-datastore-uri"
+	sourceStoreIDFlag         = "source-store-id"
+	targetDatastoreEngineFlag = "target-datastore-engine"
+	targetDatastoreURIFlag    = "target-datastore-uri"
+	targetStoreIDFlag         = "target-store-id"
+	dryRunFlag                = "dry-run"
+
+	readPageSize = 100
+)
+
+func NewSyncStoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-store",
+		Short: "Bring a store in sync with another store. NOTE: this command is in beta and may be removed in future releases.",
+		Long: "Diff the authorization models and tuples of a source store against a target store, possibly on another datastore, " +
+			"and apply the difference to the target store so that it matches the source store.\n" +
+			"Use --dry-run to only print the difference.\nNOTE: this command is in beta and may be removed in future releases.",
+		RunE: runSyncStore,
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.String(sourceDatastoreEngineFlag, "", "the datastore engine of the source store")
+	flags.String(sourceDatastoreURIFlag, "", "the connection uri to the datastore of the source store")
+	flags.String(sourceStoreIDFlag, "", "the id of the source store")
+	flags.String(targetDatastoreEngineFlag, "", "the datastore engine of the target store")
+	flags.String(targetDatastoreURIFlag, "", "the connection uri to the datastore of the target store")
+	flags.String(targetStoreIDFlag, "", "the id of the target store")
+	flags.Bool(dryRunFlag, false, "print the difference without applying it")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PreRun = bindRunFlagsFunc(flags)
+
+	return cmd
+}
+
+// SyncResult is the difference applied, or to be applied when running a dry-run, to the target store.
+type SyncResult struct {
+	DryRun bool `json:"dry_run"`
+	// WrittenModels are the ids of the authorization models missing from the target store.
+	WrittenModels []string `json:"written_models"`
+	// WrittenTuples are the tuples missing from the target store, or whose condition differs.
+	WrittenTuples []string `json:"written_tuples"`
+	// DeletedTuples are the tuples of the target store that are missing from the source store, or whose condition differs.
+	DeletedTuples []string `json:"deleted_tuples"`
+}
+
+func runSyncStore(cmd *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	sourceStoreID := viper.GetString(sourceStoreIDFlag)
+	targetStoreID := viper.GetString(targetStoreIDFlag)
+	if sourceStoreID == "" || targetStoreID == "" {
+		return fmt.Errorf("missing source or target store id")
+	}
+
+	source, err := openDatastore(viper.GetString(sourceDatastoreEngineFlag), viper.GetString(sourceDatastoreURIFlag))
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	defer source.Close()
+
+	target, err := openDatastore(viper.GetString(targetDatastoreEngineFlag), viper.GetString(targetDatastoreURIFlag))
+	if err != nil {
+		return fmt.Errorf("target: %w", err)
+	}
+	defer target.Close()
+
+	result, err := SyncStore(ctx, source, sourceStoreID, target, targetStoreID, viper.GetBool(dryRunFlag))
+	if err != nil {
+		return err
+	}
+
+	marshalled, err := json.MarshalIndent(result, " ", "    ")
+	if err != nil {
+		return fmt.Errorf("error gathering sync results: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(marshalled))
+
+	return nil
+}
+
+func openDatastore(engine, uri string) (storage.OpenFGADatastore, error) {
+	var (
+		db  storage.OpenFGADatastore
+		err error
+	)
+	switch engine {
+	case "mysql":
+		db, err = mysql.New(uri, sqlcommon.NewConfig())
+	case "postgres":
+		db, err = postgres.New(uri, sqlcommon.NewConfig())
+	case "sqlite":
+		db, err = sqlite.New(uri, sqlcommon.NewConfig())
+	case "":
+		return nil, fmt.Errorf("missing datastore engine type")
+	case "memory":
+		fallthrough
+	default:
+		return nil, fmt.Errorf("storage engine '%s' is unsupported", engine)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a connection to the datastore: %v", err)
+	}
+
+	return db, nil
+}
+
+// SyncStore makes the target store match the source store: the authorization models missing from the target
+// store are written, keeping their ids, and the tuples are written and deleted so that both stores hold the same
+// tuples. The tuples are applied atomically, in a single write regardless of the MaxTuplesPerWrite of the target
+// datastore, before the models. If dryRun is true, the difference is computed but not applied.
+func SyncStore(ctx context.Context, source storage.OpenFGADatastore, sourceStoreID string, target storage.OpenFGADatastore, targetStoreID string, dryRun bool) (*SyncResult, error) {
+	result := &SyncResult{
+		DryRun:        dryRun,
+		WrittenModels: []string{},
+		WrittenTuples: []string{},
+		DeletedTuples: []string{},
+	}
+
+	missingModels, err := diffModels(ctx, source, sourceStoreID, target, targetStoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	writes, deletes, err := diffTuples(ctx, source, sourceStoreID, target, targetStoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, model := range missingModels {
+		result.WrittenModels = append(result.WrittenModels, model.GetId())
+	}
+	for _, tk := range writes {
+		result.WrittenTuples = append(result.WrittenTuples, tuple.TupleKeyToString(tk))
+	}
+	for _, tk := range deletes {
+		result.DeletedTuples = append(result.DeletedTuples, tuple.TupleKeyToString(tk))
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	// the tuples are applied in a single write, i.e. a single transaction, so that the target store never holds
+	// some of them only. The deletes are applied before the writes, since a tuple whose condition differs is both
+	// deleted and written
+	if len(deletes) > 0 || len(writes) > 0 {
+		if err := target.Write(ctx, targetStoreID, deletes, writes); err != nil {
+			return nil, fmt.Errorf("error writing tuples: %w", err)
+		}
+	}
+
+	// the models are written once the tuples are in sync, so that the latest model of the target store is never
+	// evaluated against the tuples of the previous one. A model that fails to be written is written by the next sync
+	for _, model := range missingModels {
+		if err := target.WriteAuthorizationModel(ctx, targetStoreID, model); err != nil {
+			return nil, fmt.Errorf("error writing authorization model %s: %w", model.GetId(), err)
+		}
+	}
+
+	return result, nil
+}
+
+// diffModels returns the authorization models of the source store that are missing from the target store,
+// from the oldest to the most recent so that they are written in the order they were written to the source store.
+func diffModels(ctx context.Context, source storage.OpenFGADatastore, sourceStoreID string, target storage.OpenFGADatastore, targetStoreID string) ([]*openfgav1.AuthorizationModel, error) {
+	sourceModels, err := readAllModels(ctx, source, sourceStoreID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading source authorization models: %w", err)
+	}
+
+	targetModels, err := readAllModels(ctx, target, targetStoreID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading target authorization models: %w", err)
+	}
+
+	existing := make(map[string]struct{}, len(targetModels))
+	for _, model := range targetModels {
+		existing[model.GetId()] = struct{}{}
+	}
+
+	var missing []*openfgav1.AuthorizationModel
+	for _, model := range slices.Backward(sourceModels) {
+		if _, ok := existing[model.GetId()]; !ok {
+			missing = append(missing, model)
+		}
+	}
+
+	return missing, nil
+}
+
+// readAllModels returns every authorization model of the store, from the most recent to the oldest.
+func readAllModels(ctx context.Context, db storage.OpenFGADatastore, storeID string) ([]*openfgav1.AuthorizationModel, error) {
+	var (
+		models            []*openfgav1.AuthorizationModel
+		continuationToken string
+	)
+
+	for {
+		opts := storage.ReadAuthorizationModelsOptions{
+			Pagination: storage.NewPaginationOptions(readPageSize, continuationToken),
+		}
+		page, token, err := db.ReadAuthorizationModels(ctx, storeID, opts)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, page...)
+
+		continuationToken = token
+		if continuationToken == "" {
+			return models, nil
+		}
+	}
+}
+
+// diffTuples returns the tuples to write to and delete from the target store so that it holds the same
+// tuples as the source store.
+func diffTuples(ctx context.Context, source storage.OpenFGADatastore, sourceStoreID string, target storage.OpenFGADatastore, targetStoreID string) ([]*openfgav1.TupleKey, []*openfgav1.TupleKeyWithoutCondition, error) {
+	sourceTuples, err := readAllTuples(ctx, source, sourceStoreID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading source tuples: %w", err)
+	}
+
+	targetTuples, err := readAllTuples(ctx, target, targetStoreID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading target tuples: %w", err)
+	}
+
+	var (
+		writes  []*openfgav1.TupleKey
+		deletes []*openfgav1.TupleKeyWithoutCondition
+	)
+
+	for key, tk := range sourceTuples {
+		existing, ok := targetTuples[key]
+		if ok && proto.Equal(existing.GetCondition(), tk.GetCondition()) {
+			continue
+		}
+		if ok {
+			deletes = append(deletes, tuple.TupleKeyToTupleKeyWithoutCondition(existing))
+		}
+		writes = append(writes, tk)
+	}
+
+	for key, tk := range targetTuples {
+		if _, ok := sourceTuples[key]; !ok {
+			deletes = append(deletes, tuple.TupleKeyToTupleKeyWithoutCondition(tk))
+		}
+	}
+
+	// sort for a deterministic output
+	slices.SortFunc(writes, func(a, b *openfgav1.TupleKey) int {
+		return strings.Compare(tuple.TupleKeyToString(a), tuple.TupleKeyToString(b))
+	})
+	slices.SortFunc(deletes, func(a, b *openfgav1.TupleKeyWithoutCondition) int {
+		return strings.Compare(tuple.TupleKeyToString(a), tuple.TupleKeyToString(b))
+	})
+
+	return writes, deletes, nil
+}
+
+// readAllTuples returns every tuple of the store, keyed by their string representation without condition.
+func readAllTuples(ctx context.Context, db storage.OpenFGADatastore, storeID string) (map[string]*openfgav1.TupleKey, error) {
+	tuples := map[string]*openfgav1.TupleKey{}
+	continuationToken := ""
+
+	for {
+		opts := storage.ReadPageOptions{
+			Pagination: storage.NewPaginationOptions(readPageSize, continuationToken),
+		}
+		page, token, err := db.ReadPage(ctx, storeID, &openfgav1.TupleKey{}, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range page {
+			tuples[tuple.TupleKeyToString(t.GetKey())] = t.GetKey()
+		}
+
+		continuationToken = token
+		if continuationToken == "" {
+			return tuples, nil
+		}
+	}
+}
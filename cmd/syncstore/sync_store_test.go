@@ -0,0 +1,144 @@
+package syncstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestSyncStore(t *testing.T) {
+	ctx := context.Background()
+	sourceStoreID := ulid.Make().String()
+	targetStoreID := ulid.Make().String()
+
+	newModel := func() *openfgav1.AuthorizationModel {
+		model := parser.MustTransformDSLToProto(`
+			model
+				schema 1.1
+			type user
+			type document
+				relations
+					define viewer: [user, user with condition1]
+			condition condition1(x: int) {
+				x < 100
+			}`)
+		model.Id = ulid.Make().String()
+		return model
+	}
+
+	setup := func(t *testing.T) (storage.OpenFGADatastore, storage.OpenFGADatastore, []*openfgav1.AuthorizationModel) {
+		source := memory.New()
+		target := memory.New()
+		t.Cleanup(source.Close)
+		t.Cleanup(target.Close)
+
+		models := []*openfgav1.AuthorizationModel{newModel(), newModel()}
+		for _, model := range models {
+			require.NoError(t, source.WriteAuthorizationModel(ctx, sourceStoreID, model))
+		}
+		require.NoError(t, target.WriteAuthorizationModel(ctx, targetStoreID, models[0]))
+
+		require.NoError(t, source.Write(ctx, sourceStoreID, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:2", "viewer", "user:bob"),
+			tuple.NewTupleKeyWithCondition("document:3", "viewer", "user:charlie", "condition1", nil),
+		}))
+		require.NoError(t, target.Write(ctx, targetStoreID, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:3", "viewer", "user:charlie"),
+			tuple.NewTupleKey("document:4", "viewer", "user:dave"),
+		}))
+
+		return source, target, models
+	}
+
+	t.Run("dry_run_does_not_modify_the_target_store", func(t *testing.T) {
+		source, target, models := setup(t)
+
+		result, err := SyncStore(ctx, source, sourceStoreID, target, targetStoreID, true)
+		require.NoError(t, err)
+		require.Equal(t, &SyncResult{
+			DryRun:        true,
+			WrittenModels: []string{models[1].GetId()},
+			WrittenTuples: []string{"document:2#viewer@user:bob", "document:3#viewer@user:charlie"},
+			DeletedTuples: []string{"document:3#viewer@user:charlie", "document:4#viewer@user:dave"},
+		}, result)
+
+		latest, err := target.FindLatestAuthorizationModel(ctx, targetStoreID)
+		require.NoError(t, err)
+		require.Equal(t, models[0].GetId(), latest.GetId())
+	})
+
+	t.Run("target_store_matches_source_store", func(t *testing.T) {
+		source, target, models := setup(t)
+
+		_, err := SyncStore(ctx, source, sourceStoreID, target, targetStoreID, false)
+		require.NoError(t, err)
+
+		latest, err := target.FindLatestAuthorizationModel(ctx, targetStoreID)
+		require.NoError(t, err)
+		require.Equal(t, models[1].GetId(), latest.GetId())
+
+		sourceTuples, err := readAllTuples(ctx, source, sourceStoreID)
+		require.NoError(t, err)
+		targetTuples, err := readAllTuples(ctx, target, targetStoreID)
+		require.NoError(t, err)
+		require.Len(t, targetTuples, len(sourceTuples))
+		for key, tk := range sourceTuples {
+			require.Contains(t, targetTuples, key)
+			require.Equal(t, tk.GetCondition().GetName(), targetTuples[key].GetCondition().GetName())
+		}
+
+		result, err := SyncStore(ctx, source, sourceStoreID, target, targetStoreID, true)
+		require.NoError(t, err)
+		require.Empty(t, result.WrittenModels)
+		require.Empty(t, result.WrittenTuples)
+		require.Empty(t, result.DeletedTuples)
+	})
+
+	t.Run("failed_write_leaves_the_target_store_unchanged", func(t *testing.T) {
+		source, target, models := setup(t)
+		failing := &failingWritesDatastore{OpenFGADatastore: target}
+
+		_, err := SyncStore(ctx, source, sourceStoreID, failing, targetStoreID, false)
+		require.ErrorContains(t, err, "write failed")
+		require.Equal(t, 1, failing.writes)
+
+		latest, err := target.FindLatestAuthorizationModel(ctx, targetStoreID)
+		require.NoError(t, err)
+		require.Equal(t, models[0].GetId(), latest.GetId())
+
+		result, err := SyncStore(ctx, source, sourceStoreID, target, targetStoreID, true)
+		require.NoError(t, err)
+		require.Len(t, result.WrittenTuples, 2)
+		require.Len(t, result.DeletedTuples, 2)
+	})
+}
+
+// failingWritesDatastore fails the tuple writes, counting them.
+type failingWritesDatastore struct {
+	storage.OpenFGADatastore
+	writes int
+}
+
+func (d *failingWritesDatastore) Write(context.Context, string, storage.Deletes, storage.Writes) error {
+	d.writes++
+	return errors.New("write failed")
+}
+
+func TestSyncStoreCommandWhenInvalidEngine(t *testing.T) {
+	syncStoreCommand := NewSyncStoreCommand()
+	syncStoreCommand.SetArgs([]string{"--source-store-id", "a", "--target-store-id", "b", "--source-datastore-engine", "memory"})
+	err := syncStoreCommand.Execute()
+	require.ErrorContains(t, err, "storage engine 'memory' is unsupported")
+}
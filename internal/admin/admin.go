@@ -0,0 +1,131 @@
+// Package admin serves the operational actions of the server, e.g. flushing its caches, over HTTP on their own
+// listener, apart from the API.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/server"
+)
+
+// ServiceName is the name of the admin service in the gRPC health checks of the server.
+const ServiceName = "openfga.admin.v1.AdminService"
+
+// Operations are the operational actions of the server.
+type Operations interface {
+	FlushCheckCache() error
+	InvalidateTypesystemCache(storeID string)
+	Experimentals() []server.ExperimentalFeatureFlag
+}
+
+type handler struct {
+	ops    Operations
+	config func() any
+	keys   [][]byte
+	logger logger.Logger
+}
+
+// NewHandler returns the handler of the admin API, authenticating the requests with the preshared keys, sent as
+// bearer tokens. config returns the effective configuration of the server, dumped as JSON, so its secrets must be
+// excluded from its JSON encoding.
+//
+// The routes are:
+//
+//	POST /admin/v1/check-cache/flush
+//	POST /admin/v1/stores/{store_id}/typesystem-cache/invalidate
+//	GET  /admin/v1/config
+//	GET  /admin/v1/experimentals
+func NewHandler(ops Operations, config func() any, keys []string, l logger.Logger) http.Handler {
+	h := &handler{ops: ops, config: config, logger: l}
+	for _, key := range keys {
+		h.keys = append(h.keys, []byte(key))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/v1/check-cache/flush", h.flushCheckCache)
+	mux.HandleFunc("POST /admin/v1/stores/{store_id}/typesystem-cache/invalidate", h.invalidateTypesystemCache)
+	mux.HandleFunc("GET /admin/v1/config", h.getConfig)
+	mux.HandleFunc("GET /admin/v1/experimentals", h.getExperimentals)
+
+	return h.authenticate(mux)
+}
+
+// authenticate serves the requests with a valid preshared key with next.
+func (h *handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok {
+			for _, key := range h.keys {
+				if subtle.ConstantTimeCompare([]byte(token), key) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+
+		h.logger.Info("admin authentication failed",
+			logger.SecurityEvent(logger.SecurityEventAuthenticationFailed),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		writeError(w, http.StatusUnauthorized, "unauthenticated")
+	})
+}
+
+func (h *handler) flushCheckCache(w http.ResponseWriter, r *http.Request) {
+	if err := h.ops.FlushCheckCache(); err != nil {
+		if errors.Is(err, server.ErrCheckCacheNotFlushable) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.audit(r, "flush_check_cache")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) invalidateTypesystemCache(w http.ResponseWriter, r *http.Request) {
+	storeID := r.PathValue("store_id")
+	h.ops.InvalidateTypesystemCache(storeID)
+	h.audit(r, "invalidate_typesystem_cache", zap.String("store_id", storeID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) getConfig(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.config())
+}
+
+func (h *handler) getExperimentals(w http.ResponseWriter, _ *http.Request) {
+	experimentals := h.ops.Experimentals()
+	if experimentals == nil {
+		experimentals = []server.ExperimentalFeatureFlag{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"experimentals": experimentals})
+}
+
+// audit logs the admin action as a security event.
+func (h *handler) audit(r *http.Request, action string, fields ...zap.Field) {
+	fields = append([]zap.Field{
+		logger.SecurityEvent(logger.SecurityEventAdminAction),
+		zap.String("action", action),
+		zap.String("remote_addr", r.RemoteAddr),
+	}, fields...)
+	h.logger.Info("admin action", fields...)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"message": message})
+}
@@ -0,0 +1,174 @@
+// Package mtls implements authentication of clients by the certificate they present during a mutual TLS handshake.
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/openfga/openfga/internal/authn"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+// caReloadInterval is how often the client CA bundle is checked for changes.
+var caReloadInterval = 30 * time.Second
+
+// MTLSAuthenticator authenticates requests by the client certificate verified during the TLS handshake.
+// The certificate subject is exposed as the subject of the claims, and its common name (or, if empty, its
+// first SAN) as the client id, so that it can be used by access control and audit logging.
+type MTLSAuthenticator struct{}
+
+var _ authn.Authenticator = (*MTLSAuthenticator)(nil)
+
+func NewMTLSAuthenticator() *MTLSAuthenticator {
+	return &MTLSAuthenticator{}
+}
+
+func (m *MTLSAuthenticator) Authenticate(ctx context.Context) (*authclaims.AuthClaims, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, authn.ErrUnauthenticated
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, authn.ErrUnauthenticated
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	sans := subjectAlternativeNames(cert)
+
+	clientID := cert.Subject.CommonName
+	if clientID == "" && len(sans) > 0 {
+		clientID = sans[0]
+	}
+
+	return &authclaims.AuthClaims{
+		Subject:         cert.Subject.String(),
+		ClientID:        clientID,
+		CertificateSANs: sans,
+	}, nil
+}
+
+func (m *MTLSAuthenticator) Close() {}
+
+func subjectAlternativeNames(cert *x509.Certificate) []string {
+	var sans []string
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// ClientCAs holds the pool of certificate authorities trusted to issue client certificates. The bundle is
+// reloaded when the file changes, so that certificate authorities can be rotated without restarting the server.
+type ClientCAs struct {
+	path   string
+	logger logger.Logger
+
+	mu      sync.RWMutex
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+// NewClientCAs loads the PEM encoded certificate authorities at path.
+func NewClientCAs(path string, logger logger.Logger) (*ClientCAs, error) {
+	c := &ClientCAs{
+		path:   path,
+		logger: logger,
+	}
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Watch reloads the bundle whenever it changes, until ctx is done.
+func (c *ClientCAs) Watch(ctx context.Context) {
+	ticker := time.NewTicker(caReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := c.reload()
+			if err != nil {
+				c.logger.Error("failed to reload client CA bundle, keeping the previous one", zap.String("path", c.path), zap.Error(err))
+				continue
+			}
+			if reloaded {
+				c.logger.Info("client CA bundle reloaded", zap.String("path", c.path))
+			}
+		}
+	}
+}
+
+// reload reads the bundle if it has changed since it was last read.
+func (c *ClientCAs) reload() (bool, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+
+	c.mu.RLock()
+	unchanged := c.pool != nil && info.ModTime().Equal(c.modTime)
+	c.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	pem, err := os.ReadFile(c.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return false, errors.New("client CA bundle contains no valid certificate")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pool = pool
+	c.modTime = info.ModTime()
+
+	return true, nil
+}
+
+// Pool returns the current pool of certificate authorities.
+func (c *ClientCAs) Pool() *x509.CertPool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pool
+}
+
+// TLSConfig returns a copy of base that requires clients to present a certificate issued by one of the
+// current certificate authorities.
+func (c *ClientCAs) TLSConfig(base *tls.Config) *tls.Config {
+	cfg := base.Clone()
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		clientCfg := base.Clone()
+		clientCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		clientCfg.ClientCAs = c.Pool()
+		return clientCfg, nil
+	}
+	return cfg
+}
@@ -0,0 +1,128 @@
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/openfga/openfga/internal/authn"
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+func TestMTLSAuthenticator(t *testing.T) {
+	authenticator := NewMTLSAuthenticator()
+
+	t.Run("no_peer", func(t *testing.T) {
+		_, err := authenticator.Authenticate(context.Background())
+		require.ErrorIs(t, err, authn.ErrUnauthenticated)
+	})
+
+	t.Run("no_verified_certificate", func(t *testing.T) {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}})
+		_, err := authenticator.Authenticate(ctx)
+		require.ErrorIs(t, err, authn.ErrUnauthenticated)
+	})
+
+	t.Run("verified_certificate", func(t *testing.T) {
+		spiffeID, err := url.Parse("spiffe://example.org/ns/prod/sa/billing")
+		require.NoError(t, err)
+
+		cert := &x509.Certificate{
+			Subject:  pkix.Name{CommonName: "billing", Organization: []string{"Example"}},
+			URIs:     []*url.URL{spiffeID},
+			DNSNames: []string{"billing.example.org"},
+		}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		}})
+
+		claims, err := authenticator.Authenticate(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "CN=billing,O=Example", claims.Subject)
+		require.Equal(t, "billing", claims.ClientID)
+		require.Equal(t, []string{"spiffe://example.org/ns/prod/sa/billing", "billing.example.org"}, claims.CertificateSANs)
+	})
+
+	t.Run("client_id_falls_back_to_san", func(t *testing.T) {
+		cert := &x509.Certificate{DNSNames: []string{"billing.example.org"}}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		}})
+
+		claims, err := authenticator.Authenticate(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "billing.example.org", claims.ClientID)
+	})
+}
+
+func TestClientCAs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+
+	t.Run("invalid_bundle", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+		_, err := NewClientCAs(path, logger.NewNoopLogger())
+		require.Error(t, err)
+	})
+
+	t.Run("reload_on_change", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, generateCA(t, "first"), 0o600))
+		clientCAs, err := NewClientCAs(path, logger.NewNoopLogger())
+		require.NoError(t, err)
+		first := clientCAs.Pool()
+
+		reloaded, err := clientCAs.reload()
+		require.NoError(t, err)
+		require.False(t, reloaded)
+
+		require.NoError(t, os.WriteFile(path, generateCA(t, "second"), 0o600))
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, later, later))
+
+		reloaded, err = clientCAs.reload()
+		require.NoError(t, err)
+		require.True(t, reloaded)
+		require.False(t, first.Equal(clientCAs.Pool()))
+
+		cfg := clientCAs.TLSConfig(&tls.Config{})
+		require.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+		clientCfg, err := cfg.GetConfigForClient(nil)
+		require.NoError(t, err)
+		require.True(t, clientCAs.Pool().Equal(clientCfg.ClientCAs))
+	})
+}
+
+func generateCA(t *testing.T, name string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -30,15 +30,38 @@ type RemoteOidcAuthenticator struct {
 	Audience       string
 	Subjects       []string
 	ClientIDClaims []string
+	StoreIDClaim   string
 
 	JwksURI string
 	JWKs    *keyfunc.JWKS
 
+	// AdditionalIssuers are other identity providers whose tokens are accepted. Their keys are
+	// fetched and refreshed independently of the main issuer's.
+	AdditionalIssuers []string
+	// AdditionalAudiences are the audiences of the AdditionalIssuers, paired by position.
+	AdditionalAudiences []string
+
+	issuerJWKs map[string]*keyfunc.JWKS
 	httpClient *http.Client
 }
 
+type RemoteOidcAuthenticatorOption func(*RemoteOidcAuthenticator)
+
+// WithAdditionalIssuers accepts tokens signed by other issuers than the main one, for example to
+// serve both a workforce and a machine identity provider. The tokens of issuers[i] must have the
+// audience audiences[i], so that a token meant for another service of one issuer is not accepted.
+func WithAdditionalIssuers(issuers []string, audiences []string) RemoteOidcAuthenticatorOption {
+	return func(oidc *RemoteOidcAuthenticator) {
+		oidc.AdditionalIssuers = issuers
+		oidc.AdditionalAudiences = audiences
+	}
+}
+
 var (
 	jwkRefreshInterval = 48 * time.Hour
+	// jwkRefreshRateLimit bounds how often the keys are refetched when a token is signed with an unknown key,
+	// which happens when the issuer rotates its keys.
+	jwkRefreshRateLimit = 5 * time.Minute
 
 	errInvalidClaims = status.Error(codes.Code(openfgav1.AuthErrorCode_invalid_claims), "invalid claims")
 	fetchJWKs        = fetchJWK
@@ -47,7 +70,7 @@ var (
 var _ authn.Authenticator = (*RemoteOidcAuthenticator)(nil)
 var _ authn.OIDCAuthenticator = (*RemoteOidcAuthenticator)(nil)
 
-func NewRemoteOidcAuthenticator(mainIssuer string, issuerAliases []string, audience string, subjects []string, clientIDClaims []string) (*RemoteOidcAuthenticator, error) {
+func NewRemoteOidcAuthenticator(mainIssuer string, issuerAliases []string, audience string, subjects []string, clientIDClaims []string, storeIDClaim string, opts ...RemoteOidcAuthenticatorOption) (*RemoteOidcAuthenticator, error) {
 	client := retryablehttp.NewClient()
 	client.Logger = nil
 	oidc := &RemoteOidcAuthenticator{
@@ -57,6 +80,16 @@ func NewRemoteOidcAuthenticator(mainIssuer string, issuerAliases []string, audie
 		Subjects:       subjects,
 		httpClient:     client.StandardClient(),
 		ClientIDClaims: clientIDClaims,
+		StoreIDClaim:   storeIDClaim,
+		issuerJWKs:     map[string]*keyfunc.JWKS{},
+	}
+
+	for _, opt := range opts {
+		opt(oidc)
+	}
+
+	if len(oidc.AdditionalIssuers) != len(oidc.AdditionalAudiences) {
+		return nil, fmt.Errorf("got %d additional issuers and %d additional audiences, each additional issuer requires its audience", len(oidc.AdditionalIssuers), len(oidc.AdditionalAudiences))
 	}
 
 	// Client ID is:
@@ -85,12 +118,9 @@ func (oidc *RemoteOidcAuthenticator) Authenticate(requestContext context.Context
 		jwt.WithValidMethods([]string{"RS256"}),
 		jwt.WithIssuedAt(),
 		jwt.WithExpirationRequired(),
-		jwt.WithAudience(oidc.Audience),
 	)
 
-	token, err := jwtParser.Parse(authHeader, func(token *jwt.Token) (any, error) {
-		return oidc.JWKs.Keyfunc(token)
-	})
+	token, err := jwtParser.Parse(authHeader, oidc.keyfunc)
 	if err != nil || !token.Valid {
 		return nil, errInvalidClaims
 	}
@@ -100,18 +130,12 @@ func (oidc *RemoteOidcAuthenticator) Authenticate(requestContext context.Context
 		return nil, errInvalidClaims
 	}
 
-	validIssuers := []string{
-		oidc.MainIssuer,
+	audience, ok := oidc.audienceOf(claims)
+	if !ok {
+		return nil, errInvalidClaims
 	}
-	validIssuers = append(validIssuers, oidc.IssuerAliases...)
-
-	ok = slices.ContainsFunc(validIssuers, func(issuer string) bool {
-		v := jwt.NewValidator(jwt.WithIssuer(issuer))
-		err := v.Validate(claims)
-		return err == nil
-	})
 
-	if !ok {
+	if err := jwt.NewValidator(jwt.WithAudience(audience)).Validate(claims); err != nil {
 		return nil, errInvalidClaims
 	}
 
@@ -158,9 +182,73 @@ func (oidc *RemoteOidcAuthenticator) Authenticate(requestContext context.Context
 		}
 	}
 
+	// When configured, the token is restricted to the stores listed in the store id claim,
+	// and tokens without it are rejected.
+	if oidc.StoreIDClaim != "" {
+		storeIDs, ok := storeIDsFromClaim(claims[oidc.StoreIDClaim])
+		if !ok {
+			return nil, errInvalidClaims
+		}
+		principal.StoreIDs = storeIDs
+	}
+
 	return principal, nil
 }
 
+// storeIDsFromClaim returns the store ids held by a claim, which is either a single
+// string or an array of strings.
+func storeIDsFromClaim(claim any) ([]string, bool) {
+	switch v := claim.(type) {
+	case string:
+		if v == "" {
+			return nil, false
+		}
+		return []string{v}, true
+	case []any:
+		storeIDs := make([]string, 0, len(v))
+		for _, item := range v {
+			storeID, ok := item.(string)
+			if !ok || storeID == "" {
+				return nil, false
+			}
+			storeIDs = append(storeIDs, storeID)
+		}
+		return storeIDs, len(storeIDs) > 0
+	default:
+		return nil, false
+	}
+}
+
+// audienceOf returns the audience of the issuer of claims: the main audience for the main issuer and its aliases, or
+// the audience paired with an additional issuer. It returns false if the issuer is not a valid one.
+func (oidc *RemoteOidcAuthenticator) audienceOf(claims jwt.MapClaims) (string, bool) {
+	isIssuer := func(issuer string) bool {
+		v := jwt.NewValidator(jwt.WithIssuer(issuer))
+		err := v.Validate(claims)
+		return err == nil
+	}
+
+	if isIssuer(oidc.MainIssuer) || slices.ContainsFunc(oidc.IssuerAliases, isIssuer) {
+		return oidc.Audience, true
+	}
+
+	if i := slices.IndexFunc(oidc.AdditionalIssuers, isIssuer); i >= 0 {
+		return oidc.AdditionalAudiences[i], true
+	}
+
+	return "", false
+}
+
+// keyfunc returns the key that signed the token, looked up in the keys of the issuer of the token.
+func (oidc *RemoteOidcAuthenticator) keyfunc(token *jwt.Token) (any, error) {
+	if issuer, err := token.Claims.GetIssuer(); err == nil {
+		if jwks, ok := oidc.issuerJWKs[issuer]; ok {
+			return jwks.Keyfunc(token)
+		}
+	}
+	return oidc.JWKs.Keyfunc(token)
+}
+
 func fetchJWK(oidc *RemoteOidcAuthenticator) error {
 	oidcConfig, err := oidc.GetConfiguration()
 	if err != nil {
@@ -175,22 +263,48 @@ func fetchJWK(oidc *RemoteOidcAuthenticator) error {
 
 	oidc.JWKs = jwks
 
+	for _, issuer := range oidc.AdditionalIssuers {
+		oidcConfig, err := oidc.getConfiguration(issuer)
+		if err != nil {
+			return fmt.Errorf("error fetching OIDC configuration of issuer %s: %w", issuer, err)
+		}
+
+		jwks, err := oidc.getKeys(oidcConfig.JWKsURI)
+		if err != nil {
+			return fmt.Errorf("error fetching OIDC keys of issuer %s: %w", issuer, err)
+		}
+
+		oidc.issuerJWKs[issuer] = jwks
+	}
+
 	return nil
 }
 
 func (oidc *RemoteOidcAuthenticator) GetKeys() (*keyfunc.JWKS, error) {
-	jwks, err := keyfunc.Get(oidc.JwksURI, keyfunc.Options{
-		Client:          oidc.httpClient,
-		RefreshInterval: jwkRefreshInterval,
+	return oidc.getKeys(oidc.JwksURI)
+}
+
+// getKeys fetches the keys at jwksURI. The keys are refreshed in the background, and refetched when a token
+// is signed with an unknown key so that key rotations are picked up without a restart.
+func (oidc *RemoteOidcAuthenticator) getKeys(jwksURI string) (*keyfunc.JWKS, error) {
+	jwks, err := keyfunc.Get(jwksURI, keyfunc.Options{
+		Client:            oidc.httpClient,
+		RefreshInterval:   jwkRefreshInterval,
+		RefreshRateLimit:  jwkRefreshRateLimit,
+		RefreshUnknownKID: true,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error fetching keys from %v: %w", oidc.JwksURI, err)
+		return nil, fmt.Errorf("error fetching keys from %v: %w", jwksURI, err)
 	}
 	return jwks, nil
 }
 
 func (oidc *RemoteOidcAuthenticator) GetConfiguration() (*authn.OidcConfig, error) {
-	wellKnown := strings.TrimSuffix(oidc.MainIssuer, "/") + "/.well-known/openid-configuration"
+	return oidc.getConfiguration(oidc.MainIssuer)
+}
+
+func (oidc *RemoteOidcAuthenticator) getConfiguration(issuer string) (*authn.OidcConfig, error) {
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
 	req, err := http.NewRequest("GET", wellKnown, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error forming request to get OIDC: %w", err)
@@ -228,4 +342,7 @@ func (oidc *RemoteOidcAuthenticator) GetConfiguration() (*authn.OidcConfig, erro
 
 func (oidc *RemoteOidcAuthenticator) Close() {
 	oidc.JWKs.EndBackground()
+	for _, jwks := range oidc.issuerJWKs {
+		jwks.EndBackground()
+	}
 }
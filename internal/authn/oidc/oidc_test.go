@@ -446,6 +446,7 @@ type Config struct {
 	issuerAliases      []string
 	subjects           []string
 	clientIDClaims     []string
+	storeIDClaim       string
 	jwtClaims          jwt.MapClaims
 	privateKeyOverride *rsa.PrivateKey
 }
@@ -461,7 +462,7 @@ func quickConfigSetup(c Config) (*RemoteOidcAuthenticator, context.Context, Conf
 	fetchJWKs = fetchKeysMock(publicKey, c.jwkKid)
 
 	// Initialize RemoteOidcAuthenticator
-	oidc, err := NewRemoteOidcAuthenticator(c.issuerURL, c.issuerAliases, c.audience, c.subjects, c.clientIDClaims)
+	oidc, err := NewRemoteOidcAuthenticator(c.issuerURL, c.issuerAliases, c.audience, c.subjects, c.clientIDClaims, c.storeIDClaim)
 	if err != nil {
 		return nil, nil, c, err
 	}
@@ -516,3 +517,130 @@ func generateJWT(privateKey *rsa.PrivateKey, kid string, claims jwt.MapClaims) s
 	}
 	return signedToken
 }
+
+func TestRemoteOidcAuthenticator_StoreIDClaim(t *testing.T) {
+	newConfig := func(storeIDClaim any) Config {
+		claims := jwt.MapClaims{
+			"iss": "right_issuer",
+			"aud": "right_audience",
+			"sub": "openfga client",
+			"exp": time.Now().Add(10 * time.Minute).Unix(),
+		}
+		if storeIDClaim != nil {
+			claims["fga_store_id"] = storeIDClaim
+		}
+		return Config{
+			jwkKid:       "kid_1",
+			jwtKid:       "kid_1",
+			issuerURL:    "right_issuer",
+			audience:     "right_audience",
+			storeIDClaim: "fga_store_id",
+			jwtClaims:    claims,
+		}
+	}
+
+	t.Run("single_store_id", func(t *testing.T) {
+		oidc, requestContext, _, err := quickConfigSetup(newConfig("01JA7QGP1A6FQ7M0T0CPWHNZ1J"))
+		require.NoError(t, err)
+
+		authClaims, err := oidc.Authenticate(requestContext)
+		require.NoError(t, err)
+		require.Equal(t, []string{"01JA7QGP1A6FQ7M0T0CPWHNZ1J"}, authClaims.StoreIDs)
+	})
+
+	t.Run("multiple_store_ids", func(t *testing.T) {
+		oidc, requestContext, _, err := quickConfigSetup(newConfig([]string{"01JA7QGP1A6FQ7M0T0CPWHNZ1J", "01JA7QGP1A6FQ7M0T0CPWHNZ1K"}))
+		require.NoError(t, err)
+
+		authClaims, err := oidc.Authenticate(requestContext)
+		require.NoError(t, err)
+		require.Equal(t, []string{"01JA7QGP1A6FQ7M0T0CPWHNZ1J", "01JA7QGP1A6FQ7M0T0CPWHNZ1K"}, authClaims.StoreIDs)
+	})
+
+	t.Run("missing_claim_is_rejected", func(t *testing.T) {
+		oidc, requestContext, _, err := quickConfigSetup(newConfig(nil))
+		require.NoError(t, err)
+
+		_, err = oidc.Authenticate(requestContext)
+		require.ErrorContains(t, err, "invalid claims")
+	})
+
+	t.Run("invalid_claim_is_rejected", func(t *testing.T) {
+		oidc, requestContext, _, err := quickConfigSetup(newConfig(42))
+		require.NoError(t, err)
+
+		_, err = oidc.Authenticate(requestContext)
+		require.ErrorContains(t, err, "invalid claims")
+	})
+}
+
+func TestRemoteOidcAuthenticator_AdditionalIssuers(t *testing.T) {
+	workforceKey, workforcePublicKey := generateJWTSignatureKeys()
+	machineKey, machinePublicKey := generateJWTSignatureKeys()
+
+	fetchWorkforceKeys := fetchKeysMock(workforcePublicKey, "kid_1")
+	fetchJWKs = func(oidc *RemoteOidcAuthenticator) error {
+		if err := fetchWorkforceKeys(oidc); err != nil {
+			return err
+		}
+		oidc.issuerJWKs["machine_issuer"] = keyfunc.NewGiven(map[string]keyfunc.GivenKey{
+			"kid_1": keyfunc.NewGivenCustom(machinePublicKey, keyfunc.GivenKeyOptions{Algorithm: "RS256"}),
+		})
+		return nil
+	}
+
+	oidc, err := NewRemoteOidcAuthenticator("workforce_issuer", nil, "workforce_audience", nil, nil, "",
+		WithAdditionalIssuers([]string{"machine_issuer"}, []string{"machine_audience"}),
+	)
+	require.NoError(t, err)
+
+	claims := func(issuer, audience string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": issuer,
+			"aud": audience,
+			"sub": "openfga client",
+			"exp": time.Now().Add(10 * time.Minute).Unix(),
+		}
+	}
+
+	t.Run("main_issuer", func(t *testing.T) {
+		token := generateJWT(workforceKey, "kid_1", claims("workforce_issuer", "workforce_audience"))
+		_, err := oidc.Authenticate(generateContext(token))
+		require.NoError(t, err)
+	})
+
+	t.Run("additional_issuer_and_audience", func(t *testing.T) {
+		token := generateJWT(machineKey, "kid_1", claims("machine_issuer", "machine_audience"))
+		_, err := oidc.Authenticate(generateContext(token))
+		require.NoError(t, err)
+	})
+
+	t.Run("keys_of_another_issuer_are_rejected", func(t *testing.T) {
+		token := generateJWT(workforceKey, "kid_1", claims("machine_issuer", "machine_audience"))
+		_, err := oidc.Authenticate(generateContext(token))
+		require.ErrorContains(t, err, "invalid claims")
+	})
+
+	t.Run("unknown_audience_is_rejected", func(t *testing.T) {
+		token := generateJWT(machineKey, "kid_1", claims("machine_issuer", "other_audience"))
+		_, err := oidc.Authenticate(generateContext(token))
+		require.ErrorContains(t, err, "invalid claims")
+	})
+
+	t.Run("audience_of_another_issuer_is_rejected", func(t *testing.T) {
+		token := generateJWT(machineKey, "kid_1", claims("machine_issuer", "workforce_audience"))
+		_, err := oidc.Authenticate(generateContext(token))
+		require.ErrorContains(t, err, "invalid claims")
+
+		token = generateJWT(workforceKey, "kid_1", claims("workforce_issuer", "machine_audience"))
+		_, err = oidc.Authenticate(generateContext(token))
+		require.ErrorContains(t, err, "invalid claims")
+	})
+
+	t.Run("requires_the_audience_of_each_issuer", func(t *testing.T) {
+		_, err := NewRemoteOidcAuthenticator("workforce_issuer", nil, "workforce_audience", nil, nil, "",
+			WithAdditionalIssuers([]string{"machine_issuer", "partner_issuer"}, []string{"machine_audience"}),
+		)
+		require.Error(t, err)
+	})
+}
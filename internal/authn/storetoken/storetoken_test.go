@@ -0,0 +1,89 @@
+package storetoken
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/openfga/openfga/internal/authn"
+)
+
+func contextWithToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestStoreTokenAuthenticator(t *testing.T) {
+	storeID := "01JA7QGP1A6FQ7M0T0CPWHNZ1J"
+
+	authenticator, err := NewStoreTokenAuthenticator([]string{"new-key", "old-key"}, []string{"preshared"})
+	require.NoError(t, err)
+
+	t.Run("valid_token", func(t *testing.T) {
+		token, err := Mint("old-key", storeID, []Scope{ScopeRead, ScopeWrite}, time.Hour)
+		require.NoError(t, err)
+
+		claims, err := authenticator.Authenticate(contextWithToken(token))
+		require.NoError(t, err)
+		require.Equal(t, "store:"+storeID, claims.Subject)
+		require.Equal(t, []string{storeID}, claims.StoreIDs)
+		require.True(t, HasScope(claims, ScopeRead))
+		require.True(t, HasScope(claims, ScopeWrite))
+		require.False(t, HasScope(claims, ScopeAdmin))
+	})
+
+	t.Run("admin_grants_every_scope", func(t *testing.T) {
+		token, err := Mint("new-key", storeID, []Scope{ScopeAdmin}, 0)
+		require.NoError(t, err)
+
+		claims, err := authenticator.Authenticate(contextWithToken(token))
+		require.NoError(t, err)
+		require.True(t, HasScope(claims, ScopeRead))
+		require.True(t, HasScope(claims, ScopeWrite))
+	})
+
+	t.Run("unknown_signing_key", func(t *testing.T) {
+		token, err := Mint("other-key", storeID, []Scope{ScopeRead}, 0)
+		require.NoError(t, err)
+
+		_, err = authenticator.Authenticate(contextWithToken(token))
+		require.ErrorIs(t, err, authn.ErrUnauthenticated)
+	})
+
+	t.Run("expired_token", func(t *testing.T) {
+		token, err := Mint("new-key", storeID, []Scope{ScopeRead}, time.Nanosecond)
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+
+		_, err = authenticator.Authenticate(contextWithToken(token))
+		require.ErrorIs(t, err, authn.ErrUnauthenticated)
+	})
+
+	t.Run("preshared_key_is_unrestricted", func(t *testing.T) {
+		claims, err := authenticator.Authenticate(contextWithToken("preshared"))
+		require.NoError(t, err)
+		require.Empty(t, claims.StoreIDs)
+		require.Nil(t, claims.StoreScopes)
+	})
+
+	t.Run("missing_token", func(t *testing.T) {
+		_, err := authenticator.Authenticate(context.Background())
+		require.ErrorIs(t, err, authn.ErrMissingBearerToken)
+	})
+}
+
+func TestMint(t *testing.T) {
+	_, err := Mint("key", "store", []Scope{"delete"}, 0)
+	require.ErrorIs(t, err, ErrInvalidScope)
+
+	_, err = Mint("key", "store", nil, 0)
+	require.Error(t, err)
+
+	_, err = Mint("key", "", []Scope{ScopeRead}, 0)
+	require.Error(t, err)
+
+	_, err = Mint("", "store", []Scope{ScopeRead}, 0)
+	require.Error(t, err)
+}
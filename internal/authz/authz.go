@@ -149,7 +149,7 @@ func (a *Authorizer) getRelation(apiMethod apimethod.APIMethod) (string, error)
 	switch apiMethod {
 	case apimethod.ReadAuthorizationModel, apimethod.ReadAuthorizationModels:
 		return CanCallReadAuthorizationModels, nil
-	case apimethod.Read:
+	case apimethod.Read, apimethod.StreamedRead:
 		return CanCallRead, nil
 	case apimethod.Write:
 		return CanCallWrite, nil
@@ -163,7 +163,7 @@ func (a *Authorizer) getRelation(apiMethod apimethod.APIMethod) (string, error)
 		return CanCallWriteAssertions, nil
 	case apimethod.ReadAssertions:
 		return CanCallReadAssertions, nil
-	case apimethod.WriteAuthorizationModel:
+	case apimethod.WriteAuthorizationModel, apimethod.DeleteAuthorizationModel:
 		return CanCallWriteAuthorizationModels, nil
 	case apimethod.ListStores:
 		return CanCallListStores, nil
@@ -171,7 +171,7 @@ func (a *Authorizer) getRelation(apiMethod apimethod.APIMethod) (string, error)
 		return CanCallCreateStore, nil
 	case apimethod.GetStore:
 		return CanCallGetStore, nil
-	case apimethod.DeleteStore:
+	case apimethod.DeleteStore, apimethod.UpdateStore:
 		return CanCallDeleteStore, nil
 	case apimethod.Expand:
 		return CanCallExpand, nil
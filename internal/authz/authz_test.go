@@ -36,6 +36,7 @@ func TestGetRelation(t *testing.T) {
 		{method: apimethod.ReadAuthorizationModel, expectedResult: CanCallReadAuthorizationModels},
 		{method: apimethod.ReadAuthorizationModels, expectedResult: CanCallReadAuthorizationModels},
 		{method: apimethod.Read, expectedResult: CanCallRead},
+		{method: apimethod.StreamedRead, expectedResult: CanCallRead},
 		{method: apimethod.Write, expectedResult: CanCallWrite},
 		{method: apimethod.ListObjects, expectedResult: CanCallListObjects},
 		{method: apimethod.StreamedListObjects, expectedResult: CanCallListObjects},
@@ -45,9 +46,11 @@ func TestGetRelation(t *testing.T) {
 		{method: apimethod.WriteAssertions, expectedResult: CanCallWriteAssertions},
 		{method: apimethod.ReadAssertions, expectedResult: CanCallReadAssertions},
 		{method: apimethod.WriteAuthorizationModel, expectedResult: CanCallWriteAuthorizationModels},
+		{method: apimethod.DeleteAuthorizationModel, expectedResult: CanCallWriteAuthorizationModels},
 		{method: apimethod.CreateStore, expectedResult: CanCallCreateStore},
 		{method: apimethod.GetStore, expectedResult: CanCallGetStore},
 		{method: apimethod.DeleteStore, expectedResult: CanCallDeleteStore},
+		{method: apimethod.UpdateStore, expectedResult: CanCallDeleteStore},
 		{method: apimethod.Expand, expectedResult: CanCallExpand},
 		{method: apimethod.ReadChanges, expectedResult: CanCallReadChanges},
 		{method: "Unknown", errorMsg: "unknown API method: Unknown"},
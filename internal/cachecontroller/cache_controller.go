@@ -17,6 +17,7 @@ import (
 
 	"github.com/openfga/openfga/internal/build"
 	"github.com/openfga/openfga/internal/utils"
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/telemetry"
@@ -64,6 +65,14 @@ type CacheController interface {
 	// and if not it will spawn a goroutine to invalidate cached records conditionally
 	// based on timestamp. It may invalidate all cache records, some, or none.
 	InvalidateIfNeeded(storeID string, parentSpan trace.Span)
+
+	// InvalidateStore invalidates the records cached for the specified store up to now, e.g. when another server
+	// notifies a write to the store.
+	InvalidateStore(storeID string)
+
+	// InvalidateObjectType invalidates the records cached for the specified store up to now, the tuple iterators
+	// only for the specified object type, e.g. when another server notifies a write of tuples of that type.
+	InvalidateObjectType(storeID, objectType string)
 }
 
 type NoopCacheController struct{}
@@ -75,6 +84,12 @@ func (c *NoopCacheController) DetermineInvalidationTime(_ context.Context, _ str
 func (c *NoopCacheController) InvalidateIfNeeded(_ string, _ trace.Span) {
 }
 
+func (c *NoopCacheController) InvalidateStore(_ string) {
+}
+
+func (c *NoopCacheController) InvalidateObjectType(_, _ string) {
+}
+
 func NewNoopCacheController() CacheController {
 	return &NoopCacheController{}
 }
@@ -90,6 +105,21 @@ func WithLogger(logger logger.Logger) InMemoryCacheControllerOpt {
 	}
 }
 
+// WithClock sets the clock the invalidations are timestamped with.
+func WithClock(clock clock.Clock) InMemoryCacheControllerOpt {
+	return func(inm *InMemoryCacheController) {
+		inm.clock = clock
+	}
+}
+
+// WithIteratorCacheTTLFunc sets a function returning the TTL of the cached iterators, read for each invalidation, so
+// that the TTL can change while the controller runs. It overrides the iteratorCacheTTL of NewCacheController.
+func WithIteratorCacheTTLFunc(ttl func() time.Duration) InMemoryCacheControllerOpt {
+	return func(inm *InMemoryCacheController) {
+		inm.iteratorCacheTTLFunc = ttl
+	}
+}
+
 // InMemoryCacheController will invalidate cache iterator (InMemoryCache) and sub problem cache (CachedCheckResolver) entries
 // that are more recent than the last write for the specified store.
 // Note that the invalidation is done asynchronously, and only after a Check request is received.
@@ -101,9 +131,19 @@ type InMemoryCacheController struct {
 	// ttl for the entry that keeps the last timestamp for a Write for a storeID.
 	ttl                   time.Duration
 	iteratorCacheTTL      time.Duration
+	iteratorCacheTTLFunc  func() time.Duration
 	changelogBuckets      []uint
 	inflightInvalidations sync.Map
 	logger                logger.Logger
+	clock                 clock.Clock
+}
+
+// iteratorTTL returns the TTL of the cached iterators.
+func (c *InMemoryCacheController) iteratorTTL() time.Duration {
+	if c.iteratorCacheTTLFunc != nil {
+		return c.iteratorCacheTTLFunc()
+	}
+	return c.iteratorCacheTTL
 }
 
 func NewCacheController(ds storage.OpenFGADatastore, cache storage.InMemoryCache[any], ttl time.Duration, iteratorCacheTTL time.Duration, opts ...InMemoryCacheControllerOpt) CacheController {
@@ -115,6 +155,7 @@ func NewCacheController(ds storage.OpenFGADatastore, cache storage.InMemoryCache
 		changelogBuckets:      []uint{0, 25, 50, 75, 100},
 		inflightInvalidations: sync.Map{},
 		logger:                logger.NewNoopLogger(),
+		clock:                 clock.New(),
 	}
 
 	for _, opt := range opts {
@@ -239,7 +280,7 @@ func (c *InMemoryCacheController) findChangesAndInvalidateIfNecessary(ctx contex
 		return
 	}
 
-	timestampOfLastIteratorInvalidation := time.Now().Add(-c.iteratorCacheTTL)
+	timestampOfLastIteratorInvalidation := c.clock.Now().Add(-c.iteratorTTL())
 
 	// need to consider there might just be 1 change
 	// iterate from the oldest to most recent to determine if the last change is part of the current batch
@@ -266,7 +307,7 @@ func (c *InMemoryCacheController) findChangesAndInvalidateIfNecessary(ctx contex
 		c.invalidateIteratorCache(storeID)
 	} else {
 		// only a subset of changes are new, revoke the respective ones.
-		lastModified := time.Now()
+		lastModified := c.clock.Now()
 
 		// only increment if we're going to enter the invalidation for loop below
 		if idx >= 0 {
@@ -289,20 +330,43 @@ func (c *InMemoryCacheController) findChangesAndInvalidateIfNecessary(ctx contex
 	findChangesAndInvalidateHistogram.WithLabelValues("true", utils.Bucketize(uint(len(changes)), c.changelogBuckets)).Observe(float64(time.Since(start).Milliseconds()))
 }
 
+// InvalidateStore invalidates the records cached for the specified store up to now, without waiting for
+// the changelog entry of the store to expire.
+func (c *InMemoryCacheController) InvalidateStore(storeID string) {
+	c.logger.Debug("InMemoryCacheController InvalidateStore", zap.String("store_id", storeID))
+
+	cacheInvalidationCounter.Inc()
+	c.cache.Set(storage.GetChangelogCacheKey(storeID), &storage.ChangelogCacheEntry{LastModified: c.clock.Now()}, c.ttl)
+	c.invalidateIteratorCache(storeID)
+	invalidateCheckCacheEntries(storeID)
+}
+
+// InvalidateObjectType invalidates the records cached for the specified store up to now, like InvalidateStore,
+// except for the tuple iterators of the other object types. The check results may depend on tuples of any type, so
+// they are all invalidated.
+func (c *InMemoryCacheController) InvalidateObjectType(storeID, objectType string) {
+	c.logger.Debug("InMemoryCacheController InvalidateObjectType", zap.String("store_id", storeID), zap.String("object_type", objectType))
+
+	cacheInvalidationCounter.Inc()
+	c.cache.Set(storage.GetChangelogCacheKey(storeID), &storage.ChangelogCacheEntry{LastModified: c.clock.Now()}, c.ttl)
+	c.cache.Set(storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, objectType), &storage.InvalidEntityCacheEntry{LastModified: c.clock.Now()}, c.iteratorTTL())
+	invalidateCheckCacheEntries(storeID)
+}
+
 // invalidateIteratorCache writes a new key to the cache with a very long TTL.
 // An alternative implementation could delete invalid keys, but this approach is faster (see storagewrappers.findInCache).
 func (c *InMemoryCacheController) invalidateIteratorCache(storeID string) {
-	c.cache.Set(storage.GetInvalidIteratorCacheKey(storeID), &storage.InvalidEntityCacheEntry{LastModified: time.Now()}, math.MaxInt)
+	c.cache.Set(storage.GetInvalidIteratorCacheKey(storeID), &storage.InvalidEntityCacheEntry{LastModified: c.clock.Now()}, math.MaxInt)
 }
 
 // invalidateIteratorCacheByObjectRelation writes a new key to the cache.
 // An alternative implementation could delete invalid keys, but this approach is faster (see storagewrappers.findInCache).
 func (c *InMemoryCacheController) invalidateIteratorCacheByObjectRelation(storeID, object, relation string, ts time.Time) {
-	c.cache.Set(storage.GetInvalidIteratorByObjectRelationCacheKey(storeID, object, relation), &storage.InvalidEntityCacheEntry{LastModified: ts}, c.iteratorCacheTTL)
+	c.cache.Set(storage.GetInvalidIteratorByObjectRelationCacheKey(storeID, object, relation), &storage.InvalidEntityCacheEntry{LastModified: ts}, c.iteratorTTL())
 }
 
 // invalidateIteratorCacheByUserAndObjectType writes a new key to the cache.
 // An alternative implementation could delete invalid keys, but this approach is faster (see storagewrappers.findInCache).
 func (c *InMemoryCacheController) invalidateIteratorCacheByUserAndObjectType(storeID, user, objectType string, ts time.Time) {
-	c.cache.Set(storage.GetInvalidIteratorByUserObjectTypeCacheKeys(storeID, []string{user}, objectType)[0], &storage.InvalidEntityCacheEntry{LastModified: ts}, c.iteratorCacheTTL)
+	c.cache.Set(storage.GetInvalidIteratorByUserObjectTypeCacheKeys(storeID, []string{user}, objectType)[0], &storage.InvalidEntityCacheEntry{LastModified: ts}, c.iteratorTTL())
 }
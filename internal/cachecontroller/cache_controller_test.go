@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"go.uber.org/mock/gomock"
@@ -14,6 +15,7 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/storage"
 )
@@ -75,6 +77,76 @@ func TestInMemoryCacheController_DetermineInvalidationTime(t *testing.T) {
 	})
 }
 
+func TestInMemoryCacheController_InvalidateStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mocks.NewMockInMemoryCache[any](ctrl)
+	ds := mocks.NewMockOpenFGADatastore(ctrl)
+
+	cacheController := NewCacheController(ds, cache, 10*time.Second, 10*time.Second)
+	storeID := "id"
+
+	before := time.Now()
+	cache.EXPECT().Set(storage.GetChangelogCacheKey(storeID), gomock.Any(), 10*time.Second).
+		Do(func(_ string, entry any, _ time.Duration) {
+			require.False(t, entry.(*storage.ChangelogCacheEntry).LastModified.Before(before))
+		})
+	cache.EXPECT().Set(storage.GetInvalidIteratorCacheKey(storeID), gomock.Any(), gomock.Any())
+
+	cacheController.InvalidateStore(storeID)
+}
+
+func TestInMemoryCacheController_InvalidateObjectType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mocks.NewMockInMemoryCache[any](ctrl)
+	ds := mocks.NewMockOpenFGADatastore(ctrl)
+
+	cacheController := NewCacheController(ds, cache, 10*time.Second, 20*time.Second)
+	storeID := "id"
+
+	cache.EXPECT().Set(storage.GetChangelogCacheKey(storeID), gomock.Any(), 10*time.Second)
+	cache.EXPECT().Set(storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, "document"), gomock.Any(), 20*time.Second)
+
+	cacheController.InvalidateObjectType(storeID, "document")
+}
+
+func TestInMemoryCacheController_InvalidateStoreCheckCacheEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mocks.NewMockInMemoryCache[any](ctrl)
+	cache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	cacheController := NewCacheController(mocks.NewMockOpenFGADatastore(ctrl), cache, 10*time.Second, 20*time.Second)
+
+	storeID, bucket := "01JCHECKCACHEENTRIES", "test"
+	gauge := func() float64 {
+		return testutil.ToFloat64(checkCacheEntryGauge.WithLabelValues(bucket))
+	}
+
+	first := TrackCheckCacheEntry(storeID, bucket)
+	second := TrackCheckCacheEntry(storeID, bucket)
+	require.InDelta(t, 2, gauge(), 0)
+
+	first.Release()
+	require.InDelta(t, 1, gauge(), 0)
+
+	// the entries cached before the invalidation are uncounted, once
+	cacheController.InvalidateStore(storeID)
+	require.InDelta(t, 0, gauge(), 0)
+	second.Release()
+	require.InDelta(t, 0, gauge(), 0)
+
+	third := TrackCheckCacheEntry(storeID, bucket)
+	require.InDelta(t, 1, gauge(), 0)
+	cacheController.InvalidateObjectType(storeID, "document")
+	require.InDelta(t, 0, gauge(), 0)
+	third.Release()
+	require.InDelta(t, 0, gauge(), 0)
+}
+
 func generateChanges(object, relation, user string, count int) []*openfgav1.TupleChange {
 	changes := make([]*openfgav1.TupleChange, 0, count)
 	for i := 0; i < count; i++ {
@@ -351,6 +423,7 @@ func TestInMemoryCacheController_findChangesAndInvalidateIfNecessary(t *testing.
 				changelogBuckets:      []uint{0, 25, 50, 75, 100},
 				inflightInvalidations: sync.Map{},
 				logger:                logger.NewNoopLogger(),
+				clock:                 clock.New(),
 			}
 			cacheController.findChangesAndInvalidateIfNecessary(context.Background(), test.storeID, span)
 		})
@@ -0,0 +1,86 @@
+package cachecontroller
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openfga/openfga/internal/build"
+)
+
+var (
+	checkCacheEntryGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "check_cache_entry_count",
+		Help:      "The number of cached ResolveCheck responses that were not invalidated by a write to their store, partitioned by the bucket the store is hashed into.",
+	}, []string{"store_bucket"})
+
+	// checkCacheEntries are the counts of the cached check responses of the stores, by store id.
+	checkCacheEntries sync.Map
+)
+
+// CheckCacheEntries counts the cached check responses of a store in the check_cache_entry_count gauge, until the store
+// is invalidated by InvalidateStore or InvalidateObjectType, its responses being then discarded on their next hit.
+type CheckCacheEntries struct {
+	storeID string
+	bucket  string
+
+	mu       sync.Mutex
+	count    int64 // GUARDED_BY(mu).
+	detached bool  // GUARDED_BY(mu).
+}
+
+// TrackCheckCacheEntry counts a new cached check response of the store, labelled with the bucket of the store. The
+// returned counts must be released once the response is removed from the cache or replaced.
+func TrackCheckCacheEntry(storeID, bucket string) *CheckCacheEntries {
+	for {
+		value, _ := checkCacheEntries.LoadOrStore(storeID, &CheckCacheEntries{storeID: storeID, bucket: bucket})
+		entries := value.(*CheckCacheEntries)
+
+		entries.mu.Lock()
+		if entries.detached {
+			// the store was invalidated or its responses all released since it was loaded
+			entries.mu.Unlock()
+			checkCacheEntries.CompareAndDelete(storeID, entries)
+			continue
+		}
+		entries.count++
+		checkCacheEntryGauge.WithLabelValues(bucket).Inc()
+		entries.mu.Unlock()
+		return entries
+	}
+}
+
+// Release uncounts a cached check response, unless its store was invalidated since it was cached.
+func (e *CheckCacheEntries) Release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.detached {
+		return
+	}
+
+	e.count--
+	checkCacheEntryGauge.WithLabelValues(e.bucket).Dec()
+	if e.count == 0 {
+		e.detached = true
+		checkCacheEntries.CompareAndDelete(e.storeID, e)
+	}
+}
+
+// invalidateCheckCacheEntries uncounts all the cached check responses of the store.
+func invalidateCheckCacheEntries(storeID string) {
+	value, ok := checkCacheEntries.LoadAndDelete(storeID)
+	if !ok {
+		return
+	}
+	entries := value.(*CheckCacheEntries)
+
+	entries.mu.Lock()
+	defer entries.mu.Unlock()
+	if entries.detached {
+		return
+	}
+	entries.detached = true
+	checkCacheEntryGauge.WithLabelValues(entries.bucket).Sub(float64(entries.count))
+}
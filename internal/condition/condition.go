@@ -59,6 +59,9 @@ type EvaluableCondition struct {
 	celEnv         *cel.Env
 	celProgram     cel.Program
 	compileOnce    sync.Once
+
+	programCache *ProgramCache
+	modelID      string
 }
 
 // Compile compiles a condition expression with a CEL environment
@@ -68,10 +71,22 @@ func (e *EvaluableCondition) Compile() error {
 	var compileErr error
 
 	e.compileOnce.Do(func() {
+		if e.programCache != nil {
+			if cached, ok := e.programCache.get(e.modelID, e.Name); ok {
+				e.celEnv = cached.env
+				e.celProgram = cached.program
+				return
+			}
+		}
+
 		if err := e.compile(); err != nil {
 			compileErr = err
 			return
 		}
+
+		if e.programCache != nil {
+			e.programCache.set(e.modelID, e.Name, &compiledProgram{env: e.celEnv, program: e.celProgram})
+		}
 	})
 
 	return compileErr
@@ -315,6 +330,16 @@ func (e *EvaluableCondition) WithInterruptCheckFrequency(checkFrequency uint) *E
 	return e
 }
 
+// WithProgramCache makes the EvaluableCondition reuse the program compiled for the condition of the same name of
+// the model modelID, if the cache holds it, and store it in the cache otherwise. It returns the mutated
+// EvaluableCondition, and must be called before Compile.
+func (e *EvaluableCondition) WithProgramCache(cache *ProgramCache, modelID string) *EvaluableCondition {
+	e.programCache = cache
+	e.modelID = modelID
+
+	return e
+}
+
 // NewUncompiled returns a new EvaluableCondition that has not
 // validated and compiled its expression.
 func NewUncompiled(condition *openfgav1.Condition) *EvaluableCondition {
@@ -15,8 +15,8 @@ import (
 
 	"github.com/openfga/openfga/internal/condition"
 	"github.com/openfga/openfga/internal/condition/metrics"
+	"github.com/openfga/openfga/pkg/redact"
 	"github.com/openfga/openfga/pkg/telemetry"
-	"github.com/openfga/openfga/pkg/tuple"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
@@ -40,7 +40,7 @@ func EvaluateTupleCondition(
 	}
 
 	ctx, span := tracer.Start(ctx, "EvaluateTupleCondition", trace.WithAttributes(
-		attribute.String("tuple_key", tuple.TupleKeyWithConditionToString(tupleKey)),
+		attribute.String("tuple_key", redact.TupleKey(tupleKey)),
 		attribute.String("condition_name", conditionName)))
 	defer span.End()
 
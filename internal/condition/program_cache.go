@@ -0,0 +1,80 @@
+package condition
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+type programCacheContextKey struct{}
+
+// ProgramCache holds the compiled CEL programs of the conditions of authorization models, keyed by model id and
+// condition name, so that the conditions of a model are compiled once rather than every time a typesystem is built
+// for it. Models are immutable, so an entry never needs to be refreshed, only evicted once the model is unused.
+//
+// The conditions sharing a cache must be built with the same program options, since the options are not part of
+// the key.
+type ProgramCache struct {
+	mu       sync.RWMutex
+	programs map[string]map[string]*compiledProgram // GUARDED_BY(mu)
+}
+
+type compiledProgram struct {
+	env     *cel.Env
+	program cel.Program
+}
+
+// NewProgramCache returns an empty ProgramCache.
+func NewProgramCache() *ProgramCache {
+	return &ProgramCache{programs: map[string]map[string]*compiledProgram{}}
+}
+
+func (c *ProgramCache) get(modelID, name string) (*compiledProgram, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	program, ok := c.programs[modelID][name]
+	return program, ok
+}
+
+func (c *ProgramCache) set(modelID, name string, program *compiledProgram) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.programs[modelID]; !ok {
+		c.programs[modelID] = map[string]*compiledProgram{}
+	}
+	c.programs[modelID][name] = program
+}
+
+// Evict removes the programs of the conditions of the model.
+func (c *ProgramCache) Evict(modelID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.programs, modelID)
+}
+
+// Len returns the number of programs in the cache.
+func (c *ProgramCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var n int
+	for _, programs := range c.programs {
+		n += len(programs)
+	}
+	return n
+}
+
+// ContextWithProgramCache returns a copy of ctx carrying the cache.
+func ContextWithProgramCache(ctx context.Context, cache *ProgramCache) context.Context {
+	return context.WithValue(ctx, programCacheContextKey{}, cache)
+}
+
+// ProgramCacheFromContext returns the cache carried by ctx, if any.
+func ProgramCacheFromContext(ctx context.Context) (*ProgramCache, bool) {
+	cache, ok := ctx.Value(programCacheContextKey{}).(*ProgramCache)
+	return cache, ok && cache != nil
+}
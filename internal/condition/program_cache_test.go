@@ -0,0 +1,62 @@
+package condition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/condition"
+)
+
+func TestProgramCache(t *testing.T) {
+	const modelID = "01JBVSMFRTRQ0FCXN1TC9VZ9G4"
+
+	newCondition := func(expression string) *openfgav1.Condition {
+		return &openfgav1.Condition{
+			Name:       "condition1",
+			Expression: expression,
+			Parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"param1": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING},
+			},
+		}
+	}
+	params := map[string]*structpb.Value{"param1": structpb.NewStringValue("ok")}
+
+	cache := condition.NewProgramCache()
+
+	first := condition.NewUncompiled(newCondition("param1 == 'ok'")).WithProgramCache(cache, modelID)
+	require.NoError(t, first.Compile())
+	require.Equal(t, 1, cache.Len())
+
+	// Models are immutable, so a condition of the same model and name reuses the cached program.
+	second := condition.NewUncompiled(newCondition("param1 != 'ok'")).WithProgramCache(cache, modelID)
+	result, err := second.Evaluate(context.Background(), params)
+	require.NoError(t, err)
+	require.True(t, result.ConditionMet)
+	require.Equal(t, 1, cache.Len())
+
+	other := condition.NewUncompiled(newCondition("param1 != 'ok'")).WithProgramCache(cache, "01JBVSMFRTRQ0FCXN1TC9VZ9G5")
+	result, err = other.Evaluate(context.Background(), params)
+	require.NoError(t, err)
+	require.False(t, result.ConditionMet)
+	require.Equal(t, 2, cache.Len())
+
+	cache.Evict(modelID)
+	require.Equal(t, 1, cache.Len())
+
+	invalid := condition.NewUncompiled(newCondition("param1 +")).WithProgramCache(cache, "01JBVSMFRTRQ0FCXN1TC9VZ9G6")
+	require.Error(t, invalid.Compile())
+	require.Equal(t, 1, cache.Len())
+
+	ctx := condition.ContextWithProgramCache(context.Background(), cache)
+	fromCtx, ok := condition.ProgramCacheFromContext(ctx)
+	require.True(t, ok)
+	require.Same(t, cache, fromCtx)
+
+	_, ok = condition.ProgramCacheFromContext(context.Background())
+	require.False(t, ok)
+}
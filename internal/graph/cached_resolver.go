@@ -3,6 +3,8 @@ package graph
 import (
 	"context"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cespare/xxhash/v2"
@@ -15,15 +17,28 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/cachecontroller"
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/redact"
 	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
 	"github.com/openfga/openfga/pkg/telemetry"
 	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
 )
 
 const (
 	defaultMaxCacheSize = 10000
 	defaultCacheTTL     = 10 * time.Second
+
+	// maxConcurrentRevalidations bounds the number of cache entries being recomputed in the background at once.
+	// Entries that would exceed it are left to expire.
+	maxConcurrentRevalidations = 16
+
+	// checkCacheStoreBuckets is the number of buckets the stores are hashed into to label the cache metrics, so that
+	// the stores hitting the cache unevenly can be told apart without a series per store.
+	checkCacheStoreBuckets = 16
 )
 
 var (
@@ -33,40 +48,96 @@ var (
 		Help:      "The total number of calls to ResolveCheck.",
 	})
 
-	checkCacheHitCounter = promauto.NewCounter(prometheus.CounterOpts{
+	checkCacheHitCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "check_cache_hit_count",
-		Help:      "The total number of cache hits for ResolveCheck.",
-	})
+		Help:      "The total number of cache hits for ResolveCheck, partitioned by the bucket the store is hashed into.",
+	}, []string{"store_bucket"})
 
-	checkCacheInvalidHit = promauto.NewCounter(prometheus.CounterOpts{
+	checkCacheMissCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "check_cache_miss_count",
+		Help:      "The total number of cache misses for ResolveCheck, partitioned by the bucket the store is hashed into.",
+	}, []string{"store_bucket"})
+
+	checkCacheInvalidHit = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "check_cache_invalid_hit_count",
-		Help:      "The total number of cache hits for ResolveCheck that were discarded because they were invalidated.",
-	})
+		Help:      "The total number of cache hits for ResolveCheck that were discarded because they were invalidated by a write to their store, partitioned by the bucket the store is hashed into.",
+	}, []string{"store_bucket"})
+
+	checkCacheEvictionCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "check_cache_eviction_count",
+		Help:      "The total number of cached ResolveCheck responses evicted before expiring because the cache was full, partitioned by the bucket the store is hashed into.",
+	}, []string{"store_bucket"})
+
+	checkCacheRevalidationCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "check_cache_revalidation_count",
+		Help:      "The total number of cached ResolveCheck responses recomputed in the background before expiring, partitioned by whether the recomputation succeeded.",
+	}, []string{"success"})
 )
 
-var _ storage.CacheItem = (*CheckResponseCacheEntry)(nil)
+var _ storage.RemovalObservedCacheItem = (*CheckResponseCacheEntry)(nil)
 
 type CheckResponseCacheEntry struct {
 	LastModified  time.Time
 	CheckResponse *ResolveCheckResponse
+
+	// storeBucket labels the metrics of the entry, see storeBucket.
+	storeBucket string
+	// entries counts the entry in the check_cache_entry_count gauge until it is released.
+	entries atomic.Pointer[cachecontroller.CheckCacheEntries]
+
+	// hits and revalidating are only used when background revalidation is enabled.
+	hits         atomic.Uint32
+	revalidating atomic.Bool
 }
 
 func (c *CheckResponseCacheEntry) CacheEntityType() string {
 	return "check_response"
 }
 
+// CacheItemRemoved see [storage.RemovalObservedCacheItem].CacheItemRemoved.
+func (c *CheckResponseCacheEntry) CacheItemRemoved(reason string) {
+	c.release()
+	if reason == "evicted" {
+		checkCacheEvictionCounter.WithLabelValues(c.storeBucket).Inc()
+	}
+}
+
+// release uncounts the entry, once.
+func (c *CheckResponseCacheEntry) release() {
+	if entries := c.entries.Swap(nil); entries != nil {
+		entries.Release()
+	}
+}
+
+// storeBucket returns the bucket the store is hashed into, labelling the cache metrics.
+func storeBucket(storeID string) string {
+	return strconv.FormatUint(xxhash.Sum64String(storeID)%checkCacheStoreBuckets, 10)
+}
+
 // CachedCheckResolver attempts to resolve check sub-problems via prior computations before
 // delegating the request to some underlying CheckResolver.
 type CachedCheckResolver struct {
 	delegate CheckResolver
 	cache    storage.InMemoryCache[any]
 	cacheTTL time.Duration
-	logger   logger.Logger
+	// cacheTTLFunc, if set, returns the TTL instead of cacheTTL, see WithCacheTTLFunc.
+	cacheTTLFunc func() time.Duration
+	logger       logger.Logger
+	clock        clock.Clock
 	// allocatedCache is used to denote whether the cache is allocated by this struct.
 	// If so, CachedCheckResolver is responsible for cleaning up.
 	allocatedCache bool
+
+	revalidationWindow    time.Duration
+	revalidationMinHits   uint32
+	revalidationDatastore storage.RelationshipTupleReader
+	revalidations         chan struct{}
+	revalidationsWg       sync.WaitGroup
 }
 
 var _ CheckResolver = (*CachedCheckResolver)(nil)
@@ -82,6 +153,14 @@ func WithCacheTTL(ttl time.Duration) CachedCheckResolverOpt {
 	}
 }
 
+// WithCacheTTLFunc sets a function returning the TTL of the cache entries, read each time an entry is cached or
+// validated, so that the TTL can change while the resolver runs. It overrides WithCacheTTL.
+func WithCacheTTLFunc(ttl func() time.Duration) CachedCheckResolverOpt {
+	return func(ccr *CachedCheckResolver) {
+		ccr.cacheTTLFunc = ttl
+	}
+}
+
 // WithExistingCache sets the cache to the specified cache.
 // Note that the original cache will not be stopped as it may still be used by others. It is up to the caller
 // to check whether the original cache should be stopped.
@@ -91,6 +170,26 @@ func WithExistingCache(cache storage.InMemoryCache[any]) CachedCheckResolverOpt
 	}
 }
 
+// WithCacheRevalidation enables stale-while-revalidate: once a cache entry has been hit at least minHits times,
+// a hit during the last window of its TTL recomputes it in the background, so that frequently requested
+// sub-problems are served from the cache instead of being recomputed when they expire. A window of 0 disables it.
+// The entries are recomputed with the datastore, which must outlive the requests, rather than with the datastore
+// of the request which hit them.
+func WithCacheRevalidation(window time.Duration, minHits uint32, datastore storage.RelationshipTupleReader) CachedCheckResolverOpt {
+	return func(ccr *CachedCheckResolver) {
+		ccr.revalidationWindow = window
+		ccr.revalidationMinHits = minHits
+		ccr.revalidationDatastore = datastore
+	}
+}
+
+// WithCacheClock sets the clock the cache entries are timestamped and expired with.
+func WithCacheClock(clock clock.Clock) CachedCheckResolverOpt {
+	return func(ccr *CachedCheckResolver) {
+		ccr.clock = clock
+	}
+}
+
 // WithLogger sets the logger for the cached check resolver.
 func WithLogger(logger logger.Logger) CachedCheckResolverOpt {
 	return func(ccr *CachedCheckResolver) {
@@ -105,8 +204,10 @@ func WithLogger(logger logger.Logger) CachedCheckResolverOpt {
 // NOTE: the ResolveCheck's resolution data will be set as the default values as we actually did no database lookup.
 func NewCachedCheckResolver(opts ...CachedCheckResolverOpt) (*CachedCheckResolver, error) {
 	checker := &CachedCheckResolver{
-		cacheTTL: defaultCacheTTL,
-		logger:   logger.NewNoopLogger(),
+		cacheTTL:      defaultCacheTTL,
+		logger:        logger.NewNoopLogger(),
+		clock:         clock.New(),
+		revalidations: make(chan struct{}, maxConcurrentRevalidations),
 	}
 	checker.delegate = checker
 
@@ -142,7 +243,9 @@ func (c *CachedCheckResolver) GetDelegate() CheckResolver {
 
 // Close will deallocate resource allocated by the CachedCheckResolver
 // It will not deallocate cache if it has been passed in from WithExistingCache.
+// It waits for the background revalidations in progress to complete.
 func (c *CachedCheckResolver) Close() {
+	c.revalidationsWg.Wait()
 	if c.allocatedCache {
 		c.cache.Stop()
 	}
@@ -155,6 +258,7 @@ func (c *CachedCheckResolver) ResolveCheck(
 	span := trace.SpanFromContext(ctx)
 
 	cacheKey := BuildCacheKey(*req)
+	bucket := storeBucket(req.GetStoreID())
 
 	tryCache := req.Consistency != openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY
 
@@ -162,27 +266,33 @@ func (c *CachedCheckResolver) ResolveCheck(
 		checkCacheTotalCounter.Inc()
 		if cachedResp := c.cache.Get(cacheKey); cachedResp != nil {
 			res := cachedResp.(*CheckResponseCacheEntry)
-			isValid := res.LastModified.After(req.LastCacheInvalidationTime)
+			// the entry is also checked against the TTL, in case the clock of the resolver is not the one of the cache
+			isValid := res.LastModified.After(req.LastCacheInvalidationTime) && c.clock.Since(res.LastModified) < c.ttl()
 			c.logger.Debug("CachedCheckResolver found cache key",
 				zap.String("store_id", req.GetStoreID()),
 				zap.String("authorization_model_id", req.GetAuthorizationModelID()),
-				zap.String("tuple_key", req.GetTupleKey().String()),
+				zap.String("tuple_key", redact.TupleKey(req.GetTupleKey())),
 				zap.Bool("isValid", isValid))
 
 			span.SetAttributes(attribute.Bool("cached", isValid))
 			if isValid {
-				checkCacheHitCounter.Inc()
+				checkCacheHitCounter.WithLabelValues(bucket).Inc()
+				if md := req.GetRequestMetadata(); md != nil && md.CheckCacheHits != nil {
+					md.CheckCacheHits.Add(1)
+				}
+				c.maybeRevalidate(ctx, req, cacheKey, res)
 				// return a copy to avoid races across goroutines
 				return res.CheckResponse.clone(), nil
 			}
 
 			// we tried the cache and hit an invalid entry
-			checkCacheInvalidHit.Inc()
+			checkCacheInvalidHit.WithLabelValues(bucket).Inc()
 		} else {
+			checkCacheMissCounter.WithLabelValues(bucket).Inc()
 			c.logger.Debug("CachedCheckResolver not found cache key",
 				zap.String("store_id", req.GetStoreID()),
 				zap.String("authorization_model_id", req.GetAuthorizationModelID()),
-				zap.String("tuple_key", req.GetTupleKey().String()))
+				zap.String("tuple_key", redact.TupleKey(req.GetTupleKey())))
 		}
 	}
 
@@ -201,16 +311,98 @@ func (c *CachedCheckResolver) ResolveCheck(
 		c.logger.Debug("CachedCheckResolver not saving to cache due to cycle",
 			zap.String("store_id", req.GetStoreID()),
 			zap.String("authorization_model_id", req.GetAuthorizationModelID()),
-			zap.String("tuple_key", req.GetTupleKey().String()))
+			zap.String("tuple_key", redact.TupleKey(req.GetTupleKey())))
 		return resp, nil
 	}
 
 	clonedResp := resp.clone()
 
-	c.cache.Set(cacheKey, &CheckResponseCacheEntry{LastModified: time.Now(), CheckResponse: clonedResp}, c.cacheTTL)
+	c.set(cacheKey, req.GetStoreID(), bucket, clonedResp)
 	return resp, nil
 }
 
+// maybeRevalidate recomputes a frequently hit cache entry in the background when it is about to expire.
+func (c *CachedCheckResolver) maybeRevalidate(ctx context.Context, req *ResolveCheckRequest, cacheKey string, entry *CheckResponseCacheEntry) {
+	if c.revalidationWindow <= 0 || c.revalidationDatastore == nil {
+		return
+	}
+
+	if entry.hits.Add(1) < c.revalidationMinHits ||
+		c.clock.Until(entry.LastModified.Add(c.ttl())) > c.revalidationWindow {
+		return
+	}
+
+	typesys, ok := typesystem.TypesystemFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if !entry.revalidating.CompareAndSwap(false, true) {
+		return
+	}
+
+	select {
+	case c.revalidations <- struct{}{}:
+	default:
+		// too many revalidations in progress, let the entry expire
+		entry.revalidating.Store(false)
+		return
+	}
+
+	// the recomputation must not be accounted to, nor canceled with, the request that triggered it
+	revalidationReq := req.clone()
+	revalidationReq.RequestMetadata = NewCheckRequestMetadata()
+	if md := req.GetRequestMetadata(); md != nil {
+		revalidationReq.RequestMetadata.Depth = md.Depth
+		revalidationReq.RequestMetadata.MaxResolutionDepth = md.MaxResolutionDepth
+	}
+	revalidationReq.Consistency = req.GetConsistency()
+
+	// nor can it read with the datastore of the request, which is bounded by and released with the request
+	revalidationCtx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+	revalidationCtx = storage.ContextWithRelationshipTupleReader(revalidationCtx,
+		storagewrappers.NewCombinedTupleReader(c.revalidationDatastore, req.GetContextualTuples()))
+
+	c.revalidationsWg.Add(1)
+	go func() {
+		defer c.revalidationsWg.Done()
+		defer func() { <-c.revalidations }()
+
+		ctx, cancel := context.WithTimeout(revalidationCtx, c.revalidationWindow)
+		defer cancel()
+
+		resp, err := c.delegate.ResolveCheck(ctx, revalidationReq)
+		if err != nil || resp.GetCycleDetected() {
+			checkCacheRevalidationCounter.WithLabelValues("false").Inc()
+			entry.revalidating.Store(false)
+			return
+		}
+
+		checkCacheRevalidationCounter.WithLabelValues("true").Inc()
+		c.set(cacheKey, req.GetStoreID(), entry.storeBucket, resp.clone())
+	}()
+}
+
+// set caches the response of the store under the key.
+func (c *CachedCheckResolver) set(cacheKey, storeID, bucket string, resp *ResolveCheckResponse) {
+	// the entry replaced under the key, e.g. an invalidated or revalidated one, is not notified of its removal
+	if previous, ok := c.cache.Get(cacheKey).(*CheckResponseCacheEntry); ok {
+		previous.release()
+	}
+
+	entry := &CheckResponseCacheEntry{LastModified: c.clock.Now(), CheckResponse: resp, storeBucket: bucket}
+	entry.entries.Store(cachecontroller.TrackCheckCacheEntry(storeID, bucket))
+	c.cache.Set(cacheKey, entry, c.ttl())
+}
+
+// ttl returns the TTL of the cache entries.
+func (c *CachedCheckResolver) ttl() time.Duration {
+	if c.cacheTTLFunc != nil {
+		return c.cacheTTLFunc()
+	}
+	return c.cacheTTL
+}
+
 func BuildCacheKey(req ResolveCheckRequest) string {
 	tup := tuple.From(req.GetTupleKey())
 	cacheKeyString := tup.String() + req.GetInvariantCacheKey()
@@ -4,16 +4,25 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"go.uber.org/mock/gomock"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/pkg/clock"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	"github.com/openfga/openfga/pkg/testutils"
 	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
 )
 
 func TestResolveCheckFromCache(t *testing.T) {
@@ -574,6 +583,214 @@ func TestResolveCheckExpired(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestResolveCheckExpiredWithClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	req := &ResolveCheckRequest{
+		StoreID:              "12",
+		AuthorizationModelID: "33",
+		TupleKey:             tuple.NewTupleKey("document:abc", "reader", "user:XYZ"),
+		RequestMetadata:      NewCheckRequestMetadata(),
+	}
+
+	result := &ResolveCheckResponse{Allowed: true}
+	mockResolver := NewMockCheckResolver(ctrl)
+
+	fakeClock := clock.NewFake(time.Now())
+	dut, err := NewCachedCheckResolver(WithCacheTTL(time.Minute), WithCacheClock(fakeClock))
+	require.NoError(t, err)
+	defer dut.Close()
+
+	dut.SetDelegate(mockResolver)
+
+	mockResolver.EXPECT().ResolveCheck(gomock.Any(), req).Times(1).Return(result, nil)
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+
+	// before the TTL elapsed, the check is served from the cache
+	fakeClock.Advance(59 * time.Second)
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+
+	// once the TTL elapsed, the check is resolved again
+	mockResolver.EXPECT().ResolveCheck(gomock.Any(), req).Times(1).Return(result, nil)
+	fakeClock.Advance(time.Second)
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+}
+
+func TestResolveCheckTTLFunc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	req := &ResolveCheckRequest{
+		StoreID:              "12",
+		AuthorizationModelID: "33",
+		TupleKey:             tuple.NewTupleKey("document:abc", "reader", "user:XYZ"),
+		RequestMetadata:      NewCheckRequestMetadata(),
+	}
+
+	result := &ResolveCheckResponse{Allowed: true}
+	mockResolver := NewMockCheckResolver(ctrl)
+
+	var ttl atomic.Int64
+	ttl.Store(int64(time.Hour))
+	fakeClock := clock.NewFake(time.Now())
+	dut, err := NewCachedCheckResolver(WithCacheTTLFunc(func() time.Duration { return time.Duration(ttl.Load()) }), WithCacheClock(fakeClock))
+	require.NoError(t, err)
+	defer dut.Close()
+
+	dut.SetDelegate(mockResolver)
+
+	mockResolver.EXPECT().ResolveCheck(gomock.Any(), req).Times(1).Return(result, nil)
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+
+	fakeClock.Advance(30 * time.Second)
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+
+	// the entries already cached expire with the new TTL
+	ttl.Store(int64(10 * time.Second))
+	mockResolver.EXPECT().ResolveCheck(gomock.Any(), req).Times(1).Return(result, nil)
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+}
+
+func TestResolveCheckRevalidation(t *testing.T) {
+	ts, err := typesystem.New(testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define reader: [user]`))
+	require.NoError(t, err)
+
+	requestDatastore := memory.New()
+	t.Cleanup(requestDatastore.Close)
+	serverDatastore := memory.New()
+	t.Cleanup(serverDatastore.Close)
+
+	requestCtx := func() (context.Context, context.CancelFunc) {
+		ctx := typesystem.ContextWithTypesystem(context.Background(), ts)
+		ctx = storage.ContextWithRelationshipTupleReader(ctx, requestDatastore)
+		return context.WithCancel(ctx)
+	}
+
+	newRequest := func() *ResolveCheckRequest {
+		req := &ResolveCheckRequest{
+			StoreID:              "12",
+			AuthorizationModelID: "33",
+			TupleKey:             tuple.NewTupleKey("document:abc", "reader", "user:XYZ"),
+			RequestMetadata:      NewCheckRequestMetadata(),
+			Consistency:          openfgav1.ConsistencyPreference_MINIMIZE_LATENCY,
+		}
+		req.RequestMetadata.Depth = 2
+		req.RequestMetadata.MaxResolutionDepth = 50
+		return req
+	}
+
+	t.Run("stale_entry_is_served_then_refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		fakeClock := clock.NewFake(time.Now())
+		dut, err := NewCachedCheckResolver(
+			WithCacheTTL(10*time.Second),
+			WithCacheClock(fakeClock),
+			WithCacheRevalidation(2*time.Second, 2, serverDatastore),
+		)
+		require.NoError(t, err)
+		t.Cleanup(dut.Close)
+
+		mockResolver := NewMockCheckResolver(ctrl)
+		dut.SetDelegate(mockResolver)
+
+		ctx, cancel := requestCtx()
+		req := newRequest()
+
+		mockResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).Times(1).Return(&ResolveCheckResponse{Allowed: true}, nil)
+		res, err := dut.ResolveCheck(ctx, req)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+
+		// a hit outside of the revalidation window is served from the cache
+		fakeClock.Advance(time.Second)
+		res, err = dut.ResolveCheck(ctx, req)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+
+		// a hit within the revalidation window is served from the cache and recomputes the entry
+		var revalidationCtx context.Context
+		var revalidationReq *ResolveCheckRequest
+		var revalidationErr error
+		mockResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(ctx context.Context, req *ResolveCheckRequest) (*ResolveCheckResponse, error) {
+				revalidationCtx, revalidationReq, revalidationErr = ctx, req, ctx.Err()
+				return &ResolveCheckResponse{Allowed: false}, nil
+			})
+		fakeClock.Advance(7 * time.Second)
+		res, err = dut.ResolveCheck(ctx, req)
+		cancel()
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+
+		dut.revalidationsWg.Wait()
+
+		// the recomputation is not canceled with the request which triggered it, nor reads with its datastore
+		require.NoError(t, revalidationErr)
+		ds, ok := storage.RelationshipTupleReaderFromContext(revalidationCtx)
+		require.True(t, ok)
+		combined, ok := ds.(*storagewrappers.CombinedTupleReader)
+		require.True(t, ok)
+		require.Same(t, serverDatastore, combined.RelationshipTupleReader)
+		revalidationTypesys, ok := typesystem.TypesystemFromContext(revalidationCtx)
+		require.True(t, ok)
+		require.Same(t, ts, revalidationTypesys)
+
+		require.Equal(t, openfgav1.ConsistencyPreference_MINIMIZE_LATENCY, revalidationReq.GetConsistency())
+		require.Equal(t, uint32(2), revalidationReq.GetRequestMetadata().Depth)
+		require.Equal(t, uint32(50), revalidationReq.GetRequestMetadata().MaxResolutionDepth)
+		require.NotSame(t, req.GetRequestMetadata().DispatchCounter, revalidationReq.GetRequestMetadata().DispatchCounter)
+
+		// the recomputed entry is served with a new TTL
+		ctx, cancel = requestCtx()
+		defer cancel()
+		fakeClock.Advance(5 * time.Second)
+		res, err = dut.ResolveCheck(ctx, req)
+		require.NoError(t, err)
+		require.False(t, res.Allowed)
+	})
+
+	t.Run("entry_outside_of_window_is_not_recomputed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockResolver := NewMockCheckResolver(ctrl)
+		mockResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).Times(1).Return(&ResolveCheckResponse{Allowed: true}, nil)
+
+		dut, err := NewCachedCheckResolver(WithCacheTTL(1*time.Hour), WithCacheRevalidation(1*time.Minute, 1, serverDatastore))
+		require.NoError(t, err)
+		dut.SetDelegate(mockResolver)
+
+		ctx, cancel := requestCtx()
+		defer cancel()
+		req := newRequest()
+		for i := 0; i < 3; i++ {
+			_, err := dut.ResolveCheck(ctx, req)
+			require.NoError(t, err)
+		}
+
+		dut.Close()
+	})
+}
+
 func TestResolveCheckLastChangelogRecent(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -612,6 +829,69 @@ func TestResolveCheckLastChangelogRecent(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCachedCheckResolverMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	storeID := "01JCACHEMETRICS"
+	bucket := storeBucket(storeID)
+
+	newRequest := func(lastCacheInvalidationTime time.Time) *ResolveCheckRequest {
+		return &ResolveCheckRequest{
+			StoreID:                   storeID,
+			AuthorizationModelID:      "33",
+			TupleKey:                  tuple.NewTupleKey("document:abc", "reader", "user:XYZ"),
+			RequestMetadata:           NewCheckRequestMetadata(),
+			LastCacheInvalidationTime: lastCacheInvalidationTime,
+		}
+	}
+
+	mockResolver := NewMockCheckResolver(ctrl)
+	mockResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).Times(2).Return(&ResolveCheckResponse{Allowed: true}, nil)
+
+	dut, err := NewCachedCheckResolver(WithCacheTTL(1 * time.Hour))
+	require.NoError(t, err)
+	defer dut.Close()
+	dut.SetDelegate(mockResolver)
+
+	hits := testutil.ToFloat64(checkCacheHitCounter.WithLabelValues(bucket))
+	misses := testutil.ToFloat64(checkCacheMissCounter.WithLabelValues(bucket))
+	invalidHits := testutil.ToFloat64(checkCacheInvalidHit.WithLabelValues(bucket))
+	entryGauge := func() float64 {
+		families, err := prometheus.DefaultGatherer.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() != "openfga_check_cache_entry_count" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[0].GetValue() == bucket {
+					return metric.GetGauge().GetValue()
+				}
+			}
+		}
+		return 0
+	}
+	entries := entryGauge()
+
+	_, err = dut.ResolveCheck(ctx, newRequest(time.Time{}))
+	require.NoError(t, err)
+	require.InDelta(t, misses+1, testutil.ToFloat64(checkCacheMissCounter.WithLabelValues(bucket)), 0)
+	require.InDelta(t, entries+1, entryGauge(), 0)
+
+	_, err = dut.ResolveCheck(ctx, newRequest(time.Time{}))
+	require.NoError(t, err)
+	require.InDelta(t, hits+1, testutil.ToFloat64(checkCacheHitCounter.WithLabelValues(bucket)), 0)
+
+	// a write to the store after the entry was cached invalidates it
+	_, err = dut.ResolveCheck(ctx, newRequest(time.Now().Add(time.Minute)))
+	require.NoError(t, err)
+	require.InDelta(t, invalidHits+1, testutil.ToFloat64(checkCacheInvalidHit.WithLabelValues(bucket)), 0)
+	// the entry is replaced, not added
+	require.InDelta(t, entries+1, entryGauge(), 0)
+}
+
 func TestCachedCheckResolver_FieldsInResponse(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
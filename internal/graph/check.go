@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/emirpasic/gods/sets/hashset"
 	"github.com/sourcegraph/conc"
@@ -18,8 +19,10 @@ import (
 	"github.com/openfga/openfga/internal/checkutil"
 	"github.com/openfga/openfga/internal/concurrency"
 	openfgaErrors "github.com/openfga/openfga/internal/errors"
+	"github.com/openfga/openfga/internal/loadshedding"
 	"github.com/openfga/openfga/internal/validation"
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/redact"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/telemetry"
@@ -49,11 +52,22 @@ type checkOutcome struct {
 
 type LocalChecker struct {
 	delegate             CheckResolver
+	dispatchPool         *dispatchPool
+	dispatchPoolSize     int
 	concurrencyLimit     int
 	usersetBatchSize     int
 	logger               logger.Logger
 	optimizationsEnabled bool
 	maxResolutionDepth   uint32
+	directTupleBatchSize int
+	// directTupleBatchMaxDelay is how long a tuple key waits for its batch to fill before the batch is sent.
+	directTupleBatchMaxDelay time.Duration
+
+	unionBranchOrdering bool
+	relationCosts       RelationCosts
+
+	membershipIndex   MembershipIndex
+	materializedViews MaterializedViews
 }
 
 type LocalCheckerOption func(d *LocalChecker)
@@ -65,6 +79,26 @@ func WithResolveNodeBreadthLimit(limit uint32) LocalCheckerOption {
 	}
 }
 
+// WithDispatchPoolSize resolves the subproblems of the set operations on a pool of size workers reused across
+// requests, rather than on a new goroutine each. The subproblems submitted while every worker is busy are resolved
+// on new goroutines. A size of 0, the default, disables the pool; it is raised to the resolve node breadth limit
+// otherwise, so that a set operation can resolve all its subproblems on the pool.
+func WithDispatchPoolSize(size uint32) LocalCheckerOption {
+	return func(d *LocalChecker) {
+		d.dispatchPoolSize = int(size)
+	}
+}
+
+// WithUnionBranchOrdering starts resolving the branches of a union from the cheapest to the most expensive, as
+// estimated from the shape of the model and the known costs, if any, of its relations. The resolution of the other
+// branches is cancelled once one allows the relation, so the cheap branches allowing it spare the expensive ones.
+func WithUnionBranchOrdering(enabled bool, costs RelationCosts) LocalCheckerOption {
+	return func(d *LocalChecker) {
+		d.unionBranchOrdering = enabled
+		d.relationCosts = costs
+	}
+}
+
 func WithOptimizations(enabled bool) LocalCheckerOption {
 	return func(d *LocalChecker) {
 		d.optimizationsEnabled = enabled
@@ -78,6 +112,20 @@ func WithUsersetBatchSize(usersetBatchSize uint32) LocalCheckerOption {
 	}
 }
 
+// WithDirectTupleBatchSize see server.WithCheckDirectTupleBatchSize.
+func WithDirectTupleBatchSize(size uint32) LocalCheckerOption {
+	return func(d *LocalChecker) {
+		d.directTupleBatchSize = int(size)
+	}
+}
+
+// WithDirectTupleBatchMaxDelay see server.WithCheckDirectTupleBatchMaxDelay.
+func WithDirectTupleBatchMaxDelay(delay time.Duration) LocalCheckerOption {
+	return func(d *LocalChecker) {
+		d.directTupleBatchMaxDelay = delay
+	}
+}
+
 func WithLocalCheckerLogger(logger logger.Logger) LocalCheckerOption {
 	return func(d *LocalChecker) {
 		d.logger = logger
@@ -101,6 +149,8 @@ func NewLocalChecker(opts ...LocalCheckerOption) *LocalChecker {
 		usersetBatchSize:   serverconfig.DefaultUsersetBatchSize,
 		maxResolutionDepth: serverconfig.DefaultResolveNodeLimit,
 		logger:             logger.NewNoopLogger(),
+
+		directTupleBatchMaxDelay: serverconfig.DefaultCheckDirectTupleBatchMaxDelay,
 	}
 	// by default, a LocalChecker delegates/dispatchs subproblems to itself (e.g. local dispatch) unless otherwise configured.
 	checker.delegate = checker
@@ -109,6 +159,10 @@ func NewLocalChecker(opts ...LocalCheckerOption) *LocalChecker {
 		opt(checker)
 	}
 
+	if checker.dispatchPoolSize > 0 {
+		checker.dispatchPool = newDispatchPool(max(checker.dispatchPoolSize, checker.concurrencyLimit))
+	}
+
 	return checker
 }
 
@@ -139,6 +193,9 @@ func resolver(ctx context.Context, concurrencyLimit int, resultChan chan<- check
 	limiter := make(chan struct{}, concurrencyLimit)
 
 	var wg conc.WaitGroup
+	// pooled are the handlers resolved by the dispatch pool of ctx, if any, rather than by wg.
+	var pooled sync.WaitGroup
+	pool := dispatchPoolFromContext(ctx)
 
 	checker := func(fn CheckHandlerFunc) {
 		defer func() {
@@ -152,12 +209,16 @@ func resolver(ctx context.Context, concurrencyLimit int, resultChan chan<- check
 			return
 		}
 
-		wg.Go(func() {
-			defer close(resolved)
+		if pool != nil {
+			goRecovered(ctx, &pooled, fn, resolved)
+		} else {
+			wg.Go(func() {
+				defer close(resolved)
 
-			resp, err := fn(ctx)
-			resolved <- checkOutcome{resp, err}
-		})
+				resp, err := fn(ctx)
+				resolved <- checkOutcome{resp, err}
+			})
+		}
 
 		select {
 		case <-ctx.Done():
@@ -185,6 +246,7 @@ func resolver(ctx context.Context, concurrencyLimit int, resultChan chan<- check
 
 	return func() error {
 		recoveredError := wg.WaitAndRecover()
+		pooled.Wait()
 		close(limiter)
 
 		if recoveredError != nil {
@@ -442,12 +504,25 @@ func exclusion(ctx context.Context, concurrencyLimit int, handlers ...CheckHandl
 
 // Close is a noop.
 func (c *LocalChecker) Close() {
+	c.dispatchPool.Close()
+}
+
+// resolutionDepthExceeded returns whether req has reached its maximum resolution depth, which is the
+// maxResolutionDepth of the checker unless the request overrides it.
+func (c *LocalChecker) resolutionDepthExceeded(req *ResolveCheckRequest) bool {
+	maxDepth := c.maxResolutionDepth
+	if override := req.GetRequestMetadata().MaxResolutionDepth; override > 0 {
+		maxDepth = override
+	}
+	return req.GetRequestMetadata().Depth >= maxDepth
 }
 
 // dispatch clones the parent request, modifies its metadata and tupleKey, and dispatches the new request
 // to the CheckResolver this LocalChecker was constructed with.
 func (c *LocalChecker) dispatch(_ context.Context, parentReq *ResolveCheckRequest, tk *openfgav1.TupleKey) CheckHandlerFunc {
 	return func(ctx context.Context) (*ResolveCheckResponse, error) {
+		defer loadshedding.Track(ctx)()
+
 		parentReq.GetRequestMetadata().DispatchCounter.Add(1)
 		childRequest := parentReq.clone()
 		childRequest.TupleKey = tk
@@ -475,14 +550,16 @@ func (c *LocalChecker) ResolveCheck(
 	ctx, span := tracer.Start(ctx, "ResolveCheck", trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreID()),
 		attribute.String("resolver_type", "LocalChecker"),
-		attribute.String("tuple_key", tuple.TupleKeyWithConditionToString(req.GetTupleKey())),
+		attribute.String("tuple_key", redact.TupleKey(req.GetTupleKey())),
 	))
 	defer span.End()
 
-	if req.GetRequestMetadata().Depth == c.maxResolutionDepth {
+	if c.resolutionDepthExceeded(req) {
 		return nil, ErrResolutionDepthExceeded
 	}
 
+	ctx = contextWithDispatchPool(ctx, c.dispatchPool)
+
 	cycle := c.hasCycle(req)
 	if cycle {
 		span.SetAttributes(attribute.Bool("cycle_detected", true))
@@ -529,6 +606,20 @@ func (c *LocalChecker) ResolveCheck(
 		}, nil
 	}
 
+	if allowed, ok := c.lookupMaterializedViews(req); ok {
+		span.SetAttributes(attribute.Bool("materialized_view_hit", true))
+		return &ResolveCheckResponse{
+			Allowed: allowed,
+		}, nil
+	}
+
+	if c.checkMembershipIndex(typesys, objectType, req) {
+		span.SetAttributes(attribute.Bool("membership_index_hit", true))
+		return &ResolveCheckResponse{
+			Allowed: true,
+		}, nil
+	}
+
 	resp, err := c.checkRewrite(ctx, req, rel.GetRewrite())(ctx)
 	if err != nil {
 		telemetry.TraceError(span, err)
@@ -594,6 +685,8 @@ func checkAssociatedObjects(ctx context.Context, req *ResolveCheckRequest, objec
 type dispatchParams struct {
 	parentReq *ResolveCheckRequest
 	tk        *openfgav1.TupleKey
+	// reader, if set, is the reader the dispatched check reads tuples from, with its direct tuple prefetched.
+	reader storage.RelationshipTupleReader
 }
 
 type dispatchMsg struct {
@@ -606,10 +699,13 @@ func (c *LocalChecker) produceUsersetDispatches(ctx context.Context, req *Resolv
 	defer close(dispatches)
 	reqTupleKey := req.GetTupleKey()
 	typesys, _ := typesystem.TypesystemFromContext(ctx)
+	batcher := c.newDispatchBatcher(req, dispatches)
+	defer batcher.stop()
 	for {
 		t, err := iter.Next(ctx)
 		if err != nil {
-			// cancelled doesn't need to flush nor send errors back to main routine
+			batcher.flush(ctx)
+			// cancelled doesn't need to send errors back to main routine
 			if storage.IterIsDoneOrCancelled(err) {
 				break
 			}
@@ -631,8 +727,7 @@ func (c *LocalChecker) produceUsersetDispatches(ctx context.Context, req *Resolv
 		}
 
 		if usersetRelation != "" {
-			tupleKey := tuple.NewTupleKey(usersetObject, usersetRelation, reqTupleKey.GetUser())
-			concurrency.TrySendThroughChannel(ctx, dispatchMsg{dispatchParams: &dispatchParams{parentReq: req, tk: tupleKey}}, dispatches)
+			batcher.add(ctx, tuple.NewTupleKey(usersetObject, usersetRelation, reqTupleKey.GetUser()))
 		}
 	}
 }
@@ -671,6 +766,9 @@ func (c *LocalChecker) processDispatches(ctx context.Context, limit int, dispatc
 
 				if msg.dispatchParams != nil {
 					dispatchPool.Go(func(ctx context.Context) error {
+						if reader := msg.dispatchParams.reader; reader != nil {
+							ctx = storage.ContextWithRelationshipTupleReader(ctx, reader)
+						}
 						recoveredError := panics.Try(func() {
 							resp, err := c.dispatch(ctx, msg.dispatchParams.parentReq, msg.dispatchParams.tk)(ctx)
 							concurrency.TrySendThroughChannel(ctx, checkOutcome{resp: resp, err: err}, outcomes)
@@ -1129,7 +1227,7 @@ func (c *LocalChecker) checkDirectUserTuple(ctx context.Context, req *ResolveChe
 
 	return func(ctx context.Context) (*ResolveCheckResponse, error) {
 		ctx, span := tracer.Start(ctx, "checkDirectUserTuple",
-			trace.WithAttributes(attribute.String("tuple_key", tuple.TupleKeyWithConditionToString(reqTupleKey))))
+			trace.WithAttributes(attribute.String("tuple_key", redact.TupleKey(reqTupleKey))))
 		defer span.End()
 
 		response := &ResolveCheckResponse{
@@ -1236,7 +1334,7 @@ func (c *LocalChecker) checkDirect(parentctx context.Context, req *ResolveCheckR
 		// TODO(jpadilla): can we lift this function up?
 		checkDirectUsersetTuples := func(ctx context.Context) (*ResolveCheckResponse, error) {
 			ctx, span := tracer.Start(ctx, "checkDirectUsersetTuples", trace.WithAttributes(
-				attribute.String("userset", tuple.ToObjectRelationString(reqTupleKey.GetObject(), reqTupleKey.GetRelation())),
+				attribute.String("userset", tuple.ToObjectRelationString(redact.Object(reqTupleKey.GetObject()), reqTupleKey.GetRelation())),
 				attribute.String("resolver", "slow"),
 			))
 			defer span.End()
@@ -1342,10 +1440,13 @@ func (c *LocalChecker) produceTTUDispatches(ctx context.Context, computedRelatio
 	defer close(dispatches)
 	reqTupleKey := req.GetTupleKey()
 	typesys, _ := typesystem.TypesystemFromContext(ctx)
+	batcher := c.newDispatchBatcher(req, dispatches)
+	defer batcher.stop()
 
 	for {
 		t, err := iter.Next(ctx)
 		if err != nil {
+			batcher.flush(ctx)
 			if storage.IterIsDoneOrCancelled(err) {
 				break
 			}
@@ -1360,13 +1461,11 @@ func (c *LocalChecker) produceTTUDispatches(ctx context.Context, computedRelatio
 			}
 		}
 
-		tupleKey := &openfgav1.TupleKey{
+		batcher.add(ctx, &openfgav1.TupleKey{
 			Object:   userObj,
 			Relation: computedRelation,
 			User:     reqTupleKey.GetUser(),
-		}
-
-		concurrency.TrySendThroughChannel(ctx, dispatchMsg{dispatchParams: &dispatchParams{parentReq: req, tk: tupleKey}}, dispatches)
+		})
 	}
 }
 
@@ -1541,6 +1640,12 @@ func (c *LocalChecker) checkSetOperation(
 			reducerKey = "exclusion"
 		}
 
+		if setOpType == unionSetOperator && c.unionBranchOrdering {
+			if typesys, ok := typesystem.TypesystemFromContext(ctx); ok {
+				children = orderByCost(typesys, tuple.GetType(req.GetTupleKey().GetObject()), req.GetTupleKey().GetRelation(), c.relationCosts, children)
+			}
+		}
+
 		for _, child := range children {
 			handlers = append(handlers, c.checkRewrite(ctx, req, child))
 		}
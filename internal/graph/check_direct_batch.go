@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/concurrency"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+)
+
+// dispatchBatcher sends the tuple keys to dispatch in batches of the direct tuple batch size of its LocalChecker,
+// along with a reader answering the direct tuple reads of the batch from a single ReadUserTuples call. A batch is
+// also sent once its first tuple key waited the direct tuple batch max delay, so that the checks are not delayed when
+// the tuple keys are produced slowly. If the batch size is lower than 2, every tuple key is sent as soon as it is
+// added.
+type dispatchBatcher struct {
+	checker    *LocalChecker
+	req        *ResolveCheckRequest
+	dispatches chan dispatchMsg
+
+	mu      sync.Mutex
+	pending []*openfgav1.TupleKey // GUARDED_BY(mu)
+	timer   *time.Timer           // GUARDED_BY(mu)
+	// batch counts the batches sent, so that the timer of a batch already sent does not send the next one.
+	batch   uint64 // GUARDED_BY(mu)
+	stopped bool   // GUARDED_BY(mu)
+}
+
+func (c *LocalChecker) newDispatchBatcher(req *ResolveCheckRequest, dispatches chan dispatchMsg) *dispatchBatcher {
+	return &dispatchBatcher{checker: c, req: req, dispatches: dispatches}
+}
+
+func (b *dispatchBatcher) add(ctx context.Context, tk *openfgav1.TupleKey) {
+	if b.checker.directTupleBatchSize < 2 {
+		concurrency.TrySendThroughChannel(ctx, dispatchMsg{dispatchParams: &dispatchParams{parentReq: b.req, tk: tk}}, b.dispatches)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, tk)
+	if len(b.pending) >= b.checker.directTupleBatchSize {
+		b.flushLocked(ctx)
+		return
+	}
+
+	if len(b.pending) == 1 && b.checker.directTupleBatchMaxDelay > 0 {
+		batch := b.batch
+		b.timer = time.AfterFunc(b.checker.directTupleBatchMaxDelay, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if !b.stopped && b.batch == batch {
+				b.flushLocked(ctx)
+			}
+		})
+	}
+}
+
+// flush sends the pending tuple keys.
+func (b *dispatchBatcher) flush(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked(ctx)
+}
+
+// flushLocked sends the pending tuple keys, unless ctx is cancelled. The caller must hold mu.
+func (b *dispatchBatcher) flushLocked(ctx context.Context) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.batch++
+	pending := b.pending
+	b.pending = nil
+
+	// the dispatches of a cancelled check are not sent, so their tuples are not read
+	if len(pending) == 0 || ctx.Err() != nil {
+		return
+	}
+
+	reader := b.checker.prefetchDirectTuples(ctx, b.req, pending)
+	for _, tk := range pending {
+		concurrency.TrySendThroughChannel(ctx, dispatchMsg{dispatchParams: &dispatchParams{parentReq: b.req, tk: tk, reader: reader}}, b.dispatches)
+	}
+}
+
+// stop drops the pending tuple keys and stops the timer of their batch. It must be called before the dispatches
+// channel is closed.
+func (b *dispatchBatcher) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
+
+// prefetchDirectTuples reads the direct tuples of the tuple keys with a single ReadUserTuples call, and returns a
+// reader answering their ReadUserTuple from its result. It returns nil if fewer than two tuple keys can be directly
+// related or the read failed, in which case the dispatched checks read them one by one.
+func (c *LocalChecker) prefetchDirectTuples(ctx context.Context, req *ResolveCheckRequest, tupleKeys []*openfgav1.TupleKey) storage.RelationshipTupleReader {
+	direct := make([]*openfgav1.TupleKey, 0, len(tupleKeys))
+	for _, tk := range tupleKeys {
+		if shouldCheckDirectTuple(ctx, tk) {
+			direct = append(direct, tk)
+		}
+	}
+	if len(direct) < 2 {
+		return nil
+	}
+
+	ds, _ := storage.RelationshipTupleReaderFromContext(ctx)
+	found, err := ds.ReadUserTuples(ctx, req.GetStoreID(), direct, storage.ReadUserTupleOptions{
+		Consistency: storage.ConsistencyOptions{
+			Preference: req.GetConsistency(),
+		},
+	})
+	if err != nil {
+		c.logger.Debug("check failed to prefetch direct tuples", zap.Error(err))
+		return nil
+	}
+
+	return storagewrappers.NewPrefetchedUserTupleReader(ds, req.GetStoreID(), direct, found)
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/internal/concurrency"
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestProduceTTUDispatchesWithDirectTupleBatch(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	model := parser.MustTransformDSLToProto(`
+				model
+					schema 1.1
+
+				type user
+				type group
+					relations
+						define member: [user]
+				type document
+					relations
+						define viewer: member from owner
+						define owner: [group]`)
+
+	ts, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	storeID := ulid.Make().String()
+	req := &ResolveCheckRequest{
+		StoreID:         storeID,
+		TupleKey:        tuple.NewTupleKey("document:doc1", "viewer", "user:maria"),
+		RequestMetadata: NewCheckRequestMetadata(),
+	}
+	tuples := []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:doc1", "owner", "group:1"),
+		tuple.NewTupleKey("document:doc1", "owner", "group:2"),
+		tuple.NewTupleKey("document:doc1", "owner", "group:3"),
+	}
+	batch := []*openfgav1.TupleKey{
+		tuple.NewTupleKey("group:1", "member", "user:maria"),
+		tuple.NewTupleKey("group:2", "member", "user:maria"),
+	}
+
+	t.Run("reads_batches_with_a_single_query", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockDatastore := mocks.NewMockRelationshipTupleReader(ctrl)
+		mockDatastore.EXPECT().ReadUserTuples(gomock.Any(), storeID, batch, gomock.Any()).Times(1).
+			Return([]*openfgav1.Tuple{{Key: batch[1]}}, nil)
+
+		ctx := typesystem.ContextWithTypesystem(context.Background(), ts)
+		ctx = storage.ContextWithRelationshipTupleReader(ctx, mockDatastore)
+
+		checker := NewLocalChecker(WithDirectTupleBatchSize(2))
+		defer checker.Close()
+
+		pool := concurrency.NewPool(ctx, 1)
+		dispatchChan := make(chan dispatchMsg, 3)
+		pool.Go(func(ctx context.Context) error {
+			checker.produceTTUDispatches(ctx, "member", req, dispatchChan, storage.NewStaticTupleKeyIterator(tuples))
+			return nil
+		})
+		receivedMsgs := collectMessagesFromChannel(dispatchChan)
+		_ = pool.Wait()
+
+		require.Len(t, receivedMsgs, 3)
+		for i, msg := range receivedMsgs[:2] {
+			require.Equal(t, batch[i], msg.dispatchParams.tk)
+			require.NotNil(t, msg.dispatchParams.reader)
+		}
+
+		// the last tuple key is alone in its batch, so it is read on its own
+		require.Equal(t, tuple.NewTupleKey("group:3", "member", "user:maria"), receivedMsgs[2].dispatchParams.tk)
+		require.Nil(t, receivedMsgs[2].dispatchParams.reader)
+
+		reader := receivedMsgs[0].dispatchParams.reader
+		_, err := reader.ReadUserTuple(ctx, storeID, batch[0], storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+		found, err := reader.ReadUserTuple(ctx, storeID, batch[1], storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, batch[1], found.GetKey())
+	})
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockDatastore := mocks.NewMockRelationshipTupleReader(ctrl)
+
+		ctx := typesystem.ContextWithTypesystem(context.Background(), ts)
+		ctx = storage.ContextWithRelationshipTupleReader(ctx, mockDatastore)
+
+		checker := NewLocalChecker()
+		defer checker.Close()
+
+		pool := concurrency.NewPool(ctx, 1)
+		dispatchChan := make(chan dispatchMsg, 3)
+		pool.Go(func(ctx context.Context) error {
+			checker.produceTTUDispatches(ctx, "member", req, dispatchChan, storage.NewStaticTupleKeyIterator(tuples))
+			return nil
+		})
+		receivedMsgs := collectMessagesFromChannel(dispatchChan)
+		_ = pool.Wait()
+
+		require.Len(t, receivedMsgs, 3)
+		for _, msg := range receivedMsgs {
+			require.Nil(t, msg.dispatchParams.reader)
+		}
+	})
+
+	t.Run("sends_a_batch_once_its_max_delay_elapsed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockDatastore := mocks.NewMockRelationshipTupleReader(ctrl)
+
+		ctx, cancel := context.WithCancel(typesystem.ContextWithTypesystem(context.Background(), ts))
+		defer cancel()
+		ctx = storage.ContextWithRelationshipTupleReader(ctx, mockDatastore)
+
+		checker := NewLocalChecker(WithDirectTupleBatchSize(2), WithDirectTupleBatchMaxDelay(5*time.Millisecond))
+		defer checker.Close()
+
+		pool := concurrency.NewPool(ctx, 1)
+		dispatchChan := make(chan dispatchMsg, 3)
+		pool.Go(func(ctx context.Context) error {
+			checker.produceTTUDispatches(ctx, "member", req, dispatchChan, newBlockingTupleKeyIterator(tuples[:1]))
+			return nil
+		})
+
+		// the batch is sent while the iterator is still reading
+		select {
+		case msg := <-dispatchChan:
+			require.Equal(t, tuple.NewTupleKey("group:1", "member", "user:maria"), msg.dispatchParams.tk)
+		case <-time.After(time.Second):
+			require.Fail(t, "the batch was not sent once its max delay elapsed")
+		}
+
+		cancel()
+		require.Empty(t, collectMessagesFromChannel(dispatchChan))
+		_ = pool.Wait()
+	})
+
+	t.Run("cancelled_check_does_not_read_its_pending_batch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		// no ReadUserTuples is expected
+		mockDatastore := mocks.NewMockRelationshipTupleReader(ctrl)
+
+		ctx, cancel := context.WithCancel(typesystem.ContextWithTypesystem(context.Background(), ts))
+		defer cancel()
+		ctx = storage.ContextWithRelationshipTupleReader(ctx, mockDatastore)
+
+		checker := NewLocalChecker(WithDirectTupleBatchSize(3), WithDirectTupleBatchMaxDelay(0))
+		defer checker.Close()
+
+		iter := newBlockingTupleKeyIterator(tuples[:2])
+		pool := concurrency.NewPool(ctx, 1)
+		dispatchChan := make(chan dispatchMsg, 3)
+		pool.Go(func(ctx context.Context) error {
+			checker.produceTTUDispatches(ctx, "member", req, dispatchChan, iter)
+			return nil
+		})
+
+		<-iter.blocked
+		cancel()
+		require.Empty(t, collectMessagesFromChannel(dispatchChan))
+		_ = pool.Wait()
+	})
+}
+
+// blockingTupleKeyIterator is a [storage.TupleKeyIterator] returning its tuple keys, and then blocking until its
+// context is cancelled.
+type blockingTupleKeyIterator struct {
+	storage.TupleKeyIterator
+	blocked chan struct{}
+}
+
+func newBlockingTupleKeyIterator(tks []*openfgav1.TupleKey) *blockingTupleKeyIterator {
+	return &blockingTupleKeyIterator{TupleKeyIterator: storage.NewStaticTupleKeyIterator(tks), blocked: make(chan struct{})}
+}
+
+func (b *blockingTupleKeyIterator) Next(ctx context.Context) (*openfgav1.TupleKey, error) {
+	tk, err := b.TupleKeyIterator.Next(ctx)
+	if !errors.Is(err, storage.ErrIteratorDone) {
+		return tk, err
+	}
+	close(b.blocked)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
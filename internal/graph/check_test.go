@@ -4691,3 +4691,18 @@ func TestCheckPublicAssignable(t *testing.T) {
 		})
 	}
 }
+
+func TestResolutionDepthExceeded(t *testing.T) {
+	checker := NewLocalChecker(WithMaxResolutionDepth(3))
+	t.Cleanup(checker.Close)
+
+	req := &ResolveCheckRequest{RequestMetadata: &ResolveCheckRequestMetadata{Depth: 3}}
+	require.True(t, checker.resolutionDepthExceeded(req))
+
+	req.RequestMetadata.MaxResolutionDepth = 5
+	require.False(t, checker.resolutionDepthExceeded(req))
+	require.False(t, checker.resolutionDepthExceeded(req.clone()))
+
+	req.RequestMetadata.MaxResolutionDepth = 2
+	require.True(t, checker.resolutionDepthExceeded(req))
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openfga/openfga/internal/build"
+)
+
+var (
+	dispatchPoolWorkersGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "check_dispatch_pool_workers",
+		Help:      "The number of workers of the check dispatch pools.",
+	})
+
+	dispatchPoolBusyWorkersGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "check_dispatch_pool_busy_workers",
+		Help:      "The number of workers of the check dispatch pools resolving a subproblem. Divided by check_dispatch_pool_workers, it is the utilization of the pools.",
+	})
+
+	dispatchPoolOverflowCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "check_dispatch_pool_overflow_count",
+		Help:      "The total number of check subproblems resolved on a new goroutine because every worker of the check dispatch pools was busy.",
+	})
+)
+
+// dispatchPool is a fixed set of goroutines resolving the subproblems of the set operations of Check, reused across
+// requests rather than started for every subproblem. A subproblem submitted while every worker is busy is resolved
+// on a new goroutine, so that the subproblems waiting for their own subproblems never deadlock the pool.
+type dispatchPool struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+
+	// mu guards closed, so that no task is submitted once the pool is closed.
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newDispatchPool(size int) *dispatchPool {
+	p := &dispatchPool{tasks: make(chan func())}
+	dispatchPoolWorkersGauge.Add(float64(size))
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *dispatchPool) work() {
+	defer func() {
+		dispatchPoolWorkersGauge.Dec()
+		p.wg.Done()
+	}()
+	for task := range p.tasks {
+		dispatchPoolBusyWorkersGauge.Inc()
+		task()
+		dispatchPoolBusyWorkersGauge.Dec()
+	}
+}
+
+// Go resolves task on an idle worker, or on a new goroutine if every worker is busy. A nil *dispatchPool always
+// starts a new goroutine.
+func (p *dispatchPool) Go(task func()) {
+	if p != nil {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		if !p.closed {
+			select {
+			case p.tasks <- task:
+				return
+			default:
+				dispatchPoolOverflowCounter.Inc()
+			}
+		}
+	}
+	go task()
+}
+
+// Close stops the workers once they resolved their subproblems.
+func (p *dispatchPool) Close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.tasks)
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+type dispatchPoolContextKey struct{}
+
+// contextWithDispatchPool returns a copy of ctx resolving the subproblems with p, unless ctx already does.
+func contextWithDispatchPool(ctx context.Context, p *dispatchPool) context.Context {
+	if p == nil || dispatchPoolFromContext(ctx) == p {
+		return ctx
+	}
+	return context.WithValue(ctx, dispatchPoolContextKey{}, p)
+}
+
+func dispatchPoolFromContext(ctx context.Context) *dispatchPool {
+	p, _ := ctx.Value(dispatchPoolContextKey{}).(*dispatchPool)
+	return p
+}
+
+// goRecovered resolves fn with the pool of ctx, if any, and sends its outcome, or the panic it recovered from, to
+// resolved.
+func goRecovered(ctx context.Context, wg *sync.WaitGroup, fn CheckHandlerFunc, resolved chan<- checkOutcome) {
+	wg.Add(1)
+	dispatchPoolFromContext(ctx).Go(func() {
+		defer wg.Done()
+		defer close(resolved)
+		defer func() {
+			if r := recover(); r != nil {
+				resolved <- checkOutcome{nil, fmt.Errorf("%w: %v", ErrPanic, r)}
+			}
+		}()
+
+		resp, err := fn(ctx)
+		resolved <- checkOutcome{resp, err}
+	})
+}
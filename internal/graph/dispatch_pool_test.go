@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestDispatchPool(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	t.Run("overflows_onto_new_goroutines_while_the_workers_are_busy", func(t *testing.T) {
+		pool := newDispatchPool(1)
+		t.Cleanup(pool.Close)
+
+		release := make(chan struct{})
+		var wg sync.WaitGroup
+		block := func() {
+			wg.Add(1)
+			pool.Go(func() {
+				defer wg.Done()
+				<-release
+			})
+		}
+		// the tasks overflow until the worker is receiving them
+		require.Eventually(t, func() bool {
+			block()
+			return testutil.ToFloat64(dispatchPoolBusyWorkersGauge) == 1
+		}, time.Second, time.Millisecond)
+
+		overflows := testutil.ToFloat64(dispatchPoolOverflowCounter)
+		block()
+		require.InDelta(t, overflows+1, testutil.ToFloat64(dispatchPoolOverflowCounter), 0)
+
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("closed_pool_starts_new_goroutines", func(t *testing.T) {
+		pool := newDispatchPool(2)
+		pool.Close()
+		pool.Close()
+
+		done := make(chan struct{})
+		pool.Go(func() { close(done) })
+		<-done
+	})
+
+	t.Run("resolver_recovers_the_panics_of_the_pooled_handlers", func(t *testing.T) {
+		pool := newDispatchPool(1)
+		t.Cleanup(pool.Close)
+		ctx := contextWithDispatchPool(context.Background(), pool)
+
+		panicHandler := func(context.Context) (*ResolveCheckResponse, error) {
+			panic(panicErr)
+		}
+		allowedHandler := func(context.Context) (*ResolveCheckResponse, error) {
+			return &ResolveCheckResponse{Allowed: true}, nil
+		}
+
+		resultChan := make(chan checkOutcome, 2)
+		drain := resolver(ctx, 2, resultChan, panicHandler, allowedHandler)
+		require.NoError(t, drain())
+		close(resultChan)
+
+		var allowed bool
+		var err error
+		for outcome := range resultChan {
+			if outcome.err != nil {
+				err = outcome.err
+				continue
+			}
+			allowed = outcome.resp.GetAllowed()
+		}
+		require.True(t, allowed)
+		require.ErrorIs(t, err, ErrPanic)
+		require.ErrorContains(t, err, panicErr)
+	})
+
+	t.Run("local_checker_closes_its_pool", func(t *testing.T) {
+		checker := NewLocalChecker(WithResolveNodeBreadthLimit(4), WithDispatchPoolSize(2))
+		require.NotNil(t, checker.dispatchPool)
+		checker.Close()
+	})
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// fakeMaterializedViews answers the checks of its views, keyed by 'object#relation', for any store and model.
+type fakeMaterializedViews map[string]map[string]bool
+
+func (f fakeMaterializedViews) Lookup(_, _, object, relation, user string) (bool, bool) {
+	users, ok := f[tuple.ToObjectRelationString(object, relation)]
+	if !ok {
+		return false, false
+	}
+	return users[user], true
+}
+
+func TestResolveCheckWithMaterializedViews(t *testing.T) {
+	model := parser.MustTransformDSLToProto(`
+				model
+					schema 1.1
+
+				type user
+				type org
+					relations
+						define member: [user]`)
+	ts, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), ts)
+	ctx = storage.ContextWithRelationshipTupleReader(ctx, ds)
+
+	storeID := ulid.Make().String()
+	require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("org:acme", "member", "user:bob"),
+	}))
+
+	// the view disagrees with the tuples, to tell its answers apart from those of the graph
+	views := fakeMaterializedViews{"org:acme#member": {"user:anne": true}}
+	checker := NewLocalChecker(WithMaterializedViews(views))
+	t.Cleanup(checker.Close)
+
+	check := func(req *ResolveCheckRequest) bool {
+		req.StoreID = storeID
+		req.RequestMetadata = NewCheckRequestMetadata()
+		resp, err := checker.ResolveCheck(ctx, req)
+		require.NoError(t, err)
+		return resp.GetAllowed()
+	}
+
+	t.Run("allowed_by_the_view", func(t *testing.T) {
+		require.True(t, check(&ResolveCheckRequest{TupleKey: tuple.NewTupleKey("org:acme", "member", "user:anne")}))
+	})
+
+	t.Run("denied_by_the_view", func(t *testing.T) {
+		require.False(t, check(&ResolveCheckRequest{TupleKey: tuple.NewTupleKey("org:acme", "member", "user:bob")}))
+	})
+
+	t.Run("resolved_with_the_graph_without_a_view", func(t *testing.T) {
+		require.False(t, check(&ResolveCheckRequest{TupleKey: tuple.NewTupleKey("org:other", "member", "user:anne")}))
+	})
+
+	t.Run("not_used_for_higher_consistency", func(t *testing.T) {
+		require.True(t, check(&ResolveCheckRequest{
+			TupleKey:    tuple.NewTupleKey("org:acme", "member", "user:bob"),
+			Consistency: openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY,
+		}))
+	})
+
+	t.Run("not_used_with_contextual_tuples", func(t *testing.T) {
+		require.True(t, check(&ResolveCheckRequest{
+			TupleKey:         tuple.NewTupleKey("org:acme", "member", "user:bob"),
+			ContextualTuples: []*openfgav1.TupleKey{tuple.NewTupleKey("org:other", "member", "user:bob")},
+		}))
+	})
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+var membershipIndexLookupCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: build.ProjectName,
+	Name:      "check_membership_index_lookup_count",
+	Help:      "The total number of Check subproblems looked up in the membership index, partitioned by whether the index allowed them ('hit') or they were resolved with the graph ('miss').",
+}, []string{"result"})
+
+// MembershipIndex is a precomputed index of the transitive members of nested relations, such as the members of a
+// group and of the groups which are its members, e.g. 'define member: [user, group#member]'.
+type MembershipIndex interface {
+	// IsMember reports whether the index holds user as a member of object#relation in the store, directly or through
+	// the usersets of relation on objects of the same type. The index may be incomplete or lagging, so false only
+	// means the relation must be resolved with the graph.
+	IsMember(storeID, object, relation, user string) bool
+}
+
+// WithMembershipIndex allows the Check subproblems of nested relations found in index without resolving the graph.
+// The subproblems not found in it, or requiring higher consistency, are resolved with the graph.
+func WithMembershipIndex(index MembershipIndex) LocalCheckerOption {
+	return func(d *LocalChecker) {
+		d.membershipIndex = index
+	}
+}
+
+// checkMembershipIndex reports whether the membership index allows req, a subproblem of objectType.
+func (c *LocalChecker) checkMembershipIndex(typesys *typesystem.TypeSystem, objectType string, req *ResolveCheckRequest) bool {
+	if c.membershipIndex == nil || req.GetConsistency() == openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY {
+		return false
+	}
+
+	tk := req.GetTupleKey()
+	if !isIndexableMembership(typesys, objectType, tk.GetRelation(), tk.GetUser()) {
+		return false
+	}
+
+	if c.membershipIndex.IsMember(req.GetStoreID(), tk.GetObject(), tk.GetRelation(), tk.GetUser()) {
+		membershipIndexLookupCounter.WithLabelValues("hit").Inc()
+		return true
+	}
+	membershipIndexLookupCounter.WithLabelValues("miss").Inc()
+	return false
+}
+
+// isIndexableMembership reports whether a member of objectType#relation held by a membership index is a member
+// according to the model: relation is directly assignable, possibly in a union, to the type of user and to its own
+// usersets.
+func isIndexableMembership(typesys *typesystem.TypeSystem, objectType, relation, user string) bool {
+	if tuple.IsObjectRelation(user) || tuple.IsTypedWildcard(user) {
+		return false
+	}
+
+	rel, err := typesys.GetRelation(objectType, relation)
+	if err != nil || !hasDirectRewrite(rel.GetRewrite()) {
+		return false
+	}
+
+	directlyRelated, err := typesys.GetDirectlyRelatedUserTypes(objectType, relation)
+	if err != nil {
+		return false
+	}
+	userType := tuple.GetType(user)
+	var nested, assignable bool
+	for _, ref := range directlyRelated {
+		if ref.GetCondition() != "" || ref.GetWildcard() != nil {
+			continue
+		}
+		switch {
+		case ref.GetType() == objectType && ref.GetRelation() == relation:
+			nested = true
+		case ref.GetType() == userType && ref.GetRelation() == "":
+			assignable = true
+		}
+	}
+	return nested && assignable
+}
+
+// hasDirectRewrite reports whether rewrite is a direct relation or a union of one.
+func hasDirectRewrite(rewrite *openfgav1.Userset) bool {
+	switch rw := rewrite.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		return true
+	case *openfgav1.Userset_Union:
+		for _, child := range rw.Union.GetChild() {
+			if _, ok := child.GetUserset().(*openfgav1.Userset_This); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+type fakeMembershipIndex map[string]struct{}
+
+func (f fakeMembershipIndex) IsMember(storeID, object, relation, user string) bool {
+	_, ok := f[storeID+"/"+tuple.TupleKeyToString(tuple.NewTupleKey(object, relation, user))]
+	return ok
+}
+
+func TestIsIndexableMembership(t *testing.T) {
+	model := parser.MustTransformDSLToProto(`
+				model
+					schema 1.1
+
+				type user
+				type group
+					relations
+						define owner: [user]
+						define member: [user, group#member] or owner
+						define flat: [user]
+						define excluded: [user, group#excluded] but not owner
+						define public: [user:*, group#public]`)
+	ts, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	require.True(t, isIndexableMembership(ts, "group", "member", "user:anne"))
+	require.False(t, isIndexableMembership(ts, "group", "member", "group:eng#member"))
+	require.False(t, isIndexableMembership(ts, "group", "flat", "user:anne"))
+	require.False(t, isIndexableMembership(ts, "group", "excluded", "user:anne"))
+	require.False(t, isIndexableMembership(ts, "group", "public", "user:anne"))
+	require.False(t, isIndexableMembership(ts, "group", "undefined", "user:anne"))
+}
+
+func TestResolveCheckWithMembershipIndex(t *testing.T) {
+	model := parser.MustTransformDSLToProto(`
+				model
+					schema 1.1
+
+				type user
+				type group
+					relations
+						define member: [user, group#member]`)
+	ts, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), ts)
+	ctx = storage.ContextWithRelationshipTupleReader(ctx, ds)
+
+	storeID := ulid.Make().String()
+	index := fakeMembershipIndex{storeID + "/group:all#member@user:anne": {}}
+	checker := NewLocalChecker(WithMembershipIndex(index))
+	t.Cleanup(checker.Close)
+
+	t.Run("allowed_by_the_index", func(t *testing.T) {
+		resp, err := checker.ResolveCheck(ctx, &ResolveCheckRequest{
+			StoreID:         storeID,
+			TupleKey:        tuple.NewTupleKey("group:all", "member", "user:anne"),
+			RequestMetadata: NewCheckRequestMetadata(),
+		})
+		require.NoError(t, err)
+		require.True(t, resp.GetAllowed())
+	})
+
+	t.Run("resolved_with_the_graph_on_miss", func(t *testing.T) {
+		resp, err := checker.ResolveCheck(ctx, &ResolveCheckRequest{
+			StoreID:         storeID,
+			TupleKey:        tuple.NewTupleKey("group:all", "member", "user:bob"),
+			RequestMetadata: NewCheckRequestMetadata(),
+		})
+		require.NoError(t, err)
+		require.False(t, resp.GetAllowed())
+	})
+
+	t.Run("not_used_for_higher_consistency", func(t *testing.T) {
+		resp, err := checker.ResolveCheck(ctx, &ResolveCheckRequest{
+			StoreID:         storeID,
+			TupleKey:        tuple.NewTupleKey("group:all", "member", "user:anne"),
+			RequestMetadata: NewCheckRequestMetadata(),
+			Consistency:     openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY,
+		})
+		require.NoError(t, err)
+		require.False(t, resp.GetAllowed())
+	})
+}
@@ -38,6 +38,9 @@ type ResolveCheckRequestMetadata struct {
 	// When we jump one level, we increment it by 1. If it hits maxResolutionDepth (resolveNodeLimit), we throw ErrResolutionDepthExceeded.
 	Depth uint32
 
+	// MaxResolutionDepth overrides the maxResolutionDepth of the checker for this request. 0 keeps it.
+	MaxResolutionDepth uint32
+
 	// DispatchCounter is the address to a shared counter that keeps track of how many calls to ResolveCheck we had to do
 	// to solve the root/parent problem.
 	// The contents of this counter will be written by concurrent goroutines.
@@ -46,6 +49,10 @@ type ResolveCheckRequestMetadata struct {
 
 	// WasThrottled indicates whether the request was throttled
 	WasThrottled *atomic.Bool
+
+	// CheckCacheHits is the address to a shared counter of the sub-problems of the root problem that were
+	// served from the check cache.
+	CheckCacheHits *atomic.Uint32
 }
 
 type ResolveCheckRequestParams struct {
@@ -56,12 +63,14 @@ type ResolveCheckRequestParams struct {
 	Consistency               openfgav1.ConsistencyPreference
 	LastCacheInvalidationTime time.Time
 	AuthorizationModelID      string
+	MaxResolutionDepth        uint32
 }
 
 func NewCheckRequestMetadata() *ResolveCheckRequestMetadata {
 	return &ResolveCheckRequestMetadata{
 		DispatchCounter: new(atomic.Uint32),
 		WasThrottled:    new(atomic.Bool),
+		CheckCacheHits:  new(atomic.Uint32),
 	}
 }
 
@@ -101,6 +110,7 @@ func NewResolveCheckRequest(
 	}
 
 	r.invariantCacheKey = keyBuilder.String()
+	r.RequestMetadata.MaxResolutionDepth = params.MaxResolutionDepth
 
 	return r, nil
 }
@@ -110,9 +120,11 @@ func (r *ResolveCheckRequest) clone() *ResolveCheckRequest {
 	origRequestMetadata := r.GetRequestMetadata()
 	if origRequestMetadata != nil {
 		requestMetadata = &ResolveCheckRequestMetadata{
-			DispatchCounter: origRequestMetadata.DispatchCounter,
-			Depth:           origRequestMetadata.Depth,
-			WasThrottled:    origRequestMetadata.WasThrottled,
+			DispatchCounter:    origRequestMetadata.DispatchCounter,
+			Depth:              origRequestMetadata.Depth,
+			MaxResolutionDepth: origRequestMetadata.MaxResolutionDepth,
+			WasThrottled:       origRequestMetadata.WasThrottled,
+			CheckCacheHits:     origRequestMetadata.CheckCacheHits,
 		}
 	}
 
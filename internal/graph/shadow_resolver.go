@@ -10,6 +10,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/redact"
 )
 
 const Hundred = 100
@@ -72,7 +73,7 @@ func (s ShadowResolver) ResolveCheck(ctx context.Context, req *ResolveCheckReque
 					s.logger.ErrorWithContext(ctx, "panic recovered",
 						zap.String("resolver", s.name),
 						zap.Any("error", err),
-						zap.String("request", reqClone.GetTupleKey().String()),
+						zap.String("request", redact.TupleKey(reqClone.GetTupleKey())),
 						zap.String("store_id", reqClone.GetStoreID()),
 						zap.String("model_id", reqClone.GetAuthorizationModelID()),
 						zap.String("function", "ShadowResolver.ResolveCheck"),
@@ -88,7 +89,7 @@ func (s ShadowResolver) ResolveCheck(ctx context.Context, req *ResolveCheckReque
 				s.logger.WarnWithContext(ctx, "shadow check errored",
 					zap.String("resolver", s.name),
 					zap.Error(err),
-					zap.String("request", reqClone.GetTupleKey().String()),
+					zap.String("request", redact.TupleKey(reqClone.GetTupleKey())),
 					zap.String("store_id", reqClone.GetStoreID()),
 					zap.String("model_id", reqClone.GetAuthorizationModelID()),
 				)
@@ -97,7 +98,7 @@ func (s ShadowResolver) ResolveCheck(ctx context.Context, req *ResolveCheckReque
 			if shadowRes.GetAllowed() != resClone.GetAllowed() {
 				s.logger.InfoWithContext(ctx, "shadow check difference",
 					zap.String("resolver", s.name),
-					zap.String("request", reqClone.GetTupleKey().String()),
+					zap.String("request", redact.TupleKey(reqClone.GetTupleKey())),
 					zap.String("store_id", reqClone.GetStoreID()),
 					zap.String("model_id", reqClone.GetAuthorizationModelID()),
 					zap.Bool("main", resClone.GetAllowed()),
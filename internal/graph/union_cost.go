@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"cmp"
+	"slices"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// The estimated costs of the rewrites, in datastore reads and dispatches. They only need to be comparable with one
+// another.
+const (
+	// directCost is that of a direct relation of users, resolved with a single read.
+	directCost = 1
+	// usersetTypeCost is added to a direct relation for every userset type it allows, whose usersets are read and
+	// dispatched.
+	usersetTypeCost = 2
+	// tupleToUsersetCost is that of a tuple to userset rewrite, whose tupleset is read and whose objects are
+	// dispatched.
+	tupleToUsersetCost = 3
+	// computedUsersetCost is added to the cost of the relation of a computed userset rewrite, for its dispatch.
+	computedUsersetCost = 0.5
+	// maxCostDepth is how many computed usersets are followed to estimate the cost of a rewrite.
+	maxCostDepth = 3
+)
+
+// RelationCosts are known costs of resolving relations, keyed by 'type#relation', e.g. the average number of
+// datastore reads observed, overriding those estimated from the shape of the model. See [WithUnionBranchOrdering].
+type RelationCosts map[string]float64
+
+// rewriteCostEstimator estimates the cost of resolving the rewrites of the relations of an object type.
+type rewriteCostEstimator struct {
+	typesys    *typesystem.TypeSystem
+	objectType string
+	costs      RelationCosts
+}
+
+func (e *rewriteCostEstimator) relationCost(relation string, depth int) float64 {
+	if cost, ok := e.costs[tuple.ToObjectRelationString(e.objectType, relation)]; ok {
+		return cost
+	}
+	rel, err := e.typesys.GetRelation(e.objectType, relation)
+	if err != nil || depth >= maxCostDepth {
+		return tupleToUsersetCost
+	}
+	return e.rewriteCost(relation, rel.GetRewrite(), depth+1)
+}
+
+// rewriteCost estimates the cost of resolving rewrite, a rewrite of relation.
+func (e *rewriteCostEstimator) rewriteCost(relation string, rewrite *openfgav1.Userset, depth int) float64 {
+	switch rw := rewrite.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		cost := float64(directCost)
+		directlyRelated, _ := e.typesys.GetDirectlyRelatedUserTypes(e.objectType, relation)
+		for _, ref := range directlyRelated {
+			if ref.GetRelation() != "" {
+				cost += usersetTypeCost
+			}
+		}
+		return cost
+	case *openfgav1.Userset_ComputedUserset:
+		return computedUsersetCost + e.relationCost(rw.ComputedUserset.GetRelation(), depth)
+	case *openfgav1.Userset_TupleToUserset:
+		return tupleToUsersetCost
+	case *openfgav1.Userset_Union:
+		// a union is resolved as soon as its cheapest branch allows it
+		cheapest := 0.0
+		for i, child := range rw.Union.GetChild() {
+			cost := e.rewriteCost(relation, child, depth)
+			if i == 0 || cost < cheapest {
+				cheapest = cost
+			}
+		}
+		return cheapest
+	case *openfgav1.Userset_Intersection:
+		total := 0.0
+		for _, child := range rw.Intersection.GetChild() {
+			total += e.rewriteCost(relation, child, depth)
+		}
+		return total
+	case *openfgav1.Userset_Difference:
+		return e.rewriteCost(relation, rw.Difference.GetBase(), depth) + e.rewriteCost(relation, rw.Difference.GetSubtract(), depth)
+	default:
+		return tupleToUsersetCost
+	}
+}
+
+// orderByCost returns the children of a union rewrite of relation, ordered from the cheapest to resolve to the most
+// expensive, the children of equal cost keeping the order of the model.
+func orderByCost(typesys *typesystem.TypeSystem, objectType, relation string, costs RelationCosts, children []*openfgav1.Userset) []*openfgav1.Userset {
+	estimator := &rewriteCostEstimator{typesys: typesys, objectType: objectType, costs: costs}
+
+	type costedChild struct {
+		child *openfgav1.Userset
+		cost  float64
+	}
+	costed := make([]costedChild, 0, len(children))
+	for _, child := range children {
+		costed = append(costed, costedChild{child: child, cost: estimator.rewriteCost(relation, child, 0)})
+	}
+	slices.SortStableFunc(costed, func(a, b costedChild) int {
+		return cmp.Compare(a.cost, b.cost)
+	})
+
+	ordered := make([]*openfgav1.Userset, 0, len(children))
+	for _, c := range costed {
+		ordered = append(ordered, c.child)
+	}
+	return ordered
+}
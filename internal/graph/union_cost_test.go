@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestOrderByCost(t *testing.T) {
+	model := parser.MustTransformDSLToProto(`
+				model
+					schema 1.1
+
+				type user
+				type group
+					relations
+						define member: [user]
+				type folder
+					relations
+						define viewer: [user]
+				type document
+					relations
+						define parent: [folder]
+						define editor: [user]
+						define owner: [user] and editor
+						define viewer: [user, group#member] or owner or viewer from parent or editor`)
+
+	ts, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	rel, err := ts.GetRelation("document", "viewer")
+	require.NoError(t, err)
+	children := rel.GetRewrite().GetUnion().GetChild()
+
+	describe := func(ordered []*openfgav1.Userset) []string {
+		names := make([]string, 0, len(ordered))
+		for _, child := range ordered {
+			switch rw := child.GetUserset().(type) {
+			case *openfgav1.Userset_This:
+				names = append(names, "this")
+			case *openfgav1.Userset_ComputedUserset:
+				names = append(names, rw.ComputedUserset.GetRelation())
+			case *openfgav1.Userset_TupleToUserset:
+				names = append(names, rw.TupleToUserset.GetComputedUserset().GetRelation()+" from "+rw.TupleToUserset.GetTupleset().GetRelation())
+			}
+		}
+		return names
+	}
+
+	t.Run("estimated_from_the_model", func(t *testing.T) {
+		ordered := orderByCost(ts, "document", "viewer", nil, children)
+		// editor: 0.5 + 1, this: 1 + 2, owner: 0.5 + 1 + 1.5, viewer from parent: 3
+		require.Equal(t, []string{"editor", "this", "owner", "viewer from parent"}, describe(ordered))
+	})
+
+	t.Run("known_costs_override_the_estimates", func(t *testing.T) {
+		ordered := orderByCost(ts, "document", "viewer", RelationCosts{"document#editor": 10, "document#owner": 0}, children)
+		require.Equal(t, []string{"owner", "this", "viewer from parent", "editor"}, describe(ordered))
+	})
+
+	t.Run("does_not_modify_the_model", func(t *testing.T) {
+		orderByCost(ts, "document", "viewer", nil, children)
+		require.Equal(t, []string{"this", "owner", "viewer from parent", "editor"}, describe(children))
+	})
+}
@@ -0,0 +1,166 @@
+// Package loadshedding implements an admission controller that rejects the least important requests
+// while the server is overloaded, instead of letting every request time out.
+package loadshedding
+
+import (
+	"context"
+	"path"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+)
+
+// Priority is the importance of a request. Requests of a lower priority are shed first.
+type Priority int
+
+const (
+	// PriorityLow is the priority of the requests that are the most expensive to serve, such as ListObjects.
+	PriorityLow Priority = iota
+	// PriorityNormal is the priority of the requests that are neither low nor high priority.
+	PriorityNormal
+	// PriorityHigh is the priority of Check and Write requests, which are on the critical path of the clients.
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// shedThresholds is the fraction of the capacity in flight above which requests of each priority are shed.
+var shedThresholds = map[Priority]float64{
+	PriorityLow:    0.6,
+	PriorityNormal: 0.85,
+	PriorityHigh:   1,
+}
+
+var (
+	shedRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "shed_requests_count",
+		Help:      "The total number of requests rejected by the admission controller because the server is overloaded.",
+	}, []string{"grpc_method", "priority"})
+
+	inFlightCostGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "admission_controller_in_flight_cost",
+		Help:      "The number of in-flight dispatches and datastore reads, as last observed by the admission controller.",
+	})
+)
+
+// MethodPriority returns the priority of an API method.
+func MethodPriority(method string) Priority {
+	switch apimethod.APIMethod(method) {
+	case apimethod.ListObjects, apimethod.StreamedListObjects, apimethod.StreamedRead, apimethod.ListUsers, apimethod.Expand, apimethod.ReadChanges:
+		return PriorityLow
+	case apimethod.Check, apimethod.BatchCheck, apimethod.Write:
+		return PriorityHigh
+	default:
+		return PriorityNormal
+	}
+}
+
+// AdmissionController tracks the cost of the work in flight, i.e. the number of in-flight dispatches and
+// datastore reads, and rejects new requests once it exceeds the share of the capacity allowed for their
+// priority.
+//
+// A nil *AdmissionController is valid and admits every request.
+type AdmissionController struct {
+	capacity int64
+	inFlight atomic.Int64
+}
+
+// NewAdmissionController returns an AdmissionController that sheds every request once capacity dispatches
+// and datastore reads are in flight.
+func NewAdmissionController(capacity int64) *AdmissionController {
+	return &AdmissionController{capacity: capacity}
+}
+
+// Admit returns an Unavailable error if a request to method must be shed.
+func (a *AdmissionController) Admit(method string) error {
+	if a == nil {
+		return nil
+	}
+
+	inFlight := a.inFlight.Load()
+	inFlightCostGauge.Set(float64(inFlight))
+
+	priority := MethodPriority(method)
+	if float64(inFlight) < shedThresholds[priority]*float64(a.capacity) {
+		return nil
+	}
+
+	shedRequestsCounter.WithLabelValues(method, priority.String()).Inc()
+	return status.Errorf(codes.Unavailable, "the server is overloaded, %s requests are temporarily rejected", method)
+}
+
+// track records the start of a dispatch or datastore read, and returns the function recording its end.
+func (a *AdmissionController) track() func() {
+	a.inFlight.Add(1)
+	return func() {
+		a.inFlight.Add(-1)
+	}
+}
+
+type ctxKey struct{}
+
+// ContextWithAdmissionController returns a copy of ctx in which the work of the request is tracked by a.
+func ContextWithAdmissionController(ctx context.Context, a *AdmissionController) context.Context {
+	return context.WithValue(ctx, ctxKey{}, a)
+}
+
+// Track records the start of a dispatch or datastore read of the request of ctx, and returns the function
+// recording its end. It is a no-op if the request is not tracked by an admission controller.
+func Track(ctx context.Context) func() {
+	a, ok := ctx.Value(ctxKey{}).(*AdmissionController)
+	if !ok || a == nil {
+		return func() {}
+	}
+	return a.track()
+}
+
+// NewUnaryInterceptor returns a grpc.UnaryServerInterceptor that sheds the requests rejected by a, and tracks
+// the work of the admitted ones.
+func NewUnaryInterceptor(a *AdmissionController) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.Admit(path.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ContextWithAdmissionController(ctx, a), req)
+	}
+}
+
+// NewStreamingInterceptor is the streaming equivalent of NewUnaryInterceptor.
+func NewStreamingInterceptor(a *AdmissionController) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.Admit(path.Base(info.FullMethod)); err != nil {
+			return err
+		}
+		return handler(srv, &trackedStream{
+			ServerStream: stream,
+			ctx:          ContextWithAdmissionController(stream.Context(), a),
+		})
+	}
+}
+
+type trackedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,81 @@
+package loadshedding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdmissionController(t *testing.T) {
+	a := NewAdmissionController(100)
+	ctx := ContextWithAdmissionController(context.Background(), a)
+
+	load := func(n int) func() {
+		releases := make([]func(), 0, n)
+		for i := 0; i < n; i++ {
+			releases = append(releases, Track(ctx))
+		}
+		return func() {
+			for _, release := range releases {
+				release()
+			}
+		}
+	}
+
+	require.NoError(t, a.Admit("ListObjects"))
+	require.NoError(t, a.Admit("Check"))
+
+	release := load(60)
+	require.Equal(t, codes.Unavailable, status.Code(a.Admit("ListObjects")))
+	require.NoError(t, a.Admit("ReadAuthorizationModel"))
+	require.NoError(t, a.Admit("Check"))
+
+	releaseMore := load(25)
+	require.Equal(t, codes.Unavailable, status.Code(a.Admit("ReadAuthorizationModel")))
+	require.NoError(t, a.Admit("Check"))
+
+	releaseAll := load(15)
+	require.Equal(t, codes.Unavailable, status.Code(a.Admit("Check")))
+
+	releaseAll()
+	releaseMore()
+	release()
+	require.Zero(t, a.inFlight.Load())
+	require.NoError(t, a.Admit("ListObjects"))
+}
+
+func TestTrack_WithoutAdmissionController(t *testing.T) {
+	release := Track(context.Background())
+	require.NotNil(t, release)
+	release()
+
+	var a *AdmissionController
+	require.NoError(t, a.Admit("ListObjects"))
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	a := NewAdmissionController(1)
+	interceptor := NewUnaryInterceptor(a)
+	info := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}
+
+	var inFlight int64
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		// the work of the admitted request is tracked
+		defer Track(ctx)()
+		inFlight = a.inFlight.Load()
+		return "ok", nil
+	}
+
+	resp, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+	require.Equal(t, int64(1), inFlight)
+
+	defer Track(ContextWithAdmissionController(context.Background(), a))()
+	_, err = interceptor(context.Background(), nil, info, handler)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
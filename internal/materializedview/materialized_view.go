@@ -0,0 +1,438 @@
+// Package materializedview maintains, in the background, the users of hot relations of some objects, e.g. the
+// members of 'org:acme#member', so that Check answers them with a lookup instead of resolving the graph.
+package materializedview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/server/commands/listusers"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+const (
+	defaultRefreshInterval = 5 * time.Second
+	defaultMaxStaleness    = time.Minute
+	defaultHorizonOffset   = time.Second
+	defaultQueryTimeout    = 30 * time.Second
+)
+
+var (
+	_ graph.MaterializedViews = (*Views)(nil)
+
+	refreshDurationHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace:                       build.ProjectName,
+		Name:                            "materialized_view_refresh_duration_ms",
+		Help:                            "The duration (in ms) of recomputing the materialized views of a store.",
+		Buckets:                         []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000, 10000, 30000},
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: time.Hour,
+	})
+
+	stalenessGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "materialized_view_staleness_ms",
+		Help:      "How far (in ms) the most stale store of every materialized view is behind the changes of the store. Check resolves a view with the graph while it is staler than the max staleness.",
+	}, []string{"view"})
+
+	syncErrorCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "materialized_view_sync_error_count",
+		Help:      "The total number of failures to bring the materialized views of a store up to date.",
+	})
+)
+
+// Option defines an option that can be used to change the behavior of [Views].
+type Option func(*Views)
+
+// WithLogger sets the logger of the views.
+func WithLogger(logger logger.Logger) Option {
+	return func(v *Views) {
+		v.logger = logger
+	}
+}
+
+// WithRefreshInterval sets how often the changes of the stores are checked, and their views recomputed if needed.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(v *Views) {
+		v.refreshInterval = interval
+	}
+}
+
+// WithMaxStaleness sets how far behind the changes of a store its views may be for them to be used.
+func WithMaxStaleness(maxStaleness time.Duration) Option {
+	return func(v *Views) {
+		v.maxStaleness = maxStaleness
+	}
+}
+
+// WithHorizonOffset sets how old the changes must be to be considered, so that the changes of transactions committed
+// out of order are not skipped.
+func WithHorizonOffset(offset time.Duration) Option {
+	return func(v *Views) {
+		v.horizonOffset = offset
+	}
+}
+
+// WithQueryTimeout sets the timeout of computing the users of a view. A view whose users are not all found within
+// the timeout is not refreshed.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(v *Views) {
+		v.queryTimeout = timeout
+	}
+}
+
+// Views are the [graph.MaterializedViews] of every store: the users of every configured object#relation, as computed
+// by ListUsers with the latest model of the store. The views of a store are recomputed in the background whenever its
+// changelog or its latest model changes, so they are eventually consistent, and are not used while they were last
+// brought up to date more than the max staleness ago.
+//
+// The views are computed only for the models without conditions, as their users may depend on the context of the
+// requests. They are held in memory, so their memory grows with the users of the relations.
+type Views struct {
+	datastore storage.OpenFGADatastore
+	views     []view
+
+	logger          logger.Logger
+	refreshInterval time.Duration
+	maxStaleness    time.Duration
+	horizonOffset   time.Duration
+	queryTimeout    time.Duration
+
+	mu     sync.RWMutex
+	stores map[string]*storeViews
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+type view struct {
+	objectType string
+	objectID   string
+	relation   string
+}
+
+func (v view) String() string {
+	return tuple.ToObjectRelationString(tuple.BuildObject(v.objectType, v.objectID), v.relation)
+}
+
+// New starts maintaining the views, given as 'object#relation', of every store of datastore. It returns an error if a
+// view is not in that form.
+func New(datastore storage.OpenFGADatastore, views []string, opts ...Option) (*Views, error) {
+	v := &Views{
+		datastore:       datastore,
+		views:           make([]view, 0, len(views)),
+		logger:          logger.NewNoopLogger(),
+		refreshInterval: defaultRefreshInterval,
+		maxStaleness:    defaultMaxStaleness,
+		horizonOffset:   defaultHorizonOffset,
+		queryTimeout:    defaultQueryTimeout,
+		stores:          map[string]*storeViews{},
+		stop:            make(chan struct{}),
+	}
+
+	for _, spec := range views {
+		object, relation := tuple.SplitObjectRelation(spec)
+		objectType, objectID := tuple.SplitObject(object)
+		if objectType == "" || objectID == "" || relation == "" || tuple.IsTypedWildcard(object) {
+			return nil, fmt.Errorf("invalid materialized view '%s', expected 'object#relation'", spec)
+		}
+		v.views = append(v.views, view{objectType: objectType, objectID: objectID, relation: relation})
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	v.wg.Add(1)
+	go v.run()
+
+	return v, nil
+}
+
+// Close stops maintaining the views and waits for the current sync, if any, to be interrupted.
+func (v *Views) Close() {
+	v.once.Do(func() { close(v.stop) })
+	v.wg.Wait()
+}
+
+// Lookup see [graph.MaterializedViews].
+func (v *Views) Lookup(storeID, modelID, object, relation, user string) (allowed, ok bool) {
+	v.mu.RLock()
+	store, ok := v.stores[storeID]
+	v.mu.RUnlock()
+	if !ok {
+		return false, false
+	}
+
+	snap := store.snapshot.Load()
+	if snap == nil || snap.modelID != modelID || time.Since(snap.syncedAt) > v.maxStaleness {
+		return false, false
+	}
+
+	users, ok := snap.users[tuple.ToObjectRelationString(object, relation)]
+	if !ok {
+		return false, false
+	}
+
+	if _, ok := users[user]; ok {
+		return true, true
+	}
+	// the users found through a typed wildcard may still be excluded, e.g. by 'but not'
+	if _, ok := users[tuple.TypedPublicWildcard(tuple.GetType(user))]; ok {
+		return false, false
+	}
+	return false, true
+}
+
+func (v *Views) run() {
+	defer v.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-v.stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(v.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		v.sync(ctx)
+
+		select {
+		case <-v.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync tracks the stores created since the last sync, drops the deleted ones, and brings the views of every store
+// up to date.
+func (v *Views) sync(ctx context.Context) {
+	storeIDs, err := v.listStores(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			v.logger.Warn("failed to list the stores of the materialized views", zap.Error(err))
+		}
+		return
+	}
+
+	v.mu.Lock()
+	stores := make(map[string]*storeViews, len(storeIDs))
+	for _, storeID := range storeIDs {
+		store, ok := v.stores[storeID]
+		if !ok {
+			store = &storeViews{}
+		}
+		stores[storeID] = store
+	}
+	v.stores = stores
+	v.mu.Unlock()
+
+	for storeID, store := range stores {
+		if err := v.syncStore(ctx, storeID, store); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			syncErrorCounter.Inc()
+			v.logger.Warn("failed to bring the materialized views of a store up to date",
+				zap.String("store_id", storeID),
+				zap.Error(err))
+		}
+	}
+
+	v.reportStaleness(stores)
+}
+
+func (v *Views) listStores(ctx context.Context) ([]string, error) {
+	var storeIDs []string
+	var continuationToken string
+	for {
+		stores, token, err := v.datastore.ListStores(ctx, storage.ListStoresOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, continuationToken),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, store := range stores {
+			storeIDs = append(storeIDs, store.GetId())
+		}
+		if token == "" {
+			return storeIDs, nil
+		}
+		continuationToken = token
+	}
+}
+
+// syncStore recomputes the views of the store if its changelog or its latest model changed since they were last
+// computed, and otherwise marks them as up to date.
+func (v *Views) syncStore(ctx context.Context, storeID string, store *storeViews) error {
+	// the changes older than the horizon are all accounted for once the changelog is read up to its end
+	syncedAt := time.Now().Add(-v.horizonOffset)
+
+	changed, token, err := v.readChanges(ctx, storeID, store.token)
+	if err != nil {
+		return err
+	}
+
+	model, err := v.datastore.FindLatestAuthorizationModel(ctx, storeID)
+	if errors.Is(err, storage.ErrNotFound) {
+		store.snapshot.Store(nil)
+		store.token = token
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	previous := store.snapshot.Load()
+	if previous != nil && !changed && previous.modelID == model.GetId() {
+		store.snapshot.Store(&snapshot{modelID: previous.modelID, users: previous.users, syncedAt: syncedAt})
+		return nil
+	}
+
+	start := time.Now()
+	users, err := v.compute(ctx, storeID, model)
+	if err != nil {
+		return err
+	}
+	refreshDurationHistogram.Observe(float64(time.Since(start).Milliseconds()))
+
+	store.snapshot.Store(&snapshot{modelID: model.GetId(), users: users, syncedAt: syncedAt})
+	// the token only moves forward once the changes are accounted for, so that a failed refresh is retried
+	store.token = token
+	return nil
+}
+
+// readChanges reads the changelog of the store from token up to its end, and returns whether it had any changes and
+// the token of its end.
+func (v *Views) readChanges(ctx context.Context, storeID, token string) (bool, string, error) {
+	changed := false
+	for {
+		changes, next, err := v.datastore.ReadChanges(ctx, storeID,
+			storage.ReadChangesFilter{HorizonOffset: v.horizonOffset},
+			storage.ReadChangesOptions{Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, token)},
+		)
+		if errors.Is(err, storage.ErrNotFound) {
+			return changed, token, nil
+		}
+		if err != nil {
+			return false, "", err
+		}
+
+		changed = changed || len(changes) > 0
+		token = next
+		if len(changes) < storage.DefaultPageSize {
+			return changed, token, nil
+		}
+	}
+}
+
+// compute returns the users of the views defined by model, keyed by 'object#relation'. It returns an error if the
+// users of a view cannot all be found.
+func (v *Views) compute(ctx context.Context, storeID string, model *openfgav1.AuthorizationModel) (map[string]map[string]struct{}, error) {
+	users := map[string]map[string]struct{}{}
+	if len(model.GetConditions()) > 0 {
+		return users, nil
+	}
+
+	typesys, err := typesystem.NewAndValidate(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	ctx = typesystem.ContextWithTypesystem(ctx, typesys)
+
+	ctx, cancel := context.WithTimeout(ctx, v.queryTimeout)
+	defer cancel()
+
+	for _, view := range v.views {
+		if _, err := typesys.GetRelation(view.objectType, view.relation); err != nil {
+			continue
+		}
+
+		viewUsers := map[string]struct{}{}
+		for _, typeDefinition := range model.GetTypeDefinitions() {
+			query := listusers.NewListUsersQuery(v.datastore, nil,
+				listusers.WithListUsersQueryLogger(v.logger),
+				listusers.WithListUsersMaxResults(0),
+				listusers.WithListUsersDeadline(0),
+			)
+			resp, err := query.ListUsers(ctx, &openfgav1.ListUsersRequest{
+				StoreId:              storeID,
+				AuthorizationModelId: model.GetId(),
+				Object:               &openfgav1.Object{Type: view.objectType, Id: view.objectID},
+				Relation:             view.relation,
+				UserFilters:          []*openfgav1.UserTypeFilter{{Type: typeDefinition.GetType()}},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute the view '%s': %w", view, err)
+			}
+			// ListUsers returns the users found so far once its context is done
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("failed to compute the view '%s': %w", view, err)
+			}
+
+			for _, user := range resp.GetUsers() {
+				viewUsers[tuple.UserProtoToString(user)] = struct{}{}
+			}
+		}
+		users[view.String()] = viewUsers
+	}
+	return users, nil
+}
+
+// reportStaleness sets the staleness of every view to that of its most stale store.
+func (v *Views) reportStaleness(stores map[string]*storeViews) {
+	now := time.Now()
+	for _, view := range v.views {
+		key := view.String()
+		var staleness time.Duration
+		for _, store := range stores {
+			snap := store.snapshot.Load()
+			if snap == nil {
+				continue
+			}
+			if _, ok := snap.users[key]; ok {
+				staleness = max(staleness, now.Sub(snap.syncedAt))
+			}
+		}
+		stalenessGauge.WithLabelValues(key).Set(float64(staleness.Milliseconds()))
+	}
+}
+
+// snapshot holds the users of the views of a store computed with the model modelID, once its changes up to syncedAt
+// were accounted for. It is not modified once published.
+type snapshot struct {
+	modelID string
+	// users holds the users of every view, keyed by 'object#relation' and user, e.g. 'user:anne' or 'user:*'. The
+	// objects of a type with a typed wildcard are resolved with the graph unless they are found themselves.
+	users    map[string]map[string]struct{}
+	syncedAt time.Time
+}
+
+// storeViews holds the views of a store. token is only accessed by the sync.
+type storeViews struct {
+	// token is the changelog continuation token up to which the changes of the store are accounted for.
+	token    string
+	snapshot atomic.Pointer[snapshot]
+}
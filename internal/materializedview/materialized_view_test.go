@@ -0,0 +1,147 @@
+package materializedview
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestViews(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	store, err := ds.CreateStore(ctx, &openfgav1.Store{Id: ulid.Make().String(), Name: "store"})
+	require.NoError(t, err)
+
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+
+		type user
+		type group
+			relations
+				define member: [user, group#member]
+		type org
+			relations
+				define blocked: [user]
+				define member: [user, user:*, group#member] but not blocked`)
+	model.Id = ulid.Make().String()
+	require.NoError(t, ds.WriteAuthorizationModel(ctx, store.GetId(), model))
+
+	err = ds.Write(ctx, store.GetId(), nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("org:acme", "member", "user:anne"),
+		tuple.NewTupleKey("org:acme", "member", "group:eng#member"),
+		tuple.NewTupleKey("group:eng", "member", "user:bob"),
+		tuple.NewTupleKey("org:public", "member", "user:*"),
+		tuple.NewTupleKey("org:public", "blocked", "user:bob"),
+	})
+	require.NoError(t, err)
+
+	_, err = New(ds, []string{"org#member"})
+	require.Error(t, err)
+
+	views, err := New(ds, []string{"org:acme#member", "org:public#member"}, WithRefreshInterval(10*time.Millisecond), WithHorizonOffset(0))
+	require.NoError(t, err)
+	t.Cleanup(views.Close)
+
+	lookup := func(object, user string) (bool, bool) {
+		return views.Lookup(store.GetId(), model.GetId(), object, "member", user)
+	}
+
+	require.Eventually(t, func() bool {
+		_, ok := lookup("org:acme", "user:anne")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	allowed, ok := lookup("org:acme", "user:anne")
+	require.True(t, ok)
+	require.True(t, allowed)
+
+	allowed, ok = lookup("org:acme", "user:bob")
+	require.True(t, ok)
+	require.True(t, allowed)
+
+	allowed, ok = lookup("org:acme", "user:charlie")
+	require.True(t, ok)
+	require.False(t, allowed)
+
+	// the users of a wildcard may be excluded, so they are resolved with the graph
+	_, ok = lookup("org:public", "user:charlie")
+	require.False(t, ok)
+
+	// neither the objects without a view nor the other models are answered
+	_, ok = lookup("org:other", "user:anne")
+	require.False(t, ok)
+	_, ok = views.Lookup(store.GetId(), ulid.Make().String(), "org:acme", "member", "user:anne")
+	require.False(t, ok)
+
+	t.Run("recomputes_the_views_on_changes", func(t *testing.T) {
+		err := ds.Write(ctx, store.GetId(), nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("group:eng", "member", "user:charlie"),
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			allowed, _ := lookup("org:acme", "user:charlie")
+			return allowed
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("ignores_the_models_with_conditions", func(t *testing.T) {
+		conditional := parser.MustTransformDSLToProto(`
+			model
+				schema 1.1
+
+			type user
+			type org
+				relations
+					define member: [user with cond]
+
+			condition cond(x: int) {
+				x < 100
+			}`)
+		conditional.Id = ulid.Make().String()
+		require.NoError(t, ds.WriteAuthorizationModel(ctx, store.GetId(), conditional))
+
+		require.Eventually(t, func() bool {
+			_, ok := views.Lookup(store.GetId(), conditional.GetId(), "org:acme", "member", "user:anne")
+			_, previousOK := lookup("org:acme", "user:anne")
+			return !ok && !previousOK
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("is_not_used_once_stale", func(t *testing.T) {
+		storeID := ulid.Make().String()
+		_, err := ds.CreateStore(ctx, &openfgav1.Store{Id: storeID, Name: "stale"})
+		require.NoError(t, err)
+		require.NoError(t, ds.WriteAuthorizationModel(ctx, storeID, model))
+
+		stale, err := New(ds, []string{"org:acme#member"}, WithRefreshInterval(time.Hour), WithHorizonOffset(0), WithMaxStaleness(50*time.Millisecond))
+		require.NoError(t, err)
+		t.Cleanup(stale.Close)
+
+		require.Eventually(t, func() bool {
+			_, ok := stale.Lookup(storeID, model.GetId(), "org:acme", "member", "user:anne")
+			return ok
+		}, time.Second, time.Millisecond)
+		require.Eventually(t, func() bool {
+			_, ok := stale.Lookup(storeID, model.GetId(), "org:acme", "member", "user:anne")
+			return !ok
+		}, time.Second, 10*time.Millisecond)
+	})
+}
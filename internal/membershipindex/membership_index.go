@@ -0,0 +1,428 @@
+// Package membershipindex maintains, in the background, an index of the transitive members of nested relations,
+// such as 'define member: [user, group#member]', so that Check does not resolve every level of nesting.
+package membershipindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+const (
+	defaultRefreshInterval = time.Second
+	defaultMaxStaleness    = 10 * time.Second
+	defaultHorizonOffset   = time.Second
+)
+
+var (
+	_ graph.MembershipIndex = (*Index)(nil)
+
+	syncDurationHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace:                       build.ProjectName,
+		Name:                            "membership_index_sync_duration_ms",
+		Help:                            "The duration (in ms) of applying the changes of all the stores to the membership index.",
+		Buckets:                         []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000, 10000},
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: time.Hour,
+	})
+
+	indexedStoresGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "membership_index_stores",
+		Help:      "The number of stores whose memberships are indexed.",
+	})
+
+	syncErrorCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "membership_index_sync_error_count",
+		Help:      "The total number of failures to apply the changes of a store to the membership index.",
+	})
+)
+
+// Option defines an option that can be used to change the behavior of an [Index].
+type Option func(*Index)
+
+// WithLogger sets the logger of the index.
+func WithLogger(logger logger.Logger) Option {
+	return func(i *Index) {
+		i.logger = logger
+	}
+}
+
+// WithRefreshInterval sets how often the changes of the stores are applied to the index.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(i *Index) {
+		i.refreshInterval = interval
+	}
+}
+
+// WithMaxStaleness sets how far behind the changes of a store its index may be for it to be used.
+func WithMaxStaleness(maxStaleness time.Duration) Option {
+	return func(i *Index) {
+		i.maxStaleness = maxStaleness
+	}
+}
+
+// WithHorizonOffset sets how old the changes must be to be applied, so that the changes of transactions committed
+// out of order are not skipped.
+func WithHorizonOffset(offset time.Duration) Option {
+	return func(i *Index) {
+		i.horizonOffset = offset
+	}
+}
+
+// Index is a [graph.MembershipIndex] of the members of the relations of every store, e.g. 'group#member', and of
+// the members of their usersets of the same relation, e.g. 'group:eng#member'. It is built and kept up to date in
+// the background by replaying the changelog of every store, so it is eventually consistent, and is not used for a
+// store whose changes were last applied more than the max staleness ago.
+//
+// The index holds the memberships of every object of the indexed relations, so its memory grows with their tuples.
+// Conditional tuples and wildcards are not indexed: the memberships relying on them are resolved with the graph.
+type Index struct {
+	datastore storage.OpenFGADatastore
+	// relations are the indexed relations, grouped by object type.
+	relations map[string][]string
+
+	logger          logger.Logger
+	refreshInterval time.Duration
+	maxStaleness    time.Duration
+	horizonOffset   time.Duration
+
+	mu     sync.RWMutex
+	stores map[string]*storeIndex
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// New starts indexing relations, given as 'type#relation', of every store of datastore. It returns an error if a
+// relation is not in that form.
+func New(datastore storage.OpenFGADatastore, relations []string, opts ...Option) (*Index, error) {
+	i := &Index{
+		datastore:       datastore,
+		relations:       make(map[string][]string, len(relations)),
+		logger:          logger.NewNoopLogger(),
+		refreshInterval: defaultRefreshInterval,
+		maxStaleness:    defaultMaxStaleness,
+		horizonOffset:   defaultHorizonOffset,
+		stores:          map[string]*storeIndex{},
+		stop:            make(chan struct{}),
+	}
+
+	for _, relation := range relations {
+		objectType, rel := tuple.SplitObjectRelation(relation)
+		if objectType == "" || rel == "" || tuple.IsObjectRelation(objectType) {
+			return nil, fmt.Errorf("invalid membership index relation '%s', expected 'type#relation'", relation)
+		}
+		i.relations[objectType] = append(i.relations[objectType], rel)
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	i.wg.Add(1)
+	go i.run()
+
+	return i, nil
+}
+
+// Close stops indexing and waits for the current sync, if any, to be interrupted.
+func (i *Index) Close() {
+	i.once.Do(func() { close(i.stop) })
+	i.wg.Wait()
+}
+
+// IsMember see [graph.MembershipIndex].
+func (i *Index) IsMember(storeID, object, relation, user string) bool {
+	i.mu.RLock()
+	store, ok := i.stores[storeID]
+	i.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	snap := store.snapshot.Load()
+	if snap == nil || time.Since(snap.syncedAt) > i.maxStaleness {
+		return false
+	}
+
+	_, ok = snap.members[tuple.ToObjectRelationString(tuple.GetType(object), relation)][object][user]
+	return ok
+}
+
+func (i *Index) run() {
+	defer i.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-i.stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(i.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		i.sync(ctx)
+
+		select {
+		case <-i.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync indexes the stores created since the last sync, drops the deleted ones, and applies the changes of every
+// store.
+func (i *Index) sync(ctx context.Context) {
+	start := time.Now()
+
+	storeIDs, err := i.listStores(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			i.logger.Warn("failed to list the stores of the membership index", zap.Error(err))
+		}
+		return
+	}
+
+	i.mu.Lock()
+	stores := make(map[string]*storeIndex, len(storeIDs))
+	for _, storeID := range storeIDs {
+		store, ok := i.stores[storeID]
+		if !ok {
+			store = newStoreIndex(i.relations)
+		}
+		stores[storeID] = store
+	}
+	i.stores = stores
+	i.mu.Unlock()
+	indexedStoresGauge.Set(float64(len(stores)))
+
+	for storeID, store := range stores {
+		if err := i.syncStore(ctx, storeID, store); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			syncErrorCounter.Inc()
+			i.logger.Warn("failed to apply the changes of a store to the membership index",
+				zap.String("store_id", storeID),
+				zap.Error(err))
+		}
+	}
+
+	syncDurationHistogram.Observe(float64(time.Since(start).Milliseconds()))
+}
+
+func (i *Index) listStores(ctx context.Context) ([]string, error) {
+	var storeIDs []string
+	var continuationToken string
+	for {
+		stores, token, err := i.datastore.ListStores(ctx, storage.ListStoresOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, continuationToken),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, store := range stores {
+			storeIDs = append(storeIDs, store.GetId())
+		}
+		if token == "" {
+			return storeIDs, nil
+		}
+		continuationToken = token
+	}
+}
+
+// syncStore applies the changes of the store since its last sync, and publishes its memberships if they changed.
+func (i *Index) syncStore(ctx context.Context, storeID string, store *storeIndex) error {
+	// the changes older than the horizon are all applied once the changelog is read up to its end
+	syncedAt := time.Now().Add(-i.horizonOffset)
+
+	for objectType := range i.relations {
+		for {
+			changes, token, err := i.datastore.ReadChanges(ctx, storeID,
+				storage.ReadChangesFilter{ObjectType: objectType, HorizonOffset: i.horizonOffset},
+				storage.ReadChangesOptions{Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, store.tokens[objectType])},
+			)
+			if errors.Is(err, storage.ErrNotFound) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			for _, change := range changes {
+				store.apply(change)
+			}
+			store.tokens[objectType] = token
+
+			if len(changes) < storage.DefaultPageSize {
+				break
+			}
+		}
+	}
+
+	store.publish(syncedAt)
+	return nil
+}
+
+// snapshot holds the memberships of a store, once its changes up to syncedAt were applied. It is not modified once
+// published.
+type snapshot struct {
+	// members holds the transitive members of the objects of every relation, keyed by 'type#relation' and object.
+	members  map[string]map[string]map[string]struct{}
+	syncedAt time.Time
+}
+
+// storeIndex holds the tuples of the indexed relations of a store, only accessed by the sync, and publishes their
+// memberships.
+type storeIndex struct {
+	// relations are the indexed relations, keyed by 'type#relation'.
+	relations map[string]*relationTuples
+	// tokens are the changelog continuation tokens of the indexed object types.
+	tokens map[string]string
+	// dirty are the relations whose tuples changed since the memberships were last published.
+	dirty map[string]struct{}
+
+	snapshot atomic.Pointer[snapshot]
+}
+
+// relationTuples holds the tuples of a relation whose user is an object, or a userset of the same relation.
+type relationTuples struct {
+	relation string
+	// users are the objects related to every object, e.g. 'group:eng' -> 'user:anne'.
+	users map[string]map[string]struct{}
+	// nested are the objects whose userset is related to every object, e.g. 'group:eng' -> 'group:backend' for
+	// 'group:eng#member@group:backend#member'.
+	nested map[string]map[string]struct{}
+}
+
+func newStoreIndex(relations map[string][]string) *storeIndex {
+	s := &storeIndex{
+		relations: map[string]*relationTuples{},
+		tokens:    make(map[string]string, len(relations)),
+		dirty:     map[string]struct{}{},
+	}
+	for objectType, rels := range relations {
+		for _, relation := range rels {
+			s.relations[tuple.ToObjectRelationString(objectType, relation)] = &relationTuples{
+				relation: relation,
+				users:    map[string]map[string]struct{}{},
+				nested:   map[string]map[string]struct{}{},
+			}
+		}
+	}
+	return s
+}
+
+// apply applies change to the tuples of its relation, if indexed.
+func (s *storeIndex) apply(change *openfgav1.TupleChange) {
+	tk := change.GetTupleKey()
+	objectType := tuple.GetType(tk.GetObject())
+	key := tuple.ToObjectRelationString(objectType, tk.GetRelation())
+	rel, ok := s.relations[key]
+	if !ok {
+		return
+	}
+
+	object, user := tk.GetObject(), tk.GetUser()
+	edges := rel.users
+	if tuple.IsObjectRelation(user) {
+		userObject, userRelation := tuple.SplitObjectRelation(user)
+		if tuple.GetType(userObject) != objectType || userRelation != rel.relation {
+			return
+		}
+		edges, user = rel.nested, userObject
+	} else if tuple.IsTypedWildcard(user) {
+		return
+	}
+
+	switch change.GetOperation() {
+	case openfgav1.TupleOperation_TUPLE_OPERATION_WRITE:
+		if tk.GetCondition() != nil {
+			return
+		}
+		if edges[object] == nil {
+			edges[object] = map[string]struct{}{}
+		}
+		edges[object][user] = struct{}{}
+	case openfgav1.TupleOperation_TUPLE_OPERATION_DELETE:
+		delete(edges[object], user)
+		if len(edges[object]) == 0 {
+			delete(edges, object)
+		}
+	}
+	s.dirty[key] = struct{}{}
+}
+
+// publish computes the memberships of the dirty relations, reuses those of the others, and publishes them as the
+// memberships up to syncedAt.
+func (s *storeIndex) publish(syncedAt time.Time) {
+	previous := s.snapshot.Load()
+	members := make(map[string]map[string]map[string]struct{}, len(s.relations))
+	for key, rel := range s.relations {
+		if _, ok := s.dirty[key]; ok || previous == nil {
+			members[key] = rel.closure()
+			continue
+		}
+		members[key] = previous.members[key]
+	}
+	clear(s.dirty)
+
+	s.snapshot.Store(&snapshot{members: members, syncedAt: syncedAt})
+}
+
+// closure returns the transitive members of every object of the relation.
+func (r *relationTuples) closure() map[string]map[string]struct{} {
+	objects := make(map[string]struct{}, len(r.users)+len(r.nested))
+	for object := range r.users {
+		objects[object] = struct{}{}
+	}
+	for object := range r.nested {
+		objects[object] = struct{}{}
+	}
+
+	closure := make(map[string]map[string]struct{}, len(objects))
+	for object := range objects {
+		members := map[string]struct{}{}
+		visited := map[string]struct{}{object: {}}
+		queue := []string{object}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for user := range r.users[current] {
+				members[user] = struct{}{}
+			}
+			for nested := range r.nested[current] {
+				if _, ok := visited[nested]; !ok {
+					visited[nested] = struct{}{}
+					queue = append(queue, nested)
+				}
+			}
+		}
+		if len(members) > 0 {
+			closure[object] = members
+		}
+	}
+	return closure
+}
@@ -0,0 +1,88 @@
+package membershipindex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestIndex(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	store, err := ds.CreateStore(ctx, &openfgav1.Store{Id: ulid.Make().String(), Name: "store"})
+	require.NoError(t, err)
+
+	err = ds.Write(ctx, store.GetId(), nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("group:eng", "member", "user:anne"),
+		tuple.NewTupleKey("group:backend", "member", "user:bob"),
+		tuple.NewTupleKey("group:eng", "member", "group:backend#member"),
+		tuple.NewTupleKey("group:all", "member", "group:eng#member"),
+		// cycles are resolved
+		tuple.NewTupleKey("group:backend", "member", "group:all#member"),
+		// neither wildcards nor conditional tuples are indexed
+		tuple.NewTupleKey("group:public", "member", "user:*"),
+		tuple.NewTupleKeyWithCondition("group:conditional", "member", "user:anne", "condX", nil),
+		// nor usersets of other relations
+		tuple.NewTupleKey("group:admins", "member", "group:eng#owner"),
+	})
+	require.NoError(t, err)
+
+	_, err = New(ds, []string{"group"})
+	require.Error(t, err)
+
+	index, err := New(ds, []string{"group#member"}, WithRefreshInterval(10*time.Millisecond), WithHorizonOffset(0))
+	require.NoError(t, err)
+	t.Cleanup(index.Close)
+
+	require.Eventually(t, func() bool {
+		return index.IsMember(store.GetId(), "group:all", "member", "user:bob")
+	}, time.Second, 10*time.Millisecond)
+
+	require.True(t, index.IsMember(store.GetId(), "group:all", "member", "user:anne"))
+	require.True(t, index.IsMember(store.GetId(), "group:backend", "member", "user:anne"))
+	require.False(t, index.IsMember(store.GetId(), "group:all", "member", "user:charlie"))
+	require.False(t, index.IsMember(store.GetId(), "group:public", "member", "user:anne"))
+	require.False(t, index.IsMember(store.GetId(), "group:conditional", "member", "user:anne"))
+	require.False(t, index.IsMember(store.GetId(), "group:admins", "member", "user:anne"))
+	require.False(t, index.IsMember(ulid.Make().String(), "group:all", "member", "user:bob"))
+
+	t.Run("applies_the_deletes", func(t *testing.T) {
+		err := ds.Write(ctx, store.GetId(), []*openfgav1.TupleKeyWithoutCondition{
+			tuple.TupleKeyToTupleKeyWithoutCondition(tuple.NewTupleKey("group:eng", "member", "group:backend#member")),
+		}, nil)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return !index.IsMember(store.GetId(), "group:all", "member", "user:bob")
+		}, time.Second, 10*time.Millisecond)
+		require.True(t, index.IsMember(store.GetId(), "group:all", "member", "user:anne"))
+	})
+
+	t.Run("is_not_used_once_stale", func(t *testing.T) {
+		stale, err := New(ds, []string{"group#member"}, WithRefreshInterval(time.Hour), WithHorizonOffset(0), WithMaxStaleness(50*time.Millisecond))
+		require.NoError(t, err)
+		t.Cleanup(stale.Close)
+
+		require.Eventually(t, func() bool {
+			return stale.IsMember(store.GetId(), "group:all", "member", "user:anne")
+		}, time.Second, time.Millisecond)
+		require.Eventually(t, func() bool {
+			return !stale.IsMember(store.GetId(), "group:all", "member", "user:anne")
+		}, time.Second, 10*time.Millisecond)
+	})
+}
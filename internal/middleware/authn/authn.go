@@ -4,15 +4,25 @@ import (
 	"context"
 
 	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	"github.com/openfga/openfga/internal/authn"
 	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/logger"
 )
 
-func AuthFunc(authenticator authn.Authenticator) grpcauth.AuthFunc {
+// AuthFunc authenticates the requests with authenticator, logging the failures as security events.
+func AuthFunc(authenticator authn.Authenticator, l logger.Logger) grpcauth.AuthFunc {
 	return func(ctx context.Context) (context.Context, error) {
 		claims, err := authenticator.Authenticate(ctx)
 		if err != nil {
+			method, _ := grpc.Method(ctx)
+			l.InfoWithContext(ctx, "authentication failed",
+				logger.SecurityEvent(logger.SecurityEventAuthenticationFailed),
+				zap.String("method", method),
+				zap.Error(err),
+			)
 			return nil, err
 		}
 
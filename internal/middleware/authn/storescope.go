@@ -0,0 +1,99 @@
+package authn
+
+import (
+	"context"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/authn/storetoken"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+// ErrStoreNotAllowed is returned when the credentials of the request are not allowed to perform the request on its store.
+var ErrStoreNotAllowed = status.Error(codes.PermissionDenied, "the credentials are not allowed to access this store")
+
+// requiredScopes maps each store scoped API method to the store token scope it requires.
+// Methods that are not listed cannot be called with store restricted credentials.
+var requiredScopes = map[apimethod.APIMethod]storetoken.Scope{
+	apimethod.ReadAuthorizationModel:   storetoken.ScopeRead,
+	apimethod.ReadAuthorizationModels:  storetoken.ScopeRead,
+	apimethod.Read:                     storetoken.ScopeRead,
+	apimethod.StreamedRead:             storetoken.ScopeRead,
+	apimethod.ListObjects:              storetoken.ScopeRead,
+	apimethod.StreamedListObjects:      storetoken.ScopeRead,
+	apimethod.Check:                    storetoken.ScopeRead,
+	apimethod.BatchCheck:               storetoken.ScopeRead,
+	apimethod.ListUsers:                storetoken.ScopeRead,
+	apimethod.ReadAssertions:           storetoken.ScopeRead,
+	apimethod.GetStore:                 storetoken.ScopeRead,
+	apimethod.Expand:                   storetoken.ScopeRead,
+	apimethod.ReadChanges:              storetoken.ScopeRead,
+	apimethod.Write:                    storetoken.ScopeWrite,
+	apimethod.WriteAssertions:          storetoken.ScopeAdmin,
+	apimethod.WriteAuthorizationModel:  storetoken.ScopeAdmin,
+	apimethod.DeleteAuthorizationModel: storetoken.ScopeAdmin,
+	apimethod.DeleteStore:              storetoken.ScopeAdmin,
+	apimethod.UpdateStore:              storetoken.ScopeAdmin,
+}
+
+type hasGetStoreID interface {
+	GetStoreId() string
+}
+
+// NewStoreScopeUnaryInterceptor returns a grpc.UnaryServerInterceptor that rejects requests whose
+// authenticated credentials are restricted to other stores, or lack the scope required by the method.
+// It must run after the authentication interceptor.
+func NewStoreScopeUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := enforceStoreScope(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewStoreScopeStreamingInterceptor is the streaming equivalent of NewStoreScopeUnaryInterceptor.
+// The request is checked once it has been received.
+func NewStoreScopeStreamingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &storeScopedStream{ServerStream: stream, fullMethod: info.FullMethod})
+	}
+}
+
+type storeScopedStream struct {
+	grpc.ServerStream
+	fullMethod string
+}
+
+// RecvMsg receives the request message and checks it against the credentials before handing it to the handler.
+func (s *storeScopedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return enforceStoreScope(s.Context(), s.fullMethod, m)
+}
+
+func enforceStoreScope(ctx context.Context, fullMethod string, req interface{}) error {
+	claims, ok := authclaims.AuthClaimsFromContext(ctx)
+	if !ok || (len(claims.StoreIDs) == 0 && claims.StoreScopes == nil) {
+		return nil
+	}
+
+	r, ok := req.(hasGetStoreID)
+	if !ok || !claims.AllowsStore(r.GetStoreId()) {
+		return ErrStoreNotAllowed
+	}
+
+	if claims.StoreScopes != nil {
+		scope, ok := requiredScopes[apimethod.APIMethod(path.Base(fullMethod))]
+		if !ok || !storetoken.HasScope(claims, scope) {
+			return ErrStoreNotAllowed
+		}
+	}
+
+	return nil
+}
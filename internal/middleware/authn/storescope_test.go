@@ -0,0 +1,95 @@
+package authn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+func TestStoreScopeUnaryInterceptor(t *testing.T) {
+	storeID := "01JA7QGP1A6FQ7M0T0CPWHNZ1J"
+	interceptor := NewStoreScopeUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	info := func(method string) *grpc.UnaryServerInfo {
+		return &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/" + method}
+	}
+	readOnly := &authclaims.AuthClaims{
+		StoreIDs:    []string{storeID},
+		StoreScopes: map[string]bool{"read": true},
+	}
+
+	tests := map[string]struct {
+		claims  *authclaims.AuthClaims
+		method  string
+		req     interface{}
+		allowed bool
+	}{
+		`no_claims`: {
+			method:  "Write",
+			req:     &openfgav1.WriteRequest{StoreId: storeID},
+			allowed: true,
+		},
+		`unrestricted_claims`: {
+			claims:  &authclaims.AuthClaims{Subject: "operator"},
+			method:  "CreateStore",
+			req:     &openfgav1.CreateStoreRequest{},
+			allowed: true,
+		},
+		`allowed_store_and_scope`: {
+			claims:  readOnly,
+			method:  "Check",
+			req:     &openfgav1.CheckRequest{StoreId: storeID},
+			allowed: true,
+		},
+		`other_store`: {
+			claims: readOnly,
+			method: "Check",
+			req:    &openfgav1.CheckRequest{StoreId: "01JA7QGP1A6FQ7M0T0CPWHNZ1K"},
+		},
+		`missing_scope`: {
+			claims: readOnly,
+			method: "Write",
+			req:    &openfgav1.WriteRequest{StoreId: storeID},
+		},
+		`store_restricted_without_scopes`: {
+			claims:  &authclaims.AuthClaims{StoreIDs: []string{storeID}},
+			method:  "WriteAuthorizationModel",
+			req:     &openfgav1.WriteAuthorizationModelRequest{StoreId: storeID},
+			allowed: true,
+		},
+		`store_restricted_without_scopes_other_store`: {
+			claims: &authclaims.AuthClaims{StoreIDs: []string{storeID}},
+			method: "Write",
+			req:    &openfgav1.WriteRequest{StoreId: "01JA7QGP1A6FQ7M0T0CPWHNZ1K"},
+		},
+		`not_store_scoped`: {
+			claims: readOnly,
+			method: "CreateStore",
+			req:    &openfgav1.CreateStoreRequest{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.claims != nil {
+				ctx = authclaims.ContextWithAuthClaims(ctx, test.claims)
+			}
+
+			_, err := interceptor(ctx, test.req, info(test.method), handler)
+			if test.allowed {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrStoreNotAllowed)
+			}
+		})
+	}
+}
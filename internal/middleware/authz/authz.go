@@ -0,0 +1,126 @@
+package authz
+
+import (
+	"context"
+	"path"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/openfga/openfga/internal/authz"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+// storeScopedMethods are the API methods whose authorization only depends on the store
+// in the request and the caller, so they can be enforced before reaching the handler.
+// Write (module based authorization), CreateStore and ListStores (not scoped to a store)
+// remain enforced by the handlers.
+var storeScopedMethods = map[apimethod.APIMethod]struct{}{
+	apimethod.ReadAuthorizationModel:   {},
+	apimethod.ReadAuthorizationModels:  {},
+	apimethod.Read:                     {},
+	apimethod.StreamedRead:             {},
+	apimethod.ListObjects:              {},
+	apimethod.StreamedListObjects:      {},
+	apimethod.Check:                    {},
+	apimethod.BatchCheck:               {},
+	apimethod.ListUsers:                {},
+	apimethod.WriteAssertions:          {},
+	apimethod.ReadAssertions:           {},
+	apimethod.WriteAuthorizationModel:  {},
+	apimethod.DeleteAuthorizationModel: {},
+	apimethod.GetStore:                 {},
+	apimethod.DeleteStore:              {},
+	apimethod.UpdateStore:              {},
+	apimethod.Expand:                   {},
+	apimethod.ReadChanges:              {},
+}
+
+type hasGetStoreID interface {
+	GetStoreId() string
+}
+
+// NewUnaryInterceptor returns a grpc.UnaryServerInterceptor that authorizes the caller
+// of store scoped RPCs against the access control store. Requests that pass are marked
+// so that the handlers do not authorize them a second time.
+func NewUnaryInterceptor(authorizer authz.AuthorizerInterface, logger logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, authorizer, logger, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewStreamingInterceptor returns a grpc.StreamServerInterceptor that authorizes the caller
+// of store scoped streaming RPCs once the request message has been received.
+func NewStreamingInterceptor(authorizer authz.AuthorizerInterface, logger logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authorizingStream{
+			ServerStream: stream,
+			ctx:          stream.Context(),
+			authorizer:   authorizer,
+			logger:       logger,
+			fullMethod:   info.FullMethod,
+		})
+	}
+}
+
+type authorizingStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	authorizer authz.AuthorizerInterface
+	logger     logger.Logger
+	fullMethod string
+}
+
+// Context returns the context associated with the stream, which is marked as authorized
+// after the request message has been received and authorized.
+func (s *authorizingStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg receives the request message and authorizes it before handing it to the handler.
+func (s *authorizingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	ctx, err := authorize(s.ctx, s.authorizer, s.logger, s.fullMethod, m)
+	if err != nil {
+		return err
+	}
+	s.ctx = ctx
+	return nil
+}
+
+func authorize(ctx context.Context, authorizer authz.AuthorizerInterface, l logger.Logger, fullMethod string, req interface{}) (context.Context, error) {
+	if authclaims.SkipAuthzCheckFromContext(ctx) {
+		return ctx, nil
+	}
+
+	method := apimethod.APIMethod(path.Base(fullMethod))
+	if _, ok := storeScopedMethods[method]; !ok {
+		return ctx, nil
+	}
+
+	r, ok := req.(hasGetStoreID)
+	if !ok {
+		return ctx, nil
+	}
+
+	if err := authorizer.Authorize(ctx, r.GetStoreId(), method); err != nil {
+		l.WarnWithContext(ctx, "authorization failed",
+			logger.SecurityEvent(logger.SecurityEventAuthorizationDenied),
+			zap.String("store_id", r.GetStoreId()),
+			zap.Error(err),
+			zap.String("method", method.String()),
+		)
+		return nil, authz.ErrUnauthorizedResponse
+	}
+
+	return authclaims.ContextWithSkipAuthzCheck(ctx, true), nil
+}
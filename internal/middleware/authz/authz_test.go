@@ -0,0 +1,135 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/authz"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+type fakeAuthorizer struct {
+	authz.NoopAuthorizer
+	allowed map[apimethod.APIMethod]bool
+	calls   []apimethod.APIMethod
+}
+
+func (f *fakeAuthorizer) Authorize(_ context.Context, storeID string, apiMethod apimethod.APIMethod, _ ...string) error {
+	f.calls = append(f.calls, apiMethod)
+	if storeID == "" || !f.allowed[apiMethod] {
+		return errors.New("not allowed")
+	}
+	return nil
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	msg *openfgav1.StreamedListObjectsRequest
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeServerStream) RecvMsg(m interface{}) error {
+	req := m.(*openfgav1.StreamedListObjectsRequest)
+	req.StoreId = f.msg.GetStoreId()
+	return nil
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	authorizer := &fakeAuthorizer{allowed: map[apimethod.APIMethod]bool{apimethod.Check: true}}
+	interceptor := NewUnaryInterceptor(authorizer, logger.NewNoopLogger())
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return authclaims.SkipAuthzCheckFromContext(ctx), nil
+	}
+
+	t.Run("allowed_method_reaches_handler_as_authorized", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), &openfgav1.CheckRequest{StoreId: "store"}, &grpc.UnaryServerInfo{
+			FullMethod: "/openfga.v1.OpenFGAService/Check",
+		}, handler)
+		require.NoError(t, err)
+		require.Equal(t, true, resp)
+	})
+
+	t.Run("denied_method_returns_unauthorized", func(t *testing.T) {
+		_, err := interceptor(context.Background(), &openfgav1.ReadRequest{StoreId: "store"}, &grpc.UnaryServerInfo{
+			FullMethod: "/openfga.v1.OpenFGAService/Read",
+		}, handler)
+		require.ErrorIs(t, err, authz.ErrUnauthorizedResponse)
+	})
+
+	t.Run("denied_method_is_logged_as_a_warning", func(t *testing.T) {
+		observerLogger, logs := observer.New(zap.InfoLevel)
+		interceptor := NewUnaryInterceptor(authorizer, &logger.ZapLogger{Logger: zap.New(observerLogger)})
+
+		_, err := interceptor(context.Background(), &openfgav1.ReadRequest{StoreId: "store"}, &grpc.UnaryServerInfo{
+			FullMethod: "/openfga.v1.OpenFGAService/Read",
+		}, handler)
+		require.ErrorIs(t, err, authz.ErrUnauthorizedResponse)
+		require.Equal(t, 1, logs.Len())
+		require.Equal(t, zap.WarnLevel, logs.All()[0].Level)
+		require.Equal(t, "authorization failed", logs.All()[0].Message)
+	})
+
+	t.Run("write_is_left_to_the_handler", func(t *testing.T) {
+		authorizer.calls = nil
+		resp, err := interceptor(context.Background(), &openfgav1.WriteRequest{StoreId: "store"}, &grpc.UnaryServerInfo{
+			FullMethod: "/openfga.v1.OpenFGAService/Write",
+		}, handler)
+		require.NoError(t, err)
+		require.Equal(t, false, resp)
+		require.Empty(t, authorizer.calls)
+	})
+
+	t.Run("already_authorized_context_is_not_checked_again", func(t *testing.T) {
+		authorizer.calls = nil
+		ctx := authclaims.ContextWithSkipAuthzCheck(context.Background(), true)
+		_, err := interceptor(ctx, &openfgav1.ReadRequest{StoreId: "store"}, &grpc.UnaryServerInfo{
+			FullMethod: "/openfga.v1.OpenFGAService/Read",
+		}, handler)
+		require.NoError(t, err)
+		require.Empty(t, authorizer.calls)
+	})
+}
+
+func TestStreamingInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/openfga.v1.OpenFGAService/StreamedListObjects"}
+
+	t.Run("allowed", func(t *testing.T) {
+		authorizer := &fakeAuthorizer{allowed: map[apimethod.APIMethod]bool{apimethod.StreamedListObjects: true}}
+		interceptor := NewStreamingInterceptor(authorizer, logger.NewNoopLogger())
+
+		stream := &fakeServerStream{ctx: context.Background(), msg: &openfgav1.StreamedListObjectsRequest{StoreId: "store"}}
+		err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+			require.NoError(t, ss.RecvMsg(&openfgav1.StreamedListObjectsRequest{}))
+			require.True(t, authclaims.SkipAuthzCheckFromContext(ss.Context()))
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []apimethod.APIMethod{apimethod.StreamedListObjects}, authorizer.calls)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		authorizer := &fakeAuthorizer{}
+		interceptor := NewStreamingInterceptor(authorizer, logger.NewNoopLogger())
+
+		stream := &fakeServerStream{ctx: context.Background(), msg: &openfgav1.StreamedListObjectsRequest{StoreId: "store"}}
+		err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return ss.RecvMsg(&openfgav1.StreamedListObjectsRequest{})
+		})
+		require.ErrorIs(t, err, authz.ErrUnauthorizedResponse)
+	})
+}
@@ -0,0 +1,166 @@
+// Package limitoverrides implements a gRPC interceptor that lets trusted callers override the resolution
+// limits of their requests through metadata, within the ceilings configured on the server.
+package limitoverrides
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+const (
+	// MaxResolveDepthHeader is the metadata key overriding the resolve node limit of Check, ListObjects and
+	// ListUsers requests.
+	MaxResolveDepthHeader = "openfga-max-resolve-depth"
+	// ListObjectsDeadlineHeader is the metadata key overriding the deadline of ListObjects requests, as a
+	// duration (e.g. '10s').
+	ListObjectsDeadlineHeader = "openfga-list-objects-deadline"
+)
+
+// Ceilings are the largest values the overrides may take. A zero ceiling does not allow the override.
+type Ceilings struct {
+	MaxResolveDepth     uint32
+	ListObjectsDeadline time.Duration
+}
+
+// Overrides are the limits a request asked for. Zero values leave the server defaults in place.
+type Overrides struct {
+	MaxResolveDepth     uint32
+	ListObjectsDeadline time.Duration
+}
+
+type ctxKey struct{}
+
+// ContextWithOverrides returns a copy of ctx holding the overrides of its request.
+func ContextWithOverrides(ctx context.Context, overrides Overrides) context.Context {
+	return context.WithValue(ctx, ctxKey{}, overrides)
+}
+
+// FromContext returns the overrides of the request of ctx, if any.
+func FromContext(ctx context.Context) Overrides {
+	overrides, _ := ctx.Value(ctxKey{}).(Overrides)
+	return overrides
+}
+
+// Validator validates the overrides requested through metadata.
+type Validator struct {
+	ceilings          Ceilings
+	trustedPrincipals map[string]struct{}
+}
+
+// NewValidator returns a Validator that accepts overrides up to ceilings from the callers whose subject or
+// client id is one of trustedPrincipals.
+func NewValidator(ceilings Ceilings, trustedPrincipals []string) *Validator {
+	v := &Validator{
+		ceilings:          ceilings,
+		trustedPrincipals: make(map[string]struct{}, len(trustedPrincipals)),
+	}
+	for _, principal := range trustedPrincipals {
+		v.trustedPrincipals[principal] = struct{}{}
+	}
+	return v
+}
+
+// Overrides returns the overrides requested in the metadata of ctx. It returns a PermissionDenied error if
+// the caller is not trusted, and an InvalidArgument error if an override is malformed or above its ceiling.
+func (v *Validator) Overrides(ctx context.Context) (Overrides, error) {
+	var overrides Overrides
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return overrides, nil
+	}
+	depthValues := md.Get(MaxResolveDepthHeader)
+	deadlineValues := md.Get(ListObjectsDeadlineHeader)
+	if len(depthValues) == 0 && len(deadlineValues) == 0 {
+		return overrides, nil
+	}
+
+	if !v.isTrusted(ctx) {
+		return overrides, status.Error(codes.PermissionDenied, "the credentials are not allowed to override the resolution limits")
+	}
+
+	if len(depthValues) > 0 {
+		depth, err := strconv.ParseUint(depthValues[0], 10, 32)
+		if err != nil || depth == 0 {
+			return overrides, status.Errorf(codes.InvalidArgument, "'%s' must be a positive integer", MaxResolveDepthHeader)
+		}
+		if uint32(depth) > v.ceilings.MaxResolveDepth {
+			return overrides, status.Errorf(codes.InvalidArgument, "'%s' cannot exceed %d", MaxResolveDepthHeader, v.ceilings.MaxResolveDepth)
+		}
+		overrides.MaxResolveDepth = uint32(depth)
+	}
+
+	if len(deadlineValues) > 0 {
+		deadline, err := time.ParseDuration(deadlineValues[0])
+		if err != nil || deadline <= 0 {
+			return overrides, status.Errorf(codes.InvalidArgument, "'%s' must be a positive duration", ListObjectsDeadlineHeader)
+		}
+		if deadline > v.ceilings.ListObjectsDeadline {
+			return overrides, status.Errorf(codes.InvalidArgument, "'%s' cannot exceed %s", ListObjectsDeadlineHeader, v.ceilings.ListObjectsDeadline)
+		}
+		overrides.ListObjectsDeadline = deadline
+	}
+
+	return overrides, nil
+}
+
+func (v *Validator) isTrusted(ctx context.Context) bool {
+	claims, ok := authclaims.AuthClaimsFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	for _, principal := range []string{claims.Subject, claims.ClientID} {
+		if principal == "" {
+			continue
+		}
+		if _, found := v.trustedPrincipals[principal]; found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewUnaryInterceptor returns a grpc.UnaryServerInterceptor that rejects the requests with invalid overrides,
+// and passes the valid ones to the handlers through the context.
+func NewUnaryInterceptor(v *Validator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		overrides, err := v.Overrides(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ContextWithOverrides(ctx, overrides), req)
+	}
+}
+
+// NewStreamingInterceptor is the streaming equivalent of NewUnaryInterceptor.
+func NewStreamingInterceptor(v *Validator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		overrides, err := v.Overrides(stream.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &overridesStream{
+			ServerStream: stream,
+			ctx:          ContextWithOverrides(stream.Context(), overrides),
+		})
+	}
+}
+
+type overridesStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *overridesStream) Context() context.Context {
+	return s.ctx
+}
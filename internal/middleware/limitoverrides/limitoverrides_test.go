@@ -0,0 +1,69 @@
+package limitoverrides
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+func TestValidatorOverrides(t *testing.T) {
+	v := NewValidator(Ceilings{MaxResolveDepth: 100, ListObjectsDeadline: 30 * time.Second}, []string{"batch-job"})
+
+	newContext := func(clientID string, kv ...string) context.Context {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+		return authclaims.ContextWithAuthClaims(ctx, &authclaims.AuthClaims{ClientID: clientID})
+	}
+
+	t.Run("no_overrides", func(t *testing.T) {
+		overrides, err := v.Overrides(newContext("interactive"))
+		require.NoError(t, err)
+		require.Equal(t, Overrides{}, overrides)
+	})
+
+	t.Run("trusted_caller_within_the_ceilings", func(t *testing.T) {
+		overrides, err := v.Overrides(newContext("batch-job", MaxResolveDepthHeader, "50", ListObjectsDeadlineHeader, "10s"))
+		require.NoError(t, err)
+		require.Equal(t, Overrides{MaxResolveDepth: 50, ListObjectsDeadline: 10 * time.Second}, overrides)
+	})
+
+	t.Run("untrusted_caller", func(t *testing.T) {
+		_, err := v.Overrides(newContext("interactive", MaxResolveDepthHeader, "50"))
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	for name, kv := range map[string][]string{
+		"depth_above_the_ceiling":    {MaxResolveDepthHeader, "101"},
+		"zero_depth":                 {MaxResolveDepthHeader, "0"},
+		"malformed_depth":            {MaxResolveDepthHeader, "deep"},
+		"deadline_above_the_ceiling": {ListObjectsDeadlineHeader, "1m"},
+		"malformed_deadline":         {ListObjectsDeadlineHeader, "10"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Overrides(newContext("batch-job", kv...))
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	interceptor := NewUnaryInterceptor(NewValidator(Ceilings{MaxResolveDepth: 100}, []string{"batch-job"}))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MaxResolveDepthHeader, "50"))
+	ctx = authclaims.ContextWithAuthClaims(ctx, &authclaims.AuthClaims{Subject: "batch-job"})
+
+	var overrides Overrides
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		overrides = FromContext(ctx)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(50), overrides.MaxResolveDepth)
+}
@@ -0,0 +1,308 @@
+// Package ratelimit implements a gRPC interceptor that rate limits requests per method and per caller.
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+// RetryAfterHeader is the metadata key holding the number of seconds after which a rate limited request may be retried.
+const RetryAfterHeader = "retry-after"
+
+// Key is the dimension requests are rate limited by.
+type Key string
+
+const (
+	// KeyCaller rate limits each caller, identified by the client id or subject of its credentials.
+	KeyCaller Key = "caller"
+	// KeyStore rate limits each store.
+	KeyStore Key = "store"
+	// KeyStoreAndCaller rate limits each caller on each store.
+	KeyStoreAndCaller Key = "store_and_caller"
+)
+
+const (
+	// anonymousCaller is the caller of requests without credentials.
+	anonymousCaller = "anonymous"
+	// invalidStore is the store of requests without a valid store id, which share a limiter.
+	invalidStore = "invalid"
+
+	// maxLimiters caps the number of limiters kept, so that the requests to many distinct stores or by many
+	// distinct callers cannot grow them without bound. The least recently used limiter is evicted first.
+	maxLimiters = 100000
+
+	// idleLimiterTTL is how long the limiter of a caller that sends no request is kept.
+	idleLimiterTTL     = 10 * time.Minute
+	idleLimiterCleanup = time.Minute
+)
+
+var rateLimitedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: build.ProjectName,
+	Name:      "rate_limited_requests_count",
+	Help:      "The total number of requests rejected by the rate limiter.",
+}, []string{"grpc_method"})
+
+// Limit is the token bucket of a method: Rate requests per second, with bursts of up to Burst requests.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseMethodLimits parses per method limits of the form '<method>:<rate>:<burst>', e.g. 'Check:100:200'.
+func ParseMethodLimits(limits []string) (map[string]Limit, error) {
+	parsed := make(map[string]Limit, len(limits))
+	for _, l := range limits {
+		parts := strings.Split(l, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid rate limit '%s', expected '<method>:<rate>:<burst>'", l)
+		}
+
+		r, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("invalid rate in rate limit '%s'", l)
+		}
+
+		burst, err := strconv.Atoi(parts[2])
+		if err != nil || burst <= 0 {
+			return nil, fmt.Errorf("invalid burst in rate limit '%s'", l)
+		}
+
+		parsed[parts[0]] = Limit{Rate: r, Burst: burst}
+	}
+	return parsed, nil
+}
+
+// RateLimiter holds the token buckets of every method and caller.
+type RateLimiter struct {
+	key          Key
+	defaultLimit Limit
+	methodLimits map[string]Limit
+
+	mu          sync.Mutex
+	limiters    map[string]*list.Element // GUARDED_BY(mu), of the limiters in lru.
+	lru         *list.List               // GUARDED_BY(mu), of the limiters from the most to the least recently used.
+	maxLimiters int
+
+	now  func() time.Time
+	stop chan struct{}
+	once sync.Once
+}
+
+type limiter struct {
+	*rate.Limiter
+	key      string
+	lastSeen time.Time
+}
+
+// NewRateLimiter returns a RateLimiter that applies defaultLimit to every method, unless overridden in methodLimits.
+// A zero default rate leaves the methods without an override unlimited. Close must be called to release it.
+func NewRateLimiter(key Key, defaultLimit Limit, methodLimits map[string]Limit) (*RateLimiter, error) {
+	switch key {
+	case KeyCaller, KeyStore, KeyStoreAndCaller:
+	default:
+		return nil, fmt.Errorf("invalid rate limit key '%s'", key)
+	}
+
+	r := &RateLimiter{
+		key:          key,
+		defaultLimit: defaultLimit,
+		methodLimits: methodLimits,
+		limiters:     map[string]*list.Element{},
+		lru:          list.New(),
+		maxLimiters:  maxLimiters,
+		now:          time.Now,
+		stop:         make(chan struct{}),
+	}
+	go r.cleanup()
+
+	return r, nil
+}
+
+// Close stops the cleanup of idle limiters.
+func (r *RateLimiter) Close() {
+	r.once.Do(func() { close(r.stop) })
+}
+
+func (r *RateLimiter) cleanup() {
+	ticker := time.NewTicker(idleLimiterCleanup)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			now := r.now()
+			r.mu.Lock()
+			// the least recently used limiters are the idle ones
+			for e := r.lru.Back(); e != nil && now.Sub(e.Value.(*limiter).lastSeen) > idleLimiterTTL; e = r.lru.Back() {
+				r.remove(e)
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// allow reports whether a request to method by the caller of ctx on storeID is allowed and, if not, how long the
+// caller should wait before retrying.
+func (r *RateLimiter) allow(ctx context.Context, method, storeID string) (bool, time.Duration) {
+	limit, ok := r.methodLimits[method]
+	if !ok {
+		limit = r.defaultLimit
+	}
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+
+	now := r.now()
+	key := method + "|" + r.callerKey(ctx, storeID)
+
+	r.mu.Lock()
+	var l *limiter
+	if e, ok := r.limiters[key]; ok {
+		l = e.Value.(*limiter)
+		r.lru.MoveToFront(e)
+	} else {
+		l = &limiter{Limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst), key: key}
+		r.limiters[key] = r.lru.PushFront(l)
+		if r.lru.Len() > r.maxLimiters {
+			r.remove(r.lru.Back())
+		}
+	}
+	l.lastSeen = now
+	r.mu.Unlock()
+
+	reservation := l.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, time.Second
+	}
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return true, 0
+	}
+	reservation.CancelAt(now)
+
+	return false, delay
+}
+
+// remove drops the limiter of e. The caller must hold mu.
+func (r *RateLimiter) remove(e *list.Element) {
+	r.lru.Remove(e)
+	delete(r.limiters, e.Value.(*limiter).key)
+}
+
+func (r *RateLimiter) callerKey(ctx context.Context, storeID string) string {
+	switch r.key {
+	case KeyStore:
+		return storeKey(storeID)
+	case KeyStoreAndCaller:
+		return storeKey(storeID) + "|" + caller(ctx)
+	default:
+		return caller(ctx)
+	}
+}
+
+// storeKey returns the store id, or invalidStore if it is not a valid store id, in which case the request is
+// rejected by its validation anyway.
+func storeKey(storeID string) string {
+	if _, err := ulid.ParseStrict(storeID); err != nil {
+		return invalidStore
+	}
+	return storeID
+}
+
+func caller(ctx context.Context) string {
+	claims, ok := authclaims.AuthClaimsFromContext(ctx)
+	if !ok {
+		return anonymousCaller
+	}
+	if claims.ClientID != "" {
+		return claims.ClientID
+	}
+	if claims.Subject != "" {
+		return claims.Subject
+	}
+	return anonymousCaller
+}
+
+type hasGetStoreID interface {
+	GetStoreId() string
+}
+
+// check returns a ResourceExhausted error, and the metadata to send to the caller, if the request exceeds its rate limit.
+func (r *RateLimiter) check(ctx context.Context, fullMethod string, req interface{}) (metadata.MD, error) {
+	method := path.Base(fullMethod)
+
+	var storeID string
+	if s, ok := req.(hasGetStoreID); ok {
+		storeID = s.GetStoreId()
+	}
+
+	allowed, retryAfter := r.allow(ctx, method, storeID)
+	if allowed {
+		return nil, nil
+	}
+
+	rateLimitedCounter.WithLabelValues(method).Inc()
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	return metadata.Pairs(RetryAfterHeader, strconv.Itoa(seconds)),
+		status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s, retry after %ds", method, seconds)
+}
+
+// NewUnaryInterceptor returns a grpc.UnaryServerInterceptor that rejects requests exceeding their rate limit with
+// a ResourceExhausted error and the RetryAfterHeader. It must run after the authentication interceptor.
+func NewUnaryInterceptor(r *RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, err := r.check(ctx, info.FullMethod, req); err != nil {
+			_ = grpc.SetHeader(ctx, md)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewStreamingInterceptor is the streaming equivalent of NewUnaryInterceptor. The request is checked once it has
+// been received.
+func NewStreamingInterceptor(r *RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &rateLimitedStream{ServerStream: stream, fullMethod: info.FullMethod, limiter: r})
+	}
+}
+
+type rateLimitedStream struct {
+	grpc.ServerStream
+	fullMethod string
+	limiter    *RateLimiter
+}
+
+// RecvMsg receives the request message and checks it against the rate limit before handing it to the handler.
+func (s *rateLimitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	md, err := s.limiter.check(s.Context(), s.fullMethod, m)
+	if err != nil {
+		_ = s.SetHeader(md)
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+func TestParseMethodLimits(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		limits, err := ParseMethodLimits([]string{"Check:100:200", "ListObjects:0.5:1"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]Limit{
+			"Check":       {Rate: 100, Burst: 200},
+			"ListObjects": {Rate: 0.5, Burst: 1},
+		}, limits)
+	})
+
+	for _, invalid := range []string{"Check", "Check:100", ":1:1", "Check:abc:1", "Check:0:1", "Check:1:0", "Check:1:1:1"} {
+		t.Run(invalid, func(t *testing.T) {
+			_, err := ParseMethodLimits([]string{invalid})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNewRateLimiter_InvalidKey(t *testing.T) {
+	_, err := NewRateLimiter("invalid", Limit{}, nil)
+	require.Error(t, err)
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	storeID := "01JA7QGP1A6FQ7M0T0CPWHNZ1J"
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	info := func(method string) *grpc.UnaryServerInfo {
+		return &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/" + method}
+	}
+	withCaller := func(clientID string) context.Context {
+		return authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{ClientID: clientID})
+	}
+
+	newInterceptor := func(t *testing.T, key Key, defaultLimit Limit, methodLimits map[string]Limit) grpc.UnaryServerInterceptor {
+		r, err := NewRateLimiter(key, defaultLimit, methodLimits)
+		require.NoError(t, err)
+		t.Cleanup(r.Close)
+
+		// freeze time so that no token is refilled during the test
+		now := time.Now()
+		r.now = func() time.Time { return now }
+
+		return NewUnaryInterceptor(r)
+	}
+
+	t.Run("rejects_requests_above_the_burst", func(t *testing.T) {
+		interceptor := newInterceptor(t, KeyCaller, Limit{Rate: 1, Burst: 2}, nil)
+		ctx := withCaller("client-a")
+		req := &openfgav1.CheckRequest{StoreId: storeID}
+
+		for i := 0; i < 2; i++ {
+			_, err := interceptor(ctx, req, info("Check"), handler)
+			require.NoError(t, err)
+		}
+
+		_, err := interceptor(ctx, req, info("Check"), handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("limits_each_caller_separately", func(t *testing.T) {
+		interceptor := newInterceptor(t, KeyCaller, Limit{Rate: 1, Burst: 1}, nil)
+		req := &openfgav1.CheckRequest{StoreId: storeID}
+
+		_, err := interceptor(withCaller("client-a"), req, info("Check"), handler)
+		require.NoError(t, err)
+		_, err = interceptor(withCaller("client-a"), req, info("Check"), handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		_, err = interceptor(withCaller("client-b"), req, info("Check"), handler)
+		require.NoError(t, err)
+	})
+
+	t.Run("limits_each_store_separately", func(t *testing.T) {
+		interceptor := newInterceptor(t, KeyStore, Limit{Rate: 1, Burst: 1}, nil)
+
+		_, err := interceptor(withCaller("client-a"), &openfgav1.CheckRequest{StoreId: storeID}, info("Check"), handler)
+		require.NoError(t, err)
+		_, err = interceptor(withCaller("client-b"), &openfgav1.CheckRequest{StoreId: storeID}, info("Check"), handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		_, err = interceptor(withCaller("client-a"), &openfgav1.CheckRequest{StoreId: "01JA7QGP1A6FQ7M0T0CPWHNZ1K"}, info("Check"), handler)
+		require.NoError(t, err)
+	})
+
+	t.Run("method_limits_override_the_default", func(t *testing.T) {
+		interceptor := newInterceptor(t, KeyCaller, Limit{}, map[string]Limit{"Write": {Rate: 1, Burst: 1}})
+		ctx := withCaller("client-a")
+
+		for i := 0; i < 10; i++ {
+			_, err := interceptor(ctx, &openfgav1.CheckRequest{StoreId: storeID}, info("Check"), handler)
+			require.NoError(t, err)
+		}
+
+		_, err := interceptor(ctx, &openfgav1.WriteRequest{StoreId: storeID}, info("Write"), handler)
+		require.NoError(t, err)
+		_, err = interceptor(ctx, &openfgav1.WriteRequest{StoreId: storeID}, info("Write"), handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+	t.Run("invalid_store_ids_share_a_limiter", func(t *testing.T) {
+		interceptor := newInterceptor(t, KeyStore, Limit{Rate: 1, Burst: 1}, nil)
+		ctx := withCaller("client-a")
+
+		_, err := interceptor(ctx, &openfgav1.CheckRequest{StoreId: "foo"}, info("Check"), handler)
+		require.NoError(t, err)
+		_, err = interceptor(ctx, &openfgav1.CheckRequest{StoreId: "bar"}, info("Check"), handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
+
+func TestRateLimiterCapsItsLimiters(t *testing.T) {
+	r, err := NewRateLimiter(KeyStore, Limit{Rate: 1, Burst: 1}, nil)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+	r.maxLimiters = 2
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	stores := []string{ulid.Make().String(), ulid.Make().String(), ulid.Make().String()}
+	allowed, _ := r.allow(context.Background(), "Check", stores[0])
+	require.True(t, allowed)
+	allowed, _ = r.allow(context.Background(), "Check", stores[1])
+	require.True(t, allowed)
+
+	// the limiter of the first store is used more recently than that of the second one
+	allowed, _ = r.allow(context.Background(), "Check", stores[0])
+	require.False(t, allowed)
+
+	allowed, _ = r.allow(context.Background(), "Check", stores[2])
+	require.True(t, allowed)
+	require.Len(t, r.limiters, 2)
+	require.Equal(t, 2, r.lru.Len())
+	require.Contains(t, r.limiters, "Check|"+stores[0])
+	require.NotContains(t, r.limiters, "Check|"+stores[1])
+}
@@ -66,3 +66,27 @@ func (mr *MockCacheControllerMockRecorder) InvalidateIfNeeded(storeID, parentSpa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateIfNeeded", reflect.TypeOf((*MockCacheController)(nil).InvalidateIfNeeded), storeID, parentSpan)
 }
+
+// InvalidateObjectType mocks base method.
+func (m *MockCacheController) InvalidateObjectType(storeID, objectType string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateObjectType", storeID, objectType)
+}
+
+// InvalidateObjectType indicates an expected call of InvalidateObjectType.
+func (mr *MockCacheControllerMockRecorder) InvalidateObjectType(storeID, objectType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateObjectType", reflect.TypeOf((*MockCacheController)(nil).InvalidateObjectType), storeID, objectType)
+}
+
+// InvalidateStore mocks base method.
+func (m *MockCacheController) InvalidateStore(storeID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateStore", storeID)
+}
+
+// InvalidateStore indicates an expected call of InvalidateStore.
+func (mr *MockCacheControllerMockRecorder) InvalidateStore(storeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateStore", reflect.TypeOf((*MockCacheController)(nil).InvalidateStore), storeID)
+}
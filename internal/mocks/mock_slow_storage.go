@@ -41,6 +41,11 @@ func (m *slowDataStorage) ReadUserTuple(ctx context.Context, store string, key *
 	return m.OpenFGADatastore.ReadUserTuple(ctx, store, key, options)
 }
 
+func (m *slowDataStorage) ReadUserTuples(ctx context.Context, store string, keys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	time.Sleep(m.readTuplesDelay)
+	return m.OpenFGADatastore.ReadUserTuples(ctx, store, keys, options)
+}
+
 func (m *slowDataStorage) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
 	time.Sleep(m.readTuplesDelay)
 	return m.OpenFGADatastore.ReadUsersetTuples(ctx, store, filter, options)
@@ -117,6 +117,21 @@ func (mr *MockTupleBackendMockRecorder) ReadUserTuple(ctx, store, tupleKey, opti
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserTuple", reflect.TypeOf((*MockTupleBackend)(nil).ReadUserTuple), ctx, store, tupleKey, options)
 }
 
+// ReadUserTuples mocks base method.
+func (m *MockTupleBackend) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUserTuples", ctx, store, tupleKeys, options)
+	ret0, _ := ret[0].([]*openfgav1.Tuple)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUserTuples indicates an expected call of ReadUserTuples.
+func (mr *MockTupleBackendMockRecorder) ReadUserTuples(ctx, store, tupleKeys, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserTuples", reflect.TypeOf((*MockTupleBackend)(nil).ReadUserTuples), ctx, store, tupleKeys, options)
+}
+
 // ReadUsersetTuples mocks base method.
 func (m *MockTupleBackend) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
 	m.ctrl.T.Helper()
@@ -231,6 +246,21 @@ func (mr *MockRelationshipTupleReaderMockRecorder) ReadUserTuple(ctx, store, tup
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserTuple", reflect.TypeOf((*MockRelationshipTupleReader)(nil).ReadUserTuple), ctx, store, tupleKey, options)
 }
 
+// ReadUserTuples mocks base method.
+func (m *MockRelationshipTupleReader) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUserTuples", ctx, store, tupleKeys, options)
+	ret0, _ := ret[0].([]*openfgav1.Tuple)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUserTuples indicates an expected call of ReadUserTuples.
+func (mr *MockRelationshipTupleReaderMockRecorder) ReadUserTuples(ctx, store, tupleKeys, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserTuples", reflect.TypeOf((*MockRelationshipTupleReader)(nil).ReadUserTuples), ctx, store, tupleKeys, options)
+}
+
 // ReadUsersetTuples mocks base method.
 func (m *MockRelationshipTupleReader) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
 	m.ctrl.T.Helper()
@@ -392,6 +422,20 @@ func (m *MockTypeDefinitionWriteBackend) EXPECT() *MockTypeDefinitionWriteBacken
 	return m.recorder
 }
 
+// DeleteAuthorizationModel mocks base method.
+func (m *MockTypeDefinitionWriteBackend) DeleteAuthorizationModel(ctx context.Context, store, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthorizationModel", ctx, store, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthorizationModel indicates an expected call of DeleteAuthorizationModel.
+func (mr *MockTypeDefinitionWriteBackendMockRecorder) DeleteAuthorizationModel(ctx, store, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthorizationModel", reflect.TypeOf((*MockTypeDefinitionWriteBackend)(nil).DeleteAuthorizationModel), ctx, store, id)
+}
+
 // MaxTypesPerAuthorizationModel mocks base method.
 func (m *MockTypeDefinitionWriteBackend) MaxTypesPerAuthorizationModel() int {
 	m.ctrl.T.Helper()
@@ -444,6 +488,20 @@ func (m *MockAuthorizationModelBackend) EXPECT() *MockAuthorizationModelBackendM
 	return m.recorder
 }
 
+// DeleteAuthorizationModel mocks base method.
+func (m *MockAuthorizationModelBackend) DeleteAuthorizationModel(ctx context.Context, store, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthorizationModel", ctx, store, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthorizationModel indicates an expected call of DeleteAuthorizationModel.
+func (mr *MockAuthorizationModelBackendMockRecorder) DeleteAuthorizationModel(ctx, store, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthorizationModel", reflect.TypeOf((*MockAuthorizationModelBackend)(nil).DeleteAuthorizationModel), ctx, store, id)
+}
+
 // FindLatestAuthorizationModel mocks base method.
 func (m *MockAuthorizationModelBackend) FindLatestAuthorizationModel(ctx context.Context, store string) (*openfgav1.AuthorizationModel, error) {
 	m.ctrl.T.Helper()
@@ -557,6 +615,21 @@ func (mr *MockStoresBackendMockRecorder) CreateStore(ctx, store any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStore", reflect.TypeOf((*MockStoresBackend)(nil).CreateStore), ctx, store)
 }
 
+// CreateStoreWithLabels mocks base method.
+func (m *MockStoresBackend) CreateStoreWithLabels(ctx context.Context, store *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStoreWithLabels", ctx, store, labels)
+	ret0, _ := ret[0].(*openfgav1.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStoreWithLabels indicates an expected call of CreateStoreWithLabels.
+func (mr *MockStoresBackendMockRecorder) CreateStoreWithLabels(ctx, store, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStoreWithLabels", reflect.TypeOf((*MockStoresBackend)(nil).CreateStoreWithLabels), ctx, store, labels)
+}
+
 // DeleteStore mocks base method.
 func (m *MockStoresBackend) DeleteStore(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -602,6 +675,35 @@ func (mr *MockStoresBackendMockRecorder) ListStores(ctx, options any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStores", reflect.TypeOf((*MockStoresBackend)(nil).ListStores), ctx, options)
 }
 
+// ReadStoreLabels mocks base method.
+func (m *MockStoresBackend) ReadStoreLabels(ctx context.Context, id string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStoreLabels", ctx, id)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStoreLabels indicates an expected call of ReadStoreLabels.
+func (mr *MockStoresBackendMockRecorder) ReadStoreLabels(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStoreLabels", reflect.TypeOf((*MockStoresBackend)(nil).ReadStoreLabels), ctx, id)
+}
+
+// WriteStoreLabels mocks base method.
+func (m *MockStoresBackend) WriteStoreLabels(ctx context.Context, id string, labels map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteStoreLabels", ctx, id, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteStoreLabels indicates an expected call of WriteStoreLabels.
+func (mr *MockStoresBackendMockRecorder) WriteStoreLabels(ctx, id, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteStoreLabels", reflect.TypeOf((*MockStoresBackend)(nil).WriteStoreLabels), ctx, id, labels)
+}
+
 // MockAssertionsBackend is a mock of AssertionsBackend interface.
 type MockAssertionsBackend struct {
 	ctrl     *gomock.Controller
@@ -655,6 +757,102 @@ func (mr *MockAssertionsBackendMockRecorder) WriteAssertions(ctx, store, modelID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteAssertions", reflect.TypeOf((*MockAssertionsBackend)(nil).WriteAssertions), ctx, store, modelID, assertions)
 }
 
+// MockJobsBackend is a mock of JobsBackend interface.
+type MockJobsBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobsBackendMockRecorder
+	isgomock struct{}
+}
+
+// MockJobsBackendMockRecorder is the mock recorder for MockJobsBackend.
+type MockJobsBackendMockRecorder struct {
+	mock *MockJobsBackend
+}
+
+// NewMockJobsBackend creates a new mock instance.
+func NewMockJobsBackend(ctrl *gomock.Controller) *MockJobsBackend {
+	mock := &MockJobsBackend{ctrl: ctrl}
+	mock.recorder = &MockJobsBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobsBackend) EXPECT() *MockJobsBackendMockRecorder {
+	return m.recorder
+}
+
+// ClaimInterruptedJobs mocks base method.
+func (m *MockJobsBackend) ClaimInterruptedJobs(ctx context.Context) ([]*storage.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimInterruptedJobs", ctx)
+	ret0, _ := ret[0].([]*storage.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimInterruptedJobs indicates an expected call of ClaimInterruptedJobs.
+func (mr *MockJobsBackendMockRecorder) ClaimInterruptedJobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimInterruptedJobs", reflect.TypeOf((*MockJobsBackend)(nil).ClaimInterruptedJobs), ctx)
+}
+
+// CreateJob mocks base method.
+func (m *MockJobsBackend) CreateJob(ctx context.Context, job *storage.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockJobsBackendMockRecorder) CreateJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockJobsBackend)(nil).CreateJob), ctx, job)
+}
+
+// ReadJob mocks base method.
+func (m *MockJobsBackend) ReadJob(ctx context.Context, store, id string) (*storage.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadJob", ctx, store, id)
+	ret0, _ := ret[0].(*storage.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadJob indicates an expected call of ReadJob.
+func (mr *MockJobsBackendMockRecorder) ReadJob(ctx, store, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadJob", reflect.TypeOf((*MockJobsBackend)(nil).ReadJob), ctx, store, id)
+}
+
+// RequestJobCancellation mocks base method.
+func (m *MockJobsBackend) RequestJobCancellation(ctx context.Context, store, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestJobCancellation", ctx, store, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestJobCancellation indicates an expected call of RequestJobCancellation.
+func (mr *MockJobsBackendMockRecorder) RequestJobCancellation(ctx, store, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestJobCancellation", reflect.TypeOf((*MockJobsBackend)(nil).RequestJobCancellation), ctx, store, id)
+}
+
+// UpdateJob mocks base method.
+func (m *MockJobsBackend) UpdateJob(ctx context.Context, job *storage.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockJobsBackendMockRecorder) UpdateJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockJobsBackend)(nil).UpdateJob), ctx, job)
+}
+
 // MockChangelogBackend is a mock of ChangelogBackend interface.
 type MockChangelogBackend struct {
 	ctrl     *gomock.Controller
@@ -719,6 +917,21 @@ func (m *MockOpenFGADatastore) EXPECT() *MockOpenFGADatastoreMockRecorder {
 	return m.recorder
 }
 
+// ClaimInterruptedJobs mocks base method.
+func (m *MockOpenFGADatastore) ClaimInterruptedJobs(ctx context.Context) ([]*storage.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimInterruptedJobs", ctx)
+	ret0, _ := ret[0].([]*storage.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimInterruptedJobs indicates an expected call of ClaimInterruptedJobs.
+func (mr *MockOpenFGADatastoreMockRecorder) ClaimInterruptedJobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimInterruptedJobs", reflect.TypeOf((*MockOpenFGADatastore)(nil).ClaimInterruptedJobs), ctx)
+}
+
 // Close mocks base method.
 func (m *MockOpenFGADatastore) Close() {
 	m.ctrl.T.Helper()
@@ -731,6 +944,20 @@ func (mr *MockOpenFGADatastoreMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockOpenFGADatastore)(nil).Close))
 }
 
+// CreateJob mocks base method.
+func (m *MockOpenFGADatastore) CreateJob(ctx context.Context, job *storage.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockOpenFGADatastoreMockRecorder) CreateJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockOpenFGADatastore)(nil).CreateJob), ctx, job)
+}
+
 // CreateStore mocks base method.
 func (m *MockOpenFGADatastore) CreateStore(ctx context.Context, store *openfgav1.Store) (*openfgav1.Store, error) {
 	m.ctrl.T.Helper()
@@ -746,6 +973,35 @@ func (mr *MockOpenFGADatastoreMockRecorder) CreateStore(ctx, store any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStore", reflect.TypeOf((*MockOpenFGADatastore)(nil).CreateStore), ctx, store)
 }
 
+// CreateStoreWithLabels mocks base method.
+func (m *MockOpenFGADatastore) CreateStoreWithLabels(ctx context.Context, store *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStoreWithLabels", ctx, store, labels)
+	ret0, _ := ret[0].(*openfgav1.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStoreWithLabels indicates an expected call of CreateStoreWithLabels.
+func (mr *MockOpenFGADatastoreMockRecorder) CreateStoreWithLabels(ctx, store, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStoreWithLabels", reflect.TypeOf((*MockOpenFGADatastore)(nil).CreateStoreWithLabels), ctx, store, labels)
+}
+
+// DeleteAuthorizationModel mocks base method.
+func (m *MockOpenFGADatastore) DeleteAuthorizationModel(ctx context.Context, store, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthorizationModel", ctx, store, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthorizationModel indicates an expected call of DeleteAuthorizationModel.
+func (mr *MockOpenFGADatastoreMockRecorder) DeleteAuthorizationModel(ctx, store, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthorizationModel", reflect.TypeOf((*MockOpenFGADatastore)(nil).DeleteAuthorizationModel), ctx, store, id)
+}
+
 // DeleteStore mocks base method.
 func (m *MockOpenFGADatastore) DeleteStore(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -926,6 +1182,21 @@ func (mr *MockOpenFGADatastoreMockRecorder) ReadChanges(ctx, store, filter, opti
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadChanges", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadChanges), ctx, store, filter, options)
 }
 
+// ReadJob mocks base method.
+func (m *MockOpenFGADatastore) ReadJob(ctx context.Context, store, id string) (*storage.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadJob", ctx, store, id)
+	ret0, _ := ret[0].(*storage.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadJob indicates an expected call of ReadJob.
+func (mr *MockOpenFGADatastoreMockRecorder) ReadJob(ctx, store, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadJob", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadJob), ctx, store, id)
+}
+
 // ReadPage mocks base method.
 func (m *MockOpenFGADatastore) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
 	m.ctrl.T.Helper()
@@ -957,6 +1228,21 @@ func (mr *MockOpenFGADatastoreMockRecorder) ReadStartingWithUser(ctx, store, fil
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStartingWithUser", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadStartingWithUser), ctx, store, filter, options)
 }
 
+// ReadStoreLabels mocks base method.
+func (m *MockOpenFGADatastore) ReadStoreLabels(ctx context.Context, id string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStoreLabels", ctx, id)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStoreLabels indicates an expected call of ReadStoreLabels.
+func (mr *MockOpenFGADatastoreMockRecorder) ReadStoreLabels(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStoreLabels", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadStoreLabels), ctx, id)
+}
+
 // ReadUserTuple mocks base method.
 func (m *MockOpenFGADatastore) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
 	m.ctrl.T.Helper()
@@ -972,6 +1258,21 @@ func (mr *MockOpenFGADatastoreMockRecorder) ReadUserTuple(ctx, store, tupleKey,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserTuple", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadUserTuple), ctx, store, tupleKey, options)
 }
 
+// ReadUserTuples mocks base method.
+func (m *MockOpenFGADatastore) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUserTuples", ctx, store, tupleKeys, options)
+	ret0, _ := ret[0].([]*openfgav1.Tuple)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUserTuples indicates an expected call of ReadUserTuples.
+func (mr *MockOpenFGADatastoreMockRecorder) ReadUserTuples(ctx, store, tupleKeys, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserTuples", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadUserTuples), ctx, store, tupleKeys, options)
+}
+
 // ReadUsersetTuples mocks base method.
 func (m *MockOpenFGADatastore) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
 	m.ctrl.T.Helper()
@@ -987,6 +1288,34 @@ func (mr *MockOpenFGADatastoreMockRecorder) ReadUsersetTuples(ctx, store, filter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUsersetTuples", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadUsersetTuples), ctx, store, filter, options)
 }
 
+// RequestJobCancellation mocks base method.
+func (m *MockOpenFGADatastore) RequestJobCancellation(ctx context.Context, store, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestJobCancellation", ctx, store, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestJobCancellation indicates an expected call of RequestJobCancellation.
+func (mr *MockOpenFGADatastoreMockRecorder) RequestJobCancellation(ctx, store, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestJobCancellation", reflect.TypeOf((*MockOpenFGADatastore)(nil).RequestJobCancellation), ctx, store, id)
+}
+
+// UpdateJob mocks base method.
+func (m *MockOpenFGADatastore) UpdateJob(ctx context.Context, job *storage.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockOpenFGADatastoreMockRecorder) UpdateJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockOpenFGADatastore)(nil).UpdateJob), ctx, job)
+}
+
 // Write mocks base method.
 func (m *MockOpenFGADatastore) Write(ctx context.Context, store string, d storage.Deletes, w storage.Writes) error {
 	m.ctrl.T.Helper()
@@ -1028,3 +1357,55 @@ func (mr *MockOpenFGADatastoreMockRecorder) WriteAuthorizationModel(ctx, store,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteAuthorizationModel", reflect.TypeOf((*MockOpenFGADatastore)(nil).WriteAuthorizationModel), ctx, store, model)
 }
+
+// WriteStoreLabels mocks base method.
+func (m *MockOpenFGADatastore) WriteStoreLabels(ctx context.Context, id string, labels map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteStoreLabels", ctx, id, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteStoreLabels indicates an expected call of WriteStoreLabels.
+func (mr *MockOpenFGADatastoreMockRecorder) WriteStoreLabels(ctx, id, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteStoreLabels", reflect.TypeOf((*MockOpenFGADatastore)(nil).WriteStoreLabels), ctx, id, labels)
+}
+
+// MockTupleChangeListener is a mock of TupleChangeListener interface.
+type MockTupleChangeListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockTupleChangeListenerMockRecorder
+	isgomock struct{}
+}
+
+// MockTupleChangeListenerMockRecorder is the mock recorder for MockTupleChangeListener.
+type MockTupleChangeListenerMockRecorder struct {
+	mock *MockTupleChangeListener
+}
+
+// NewMockTupleChangeListener creates a new mock instance.
+func NewMockTupleChangeListener(ctrl *gomock.Controller) *MockTupleChangeListener {
+	mock := &MockTupleChangeListener{ctrl: ctrl}
+	mock.recorder = &MockTupleChangeListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTupleChangeListener) EXPECT() *MockTupleChangeListenerMockRecorder {
+	return m.recorder
+}
+
+// ListenTupleChanges mocks base method.
+func (m *MockTupleChangeListener) ListenTupleChanges(ctx context.Context, handler func(storage.TupleChangeNotification)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenTupleChanges", ctx, handler)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenTupleChanges indicates an expected call of ListenTupleChanges.
+func (mr *MockTupleChangeListenerMockRecorder) ListenTupleChanges(ctx, handler any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenTupleChanges", reflect.TypeOf((*MockTupleChangeListener)(nil).ListenTupleChanges), ctx, handler)
+}
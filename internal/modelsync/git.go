@@ -0,0 +1,116 @@
+package modelsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// gitSource fetches a file of a git repository with the git executable, versioned by the commit of the ref.
+type gitSource struct {
+	repo string
+	ref  string
+	file string
+}
+
+func newGitSource(rawURL string) (*gitSource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model source 'git+%s': %w", redactURL(rawURL), err)
+	}
+
+	ref := u.Query().Get("ref")
+	u.RawQuery = ""
+
+	repoPath, file, found := strings.Cut(u.Path, "//")
+	file = path.Clean(file)
+	if !found || file == "." || strings.HasPrefix(file, "..") || path.IsAbs(file) {
+		return nil, fmt.Errorf("invalid model source 'git+%s', expected the path of the model file in the repository after '//'", u.Redacted())
+	}
+	u.Path = repoPath
+
+	return &gitSource{repo: u.String(), ref: ref, file: file}, nil
+}
+
+func (s *gitSource) Fetch(ctx context.Context, previous string) (*Artifact, error) {
+	ref := s.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// the commit of the ref is listed first, so that the repository is only cloned when it changed
+	out, err := s.git(ctx, "", "ls-remote", s.repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	commit, _, _ := strings.Cut(string(out), "\t")
+	if commit == "" {
+		return nil, fmt.Errorf("the ref '%s' was not found in the repository '%s'", ref, s)
+	}
+	if commit == previous {
+		return nil, ErrNotModified
+	}
+
+	dir, err := os.MkdirTemp("", "openfga-model-sync-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if s.ref != "" {
+		args = append(args, "--branch", s.ref)
+	}
+	if _, err := s.git(ctx, "", append(args, "--", s.repo, dir)...); err != nil {
+		return nil, err
+	}
+
+	// the ref may have moved since it was listed
+	out, err = s.git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	commit = strings.TrimSpace(string(out))
+
+	// the file is opened within the checkout, so that a symlink of the repository can't read a file of the host
+	f, err := os.OpenInRoot(dir, filepath.FromSlash(s.file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the model file '%s' of the repository '%s': %w", s.file, s, err)
+	}
+	defer f.Close()
+
+	content, err := readLimited(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Artifact{Name: path.Base(s.file), Content: content, Version: commit}, nil
+}
+
+// git runs a git command in dir, returning its output.
+func (s *gitSource) git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// never wait for credentials to be typed
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// the output may contain the credentials of the repository URL
+		message := strings.ReplaceAll(strings.TrimSpace(stderr.String()), s.repo, s.String())
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, message)
+	}
+	return out, nil
+}
+
+func (s *gitSource) String() string {
+	return redactURL(s.repo) + "//" + s.file
+}
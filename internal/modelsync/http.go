@@ -0,0 +1,74 @@
+package modelsync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+)
+
+// httpTimeout bounds the requests of the HTTP and OCI sources.
+const httpTimeout = 30 * time.Second
+
+// httpSource fetches a file served over HTTP, versioned by its ETag, or else by the digest of its content.
+type httpSource struct {
+	url    *url.URL
+	client *http.Client
+}
+
+func newHTTPSource(rawURL string) (*httpSource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model source '%s': %w", redactURL(rawURL), err)
+	}
+	if u.Host == "" || path.Base(u.Path) == "/" || path.Base(u.Path) == "." {
+		return nil, fmt.Errorf("invalid model source '%s', expected the URL of a file", u.Redacted())
+	}
+
+	return &httpSource{url: u, client: &http.Client{Timeout: httpTimeout}}, nil
+}
+
+func (s *httpSource) Fetch(ctx context.Context, previous string) (*Artifact, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if previous != "" {
+		req.Header.Set("If-None-Match", previous)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, ErrNotModified
+	default:
+		return nil, unexpectedStatus(resp)
+	}
+
+	content, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	version := resp.Header.Get("ETag")
+	if version == "" {
+		version = contentDigest(content)
+	}
+	if version == previous {
+		return nil, ErrNotModified
+	}
+
+	return &Artifact{Name: path.Base(s.url.Path), Content: content, Version: version}, nil
+}
+
+func (s *httpSource) String() string {
+	return s.url.Redacted()
+}
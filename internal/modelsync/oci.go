@@ -0,0 +1,282 @@
+package modelsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// ociTitleAnnotation is the annotation naming the file of a layer, e.g. set by 'oras push'.
+	ociTitleAnnotation = "org.opencontainers.image.title"
+)
+
+// ociSource fetches a layer of an OCI artifact with the distribution API, versioned by the digest of the manifest.
+type ociSource struct {
+	scheme     string
+	registry   string
+	repository string
+	reference  string
+	file       string
+	user       *url.Userinfo
+	client     *http.Client
+
+	mu    sync.Mutex
+	token string // GUARDED_BY(mu).
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+func newOCISource(rawURL, scheme string) (*ociSource, error) {
+	u, err := url.Parse(scheme + "://" + rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model source 'oci://%s': %w", redactURL(rawURL), err)
+	}
+
+	name, file, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "//")
+	repository, reference := name, "latest"
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		repository, reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		repository, reference = name[:i], name[i+1:]
+	}
+	if u.Host == "" || repository == "" || reference == "" {
+		return nil, fmt.Errorf("invalid model source 'oci://%s', expected 'oci://<registry>/<repository>[:<tag>|@<digest>][//<file>]'", redactURL(rawURL))
+	}
+
+	return &ociSource{
+		scheme:     scheme,
+		registry:   u.Host,
+		repository: repository,
+		reference:  reference,
+		file:       file,
+		user:       u.User,
+		client:     &http.Client{Timeout: httpTimeout},
+	}, nil
+}
+
+func (s *ociSource) Fetch(ctx context.Context, previous string) (*Artifact, error) {
+	resp, err := s.get(ctx, "manifests/"+s.reference, ociManifestMediaType)
+	if err != nil {
+		return nil, err
+	}
+	content, err := readLimited(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	version := resp.Header.Get("Docker-Content-Digest")
+	if version == "" {
+		version = contentDigest(content)
+	}
+	if version == previous {
+		return nil, ErrNotModified
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode the manifest of '%s': %w", s, err)
+	}
+	layer, err := s.layer(&manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err = s.get(ctx, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err = readLimited(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if digest := contentDigest(content); digest != layer.Digest {
+		return nil, fmt.Errorf("the layer of '%s' has the digest '%s' instead of '%s'", s, digest, layer.Digest)
+	}
+
+	name := layer.Annotations[ociTitleAnnotation]
+	if name == "" {
+		name = s.file
+	}
+	return &Artifact{Name: name, Content: content, Version: version}, nil
+}
+
+// layer returns the layer of the model file.
+func (s *ociSource) layer(manifest *ociManifest) (*ociDescriptor, error) {
+	if s.file == "" {
+		if len(manifest.Layers) != 1 {
+			return nil, fmt.Errorf("the artifact '%s' has %d layers, the model file must be named", s, len(manifest.Layers))
+		}
+		return &manifest.Layers[0], nil
+	}
+
+	for i := range manifest.Layers {
+		if manifest.Layers[i].Annotations[ociTitleAnnotation] == s.file {
+			return &manifest.Layers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("the artifact '%s' has no layer titled '%s'", s, s.file)
+}
+
+// get sends a GET request to an endpoint of the repository, authenticating with a bearer token or the credentials of
+// the URL when the registry requires it.
+func (s *ociSource) get(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	endpointURL := fmt.Sprintf("%s://%s/v2/%s/%s", s.scheme, s.registry, s.repository, endpoint)
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpointURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		s.mu.Lock()
+		token := s.token
+		s.mu.Unlock()
+		switch {
+		case token != "":
+			req.Header.Set("Authorization", "Bearer "+token)
+		case s.user != nil:
+			password, _ := s.user.Password()
+			req.SetBasicAuth(s.user.Username(), password)
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		// a token is requested once, or again if the previous one expired
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return nil, unexpectedStatus(resp)
+		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return nil, unexpectedStatus(resp)
+		}
+		token, err = s.requestToken(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.token = token
+		s.mu.Unlock()
+	}
+}
+
+// requestToken requests a bearer token from the authorization server of a 'Bearer realm="...",service="...",
+// scope="..."' challenge.
+func (s *ociSource) requestToken(ctx context.Context, challenge string) (string, error) {
+	params := parseChallenge(challenge[len("bearer "):])
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid authentication challenge '%s' from the registry of '%s'", challenge, s)
+	}
+
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", s.repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if s.user != nil {
+		password, _ := s.user.Password()
+		req.SetBasicAuth(s.user.Username(), password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", unexpectedStatus(resp)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxModelFileSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode the token of the registry of '%s': %w", s, err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", errors.New("the registry returned an empty token")
+}
+
+// parseChallenge parses the comma separated 'key="value"' parameters of an authentication challenge.
+func parseChallenge(params string) map[string]string {
+	parsed := make(map[string]string)
+	for params != "" {
+		key, rest, found := strings.Cut(strings.TrimLeft(params, " ,"), "=")
+		if !found {
+			break
+		}
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		parsed[strings.ToLower(strings.TrimSpace(key))] = value
+		params = rest
+	}
+	return parsed
+}
+
+func (s *ociSource) String() string {
+	ref := s.registry + "/" + s.repository
+	if strings.HasPrefix(s.reference, "sha256:") {
+		ref += "@" + s.reference
+	} else {
+		ref += ":" + s.reference
+	}
+	if s.file != "" {
+		ref += "//" + s.file
+	}
+	if s.scheme == "http" {
+		return "oci+http://" + ref
+	}
+	return "oci://" + ref
+}
@@ -0,0 +1,93 @@
+// Package redis adapts a go-redis client to the cache shared by the servers, e.g. the typesystem.SharedModelCache.
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultPoolSize    = 10
+	defaultDialTimeout = 5 * time.Second
+	// defaultTimeout bounds the reads and writes of the commands sent without a deadline in their context.
+	defaultTimeout = time.Second
+)
+
+// ErrClosed is returned by the commands sent after the client was closed.
+var ErrClosed = goredis.ErrClosed
+
+// Config is the configuration of a Client.
+type Config struct {
+	// Addr is the 'host:port' address of the Redis server.
+	Addr string
+	// Username and Password authenticate the connections, if Password isn't empty. Username may be empty to
+	// authenticate as the default user.
+	Username string
+	Password string
+	// DB is the database selected on the connections.
+	DB int
+	// TLS reports whether the connections use TLS.
+	TLS bool
+	// PoolSize is the maximum number of connections open at once. Defaults to 10.
+	PoolSize int
+}
+
+// Client sends commands to a Redis server over a pool of connections. It is safe for concurrent use.
+type Client struct {
+	client *goredis.Client
+}
+
+// New returns a client of the Redis server of cfg. Connections are opened as commands are sent.
+func New(cfg Config) *Client {
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = defaultPoolSize
+	}
+
+	opts := &goredis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+		// RESP2 is enough for the commands sent, and spares the HELLO handshake with the servers before Redis 6
+		Protocol:              2,
+		DisableIdentity:       true,
+		DialTimeout:           defaultDialTimeout,
+		ReadTimeout:           defaultTimeout,
+		WriteTimeout:          defaultTimeout,
+		ContextTimeoutEnabled: true,
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return &Client{client: goredis.NewClient(opts)}
+}
+
+// Get returns the value of key, or nil if key doesn't exist.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Set sets the value of key, expiring after ttl.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Ping checks that the Redis server is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Close closes the connections of the client.
+func (c *Client) Close() {
+	_ = c.client.Close()
+}
@@ -0,0 +1,264 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer serves GET, SET, PING and AUTH from a map, ignoring expirations.
+type fakeServer struct {
+	listener net.Listener
+	password string
+	// fragmented writes the replies a byte at a time.
+	fragmented bool
+	// truncated is the number of the next replies cut in half, the connection being closed after them.
+	truncated atomic.Int32
+
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &fakeServer{listener: listener, password: password, values: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			authenticated = args[len(args)-1] == s.password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case cmd == "PING":
+			reply = "+PONG\r\n"
+		case cmd == "SET":
+			s.mu.Lock()
+			s.values[args[1]] = args[2]
+			s.mu.Unlock()
+			reply = "+OK\r\n"
+		case cmd == "GET":
+			s.mu.Lock()
+			value, ok := s.values[args[1]]
+			s.mu.Unlock()
+			reply = "$-1\r\n"
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+
+		if s.truncated.Add(-1) >= 0 {
+			_, _ = io.WriteString(conn, reply[:len(reply)/2])
+			return
+		}
+		if !s.fragmented {
+			if _, err := io.WriteString(conn, reply); err != nil {
+				return
+			}
+			continue
+		}
+		for i := range len(reply) {
+			if _, err := io.WriteString(conn, reply[i:i+1]); err != nil {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(r, arg); err != nil {
+			return nil, err
+		}
+		args[i] = string(arg[:size])
+	}
+	return args, nil
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get_and_set", func(t *testing.T) {
+		server := newFakeServer(t, "")
+		client := New(Config{Addr: server.listener.Addr().String(), PoolSize: 2})
+		t.Cleanup(client.Close)
+
+		require.NoError(t, client.Ping(ctx))
+
+		value, err := client.Get(ctx, "missing")
+		require.NoError(t, err)
+		require.Nil(t, value)
+
+		// values are binary safe
+		require.NoError(t, client.Set(ctx, "key", []byte("a\r\nb"), time.Minute))
+		value, err = client.Get(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, []byte("a\r\nb"), value)
+
+		require.NoError(t, client.Set(ctx, "empty", []byte{}, time.Minute))
+		value, err = client.Get(ctx, "empty")
+		require.NoError(t, err)
+		require.Equal(t, []byte{}, value)
+
+		// an error reply leaves the connection usable
+		var replyErr goredis.Error
+		require.ErrorAs(t, client.client.Do(ctx, "UNKNOWN").Err(), &replyErr)
+		require.NoError(t, client.Ping(ctx))
+	})
+
+	t.Run("concurrent_commands", func(t *testing.T) {
+		server := newFakeServer(t, "")
+		client := New(Config{Addr: server.listener.Addr().String(), PoolSize: 2})
+		t.Cleanup(client.Close)
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				key := strconv.Itoa(i)
+				if err := client.Set(ctx, key, []byte(key), time.Minute); err != nil {
+					t.Error(err)
+					return
+				}
+				value, err := client.Get(ctx, key)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if string(value) != key {
+					t.Errorf("got %q, want %q", value, key)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("authentication", func(t *testing.T) {
+		server := newFakeServer(t, "secret")
+
+		client := New(Config{Addr: server.listener.Addr().String(), Password: "secret"})
+		t.Cleanup(client.Close)
+		require.NoError(t, client.Ping(ctx))
+
+		unauthenticated := New(Config{Addr: server.listener.Addr().String(), Password: "wrong"})
+		t.Cleanup(unauthenticated.Close)
+		var replyErr goredis.Error
+		require.ErrorAs(t, unauthenticated.Ping(ctx), &replyErr)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		server := newFakeServer(t, "")
+		client := New(Config{Addr: server.listener.Addr().String()})
+		require.NoError(t, client.Ping(ctx))
+
+		client.Close()
+		require.ErrorIs(t, client.Ping(ctx), ErrClosed)
+	})
+	t.Run("partial_reads", func(t *testing.T) {
+		server := newFakeServer(t, "")
+		server.fragmented = true
+		client := New(Config{Addr: server.listener.Addr().String()})
+		t.Cleanup(client.Close)
+
+		require.NoError(t, client.Set(ctx, "key", []byte("a\r\nb"), time.Minute))
+		value, err := client.Get(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, []byte("a\r\nb"), value)
+	})
+
+	t.Run("connection_loss", func(t *testing.T) {
+		server := newFakeServer(t, "")
+		client := New(Config{Addr: server.listener.Addr().String(), PoolSize: 1})
+		t.Cleanup(client.Close)
+		require.NoError(t, client.Set(ctx, "key", []byte("value"), time.Minute))
+
+		// the command is sent again on a new connection
+		server.truncated.Store(1)
+		value, err := client.Get(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), value)
+
+		// a truncated reply is never returned
+		server.truncated.Store(100)
+		value, err = client.Get(ctx, "key")
+		require.Error(t, err)
+		require.Nil(t, value)
+
+		server.truncated.Store(0)
+		value, err = client.Get(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), value)
+	})
+
+	t.Run("unreachable_server", func(t *testing.T) {
+		server := newFakeServer(t, "")
+		addr := server.listener.Addr().String()
+		require.NoError(t, server.listener.Close())
+
+		client := New(Config{Addr: addr})
+		t.Cleanup(client.Close)
+		require.Error(t, client.Ping(ctx))
+	})
+}
@@ -3,10 +3,12 @@ package shared
 import (
 	"context"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/singleflight"
 
 	"github.com/openfga/openfga/internal/cachecontroller"
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/logger"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	"github.com/openfga/openfga/pkg/storage"
@@ -24,6 +26,13 @@ func WithLogger(logger logger.Logger) SharedDatastoreResourcesOpt {
 	}
 }
 
+// WithClock sets the clock the cache entries are timestamped with.
+func WithClock(clock clock.Clock) SharedDatastoreResourcesOpt {
+	return func(scr *SharedDatastoreResources) {
+		scr.Clock = clock
+	}
+}
+
 // WithCacheController allows overriding the default cacheController created in NewSharedDatastoreResources().
 func WithCacheController(cacheController cachecontroller.CacheController) SharedDatastoreResourcesOpt {
 	return func(scr *SharedDatastoreResources) {
@@ -31,6 +40,22 @@ func WithCacheController(cacheController cachecontroller.CacheController) Shared
 	}
 }
 
+// WithCheckCache allows overriding the default in-memory cache created in NewSharedDatastoreResources(), for example
+// with a cache shared between servers. The cache is stopped on Close.
+func WithCheckCache(cache storage.InMemoryCache[any]) SharedDatastoreResourcesOpt {
+	return func(scr *SharedDatastoreResources) {
+		scr.CheckCache = cache
+	}
+}
+
+// WithIteratorCacheTTLFunc sets a function returning the TTL of the cached iterators to the cache controller created
+// in NewSharedDatastoreResources(), so that the TTL can change while it runs.
+func WithIteratorCacheTTLFunc(ttl func() time.Duration) SharedDatastoreResourcesOpt {
+	return func(scr *SharedDatastoreResources) {
+		scr.iteratorCacheTTLFunc = ttl
+	}
+}
+
 // SharedDatastoreResources contains resources that can be shared across Check requests.
 type SharedDatastoreResources struct {
 	SingleflightGroup     *singleflight.Group
@@ -39,7 +64,10 @@ type SharedDatastoreResources struct {
 	CheckCache            storage.InMemoryCache[any]
 	CacheController       cachecontroller.CacheController
 	Logger                logger.Logger
+	Clock                 clock.Clock
 	SharedIteratorStorage *sharediterator.Storage
+
+	iteratorCacheTTLFunc func() time.Duration
 }
 
 func NewSharedDatastoreResources(
@@ -53,34 +81,52 @@ func NewSharedDatastoreResources(
 		WaitGroup:         &sync.WaitGroup{},
 		SingleflightGroup: sharedSf,
 		ServerCtx:         sharedCtx,
-		CacheController:   cachecontroller.NewNoopCacheController(),
 		Logger:            logger.NewNoopLogger(),
+		Clock:             clock.New(),
 		SharedIteratorStorage: sharediterator.NewSharedIteratorDatastoreStorage(
 			sharediterator.WithSharedIteratorDatastoreStorageLimit(
 				int(settings.SharedIteratorLimit))),
 	}
 
-	if settings.ShouldCreateNewCache() {
-		var err error
-		s.CheckCache, err = storage.NewInMemoryLRUCache([]storage.InMemoryLRUCacheOpt[any]{
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.CheckCache == nil && settings.ShouldCreateNewCache() {
+		cache, err := storage.NewInMemoryLRUCache([]storage.InMemoryLRUCacheOpt[any]{
 			storage.WithMaxCacheSize[any](int64(settings.CheckCacheLimit)),
 		}...)
 		if err != nil {
 			return nil, err
 		}
+		s.CheckCache = storage.NewFlushableCache[any](cache)
 	}
 
-	if settings.ShouldCreateCacheController() {
-		s.CacheController = cachecontroller.NewCacheController(ds, s.CheckCache, settings.CacheControllerTTL, settings.CheckIteratorCacheTTL, cachecontroller.WithLogger(s.Logger))
-	}
-
-	for _, opt := range opts {
-		opt(s)
+	if s.CacheController == nil {
+		s.CacheController = cachecontroller.NewNoopCacheController()
+		if settings.ShouldCreateCacheController() {
+			controllerOpts := []cachecontroller.InMemoryCacheControllerOpt{cachecontroller.WithLogger(s.Logger), cachecontroller.WithClock(s.Clock)}
+			if s.iteratorCacheTTLFunc != nil {
+				controllerOpts = append(controllerOpts, cachecontroller.WithIteratorCacheTTLFunc(s.iteratorCacheTTLFunc))
+			}
+			s.CacheController = cachecontroller.NewCacheController(ds, s.CheckCache, settings.CacheControllerTTL, settings.CheckIteratorCacheTTL, controllerOpts...)
+		}
 	}
 
 	return s, nil
 }
 
+// FlushCheckCache discards the entries of the check cache. It returns false if the cache cannot be flushed, i.e. if
+// there is no cache, or if it was set with WithCheckCache and has no Flush method.
+func (s *SharedDatastoreResources) FlushCheckCache() bool {
+	cache, ok := s.CheckCache.(interface{ Flush() })
+	if !ok {
+		return false
+	}
+	cache.Flush()
+	return true
+}
+
 func (s *SharedDatastoreResources) Close() {
 	// wait for any goroutines still in flight before
 	// closing the cache instance to avoid data races
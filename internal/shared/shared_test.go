@@ -11,6 +11,7 @@ import (
 	"github.com/openfga/openfga/internal/cachecontroller"
 	mockstorage "github.com/openfga/openfga/internal/mocks"
 	"github.com/openfga/openfga/pkg/server/config"
+	"github.com/openfga/openfga/pkg/storage"
 )
 
 func TestSharedDatastoreResources(t *testing.T) {
@@ -64,4 +65,22 @@ func TestSharedDatastoreResources(t *testing.T) {
 		_, ok := s.CacheController.(*cachecontroller.InMemoryCacheController)
 		require.True(t, ok)
 	})
+
+	t.Run("with_existing_cache", func(t *testing.T) {
+		settings := config.CacheSettings{
+			CheckCacheLimit:           1,
+			CheckIteratorCacheEnabled: true,
+			CacheControllerEnabled:    true,
+		}
+		cache, err := storage.NewInMemoryLRUCache[any]()
+		require.NoError(t, err)
+
+		s, err := NewSharedDatastoreResources(sharedCtx, sharedSf, mockDatastore, settings, WithCheckCache(cache))
+		require.NoError(t, err)
+		t.Cleanup(s.Close)
+
+		require.Same(t, cache, s.CheckCache)
+		_, ok := s.CacheController.(*cachecontroller.InMemoryCacheController)
+		require.True(t, ok)
+	})
 }
@@ -10,22 +10,56 @@ func (a APIMethod) String() string {
 
 // API methods.
 const (
-	ReadAuthorizationModel  APIMethod = "ReadAuthorizationModel"
-	ReadAuthorizationModels APIMethod = "ReadAuthorizationModels"
-	Read                    APIMethod = "Read"
-	Write                   APIMethod = "Write"
-	ListObjects             APIMethod = "ListObjects"
-	StreamedListObjects     APIMethod = "StreamedListObjects"
-	Check                   APIMethod = "Check"
-	BatchCheck              APIMethod = "BatchCheck"
-	ListUsers               APIMethod = "ListUsers"
-	WriteAssertions         APIMethod = "WriteAssertions"
-	ReadAssertions          APIMethod = "ReadAssertions"
-	WriteAuthorizationModel APIMethod = "WriteAuthorizationModel"
-	ListStores              APIMethod = "ListStores"
-	CreateStore             APIMethod = "CreateStore"
-	GetStore                APIMethod = "GetStore"
-	DeleteStore             APIMethod = "DeleteStore"
-	Expand                  APIMethod = "Expand"
-	ReadChanges             APIMethod = "ReadChanges"
+	ReadAuthorizationModel   APIMethod = "ReadAuthorizationModel"
+	ReadAuthorizationModels  APIMethod = "ReadAuthorizationModels"
+	Read                     APIMethod = "Read"
+	StreamedRead             APIMethod = "StreamedRead"
+	Write                    APIMethod = "Write"
+	ListObjects              APIMethod = "ListObjects"
+	StreamedListObjects      APIMethod = "StreamedListObjects"
+	Check                    APIMethod = "Check"
+	BatchCheck               APIMethod = "BatchCheck"
+	ListUsers                APIMethod = "ListUsers"
+	WriteAssertions          APIMethod = "WriteAssertions"
+	ReadAssertions           APIMethod = "ReadAssertions"
+	WriteAuthorizationModel  APIMethod = "WriteAuthorizationModel"
+	DeleteAuthorizationModel APIMethod = "DeleteAuthorizationModel"
+	ListStores               APIMethod = "ListStores"
+	CreateStore              APIMethod = "CreateStore"
+	GetStore                 APIMethod = "GetStore"
+	DeleteStore              APIMethod = "DeleteStore"
+	UpdateStore              APIMethod = "UpdateStore"
+	Expand                   APIMethod = "Expand"
+	ReadChanges              APIMethod = "ReadChanges"
 )
+
+// apiMethods are the API methods, to validate method names.
+var apiMethods = map[APIMethod]struct{}{
+	ReadAuthorizationModel:   {},
+	ReadAuthorizationModels:  {},
+	Read:                     {},
+	StreamedRead:             {},
+	Write:                    {},
+	ListObjects:              {},
+	StreamedListObjects:      {},
+	Check:                    {},
+	BatchCheck:               {},
+	ListUsers:                {},
+	WriteAssertions:          {},
+	ReadAssertions:           {},
+	WriteAuthorizationModel:  {},
+	DeleteAuthorizationModel: {},
+	ListStores:               {},
+	CreateStore:              {},
+	GetStore:                 {},
+	DeleteStore:              {},
+	UpdateStore:              {},
+	Expand:                   {},
+	ReadChanges:              {},
+}
+
+// IsValid reports whether the APIMethod is the name of an API method, e.g. 'Check'.
+func (a APIMethod) IsValid() bool {
+	_, ok := apiMethods[a]
+	return ok
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"reflect"
 
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/pkg/storage"
@@ -12,6 +15,11 @@ import (
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
+var (
+	ErrContextTooLarge = errors.New("context size limit exceeded")
+	ErrContextTooDeep  = errors.New("context depth limit exceeded")
+)
+
 // ValidateUserObjectRelation returns nil if the tuple is well-formed and valid according to the provided model.
 func ValidateUserObjectRelation(typesys *typesystem.TypeSystem, tk *openfgav1.TupleKey) error {
 	if err := ValidateUser(typesys, tk.GetUser()); err != nil {
@@ -360,3 +368,54 @@ func ValidateUser(typesys *typesystem.TypeSystem, user string) error {
 
 	return nil
 }
+
+// ValidateContext returns an error if the context of a request, which is used for condition evaluation, is larger
+// than maxSizeBytes or nested deeper than maxDepth. A struct holding no struct or list has a depth of 1.
+// A limit of 0 disables the corresponding check.
+func ValidateContext(ctx *structpb.Struct, maxSizeBytes, maxDepth uint32) error {
+	if ctx == nil {
+		return nil
+	}
+
+	if maxSizeBytes > 0 {
+		if size := proto.Size(ctx); size > int(maxSizeBytes) {
+			return fmt.Errorf("%w: %d bytes exceeds %d bytes", ErrContextTooLarge, size, maxSizeBytes)
+		}
+	}
+
+	if maxDepth > 0 && structDepth(ctx, int(maxDepth)) > int(maxDepth) {
+		return fmt.Errorf("%w: nested deeper than %d levels", ErrContextTooDeep, maxDepth)
+	}
+
+	return nil
+}
+
+// structDepth returns the nesting depth of s. It stops descending once the depth exceeds limit.
+func structDepth(s *structpb.Struct, limit int) int {
+	depth := 1
+	for _, v := range s.GetFields() {
+		depth = max(depth, 1+valueDepth(v, limit-1))
+		if depth > limit {
+			break
+		}
+	}
+	return depth
+}
+
+func valueDepth(v *structpb.Value, limit int) int {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_StructValue:
+		return structDepth(kind.StructValue, limit)
+	case *structpb.Value_ListValue:
+		depth := 1
+		for _, item := range kind.ListValue.GetValues() {
+			depth = max(depth, 1+valueDepth(item, limit-1))
+			if depth > limit {
+				break
+			}
+		}
+		return depth
+	default:
+		return 0
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
@@ -1206,3 +1207,74 @@ func BenchmarkValidateTupleForRead(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func TestValidateContext(t *testing.T) {
+	nested, err := structpb.NewStruct(map[string]interface{}{
+		"x": 1,
+		"a": map[string]interface{}{
+			"b": []interface{}{
+				map[string]interface{}{"c": "d"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	flat, err := structpb.NewStruct(map[string]interface{}{"x": 1, "y": "z"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		ctx           *structpb.Struct
+		maxSizeBytes  uint32
+		maxDepth      uint32
+		expectedError error
+	}{
+		{
+			name: "nil_context",
+		},
+		{
+			name:         "no_limits",
+			ctx:          nested,
+			maxSizeBytes: 0,
+			maxDepth:     0,
+		},
+		{
+			name:     "flat_context_within_depth",
+			ctx:      flat,
+			maxDepth: 1,
+		},
+		{
+			name:     "nested_context_within_depth",
+			ctx:      nested,
+			maxDepth: 4,
+		},
+		{
+			name:          "nested_context_too_deep",
+			ctx:           nested,
+			maxDepth:      3,
+			expectedError: ErrContextTooDeep,
+		},
+		{
+			name:          "context_too_large",
+			ctx:           nested,
+			maxSizeBytes:  10,
+			expectedError: ErrContextTooLarge,
+		},
+		{
+			name:         "context_within_size",
+			ctx:          flat,
+			maxSizeBytes: 1024,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateContext(test.ctx, test.maxSizeBytes, test.maxDepth)
+			if test.expectedError == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
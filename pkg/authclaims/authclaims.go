@@ -2,6 +2,7 @@ package authclaims
 
 import (
 	"context"
+	"slices"
 )
 
 type ctxKey string
@@ -17,6 +18,19 @@ type AuthClaims struct {
 	Subject  string
 	Scopes   map[string]bool
 	ClientID string
+
+	// StoreIDs, if not empty, restricts the credentials to the listed stores.
+	StoreIDs []string
+	// StoreScopes, if not nil, restricts the API methods the credentials may call on their stores.
+	StoreScopes map[string]bool
+
+	// CertificateSANs are the subject alternative names of the client certificate, when authenticated by mutual TLS.
+	CertificateSANs []string
+}
+
+// AllowsStore reports whether the claims grant access to the given store.
+func (c *AuthClaims) AllowsStore(storeID string) bool {
+	return len(c.StoreIDs) == 0 || slices.Contains(c.StoreIDs, storeID)
 }
 
 // ContextWithAuthClaims creates a copy of the parent context with the provided AuthClaims.
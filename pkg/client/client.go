@@ -0,0 +1,309 @@
+// Package client provides an in-process client of an OpenFGA server, for the applications embedding it. It calls the
+// handlers of the server directly, without the network hops, the serialization and the interceptors of the gRPC and
+// HTTP APIs, so the authentication and the interceptor based limits do not apply.
+package client
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// Error is an error returned by the server. It can be compared with errors.Is to the sentinel errors of this
+// package, e.g. ErrNotFound, or to an Error with a Reason, e.g. &Error{Reason: "store_id_not_found"}.
+type Error struct {
+	// Code is the gRPC code of the error, as returned by the gRPC API.
+	Code codes.Code
+
+	// Reason is the OpenFGA error code, as returned in the `code` field by the HTTP API, e.g. "validation_error".
+	Reason string
+
+	// Message describes the error.
+	Message string
+
+	err error
+}
+
+var (
+	// ErrInvalidArgument is returned when the request is invalid, e.g. its relation is not defined in the model.
+	ErrInvalidArgument = &Error{Code: codes.InvalidArgument}
+
+	// ErrNotFound is returned when the store or the authorization model is not found.
+	ErrNotFound = &Error{Code: codes.NotFound}
+
+	// ErrThrottled is returned when the request exceeded a limit of the server, e.g. its resolution was throttled.
+	ErrThrottled = &Error{Code: codes.ResourceExhausted}
+
+	// ErrDeadlineExceeded is returned when the request did not complete before its deadline.
+	ErrDeadlineExceeded = &Error{Code: codes.DeadlineExceeded}
+
+	// ErrUnavailable is returned when the server or its datastore is temporarily unavailable, e.g. as it drains.
+	ErrUnavailable = &Error{Code: codes.Unavailable}
+
+	// ErrInternal is returned when the server failed to process the request.
+	ErrInternal = &Error{Code: codes.Internal}
+)
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error returned by the server.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the target is an Error with the same code and, if the target has one, the same reason.
+func (e *Error) Is(target error) bool {
+	var t *Error
+	if !errors.As(target, &t) {
+		return false
+	}
+	if t.Code != codes.OK && t.Code != e.Code {
+		return false
+	}
+	return t.Reason == "" || t.Reason == e.Reason
+}
+
+// newError converts the error returned by a handler of the server to an Error. The context errors are returned as
+// is, so that errors.Is(err, context.Canceled) holds.
+func newError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return &Error{Code: codes.Internal, Reason: openfgav1.InternalErrorCode_internal_error.String(), Message: err.Error(), err: err}
+	}
+
+	encoded := serverErrors.NewEncodedError(serverErrors.ConvertToEncodedErrorCode(st), st.Message())
+	return &Error{
+		Code:    encoded.GRPCStatusCode,
+		Reason:  encoded.Code(),
+		Message: encoded.Error(),
+		err:     err,
+	}
+}
+
+// Client calls the handlers of an in-process server.
+type Client struct {
+	server *server.Server
+}
+
+// New returns a client of the server. Closing the server is up to the caller.
+func New(s *server.Server) *Client {
+	return &Client{server: s}
+}
+
+// Option sets an optional field of a request.
+type Option func(*options)
+
+type options struct {
+	modelID          string
+	contextualTuples []*openfgav1.TupleKey
+	context          map[string]any
+	consistency      openfgav1.ConsistencyPreference
+	pageSize         int32
+	token            string
+}
+
+// WithAuthorizationModelID sets the ID of the authorization model to evaluate the request with. Defaults to the
+// latest model of the store.
+func WithAuthorizationModelID(id string) Option {
+	return func(o *options) {
+		o.modelID = id
+	}
+}
+
+// WithContextualTuples adds tuples to the tuples of the store, for this request only.
+func WithContextualTuples(tuples ...*openfgav1.TupleKey) Option {
+	return func(o *options) {
+		o.contextualTuples = append(o.contextualTuples, tuples...)
+	}
+}
+
+// WithContext sets the context the conditions are evaluated with. Its values must be convertible by
+// structpb.NewStruct.
+func WithContext(context map[string]any) Option {
+	return func(o *options) {
+		o.context = context
+	}
+}
+
+// WithConsistency sets the consistency preference of the request.
+func WithConsistency(consistency openfgav1.ConsistencyPreference) Option {
+	return func(o *options) {
+		o.consistency = consistency
+	}
+}
+
+// WithPageSize sets the maximum number of results of a paginated request.
+func WithPageSize(pageSize int32) Option {
+	return func(o *options) {
+		o.pageSize = pageSize
+	}
+}
+
+// WithContinuationToken sets the continuation token returned by the previous page of a paginated request.
+func WithContinuationToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// requestContext converts the context of the conditions to the protobuf struct of the requests.
+func (o *options) requestContext() (*structpb.Struct, error) {
+	if o.context == nil {
+		return nil, nil
+	}
+	s, err := structpb.NewStruct(o.context)
+	if err != nil {
+		return nil, &Error{Code: codes.InvalidArgument, Reason: openfgav1.ErrorCode_validation_error.String(), Message: "invalid context: " + err.Error(), err: err}
+	}
+	return s, nil
+}
+
+func (o *options) requestContextualTuples() *openfgav1.ContextualTupleKeys {
+	if len(o.contextualTuples) == 0 {
+		return nil
+	}
+	return &openfgav1.ContextualTupleKeys{TupleKeys: o.contextualTuples}
+}
+
+// Check returns whether the user has the relation with the object, e.g.
+//
+//	allowed, err := c.Check(ctx, storeID, "user:anne", "viewer", "document:1")
+func (c *Client) Check(ctx context.Context, storeID, user, relation, object string, opts ...Option) (bool, error) {
+	o := newOptions(opts)
+	reqContext, err := o.requestContext()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.server.Check(ctx, &openfgav1.CheckRequest{
+		StoreId:              storeID,
+		AuthorizationModelId: o.modelID,
+		TupleKey:             tuple.NewCheckRequestTupleKey(object, relation, user),
+		ContextualTuples:     o.requestContextualTuples(),
+		Context:              reqContext,
+		Consistency:          o.consistency,
+	})
+	if err != nil {
+		return false, newError(err)
+	}
+	return res.GetAllowed(), nil
+}
+
+// ListObjects returns the objects of the type the user has the relation with, e.g.
+//
+//	objects, err := c.ListObjects(ctx, storeID, "user:anne", "viewer", "document")
+func (c *Client) ListObjects(ctx context.Context, storeID, user, relation, objectType string, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+	reqContext, err := o.requestContext()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.server.ListObjects(ctx, &openfgav1.ListObjectsRequest{
+		StoreId:              storeID,
+		AuthorizationModelId: o.modelID,
+		User:                 user,
+		Relation:             relation,
+		Type:                 objectType,
+		ContextualTuples:     o.requestContextualTuples(),
+		Context:              reqContext,
+		Consistency:          o.consistency,
+	})
+	if err != nil {
+		return nil, newError(err)
+	}
+	return res.GetObjects(), nil
+}
+
+// ListUsers returns the users of the type which have the relation with the object, e.g.
+//
+//	users, err := c.ListUsers(ctx, storeID, "document:1", "viewer", "user")
+func (c *Client) ListUsers(ctx context.Context, storeID, object, relation, userType string, opts ...Option) ([]*openfgav1.User, error) {
+	o := newOptions(opts)
+	reqContext, err := o.requestContext()
+	if err != nil {
+		return nil, err
+	}
+
+	objectType, objectID := tuple.SplitObject(object)
+	res, err := c.server.ListUsers(ctx, &openfgav1.ListUsersRequest{
+		StoreId:              storeID,
+		AuthorizationModelId: o.modelID,
+		Object:               &openfgav1.Object{Type: objectType, Id: objectID},
+		Relation:             relation,
+		UserFilters:          []*openfgav1.UserTypeFilter{{Type: userType}},
+		ContextualTuples:     o.contextualTuples,
+		Context:              reqContext,
+		Consistency:          o.consistency,
+	})
+	if err != nil {
+		return nil, newError(err)
+	}
+	return res.GetUsers(), nil
+}
+
+// WriteTuples writes the tuples to the store, e.g.
+//
+//	err := c.WriteTuples(ctx, storeID, tuple.NewTupleKey("document:1", "viewer", "user:anne"))
+func (c *Client) WriteTuples(ctx context.Context, storeID string, tuples ...*openfgav1.TupleKey) error {
+	_, err := c.server.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes:  &openfgav1.WriteRequestWrites{TupleKeys: tuples},
+	})
+	return newError(err)
+}
+
+// DeleteTuples deletes the tuples from the store.
+func (c *Client) DeleteTuples(ctx context.Context, storeID string, tuples ...*openfgav1.TupleKeyWithoutCondition) error {
+	_, err := c.server.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Deletes: &openfgav1.WriteRequestDeletes{TupleKeys: tuples},
+	})
+	return newError(err)
+}
+
+// Read returns a page of the tuples of the store matching the filter, and the continuation token of the next page,
+// empty on the last page. All the tuples are read if the filter is nil.
+func (c *Client) Read(ctx context.Context, storeID string, filter *openfgav1.ReadRequestTupleKey, opts ...Option) ([]*openfgav1.Tuple, string, error) {
+	o := newOptions(opts)
+	var pageSize *wrapperspb.Int32Value
+	if o.pageSize > 0 {
+		pageSize = wrapperspb.Int32(o.pageSize)
+	}
+
+	res, err := c.server.Read(ctx, &openfgav1.ReadRequest{
+		StoreId:           storeID,
+		TupleKey:          filter,
+		PageSize:          pageSize,
+		ContinuationToken: o.token,
+		Consistency:       o.consistency,
+	})
+	if err != nil {
+		return nil, "", newError(err)
+	}
+	return res.GetTuples(), res.GetContinuationToken(), nil
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestClient(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	s := server.MustNewServerWithOpts(server.WithDatastore(ds))
+	t.Cleanup(s.Close)
+	c := New(s)
+
+	ctx := context.Background()
+	store, err := s.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "openfga-test"})
+	require.NoError(t, err)
+	storeID := store.GetId()
+
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user, user with non_expired]
+
+		condition non_expired(expired: bool) {
+			!expired
+		}
+	`)
+	_, err = s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:         storeID,
+		SchemaVersion:   model.GetSchemaVersion(),
+		TypeDefinitions: model.GetTypeDefinitions(),
+		Conditions:      model.GetConditions(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, c.WriteTuples(ctx, storeID,
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKeyWithCondition("document:2", "viewer", "user:anne", "non_expired", nil),
+	))
+
+	t.Run("check", func(t *testing.T) {
+		allowed, err := c.Check(ctx, storeID, "user:anne", "viewer", "document:1")
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		allowed, err = c.Check(ctx, storeID, "user:bob", "viewer", "document:1")
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		allowed, err = c.Check(ctx, storeID, "user:bob", "viewer", "document:1",
+			WithContextualTuples(tuple.NewTupleKey("document:1", "viewer", "user:bob")))
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		allowed, err = c.Check(ctx, storeID, "user:anne", "viewer", "document:2", WithContext(map[string]any{"expired": false}))
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+
+	t.Run("list_objects", func(t *testing.T) {
+		objects, err := c.ListObjects(ctx, storeID, "user:anne", "viewer", "document", WithContext(map[string]any{"expired": true}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"document:1"}, objects)
+	})
+
+	t.Run("list_users", func(t *testing.T) {
+		users, err := c.ListUsers(ctx, storeID, "document:1", "viewer", "user")
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		require.Equal(t, "anne", users[0].GetObject().GetId())
+	})
+
+	t.Run("read_and_delete", func(t *testing.T) {
+		tuples, token, err := c.Read(ctx, storeID, nil, WithPageSize(1))
+		require.NoError(t, err)
+		require.Len(t, tuples, 1)
+		require.NotEmpty(t, token)
+
+		require.NoError(t, c.DeleteTuples(ctx, storeID, tuple.TupleKeyToTupleKeyWithoutCondition(tuple.NewTupleKey("document:1", "viewer", "user:anne"))))
+		allowed, err := c.Check(ctx, storeID, "user:anne", "viewer", "document:1")
+		require.NoError(t, err)
+		require.False(t, allowed)
+	})
+
+	t.Run("typed_errors", func(t *testing.T) {
+		_, err := c.Check(ctx, storeID, "user:anne", "undefined", "document:1")
+		require.ErrorIs(t, err, ErrInvalidArgument)
+		require.NotErrorIs(t, err, ErrNotFound)
+
+		_, err = c.Check(ctx, storeID, "user:anne", "viewer", "document:1", WithAuthorizationModelID("01JQ0000000000000000000000"))
+		require.ErrorIs(t, err, &Error{Reason: openfgav1.ErrorCode_authorization_model_not_found.String()})
+
+		var clientErr *Error
+		require.True(t, errors.As(err, &clientErr))
+		require.Equal(t, codes.InvalidArgument, clientErr.Code)
+		require.NotEmpty(t, clientErr.Message)
+
+		err = c.WriteTuples(ctx, "01JQ0000000000000000000000", tuple.NewTupleKey("document:1", "viewer", "user:anne"))
+		require.ErrorIs(t, err, &Error{Code: codes.InvalidArgument, Reason: openfgav1.ErrorCode_latest_authorization_model_not_found.String()})
+	})
+}
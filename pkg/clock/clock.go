@@ -0,0 +1,69 @@
+// Package clock provides the clock the server reads the current time from, so that tests can control the time, e.g.
+// to expire cache entries. Its Clock interface is a subset of the clocks of github.com/jonboulle/clockwork, which
+// can thus be used in its place.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Until(t time.Time) time.Duration
+}
+
+type realClock struct{}
+
+// New returns the clock of the system.
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+// FakeClock is a clock whose time only changes when advanced.
+type FakeClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+var _ Clock = (*FakeClock)(nil)
+
+// NewFake returns a fake clock at the given time.
+func NewFake(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (f *FakeClock) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+func (f *FakeClock) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *FakeClock) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+// Advance moves the time of the clock forward by d.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -0,0 +1,132 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ContinuationTokenVersion is the version of the continuation tokens encoded by VersionedTokenEncoder. It must be
+// incremented whenever the format of the tokens changes, so that the tokens of a previous version are detected.
+const ContinuationTokenVersion byte = 1
+
+// versionedTokenMagic prefixes the versioned tokens. It is not ASCII, unlike the unversioned tokens, so that both can
+// be told apart.
+var versionedTokenMagic = []byte{0xfa, 0x67}
+
+// versionedTokenHeaderSize is the size of the magic, the version and the expiry prefixing the data.
+const versionedTokenHeaderSize = 2 + 1 + 8
+
+var (
+	// ErrContinuationTokenExpired is returned when decoding a continuation token past its expiry.
+	ErrContinuationTokenExpired = errors.New("continuation token expired")
+
+	// ErrContinuationTokenIncompatible is returned when decoding a continuation token of another version.
+	ErrContinuationTokenIncompatible = errors.New("continuation token is incompatible with this server version")
+)
+
+// Ensure VersionedTokenEncoder implements the Encoder interface.
+var _ Encoder = (*VersionedTokenEncoder)(nil)
+
+// VersionedTokenEncoder prefixes the continuation tokens with their version and, optionally, their expiry, before
+// encoding them with its encoder. The tokens without a version, encoded before the versioning, are still decoded,
+// unless limited with WithUnversionedTokensUntil.
+type VersionedTokenEncoder struct {
+	encoder Encoder
+	ttl     time.Duration
+	now     func() time.Time
+
+	limitUnversioned bool
+	unversionedUntil time.Time
+}
+
+// VersionedTokenEncoderOpt defines an option of a VersionedTokenEncoder.
+type VersionedTokenEncoderOpt func(*VersionedTokenEncoder)
+
+// WithTokenTTL makes the tokens expire once ttl elapsed. If 0, the default, the tokens never expire.
+func WithTokenTTL(ttl time.Duration) VersionedTokenEncoderOpt {
+	return func(e *VersionedTokenEncoder) {
+		e.ttl = ttl
+	}
+}
+
+// WithUnversionedTokensUntil accepts the tokens without a version, which have no expiry, only until deadline. Past it,
+// they are rejected with ErrContinuationTokenIncompatible.
+func WithUnversionedTokensUntil(deadline time.Time) VersionedTokenEncoderOpt {
+	return func(e *VersionedTokenEncoder) {
+		e.limitUnversioned = true
+		e.unversionedUntil = deadline
+	}
+}
+
+// WithTokenClock sets the function returning the current time, when encoding and checking the expiry of tokens.
+func WithTokenClock(now func() time.Time) VersionedTokenEncoderOpt {
+	return func(e *VersionedTokenEncoder) {
+		e.now = now
+	}
+}
+
+// NewVersionedTokenEncoder constructs a VersionedTokenEncoder encoding the versioned tokens with encoder.
+func NewVersionedTokenEncoder(encoder Encoder, opts ...VersionedTokenEncoderOpt) *VersionedTokenEncoder {
+	e := &VersionedTokenEncoder{
+		encoder: encoder,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Decode decodes the input string with its encoder, then checks the version and the expiry of the token. It returns
+// ErrContinuationTokenIncompatible if the token has another version, or no version past the deadline of
+// WithUnversionedTokensUntil, and ErrContinuationTokenExpired if it expired.
+func (e *VersionedTokenEncoder) Decode(s string) ([]byte, error) {
+	decoded, err := e.encoder.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(decoded, versionedTokenMagic) {
+		// the token was encoded before the versioning
+		if e.limitUnversioned && e.now().After(e.unversionedUntil) {
+			return nil, ErrContinuationTokenIncompatible
+		}
+		return decoded, nil
+	}
+	if len(decoded) < versionedTokenHeaderSize {
+		return nil, ErrContinuationTokenIncompatible
+	}
+	if version := decoded[len(versionedTokenMagic)]; version != ContinuationTokenVersion {
+		return nil, ErrContinuationTokenIncompatible
+	}
+
+	expiry := int64(binary.BigEndian.Uint64(decoded[len(versionedTokenMagic)+1 : versionedTokenHeaderSize]))
+	if expiry != 0 && e.now().Unix() > expiry {
+		return nil, ErrContinuationTokenExpired
+	}
+
+	return decoded[versionedTokenHeaderSize:], nil
+}
+
+// Encode prefixes the data with the version and the expiry of the token, then encodes it with its encoder. Empty data,
+// i.e. the absence of a continuation token, is encoded as is.
+func (e *VersionedTokenEncoder) Encode(data []byte) (string, error) {
+	if len(data) == 0 {
+		return e.encoder.Encode(data)
+	}
+
+	var expiry int64
+	if e.ttl > 0 {
+		expiry = e.now().Add(e.ttl).Unix()
+	}
+
+	versioned := make([]byte, 0, versionedTokenHeaderSize+len(data))
+	versioned = append(versioned, versionedTokenMagic...)
+	versioned = append(versioned, ContinuationTokenVersion)
+	versioned = binary.BigEndian.AppendUint64(versioned, uint64(expiry))
+	versioned = append(versioned, data...)
+
+	return e.encoder.Encode(versioned)
+}
@@ -0,0 +1,80 @@
+package encoder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionedTokenEncoder(t *testing.T) {
+	now := time.Now()
+	encoder := NewVersionedTokenEncoder(NewBase64Encoder(), WithTokenTTL(time.Minute), WithTokenClock(func() time.Time { return now }))
+
+	t.Run("empty", func(t *testing.T) {
+		encoded, err := encoder.Encode(nil)
+		require.NoError(t, err)
+		require.Empty(t, encoded)
+
+		decoded, err := encoder.Decode("")
+		require.NoError(t, err)
+		require.Empty(t, decoded)
+	})
+
+	t.Run("encode_decode", func(t *testing.T) {
+		encoded, err := encoder.Encode([]byte("01JQ6D4XQ8ZW3|document"))
+		require.NoError(t, err)
+
+		decoded, err := encoder.Decode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, []byte("01JQ6D4XQ8ZW3|document"), decoded)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		encoded, err := encoder.Encode([]byte("01JQ6D4XQ8ZW3|document"))
+		require.NoError(t, err)
+
+		later := NewVersionedTokenEncoder(NewBase64Encoder(), WithTokenClock(func() time.Time { return now.Add(2 * time.Minute) }))
+		_, err = later.Decode(encoded)
+		require.ErrorIs(t, err, ErrContinuationTokenExpired)
+	})
+
+	t.Run("unversioned", func(t *testing.T) {
+		encoded, err := NewBase64Encoder().Encode([]byte("01JQ6D4XQ8ZW3|document"))
+		require.NoError(t, err)
+
+		decoded, err := encoder.Decode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, []byte("01JQ6D4XQ8ZW3|document"), decoded)
+	})
+
+	t.Run("unversioned_past_the_transition_window", func(t *testing.T) {
+		encoded, err := NewBase64Encoder().Encode([]byte("01JQ6D4XQ8ZW3|document"))
+		require.NoError(t, err)
+
+		within := NewVersionedTokenEncoder(NewBase64Encoder(), WithUnversionedTokensUntil(now.Add(time.Minute)), WithTokenClock(func() time.Time { return now }))
+		decoded, err := within.Decode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, []byte("01JQ6D4XQ8ZW3|document"), decoded)
+
+		past := NewVersionedTokenEncoder(NewBase64Encoder(), WithUnversionedTokensUntil(now), WithTokenClock(func() time.Time { return now.Add(time.Second) }))
+		_, err = past.Decode(encoded)
+		require.ErrorIs(t, err, ErrContinuationTokenIncompatible)
+
+		// the versioned tokens are still accepted
+		encoded, err = encoder.Encode([]byte("01JQ6D4XQ8ZW3|document"))
+		require.NoError(t, err)
+		decoded, err = past.Decode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, []byte("01JQ6D4XQ8ZW3|document"), decoded)
+	})
+
+	t.Run("incompatible", func(t *testing.T) {
+		token := append([]byte{0xfa, 0x67, ContinuationTokenVersion + 1}, make([]byte, 8)...)
+		encoded, err := NewBase64Encoder().Encode(append(token, "data"...))
+		require.NoError(t, err)
+
+		_, err = encoder.Decode(encoded)
+		require.ErrorIs(t, err, ErrContinuationTokenIncompatible)
+	})
+}
@@ -0,0 +1,303 @@
+package connect
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	connectrpc "connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/openfga/openfga/pkg/gateway"
+	httpmiddleware "github.com/openfga/openfga/pkg/middleware/http"
+)
+
+const (
+	trailerPrefix = "Trailer-"
+
+	exposeHeadersHeader = "Access-Control-Expose-Headers"
+	allowOriginHeader   = "Access-Control-Allow-Origin"
+)
+
+// AllowedHeaders are the request headers of the protocol the browsers must be allowed to send with CORS. The
+// 'Trailer-' response headers of the unary methods are exposed by the handler itself, as their names vary.
+var AllowedHeaders = []string{"Connect-Protocol-Version", "Connect-Timeout-Ms", "Connect-Content-Encoding", "Connect-Accept-Encoding"}
+
+// Handler serves the unary and server streaming methods of a gRPC service over the Connect protocol with the handlers
+// of connect-go, which also accept the gRPC and gRPC-Web protocols. It forwards the requests to the service through
+// a gRPC client connection, like the HTTP gateway, so that they go through the same interceptors, e.g. the
+// authentication and the validation of the requests.
+//
+// The request headers are forwarded as gRPC metadata, and the gRPC headers and trailers of the responses are returned
+// as response headers and trailers.
+//
+// The unary methods without side effects, i.e. marked with the NO_SIDE_EFFECTS idempotency level or allowed with
+// WithGetMethods, can also be called with GET requests, their message being in the query of the URL.
+type Handler struct {
+	conn            grpc.ClientConnInterface
+	path            string
+	handlers        map[string]http.Handler
+	defaultTimeout  time.Duration
+	maxMessageBytes int
+	getMethods      []string
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithDefaultTimeout sets the timeout of the requests that do not set one with the Connect-Timeout-Ms header.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(h *Handler) {
+		h.defaultTimeout = timeout
+	}
+}
+
+// WithMaxMessageBytes sets the maximum size of the request messages, 4MB by default like the gRPC servers. It should
+// be the maximum size of the messages the gRPC server receives.
+func WithMaxMessageBytes(size int) Option {
+	return func(h *Handler) {
+		h.maxMessageBytes = size
+	}
+}
+
+// WithGetMethods allows the GET requests of the unary methods names, e.g. 'Check', as they have no side effects
+// although they are not marked with the NO_SIDE_EFFECTS idempotency level.
+func WithGetMethods(names ...string) Option {
+	return func(h *Handler) {
+		h.getMethods = append(h.getMethods, names...)
+	}
+}
+
+// NewHandler returns a handler serving the service serviceName, e.g. 'openfga.v1.OpenFGAService', through conn. The
+// service must be registered in the global protobuf registry.
+func NewHandler(conn grpc.ClientConnInterface, serviceName string, opts ...Option) (*Handler, error) {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the service %q: %w", serviceName, err)
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", serviceName)
+	}
+
+	h := &Handler{
+		conn:            conn,
+		path:            "/" + serviceName + "/",
+		handlers:        map[string]http.Handler{},
+		maxMessageBytes: gateway.DefaultMaxMessageBytes,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	for _, name := range h.getMethods {
+		md := service.Methods().ByName(protoreflect.Name(name))
+		if md == nil || md.IsStreamingClient() || md.IsStreamingServer() {
+			return nil, fmt.Errorf("%q is not a unary method of %q", name, serviceName)
+		}
+	}
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		if md.IsStreamingClient() {
+			// client streaming requires HTTP/2 end to end, which the HTTP server does not guarantee
+			continue
+		}
+
+		handlerOpts := []connectrpc.HandlerOption{
+			connectrpc.WithSchema(md),
+			connectrpc.WithRequestInitializer(initializeRequest),
+			connectrpc.WithReadMaxBytes(h.maxMessageBytes),
+		}
+		if noSideEffects(md) || slices.Contains(h.getMethods, string(md.Name())) {
+			handlerOpts = append(handlerOpts, connectrpc.WithIdempotency(connectrpc.IdempotencyNoSideEffects))
+		}
+
+		procedure := h.path + string(md.Name())
+		if md.IsStreamingServer() {
+			h.handlers[string(md.Name())] = connectrpc.NewServerStreamHandler(procedure, h.serverStream(procedure, md), handlerOpts...)
+		} else {
+			h.handlers[string(md.Name())] = connectrpc.NewUnaryHandler(procedure, h.unary(procedure, md), handlerOpts...)
+		}
+	}
+	return h, nil
+}
+
+// noSideEffects reports whether md is marked with the NO_SIDE_EFFECTS idempotency level.
+func noSideEffects(md protoreflect.MethodDescriptor) bool {
+	opts, ok := md.Options().(*descriptorpb.MethodOptions)
+	return ok && opts.GetIdempotencyLevel() == descriptorpb.MethodOptions_NO_SIDE_EFFECTS
+}
+
+// initializeRequest initializes the request messages of the handlers with the input of their method.
+func initializeRequest(spec connectrpc.Spec, message any) error {
+	md, ok := spec.Schema.(protoreflect.MethodDescriptor)
+	if !ok {
+		return fmt.Errorf("the procedure %q has no method descriptor", spec.Procedure)
+	}
+	msg, ok := message.(*dynamicpb.Message)
+	if !ok {
+		return fmt.Errorf("unexpected request message %T", message)
+	}
+	*msg = *dynamicpb.NewMessage(md.Input())
+	return nil
+}
+
+// Mount returns a handler serving the requests of the service with h, and the other requests with next.
+func (h *Handler) Mount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, h.path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type exposeTrailersKey struct{}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, ok := h.handlers[strings.TrimPrefix(r.URL.Path, h.path)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	ctx := metadata.NewOutgoingContext(r.Context(), gateway.RequestMetadata(r, AllowedHeaders...))
+	// the CORS handler sets the allowed origin before the handler is called
+	if w.Header().Get(allowOriginHeader) != "" {
+		ctx = context.WithValue(ctx, exposeTrailersKey{}, true)
+	}
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (h *Handler) unary(procedure string, md protoreflect.MethodDescriptor) func(context.Context, *connectrpc.Request[dynamicpb.Message]) (*connectrpc.Response[dynamicpb.Message], error) {
+	return func(ctx context.Context, req *connectrpc.Request[dynamicpb.Message]) (*connectrpc.Response[dynamicpb.Message], error) {
+		ctx, cancel := h.context(ctx)
+		defer cancel()
+
+		res := dynamicpb.NewMessage(md.Output())
+		var header, trailer metadata.MD
+		if err := h.conn.Invoke(ctx, procedure, req.Msg, res, grpc.Header(&header), grpc.Trailer(&trailer)); err != nil {
+			return nil, newError(err, header, trailer)
+		}
+
+		response := connectrpc.NewResponse(res)
+		writeMetadata(response.Header(), header)
+		writeMetadata(response.Trailer(), trailer)
+		if expose, _ := ctx.Value(exposeTrailersKey{}).(bool); expose {
+			exposeTrailers(response.Header(), response.Trailer())
+		}
+		return response, nil
+	}
+}
+
+func (h *Handler) serverStream(procedure string, md protoreflect.MethodDescriptor) func(context.Context, *connectrpc.Request[dynamicpb.Message], *connectrpc.ServerStream[dynamicpb.Message]) error {
+	desc := &grpc.StreamDesc{ServerStreams: true}
+	return func(ctx context.Context, req *connectrpc.Request[dynamicpb.Message], stream *connectrpc.ServerStream[dynamicpb.Message]) error {
+		ctx, cancel := h.context(ctx)
+		defer cancel()
+
+		clientStream, err := h.conn.NewStream(ctx, desc, procedure)
+		if err != nil {
+			return newError(err, nil, nil)
+		}
+		// an error of SendMsg is returned by RecvMsg
+		if err := clientStream.SendMsg(req.Msg); err == nil {
+			_ = clientStream.CloseSend()
+		}
+
+		headerWritten := false
+		for {
+			res := dynamicpb.NewMessage(md.Output())
+			if err = clientStream.RecvMsg(res); err != nil {
+				break
+			}
+
+			if !headerWritten {
+				header, _ := clientStream.Header()
+				writeMetadata(stream.ResponseHeader(), header)
+				headerWritten = true
+			}
+			if err := stream.Send(res); err != nil {
+				// the client is gone
+				return err
+			}
+		}
+
+		if !headerWritten {
+			header, _ := clientStream.Header()
+			writeMetadata(stream.ResponseHeader(), header)
+		}
+		writeMetadata(stream.ResponseTrailer(), clientStream.Trailer())
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return newError(err, nil, nil)
+	}
+}
+
+// context returns the context of the gRPC call, with the default timeout if the request did not set one.
+func (h *Handler) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || h.defaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, h.defaultTimeout)
+}
+
+// newError returns the Connect error of the gRPC error err, with the metadata of its response.
+func newError(err error, header, trailer metadata.MD) error {
+	st := status.Convert(err)
+	connectErr := connectrpc.NewError(connectrpc.Code(st.Code()), errors.New(st.Message()))
+	for _, detail := range st.Proto().GetDetails() {
+		if errorDetail, err := connectrpc.NewErrorDetail(detail); err == nil {
+			connectErr.AddDetail(errorDetail)
+		}
+	}
+	writeMetadata(connectErr.Meta(), header)
+	writeMetadata(connectErr.Meta(), trailer)
+	return connectErr
+}
+
+// writeMetadata writes the gRPC metadata md as headers.
+func writeMetadata(header http.Header, md metadata.MD) {
+	for key, values := range md {
+		if key == "content-type" || key == httpmiddleware.XHttpCode || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.RawStdEncoding.EncodeToString([]byte(value))
+			}
+			header.Add(key, value)
+		}
+	}
+}
+
+// exposeTrailers allows the browsers to read the 'Trailer-' headers of a unary response, as the trailers are sent as
+// headers by the Connect protocol.
+func exposeTrailers(header, trailer http.Header) {
+	trailers := make([]string, 0, len(trailer))
+	for key := range trailer {
+		trailers = append(trailers, trailerPrefix+key)
+	}
+	if len(trailers) > 0 {
+		sort.Strings(trailers)
+		header.Add(exposeHeadersHeader, strings.Join(trailers, ", "))
+	}
+}
@@ -0,0 +1,331 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+type testServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+}
+
+func (s *testServer) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("authorization")) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+	if req.GetStoreId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing store id")
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("openfga-authorization-model-id", "01JMODEL"))
+	_ = grpc.SetTrailer(ctx, metadata.Pairs("openfga-datastore-query-count", "2"))
+	return &openfgav1.CheckResponse{Allowed: req.GetTupleKey().GetUser() == "user:anne"}, nil
+}
+
+func (s *testServer) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest, srv openfgav1.OpenFGAService_StreamedListObjectsServer) error {
+	if req.GetType() != "document" {
+		return status.Error(codes.InvalidArgument, "unknown type")
+	}
+	for _, object := range []string{"document:1", "document:2"} {
+		if err := srv.Send(&openfgav1.StreamedListObjectsResponse{Object: object}); err != nil {
+			return err
+		}
+	}
+	srv.SetTrailer(metadata.Pairs("openfga-datastore-query-count", "3"))
+	return nil
+}
+
+func newTestHandler(t *testing.T, opts ...Option) http.Handler {
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	openfgav1.RegisterOpenFGAServiceServer(srv, &testServer{})
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	handler, err := NewHandler(conn, openfgav1.OpenFGAService_ServiceDesc.ServiceName, opts...)
+	require.NoError(t, err)
+
+	return handler.Mount(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+}
+
+const flagEndStream = 0b10
+
+// wireError is the JSON representation of an error in the Connect protocol.
+type wireError struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// endStreamMessage is the last message of a streaming response, with its error, if any, and its trailers.
+type endStreamMessage struct {
+	Error    *wireError          `json:"error,omitempty"`
+	Metadata map[string][]string `json:"metadata,omitempty"`
+}
+
+func envelope(flags byte, data []byte) []byte {
+	var prefix [5]byte
+	prefix[0] = flags
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(data)))
+	return append(prefix[:], data...)
+}
+
+func TestUnary(t *testing.T) {
+	handler := newTestHandler(t)
+
+	post := func(contentType string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/Check", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Connect-Protocol-Version", "1")
+		req.Header.Set("Authorization", "Bearer key")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("json", func(t *testing.T) {
+		rec := post("application/json",
+			[]byte(`{"store_id":"01JSTORE","tuple_key":{"object":"document:1","relation":"viewer","user":"user:anne"}}`), nil)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"allowed":true}`, rec.Body.String())
+		require.Equal(t, "01JMODEL", rec.Header().Get("Openfga-Authorization-Model-Id"))
+		require.Equal(t, "2", rec.Header().Get("Trailer-Openfga-Datastore-Query-Count"))
+	})
+
+	t.Run("proto", func(t *testing.T) {
+		body, err := proto.Marshal(&openfgav1.CheckRequest{
+			StoreId:  "01JSTORE",
+			TupleKey: &openfgav1.CheckRequestTupleKey{Object: "document:1", Relation: "viewer", User: "user:bob"},
+		})
+		require.NoError(t, err)
+
+		rec := post("application/proto", body, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var res openfgav1.CheckResponse
+		require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &res))
+		require.False(t, res.GetAllowed())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		rec := post("application/json", []byte(`{}`), nil)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.JSONEq(t, `{"code":"invalid_argument","message":"missing store id"}`, rec.Body.String())
+
+		rec = post("application/json", []byte(`{"store_id":`), nil)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = post("application/json", []byte(`{}`), map[string]string{"Connect-Timeout-Ms": "soon"})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = post("application/json", []byte(`{}`), map[string]string{"Content-Encoding": "br"})
+		require.Equal(t, http.StatusNotImplemented, rec.Code)
+
+		rec = post("text/plain", []byte(`{}`), nil)
+		require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("routing", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openfga.v1.OpenFGAService/Check", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/Unknown", nil))
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/01JSTORE/check", nil))
+		require.Equal(t, http.StatusTeapot, rec.Code)
+	})
+}
+
+func TestUnaryGet(t *testing.T) {
+	handler := newTestHandler(t, WithGetMethods("Check"))
+
+	get := func(query url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/openfga.v1.OpenFGAService/Check?"+query.Encode(), nil)
+		req.Header.Set("Authorization", "Bearer key")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("json", func(t *testing.T) {
+		rec := get(url.Values{
+			"connect":  {"v1"},
+			"encoding": {"json"},
+			"message":  {`{"store_id":"01JSTORE","tuple_key":{"object":"document:1","relation":"viewer","user":"user:anne"}}`},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"allowed":true}`, rec.Body.String())
+	})
+
+	t.Run("base64_proto", func(t *testing.T) {
+		body, err := proto.Marshal(&openfgav1.CheckRequest{
+			StoreId:  "01JSTORE",
+			TupleKey: &openfgav1.CheckRequestTupleKey{Object: "document:1", Relation: "viewer", User: "user:anne"},
+		})
+		require.NoError(t, err)
+
+		rec := get(url.Values{"encoding": {"proto"}, "base64": {"1"}, "message": {base64.URLEncoding.EncodeToString(body)}})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var res openfgav1.CheckResponse
+		require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &res))
+		require.True(t, res.GetAllowed())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		rec := get(url.Values{"encoding": {"xml"}, "message": {"{}"}})
+		require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+		rec = get(url.Values{"connect": {"v2"}, "encoding": {"json"}, "message": {"{}"}})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = get(url.Values{"encoding": {"json"}, "message": {"{}"}, "compression": {"br"}})
+		require.Equal(t, http.StatusNotImplemented, rec.Code)
+	})
+
+	t.Run("only_the_methods_without_side_effects", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openfga.v1.OpenFGAService/Write?encoding=json&message={}", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		require.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/openfga.v1.OpenFGAService/Check", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		require.Equal(t, "GET, POST", rec.Header().Get("Allow"))
+
+		_, err := NewHandler(nil, openfgav1.OpenFGAService_ServiceDesc.ServiceName, WithGetMethods("StreamedListObjects"))
+		require.Error(t, err)
+	})
+}
+
+func TestMaxMessageBytes(t *testing.T) {
+	handler := newTestHandler(t, WithMaxMessageBytes(64))
+
+	body := []byte(`{"store_id":"01JSTORE","tuple_key":{"object":"document:1","relation":"viewer","user":"user:` + strings.Repeat("a", 64) + `"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/Check", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Contains(t, rec.Body.String(), "resource_exhausted")
+
+	req = httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/StreamedListObjects", bytes.NewReader(envelope(0, body)))
+	req.Header.Set("Content-Type", "application/connect+json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Contains(t, rec.Body.String(), "resource_exhausted")
+}
+
+func TestExposedTrailers(t *testing.T) {
+	handler := newTestHandler(t)
+
+	check := func(allowOrigin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/Check",
+			strings.NewReader(`{"store_id":"01JSTORE","tuple_key":{"object":"document:1","relation":"viewer","user":"user:anne"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer key")
+		rec := httptest.NewRecorder()
+		// the CORS handler sets the allowed origin before the handler is called
+		if allowOrigin != "" {
+			rec.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+		}
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := check("https://example.com")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "Trailer-Openfga-Datastore-Query-Count", rec.Header().Get("Access-Control-Expose-Headers"))
+
+	rec = check("")
+	require.Empty(t, rec.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestServerStreaming(t *testing.T) {
+	handler := newTestHandler(t)
+
+	stream := func(body string) (messages []string, end endStreamMessage) {
+		req := httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/StreamedListObjects",
+			bytes.NewReader(envelope(0, []byte(body))))
+		req.Header.Set("Content-Type", "application/connect+json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/connect+json", rec.Header().Get("Content-Type"))
+
+		for {
+			var prefix [5]byte
+			_, err := io.ReadFull(rec.Body, prefix[:])
+			require.NoError(t, err)
+			data := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+			_, err = io.ReadFull(rec.Body, data)
+			require.NoError(t, err)
+
+			if prefix[0]&flagEndStream != 0 {
+				require.NoError(t, json.Unmarshal(data, &end))
+				require.Zero(t, rec.Body.Len())
+				return messages, end
+			}
+			messages = append(messages, strings.TrimSpace(string(data)))
+		}
+	}
+
+	messages, end := stream(`{"store_id":"01JSTORE","type":"document","relation":"viewer","user":"user:anne"}`)
+	require.Len(t, messages, 2)
+	require.JSONEq(t, `{"object":"document:1"}`, messages[0])
+	require.JSONEq(t, `{"object":"document:2"}`, messages[1])
+	require.Nil(t, end.Error)
+	require.Equal(t, []string{"3"}, end.Metadata["Openfga-Datastore-Query-Count"])
+
+	messages, end = stream(`{"store_id":"01JSTORE","type":"folder"}`)
+	require.Empty(t, messages)
+	require.Equal(t, &wireError{Code: "invalid_argument", Message: "unknown type"}, end.Error)
+}
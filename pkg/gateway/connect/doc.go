@@ -0,0 +1,3 @@
+// Package connect serves a gRPC service over the Connect protocol (https://connectrpc.com/docs/protocol) with
+// connect-go, alongside the grpc-gateway REST mapping of the HTTP server.
+package connect
@@ -0,0 +1,4 @@
+// Package grpcweb serves gRPC services over the gRPC-Web protocol
+// (https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-WEB.md), so that browsers can call them, including their
+// streaming methods.
+package grpcweb
@@ -0,0 +1,361 @@
+package grpcweb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openfga/openfga/pkg/gateway"
+	httpmiddleware "github.com/openfga/openfga/pkg/middleware/http"
+)
+
+const (
+	contentTypePrefix     = "application/grpc-web"
+	contentTypeTextPrefix = "application/grpc-web-text"
+	timeoutHeader         = "grpc-timeout"
+
+	flagCompressed = 0x01
+	flagTrailer    = 0x80
+)
+
+// ExposedHeaders are the response headers the browsers must be allowed to read with CORS, for the status of the
+// calls failing before their response.
+var ExposedHeaders = []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"}
+
+// AllowedHeaders are the request headers of the protocol the browsers must be allowed to send with CORS.
+var AllowedHeaders = []string{"X-Grpc-Web", "X-User-Agent", timeoutHeader}
+
+// Handler serves the methods of a gRPC service over the gRPC-Web protocol, in its binary and its base64 text
+// encodings. It forwards the calls to the service through a gRPC client connection, like the HTTP gateway, so that
+// they go through the same interceptors, e.g. the authentication and the validation of the requests. The messages are
+// forwarded as they are, without decoding them.
+//
+// The request headers are forwarded as gRPC metadata. The gRPC headers of the responses are returned as response
+// headers, and their status and trailers in a trailer frame at the end of their body.
+type Handler struct {
+	conn            grpc.ClientConnInterface
+	path            string
+	defaultTimeout  time.Duration
+	maxMessageBytes int
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithDefaultTimeout sets the timeout of the calls that do not set one with the grpc-timeout header.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(h *Handler) {
+		h.defaultTimeout = timeout
+	}
+}
+
+// WithMaxMessageBytes sets the maximum size of the request messages, 4MB by default like the gRPC servers. It should
+// be the maximum size of the messages the gRPC server receives.
+func WithMaxMessageBytes(size int) Option {
+	return func(h *Handler) {
+		h.maxMessageBytes = size
+	}
+}
+
+// NewHandler returns a handler serving the service serviceName, e.g. 'openfga.v1.OpenFGAService', through conn.
+func NewHandler(conn grpc.ClientConnInterface, serviceName string, opts ...Option) *Handler {
+	h := &Handler{
+		conn:            conn,
+		path:            "/" + serviceName + "/",
+		maxMessageBytes: gateway.DefaultMaxMessageBytes,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// IsGRPCWebRequest reports whether r is a gRPC-Web request.
+func IsGRPCWebRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), contentTypePrefix)
+}
+
+// Mount returns a handler serving the gRPC-Web requests of the service with h, and the other requests with next.
+func (h *Handler) Mount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, h.path) && IsGRPCWebRequest(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, h.path) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var text bool
+	switch contentType {
+	case contentTypePrefix, contentTypePrefix + "+proto":
+	case contentTypeTextPrefix, contentTypeTextPrefix + "+proto":
+		text = true
+	default:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	rw := &responseWriter{w: w, controller: http.NewResponseController(w), text: text}
+
+	body := io.Reader(r.Body)
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, r.Body)
+	}
+
+	ctx, cancel, err := h.context(r)
+	if err != nil {
+		rw.writeTrailers(nil, nil, err)
+		return
+	}
+	defer cancel()
+
+	stream, err := h.conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, r.URL.Path,
+		grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		rw.writeTrailers(nil, nil, err)
+		return
+	}
+
+	if err := h.sendRequest(stream, body); err != nil {
+		cancel()
+		rw.writeTrailers(nil, nil, err)
+		return
+	}
+
+	headerWritten := false
+	for {
+		var msg []byte
+		if err = stream.RecvMsg(&msg); err != nil {
+			break
+		}
+		if !headerWritten {
+			header, _ := stream.Header()
+			writeMetadata(w.Header(), header)
+			w.WriteHeader(http.StatusOK)
+			headerWritten = true
+		}
+		if err := rw.writeFrame(0, msg); err != nil {
+			// the client is gone
+			return
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	var header metadata.MD
+	if !headerWritten {
+		header, _ = stream.Header()
+	}
+	rw.writeTrailers(header, stream.Trailer(), err)
+}
+
+// sendRequest sends the messages of the request body to stream, and closes it for sending.
+func (h *Handler) sendRequest(stream grpc.ClientStream, body io.Reader) error {
+	for {
+		var prefix [5]byte
+		if _, err := io.ReadFull(body, prefix[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return status.Errorf(codes.InvalidArgument, "failed to read the request: %v", err)
+		}
+		if prefix[0]&flagCompressed != 0 {
+			return status.Error(codes.Unimplemented, "compressed requests are not supported")
+		}
+		size := binary.BigEndian.Uint32(prefix[1:])
+		if int64(size) > int64(h.maxMessageBytes) {
+			return status.Errorf(codes.ResourceExhausted, "the request is larger than %d bytes", h.maxMessageBytes)
+		}
+
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(body, msg); err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed to read the request: %v", err)
+		}
+		if err := stream.SendMsg(&msg); err != nil {
+			// the error of the call is returned by RecvMsg
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+	return stream.CloseSend()
+}
+
+// context returns the context of the gRPC call of r, with its timeout and its headers as outgoing metadata.
+func (h *Handler) context(r *http.Request) (context.Context, context.CancelFunc, error) {
+	timeout := h.defaultTimeout
+	if value := r.Header.Get(timeoutHeader); value != "" {
+		var err error
+		if timeout, err = parseTimeout(value); err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "invalid %s header %q", timeoutHeader, value)
+		}
+	}
+
+	ctx := metadata.NewOutgoingContext(r.Context(), gateway.RequestMetadata(r, AllowedHeaders...))
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		return ctx, cancel, nil
+	}
+	return ctx, func() {}, nil
+}
+
+// parseTimeout parses a gRPC timeout, e.g. '100m' for 100 milliseconds.
+func parseTimeout(value string) (time.Duration, error) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, fmt.Errorf("invalid timeout %q", value)
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid timeout unit %q", value)
+	}
+	amount, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid timeout %q", value)
+	}
+	return time.Duration(amount) * unit, nil
+}
+
+// writeMetadata writes the gRPC metadata md as headers.
+func writeMetadata(header http.Header, md metadata.MD) {
+	for key, values := range md {
+		if key == "content-type" || key == httpmiddleware.XHttpCode || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+			header.Add(key, value)
+		}
+	}
+}
+
+type responseWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	text       bool
+}
+
+// writeFrame writes a frame of the response, i.e. its flags, its size and its data, encoded in base64 for the text
+// encoding.
+func (rw *responseWriter) writeFrame(flags byte, data []byte) error {
+	frame := make([]byte, 5+len(data))
+	frame[0] = flags
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	copy(frame[5:], data)
+
+	if rw.text {
+		frame = []byte(base64.StdEncoding.EncodeToString(frame))
+	}
+	if _, err := rw.w.Write(frame); err != nil {
+		return err
+	}
+	_ = rw.controller.Flush()
+	return nil
+}
+
+// writeTrailers writes the status of the call and its trailers in the trailer frame of the response. header is
+// written if the response has not started.
+func (rw *responseWriter) writeTrailers(header, trailer metadata.MD, err error) {
+	st := status.Convert(err)
+
+	var sb strings.Builder
+	sb.WriteString("grpc-status: " + strconv.Itoa(int(st.Code())) + "\r\n")
+	if st.Message() != "" {
+		sb.WriteString("grpc-message: " + encodeMessage(st.Message()) + "\r\n")
+	}
+	if len(st.Proto().GetDetails()) > 0 {
+		if details, err := proto.Marshal(st.Proto()); err == nil {
+			sb.WriteString("grpc-status-details-bin: " + base64.RawStdEncoding.EncodeToString(details) + "\r\n")
+		}
+	}
+	trailers := http.Header{}
+	writeMetadata(trailers, trailer)
+	for key, values := range trailers {
+		for _, value := range values {
+			sb.WriteString(strings.ToLower(key) + ": " + value + "\r\n")
+		}
+	}
+
+	if len(header) > 0 {
+		writeMetadata(rw.w.Header(), header)
+	}
+	_ = rw.writeFrame(flagTrailer, []byte(sb.String()))
+}
+
+// encodeMessage percent-encodes the status message of a call, as in the grpc-message trailer.
+func encodeMessage(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&sb, "%%%02X", c)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// rawCodec forwards the messages as they are, encoded in protobuf.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	msg, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return *msg, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
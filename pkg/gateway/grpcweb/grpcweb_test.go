@@ -0,0 +1,264 @@
+package grpcweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+type testServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+}
+
+func (s *testServer) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("authorization")) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+	if req.GetStoreId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing store id")
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("openfga-authorization-model-id", "01JMODEL"))
+	_ = grpc.SetTrailer(ctx, metadata.Pairs("openfga-datastore-query-count", "2"))
+	return &openfgav1.CheckResponse{Allowed: req.GetTupleKey().GetUser() == "user:anne"}, nil
+}
+
+func (s *testServer) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest, srv openfgav1.OpenFGAService_StreamedListObjectsServer) error {
+	if req.GetType() != "document" {
+		return status.Error(codes.InvalidArgument, "unknown type")
+	}
+	for _, object := range []string{"document:1", "document:2"} {
+		if err := srv.Send(&openfgav1.StreamedListObjectsResponse{Object: object}); err != nil {
+			return err
+		}
+	}
+	srv.SetTrailer(metadata.Pairs("openfga-datastore-query-count", "3"))
+	return nil
+}
+
+func newTestHandler(t *testing.T, opts ...Option) http.Handler {
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	openfgav1.RegisterOpenFGAServiceServer(srv, &testServer{})
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return NewHandler(conn, openfgav1.OpenFGAService_ServiceDesc.ServiceName, opts...).
+		Mount(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}))
+}
+
+func frame(flags byte, data []byte) []byte {
+	var prefix [5]byte
+	prefix[0] = flags
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(data)))
+	return append(prefix[:], data...)
+}
+
+type response struct {
+	header   http.Header
+	messages [][]byte
+	trailers map[string]string
+}
+
+func call(t *testing.T, handler http.Handler, method, contentType string, req proto.Message, headers map[string]string) response {
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	body := frame(0, data)
+	text := strings.HasPrefix(contentType, contentTypeTextPrefix)
+	if text {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/"+method, bytes.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Set("X-Grpc-Web", "1")
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var content io.Reader = rec.Body
+	if text {
+		// the frames are encoded separately, each with its own padding
+		var decoded []byte
+		for _, chunk := range strings.SplitAfter(rec.Body.String(), "=") {
+			if chunk == "" || strings.Trim(chunk, "=") == "" {
+				continue
+			}
+			for len(chunk)%4 != 0 {
+				chunk += "="
+			}
+			b, err := base64.StdEncoding.DecodeString(chunk)
+			require.NoError(t, err)
+			decoded = append(decoded, b...)
+		}
+		content = bytes.NewReader(decoded)
+	}
+
+	res := response{header: rec.Header()}
+	for {
+		var prefix [5]byte
+		_, err := io.ReadFull(content, prefix[:])
+		require.NoError(t, err)
+		data := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+		_, err = io.ReadFull(content, data)
+		require.NoError(t, err)
+
+		if prefix[0]&flagTrailer == 0 {
+			res.messages = append(res.messages, data)
+			continue
+		}
+
+		res.trailers = map[string]string{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\r\n") {
+			key, value, ok := strings.Cut(line, ": ")
+			require.True(t, ok)
+			res.trailers[key] = value
+		}
+		_, err = content.Read(prefix[:])
+		require.ErrorIs(t, err, io.EOF)
+		return res
+	}
+}
+
+func TestUnary(t *testing.T) {
+	handler := newTestHandler(t)
+
+	for _, contentType := range []string{"application/grpc-web+proto", "application/grpc-web-text"} {
+		t.Run(contentType, func(t *testing.T) {
+			res := call(t, handler, "Check", contentType, &openfgav1.CheckRequest{
+				StoreId:  "01JSTORE",
+				TupleKey: &openfgav1.CheckRequestTupleKey{Object: "document:1", Relation: "viewer", User: "user:anne"},
+			}, map[string]string{"Authorization": "Bearer key"})
+
+			require.Equal(t, contentType, res.header.Get("Content-Type"))
+			require.Equal(t, "01JMODEL", res.header.Get("Openfga-Authorization-Model-Id"))
+			require.Len(t, res.messages, 1)
+
+			var checkResponse openfgav1.CheckResponse
+			require.NoError(t, proto.Unmarshal(res.messages[0], &checkResponse))
+			require.True(t, checkResponse.GetAllowed())
+			require.Equal(t, "0", res.trailers["grpc-status"])
+			require.Equal(t, "2", res.trailers["openfga-datastore-query-count"])
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		res := call(t, handler, "Check", "application/grpc-web", &openfgav1.CheckRequest{}, nil)
+		require.Empty(t, res.messages)
+		require.Equal(t, "16", res.trailers["grpc-status"])
+		require.Equal(t, "missing credentials", res.trailers["grpc-message"])
+
+		res = call(t, handler, "Check", "application/grpc-web", &openfgav1.CheckRequest{},
+			map[string]string{"Authorization": "Bearer key"})
+		require.Equal(t, "3", res.trailers["grpc-status"])
+		require.Equal(t, "missing store id", res.trailers["grpc-message"])
+
+		res = call(t, handler, "ReadChanges", "application/grpc-web", &openfgav1.ReadChangesRequest{}, nil)
+		require.Equal(t, "12", res.trailers["grpc-status"])
+
+		res = call(t, handler, "Check", "application/grpc-web", &openfgav1.CheckRequest{},
+			map[string]string{"Grpc-Timeout": "soon"})
+		require.Equal(t, "3", res.trailers["grpc-status"])
+	})
+
+	t.Run("routing", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/openfga.v1.OpenFGAService/Check", nil)
+		r.Header.Set("Content-Type", "application/grpc-web")
+		handler.ServeHTTP(rec, r)
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+		rec = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/Check", nil)
+		r.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(rec, r)
+		require.Equal(t, http.StatusTeapot, rec.Code)
+	})
+}
+
+func TestServerStreaming(t *testing.T) {
+	handler := newTestHandler(t)
+
+	res := call(t, handler, "StreamedListObjects", "application/grpc-web", &openfgav1.StreamedListObjectsRequest{
+		StoreId: "01JSTORE", Type: "document", Relation: "viewer", User: "user:anne",
+	}, nil)
+	require.Len(t, res.messages, 2)
+	for i, object := range []string{"document:1", "document:2"} {
+		var listResponse openfgav1.StreamedListObjectsResponse
+		require.NoError(t, proto.Unmarshal(res.messages[i], &listResponse))
+		require.Equal(t, object, listResponse.GetObject())
+	}
+	require.Equal(t, "0", res.trailers["grpc-status"])
+	require.Equal(t, "3", res.trailers["openfga-datastore-query-count"])
+
+	res = call(t, handler, "StreamedListObjects", "application/grpc-web", &openfgav1.StreamedListObjectsRequest{
+		StoreId: "01JSTORE", Type: "folder",
+	}, nil)
+	require.Empty(t, res.messages)
+	require.Equal(t, "3", res.trailers["grpc-status"])
+	require.Equal(t, "unknown type", res.trailers["grpc-message"])
+}
+
+func TestMaxMessageBytes(t *testing.T) {
+	handler := newTestHandler(t, WithMaxMessageBytes(64))
+
+	res := call(t, handler, "Check", "application/grpc-web", &openfgav1.CheckRequest{
+		StoreId:  "01JSTORE",
+		TupleKey: &openfgav1.CheckRequestTupleKey{Object: "document:1", Relation: "viewer", User: "user:" + strings.Repeat("a", 64)},
+	}, map[string]string{"Authorization": "Bearer key"})
+	require.Empty(t, res.messages)
+	require.Equal(t, "8", res.trailers["grpc-status"])
+}
+
+func TestParseTimeout(t *testing.T) {
+	timeout, err := parseTimeout("250m")
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, timeout)
+
+	timeout, err = parseTimeout("3S")
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, timeout)
+
+	for _, value := range []string{"", "m", "10x", "-1S", "1234567890S"} {
+		_, err := parseTimeout(value)
+		require.Error(t, err, value)
+	}
+}
@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// UserAgentMetadataKey is the metadata the user agent of the HTTP requests is forwarded in, as the gRPC client
+	// sets its own user agent.
+	UserAgentMetadataKey = "grpcgateway-user-agent"
+
+	// DefaultMaxMessageBytes is the default maximum size of a request message of the HTTP handlers forwarding the
+	// requests to the gRPC server, the default of the gRPC servers.
+	DefaultMaxMessageBytes = 4 * 1024 * 1024
+)
+
+// reservedHeaders are the request headers not forwarded to the gRPC service, as they describe the HTTP request.
+var reservedHeaders = map[string]struct{}{
+	"accept-encoding":   {},
+	"connection":        {},
+	"content-encoding":  {},
+	"content-length":    {},
+	"content-type":      {},
+	"host":              {},
+	"keep-alive":        {},
+	"proxy-connection":  {},
+	"te":                {},
+	"trailer":           {},
+	"transfer-encoding": {},
+	"upgrade":           {},
+	"user-agent":        {},
+	"x-user-agent":      {},
+}
+
+// RequestMetadata returns the headers of r to forward as gRPC metadata, except those describing the HTTP request and
+// the headers of the protocol protocolHeaders, e.g. 'Connect-Timeout-Ms'. The binary headers, suffixed with '-bin',
+// are decoded from base64.
+//
+// The user agent is forwarded in the UserAgentMetadataKey metadata, from the X-User-Agent header if set, e.g. by the
+// gRPC-Web clients of the browsers, and the address of the client in the x-forwarded-for metadata.
+func RequestMetadata(r *http.Request, protocolHeaders ...string) metadata.MD {
+	protocol := make(map[string]struct{}, len(protocolHeaders))
+	for _, header := range protocolHeaders {
+		protocol[strings.ToLower(header)] = struct{}{}
+	}
+
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		key = strings.ToLower(key)
+		if _, ok := reservedHeaders[key]; ok || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		if _, ok := protocol[key]; ok {
+			continue
+		}
+		if !strings.HasSuffix(key, "-bin") {
+			md.Append(key, values...)
+			continue
+		}
+		for _, value := range values {
+			if decoded, err := decodeBinaryHeader(value); err == nil {
+				md.Append(key, string(decoded))
+			}
+		}
+	}
+
+	userAgent := r.Header.Get("X-User-Agent")
+	if userAgent == "" {
+		userAgent = r.Header.Get("User-Agent")
+	}
+	if userAgent != "" {
+		md.Set(UserAgentMetadataKey, userAgent)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		md.Append("x-forwarded-for", host)
+	}
+	return md
+}
+
+// decodeBinaryHeader decodes the value of a binary header, with or without padding.
+func decodeBinaryHeader(value string) ([]byte, error) {
+	if len(value)%4 == 0 {
+		return base64.StdEncoding.DecodeString(value)
+	}
+	return base64.RawStdEncoding.DecodeString(value)
+}
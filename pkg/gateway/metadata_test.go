@@ -0,0 +1,33 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestMetadata(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/openfga.v1.OpenFGAService/Check", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("Authorization", "Bearer key")
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Connect-Timeout-Ms", "100")
+	r.Header.Set("Grpc-Timeout", "100m")
+	r.Header.Set("Trace-Bin", "AQI")
+	r.Header.Set("User-Agent", "browser")
+
+	require.Equal(t, metadata.MD{
+		"authorization":      {"Bearer key"},
+		"trace-bin":          {"\x01\x02"},
+		UserAgentMetadataKey: {"browser"},
+		"x-forwarded-for":    {"10.0.0.1"},
+	}, RequestMetadata(r, "Connect-Timeout-Ms"))
+
+	r.Header.Set("X-User-Agent", "grpc-web-javascript/0.1")
+	require.Equal(t, []string{"grpc-web-javascript/0.1"}, RequestMetadata(r).Get(UserAgentMetadataKey))
+	require.Equal(t, []string{"100"}, RequestMetadata(r).Get("connect-timeout-ms"))
+	require.Empty(t, RequestMetadata(r).Get("x-user-agent"))
+}
@@ -17,11 +17,29 @@ type Transport interface {
 	SetHeader(ctx context.Context, key, value string)
 }
 
+// TrailerTransport is implemented by the transports that can also set response trailers. The trailers are only set
+// with the transports implementing it, see [SetTrailer].
+type TrailerTransport interface {
+	// SetTrailer sets a response trailer with a key and a value.
+	// Unlike SetHeader, it can be called after the messages of a streaming response have been sent.
+	SetTrailer(ctx context.Context, key, value string)
+}
+
+// SetTrailer sets a response trailer with a key and a value if t is a [TrailerTransport], and does nothing otherwise.
+func SetTrailer(ctx context.Context, t Transport, key, value string) {
+	if trailers, ok := t.(TrailerTransport); ok {
+		trailers.SetTrailer(ctx, key, value)
+	}
+}
+
 // NoopTransport defines a no-op transport.
 type NoopTransport struct {
 }
 
-var _ Transport = (*NoopTransport)(nil)
+var (
+	_ Transport        = (*NoopTransport)(nil)
+	_ TrailerTransport = (*NoopTransport)(nil)
+)
 
 func NewNoopTransport() *NoopTransport {
 	return &NoopTransport{}
@@ -31,12 +49,19 @@ func (n *NoopTransport) SetHeader(_ context.Context, key, value string) {
 
 }
 
+func (n *NoopTransport) SetTrailer(_ context.Context, key, value string) {
+
+}
+
 // RPCTransport defines a transport for gRPC.
 type RPCTransport struct {
 	logger logger.Logger
 }
 
-var _ Transport = (*RPCTransport)(nil)
+var (
+	_ Transport        = (*RPCTransport)(nil)
+	_ TrailerTransport = (*RPCTransport)(nil)
+)
 
 // NewRPCTransport returns a transport for gRPC.
 func NewRPCTransport(l logger.Logger) *RPCTransport {
@@ -54,3 +79,15 @@ func (g *RPCTransport) SetHeader(ctx context.Context, key, value string) {
 		)
 	}
 }
+
+// SetTrailer tries to set a trailer. If an error occurred, it logs an error.
+func (g *RPCTransport) SetTrailer(ctx context.Context, key, value string) {
+	if err := grpc.SetTrailer(ctx, metadata.Pairs(key, value)); err != nil {
+		g.logger.ErrorWithContext(
+			ctx,
+			"failed to set grpc trailer",
+			zap.Error(err),
+			zap.String("trailer", key),
+		)
+	}
+}
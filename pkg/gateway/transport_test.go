@@ -19,4 +19,28 @@ func TestRPCTransport(t *testing.T) {
 	log := logs.All()[0]
 
 	require.Contains(t, log.Message, "failed to set grpc header")
+
+	transport.SetTrailer(context.Background(), "test", "test")
+	log = logs.All()[1]
+
+	require.Contains(t, log.Message, "failed to set grpc trailer")
+}
+
+type headerTransport struct {
+	headers map[string]string
+}
+
+func (h *headerTransport) SetHeader(_ context.Context, key, value string) {
+	h.headers[key] = value
+}
+
+func TestSetTrailer(t *testing.T) {
+	observerLogger, logs := observer.New(zap.ErrorLevel)
+	SetTrailer(context.Background(), NewRPCTransport(&logger.ZapLogger{Logger: zap.New(observerLogger)}), "test", "test")
+	require.Contains(t, logs.All()[0].Message, "failed to set grpc trailer")
+
+	// the transports without trailers are left untouched
+	transport := &headerTransport{headers: map[string]string{}}
+	SetTrailer(context.Background(), transport, "test", "test")
+	require.Empty(t, transport.headers)
 }
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+const (
+	traceIDKey              = "trace_id"
+	spanIDKey               = "span_id"
+	requestIDKey            = "request_id"
+	storeIDKey              = "store_id"
+	authorizationModelIDKey = "authorization_model_id"
+	subjectKey              = "subject"
+)
+
+// contextTagKeys are the request tags set by the middlewares and the server that are logged with the context.
+var contextTagKeys = []string{requestIDKey, storeIDKey, authorizationModelIDKey}
+
+// withContextFields returns the fields, preceded by the fields of the request of ctx that they do not already have:
+// the ids of its trace and span, the ids of its request, store and authorization model, and its authenticated
+// subject.
+func withContextFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	if ctx == nil {
+		return fields
+	}
+
+	ctxFields := make([]zap.Field, 0, 6+len(fields))
+	add := func(key, value string) {
+		if value == "" {
+			return
+		}
+		for _, field := range fields {
+			if field.Key == key {
+				return
+			}
+		}
+		ctxFields = append(ctxFields, zap.String(key, value))
+	}
+
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		add(traceIDKey, spanCtx.TraceID().String())
+		add(spanIDKey, spanCtx.SpanID().String())
+	}
+
+	tags := grpc_ctxtags.Extract(ctx).Values()
+	for _, key := range contextTagKeys {
+		if value, ok := tags[key].(string); ok {
+			add(key, value)
+		}
+	}
+
+	if claims, ok := authclaims.AuthClaimsFromContext(ctx); ok && claims != nil {
+		add(subjectKey, claims.Subject)
+	}
+
+	if len(ctxFields) == 0 {
+		return fields
+	}
+	return append(ctxFields, fields...)
+}
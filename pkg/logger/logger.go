@@ -10,6 +10,8 @@ import (
 	"github.com/openfga/openfga/internal/build"
 )
 
+// Logger is the logger of the server. It is implemented with zap by [ZapLogger], the default, and with slog by
+// [SlogLogger].
 type Logger interface {
 	// These are ops that call directly to the actual zap implementation
 	Debug(string, ...zap.Field)
@@ -20,7 +22,8 @@ type Logger interface {
 	Fatal(string, ...zap.Field)
 	With(...zap.Field) Logger
 
-	// These are the equivalent logger function but with context provided
+	// These are the equivalent logger function but with context provided. The ids of the trace, span, request,
+	// store and authorization model of the context, and its authenticated subject, are added to the fields.
 	DebugWithContext(context.Context, string, ...zap.Field)
 	InfoWithContext(context.Context, string, ...zap.Field)
 	WarnWithContext(context.Context, string, ...zap.Field)
@@ -76,27 +79,27 @@ func (l *ZapLogger) Fatal(msg string, fields ...zap.Field) {
 }
 
 func (l *ZapLogger) DebugWithContext(ctx context.Context, msg string, fields ...zap.Field) {
-	l.Logger.Debug(msg, fields...)
+	l.Logger.Debug(msg, withContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) InfoWithContext(ctx context.Context, msg string, fields ...zap.Field) {
-	l.Logger.Info(msg, fields...)
+	l.Logger.Info(msg, withContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) WarnWithContext(ctx context.Context, msg string, fields ...zap.Field) {
-	l.Logger.Warn(msg, fields...)
+	l.Logger.Warn(msg, withContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) ErrorWithContext(ctx context.Context, msg string, fields ...zap.Field) {
-	l.Logger.Error(msg, fields...)
+	l.Logger.Error(msg, withContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) PanicWithContext(ctx context.Context, msg string, fields ...zap.Field) {
-	l.Logger.Panic(msg, fields...)
+	l.Logger.Panic(msg, withContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) FatalWithContext(ctx context.Context, msg string, fields ...zap.Field) {
-	l.Logger.Fatal(msg, fields...)
+	l.Logger.Fatal(msg, withContextFields(ctx, fields)...)
 }
 
 // OptionsLogger Implements options for logger.
@@ -105,6 +108,15 @@ type OptionsLogger struct {
 	level           string
 	timestampFormat string
 	outputPaths     []string
+
+	samplingPerSecond  int
+	samplingThereafter int
+
+	rotation *Rotation
+
+	securityFormat      string
+	securityOutputPaths []string
+	unsampled           bool
 }
 
 type OptionLogger func(ol *OptionsLogger)
@@ -145,6 +157,18 @@ func WithOutputPaths(paths ...string) OptionLogger {
 	}
 }
 
+// WithSampling samples the messages of each level, or of each [SamplingKey], so that a repeated message does not flood
+// the logs: in each second, the first perSecond entries are written, and then every thereafter-th entry, or none if
+// thereafter is 0. The entries written after some were dropped report their number in a 'sampled_out' field.
+//
+// Without it, the messages are sampled by zap's default production sampling.
+func WithSampling(perSecond, thereafter int) OptionLogger {
+	return func(ol *OptionsLogger) {
+		ol.samplingPerSecond = perSecond
+		ol.samplingThereafter = thereafter
+	}
+}
+
 func NewLogger(options ...OptionLogger) (*ZapLogger, error) {
 	logOptions := &OptionsLogger{
 		level:           "info",
@@ -169,6 +193,12 @@ func NewLogger(options ...OptionLogger) (*ZapLogger, error) {
 	cfg := zap.NewProductionConfig()
 	cfg.Level = level
 	cfg.OutputPaths = logOptions.outputPaths
+	if logOptions.rotation != nil {
+		cfg.OutputPaths, err = rotationOutputPaths(logOptions.outputPaths, *logOptions.rotation)
+		if err != nil {
+			return nil, err
+		}
+	}
 	cfg.EncoderConfig.TimeKey = "timestamp"
 	cfg.EncoderConfig.CallerKey = "" // remove the "caller" field
 	cfg.DisableStacktrace = true
@@ -185,7 +215,39 @@ func NewLogger(options ...OptionLogger) (*ZapLogger, error) {
 		}
 	}
 
-	log, err := cfg.Build()
+	var buildOptions []zap.Option
+	if logOptions.unsampled {
+		cfg.Sampling = nil
+	} else if logOptions.samplingPerSecond > 0 {
+		cfg.Sampling = nil
+		buildOptions = append(buildOptions, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newSamplingCore(core, logOptions.samplingPerSecond, logOptions.samplingThereafter)
+		}))
+	}
+
+	if len(logOptions.securityOutputPaths) > 0 {
+		securityOptions := []OptionLogger{
+			WithFormat(logOptions.securityFormat),
+			WithLevel("info"),
+			WithTimestampFormat(logOptions.timestampFormat),
+			WithOutputPaths(logOptions.securityOutputPaths...),
+			func(ol *OptionsLogger) {
+				ol.unsampled = true // the security events must not be dropped
+			},
+		}
+		if logOptions.rotation != nil {
+			securityOptions = append(securityOptions, WithRotation(*logOptions.rotation))
+		}
+		securityLogger, err := NewLogger(securityOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the security log sink: %w", err)
+		}
+		buildOptions = append(buildOptions, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &securityCore{Core: core, security: securityLogger.Core()}
+		}))
+	}
+
+	log, err := cfg.Build(buildOptions...)
 	if err != nil {
 		return nil, err
 	}
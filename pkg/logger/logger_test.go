@@ -4,10 +4,14 @@ import (
 	"context"
 	"testing"
 
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/openfga/openfga/pkg/authclaims"
 )
 
 func TestWithoutContext(t *testing.T) {
@@ -132,3 +136,31 @@ func TestWithFields(t *testing.T) {
 	parentMessage := logs.All()[1]
 	require.Empty(t, parentMessage.ContextMap())
 }
+
+func TestWithContextFields(t *testing.T) {
+	observerLogger, logs := observer.New(zap.DebugLevel)
+	logger := ZapLogger{zap.New(observerLogger)}
+
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01},
+		SpanID:  trace.SpanID{0x02},
+	}))
+	ctx = grpc_ctxtags.SetInContext(ctx, grpc_ctxtags.NewTags().
+		Set("request_id", "req").
+		Set("store_id", "01STORE").
+		Set("authorization_model_id", "01MODEL"))
+	ctx = authclaims.ContextWithAuthClaims(ctx, &authclaims.AuthClaims{Subject: "client"})
+
+	logger.InfoWithContext(ctx, "ABC", zap.String("store_id", "01OTHER"), zap.Int("count", 1))
+
+	require.Equal(t, 1, logs.Len())
+	require.Equal(t, map[string]interface{}{
+		"trace_id":               trace.TraceID{0x01}.String(),
+		"span_id":                trace.SpanID{0x02}.String(),
+		"request_id":             "req",
+		"store_id":               "01OTHER",
+		"authorization_model_id": "01MODEL",
+		"subject":                "client",
+		"count":                  int64(1),
+	}, logs.All()[0].ContextMap())
+}
@@ -0,0 +1,380 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	rotationSinkScheme     = "openfga-rotate"
+	rotationBackupTimeFmt  = "2006-01-02T15-04-05.000"
+	rotationCompressSuffix = ".gz"
+	megabyte               = 1024 * 1024
+)
+
+// Rotation configures the rotation of the log files written by a logger, see [WithRotation].
+type Rotation struct {
+	// MaxSizeMB is the size in megabytes a log file is rotated at. Defaults to 100.
+	MaxSizeMB int
+	// MaxAge is the age after which a rotated log file is removed, or 0 to keep the files regardless of their age.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated log files kept, or 0 to keep them all (subject to MaxAge).
+	MaxBackups int
+	// Compress compresses the rotated log files with gzip.
+	Compress bool
+}
+
+// WithRotation rotates the log files of the output paths, i.e. the paths without a scheme or with the "file" scheme,
+// once they reach rotation.MaxSizeMB. A rotated file is renamed with the time of its rotation, e.g.
+// 'openfga-2024-01-02T15-04-05.000.log', followed by a sequence number if the file was rotated several times in the
+// same millisecond, e.g. 'openfga-2024-01-02T15-04-05.000-1.log', and compressed and removed according to rotation.
+//
+// The other output paths, e.g. "stdout", are written as usual.
+func WithRotation(rotation Rotation) OptionLogger {
+	return func(ol *OptionsLogger) {
+		ol.rotation = &rotation
+	}
+}
+
+var registerRotationSinkOnce sync.Once
+
+// rotationOutputPaths returns the output paths with the file paths replaced by the URLs of rotating sinks.
+func rotationOutputPaths(paths []string, rotation Rotation) ([]string, error) {
+	var err error
+	registerRotationSinkOnce.Do(func() {
+		err = zap.RegisterSink(rotationSinkScheme, newRotatingSink)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register the log rotation: %w", err)
+	}
+
+	query := url.Values{}
+	query.Set("maxSize", strconv.Itoa(rotation.MaxSizeMB))
+	query.Set("maxAge", rotation.MaxAge.String())
+	query.Set("maxBackups", strconv.Itoa(rotation.MaxBackups))
+	query.Set("compress", strconv.FormatBool(rotation.Compress))
+
+	res := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path == "stdout" || path == "stderr" {
+			res = append(res, path)
+			continue
+		}
+
+		u, err := url.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %q as a URL: %w", path, err)
+		}
+		switch u.Scheme {
+		case "":
+		case "file":
+			path = u.Path
+		default:
+			res = append(res, path)
+			continue
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, (&url.URL{Scheme: rotationSinkScheme, Path: filepath.ToSlash(absPath), RawQuery: query.Encode()}).String())
+	}
+	return res, nil
+}
+
+func newRotatingSink(u *url.URL) (zap.Sink, error) {
+	query := u.Query()
+
+	maxSize, err := strconv.Atoi(query.Get("maxSize"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log rotation size: %w", err)
+	}
+	maxAge, err := time.ParseDuration(query.Get("maxAge"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log rotation age: %w", err)
+	}
+	maxBackups, err := strconv.Atoi(query.Get("maxBackups"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log rotation backups: %w", err)
+	}
+	compress, err := strconv.ParseBool(query.Get("compress"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log rotation compression: %w", err)
+	}
+
+	return newRotatingFile(filepath.FromSlash(u.Path), Rotation{
+		MaxSizeMB:  maxSize,
+		MaxAge:     maxAge,
+		MaxBackups: maxBackups,
+		Compress:   compress,
+	})
+}
+
+// rotatingFile is a log file rotated once it reaches its maximum size. The rotated files are compressed and removed
+// in the background.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	now        func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// millMu serializes the compressions and removals of the rotated files.
+	millMu sync.Mutex
+	millWg sync.WaitGroup
+}
+
+var _ zap.Sink = (*rotatingFile)(nil)
+
+func newRotatingFile(path string, rotation Rotation) (*rotatingFile, error) {
+	if rotation.MaxSizeMB <= 0 {
+		rotation.MaxSizeMB = 100
+	}
+
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    int64(rotation.MaxSizeMB) * megabyte,
+		maxAge:     rotation.MaxAge,
+		maxBackups: rotation.MaxBackups,
+		compress:   rotation.Compress,
+		now:        time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create the log directory: %w", err)
+	}
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open the log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open the log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write implements [io.Writer].
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate renames the log file with the current time and opens a new one. It must be called with mu held.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	if err := os.Rename(r.path, r.backupPath(r.now())); err != nil {
+		return fmt.Errorf("failed to rotate the log file: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.millWg.Add(1)
+	go func() {
+		defer r.millWg.Done()
+		r.mill()
+	}()
+	return nil
+}
+
+// backupPath returns the path to rename the log file rotated at t to: the path suffixed with t or, if a file was
+// already rotated at t, with t and the next sequence number.
+func (r *rotatingFile) backupPath(t time.Time) string {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(r.path, ext) + "-" + t.UTC().Format(rotationBackupTimeFmt)
+	path := prefix + ext
+	for seq := 1; backupExists(path); seq++ {
+		path = prefix + "-" + strconv.Itoa(seq) + ext
+	}
+	return path
+}
+
+// backupExists reports whether the rotated log file path exists, compressed or not.
+func backupExists(path string) bool {
+	for _, p := range []string{path, path + rotationCompressSuffix} {
+		if _, err := os.Lstat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+type rotatedFile struct {
+	path      string
+	rotatedAt time.Time
+	seq       int
+}
+
+// backups returns the rotated log files, the most recent first.
+func (r *rotatingFile) backups() ([]rotatedFile, error) {
+	dir := filepath.Dir(r.path)
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []rotatedFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		timestamp := strings.TrimPrefix(strings.TrimSuffix(name, rotationCompressSuffix), prefix)
+		if !strings.HasSuffix(timestamp, ext) {
+			continue
+		}
+		timestamp = strings.TrimSuffix(timestamp, ext)
+		if len(timestamp) < len(rotationBackupTimeFmt) {
+			continue
+		}
+		rotatedAt, err := time.Parse(rotationBackupTimeFmt, timestamp[:len(rotationBackupTimeFmt)])
+		if err != nil {
+			continue
+		}
+		var seq int
+		if suffix := timestamp[len(rotationBackupTimeFmt):]; suffix != "" {
+			if seq, err = strconv.Atoi(strings.TrimPrefix(suffix, "-")); err != nil || seq <= 0 || !strings.HasPrefix(suffix, "-") {
+				continue
+			}
+		}
+		backups = append(backups, rotatedFile{path: filepath.Join(dir, name), rotatedAt: rotatedAt, seq: seq})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].rotatedAt.Equal(backups[j].rotatedAt) {
+			return backups[i].seq > backups[j].seq
+		}
+		return backups[i].rotatedAt.After(backups[j].rotatedAt)
+	})
+	return backups, nil
+}
+
+// mill removes the rotated log files beyond maxBackups or older than maxAge, and compresses the others. Errors are
+// ignored, as there is nowhere to log them, and the files are retried on the next rotation.
+func (r *rotatingFile) mill() {
+	r.millMu.Lock()
+	defer r.millMu.Unlock()
+
+	backups, err := r.backups()
+	if err != nil {
+		return
+	}
+
+	cutoff := r.now().Add(-r.maxAge)
+	for i, backup := range backups {
+		if (r.maxBackups > 0 && i >= r.maxBackups) || (r.maxAge > 0 && backup.rotatedAt.Before(cutoff)) {
+			_ = os.Remove(backup.path)
+			continue
+		}
+		if r.compress && !strings.HasSuffix(backup.path, rotationCompressSuffix) {
+			_ = compressFile(backup.path)
+		}
+	}
+}
+
+// compressFile compresses path to path.gz and removes it.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := path + rotationCompressSuffix + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path+rotationCompressSuffix); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Sync implements [zapcore.WriteSyncer].
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+// Close closes the log file, after waiting for the rotated files to be compressed and removed.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.millWg.Wait()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
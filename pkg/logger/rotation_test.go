@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	newFile := func(t *testing.T, rotation Rotation) (*rotatingFile, string) {
+		path := filepath.Join(t.TempDir(), "openfga.log")
+		r, err := newRotatingFile(path, rotation)
+		require.NoError(t, err)
+		r.maxSize = 10
+		t.Cleanup(func() {
+			_ = r.Close()
+		})
+		return r, path
+	}
+
+	names := func(t *testing.T, dir string) []string {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		var res []string
+		for _, entry := range entries {
+			res = append(res, entry.Name())
+		}
+		return res
+	}
+
+	t.Run("rotates_at_the_max_size", func(t *testing.T) {
+		r, path := newFile(t, Rotation{})
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		r.now = func() time.Time { return now }
+
+		_, err := r.Write([]byte("123456\n"))
+		require.NoError(t, err)
+		_, err = r.Write([]byte("789\n"))
+		require.NoError(t, err)
+		r.millWg.Wait()
+
+		require.ElementsMatch(t, []string{"openfga.log", "openfga-2024-01-02T15-04-05.000.log"}, names(t, filepath.Dir(path)))
+
+		rotated, err := os.ReadFile(filepath.Join(filepath.Dir(path), "openfga-2024-01-02T15-04-05.000.log"))
+		require.NoError(t, err)
+		require.Equal(t, "123456\n", string(rotated))
+
+		current, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "789\n", string(current))
+	})
+
+	t.Run("numbers_the_files_rotated_in_the_same_millisecond", func(t *testing.T) {
+		r, path := newFile(t, Rotation{MaxBackups: 2, Compress: true})
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		r.now = func() time.Time { return now }
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err := r.Write([]byte(line))
+			require.NoError(t, err)
+			r.millWg.Wait()
+		}
+
+		// the most recent backups are kept
+		require.ElementsMatch(t, []string{
+			"openfga.log",
+			"openfga-2024-01-02T15-04-05.000-1.log.gz",
+			"openfga-2024-01-02T15-04-05.000-2.log.gz",
+		}, names(t, filepath.Dir(path)))
+
+		f, err := os.Open(filepath.Join(filepath.Dir(path), "openfga-2024-01-02T15-04-05.000-2.log.gz"))
+		require.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		rotated, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		require.Equal(t, "third\n", string(rotated))
+	})
+
+	t.Run("removes_the_rotated_files_beyond_max_backups_and_max_age", func(t *testing.T) {
+		r, path := newFile(t, Rotation{MaxBackups: 2, MaxAge: 3 * time.Hour})
+		now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		r.now = func() time.Time { return now }
+
+		for i := 0; i < 4; i++ {
+			_, err := r.Write([]byte("123456\n"))
+			require.NoError(t, err)
+			r.millWg.Wait()
+			now = now.Add(time.Hour)
+		}
+		require.ElementsMatch(t, []string{
+			"openfga.log",
+			"openfga-2024-01-02T02-00-00.000.log",
+			"openfga-2024-01-02T03-00-00.000.log",
+		}, names(t, filepath.Dir(path)))
+
+		now = now.Add(3 * time.Hour)
+		_, err := r.Write([]byte("123456\n"))
+		require.NoError(t, err)
+		r.millWg.Wait()
+
+		require.ElementsMatch(t, []string{
+			"openfga.log",
+			"openfga-2024-01-02T07-00-00.000.log",
+		}, names(t, filepath.Dir(path)))
+	})
+
+	t.Run("compresses_the_rotated_files", func(t *testing.T) {
+		r, path := newFile(t, Rotation{Compress: true})
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		r.now = func() time.Time { return now }
+
+		_, err := r.Write([]byte("123456\n"))
+		require.NoError(t, err)
+		_, err = r.Write([]byte("789\n"))
+		require.NoError(t, err)
+		r.millWg.Wait()
+
+		require.ElementsMatch(t, []string{"openfga.log", "openfga-2024-01-02T15-04-05.000.log.gz"}, names(t, filepath.Dir(path)))
+
+		f, err := os.Open(filepath.Join(filepath.Dir(path), "openfga-2024-01-02T15-04-05.000.log.gz"))
+		require.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		rotated, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		require.Equal(t, "123456\n", string(rotated))
+	})
+}
+
+func TestNewLoggerWithRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "openfga.log")
+
+	logger, err := NewLogger(
+		WithFormat("json"),
+		WithOutputPaths(path),
+		WithRotation(Rotation{MaxSizeMB: 1}),
+	)
+	require.NoError(t, err)
+
+	logger.Info("hello")
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(content), `"msg":"hello"`))
+}
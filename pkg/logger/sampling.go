@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	samplingKeyField  = "log_key"
+	sampledOutField   = "sampled_out"
+	samplingTick      = time.Second
+	samplingMaxKeys   = 4096
+	samplingKeyPrefix = "|"
+)
+
+// SamplingKey returns a field that groups the messages of a logger under key for the sampling enabled by
+// [WithSampling], rather than by their message, e.g. messages that include a variable id. As the sampling is decided
+// before the fields of an entry are encoded, the key must be added to the logger with With, e.g.
+// logger.With(SamplingKey("store-not-ready")).Warn(...).
+func SamplingKey(key string) zap.Field {
+	return zap.String(samplingKeyField, key)
+}
+
+// samplingCore samples the entries of a message, or of a [SamplingKey], and level: in each second, it writes the first
+// perSecond entries and then every thereafter-th entry, or none if thereafter is 0. The next entry written after some
+// were dropped reports their number in its 'sampled_out' field. Panic and fatal entries are never dropped.
+//
+// The entries are sampled by Check, so that the dropped ones are never written.
+type samplingCore struct {
+	zapcore.Core
+	sampler *messageSampler
+	// key is the last SamplingKey added with With, if any.
+	key string
+}
+
+var _ zapcore.Core = (*samplingCore)(nil)
+
+func newSamplingCore(core zapcore.Core, perSecond, thereafter int) *samplingCore {
+	return &samplingCore{
+		Core: core,
+		sampler: &messageSampler{
+			perSecond:  perSecond,
+			thereafter: thereafter,
+			counts:     map[string]*messageCount{},
+		},
+	}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	key := c.key
+	for _, field := range fields {
+		if field.Key == samplingKeyField && field.Type == zapcore.StringType {
+			key = samplingKeyPrefix + field.String
+		}
+	}
+	return &samplingCore{Core: c.Core.With(fields), sampler: c.sampler, key: key}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if ent.Level >= zapcore.DPanicLevel {
+		return c.Core.Check(ent, ce)
+	}
+
+	key := c.key
+	if key == "" {
+		key = ent.Message
+	}
+	write, sampledOut := c.sampler.sample(ent.Level.String()+samplingKeyPrefix+key, ent.Time)
+	if !write {
+		return ce
+	}
+	if sampledOut > 0 {
+		return c.Core.With([]zapcore.Field{zap.Int(sampledOutField, sampledOut)}).Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+type messageSampler struct {
+	perSecond  int
+	thereafter int
+
+	mu     sync.Mutex
+	counts map[string]*messageCount
+}
+
+type messageCount struct {
+	resetAt    time.Time
+	count      int
+	sampledOut int
+}
+
+// sample reports whether to write the entry of key at t, and how many entries of key were dropped since the last
+// one written.
+func (s *messageSampler) sample(key string, t time.Time) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mc, ok := s.counts[key]
+	if !ok {
+		if len(s.counts) >= samplingMaxKeys {
+			s.evict(t)
+		}
+		mc = &messageCount{}
+		s.counts[key] = mc
+	}
+	if !t.Before(mc.resetAt) {
+		mc.count = 0
+		mc.resetAt = t.Add(samplingTick)
+	}
+	mc.count++
+
+	if mc.count <= s.perSecond || (s.thereafter > 0 && (mc.count-s.perSecond)%s.thereafter == 0) {
+		sampledOut := mc.sampledOut
+		mc.sampledOut = 0
+		return true, sampledOut
+	}
+	mc.sampledOut++
+	return false, 0
+}
+
+// evict forgets the keys whose second has elapsed, or all the keys if none has, to bound the memory of the sampler.
+func (s *messageSampler) evict(t time.Time) {
+	for key, mc := range s.counts {
+		if !t.Before(mc.resetAt) {
+			delete(s.counts, key)
+		}
+	}
+	if len(s.counts) >= samplingMaxKeys {
+		clear(s.counts)
+	}
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSamplingCore(t *testing.T) {
+	newLogger := func() (*ZapLogger, *observer.ObservedLogs) {
+		observerCore, logs := observer.New(zap.DebugLevel)
+		return &ZapLogger{zap.New(newSamplingCore(observerCore, 2, 3))}, logs
+	}
+
+	t.Run("samples_each_message", func(t *testing.T) {
+		logger, logs := newLogger()
+
+		for i := 0; i < 8; i++ {
+			logger.Warn("datastore is not ready", zap.Int("i", i))
+			logger.Info("other")
+		}
+
+		var written []interface{}
+		for _, entry := range logs.FilterMessage("datastore is not ready").All() {
+			written = append(written, entry.ContextMap()["i"])
+		}
+		// the first two, and then every third
+		require.Equal(t, []interface{}{int64(0), int64(1), int64(4), int64(7)}, written)
+		require.Equal(t, 4, logs.FilterMessage("other").Len())
+
+		sampledOut := logs.FilterMessage("datastore is not ready").All()[2].ContextMap()[sampledOutField]
+		require.Equal(t, int64(2), sampledOut)
+	})
+
+	t.Run("samples_by_key", func(t *testing.T) {
+		logger, logs := newLogger()
+
+		keyed := logger.With(zap.String("component", "datastore"), SamplingKey("store-not-ready"))
+		for i := 0; i < 4; i++ {
+			keyed.Warn(fmt.Sprintf("store %d is not ready", i))
+		}
+		require.Equal(t, 2, logs.Len())
+
+		// the key is kept by the loggers derived from it, the fifth entry being the third after the first two
+		keyed.With(zap.Int("attempt", 1)).Warn("store 4 is not ready")
+		require.Equal(t, 3, logs.Len())
+		require.Equal(t, int64(2), logs.All()[2].ContextMap()[sampledOutField])
+
+		// and replaced by a new key
+		keyed.With(SamplingKey("other")).Warn("store 5 is not ready")
+		require.Equal(t, 4, logs.Len())
+	})
+
+	t.Run("drops_the_entries_before_writing_them", func(t *testing.T) {
+		observerCore, logs := observer.New(zap.DebugLevel)
+		core := newSamplingCore(observerCore, 1, 0)
+
+		entry := zapcore.Entry{Level: zapcore.WarnLevel, Message: "message", Time: time.Now()}
+		require.NotNil(t, core.Check(entry, nil))
+		require.Nil(t, core.Check(entry, nil))
+		require.Equal(t, 0, logs.Len())
+	})
+
+	t.Run("samples_each_level_separately", func(t *testing.T) {
+		logger, logs := newLogger()
+
+		for i := 0; i < 2; i++ {
+			logger.Info("message")
+			logger.Warn("message")
+			logger.Error("message")
+		}
+
+		require.Equal(t, 6, logs.Len())
+	})
+
+	t.Run("resets_every_second", func(t *testing.T) {
+		sampler := &messageSampler{perSecond: 1, thereafter: 0, counts: map[string]*messageCount{}}
+		now := time.Now()
+
+		write, _ := sampler.sample("key", now)
+		require.True(t, write)
+		write, _ = sampler.sample("key", now.Add(500*time.Millisecond))
+		require.False(t, write)
+
+		write, sampledOut := sampler.sample("key", now.Add(time.Second))
+		require.True(t, write)
+		require.Equal(t, 1, sampledOut)
+	})
+
+	t.Run("never_drops_panics", func(t *testing.T) {
+		observerCore, logs := observer.New(zap.DebugLevel)
+		core := newSamplingCore(observerCore, 1, 0)
+
+		for i := 0; i < 3; i++ {
+			entry := zapcore.Entry{Level: zapcore.PanicLevel, Message: "panic", Time: time.Now()}
+			core.Check(entry, nil).Write()
+		}
+
+		require.Equal(t, 3, logs.Len())
+	})
+}
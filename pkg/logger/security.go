@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const securityEventKey = "security_event"
+
+// The security events logged by the server.
+const (
+	SecurityEventAuthenticationFailed = "authentication_failed"
+	SecurityEventAuthorizationDenied  = "authorization_denied"
+	SecurityEventStoreDeleted         = "store_deleted"
+	SecurityEventAdminAction          = "admin_action"
+)
+
+// SecurityEvent returns a field that marks a message as the security event, e.g.
+// [SecurityEventAuthorizationDenied]. The security events are written to the security sink of the logger, if it has
+// one, rather than with the other messages. See [WithSecuritySink].
+func SecurityEvent(event string) zap.Field {
+	return zap.String(securityEventKey, event)
+}
+
+// WithSecuritySink writes the security events, i.e. the messages with a [SecurityEvent] field, to their own output
+// paths in their own format ('text' or 'json'), e.g. to keep them for longer than the other messages. They are
+// written from the info level, whatever the level of the logger, and are never sampled.
+func WithSecuritySink(format string, outputPaths ...string) OptionLogger {
+	return func(ol *OptionsLogger) {
+		ol.securityFormat = format
+		ol.securityOutputPaths = outputPaths
+	}
+}
+
+// securityCore writes the security events to its security core, and the other entries to its core.
+type securityCore struct {
+	zapcore.Core
+	security zapcore.Core
+
+	// isSecurity is set once the core was given a SecurityEvent field by With.
+	isSecurity bool
+}
+
+var _ zapcore.Core = (*securityCore)(nil)
+
+func (c *securityCore) Enabled(level zapcore.Level) bool {
+	return c.Core.Enabled(level) || c.security.Enabled(level)
+}
+
+func (c *securityCore) With(fields []zapcore.Field) zapcore.Core {
+	return &securityCore{
+		Core:       c.Core.With(fields),
+		security:   c.security.With(fields),
+		isSecurity: c.isSecurity || hasSecurityEvent(fields),
+	}
+}
+
+func (c *securityCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *securityCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.isSecurity || hasSecurityEvent(fields) {
+		if !c.security.Enabled(ent.Level) {
+			return nil
+		}
+		return c.security.Write(ent, fields)
+	}
+	if !c.Core.Enabled(ent.Level) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+func (c *securityCore) Sync() error {
+	err := c.Core.Sync()
+	if securityErr := c.security.Sync(); err == nil {
+		err = securityErr
+	}
+	return err
+}
+
+func hasSecurityEvent(fields []zapcore.Field) bool {
+	for _, field := range fields {
+		if field.Key == securityEventKey {
+			return true
+		}
+	}
+	return false
+}
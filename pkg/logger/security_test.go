@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSecurityCore(t *testing.T) {
+	appCore, appLogs := observer.New(zap.WarnLevel)
+	securityObserver, securityLogs := observer.New(zap.InfoLevel)
+	logger := &ZapLogger{zap.New(&securityCore{Core: appCore, security: securityObserver})}
+
+	logger.Info("store deleted", SecurityEvent(SecurityEventStoreDeleted), zap.String("store_id", "abc"))
+	logger.With(SecurityEvent(SecurityEventAuthenticationFailed)).Info("authentication failed")
+	logger.Debug("authorization failed", SecurityEvent(SecurityEventAuthorizationDenied))
+	logger.Info("dropped below the level of the application logs")
+	logger.Warn("written to the application logs")
+
+	require.Equal(t, 1, appLogs.Len())
+	require.Equal(t, "written to the application logs", appLogs.All()[0].Message)
+
+	require.Equal(t, 2, securityLogs.Len())
+	require.Equal(t, "store deleted", securityLogs.All()[0].Message)
+	require.Equal(t, SecurityEventStoreDeleted, securityLogs.All()[0].ContextMap()[securityEventKey])
+	require.Equal(t, "abc", securityLogs.All()[0].ContextMap()["store_id"])
+	require.Equal(t, "authentication failed", securityLogs.All()[1].Message)
+	require.Equal(t, SecurityEventAuthenticationFailed, securityLogs.All()[1].ContextMap()[securityEventKey])
+}
+
+func TestNewLoggerWithSecuritySink(t *testing.T) {
+	dir := t.TempDir()
+	appPath := filepath.Join(dir, "openfga.log")
+	securityPath := filepath.Join(dir, "openfga-security.log")
+
+	logger, err := NewLogger(
+		WithFormat("text"),
+		WithLevel("error"),
+		WithOutputPaths(appPath),
+		WithSampling(1, 0),
+		WithSecuritySink("json", securityPath),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		logger.Info("authorization failed", SecurityEvent(SecurityEventAuthorizationDenied))
+	}
+	logger.Error("boom")
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(securityPath)
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(string(content), `"security_event":"authorization_denied"`))
+	require.NotContains(t, string(content), "boom")
+
+	content, err = os.ReadFile(appPath)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "boom")
+	require.NotContains(t, string(content), "authorization failed")
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The slog levels of the panic and fatal messages, above [slog.LevelError].
+const (
+	LevelPanic = slog.Level(12)
+	LevelFatal = slog.Level(16)
+)
+
+// SlogLogger is an implementation of Logger that writes to a [slog.Logger], so that the server can be embedded in
+// applications logging with slog, or with any logging library providing a [slog.Handler], e.g. zerolog.
+//
+// The zap fields are converted to slog attributes. Panic messages are logged at [LevelPanic] before panicking, and
+// fatal messages at [LevelFatal] before exiting.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+var _ Logger = (*SlogLogger)(nil)
+
+// NewSlogLogger returns a Logger writing to logger.
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	return &SlogLogger{logger: logger}
+}
+
+// With creates a child logger and adds structured context to it.
+func (l *SlogLogger) With(fields ...zap.Field) Logger {
+	attrs := slogAttrs(fields)
+	args := make([]any, 0, len(attrs))
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	return &SlogLogger{logger: l.logger.With(args...)}
+}
+
+func (l *SlogLogger) Debug(msg string, fields ...zap.Field) {
+	l.log(context.Background(), slog.LevelDebug, msg, fields)
+}
+
+func (l *SlogLogger) Info(msg string, fields ...zap.Field) {
+	l.log(context.Background(), slog.LevelInfo, msg, fields)
+}
+
+func (l *SlogLogger) Warn(msg string, fields ...zap.Field) {
+	l.log(context.Background(), slog.LevelWarn, msg, fields)
+}
+
+func (l *SlogLogger) Error(msg string, fields ...zap.Field) {
+	l.log(context.Background(), slog.LevelError, msg, fields)
+}
+
+func (l *SlogLogger) Panic(msg string, fields ...zap.Field) {
+	l.log(context.Background(), LevelPanic, msg, fields)
+	panic(msg)
+}
+
+func (l *SlogLogger) Fatal(msg string, fields ...zap.Field) {
+	l.log(context.Background(), LevelFatal, msg, fields)
+	os.Exit(1)
+}
+
+func (l *SlogLogger) DebugWithContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l.log(ctx, slog.LevelDebug, msg, withContextFields(ctx, fields))
+}
+
+func (l *SlogLogger) InfoWithContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l.log(ctx, slog.LevelInfo, msg, withContextFields(ctx, fields))
+}
+
+func (l *SlogLogger) WarnWithContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l.log(ctx, slog.LevelWarn, msg, withContextFields(ctx, fields))
+}
+
+func (l *SlogLogger) ErrorWithContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l.log(ctx, slog.LevelError, msg, withContextFields(ctx, fields))
+}
+
+func (l *SlogLogger) PanicWithContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l.log(ctx, LevelPanic, msg, withContextFields(ctx, fields))
+	panic(msg)
+}
+
+func (l *SlogLogger) FatalWithContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l.log(ctx, LevelFatal, msg, withContextFields(ctx, fields))
+	os.Exit(1)
+}
+
+func (l *SlogLogger) log(ctx context.Context, level slog.Level, msg string, fields []zap.Field) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	l.logger.LogAttrs(ctx, level, msg, slogAttrs(fields)...)
+}
+
+// slogAttrs converts zap fields to slog attributes, using the values zap would encode for them, e.g. the message of
+// an error.
+func slogAttrs(fields []zap.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		enc := zapcore.NewMapObjectEncoder()
+		field.AddTo(enc)
+
+		// a field is usually encoded as one key, but can be encoded as several, e.g. an error and its details
+		keys := make([]string, 0, len(enc.Fields))
+		for key := range enc.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attrs = append(attrs, slog.Any(key, enc.Fields[key]))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestSlogLogger(t *testing.T) {
+	newLogger := func() (*SlogLogger, *bytes.Buffer) {
+		var buf bytes.Buffer
+		handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return attr
+			},
+		})
+		return NewSlogLogger(slog.New(handler)), &buf
+	}
+
+	decode := func(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+
+	t.Run("converts_the_fields", func(t *testing.T) {
+		logger, buf := newLogger()
+
+		logger.With(zap.String("store_id", "01STORE")).Warn("ABC",
+			zap.Int("count", 3),
+			zap.Bool("ok", true),
+			zap.Duration("duration", time.Second),
+			zap.Error(errors.New("boom")),
+		)
+
+		require.Equal(t, map[string]interface{}{
+			"level":    "WARN",
+			"msg":      "ABC",
+			"store_id": "01STORE",
+			"count":    float64(3),
+			"ok":       true,
+			"duration": float64(time.Second),
+			"error":    "boom",
+		}, decode(t, buf))
+	})
+
+	t.Run("adds_the_context_fields", func(t *testing.T) {
+		logger, buf := newLogger()
+
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{0x01},
+			SpanID:  trace.SpanID{0x02},
+		}))
+		logger.InfoWithContext(ctx, "ABC")
+
+		record := decode(t, buf)
+		require.Equal(t, trace.TraceID{0x01}.String(), record["trace_id"])
+		require.Equal(t, trace.SpanID{0x02}.String(), record["span_id"])
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		logger, buf := newLogger()
+
+		require.PanicsWithValue(t, "ABC", func() {
+			logger.Panic("ABC")
+		})
+		require.Equal(t, LevelPanic.String(), decode(t, buf)["level"])
+	})
+
+	t.Run("skips_the_disabled_levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+		logger.Debug("ABC")
+		require.Zero(t, buf.Len())
+	})
+}
@@ -0,0 +1,187 @@
+package accesslog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+// The fields of the access log lines.
+const (
+	MethodField               = "method"
+	RequestIDField            = "request_id"
+	StoreIDField              = "store_id"
+	AuthorizationModelIDField = "authorization_model_id"
+	PeerField                 = "peer"
+	LatencyField              = "latency_ms"
+	CodeField                 = "code"
+	DatastoreQueryCountField  = "datastore_query_count"
+	DispatchCountField        = "dispatch_count"
+
+	accessLogMessage = "access"
+
+	// forwardedForHeader is set by the HTTP gateway to the address of its client, appended to the addresses the
+	// client sent.
+	forwardedForHeader = "x-forwarded-for"
+)
+
+// Fields are all the fields of the access log lines, in the order they are written.
+var Fields = []string{
+	MethodField,
+	RequestIDField,
+	StoreIDField,
+	AuthorizationModelIDField,
+	PeerField,
+	LatencyField,
+	CodeField,
+	DatastoreQueryCountField,
+	DispatchCountField,
+}
+
+// tagFields are the fields read from the request tags set by the other middlewares and the server.
+var tagFields = []string{
+	RequestIDField,
+	StoreIDField,
+	AuthorizationModelIDField,
+	DatastoreQueryCountField,
+	DispatchCountField,
+}
+
+// AccessLogger logs one line per request with the chosen [Fields], except for the skipped methods. The requests of
+// the HTTP gateway are logged as the gRPC requests they are served by, with the address of the HTTP client as peer.
+type AccessLogger struct {
+	logger         logger.Logger
+	fields         []string
+	skippedMethods map[string]struct{}
+}
+
+// NewAccessLogger returns an [AccessLogger] writing the fields, or all the [Fields] if empty, to logger. The skipped
+// methods are either method names, e.g. 'Check', or full method names, e.g. 'grpc.health.v1.Health/Check'.
+func NewAccessLogger(logger logger.Logger, fields []string, skippedMethods []string) (*AccessLogger, error) {
+	if len(fields) == 0 {
+		fields = Fields
+	}
+	for _, field := range fields {
+		if !slices.Contains(Fields, field) {
+			return nil, fmt.Errorf("unknown access log field '%s', expected one of %v", field, Fields)
+		}
+	}
+
+	skipped := make(map[string]struct{}, len(skippedMethods))
+	for _, method := range skippedMethods {
+		skipped[method] = struct{}{}
+	}
+
+	return &AccessLogger{
+		logger:         logger,
+		fields:         fields,
+		skippedMethods: skipped,
+	}, nil
+}
+
+// NewUnaryInterceptor returns a grpc.UnaryServerInterceptor that logs the unary requests.
+func (a *AccessLogger) NewUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return interceptors.UnaryServerInterceptor(a.reportable())
+}
+
+// NewStreamingInterceptor returns a grpc.StreamServerInterceptor that logs the streaming requests.
+func (a *AccessLogger) NewStreamingInterceptor() grpc.StreamServerInterceptor {
+	return interceptors.StreamServerInterceptor(a.reportable())
+}
+
+func (a *AccessLogger) skipped(c interceptors.CallMeta) bool {
+	if _, ok := a.skippedMethods[c.Method]; ok {
+		return true
+	}
+	_, ok := a.skippedMethods[c.Service+"/"+c.Method]
+	return ok
+}
+
+func (a *AccessLogger) reportable() interceptors.CommonReportableFunc {
+	return func(ctx context.Context, c interceptors.CallMeta) (interceptors.Reporter, context.Context) {
+		if a.skipped(c) {
+			return interceptors.NoopReporter{}, ctx
+		}
+		return &reporter{ctx: ctx, accessLogger: a, method: c.Method}, ctx
+	}
+}
+
+type reporter struct {
+	interceptors.NoopReporter
+
+	ctx          context.Context
+	accessLogger *AccessLogger
+	method       string
+}
+
+// PostCall logs the request once it is served.
+func (r *reporter) PostCall(err error, duration time.Duration) {
+	tags := grpc_ctxtags.Extract(r.ctx).Values()
+
+	fields := make([]zap.Field, 0, len(r.accessLogger.fields))
+	for _, field := range r.accessLogger.fields {
+		switch field {
+		case MethodField:
+			fields = append(fields, zap.String(MethodField, r.method))
+		case PeerField:
+			if addr := peerFromContext(r.ctx); addr != "" {
+				fields = append(fields, zap.String(PeerField, addr))
+			}
+		case LatencyField:
+			fields = append(fields, zap.Int64(LatencyField, duration.Milliseconds()))
+		case CodeField:
+			fields = append(fields, zap.String(CodeField, status.Code(err).String()))
+		default:
+			if slices.Contains(tagFields, field) {
+				if value, ok := tags[field]; ok {
+					fields = append(fields, zap.Any(field, value))
+				}
+			}
+		}
+	}
+
+	r.accessLogger.logger.Info(accessLogMessage, fields...)
+}
+
+// peerFromContext returns the address of the client of the request: the transport peer, or the client of the HTTP
+// gateway for the requests it forwards. The forwarded address is only honoured when the transport peer is the
+// gateway, i.e. a loopback address, and only its last hop, appended by the gateway, is used, as the previous ones are
+// sent by the client and cannot be trusted.
+func peerFromContext(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if !isLoopback(p.Addr) {
+		return p.Addr.String()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if forwardedFor := md.Get(forwardedForHeader); len(forwardedFor) > 0 {
+			hops := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+			if client := strings.TrimSpace(hops[len(hops)-1]); client != "" {
+				return client
+			}
+		}
+	}
+	return p.Addr.String()
+}
+
+// isLoopback reports whether addr is a loopback TCP address.
+func isLoopback(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
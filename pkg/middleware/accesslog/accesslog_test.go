@@ -0,0 +1,126 @@
+package accesslog
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+func TestAccessLogger(t *testing.T) {
+	newContext := func() context.Context {
+		ctx := grpc_ctxtags.SetInContext(context.Background(), grpc_ctxtags.NewTags())
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}})
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		grpc_ctxtags.Extract(ctx).
+			Set(StoreIDField, "01STORE").
+			Set(AuthorizationModelIDField, "01MODEL").
+			Set(DatastoreQueryCountField, float64(3)).
+			Set(DispatchCountField, float64(2))
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+
+	newInterceptor := func(t *testing.T, fields, skippedMethods []string) (grpc.UnaryServerInterceptor, *observer.ObservedLogs) {
+		core, logs := observer.New(zap.DebugLevel)
+		accessLogger, err := NewAccessLogger(&logger.ZapLogger{Logger: zap.New(core)}, fields, skippedMethods)
+		require.NoError(t, err)
+		return accessLogger.NewUnaryInterceptor(), logs
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}
+
+	t.Run("logs_all_the_fields", func(t *testing.T) {
+		interceptor, logs := newInterceptor(t, nil, nil)
+
+		_, err := interceptor(newContext(), nil, info, handler)
+		require.Error(t, err)
+
+		require.Equal(t, 1, logs.Len())
+		entry := logs.All()[0]
+		require.Equal(t, accessLogMessage, entry.Message)
+
+		fields := entry.ContextMap()
+		require.Contains(t, fields, LatencyField)
+		delete(fields, LatencyField)
+		require.Equal(t, map[string]interface{}{
+			MethodField:               "Check",
+			StoreIDField:              "01STORE",
+			AuthorizationModelIDField: "01MODEL",
+			PeerField:                 "10.0.0.1:1234",
+			CodeField:                 "NotFound",
+			DatastoreQueryCountField:  float64(3),
+			DispatchCountField:        float64(2),
+		}, fields)
+	})
+
+	t.Run("logs_the_chosen_fields", func(t *testing.T) {
+		interceptor, logs := newInterceptor(t, []string{MethodField, CodeField}, nil)
+
+		_, err := interceptor(newContext(), nil, info, handler)
+		require.Error(t, err)
+
+		require.Equal(t, 1, logs.Len())
+		require.Equal(t, map[string]interface{}{
+			MethodField: "Check",
+			CodeField:   "NotFound",
+		}, logs.All()[0].ContextMap())
+	})
+
+	t.Run("logs_the_client_of_the_http_gateway_as_peer", func(t *testing.T) {
+		interceptor, logs := newInterceptor(t, []string{PeerField}, nil)
+
+		// the first address is sent by the client, the last one appended by the gateway
+		ctx := peer.NewContext(newContext(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5678}})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(forwardedForHeader, "192.168.0.1, 10.0.0.2"))
+		_, err := interceptor(ctx, nil, info, handler)
+		require.Error(t, err)
+
+		require.Equal(t, map[string]interface{}{
+			PeerField: "10.0.0.2",
+		}, logs.All()[0].ContextMap())
+	})
+
+	t.Run("ignores_the_forwarded_address_of_other_peers", func(t *testing.T) {
+		interceptor, logs := newInterceptor(t, []string{PeerField}, nil)
+
+		ctx := metadata.NewIncomingContext(newContext(), metadata.Pairs(forwardedForHeader, "192.168.0.1"))
+		_, err := interceptor(ctx, nil, info, handler)
+		require.Error(t, err)
+
+		require.Equal(t, map[string]interface{}{
+			PeerField: "10.0.0.1:1234",
+		}, logs.All()[0].ContextMap())
+	})
+
+	t.Run("skips_methods", func(t *testing.T) {
+		interceptor, logs := newInterceptor(t, nil, []string{"Check", "grpc.health.v1.Health/Watch"})
+
+		_, err := interceptor(newContext(), nil, info, handler)
+		require.Error(t, err)
+		_, err = interceptor(newContext(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}, handler)
+		require.Error(t, err)
+		require.Zero(t, logs.Len())
+
+		_, err = interceptor(newContext(), nil, &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Write"}, handler)
+		require.Error(t, err)
+		require.Equal(t, 1, logs.Len())
+	})
+
+	t.Run("unknown_field", func(t *testing.T) {
+		_, err := NewAccessLogger(logger.NewNoopLogger(), []string{"raw_request"}, nil)
+		require.Error(t, err)
+	})
+}
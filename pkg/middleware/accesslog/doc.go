@@ -0,0 +1,2 @@
+// Package accesslog contains middleware to log one structured line per request.
+package accesslog
@@ -0,0 +1,2 @@
+// Package errorinfo contains middleware to attach a machine-readable reason to the errors returned to the clients.
+package errorinfo
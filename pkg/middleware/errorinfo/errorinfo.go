@@ -0,0 +1,31 @@
+package errorinfo
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+)
+
+// NewUnaryInterceptor creates a grpc.UnaryServerInterceptor which attaches a google.rpc.ErrorInfo detail with the
+// reason of the error, e.g. MODEL_NOT_FOUND, to the errors returned by the handlers and the interceptors after it. It
+// must come right after the panic middleware, so that the errors of all the other interceptors are covered.
+func NewUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, serverErrors.WithErrorInfo(err)
+		}
+		return resp, nil
+	}
+}
+
+// NewStreamingInterceptor creates a grpc.StreamServerInterceptor which attaches a google.rpc.ErrorInfo detail with
+// the reason of the error to the errors returned by the handlers and the interceptors after it. It must come right
+// after the panic middleware.
+func NewStreamingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return serverErrors.WithErrorInfo(handler(srv, stream))
+	}
+}
@@ -0,0 +1,54 @@
+package errorinfo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	interceptor := NewUnaryInterceptor()
+
+	t.Run("attaches_the_reason", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+			return nil, serverErrors.AuthorizationModelNotFound("01JQ0000000000000000000000")
+		})
+		require.Equal(t, serverErrors.ReasonModelNotFound, serverErrors.Reason(status.Convert(err)))
+		require.Len(t, status.Convert(err).Details(), 1)
+	})
+
+	t.Run("keeps_the_reason", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+			return nil, serverErrors.ConditionEvaluationError(status.Error(codes.InvalidArgument, "missing context parameters"))
+		})
+		require.Equal(t, serverErrors.ReasonConditionEvaluationFailed, serverErrors.Reason(status.Convert(err)))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ok", resp)
+	})
+}
+
+func TestStreamingInterceptor(t *testing.T) {
+	interceptor := NewStreamingInterceptor()
+
+	err := interceptor(nil, nil, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+		return serverErrors.ErrThrottledTimeout
+	})
+	require.Equal(t, serverErrors.ReasonThrottled, serverErrors.Reason(status.Convert(err)))
+
+	err = interceptor(nil, nil, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -20,6 +20,19 @@ import (
 // XHttpCode is used to set the header for the response HTTP code.
 const XHttpCode = "x-http-code"
 
+// openfgaHeaderPrefix is the prefix of the request headers forwarded as metadata by IncomingHeaderMatcher.
+const openfgaHeaderPrefix = "Openfga-"
+
+// IncomingHeaderMatcher forwards the request headers prefixed with 'Openfga-', e.g. 'Openfga-Read-Relations', as
+// metadata, so that the HTTP clients can send the request metadata of the server without the 'Grpc-Metadata-'
+// prefix. The other headers are matched by [runtime.DefaultHeaderMatcher].
+func IncomingHeaderMatcher(key string) (string, bool) {
+	if strings.HasPrefix(textproto.CanonicalMIMEHeaderKey(key), openfgaHeaderPrefix) {
+		return key, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
 // HTTPResponseModifier is a helper function designed to modify the status code in the context of HTTP responses.
 func HTTPResponseModifier(ctx context.Context, w http.ResponseWriter, p proto.Message) error {
 	md, ok := runtime.ServerMetadataFromContext(ctx)
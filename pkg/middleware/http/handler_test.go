@@ -74,3 +74,20 @@ func TestCustomHTTPErrorHandlerSpecialEncoding(t *testing.T) {
 	expectedData := "{\"code\":\"assertions_too_many_items\",\"message\":\"invalid character '<' looking for beginning of value,\"}"
 	require.Equal(t, expectedData, strings.TrimSpace(string(data)))
 }
+
+func TestIncomingHeaderMatcher(t *testing.T) {
+	key, ok := IncomingHeaderMatcher("Openfga-Read-Relations")
+	require.True(t, ok)
+	require.Equal(t, "Openfga-Read-Relations", key)
+
+	key, ok = IncomingHeaderMatcher("Grpc-Metadata-Openfga-Read-User-Type")
+	require.True(t, ok)
+	require.Equal(t, "Openfga-Read-User-Type", key)
+
+	key, ok = IncomingHeaderMatcher("Authorization")
+	require.True(t, ok)
+	require.Equal(t, "grpcgateway-Authorization", key)
+
+	_, ok = IncomingHeaderMatcher("X-Custom")
+	require.False(t, ok)
+}
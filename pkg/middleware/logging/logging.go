@@ -18,6 +18,7 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/redact"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 )
 
@@ -71,10 +72,10 @@ func (r *reporter) PostCall(err error, rpcDuration time.Duration) {
 	if err != nil {
 		var internalError serverErrors.InternalError
 		if errors.As(err, &internalError) {
-			r.fields = append(r.fields, zap.String(internalErrorKey, internalError.Unwrap().Error()))
-			r.logger.Error(err.Error(), r.fields...)
+			r.fields = append(r.fields, zap.String(internalErrorKey, redact.Text(internalError.Unwrap().Error())))
+			r.logger.Error(redact.Text(err.Error()), r.fields...)
 		} else {
-			r.fields = append(r.fields, zap.Error(err))
+			r.fields = append(r.fields, zap.Error(redact.Error(err)))
 			r.logger.Info(grpcReqCompleteKey, r.fields...)
 		}
 
@@ -94,7 +95,7 @@ func (r *reporter) PostMsgSend(msg interface{}, err error, _ time.Duration) {
 	if err != nil {
 		// This is the actual error that customers see.
 		intCode := serverErrors.ConvertToEncodedErrorCode(status.Convert(err))
-		encodedError := serverErrors.NewEncodedError(intCode, err.Error())
+		encodedError := serverErrors.NewEncodedError(intCode, redact.Text(err.Error()))
 		protomsg := encodedError.ActualError
 		if resp, err := json.Marshal(protomsg); err == nil {
 			r.fields = append(r.fields, zap.Any(rawResponseKey, json.RawMessage(resp)))
@@ -104,7 +105,7 @@ func (r *reporter) PostMsgSend(msg interface{}, err error, _ time.Duration) {
 	protomsg, ok := msg.(protoreflect.ProtoMessage)
 	if ok {
 		if resp, err := r.protomarshaler.Marshal(protomsg); err == nil {
-			r.fields = append(r.fields, zap.Any(rawResponseKey, json.RawMessage(resp)))
+			r.fields = append(r.fields, zap.Any(rawResponseKey, json.RawMessage(redact.JSON(resp))))
 		}
 	}
 }
@@ -114,7 +115,7 @@ func (r *reporter) PostMsgReceive(msg interface{}, _ error, _ time.Duration) {
 	protomsg, ok := msg.(protoreflect.ProtoMessage)
 	if ok {
 		if req, err := r.protomarshaler.Marshal(protomsg); err == nil {
-			r.fields = append(r.fields, zap.Any(rawRequestKey, json.RawMessage(req)))
+			r.fields = append(r.fields, zap.Any(rawRequestKey, json.RawMessage(redact.JSON(req))))
 		}
 	}
 }
@@ -150,10 +151,8 @@ func reportable(l logger.Logger) interceptors.CommonReportableFunc {
 			fields = append(fields, zap.String(userAgentKey, userAgent))
 		}
 
-		zapLogger := l.(*logger.ZapLogger)
-
 		return &reporter{
-			ctx:            ctxzap.ToContext(ctx, zapLogger.Logger),
+			ctx:            ctx,
 			logger:         l,
 			fields:         fields,
 			protomarshaler: protojson.MarshalOptions{EmitUnpopulated: true},
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"path"
 	"time"
 
 	grpcvalidator "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/validator"
@@ -12,17 +13,40 @@ import (
 
 // TimeoutInterceptor sets the timeout in each request.
 type TimeoutInterceptor struct {
-	timeout time.Duration
-	logger  logger.Logger
+	timeout         time.Duration
+	logger          logger.Logger
+	excludedMethods map[string]struct{}
+}
+
+type TimeoutInterceptorOption func(*TimeoutInterceptor)
+
+// WithExcludedMethods excludes API methods, e.g. 'Check', from the timeout. It is meant for methods
+// whose handler enforces a timeout of its own.
+func WithExcludedMethods(methods ...string) TimeoutInterceptorOption {
+	return func(h *TimeoutInterceptor) {
+		for _, method := range methods {
+			h.excludedMethods[method] = struct{}{}
+		}
+	}
 }
 
 // NewTimeoutInterceptor returns new TimeoutInterceptor that timeouts request if it
 // exceeds the timeout value.
-func NewTimeoutInterceptor(timeout time.Duration, logger logger.Logger) *TimeoutInterceptor {
-	return &TimeoutInterceptor{
-		timeout: timeout,
-		logger:  logger,
+func NewTimeoutInterceptor(timeout time.Duration, logger logger.Logger, opts ...TimeoutInterceptorOption) *TimeoutInterceptor {
+	h := &TimeoutInterceptor{
+		timeout:         timeout,
+		logger:          logger,
+		excludedMethods: map[string]struct{}{},
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *TimeoutInterceptor) isExcluded(fullMethod string) bool {
+	_, ok := h.excludedMethods[path.Base(fullMethod)]
+	return ok
 }
 
 // NewUnaryTimeoutInterceptor returns an interceptor that will timeout according to the configured timeout.
@@ -30,6 +54,9 @@ func NewTimeoutInterceptor(timeout time.Duration, logger logger.Logger) *Timeout
 // to return proper error code.
 func (h *TimeoutInterceptor) NewUnaryTimeoutInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info != nil && h.isExcluded(info.FullMethod) {
+			return handler(ctx, req)
+		}
 		ctx, cancel := context.WithTimeout(ctx, h.timeout)
 		defer cancel()
 		return handler(ctx, req)
@@ -43,6 +70,9 @@ func (h *TimeoutInterceptor) NewStreamTimeoutInterceptor() grpc.StreamServerInte
 	validator := grpcvalidator.StreamServerInterceptor()
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		return validator(srv, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+			if info != nil && h.isExcluded(info.FullMethod) {
+				return handler(srv, ss)
+			}
 			ctx, cancel := context.WithTimeout(stream.Context(), h.timeout)
 			defer cancel()
 
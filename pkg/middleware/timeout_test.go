@@ -73,3 +73,43 @@ func TestNewStreamTimeoutInterceptor(t *testing.T) {
 	err := interceptor(nil, mockServerGRPCStream{ctx: context.Background()}, nil, handler)
 	require.ErrorIs(t, err, context.DeadlineExceeded)
 }
+
+func TestTimeoutInterceptor_ExcludedMethods(t *testing.T) {
+	timeoutInterceptor := NewTimeoutInterceptor(5*time.Millisecond, logger.NewNoopLogger(), WithExcludedMethods("ListObjects"))
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		select {
+		case <-time.After(20 * time.Millisecond):
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	interceptor := timeoutInterceptor.NewUnaryTimeoutInterceptor()
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/ListObjects"}, handler)
+	require.NoError(t, err)
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}, handler)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTimeoutInterceptor_ExcludedMethodsStream(t *testing.T) {
+	timeoutInterceptor := NewTimeoutInterceptor(5*time.Millisecond, logger.NewNoopLogger(), WithExcludedMethods("StreamedListObjects"))
+
+	handler := func(srv any, stream grpc.ServerStream) error {
+		select {
+		case <-time.After(20 * time.Millisecond):
+			return nil
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+	interceptor := timeoutInterceptor.NewStreamTimeoutInterceptor()
+
+	err := interceptor(nil, mockServerGRPCStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/openfga.v1.OpenFGAService/StreamedListObjects"}, handler)
+	require.NoError(t, err)
+
+	err = interceptor(nil, mockServerGRPCStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/openfga.v1.OpenFGAService/StreamedRead"}, handler)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
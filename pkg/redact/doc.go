@@ -0,0 +1,3 @@
+// Package redact redacts the ids of the users and objects, and the values of the condition contexts, before they are
+// attached to spans, log fields and error messages, for the deployments with strict privacy rules.
+package redact
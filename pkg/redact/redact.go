@@ -0,0 +1,264 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// Mode is how the ids and context values are redacted.
+type Mode string
+
+const (
+	// ModeOff leaves the ids and context values as they are.
+	ModeOff Mode = "off"
+	// ModeHash replaces the ids and context values by a keyed hash, so that the spans and logs of the same user or
+	// object can still be correlated.
+	ModeHash Mode = "hash"
+	// ModeDrop replaces the ids and context values by '?'.
+	ModeDrop Mode = "drop"
+)
+
+const (
+	dropped    = "?"
+	hashLength = 16
+)
+
+// Modes are the valid redaction modes.
+var Modes = []Mode{ModeOff, ModeHash, ModeDrop}
+
+// textObjectRegex matches the objects, e.g. 'document:budget', in a text.
+var textObjectRegex = regexp.MustCompile(`\b([A-Za-z][^\s'":#@,()]*):([^\s'"#@,()]+)`)
+
+type redactor struct {
+	mode Mode
+	key  []byte
+}
+
+var current atomic.Pointer[redactor]
+
+// dropper replaces the ids by '?' whatever the configured mode, e.g. to log the shape of a filter.
+var dropper = &redactor{mode: ModeDrop}
+
+// Configure sets how the ids and context values are redacted in the process. With ModeHash, they are replaced by the
+// first 16 hexadecimal digits of their HMAC-SHA256 with hashKey, which should be kept secret so that the low entropy
+// ids cannot be recovered by hashing guesses.
+func Configure(mode Mode, hashKey string) {
+	current.Store(&redactor{mode: mode, key: []byte(hashKey)})
+}
+
+// Enabled reports whether the ids and context values are redacted.
+func Enabled() bool {
+	r := current.Load()
+	return r != nil && r.mode != ModeOff && r.mode != ""
+}
+
+func (r *redactor) value(value string) string {
+	if r.mode == ModeDrop {
+		return dropped
+	}
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:hashLength]
+}
+
+func (r *redactor) object(object string) string {
+	objectType, objectID := tuple.SplitObject(object)
+	switch {
+	case objectID == "" || objectID == tuple.Wildcard:
+		return object
+	case objectType == "":
+		return r.value(objectID)
+	}
+	return objectType + ":" + r.value(objectID)
+}
+
+func (r *redactor) user(user string) string {
+	object, relation := tuple.SplitObjectRelation(user)
+	if relation == "" {
+		return r.object(object)
+	}
+	return r.object(object) + "#" + relation
+}
+
+// Object returns the object with its id redacted, keeping its type. The wildcard is not redacted.
+func Object(object string) string {
+	if !Enabled() {
+		return object
+	}
+	return current.Load().object(object)
+}
+
+// User returns the user with its id redacted, keeping its type and the relation of a userset. The wildcard is not
+// redacted.
+func User(user string) string {
+	if !Enabled() {
+		return user
+	}
+	return current.Load().user(user)
+}
+
+// ObjectShape returns the object with its id replaced by '?', whatever the configured mode. The wildcard is not
+// redacted.
+func ObjectShape(object string) string {
+	return dropper.object(object)
+}
+
+// UserShape returns the user with its id replaced by '?', whatever the configured mode, keeping its type and the
+// relation of a userset. The wildcard is not redacted.
+func UserShape(user string) string {
+	return dropper.user(user)
+}
+
+// TupleKey returns the string of the tuple key, e.g. 'document:budget#viewer@user:anne (condition in_office)', with
+// the ids of its object and user redacted. It never includes the context of the condition.
+func TupleKey(tk tuple.TupleWithCondition) string {
+	if !Enabled() {
+		return tuple.TupleKeyWithConditionToString(tk)
+	}
+
+	s := Object(tk.GetObject()) + "#" + tk.GetRelation() + "@" + User(tk.GetUser())
+	if tk.GetCondition() != nil {
+		s += " (condition " + tk.GetCondition().GetName() + ")"
+	}
+	return s
+}
+
+// Text returns the text, e.g. an error message, with the ids of the objects and users it mentions redacted.
+func Text(text string) string {
+	if !Enabled() {
+		return text
+	}
+	r := current.Load()
+	return textObjectRegex.ReplaceAllStringFunc(text, func(object string) string {
+		if isAddress(object) {
+			return object
+		}
+		return r.object(object)
+	})
+}
+
+// isAddress reports whether an object matched in a text is rather a network address, i.e. a host with a port, e.g.
+// 'localhost:5432' or 'db.internal:3306', or the scheme of a URL, e.g. 'postgres://...'.
+func isAddress(object string) bool {
+	host, port, _ := strings.Cut(object, ":")
+	if strings.HasPrefix(port, "/") {
+		return true
+	}
+	if host != "localhost" && !strings.Contains(host, ".") {
+		return false
+	}
+	// the port may be followed by a path or by the colon of an error message
+	if end := strings.IndexAny(port, ":/"); end >= 0 {
+		port = port[:end]
+	}
+	_, err := strconv.ParseUint(port, 10, 16)
+	return err == nil
+}
+
+// Error returns an error with the message of err redacted as by [Text], or err itself if nothing is redacted.
+func Error(err error) error {
+	if err == nil || !Enabled() {
+		return err
+	}
+	return errors.New(Text(err.Error()))
+}
+
+// JSON returns the JSON encoding of a request or response with the ids of its users and objects, and the values of its
+// contexts, redacted. It returns raw as is if it is not valid JSON.
+func JSON(raw []byte) []byte {
+	if !Enabled() {
+		return raw
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return raw
+	}
+	redacted, err := json.Marshal(current.Load().json("", value))
+	if err != nil {
+		return raw
+	}
+	return redacted
+}
+
+// json redacts the value of a field named key: the user and object strings, the usersets of the Expand trees, the ids
+// of the messages with a type, e.g. the objects of ListUsers, and every value of the contexts.
+func (r *redactor) json(key string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if key == "context" {
+			for field, fieldValue := range v {
+				v[field] = r.contextValue(fieldValue)
+			}
+			return v
+		}
+		if id, ok := v["id"].(string); ok && id != tuple.Wildcard {
+			if _, ok := v["type"]; ok {
+				v["id"] = r.value(id)
+			}
+		}
+		// the name of an Expand node is its userset, e.g. 'document:budget#viewer'
+		if name, ok := v["name"].(string); ok && isExpandNode(v) {
+			v["name"] = r.user(name)
+		}
+		for field, fieldValue := range v {
+			if field != "id" && field != "name" {
+				v[field] = r.json(field, fieldValue)
+			}
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = r.json(key, item)
+		}
+		return v
+	case string:
+		switch key {
+		case "user", "users", "object", "objects", "userset", "tupleset":
+			return r.user(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isExpandNode reports whether a JSON object is a node of an Expand tree.
+func isExpandNode(v map[string]any) bool {
+	for _, field := range []string{"leaf", "union", "intersection", "difference"} {
+		if _, ok := v[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// contextValue redacts every value of a context, keeping the structure of the lists and maps.
+func (r *redactor) contextValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for field, fieldValue := range v {
+			v[field] = r.contextValue(fieldValue)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = r.contextValue(item)
+		}
+		return v
+	case nil:
+		return nil
+	default:
+		encoded, _ := json.Marshal(v)
+		return r.value(string(encoded))
+	}
+}
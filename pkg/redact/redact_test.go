@@ -0,0 +1,94 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestRedact(t *testing.T) {
+	t.Cleanup(func() {
+		Configure(ModeOff, "")
+	})
+
+	tk := tuple.NewTupleKeyWithCondition("document:budget", "viewer", "group:eng#member", "in_office", nil)
+
+	t.Run("off", func(t *testing.T) {
+		Configure(ModeOff, "")
+		require.False(t, Enabled())
+		require.Equal(t, "document:budget", Object("document:budget"))
+		require.Equal(t, "user:anne", User("user:anne"))
+		require.Equal(t, "document:budget#viewer@group:eng#member (condition in_office)", TupleKey(tk))
+		require.Equal(t, "Invalid tuple 'document:budget#viewer@user:anne'", Text("Invalid tuple 'document:budget#viewer@user:anne'"))
+		require.JSONEq(t, `{"user":"user:anne","context":{"ip":"10.0.0.1"}}`, string(JSON([]byte(`{"user":"user:anne","context":{"ip":"10.0.0.1"}}`))))
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		Configure(ModeDrop, "")
+		require.True(t, Enabled())
+		require.Equal(t, "document:?", Object("document:budget"))
+		require.Equal(t, "user:*", User("user:*"))
+		require.Equal(t, "group:?#member", User("group:eng#member"))
+		require.Equal(t, "document:?#viewer@group:?#member (condition in_office)", TupleKey(tk))
+		require.Equal(t, "rpc error: Invalid tuple 'document:?#viewer@user:?'. Reason: type 'user' not found",
+			Text("rpc error: Invalid tuple 'document:budget#viewer@user:anne'. Reason: type 'user' not found"))
+
+		redacted := JSON([]byte(`{
+			"tuple_key": {"object": "document:budget", "relation": "viewer", "user": "user:anne"},
+			"contextual_tuples": {"tuple_keys": [{"object": "folder:x", "relation": "parent", "user": "user:*"}]},
+			"context": {"ip": "10.0.0.1", "roles": ["admin"], "nested": {"level": 3}},
+			"objects": ["document:budget"],
+			"users": [{"object": {"type": "user", "id": "anne"}}, {"wildcard": {"type": "user"}}],
+			"relation": "viewer"
+		}`))
+		require.JSONEq(t, `{
+			"tuple_key": {"object": "document:?", "relation": "viewer", "user": "user:?"},
+			"contextual_tuples": {"tuple_keys": [{"object": "folder:?", "relation": "parent", "user": "user:*"}]},
+			"context": {"ip": "?", "roles": ["?"], "nested": {"level": "?"}},
+			"objects": ["document:?"],
+			"users": [{"object": {"type": "user", "id": "?"}}, {"wildcard": {"type": "user"}}],
+			"relation": "viewer"
+		}`, string(redacted))
+
+		redacted = JSON([]byte(`{"tree": {"root": {"name": "document:budget#viewer", "union": {"nodes": [
+			{"name": "document:budget#viewer", "leaf": {"users": {"users": ["user:anne", "group:eng#member"]}}},
+			{"name": "document:budget#viewer", "leaf": {"computed": {"userset": "document:budget#editor"}}},
+			{"name": "document:budget#viewer", "leaf": {"tupleToUserset": {"tupleset": "document:budget#parent", "computed": [{"userset": "folder:x#viewer"}]}}}
+		]}}}}`))
+		require.JSONEq(t, `{"tree": {"root": {"name": "document:?#viewer", "union": {"nodes": [
+			{"name": "document:?#viewer", "leaf": {"users": {"users": ["user:?", "group:?#member"]}}},
+			{"name": "document:?#viewer", "leaf": {"computed": {"userset": "document:?#editor"}}},
+			{"name": "document:?#viewer", "leaf": {"tupleToUserset": {"tupleset": "document:?#parent", "computed": [{"userset": "folder:?#viewer"}]}}}
+		]}}}}`, string(redacted))
+
+		// the names of the stores and conditions are not redacted
+		require.JSONEq(t, `{"name": "acme:prod", "condition": {"name": "in_office"}}`,
+			string(JSON([]byte(`{"name": "acme:prod", "condition": {"name": "in_office"}}`))))
+
+		// the network addresses are not redacted
+		require.Equal(t, "dial tcp localhost:5432: connection refused for 'user:?'",
+			Text("dial tcp localhost:5432: connection refused for 'user:anne'"))
+		require.Equal(t, "failed to connect to db.internal:3306 with postgres://db.internal/openfga",
+			Text("failed to connect to db.internal:3306 with postgres://db.internal/openfga"))
+
+		require.Equal(t, "not json", string(JSON([]byte("not json"))))
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		Configure(ModeHash, "secret")
+		hashed := Object("document:budget")
+		require.Regexp(t, `^document:[0-9a-f]{16}$`, hashed)
+		require.Equal(t, hashed, Object("document:budget"))
+		require.NotEqual(t, hashed, Object("document:roadmap"))
+		require.Equal(t, hashed+"#viewer", User("document:budget#viewer"))
+
+		Configure(ModeHash, "other")
+		require.NotEqual(t, hashed, Object("document:budget"))
+
+		// the shapes drop the ids whatever the mode
+		require.Equal(t, "document:?", ObjectShape("document:budget"))
+		require.Equal(t, "group:?#member", UserShape("group:eng#member"))
+	})
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// ErrCheckCacheNotFlushable is returned by FlushCheckCache when the server has no check cache, or when its cache,
+// set with WithCheckCache, cannot be flushed.
+var ErrCheckCacheNotFlushable = errors.New("the check cache cannot be flushed")
+
+// runtimeExperimentals are the experimental flags read by each request, which can thus be changed while the server
+// runs. The others are read when the server is created.
+var runtimeExperimentals = []ExperimentalFeatureFlag{
+	ExperimentalSimulation,
+}
+
+// FlushCheckCache discards the check queries, iterators and tuples cached by the server.
+func (s *Server) FlushCheckCache() error {
+	if !s.sharedDatastoreResources.FlushCheckCache() {
+		return ErrCheckCacheNotFlushable
+	}
+	s.logger.Info("check cache flushed")
+	return nil
+}
+
+// InvalidateTypesystemCache discards the authorization models of the store cached by the server, so that they are
+// read again from the datastore.
+func (s *Server) InvalidateTypesystemCache(storeID string) {
+	s.typesystemResolverInvalidate(storeID)
+	s.logger.Info("typesystem cache invalidated", zap.String("store_id", storeID))
+}
+
+// Experimentals returns the experimental flags enabled on the server.
+func (s *Server) Experimentals() []ExperimentalFeatureFlag {
+	s.experimentalsMu.RLock()
+	defer s.experimentalsMu.RUnlock()
+	return slices.Clone(s.experimentals)
+}
+
+// SetExperimental enables or disables the experimental flag on the server. Only the flags read by each request, i.e.
+// 'enable-simulation' gating SimulateCheck, can be changed while the server runs, by the Go programs embedding it.
+func (s *Server) SetExperimental(flag ExperimentalFeatureFlag, enabled bool) error {
+	if !slices.Contains(runtimeExperimentals, flag) {
+		return fmt.Errorf("the experimental flag '%s' cannot be changed while the server runs", flag)
+	}
+
+	s.experimentalsMu.Lock()
+	defer s.experimentalsMu.Unlock()
+	experimentals := slices.DeleteFunc(slices.Clone(s.experimentals), func(f ExperimentalFeatureFlag) bool {
+		return f == flag
+	})
+	if enabled {
+		experimentals = append(experimentals, flag)
+	}
+	s.experimentals = experimentals
+
+	s.logger.Info("experimental flag changed", zap.String("flag", string(flag)), zap.Bool("enabled", enabled))
+	return nil
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/oklog/ulid/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
@@ -20,6 +23,9 @@ import (
 )
 
 func (s *Server) WriteAssertions(ctx context.Context, req *openfgav1.WriteAssertionsRequest) (*openfgav1.WriteAssertionsResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.WriteAssertions)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.WriteAssertions.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 	))
@@ -64,6 +70,9 @@ func (s *Server) WriteAssertions(ctx context.Context, req *openfgav1.WriteAssert
 }
 
 func (s *Server) ReadAssertions(ctx context.Context, req *openfgav1.ReadAssertionsRequest) (*openfgav1.ReadAssertionsResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ReadAssertions)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.ReadAssertions.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 	))
@@ -91,5 +100,70 @@ func (s *Server) ReadAssertions(ctx context.Context, req *openfgav1.ReadAssertio
 	}
 
 	q := commands.NewReadAssertionsQuery(s.datastore, commands.WithReadAssertionsQueryLogger(s.logger))
-	return q.Execute(ctx, req.GetStoreId(), typesys.GetAuthorizationModelID())
+	resp, err := q.Execute(ctx, req.GetStoreId(), typesys.GetAuthorizationModelID())
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := commands.ComputeAssertionCoverage(typesys, resp.GetAssertions())
+	s.transport.SetHeader(ctx, AssertionRelationCoverageHeader, formatCoverage(len(coverage.CoveredRelations), len(coverage.UncoveredRelations)))
+	s.transport.SetHeader(ctx, AssertionTypeCoverageHeader, formatCoverage(len(coverage.CoveredTypes), len(coverage.UncoveredTypes)))
+	if len(coverage.UncoveredRelations) > 0 {
+		s.transport.SetHeader(ctx, UncoveredRelationsHeader, strings.Join(coverage.UncoveredRelations, ","))
+	}
+
+	return resp, nil
+}
+
+// formatCoverage formats the number of covered items out of all of them, e.g. '3/5'.
+func formatCoverage(covered, uncovered int) string {
+	return strconv.Itoa(covered) + "/" + strconv.Itoa(covered+uncovered)
+}
+
+// RunAssertions runs the assertions written for an authorization model of the store, or its latest model if
+// modelID is empty, through the Check resolver and returns whether each of them passed. The caller must be allowed
+// to both read the assertions and call Check. Every assertion is checked with the options, and within the timeout, of
+// the Check method. It is a library method, for the servers embedding OpenFGA: it is not exposed by the gRPC and HTTP
+// APIs.
+func (s *Server) RunAssertions(ctx context.Context, storeID, modelID string) ([]commands.AssertionResult, error) {
+	ctx, span := tracer.Start(ctx, "RunAssertions", trace.WithAttributes(
+		attribute.String("store_id", storeID),
+		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(modelID)},
+	))
+	defer span.End()
+
+	if _, err := ulid.ParseStrict(storeID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid store id")
+	}
+	if modelID != "" {
+		if _, err := ulid.ParseStrict(modelID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid authorization model id")
+		}
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.ReadAssertions.String(),
+	})
+
+	for _, method := range []apimethod.APIMethod{apimethod.ReadAssertions, apimethod.Check} {
+		if err := s.checkAuthz(ctx, storeID, method); err != nil {
+			return nil, err
+		}
+	}
+
+	typesys, err := s.resolveTypesystem(ctx, storeID, modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := commands.NewRunAssertionsCommand(s.datastore, s.checkResolver, typesys,
+		commands.WithRunAssertionsLogger(s.logger),
+		commands.WithRunAssertionsContextFunc(func(assertionCtx *structpb.Struct) *structpb.Struct {
+			return s.withTrustedContext(ctx, assertionCtx)
+		}),
+		commands.WithRunAssertionsCheckOptions(s.checkCommandOptions(ctx)...),
+		commands.WithRunAssertionsCheckTimeout(s.methodTimeouts[apimethod.Check.String()]),
+	)
+	return c.Execute(ctx, storeID)
 }
@@ -4,11 +4,14 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/oklog/ulid/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
@@ -16,10 +19,15 @@ import (
 	httpmiddleware "github.com/openfga/openfga/pkg/middleware/http"
 	"github.com/openfga/openfga/pkg/middleware/validator"
 	"github.com/openfga/openfga/pkg/server/commands"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/telemetry"
+	"github.com/openfga/openfga/pkg/typesystem"
 )
 
 func (s *Server) ReadAuthorizationModel(ctx context.Context, req *openfgav1.ReadAuthorizationModelRequest) (*openfgav1.ReadAuthorizationModelResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ReadAuthorizationModel)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.ReadAuthorizationModel.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(req.GetId())},
@@ -47,6 +55,9 @@ func (s *Server) ReadAuthorizationModel(ctx context.Context, req *openfgav1.Read
 }
 
 func (s *Server) WriteAuthorizationModel(ctx context.Context, req *openfgav1.WriteAuthorizationModelRequest) (*openfgav1.WriteAuthorizationModelResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.WriteAuthorizationModel)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.WriteAuthorizationModel.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 	))
@@ -72,17 +83,21 @@ func (s *Server) WriteAuthorizationModel(ctx context.Context, req *openfgav1.Wri
 		commands.WithWriteAuthModelLogger(s.logger),
 		commands.WithWriteAuthModelMaxSizeInBytes(s.maxAuthorizationModelSizeInBytes),
 	)
-	res, err := c.Execute(ctx, req)
+	res, warnings, err := c.ExecuteWithWarnings(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	s.setAuthorizationModelWarningsHeader(ctx, warnings)
 	s.transport.SetHeader(ctx, httpmiddleware.XHttpCode, strconv.Itoa(http.StatusCreated))
 
 	return res, nil
 }
 
 func (s *Server) ReadAuthorizationModels(ctx context.Context, req *openfgav1.ReadAuthorizationModelsRequest) (*openfgav1.ReadAuthorizationModelsResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ReadAuthorizationModels)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.ReadAuthorizationModels.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 	))
@@ -104,9 +119,216 @@ func (s *Server) ReadAuthorizationModels(ctx context.Context, req *openfgav1.Rea
 		return nil, err
 	}
 
+	pageSize, err := s.pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	if pageSize != req.GetPageSize() {
+		req = proto.Clone(req).(*openfgav1.ReadAuthorizationModelsRequest)
+		req.PageSize = pageSize
+	}
+
 	c := commands.NewReadAuthorizationModelsQuery(s.datastore,
 		commands.WithReadAuthModelsQueryLogger(s.logger),
 		commands.WithReadAuthModelsQueryEncoder(s.encoder),
 	)
 	return c.Execute(ctx, req)
 }
+
+// DeleteAuthorizationModel deletes an authorization model of a store, along with its assertions, and evicts the
+// models of the store cached by the server. The latest model of the store cannot be deleted. The API has no
+// DeleteAuthorizationModel RPC, so it is only available to the Go programs embedding the server.
+func (s *Server) DeleteAuthorizationModel(ctx context.Context, storeID, modelID string) error {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.DeleteAuthorizationModel)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, apimethod.DeleteAuthorizationModel.String(), trace.WithAttributes(
+		attribute.String("store_id", storeID),
+		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(modelID)},
+	))
+	defer span.End()
+
+	if _, err := ulid.ParseStrict(storeID); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid store id")
+	}
+	if _, err := ulid.ParseStrict(modelID); err != nil {
+		return serverErrors.AuthorizationModelNotFound(modelID)
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.DeleteAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, storeID, apimethod.DeleteAuthorizationModel)
+	if err != nil {
+		return err
+	}
+
+	c := commands.NewDeleteAuthorizationModelCommand(s.datastore, commands.WithDeleteAuthModelLogger(s.logger))
+	if err := c.Execute(ctx, storeID, modelID); err != nil {
+		return err
+	}
+
+	// the deleted model must not be resolved from the cache anymore
+	s.typesystemResolverInvalidate(storeID)
+	return nil
+}
+
+// WriteAuthorizationModelDSL writes the authorization model described by dsl, in the FGA DSL, to the store. It is a
+// library method, for the servers embedding OpenFGA: it is not exposed by the gRPC and HTTP APIs.
+func (s *Server) WriteAuthorizationModelDSL(ctx context.Context, storeID, dsl string) (*openfgav1.WriteAuthorizationModelResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.WriteAuthorizationModel)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "WriteAuthorizationModelDSL", trace.WithAttributes(
+		attribute.String("store_id", storeID),
+	))
+	defer span.End()
+
+	if _, err := ulid.ParseStrict(storeID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid store id")
+	}
+	if dsl == "" {
+		return nil, status.Error(codes.InvalidArgument, "the DSL cannot be empty")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.WriteAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, storeID, apimethod.WriteAuthorizationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	c := commands.NewWriteAuthorizationModelCommand(s.datastore,
+		commands.WithWriteAuthModelLogger(s.logger),
+		commands.WithWriteAuthModelMaxSizeInBytes(s.maxAuthorizationModelSizeInBytes),
+		commands.WithWriteAuthModelDSL(dsl),
+	)
+	res, warnings, err := c.ExecuteWithWarnings(ctx, &openfgav1.WriteAuthorizationModelRequest{StoreId: storeID})
+	if err != nil {
+		return nil, err
+	}
+
+	s.setAuthorizationModelWarningsHeader(ctx, warnings)
+
+	return res, nil
+}
+
+// setAuthorizationModelWarningsHeader sets the AuthorizationModelWarningsHeader to the warnings, if any.
+func (s *Server) setAuthorizationModelWarningsHeader(ctx context.Context, warnings []typesystem.Warning) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	messages := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		messages = append(messages, warning.String())
+	}
+	s.transport.SetHeader(ctx, AuthorizationModelWarningsHeader, strings.Join(messages, "; "))
+}
+
+// ReadAuthorizationModelDSL returns an authorization model of the store rendered in the FGA DSL. It is a library
+// method, for the servers embedding OpenFGA: it is not exposed by the gRPC and HTTP APIs.
+func (s *Server) ReadAuthorizationModelDSL(ctx context.Context, storeID, modelID string) (string, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ReadAuthorizationModel)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "ReadAuthorizationModelDSL", trace.WithAttributes(
+		attribute.String("store_id", storeID),
+		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(modelID)},
+	))
+	defer span.End()
+
+	req := &openfgav1.ReadAuthorizationModelRequest{StoreId: storeID, Id: modelID}
+	if err := req.Validate(); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.ReadAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, storeID, apimethod.ReadAuthorizationModel)
+	if err != nil {
+		return "", err
+	}
+
+	q := commands.NewReadAuthorizationModelQuery(s.datastore, commands.WithReadAuthModelQueryLogger(s.logger))
+	return q.ExecuteDSL(ctx, req)
+}
+
+// DiffAuthorizationModels returns the changes from the authorization model fromModelID of a store to its model
+// toModelID, and whether they are breaking. It is a library method, for the servers embedding OpenFGA: it is not
+// exposed by the gRPC and HTTP APIs.
+func (s *Server) DiffAuthorizationModels(ctx context.Context, storeID, fromModelID, toModelID string) (*commands.AuthorizationModelDiff, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ReadAuthorizationModel)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "DiffAuthorizationModels", trace.WithAttributes(
+		attribute.String("store_id", storeID),
+		attribute.String("from_authorization_model_id", fromModelID),
+		attribute.String("to_authorization_model_id", toModelID),
+	))
+	defer span.End()
+
+	for _, modelID := range []string{fromModelID, toModelID} {
+		req := &openfgav1.ReadAuthorizationModelRequest{StoreId: storeID, Id: modelID}
+		if err := req.Validate(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.ReadAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, storeID, apimethod.ReadAuthorizationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	q := commands.NewDiffAuthorizationModelsQuery(s.datastore, commands.WithDiffAuthModelsQueryLogger(s.logger))
+	return q.Execute(ctx, storeID, fromModelID, toModelID)
+}
+
+// EstimateQuery returns the expected cost of resolving the relation of objectType, e.g. with Check or ListObjects,
+// against an authorization model of a store, or its latest model if modelID is empty, without executing any query:
+// the depth of its dispatches, the branches fanning out per tuple, and those ListObjects reads by user.
+func (s *Server) EstimateQuery(ctx context.Context, storeID, modelID, objectType, relation string) (*commands.QueryEstimate, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ReadAuthorizationModel)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "EstimateQuery", trace.WithAttributes(
+		attribute.String("store_id", storeID),
+		attribute.String("object_type", objectType),
+		attribute.String("relation", relation),
+	))
+	defer span.End()
+
+	if _, err := ulid.ParseStrict(storeID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid store id")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.ReadAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, storeID, apimethod.ReadAuthorizationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	typesys, err := s.resolveTypesystem(ctx, storeID, modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return commands.EstimateQuery(typesys, objectType, relation)
+}
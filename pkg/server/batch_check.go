@@ -9,6 +9,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
@@ -22,6 +23,9 @@ import (
 )
 
 func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckRequest) (*openfgav1.BatchCheckResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.BatchCheck)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.BatchCheck.String(), trace.WithAttributes(
 		attribute.KeyValue{Key: "store_id", Value: attribute.StringValue(req.GetStoreId())},
 		attribute.KeyValue{Key: "batch_size", Value: attribute.IntValue(len(req.GetChecks()))},
@@ -46,6 +50,22 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 		return nil, err
 	}
 
+	checks := req.GetChecks()
+	if s.hasTrustedContext() {
+		// the items of the request are not modified, as they are still read by the interceptors
+		checks = make([]*openfgav1.BatchCheckItem, len(req.GetChecks()))
+	}
+	for i, check := range req.GetChecks() {
+		if err := s.validateRequestLimits(apimethod.BatchCheck, check.GetContext(), len(check.GetContextualTuples().GetTupleKeys())); err != nil {
+			return nil, err
+		}
+		if s.hasTrustedContext() {
+			check = proto.Clone(check).(*openfgav1.BatchCheckItem)
+			check.Context = s.withTrustedContext(ctx, check.GetContext())
+			checks[i] = check
+		}
+	}
+
 	typesys, err := s.resolveTypesystem(ctx, storeID, req.GetAuthorizationModelId())
 	if err != nil {
 		return nil, err
@@ -55,16 +75,18 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 		s.datastore,
 		s.checkResolver,
 		typesys,
-		commands.WithBatchCheckCacheOptions(s.sharedDatastoreResources, s.cacheSettings),
+		commands.WithBatchCheckCacheOptions(s.sharedDatastoreResources, s.requestCacheSettings()),
 		commands.WithBatchCheckCommandLogger(s.logger),
-		commands.WithBatchCheckMaxChecksPerBatch(s.maxChecksPerBatchCheck),
+		commands.WithBatchCheckMaxChecksPerBatch(s.TunableSettings().MaxChecksPerBatchCheck),
 		commands.WithBatchCheckMaxConcurrentChecks(s.maxConcurrentChecksPerBatch),
 		commands.WithBatchCheckDatastoreThrottler(s.checkDatastoreThrottleThreshold, s.checkDatastoreThrottleDuration),
+		commands.WithBatchCheckLimiterSaturationMonitor(s.datastoreLimiterSaturationMonitor),
+		commands.WithBatchCheckHedgeDelay(s.datastoreHedgeDelay),
 	)
 
 	result, metadata, err := cmd.Execute(ctx, &commands.BatchCheckCommandParams{
 		AuthorizationModelID: typesys.GetAuthorizationModelID(),
-		Checks:               req.GetChecks(),
+		Checks:               checks,
 		Consistency:          req.GetConsistency(),
 		StoreID:              storeID,
 	})
@@ -99,10 +121,11 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 
 	queryCount := float64(metadata.DatastoreQueryCount)
 	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, queryCount))
-	datastoreQueryCountHistogram.WithLabelValues(
+	telemetry.ObserveWithTraceExemplar(ctx, datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
-	).Observe(queryCount)
+		s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+	), queryCount)
 
 	duplicateChecks := "duplicate_checks"
 	span.SetAttributes(attribute.Int(duplicateChecks, metadata.DuplicateCheckCount))
@@ -116,6 +139,8 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 
 	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, metadata.DatastoreQueryCount)
 
+	s.setResolutionMetadataHeaders(ctx, metadata.DatastoreQueryCount, metadata.DispatchCount, metadata.CheckCacheHits)
+
 	return &openfgav1.BatchCheckResponse{Result: batchResult}, nil
 }
 
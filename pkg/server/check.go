@@ -15,15 +15,20 @@ import (
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/internal/middleware/limitoverrides"
 	"github.com/openfga/openfga/internal/utils"
 	"github.com/openfga/openfga/internal/utils/apimethod"
 	"github.com/openfga/openfga/pkg/middleware/validator"
+	"github.com/openfga/openfga/pkg/redact"
 	"github.com/openfga/openfga/pkg/server/commands"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/telemetry"
 )
 
 func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.Check)
+	defer cancel()
+
 	const methodName = "check"
 
 	startTime := time.Now()
@@ -31,9 +36,9 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 	tk := req.GetTupleKey()
 	ctx, span := tracer.Start(ctx, apimethod.Check.String(), trace.WithAttributes(
 		attribute.KeyValue{Key: "store_id", Value: attribute.StringValue(req.GetStoreId())},
-		attribute.KeyValue{Key: "object", Value: attribute.StringValue(tk.GetObject())},
+		attribute.KeyValue{Key: "object", Value: attribute.StringValue(redact.Object(tk.GetObject()))},
 		attribute.KeyValue{Key: "relation", Value: attribute.StringValue(tk.GetRelation())},
-		attribute.KeyValue{Key: "user", Value: attribute.StringValue(tk.GetUser())},
+		attribute.KeyValue{Key: "user", Value: attribute.StringValue(redact.User(tk.GetUser()))},
 		attribute.KeyValue{Key: "consistency", Value: attribute.StringValue(req.GetConsistency().String())},
 	))
 	defer span.End()
@@ -48,6 +53,10 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		return nil, err
 	}
 
+	if err := s.validateRequestLimits(apimethod.Check, req.GetContext(), len(req.GetContextualTuples().GetTupleKeys())); err != nil {
+		return nil, err
+	}
+
 	if !validator.RequestIsValidatedFromContext(ctx) {
 		if err := req.Validate(); err != nil {
 			return nil, status.Error(codes.InvalidArgument, err.Error())
@@ -61,21 +70,13 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		return nil, err
 	}
 
-	checkQuery := commands.NewCheckCommand(
-		s.datastore,
-		s.checkResolver,
-		typesys,
-		commands.WithCheckCommandLogger(s.logger),
-		commands.WithCheckCommandMaxConcurrentReads(s.maxConcurrentReadsForCheck),
-		commands.WithCheckCommandCache(s.sharedDatastoreResources, s.cacheSettings),
-		commands.WithCheckDatastoreThrottler(s.checkDatastoreThrottleThreshold, s.checkDatastoreThrottleDuration),
-	)
+	checkQuery := commands.NewCheckCommand(s.datastore, s.checkResolver, typesys, s.checkCommandOptions(ctx)...)
 
 	resp, checkRequestMetadata, err := checkQuery.Execute(ctx, &commands.CheckCommandParams{
 		StoreID:          storeID,
 		TupleKey:         req.GetTupleKey(),
 		ContextualTuples: req.GetContextualTuples(),
-		Context:          req.GetContext(),
+		Context:          s.withTrustedContext(ctx, req.GetContext()),
 		Consistency:      req.GetConsistency(),
 	})
 
@@ -104,18 +105,20 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 
 		grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, queryCount)
 		span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, queryCount))
-		datastoreQueryCountHistogram.WithLabelValues(
+		telemetry.ObserveWithTraceExemplar(ctx, datastoreQueryCountHistogram.WithLabelValues(
 			s.serviceName,
 			methodName,
-		).Observe(queryCount)
+			s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+		), queryCount)
 
-		requestDurationHistogram.WithLabelValues(
+		telemetry.ObserveWithTraceExemplar(ctx, requestDurationHistogram.WithLabelValues(
 			s.serviceName,
 			methodName,
 			utils.Bucketize(uint(queryCount), s.requestDurationByQueryHistogramBuckets),
 			utils.Bucketize(uint(rawDispatchCount), s.requestDurationByDispatchCountHistogramBuckets),
 			req.GetConsistency().String(),
-		).Observe(float64(endTime))
+			s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+		), float64(endTime))
 
 		if s.authorizer.AccessControlStoreID() == req.GetStoreId() {
 			accessControlStoreCheckDurationHistogram.WithLabelValues(
@@ -148,9 +151,29 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		attribute.Bool("cycle_detected", resp.GetCycleDetected()),
 		attribute.Bool("allowed", resp.GetAllowed()))
 
+	s.setResolutionMetadataHeaders(ctx,
+		resp.GetResolutionMetadata().DatastoreQueryCount,
+		rawDispatchCount,
+		checkRequestMetadata.CheckCacheHits.Load(),
+	)
+
 	res := &openfgav1.CheckResponse{
 		Allowed: resp.Allowed,
 	}
 
 	return res, nil
 }
+
+// checkCommandOptions returns the options of the Check commands of the server, shared by Check and the methods
+// resolving checks of their own.
+func (s *Server) checkCommandOptions(ctx context.Context) []commands.CheckQueryOption {
+	return []commands.CheckQueryOption{
+		commands.WithCheckCommandLogger(s.logger),
+		commands.WithCheckCommandMaxConcurrentReads(s.TunableSettings().MaxConcurrentReadsForCheck),
+		commands.WithCheckCommandCache(s.sharedDatastoreResources, s.requestCacheSettings()),
+		commands.WithCheckDatastoreThrottler(s.checkDatastoreThrottleThreshold, s.checkDatastoreThrottleDuration),
+		commands.WithCheckLimiterSaturationMonitor(s.datastoreLimiterSaturationMonitor),
+		commands.WithCheckHedgeDelay(s.datastoreHedgeDelay),
+		commands.WithCheckCommandMaxResolutionDepth(limitoverrides.FromContext(ctx).MaxResolveDepth),
+	}
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"slices"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// AssertionCoverage describes which relations and types of an authorization model are exercised by at least
+// one of its assertions. Relations are written as 'type#relation', and types without relations are left out,
+// since no assertion can exercise them.
+type AssertionCoverage struct {
+	CoveredRelations   []string
+	UncoveredRelations []string
+	CoveredTypes       []string
+	UncoveredTypes     []string
+}
+
+// ComputeAssertionCoverage returns the coverage of the model of typesys by assertions. Assertions on a relation
+// that the model does not define are ignored.
+func ComputeAssertionCoverage(typesys *typesystem.TypeSystem, assertions []*openfgav1.Assertion) *AssertionCoverage {
+	exercised := make(map[string]struct{}, len(assertions))
+	for _, assertion := range assertions {
+		tk := assertion.GetTupleKey()
+		exercised[tuple.ToObjectRelationString(tuple.GetType(tk.GetObject()), tk.GetRelation())] = struct{}{}
+	}
+
+	coverage := &AssertionCoverage{}
+	for objectType, relations := range typesys.GetAllRelations() {
+		if len(relations) == 0 {
+			continue
+		}
+
+		typeCovered := false
+		for relation := range relations {
+			ref := tuple.ToObjectRelationString(objectType, relation)
+			if _, ok := exercised[ref]; ok {
+				coverage.CoveredRelations = append(coverage.CoveredRelations, ref)
+				typeCovered = true
+			} else {
+				coverage.UncoveredRelations = append(coverage.UncoveredRelations, ref)
+			}
+		}
+
+		if typeCovered {
+			coverage.CoveredTypes = append(coverage.CoveredTypes, objectType)
+		} else {
+			coverage.UncoveredTypes = append(coverage.UncoveredTypes, objectType)
+		}
+	}
+
+	slices.Sort(coverage.CoveredRelations)
+	slices.Sort(coverage.UncoveredRelations)
+	slices.Sort(coverage.CoveredTypes)
+	slices.Sort(coverage.UncoveredTypes)
+
+	return coverage
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestComputeAssertionCoverage(t *testing.T) {
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type folder
+			relations
+				define viewer: [user]
+		type doc
+			relations
+				define owner: [user]
+				define editor: [user] or owner
+				define viewer: [user] or editor`)
+	ts, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+
+	t.Run("no_assertions", func(t *testing.T) {
+		coverage := ComputeAssertionCoverage(ts, nil)
+		require.Empty(t, coverage.CoveredRelations)
+		require.Equal(t, []string{"doc#editor", "doc#owner", "doc#viewer", "folder#viewer"}, coverage.UncoveredRelations)
+		require.Empty(t, coverage.CoveredTypes)
+		require.Equal(t, []string{"doc", "folder"}, coverage.UncoveredTypes)
+	})
+
+	t.Run("partial_coverage", func(t *testing.T) {
+		coverage := ComputeAssertionCoverage(ts, []*openfgav1.Assertion{
+			{TupleKey: tuple.NewAssertionTupleKey("doc:1", "viewer", "user:anne"), Expectation: true},
+			{TupleKey: tuple.NewAssertionTupleKey("doc:2", "viewer", "user:bob"), Expectation: false},
+			{TupleKey: tuple.NewAssertionTupleKey("doc:1", "owner", "user:anne"), Expectation: true},
+			{TupleKey: tuple.NewAssertionTupleKey("doc:1", "undefined", "user:anne"), Expectation: false},
+		})
+		require.Equal(t, []string{"doc#owner", "doc#viewer"}, coverage.CoveredRelations)
+		require.Equal(t, []string{"doc#editor", "folder#viewer"}, coverage.UncoveredRelations)
+		require.Equal(t, []string{"doc"}, coverage.CoveredTypes)
+		require.Equal(t, []string{"folder"}, coverage.UncoveredTypes)
+	})
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/server/config"
 	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	"github.com/openfga/openfga/pkg/tuple"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
@@ -33,6 +35,8 @@ type BatchCheckQuery struct {
 	typesys                    *typesystem.TypeSystem
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	saturationMonitor          *storagewrappers.LimiterSaturationMonitor
+	hedgeDelay                 time.Duration
 }
 
 type BatchCheckCommandParams struct {
@@ -51,6 +55,7 @@ type BatchCheckMetadata struct {
 	ThrottleCount       uint32
 	DispatchCount       uint32
 	DatastoreQueryCount uint32
+	CheckCacheHits      uint32
 	DuplicateCheckCount int
 }
 
@@ -104,6 +109,20 @@ func WithBatchCheckDatastoreThrottler(threshold int, duration time.Duration) Bat
 	}
 }
 
+// WithBatchCheckLimiterSaturationMonitor sets the monitor that observes the wait times of the datastore concurrency limiter.
+func WithBatchCheckLimiterSaturationMonitor(m *storagewrappers.LimiterSaturationMonitor) BatchCheckQueryOption {
+	return func(bq *BatchCheckQuery) {
+		bq.saturationMonitor = m
+	}
+}
+
+// WithBatchCheckHedgeDelay sets how long a datastore read of a tuple may take before an identical read is issued.
+func WithBatchCheckHedgeDelay(d time.Duration) BatchCheckQueryOption {
+	return func(bq *BatchCheckQuery) {
+		bq.hedgeDelay = d
+	}
+}
+
 func NewBatchCheckCommand(datastore storage.RelationshipTupleReader, checkResolver graph.CheckResolver, typesys *typesystem.TypeSystem, opts ...BatchCheckQueryOption) *BatchCheckQuery {
 	cmd := &BatchCheckQuery{
 		logger:              logger.NewNoopLogger(),
@@ -163,8 +182,15 @@ func (bq *BatchCheckQuery) Execute(ctx context.Context, params *BatchCheckComman
 
 	var resultMap = new(sync.Map)
 	var totalQueryCount atomic.Uint32
+
+	datastore := bq.datastore
+	if prefetched, ok := bq.prefetchDirectTuples(ctx, params, cacheKeyMap); ok {
+		datastore = prefetched
+		totalQueryCount.Add(1)
+	}
 	var totalDispatchCount atomic.Uint32
 	var totalThrottleCount atomic.Uint32
+	var totalCheckCacheHits atomic.Uint32
 
 	pool := concurrency.NewPool(ctx, int(bq.maxConcurrentChecks))
 	for key, item := range cacheKeyMap {
@@ -180,12 +206,14 @@ func (bq *BatchCheckQuery) Execute(ctx context.Context, params *BatchCheckComman
 			}
 
 			checkQuery := NewCheckCommand(
-				bq.datastore,
+				datastore,
 				bq.checkResolver,
 				bq.typesys,
 				WithCheckCommandLogger(bq.logger),
 				WithCheckCommandCache(bq.sharedCheckResources, bq.cacheSettings),
 				WithCheckDatastoreThrottler(bq.datastoreThrottleThreshold, bq.datastoreThrottleDuration),
+				WithCheckLimiterSaturationMonitor(bq.saturationMonitor),
+				WithCheckHedgeDelay(bq.hedgeDelay),
 			)
 
 			checkParams := &CheckCommandParams{
@@ -208,6 +236,7 @@ func (bq *BatchCheckQuery) Execute(ctx context.Context, params *BatchCheckComman
 					totalThrottleCount.Add(1)
 				}
 				totalDispatchCount.Add(metadata.DispatchCounter.Load())
+				totalCheckCacheHits.Add(metadata.CheckCacheHits.Load())
 			}
 
 			totalQueryCount.Add(response.GetResolutionMetadata().DatastoreQueryCount)
@@ -235,10 +264,49 @@ func (bq *BatchCheckQuery) Execute(ctx context.Context, params *BatchCheckComman
 		ThrottleCount:       totalThrottleCount.Load(),
 		DatastoreQueryCount: totalQueryCount.Load(),
 		DispatchCount:       totalDispatchCount.Load(),
+		CheckCacheHits:      totalCheckCacheHits.Load(),
 		DuplicateCheckCount: len(params.Checks) - len(cacheKeyMap),
 	}, nil
 }
 
+// prefetchDirectTuples reads, in a single round-trip, the tuples that would satisfy each check in the batch
+// through a direct relationship, and returns a reader that serves them to the direct-tuple lookups of the
+// individual checks. It returns false if there is nothing to prefetch or the read failed, in which case
+// the checks read from the datastore as usual.
+func (bq *BatchCheckQuery) prefetchDirectTuples(
+	ctx context.Context,
+	params *BatchCheckCommandParams,
+	cacheKeyMap map[CacheKey]*checkAndCorrelationIDs,
+) (storage.RelationshipTupleReader, bool) {
+	tupleKeys := make([]*openfgav1.TupleKey, 0, len(cacheKeyMap))
+	for _, item := range cacheKeyMap {
+		tk := tuple.ConvertCheckRequestTupleKeyToTupleKey(item.Check.GetTupleKey())
+		isDirectlyRelated, _ := bq.typesys.IsDirectlyRelated(
+			typesystem.DirectRelationReference(tuple.GetType(tk.GetObject()), tk.GetRelation()),
+			typesystem.DirectRelationReference(tuple.GetType(tk.GetUser()), tuple.GetRelation(tk.GetUser())),
+		)
+		if isDirectlyRelated {
+			tupleKeys = append(tupleKeys, tk)
+		}
+	}
+
+	if len(tupleKeys) < 2 {
+		return nil, false
+	}
+
+	found, err := bq.datastore.ReadUserTuples(ctx, params.StoreID, tupleKeys, storage.ReadUserTupleOptions{
+		Consistency: storage.ConsistencyOptions{
+			Preference: params.Consistency,
+		},
+	})
+	if err != nil {
+		bq.logger.Warn("batch check failed to prefetch direct tuples", zap.Error(err))
+		return nil, false
+	}
+
+	return storagewrappers.NewPrefetchedUserTupleReader(bq.datastore, params.StoreID, tupleKeys, found), true
+}
+
 func validateCorrelationIDs(checks []*openfgav1.BatchCheckItem) error {
 	seen := map[string]struct{}{}
 
@@ -17,6 +17,7 @@ import (
 	"github.com/openfga/openfga/pkg/server/config"
 	"github.com/openfga/openfga/pkg/storage/memory"
 	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
@@ -29,6 +30,7 @@ func TestBatchCheckCommand(t *testing.T) {
 	mockController := gomock.NewController(t)
 	defer mockController.Finish()
 	ds := mockstorage.NewMockOpenFGADatastore(mockController)
+	ds.EXPECT().ReadUserTuples(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 
 	model := testutils.MustTransformDSLToProtoWithID(`
 		model
@@ -76,8 +78,8 @@ func TestBatchCheckCommand(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, len(result), numChecks)
 
-		// No actual datastore queries should have been run since we're mocking
-		require.Equal(t, 0, int(meta.DatastoreQueryCount))
+		// Only the direct tuple prefetch should have been run since we're mocking the check resolver
+		require.Equal(t, 1, int(meta.DatastoreQueryCount))
 		require.Equal(t, 0, meta.DuplicateCheckCount)
 	})
 
@@ -352,6 +354,51 @@ func TestBatchCheckCommand(t *testing.T) {
 	})
 }
 
+func TestBatchCheckCommandPrefetchesDirectTuples(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type doc
+			relations
+				define viewer: [user]
+	`)
+	ts, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+
+	storeID := ulid.Make().String()
+	err = ds.Write(context.Background(), storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+	})
+	require.NoError(t, err)
+
+	checkResolver, checkResolverCloser, err := graph.NewOrderedCheckResolvers().Build()
+	require.NoError(t, err)
+	t.Cleanup(checkResolverCloser)
+
+	cmd := NewBatchCheckCommand(ds, checkResolver, ts)
+	result, _, err := cmd.Execute(context.Background(), &BatchCheckCommandParams{
+		AuthorizationModelID: ts.GetAuthorizationModelID(),
+		Checks: []*openfgav1.BatchCheckItem{
+			{TupleKey: tuple.NewCheckRequestTupleKey("doc:1", "viewer", "user:anne"), CorrelationId: "anne"},
+			{TupleKey: tuple.NewCheckRequestTupleKey("doc:1", "viewer", "user:bob"), CorrelationId: "bob"},
+		},
+		StoreID: storeID,
+	})
+	require.NoError(t, err)
+	require.NoError(t, result["anne"].Err)
+	require.True(t, result["anne"].CheckResponse.GetAllowed())
+	require.NoError(t, result["bob"].Err)
+	require.False(t, result["bob"].CheckResponse.GetAllowed())
+}
+
 func BenchmarkBatchCheckCommand(b *testing.B) {
 	ds := memory.New()
 	model := testutils.MustTransformDSLToProtoWithID(`
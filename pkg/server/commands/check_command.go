@@ -38,6 +38,9 @@ type CheckQuery struct {
 	shouldCacheIterators       bool
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	saturationMonitor          *storagewrappers.LimiterSaturationMonitor
+	hedgeDelay                 time.Duration
+	maxResolutionDepth         uint32
 }
 
 type CheckCommandParams struct {
@@ -76,6 +79,28 @@ func WithCheckDatastoreThrottler(threshold int, duration time.Duration) CheckQue
 	}
 }
 
+// WithCheckLimiterSaturationMonitor sets the monitor that observes the wait times of the datastore concurrency limiter.
+func WithCheckLimiterSaturationMonitor(m *storagewrappers.LimiterSaturationMonitor) CheckQueryOption {
+	return func(c *CheckQuery) {
+		c.saturationMonitor = m
+	}
+}
+
+// WithCheckHedgeDelay sets how long a datastore read of a tuple may take before an identical read is issued.
+func WithCheckHedgeDelay(d time.Duration) CheckQueryOption {
+	return func(c *CheckQuery) {
+		c.hedgeDelay = d
+	}
+}
+
+// WithCheckCommandMaxResolutionDepth overrides the maximum resolution depth of the check resolver for this check.
+// 0 keeps the depth of the check resolver.
+func WithCheckCommandMaxResolutionDepth(depth uint32) CheckQueryOption {
+	return func(c *CheckQuery) {
+		c.maxResolutionDepth = depth
+	}
+}
+
 // TODO accept CheckCommandParams so we can build the datastore object right away.
 func NewCheckCommand(datastore storage.RelationshipTupleReader, checkResolver graph.CheckResolver, typesys *typesystem.TypeSystem, opts ...CheckQueryOption) *CheckQuery {
 	cmd := &CheckQuery{
@@ -109,15 +134,21 @@ func (c *CheckQuery) Execute(ctx context.Context, params *CheckCommandParams) (*
 		cacheInvalidationTime = c.sharedCheckResources.CacheController.DetermineInvalidationTime(ctx, params.StoreID)
 	}
 
+	tupleKey := tuple.ConvertCheckRequestTupleKeyToTupleKey(params.TupleKey)
+	if !tuple.IsSelfDefining(tupleKey) {
+		tupleKey.Relation = c.typesys.ResolveRelationAlias(tuple.GetType(tupleKey.GetObject()), tupleKey.GetRelation())
+	}
+
 	resolveCheckRequest, err := graph.NewResolveCheckRequest(
 		graph.ResolveCheckRequestParams{
 			StoreID:                   params.StoreID,
-			TupleKey:                  tuple.ConvertCheckRequestTupleKeyToTupleKey(params.TupleKey),
+			TupleKey:                  tupleKey,
 			Context:                   params.Context,
 			ContextualTuples:          params.ContextualTuples,
 			Consistency:               params.Consistency,
 			LastCacheInvalidationTime: cacheInvalidationTime,
 			AuthorizationModelID:      c.typesys.GetAuthorizationModelID(),
+			MaxResolutionDepth:        c.maxResolutionDepth,
 		},
 	)
 
@@ -133,6 +164,8 @@ func (c *CheckQuery) Execute(ctx context.Context, params *CheckCommandParams) (*
 			Concurrency:       c.maxConcurrentReads,
 			ThrottleThreshold: c.datastoreThrottleThreshold,
 			ThrottleDuration:  c.datastoreThrottleDuration,
+			SaturationMonitor: c.saturationMonitor,
+			HedgeDelay:        c.hedgeDelay,
 		},
 		c.sharedCheckResources,
 		c.cacheSettings,
@@ -258,7 +258,7 @@ func TestCheckCommandErrorToServerError(t *testing.T) {
 		},
 		`2`: {
 			inputError:    condition.ErrEvaluationFailed,
-			expectedError: serverErrors.ValidationError(condition.ErrEvaluationFailed),
+			expectedError: serverErrors.ConditionEvaluationError(condition.ErrEvaluationFailed),
 		},
 		`3`: {
 			inputError:    &ThrottledError{},
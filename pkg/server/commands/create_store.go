@@ -15,6 +15,7 @@ import (
 type CreateStoreCommand struct {
 	storesBackend storage.StoresBackend
 	logger        logger.Logger
+	labels        map[string]string
 }
 
 type CreateStoreCmdOption func(*CreateStoreCommand)
@@ -25,6 +26,13 @@ func WithCreateStoreCmdLogger(l logger.Logger) CreateStoreCmdOption {
 	}
 }
 
+// WithCreateStoreCmdLabels sets the labels of the store created.
+func WithCreateStoreCmdLabels(labels map[string]string) CreateStoreCmdOption {
+	return func(c *CreateStoreCommand) {
+		c.labels = labels
+	}
+}
+
 func NewCreateStoreCommand(
 	storesBackend storage.StoresBackend,
 	opts ...CreateStoreCmdOption,
@@ -41,11 +49,23 @@ func NewCreateStoreCommand(
 }
 
 func (s *CreateStoreCommand) Execute(ctx context.Context, req *openfgav1.CreateStoreRequest) (*openfgav1.CreateStoreResponse, error) {
-	store, err := s.storesBackend.CreateStore(ctx, &openfgav1.Store{
+	if err := ValidateStoreLabels(s.labels); err != nil {
+		return nil, err
+	}
+
+	newStore := &openfgav1.Store{
 		Id:   ulid.Make().String(),
 		Name: req.GetName(),
 		// TODO why not pass CreatedAt and UpdatedAt as derived from the ulid?
-	})
+	}
+
+	var store *openfgav1.Store
+	var err error
+	if len(s.labels) > 0 {
+		store, err = s.storesBackend.CreateStoreWithLabels(ctx, newStore, s.labels)
+	} else {
+		store, err = s.storesBackend.CreateStore(ctx, newStore)
+	}
 	if err != nil {
 		return nil, serverErrors.HandleError("", err)
 	}
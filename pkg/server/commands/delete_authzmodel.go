@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/pkg/logger"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// DeleteAuthorizationModelCommand deletes authorization models of a store, other than its latest one.
+type DeleteAuthorizationModelCommand struct {
+	backend storage.AuthorizationModelBackend
+	logger  logger.Logger
+}
+
+type DeleteAuthModelOption func(*DeleteAuthorizationModelCommand)
+
+func WithDeleteAuthModelLogger(l logger.Logger) DeleteAuthModelOption {
+	return func(c *DeleteAuthorizationModelCommand) {
+		c.logger = l
+	}
+}
+
+func NewDeleteAuthorizationModelCommand(backend storage.AuthorizationModelBackend, opts ...DeleteAuthModelOption) *DeleteAuthorizationModelCommand {
+	cmd := &DeleteAuthorizationModelCommand{
+		backend: backend,
+		logger:  logger.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(cmd)
+	}
+	return cmd
+}
+
+// Execute deletes the model modelID of the store. The latest model of the store, which is the one requests
+// without a model id are evaluated against, cannot be deleted.
+func (c *DeleteAuthorizationModelCommand) Execute(ctx context.Context, storeID, modelID string) error {
+	latest, err := c.backend.FindLatestAuthorizationModel(ctx, storeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return serverErrors.AuthorizationModelNotFound(modelID)
+		}
+		return serverErrors.HandleError("", err)
+	}
+
+	if latest.GetId() == modelID {
+		return status.Errorf(codes.FailedPrecondition, "the latest authorization model '%s' of the store cannot be deleted", modelID)
+	}
+
+	if err := c.backend.DeleteAuthorizationModel(ctx, storeID, modelID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return serverErrors.AuthorizationModelNotFound(modelID)
+		}
+		return serverErrors.HandleError("", err)
+	}
+	return nil
+}
+
+// Prune deletes the models of the store but the keep most recent ones, and returns the number of models deleted.
+// keep must be at least 1 so that the latest model is kept.
+func (c *DeleteAuthorizationModelCommand) Prune(ctx context.Context, storeID string, keep int) (int, error) {
+	if keep < 1 {
+		return 0, errors.New("at least one authorization model must be kept")
+	}
+
+	// Models are read from newest to oldest. They are collected before being deleted, so that the deletions do
+	// not shift the pages being read.
+	var (
+		pruned            []string
+		seen              int
+		continuationToken string
+	)
+	for {
+		models, token, err := c.backend.ReadAuthorizationModels(ctx, storeID, storage.ReadAuthorizationModelsOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, continuationToken),
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, model := range models {
+			seen++
+			if seen > keep {
+				pruned = append(pruned, model.GetId())
+			}
+		}
+		if token == "" {
+			break
+		}
+		continuationToken = token
+	}
+
+	for i, modelID := range pruned {
+		err := c.backend.DeleteAuthorizationModel(ctx, storeID, modelID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return i, err
+		}
+	}
+	return len(pruned), nil
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+func TestDeleteAuthorizationModel(t *testing.T) {
+	storeID := ulid.Make().String()
+	latest := &openfgav1.AuthorizationModel{Id: ulid.Make().String()}
+	modelID := ulid.Make().String()
+
+	t.Run("deletes_an_older_model", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Times(1).Return(latest, nil)
+		mockDatastore.EXPECT().DeleteAuthorizationModel(gomock.Any(), storeID, modelID).Times(1).Return(nil)
+
+		err := NewDeleteAuthorizationModelCommand(mockDatastore).Execute(context.Background(), storeID, modelID)
+		require.NoError(t, err)
+	})
+
+	t.Run("refuses_to_delete_the_latest_model", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Times(1).Return(latest, nil)
+
+		err := NewDeleteAuthorizationModelCommand(mockDatastore).Execute(context.Background(), storeID, latest.GetId())
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("unknown_model_returns_not_found", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Times(1).Return(latest, nil)
+		mockDatastore.EXPECT().DeleteAuthorizationModel(gomock.Any(), storeID, modelID).Times(1).Return(storage.ErrNotFound)
+
+		err := NewDeleteAuthorizationModelCommand(mockDatastore).Execute(context.Background(), storeID, modelID)
+		require.ErrorIs(t, err, serverErrors.AuthorizationModelNotFound(modelID))
+	})
+}
+
+func TestPruneAuthorizationModels(t *testing.T) {
+	storeID := ulid.Make().String()
+	models := []*openfgav1.AuthorizationModel{
+		{Id: ulid.Make().String()},
+		{Id: ulid.Make().String()},
+		{Id: ulid.Make().String()},
+	}
+
+	t.Run("deletes_the_models_beyond_the_ones_kept", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		gomock.InOrder(
+			mockDatastore.EXPECT().ReadAuthorizationModels(gomock.Any(), storeID, gomock.Any()).Times(1).Return(models[:2], "token", nil),
+			mockDatastore.EXPECT().ReadAuthorizationModels(gomock.Any(), storeID, gomock.Any()).Times(1).Return(models[2:], "", nil),
+			mockDatastore.EXPECT().DeleteAuthorizationModel(gomock.Any(), storeID, models[1].GetId()).Times(1).Return(nil),
+			mockDatastore.EXPECT().DeleteAuthorizationModel(gomock.Any(), storeID, models[2].GetId()).Times(1).Return(nil),
+		)
+
+		pruned, err := NewDeleteAuthorizationModelCommand(mockDatastore).Prune(context.Background(), storeID, 1)
+		require.NoError(t, err)
+		require.Equal(t, 2, pruned)
+	})
+
+	t.Run("keeps_every_model_within_the_retention", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadAuthorizationModels(gomock.Any(), storeID, gomock.Any()).Times(1).Return(models, "", nil)
+
+		pruned, err := NewDeleteAuthorizationModelCommand(mockDatastore).Prune(context.Background(), storeID, 3)
+		require.NoError(t, err)
+		require.Zero(t, pruned)
+	})
+
+	t.Run("requires_keeping_a_model", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+
+		_, err := NewDeleteAuthorizationModelCommand(mockDatastore).Prune(context.Background(), storeID, 0)
+		require.Error(t, err)
+	})
+}
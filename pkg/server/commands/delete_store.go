@@ -12,8 +12,10 @@ import (
 )
 
 type DeleteStoreCommand struct {
-	storesBackend storage.StoresBackend
-	logger        logger.Logger
+	storesBackend   storage.StoresBackend
+	logger          logger.Logger
+	tupleReader     storage.RelationshipTupleReader
+	protectedTuples *ProtectedTuples
 }
 
 type DeleteStoreCmdOption func(*DeleteStoreCommand)
@@ -24,6 +26,15 @@ func WithDeleteStoreCmdLogger(l logger.Logger) DeleteStoreCmdOption {
 	}
 }
 
+// WithDeleteStoreCmdProtectedTuples rejects deleting a store holding tuples under legal hold, read with tupleReader,
+// unless the request is a forced delete issued by a privileged principal, as deleting the tuples would be.
+func WithDeleteStoreCmdProtectedTuples(tupleReader storage.RelationshipTupleReader, p *ProtectedTuples) DeleteStoreCmdOption {
+	return func(c *DeleteStoreCommand) {
+		c.tupleReader = tupleReader
+		c.protectedTuples = p
+	}
+}
+
 func NewDeleteStoreCommand(
 	storesBackend storage.StoresBackend,
 	opts ...DeleteStoreCmdOption,
@@ -48,6 +59,12 @@ func (s *DeleteStoreCommand) Execute(ctx context.Context, req *openfgav1.DeleteS
 		return nil, serverErrors.HandleError("", err)
 	}
 
+	if s.tupleReader != nil {
+		if err := s.protectedTuples.validateDeleteStore(ctx, s.tupleReader, store.GetId()); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.storesBackend.DeleteStore(ctx, store.GetId()); err != nil {
 		return nil, serverErrors.HandleError("Error deleting store", err)
 	}
@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// AuthorizationModelDiff describes the changes from an authorization model to another. Relations are written as
+// 'type#relation' and directly related user types as 'user', 'user:*' or 'group#member', followed by
+// ' with <condition>' when conditional. Every list is sorted.
+type AuthorizationModelDiff struct {
+	AddedTypes        []string
+	RemovedTypes      []string
+	AddedRelations    []string
+	RemovedRelations  []string
+	ChangedRelations  []RelationChange
+	AddedConditions   []string
+	RemovedConditions []string
+	ChangedConditions []string
+
+	// Breaking reports whether the change may revoke access granted by the previous model, or make requests that
+	// succeeded against it fail: a type, relation or condition was removed, a rewrite or condition was changed, or
+	// a directly related user type was removed.
+	Breaking bool
+}
+
+// RelationChange describes the changes of a relation defined by both models.
+type RelationChange struct {
+	Relation string
+	// RewriteChanged reports whether the rewrite defining the relation changed.
+	RewriteChanged                  bool
+	AddedDirectlyRelatedUserTypes   []string
+	RemovedDirectlyRelatedUserTypes []string
+}
+
+// DiffAuthorizationModels returns the changes from the model from to the model to.
+func DiffAuthorizationModels(from, to *openfgav1.AuthorizationModel) *AuthorizationModelDiff {
+	diff := &AuthorizationModelDiff{}
+
+	fromTypes := typeDefinitionsByType(from)
+	toTypes := typeDefinitionsByType(to)
+
+	for _, objectType := range slices.Sorted(maps.Keys(toTypes)) {
+		if _, ok := fromTypes[objectType]; !ok {
+			diff.AddedTypes = append(diff.AddedTypes, objectType)
+		}
+	}
+
+	for _, objectType := range slices.Sorted(maps.Keys(fromTypes)) {
+		fromType := fromTypes[objectType]
+		toType, ok := toTypes[objectType]
+		if !ok {
+			diff.RemovedTypes = append(diff.RemovedTypes, objectType)
+			continue
+		}
+
+		for _, relation := range slices.Sorted(maps.Keys(fromType.GetRelations())) {
+			ref := tuple.ToObjectRelationString(objectType, relation)
+			toRewrite, ok := toType.GetRelations()[relation]
+			if !ok {
+				diff.RemovedRelations = append(diff.RemovedRelations, ref)
+				continue
+			}
+
+			added, removed := diffStrings(
+				directlyRelatedUserTypes(fromType, relation),
+				directlyRelatedUserTypes(toType, relation),
+			)
+			change := RelationChange{
+				Relation:                        ref,
+				RewriteChanged:                  !proto.Equal(fromType.GetRelations()[relation], toRewrite),
+				AddedDirectlyRelatedUserTypes:   added,
+				RemovedDirectlyRelatedUserTypes: removed,
+			}
+			if change.RewriteChanged || len(added) > 0 || len(removed) > 0 {
+				diff.ChangedRelations = append(diff.ChangedRelations, change)
+			}
+			if change.RewriteChanged || len(removed) > 0 {
+				diff.Breaking = true
+			}
+		}
+	}
+
+	for _, objectType := range slices.Sorted(maps.Keys(toTypes)) {
+		fromType := fromTypes[objectType]
+		for _, relation := range slices.Sorted(maps.Keys(toTypes[objectType].GetRelations())) {
+			if _, ok := fromType.GetRelations()[relation]; !ok {
+				diff.AddedRelations = append(diff.AddedRelations, tuple.ToObjectRelationString(objectType, relation))
+			}
+		}
+	}
+
+	fromConditions := from.GetConditions()
+	toConditions := to.GetConditions()
+	for _, name := range slices.Sorted(maps.Keys(toConditions)) {
+		if _, ok := fromConditions[name]; !ok {
+			diff.AddedConditions = append(diff.AddedConditions, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(fromConditions)) {
+		toCondition, ok := toConditions[name]
+		switch {
+		case !ok:
+			diff.RemovedConditions = append(diff.RemovedConditions, name)
+		// the expressions keep the whitespace surrounding them in the DSL
+		case strings.TrimSpace(fromConditions[name].GetExpression()) != strings.TrimSpace(toCondition.GetExpression()) ||
+			!maps.EqualFunc(fromConditions[name].GetParameters(), toCondition.GetParameters(), func(a, b *openfgav1.ConditionParamTypeRef) bool {
+				return proto.Equal(a, b)
+			}):
+			diff.ChangedConditions = append(diff.ChangedConditions, name)
+		}
+	}
+
+	if len(diff.RemovedTypes) > 0 || len(diff.RemovedRelations) > 0 ||
+		len(diff.RemovedConditions) > 0 || len(diff.ChangedConditions) > 0 {
+		diff.Breaking = true
+	}
+
+	return diff
+}
+
+func typeDefinitionsByType(model *openfgav1.AuthorizationModel) map[string]*openfgav1.TypeDefinition {
+	typeDefinitions := make(map[string]*openfgav1.TypeDefinition, len(model.GetTypeDefinitions()))
+	for _, typeDefinition := range model.GetTypeDefinitions() {
+		typeDefinitions[typeDefinition.GetType()] = typeDefinition
+	}
+	return typeDefinitions
+}
+
+// directlyRelatedUserTypes returns the user types directly related to the relation of typeDefinition.
+func directlyRelatedUserTypes(typeDefinition *openfgav1.TypeDefinition, relation string) []string {
+	refs := typeDefinition.GetMetadata().GetRelations()[relation].GetDirectlyRelatedUserTypes()
+	userTypes := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		userType := ref.GetType()
+		switch {
+		case ref.GetRelation() != "":
+			userType = tuple.ToObjectRelationString(userType, ref.GetRelation())
+		case ref.GetWildcard() != nil:
+			userType = tuple.TypedPublicWildcard(userType)
+		}
+		if ref.GetCondition() != "" {
+			userType += " with " + ref.GetCondition()
+		}
+		userTypes = append(userTypes, userType)
+	}
+	return userTypes
+}
+
+// diffStrings returns the sorted strings of to missing from from, and those of from missing from to.
+func diffStrings(from, to []string) (added, removed []string) {
+	for _, s := range to {
+		if !slices.Contains(from, s) {
+			added = append(added, s)
+		}
+	}
+	for _, s := range from {
+		if !slices.Contains(to, s) {
+			removed = append(removed, s)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+// DiffAuthorizationModelsQuery compares two authorization models of a store.
+type DiffAuthorizationModelsQuery struct {
+	backend storage.AuthorizationModelReadBackend
+	logger  logger.Logger
+}
+
+type DiffAuthModelsQueryOption func(*DiffAuthorizationModelsQuery)
+
+func WithDiffAuthModelsQueryLogger(l logger.Logger) DiffAuthModelsQueryOption {
+	return func(q *DiffAuthorizationModelsQuery) {
+		q.logger = l
+	}
+}
+
+func NewDiffAuthorizationModelsQuery(backend storage.AuthorizationModelReadBackend, opts ...DiffAuthModelsQueryOption) *DiffAuthorizationModelsQuery {
+	q := &DiffAuthorizationModelsQuery{
+		backend: backend,
+		logger:  logger.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Execute returns the changes from the model fromModelID of the store to its model toModelID.
+func (q *DiffAuthorizationModelsQuery) Execute(ctx context.Context, storeID, fromModelID, toModelID string) (*AuthorizationModelDiff, error) {
+	read := NewReadAuthorizationModelQuery(q.backend, WithReadAuthModelQueryLogger(q.logger))
+
+	from, err := read.Execute(ctx, &openfgav1.ReadAuthorizationModelRequest{StoreId: storeID, Id: fromModelID})
+	if err != nil {
+		return nil, err
+	}
+	to, err := read.Execute(ctx, &openfgav1.ReadAuthorizationModelRequest{StoreId: storeID, Id: toModelID})
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffAuthorizationModels(from.GetAuthorizationModel(), to.GetAuthorizationModel()), nil
+}
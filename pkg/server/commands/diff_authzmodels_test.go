@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/internal/mocks"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+func TestDiffAuthorizationModels(t *testing.T) {
+	from := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+
+		type user
+
+		type team
+			relations
+				define member: [user]
+
+		type folder
+			relations
+				define viewer: [user]
+
+		type document
+			relations
+				define owner: [user]
+				define editor: [user, team#member]
+				define viewer: [user] or editor
+
+		condition non_expired(expires_at: timestamp, now: timestamp) {
+			now < expires_at
+		}
+
+		condition in_region(region: string, allowed: list<string>) {
+			region in allowed
+		}`)
+
+	t.Run("additions_are_not_breaking", func(t *testing.T) {
+		to := parser.MustTransformDSLToProto(`
+			model
+				schema 1.1
+
+			type user
+
+			type team
+				relations
+					define member: [user, user:*]
+
+			type folder
+				relations
+					define viewer: [user]
+
+			type document
+				relations
+					define owner: [user]
+					define editor: [user, team#member]
+					define viewer: [user] or editor
+					define commenter: [user]
+
+			type project
+
+			condition non_expired(expires_at: timestamp, now: timestamp) {
+				now < expires_at
+			}
+
+			condition in_region(region: string, allowed: list<string>) {
+				region in allowed
+			}
+
+			condition weekday(day: int) {
+				day < 6
+			}`)
+
+		diff := DiffAuthorizationModels(from, to)
+		require.Equal(t, []string{"project"}, diff.AddedTypes)
+		require.Empty(t, diff.RemovedTypes)
+		require.Equal(t, []string{"document#commenter"}, diff.AddedRelations)
+		require.Empty(t, diff.RemovedRelations)
+		require.Equal(t, []RelationChange{{
+			Relation:                      "team#member",
+			AddedDirectlyRelatedUserTypes: []string{"user:*"},
+		}}, diff.ChangedRelations)
+		require.Equal(t, []string{"weekday"}, diff.AddedConditions)
+		require.False(t, diff.Breaking)
+	})
+
+	t.Run("removals_and_changes_are_breaking", func(t *testing.T) {
+		to := parser.MustTransformDSLToProto(`
+			model
+				schema 1.1
+
+			type user
+
+			type team
+				relations
+					define member: [user]
+
+			type document
+				relations
+					define editor: [user with non_expired]
+					define viewer: [user] or editor or owner
+					define owner: [user]
+
+			condition non_expired(expires_at: timestamp, current_time: timestamp) {
+				current_time < expires_at
+			}`)
+
+		diff := DiffAuthorizationModels(from, to)
+		require.Empty(t, diff.AddedTypes)
+		require.Equal(t, []string{"folder"}, diff.RemovedTypes)
+		require.Empty(t, diff.RemovedRelations)
+		require.Equal(t, []RelationChange{
+			{
+				Relation:                        "document#editor",
+				AddedDirectlyRelatedUserTypes:   []string{"user with non_expired"},
+				RemovedDirectlyRelatedUserTypes: []string{"team#member", "user"},
+			},
+			{
+				Relation:       "document#viewer",
+				RewriteChanged: true,
+			},
+		}, diff.ChangedRelations)
+		require.Equal(t, []string{"in_region"}, diff.RemovedConditions)
+		require.Equal(t, []string{"non_expired"}, diff.ChangedConditions)
+		require.True(t, diff.Breaking)
+	})
+
+	t.Run("identical_models_have_no_changes", func(t *testing.T) {
+		diff := DiffAuthorizationModels(from, from)
+		require.Equal(t, &AuthorizationModelDiff{}, diff)
+	})
+}
+
+func TestDiffAuthorizationModelsQuery(t *testing.T) {
+	storeID := ulid.Make().String()
+	from := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+
+		type user`)
+	from.Id = ulid.Make().String()
+	to := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+
+		type user
+
+		type document`)
+	to.Id = ulid.Make().String()
+
+	t.Run("compares_the_two_models", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, from.GetId()).Times(1).Return(from, nil)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, to.GetId()).Times(1).Return(to, nil)
+
+		diff, err := NewDiffAuthorizationModelsQuery(mockDatastore).Execute(context.Background(), storeID, from.GetId(), to.GetId())
+		require.NoError(t, err)
+		require.Equal(t, []string{"document"}, diff.AddedTypes)
+		require.False(t, diff.Breaking)
+	})
+
+	t.Run("unknown_model_returns_not_found", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, from.GetId()).Times(1).Return(from, nil)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, to.GetId()).Times(1).Return(nil, storage.ErrNotFound)
+
+		_, err := NewDiffAuthorizationModelsQuery(mockDatastore).Execute(context.Background(), storeID, from.GetId(), to.GetId())
+		require.ErrorIs(t, err, serverErrors.AuthorizationModelNotFound(to.GetId()))
+	})
+}
@@ -65,7 +65,7 @@ func CheckCommandErrorToServerError(err error) error {
 	}
 
 	if errors.Is(err, condition.ErrEvaluationFailed) {
-		return serverErrors.ValidationError(err)
+		return serverErrors.ConditionEvaluationError(err)
 	}
 
 	var throttledError *ThrottledError
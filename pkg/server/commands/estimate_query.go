@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"errors"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// The kinds of the rewrites of a BranchEstimate.
+const (
+	RewriteDirect          = "direct"
+	RewriteComputedUserset = "computed_userset"
+	RewriteTupleToUserset  = "tuple_to_userset"
+)
+
+// The set operations a BranchEstimate is an operand of.
+const (
+	OperationUnion             = "union"
+	OperationIntersection      = "intersection"
+	OperationExclusionBase     = "exclusion_base"
+	OperationExclusionSubtract = "exclusion_subtract"
+)
+
+// QueryEstimate is the expected cost of resolving a relation of an object type, e.g. with Check or ListObjects,
+// analyzed from the shape of the authorization model alone, without reading any tuple. Relations are written as
+// 'type#relation'.
+type QueryEstimate struct {
+	Relation string
+	// MaxDispatchDepth is the largest number of nested dispatches resolving the relation may take, following the
+	// longest chain of relations the analysis finds without going around a cycle.
+	MaxDispatchDepth int
+	// Recursive reports whether the relation depends on a cycle of relations, e.g. that of
+	// 'define member: [user, group#member]', in which case the depth of the dispatches depends on the tuples, up to
+	// the resolve node limit.
+	Recursive bool
+	// Branches are the rewrites of the relations the relation depends on, including its own, relation by relation
+	// in the order they are reached.
+	Branches []BranchEstimate
+}
+
+// BranchEstimate describes a rewrite defining a relation.
+type BranchEstimate struct {
+	// Relation is the relation defined by the rewrite.
+	Relation string
+	// Rewrite is the kind of rewrite, e.g. RewriteTupleToUserset.
+	Rewrite string
+	// Operation is the set operation the rewrite is an operand of, e.g. OperationIntersection, or empty if the
+	// rewrite defines the relation by itself.
+	Operation string
+	// Targets are the relations the rewrite dispatches to: the userset types of a direct rewrite, the relation of a
+	// computed userset, or the computed relation of a tuple to userset on every type of its tupleset.
+	Targets []string
+	// FanOut reports whether the rewrite dispatches once per tuple read, i.e. whether it is a direct rewrite
+	// allowing usersets or a tuple to userset, so that its cost grows with the number of tuples.
+	FanOut bool
+	// ReverseRead reports whether ListObjects reads the tuples of the rewrite by user, i.e. whether it is a direct
+	// rewrite or a tuple to userset.
+	ReverseRead bool
+}
+
+// EstimateQuery returns the estimated cost of resolving the relation of objectType with the model of typesys. It
+// returns an error if the model doesn't define the relation.
+func EstimateQuery(typesys *typesystem.TypeSystem, objectType, relation string) (*QueryEstimate, error) {
+	if _, err := typesys.GetRelation(objectType, relation); err != nil {
+		if errors.Is(err, typesystem.ErrObjectTypeUndefined) {
+			return nil, serverErrors.TypeNotFound(objectType)
+		}
+
+		if errors.Is(err, typesystem.ErrRelationUndefined) {
+			return nil, serverErrors.RelationNotFound(relation, objectType, nil)
+		}
+
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	e := &queryEstimator{
+		typesys:  typesys,
+		depths:   make(map[string]int),
+		visiting: make(map[string]bool),
+	}
+	ref := tuple.ToObjectRelationString(objectType, relation)
+	depth := e.estimate(objectType, relation)
+
+	return &QueryEstimate{
+		Relation:         ref,
+		MaxDispatchDepth: depth,
+		Recursive:        e.recursive,
+		Branches:         e.branches,
+	}, nil
+}
+
+// queryEstimator walks the relations a relation depends on, depth first.
+type queryEstimator struct {
+	typesys   *typesystem.TypeSystem
+	branches  []BranchEstimate
+	recursive bool
+
+	// depths are the dispatch depths of the relations already estimated.
+	depths map[string]int
+	// visiting are the relations being estimated, which are reached again through a cycle.
+	visiting map[string]bool
+}
+
+// estimate returns the dispatch depth of the relation of objectType, recording the branches of the relations it
+// depends on the first time they are reached.
+func (e *queryEstimator) estimate(objectType, relation string) int {
+	ref := tuple.ToObjectRelationString(objectType, relation)
+	if e.visiting[ref] {
+		e.recursive = true
+		return 0
+	}
+	if depth, ok := e.depths[ref]; ok {
+		return depth
+	}
+
+	rel, err := e.typesys.GetRelation(objectType, relation)
+	if err != nil {
+		// A tuple to userset may compute a relation some types of its tupleset don't define.
+		return 0
+	}
+
+	e.visiting[ref] = true
+	defer delete(e.visiting, ref)
+
+	// The branches of the relation are recorded before those of the relations they dispatch to.
+	first := len(e.branches)
+	e.collectBranches(objectType, relation, rel.GetRewrite(), "")
+	branches := e.branches[first:len(e.branches):len(e.branches)]
+
+	depth := 0
+	for _, branch := range branches {
+		for _, target := range branch.Targets {
+			targetObject, targetRelation := tuple.SplitObjectRelation(target)
+			depth = max(depth, 1+e.estimate(targetObject, targetRelation))
+		}
+	}
+
+	e.depths[ref] = depth
+	return depth
+}
+
+// collectBranches records the leaf rewrites of rewrite, a rewrite of the relation of objectType, which are operands
+// of operation.
+func (e *queryEstimator) collectBranches(objectType, relation string, rewrite *openfgav1.Userset, operation string) {
+	branch := BranchEstimate{
+		Relation:  tuple.ToObjectRelationString(objectType, relation),
+		Operation: operation,
+	}
+
+	switch rw := rewrite.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		branch.Rewrite = RewriteDirect
+		branch.ReverseRead = true
+		directlyRelated, _ := e.typesys.GetDirectlyRelatedUserTypes(objectType, relation)
+		for _, ref := range directlyRelated {
+			if ref.GetRelation() != "" {
+				branch.Targets = append(branch.Targets, tuple.ToObjectRelationString(ref.GetType(), ref.GetRelation()))
+				branch.FanOut = true
+			}
+		}
+	case *openfgav1.Userset_ComputedUserset:
+		branch.Rewrite = RewriteComputedUserset
+		branch.Targets = []string{tuple.ToObjectRelationString(objectType, rw.ComputedUserset.GetRelation())}
+	case *openfgav1.Userset_TupleToUserset:
+		branch.Rewrite = RewriteTupleToUserset
+		branch.FanOut = true
+		branch.ReverseRead = true
+		computedRelation := rw.TupleToUserset.GetComputedUserset().GetRelation()
+		tuplesetTypes, _ := e.typesys.GetDirectlyRelatedUserTypes(objectType, rw.TupleToUserset.GetTupleset().GetRelation())
+		for _, ref := range tuplesetTypes {
+			if _, err := e.typesys.GetRelation(ref.GetType(), computedRelation); err == nil {
+				branch.Targets = append(branch.Targets, tuple.ToObjectRelationString(ref.GetType(), computedRelation))
+			}
+		}
+	case *openfgav1.Userset_Union:
+		for _, child := range rw.Union.GetChild() {
+			e.collectBranches(objectType, relation, child, OperationUnion)
+		}
+		return
+	case *openfgav1.Userset_Intersection:
+		for _, child := range rw.Intersection.GetChild() {
+			e.collectBranches(objectType, relation, child, OperationIntersection)
+		}
+		return
+	case *openfgav1.Userset_Difference:
+		e.collectBranches(objectType, relation, rw.Difference.GetBase(), OperationExclusionBase)
+		e.collectBranches(objectType, relation, rw.Difference.GetSubtract(), OperationExclusionSubtract)
+		return
+	default:
+		return
+	}
+
+	e.branches = append(e.branches, branch)
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestEstimateQuery(t *testing.T) {
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user, group#member]
+		type folder
+			relations
+				define viewer: [user]
+		type doc
+			relations
+				define parent: [folder]
+				define blocked: [user]
+				define owner: [user]
+				define editor: owner
+				define viewer: ([user, group#member] or editor or viewer from parent) but not blocked`)
+	ts, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+
+	t.Run("direct_relation", func(t *testing.T) {
+		estimate, err := EstimateQuery(ts, "doc", "owner")
+		require.NoError(t, err)
+		require.Equal(t, &QueryEstimate{
+			Relation:         "doc#owner",
+			MaxDispatchDepth: 0,
+			Branches: []BranchEstimate{
+				{Relation: "doc#owner", Rewrite: RewriteDirect, ReverseRead: true},
+			},
+		}, estimate)
+	})
+
+	t.Run("computed_userset", func(t *testing.T) {
+		estimate, err := EstimateQuery(ts, "doc", "editor")
+		require.NoError(t, err)
+		require.Equal(t, 1, estimate.MaxDispatchDepth)
+		require.False(t, estimate.Recursive)
+		require.Equal(t, []BranchEstimate{
+			{Relation: "doc#editor", Rewrite: RewriteComputedUserset, Targets: []string{"doc#owner"}},
+			{Relation: "doc#owner", Rewrite: RewriteDirect, ReverseRead: true},
+		}, estimate.Branches)
+	})
+
+	t.Run("recursive_userset", func(t *testing.T) {
+		estimate, err := EstimateQuery(ts, "group", "member")
+		require.NoError(t, err)
+		require.True(t, estimate.Recursive)
+		require.Equal(t, []BranchEstimate{
+			{Relation: "group#member", Rewrite: RewriteDirect, Targets: []string{"group#member"}, FanOut: true, ReverseRead: true},
+		}, estimate.Branches)
+	})
+
+	t.Run("set_operations", func(t *testing.T) {
+		estimate, err := EstimateQuery(ts, "doc", "viewer")
+		require.NoError(t, err)
+		require.Equal(t, 2, estimate.MaxDispatchDepth)
+		require.True(t, estimate.Recursive)
+		require.Equal(t, []BranchEstimate{
+			{Relation: "doc#viewer", Rewrite: RewriteDirect, Operation: OperationUnion, Targets: []string{"group#member"}, FanOut: true, ReverseRead: true},
+			{Relation: "doc#viewer", Rewrite: RewriteComputedUserset, Operation: OperationUnion, Targets: []string{"doc#editor"}},
+			{Relation: "doc#viewer", Rewrite: RewriteTupleToUserset, Operation: OperationUnion, Targets: []string{"folder#viewer"}, FanOut: true, ReverseRead: true},
+			{Relation: "doc#viewer", Rewrite: RewriteComputedUserset, Operation: OperationExclusionSubtract, Targets: []string{"doc#blocked"}},
+			{Relation: "group#member", Rewrite: RewriteDirect, Targets: []string{"group#member"}, FanOut: true, ReverseRead: true},
+			{Relation: "doc#editor", Rewrite: RewriteComputedUserset, Targets: []string{"doc#owner"}},
+			{Relation: "doc#owner", Rewrite: RewriteDirect, ReverseRead: true},
+			{Relation: "folder#viewer", Rewrite: RewriteDirect, ReverseRead: true},
+			{Relation: "doc#blocked", Rewrite: RewriteDirect, ReverseRead: true},
+		}, estimate.Branches)
+	})
+
+	t.Run("undefined_relation", func(t *testing.T) {
+		_, err := EstimateQuery(ts, "doc", "undefined")
+		require.Equal(t, openfgav1.ErrorCode_relation_not_found, openfgav1.ErrorCode(status.Code(err)))
+
+		_, err = EstimateQuery(ts, "undefined", "viewer")
+		require.Equal(t, openfgav1.ErrorCode_type_not_found, openfgav1.ErrorCode(status.Code(err)))
+	})
+}
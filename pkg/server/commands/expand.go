@@ -7,9 +7,12 @@ import (
 	"slices"
 
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/internal/checkutil"
+	"github.com/openfga/openfga/internal/condition"
 	openfgaErrors "github.com/openfga/openfga/internal/errors"
 	"github.com/openfga/openfga/internal/validation"
 	"github.com/openfga/openfga/pkg/logger"
@@ -24,6 +27,7 @@ import (
 type ExpandQuery struct {
 	logger    logger.Logger
 	datastore storage.RelationshipTupleReader
+	context   *structpb.Struct
 }
 
 type ExpandQueryOption func(*ExpandQuery)
@@ -34,6 +38,15 @@ func WithExpandQueryLogger(l logger.Logger) ExpandQueryOption {
 	}
 }
 
+// WithExpandQueryContext sets the context the conditions of the tuples are evaluated with. When set, the tuples whose
+// condition is not met are left out of the tree. Otherwise conditional tuples are expanded regardless of their
+// condition.
+func WithExpandQueryContext(reqCtx *structpb.Struct) ExpandQueryOption {
+	return func(eq *ExpandQuery) {
+		eq.context = reqCtx
+	}
+}
+
 // NewExpandQuery creates a new ExpandQuery using the supplied backends for retrieving data.
 func NewExpandQuery(datastore storage.OpenFGADatastore, opts ...ExpandQueryOption) *ExpandQuery {
 	eq := &ExpandQuery{
@@ -101,6 +114,8 @@ func (q *ExpandQuery) Execute(ctx context.Context, req *openfgav1.ExpandRequest)
 
 	userset := rel.GetRewrite()
 
+	// Expand resolves one level of the tree: relation aliases are returned as computed usersets, like any other
+	// computed userset, rather than resolved with the typesystem.
 	root, err := q.resolveUserset(ctx, store, userset, tk, typesys, req.GetConsistency())
 	if err != nil {
 		return nil, err
@@ -142,6 +157,22 @@ func (q *ExpandQuery) resolveUserset(
 	}
 }
 
+// filterTuples filters out the tuples that are invalid for the model, and those whose condition is not met by the
+// context of the query if it has one.
+func (q *ExpandQuery) filterTuples(ctx context.Context, tupleIter storage.TupleIterator, typesys *typesystem.TypeSystem) storage.TupleKeyIterator {
+	filteredIter := storage.NewFilteredTupleKeyIterator(
+		storage.NewTupleKeyIteratorFromTupleIterator(tupleIter),
+		validation.FilterInvalidTuples(typesys),
+	)
+	if q.context == nil {
+		return filteredIter
+	}
+	return storage.NewConditionsFilteredTupleKeyIterator(
+		filteredIter,
+		checkutil.BuildTupleKeyConditionFilter(ctx, q.context, typesys),
+	)
+}
+
 // resolveThis resolves a DirectUserset into a leaf node containing a distinct set of users with that relation.
 func (q *ExpandQuery) resolveThis(ctx context.Context, store string, tk *openfgav1.TupleKey, typesys *typesystem.TypeSystem, consistency openfgav1.ConsistencyPreference) (*openfgav1.UsersetTree_Node, error) {
 	ctx, span := tracer.Start(ctx, "resolveThis")
@@ -157,10 +188,7 @@ func (q *ExpandQuery) resolveThis(ctx context.Context, store string, tk *openfga
 		return nil, serverErrors.HandleError("", err)
 	}
 
-	filteredIter := storage.NewFilteredTupleKeyIterator(
-		storage.NewTupleKeyIteratorFromTupleIterator(tupleIter),
-		validation.FilterInvalidTuples(typesys),
-	)
+	filteredIter := q.filterTuples(ctx, tupleIter, typesys)
 	defer filteredIter.Stop()
 
 	distinctUsers := make(map[string]bool)
@@ -170,6 +198,9 @@ func (q *ExpandQuery) resolveThis(ctx context.Context, store string, tk *openfga
 			if err == storage.ErrIteratorDone {
 				break
 			}
+			if errors.Is(err, condition.ErrEvaluationFailed) {
+				return nil, serverErrors.ConditionEvaluationError(err)
+			}
 			return nil, serverErrors.HandleError("", err)
 		}
 		distinctUsers[tk.GetUser()] = true
@@ -276,10 +307,7 @@ func (q *ExpandQuery) resolveTupleToUserset(
 		return nil, serverErrors.HandleError("", err)
 	}
 
-	filteredIter := storage.NewFilteredTupleKeyIterator(
-		storage.NewTupleKeyIteratorFromTupleIterator(tupleIter),
-		validation.FilterInvalidTuples(typesys),
-	)
+	filteredIter := q.filterTuples(ctx, tupleIter, typesys)
 	defer filteredIter.Stop()
 
 	var computed []*openfgav1.UsersetTree_Computed
@@ -290,6 +318,9 @@ func (q *ExpandQuery) resolveTupleToUserset(
 			if err == storage.ErrIteratorDone {
 				break
 			}
+			if errors.Is(err, condition.ErrEvaluationFailed) {
+				return nil, serverErrors.ConditionEvaluationError(err)
+			}
 			return nil, serverErrors.HandleError("", err)
 		}
 		user := tk.GetUser()
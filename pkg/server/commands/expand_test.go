@@ -193,6 +193,43 @@ func TestExpand(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "relation_alias_is_a_computed_userset",
+			model: testutils.MustTransformDSLToProtoWithID(`
+				model
+					schema 1.1
+				type user
+				type repo
+					relations
+						define admin: [user]
+						define writer: admin
+						define reader: writer`),
+			tuples: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("repo:openfga/foo", "admin", "user:jon"),
+			},
+			request: &openfgav1.ExpandRequest{
+				TupleKey: tuple.NewExpandRequestTupleKey(
+					"repo:openfga/foo",
+					"reader",
+				),
+			},
+			expected: &openfgav1.ExpandResponse{
+				Tree: &openfgav1.UsersetTree{
+					Root: &openfgav1.UsersetTree_Node{
+						Name: "repo:openfga/foo#reader",
+						Value: &openfgav1.UsersetTree_Node_Leaf{
+							Leaf: &openfgav1.UsersetTree_Leaf{
+								Value: &openfgav1.UsersetTree_Leaf_Computed{
+									Computed: &openfgav1.UsersetTree_Computed{
+										Userset: "repo:openfga/foo#writer",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "tuple_to_userset",
 			model: testutils.MustTransformDSLToProtoWithID(`
@@ -1130,3 +1167,65 @@ func TestExpand(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandWithContext(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user, user with in_region]
+
+		condition in_region(region: string, allowed: string) {
+			region == allowed
+		}`)
+	typesys, err := typesystem.NewAndValidate(ctx, model)
+	require.NoError(t, err)
+	ctx = typesystem.ContextWithTypesystem(ctx, typesys)
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKeyWithCondition("document:1", "viewer", "user:anne", "in_region", testutils.MustNewStruct(t, map[string]interface{}{"allowed": "eu"})),
+		tuple.NewTupleKeyWithCondition("document:1", "viewer", "user:bob", "in_region", testutils.MustNewStruct(t, map[string]interface{}{"allowed": "us"})),
+		tuple.NewTupleKey("document:1", "viewer", "user:carl"),
+	})
+	require.NoError(t, err)
+
+	req := &openfgav1.ExpandRequest{
+		StoreId:  storeID,
+		TupleKey: &openfgav1.ExpandRequestTupleKey{Object: "document:1", Relation: "viewer"},
+		ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKeyWithCondition("document:1", "viewer", "user:dan", "in_region", testutils.MustNewStruct(t, map[string]interface{}{"allowed": "eu"})),
+		}},
+	}
+	users := func(resp *openfgav1.ExpandResponse) []string {
+		return resp.GetTree().GetRoot().GetLeaf().GetUsers().GetUsers()
+	}
+
+	t.Run("without_context_conditions_are_ignored", func(t *testing.T) {
+		resp, err := NewExpandQuery(ds).Execute(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"user:anne", "user:bob", "user:carl", "user:dan"}, users(resp))
+	})
+
+	t.Run("with_context_unmet_conditions_are_left_out", func(t *testing.T) {
+		resp, err := NewExpandQuery(ds, WithExpandQueryContext(testutils.MustNewStruct(t, map[string]interface{}{"region": "eu"}))).
+			Execute(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"user:anne", "user:carl", "user:dan"}, users(resp))
+	})
+
+	t.Run("tuples_missing_context_parameters_are_left_out", func(t *testing.T) {
+		resp, err := NewExpandQuery(ds, WithExpandQueryContext(testutils.MustNewStruct(t, map[string]interface{}{}))).
+			Execute(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"user:carl"}, users(resp))
+	})
+}
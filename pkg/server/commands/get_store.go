@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/pkg/logger"
@@ -52,3 +55,31 @@ func (q *GetStoreQuery) Execute(ctx context.Context, req *openfgav1.GetStoreRequ
 		UpdatedAt: store.GetUpdatedAt(),
 	}, nil
 }
+
+// ExecuteByName returns the store named name. If storeIDs is not empty, the store must be one of them. It returns a
+// FailedPrecondition error if more than one store has this name.
+func (q *GetStoreQuery) ExecuteByName(ctx context.Context, name string, storeIDs []string) (*openfgav1.GetStoreResponse, error) {
+	stores, _, err := q.storesBackend.ListStores(ctx, storage.ListStoresOptions{
+		IDs:        storeIDs,
+		Name:       name,
+		Pagination: storage.NewPaginationOptions(2, ""),
+	})
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	switch len(stores) {
+	case 0:
+		return nil, serverErrors.ErrStoreIDNotFound
+	case 1:
+		store := stores[0]
+		return &openfgav1.GetStoreResponse{
+			Id:        store.GetId(),
+			Name:      store.GetName(),
+			CreatedAt: store.GetCreatedAt(),
+			UpdatedAt: store.GetUpdatedAt(),
+		}, nil
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "more than one store is named '%s'", name)
+	}
+}
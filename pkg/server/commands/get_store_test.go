@@ -82,3 +82,48 @@ func TestGetStore(t *testing.T) {
 		require.Nil(t, resp)
 	})
 }
+
+func TestGetStoreByName(t *testing.T) {
+	store := &openfgav1.Store{
+		Id:   ulid.Make().String(),
+		Name: "acme",
+	}
+
+	t.Run("succeeds", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ListStores(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, options storage.ListStoresOptions) ([]*openfgav1.Store, string, error) {
+				require.Equal(t, "acme", options.Name)
+				require.Equal(t, []string{store.GetId()}, options.IDs)
+				return []*openfgav1.Store{store}, "", nil
+			})
+
+		resp, err := NewGetStoreQuery(mockDatastore).ExecuteByName(context.Background(), "acme", []string{store.GetId()})
+		require.NoError(t, err)
+		require.Equal(t, store.GetId(), resp.GetId())
+		require.Equal(t, "acme", resp.GetName())
+	})
+
+	t.Run("no_match_returns_store_id_not_found", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ListStores(gomock.Any(), gomock.Any()).Times(1).Return(nil, "", nil)
+
+		_, err := NewGetStoreQuery(mockDatastore).ExecuteByName(context.Background(), "acme", nil)
+		require.ErrorIs(t, err, serverErrors.ErrStoreIDNotFound)
+	})
+
+	t.Run("ambiguous_name_fails", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ListStores(gomock.Any(), gomock.Any()).Times(1).
+			Return([]*openfgav1.Store{store, {Id: ulid.Make().String(), Name: "acme"}}, "", nil)
+
+		_, err := NewGetStoreQuery(mockDatastore).ExecuteByName(context.Background(), "acme", nil)
+		require.ErrorContains(t, err, "more than one store is named 'acme'")
+	})
+}
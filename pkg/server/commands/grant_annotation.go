@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/checkutil"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// GrantKind describes how a user was granted a relation on an object.
+type GrantKind string
+
+const (
+	// GrantDirect is a grant by a tuple of the relation relating the object to the user itself.
+	GrantDirect GrantKind = "direct"
+	// GrantWildcard is a grant by the public wildcard of the type of the user, which every user of the type has.
+	GrantWildcard GrantKind = "wildcard"
+	// GrantUserset is a grant through a userset, a computed relation or a tuple to userset.
+	GrantUserset GrantKind = "userset"
+)
+
+// GrantAnnotationParams are the parameters of the ListObjects or ListUsers request whose results are annotated.
+type GrantAnnotationParams struct {
+	StoreID          string
+	Relation         string
+	ContextualTuples []*openfgav1.TupleKey
+	Context          *structpb.Struct
+	Consistency      openfgav1.ConsistencyPreference
+}
+
+// GrantAnnotator classifies how the results of ListObjects and ListUsers were granted their relation. It costs a
+// datastore read, and possibly a Check, per result.
+type GrantAnnotator struct {
+	datastore  storage.RelationshipTupleReader
+	checkQuery *CheckQuery
+}
+
+// NewGrantAnnotator returns a GrantAnnotator reading the tuples of datastore and resolving wildcard grants with
+// checkQuery, which must use the typesystem of the annotated request.
+func NewGrantAnnotator(datastore storage.RelationshipTupleReader, checkQuery *CheckQuery) *GrantAnnotator {
+	return &GrantAnnotator{
+		datastore:  datastore,
+		checkQuery: checkQuery,
+	}
+}
+
+// Annotate returns how user, which has the relation of params on object, was granted it. A typed wildcard user is
+// always granted by the wildcard. Otherwise a direct tuple takes precedence over the public wildcard, which takes
+// precedence over usersets.
+func (a *GrantAnnotator) Annotate(ctx context.Context, params *GrantAnnotationParams, object, user string) (GrantKind, error) {
+	if tuple.IsTypedWildcard(user) {
+		return GrantWildcard, nil
+	}
+
+	ds := storagewrappers.NewCombinedTupleReader(a.datastore, params.ContextualTuples)
+	t, err := ds.ReadUserTuple(ctx, params.StoreID, tuple.NewTupleKey(object, params.Relation, user), storage.ReadUserTupleOptions{
+		Consistency: storage.ConsistencyOptions{Preference: params.Consistency},
+	})
+	switch {
+	case err == nil:
+		met, err := checkutil.BuildTupleKeyConditionFilter(ctx, params.Context, a.checkQuery.typesys)(t.GetKey())
+		if err == nil && met {
+			return GrantDirect, nil
+		}
+	case !errors.Is(err, storage.ErrNotFound):
+		return "", err
+	}
+
+	if _, relation := tuple.SplitObjectRelation(user); relation == "" {
+		resp, _, err := a.checkQuery.Execute(ctx, &CheckCommandParams{
+			StoreID:          params.StoreID,
+			TupleKey:         tuple.NewCheckRequestTupleKey(object, params.Relation, tuple.TypedPublicWildcard(tuple.GetType(user))),
+			ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: params.ContextualTuples},
+			Context:          params.Context,
+			Consistency:      params.Consistency,
+		})
+		var (
+			invalidTupleError    *InvalidTupleError
+			invalidRelationError *InvalidRelationError
+		)
+		switch {
+		case errors.As(err, &invalidTupleError), errors.As(err, &invalidRelationError):
+			// The wildcard of the type of the user is not allowed by the model.
+		case err != nil:
+			return "", err
+		case resp.GetAllowed():
+			return GrantWildcard, nil
+		}
+	}
+
+	return GrantUserset, nil
+}
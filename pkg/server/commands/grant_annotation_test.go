@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestGrantAnnotator(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user]
+		type doc
+			relations
+				define viewer: [user, user:*, group#member]
+				define owner: [user]`)
+	ts, err := typesystem.NewAndValidate(ctx, model)
+	require.NoError(t, err)
+
+	storeID := ulid.Make().String()
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("doc:2", "viewer", "user:*"),
+		tuple.NewTupleKey("doc:3", "viewer", "group:eng#member"),
+		tuple.NewTupleKey("group:eng", "member", "user:carl"),
+	})
+	require.NoError(t, err)
+
+	checkResolver, checkResolverCloser, err := graph.NewOrderedCheckResolvers().Build()
+	require.NoError(t, err)
+	t.Cleanup(checkResolverCloser)
+
+	annotator := NewGrantAnnotator(ds, NewCheckCommand(ds, checkResolver, ts))
+	params := &GrantAnnotationParams{
+		StoreID:  storeID,
+		Relation: "viewer",
+		ContextualTuples: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("doc:4", "viewer", "user:dave"),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		object   string
+		user     string
+		expected GrantKind
+	}{
+		{name: "direct_tuple", object: "doc:1", user: "user:anne", expected: GrantDirect},
+		{name: "direct_contextual_tuple", object: "doc:4", user: "user:dave", expected: GrantDirect},
+		{name: "public_wildcard", object: "doc:2", user: "user:bob", expected: GrantWildcard},
+		{name: "typed_wildcard_user", object: "doc:2", user: "user:*", expected: GrantWildcard},
+		{name: "userset", object: "doc:3", user: "user:carl", expected: GrantUserset},
+		{name: "direct_userset_user", object: "doc:3", user: "group:eng#member", expected: GrantDirect},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kind, err := annotator.Annotate(ctx, params, test.object, test.user)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, kind)
+		})
+	}
+
+	t.Run("wildcard_not_allowed_by_the_model", func(t *testing.T) {
+		kind, err := annotator.Annotate(ctx, &GrantAnnotationParams{
+			StoreID:  storeID,
+			Relation: "owner",
+		}, "doc:1", "user:anne")
+		require.NoError(t, err)
+		require.Equal(t, GrantUserset, kind)
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -36,7 +37,23 @@ import (
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
-const streamedBufferSize = 100
+const (
+	streamedBufferSize = 100
+
+	// defaultStreamedProgressResultsInterval and defaultStreamedProgressQueriesInterval are how many results and
+	// datastore reads a StreamedListObjects request goes through between two progress span events.
+	defaultStreamedProgressResultsInterval = 100
+	defaultStreamedProgressQueriesInterval = 1000
+
+	// streamedProgressCheckInterval is how often the datastore reads are checked while no result is sent.
+	streamedProgressCheckInterval = time.Second
+
+	streamedProgressEventName = "streamed_list_objects_progress"
+
+	// datastoreQueryBudgetCheckInterval is how often the datastore reads are compared to the budget while no check
+	// completes.
+	datastoreQueryBudgetCheckInterval = 10 * time.Millisecond
+)
 
 var (
 	furtherEvalRequiredCounter = promauto.NewCounter(prometheus.CounterOpts{
@@ -61,14 +78,53 @@ type ListObjectsQuery struct {
 	resolveNodeBreadthLimit uint32
 	maxConcurrentReads      uint32
 
+	// checkMaxResolutionDepth overrides the maximum resolution depth of the check resolver. 0 keeps it.
+	checkMaxResolutionDepth uint32
+
 	dispatchThrottlerConfig threshold.Config
 
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	saturationMonitor          *storagewrappers.LimiterSaturationMonitor
 
 	checkResolver            graph.CheckResolver
 	cacheSettings            serverconfig.CacheSettings
 	sharedDatastoreResources *shared.SharedDatastoreResources
+
+	streamedProgressResultsInterval uint32
+	streamedProgressQueriesInterval uint32
+
+	// datastoreQueryBudget is the number of datastore reads after which the resolution stops. 0 does not limit it.
+	datastoreQueryBudget uint32
+}
+
+// TruncationReason tells why the results of a ListObjects request may be partial.
+type TruncationReason int32
+
+const (
+	// NotTruncated is the reason of the requests whose resolution completed.
+	NotTruncated TruncationReason = iota
+	// TruncatedByDeadline is the reason of the requests whose resolution was stopped by the ListObjects deadline.
+	TruncatedByDeadline
+	// TruncatedByDatastoreQueryBudget is the reason of the requests whose resolution was stopped once it had spent
+	// the datastore query budget.
+	TruncatedByDatastoreQueryBudget
+	// TruncatedByMaxResults is the reason of the requests whose resolution was stopped once it had found the
+	// maximum number of results, while more candidate objects were left.
+	TruncatedByMaxResults
+)
+
+func (r TruncationReason) String() string {
+	switch r {
+	case TruncatedByDeadline:
+		return "deadline"
+	case TruncatedByDatastoreQueryBudget:
+		return "datastore_query_budget"
+	case TruncatedByMaxResults:
+		return "max_results"
+	default:
+		return ""
+	}
 }
 
 type ListObjectsResolutionMetadata struct {
@@ -80,14 +136,55 @@ type ListObjectsResolutionMetadata struct {
 
 	// WasThrottled indicates whether the request was throttled
 	WasThrottled *atomic.Bool
+
+	// The total number of Check sub-problems served from the check cache
+	CheckCacheHits *atomic.Uint32
+
+	// truncationReason is the TruncationReason of the first cause the resolution was stopped for.
+	truncationReason *atomic.Int32
+
+	// reverseExpandStorage is the storage of the reverse expansion while it is in progress. Its reads are added
+	// to DatastoreQueryCount once it is done.
+	reverseExpandStorage *atomic.Pointer[storagewrappers.RequestStorageWrapper]
 }
 
 func NewListObjectsResolutionMetadata() *ListObjectsResolutionMetadata {
 	return &ListObjectsResolutionMetadata{
-		DatastoreQueryCount: new(atomic.Uint32),
-		DispatchCounter:     new(atomic.Uint32),
-		WasThrottled:        new(atomic.Bool),
+		DatastoreQueryCount:  new(atomic.Uint32),
+		DispatchCounter:      new(atomic.Uint32),
+		WasThrottled:         new(atomic.Bool),
+		CheckCacheHits:       new(atomic.Uint32),
+		truncationReason:     new(atomic.Int32),
+		reverseExpandStorage: new(atomic.Pointer[storagewrappers.RequestStorageWrapper]),
+	}
+}
+
+// TruncationReason returns why the results may be partial, or NotTruncated.
+func (m *ListObjectsResolutionMetadata) TruncationReason() TruncationReason {
+	if m.truncationReason == nil {
+		return NotTruncated
 	}
+	return TruncationReason(m.truncationReason.Load())
+}
+
+// truncate records the reason the resolution was stopped for, unless it was stopped for another one before.
+func (m *ListObjectsResolutionMetadata) truncate(reason TruncationReason) {
+	if m.truncationReason != nil {
+		m.truncationReason.CompareAndSwap(int32(NotTruncated), int32(reason))
+	}
+}
+
+// currentDatastoreQueryCount returns the number of datastore reads so far, including those of a reverse
+// expansion still in progress.
+func (m *ListObjectsResolutionMetadata) currentDatastoreQueryCount() uint32 {
+	count := m.DatastoreQueryCount.Load()
+	if m.reverseExpandStorage == nil {
+		return count
+	}
+	if ds := m.reverseExpandStorage.Load(); ds != nil {
+		count += ds.GetMetadata().DatastoreQueryCount
+	}
+	return count
 }
 
 type ListObjectsResponse struct {
@@ -122,6 +219,13 @@ func WithResolveNodeLimit(limit uint32) ListObjectsQueryOption {
 	}
 }
 
+// WithListObjectsCheckMaxResolutionDepth overrides the maximum resolution depth of the checks of the candidate objects.
+func WithListObjectsCheckMaxResolutionDepth(depth uint32) ListObjectsQueryOption {
+	return func(d *ListObjectsQuery) {
+		d.checkMaxResolutionDepth = depth
+	}
+}
+
 // WithResolveNodeBreadthLimit see server.WithResolveNodeBreadthLimit.
 func WithResolveNodeBreadthLimit(limit uint32) ListObjectsQueryOption {
 	return func(d *ListObjectsQuery) {
@@ -156,6 +260,30 @@ func WithListObjectsDatastoreThrottler(threshold int, duration time.Duration) Li
 	}
 }
 
+// WithStreamedListObjectsProgressInterval sets how many results and datastore reads a StreamedListObjects request
+// goes through between two progress span events. 0 disables the corresponding trigger.
+func WithStreamedListObjectsProgressInterval(results, queries uint32) ListObjectsQueryOption {
+	return func(d *ListObjectsQuery) {
+		d.streamedProgressResultsInterval = results
+		d.streamedProgressQueriesInterval = queries
+	}
+}
+
+// WithListObjectsDatastoreQueryBudget stops the resolution once it has read the datastore budget times, the results
+// found so far being returned as truncated by TruncatedByDatastoreQueryBudget. 0 does not limit the reads.
+func WithListObjectsDatastoreQueryBudget(budget uint32) ListObjectsQueryOption {
+	return func(d *ListObjectsQuery) {
+		d.datastoreQueryBudget = budget
+	}
+}
+
+// WithListObjectsLimiterSaturationMonitor sets the monitor that observes the wait times of the datastore concurrency limiter.
+func WithListObjectsLimiterSaturationMonitor(m *storagewrappers.LimiterSaturationMonitor) ListObjectsQueryOption {
+	return func(d *ListObjectsQuery) {
+		d.saturationMonitor = m
+	}
+}
+
 func NewListObjectsQuery(
 	ds storage.RelationshipTupleReader,
 	checkResolver graph.CheckResolver,
@@ -188,6 +316,8 @@ func NewListObjectsQuery(
 		sharedDatastoreResources: &shared.SharedDatastoreResources{
 			CacheController: cachecontroller.NewNoopCacheController(),
 		},
+		streamedProgressResultsInterval: defaultStreamedProgressResultsInterval,
+		streamedProgressQueriesInterval: defaultStreamedProgressQueriesInterval,
 	}
 
 	for _, opt := range opts {
@@ -262,6 +392,12 @@ func (q *ListObjectsQuery) evaluate(
 		return serverErrors.HandleError("", err)
 	}
 
+	// aliases are expanded from the relation they alias, unless the user is a userset of the alias itself, which
+	// defines the objects it is a userset of
+	if userObj, userRel := tuple.SplitObjectRelation(req.GetUser()); tuple.GetType(userObj) != targetObjectType || userRel != targetRelation {
+		targetRelation = typesys.ResolveRelationAlias(targetObjectType, targetRelation)
+	}
+
 	if err := validation.ValidateUser(typesys, req.GetUser()); err != nil {
 		return serverErrors.ValidationError(fmt.Errorf("invalid 'user' value: %s", err))
 	}
@@ -302,10 +438,14 @@ func (q *ListObjectsQuery) evaluate(
 				Concurrency:       q.maxConcurrentReads,
 				ThrottleThreshold: q.datastoreThrottleThreshold,
 				ThrottleDuration:  q.datastoreThrottleDuration,
+				SaturationMonitor: q.saturationMonitor,
 			},
 			q.sharedDatastoreResources,
 			q.cacheSettings,
 		)
+		if resolutionMetadata.reverseExpandStorage != nil {
+			resolutionMetadata.reverseExpandStorage.Store(ds)
+		}
 
 		reverseExpandQuery := reverseexpand.NewReverseExpandQuery(
 			ds,
@@ -321,6 +461,23 @@ func (q *ListObjectsQuery) evaluate(
 		defer cancel()
 		pool := concurrency.NewPool(cancelCtx, int(1+q.resolveNodeBreadthLimit))
 
+		// the reads of the reverse expansion and of the checks are compared to the budget periodically, and
+		// whenever a check completes.
+		var budgetCheck <-chan time.Time
+		if q.datastoreQueryBudget > 0 {
+			ticker := time.NewTicker(datastoreQueryBudgetCheckInterval)
+			defer ticker.Stop()
+			budgetCheck = ticker.C
+		}
+		budgetSpent := func() bool {
+			if q.datastoreQueryBudget == 0 || resolutionMetadata.currentDatastoreQueryCount() < q.datastoreQueryBudget {
+				return false
+			}
+			resolutionMetadata.truncate(TruncatedByDatastoreQueryBudget)
+			cancel()
+			return true
+		}
+
 		pool.Go(func(ctx context.Context) error {
 			reverseExpandResolutionMetadata := reverseexpand.NewResolutionMetadata()
 			err := reverseExpandQuery.Execute(ctx, &reverseexpand.ReverseExpandRequest{
@@ -350,8 +507,15 @@ func (q *ListObjectsQuery) evaluate(
 				cancel() // cancel any inflight work if e.g. model too complex
 				break ConsumerReadLoop
 			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					resolutionMetadata.truncate(TruncatedByDeadline)
+				}
 				cancel() // cancel any inflight work if e.g. deadline exceeded
 				break ConsumerReadLoop
+			case <-budgetCheck:
+				if budgetSpent() {
+					break ConsumerReadLoop
+				}
 			case res, channelOpen := <-reverseExpandResultsChan:
 				if !channelOpen {
 					// don't cancel here. Reverse Expand has finished finding candidate object IDs
@@ -363,6 +527,7 @@ func (q *ListObjectsQuery) evaluate(
 				}
 
 				if (maxResults != 0) && objectsFound.Load() >= maxResults {
+					resolutionMetadata.truncate(TruncatedByMaxResults)
 					cancel() // cancel any inflight work if we already found enough results
 					break ConsumerReadLoop
 				}
@@ -380,6 +545,8 @@ func (q *ListObjectsQuery) evaluate(
 						WithCheckCommandLogger(q.logger),
 						WithCheckCommandMaxConcurrentReads(q.maxConcurrentReads),
 						WithCheckDatastoreThrottler(q.datastoreThrottleThreshold, q.datastoreThrottleDuration),
+						WithCheckLimiterSaturationMonitor(q.saturationMonitor),
+						WithCheckCommandMaxResolutionDepth(q.checkMaxResolutionDepth),
 					).
 						Execute(ctx, &CheckCommandParams{
 							StoreID:          req.GetStoreId(),
@@ -393,12 +560,14 @@ func (q *ListObjectsQuery) evaluate(
 					}
 					resolutionMetadata.DatastoreQueryCount.Add(resp.GetResolutionMetadata().DatastoreQueryCount)
 					resolutionMetadata.DispatchCounter.Add(checkRequestMetadata.DispatchCounter.Load())
+					resolutionMetadata.CheckCacheHits.Add(checkRequestMetadata.CheckCacheHits.Load())
 					if !resolutionMetadata.WasThrottled.Load() && checkRequestMetadata.WasThrottled.Load() {
 						resolutionMetadata.WasThrottled.Store(true)
 					}
 					if resp.Allowed {
 						trySendObject(ctx, res.Object, &objectsFound, maxResults, resultsChan)
 					}
+					budgetSpent()
 					return nil
 				})
 			}
@@ -409,9 +578,14 @@ func (q *ListObjectsQuery) evaluate(
 			if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
 				resultsChan <- ListObjectsResult{Err: err}
 			}
-			// TODO set header to indicate "deadline exceeded"
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				resolutionMetadata.truncate(TruncatedByDeadline)
+			}
 		}
 		close(resultsChan)
+		if resolutionMetadata.reverseExpandStorage != nil {
+			resolutionMetadata.reverseExpandStorage.Store(nil)
+		}
 		dsMeta := ds.GetMetadata()
 		resolutionMetadata.DatastoreQueryCount.Add(dsMeta.DatastoreQueryCount)
 		resolutionMetadata.WasThrottled.CompareAndSwap(false, dsMeta.WasThrottled)
@@ -497,7 +671,10 @@ func (q *ListObjectsQuery) Execute(
 // ExecuteStreamed executes the ListObjectsQuery, returning a stream of object IDs.
 // It ignores the value of q.listObjectsMaxResults and returns all available results
 // until q.listObjectsDeadline is hit.
-func (q *ListObjectsQuery) ExecuteStreamed(ctx context.Context, req *openfgav1.StreamedListObjectsRequest, srv openfgav1.OpenFGAService_StreamedListObjectsServer) (*ListObjectsResolutionMetadata, error) {
+//
+// While the results are sent, span events record the number of results sent, datastore reads and dispatches so
+// far, so that the telemetry of a stream that is cancelled or fails tells how far the resolution got.
+func (q *ListObjectsQuery) ExecuteStreamed(ctx context.Context, req *openfgav1.StreamedListObjectsRequest, srv openfgav1.OpenFGAService_StreamedListObjectsServer) (_ *ListObjectsResolutionMetadata, err error) {
 	maxResults := uint32(math.MaxUint32)
 	// make a buffered channel so that writer goroutines aren't blocked when attempting to send a result
 	resultsChan := make(chan ListObjectsResult, streamedBufferSize)
@@ -511,12 +688,42 @@ func (q *ListObjectsQuery) ExecuteStreamed(ctx context.Context, req *openfgav1.S
 
 	resolutionMetadata := NewListObjectsResolutionMetadata()
 
-	err := q.evaluate(timeoutCtx, req, resultsChan, maxResults, resolutionMetadata)
+	err = q.evaluate(timeoutCtx, req, resultsChan, maxResults, resolutionMetadata)
 	if err != nil {
 		return nil, err
 	}
 
-	for result := range resultsChan {
+	progress := &streamedProgress{
+		span:            trace.SpanFromContext(ctx),
+		start:           time.Now(),
+		resultsInterval: q.streamedProgressResultsInterval,
+		queriesInterval: q.streamedProgressQueriesInterval,
+		metadata:        resolutionMetadata,
+	}
+	defer func() {
+		if err != nil {
+			progress.record(true)
+		}
+	}()
+
+	ticker := time.NewTicker(streamedProgressCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		var (
+			result ListObjectsResult
+			ok     bool
+		)
+		select {
+		case <-ticker.C:
+			progress.maybeRecord()
+			continue
+		case result, ok = <-resultsChan:
+		}
+		if !ok {
+			break
+		}
+
 		if result.Err != nil {
 			if errors.Is(result.Err, graph.ErrResolutionDepthExceeded) {
 				return nil, serverErrors.ErrAuthorizationModelResolutionTooComplex
@@ -534,7 +741,47 @@ func (q *ListObjectsQuery) ExecuteStreamed(ctx context.Context, req *openfgav1.S
 		}); err != nil {
 			return nil, serverErrors.HandleError("", err)
 		}
+
+		progress.resultsSent++
+		progress.maybeRecord()
 	}
 
 	return resolutionMetadata, nil
 }
+
+// streamedProgress records the progress of a StreamedListObjects request as span events.
+type streamedProgress struct {
+	span            trace.Span
+	start           time.Time
+	resultsInterval uint32
+	queriesInterval uint32
+	metadata        *ListObjectsResolutionMetadata
+
+	resultsSent uint32
+	// lastResults and lastQueries are the results sent and datastore reads when the last event was recorded.
+	lastResults uint32
+	lastQueries uint32
+}
+
+// maybeRecord records an event if enough results were sent, or datastore reads made, since the last one.
+func (p *streamedProgress) maybeRecord() {
+	queries := p.metadata.currentDatastoreQueryCount()
+	if (p.resultsInterval > 0 && p.resultsSent-p.lastResults >= p.resultsInterval) ||
+		(p.queriesInterval > 0 && queries-p.lastQueries >= p.queriesInterval) {
+		p.record(false)
+	}
+}
+
+// record records an event. final is true for the event recorded when the stream ends with an error.
+func (p *streamedProgress) record(final bool) {
+	queries := p.metadata.currentDatastoreQueryCount()
+	p.lastResults, p.lastQueries = p.resultsSent, queries
+
+	p.span.AddEvent(streamedProgressEventName, trace.WithAttributes(
+		attribute.Int64("results_sent", int64(p.resultsSent)),
+		attribute.Int64("datastore_query_count", int64(queries)),
+		attribute.Int64("dispatch_count", int64(p.metadata.DispatchCounter.Load())),
+		attribute.Int64("elapsed_ms", time.Since(p.start).Milliseconds()),
+		attribute.Bool("final", final),
+	))
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/goleak"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/sync/singleflight"
@@ -226,7 +229,7 @@ func TestListObjectsDispatchCount(t *testing.T) {
 			objectType:              "document",
 			relation:                "viewer",
 			user:                    "user:jon",
-			expectedDispatchCount:   2,
+			expectedDispatchCount:   1, // viewer is an alias of editor and is resolved without a dispatch
 			expectedThrottlingValue: 0,
 		},
 	}
@@ -280,6 +283,80 @@ func TestListObjectsDispatchCount(t *testing.T) {
 	}
 }
 
+func TestListObjectsTruncationReason(t *testing.T) {
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+
+		type user
+
+		type folder
+			relations
+				define editor: [user]
+				define viewer: [user]
+				define can_delete: [user] and editor`, []string{
+		"folder:A#viewer@user:jon",
+		"folder:B#viewer@user:jon",
+		"folder:C#viewer@user:jon",
+		"folder:A#can_delete@user:jon",
+		"folder:A#editor@user:jon",
+		"folder:B#can_delete@user:jon",
+		"folder:B#editor@user:jon",
+	})
+	ts, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+	ctx := typesystem.ContextWithTypesystem(storage.ContextWithRelationshipTupleReader(context.Background(), ds), ts)
+
+	checker, checkResolverCloser, err := graph.NewOrderedCheckResolvers().Build()
+	require.NoError(t, err)
+	t.Cleanup(checkResolverCloser)
+
+	tests := map[string]struct {
+		relation         string
+		opts             []ListObjectsQueryOption
+		expectedReason   TruncationReason
+		expectedMaxCount int
+	}{
+		`not_truncated`: {
+			relation:         "viewer",
+			expectedReason:   NotTruncated,
+			expectedMaxCount: 3,
+		},
+		`max_results`: {
+			relation:         "viewer",
+			opts:             []ListObjectsQueryOption{WithListObjectsMaxResults(1)},
+			expectedReason:   TruncatedByMaxResults,
+			expectedMaxCount: 1,
+		},
+		`datastore_query_budget`: {
+			relation:         "can_delete",
+			opts:             []ListObjectsQueryOption{WithListObjectsDatastoreQueryBudget(1)},
+			expectedReason:   TruncatedByDatastoreQueryBudget,
+			expectedMaxCount: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			q, err := NewListObjectsQuery(ds, checker, test.opts...)
+			require.NoError(t, err)
+
+			resp, err := q.Execute(ctx, &openfgav1.ListObjectsRequest{
+				StoreId:  storeID,
+				Type:     "folder",
+				Relation: test.relation,
+				User:     "user:jon",
+			})
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(resp.Objects), test.expectedMaxCount)
+			require.Equal(t, test.expectedReason, resp.ResolutionMetadata.TruncationReason())
+		})
+	}
+}
+
 func TestDoesNotUseCacheWhenHigherConsistencyEnabled(t *testing.T) {
 	ds := memory.New()
 	t.Cleanup(ds.Close)
@@ -521,3 +598,55 @@ func TestAttemptsToInvalidateWhenIteratorCacheIsEnabled(t *testing.T) {
 	sharedResources.Close()
 	require.NoError(t, err)
 }
+
+func TestStreamedProgress(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := tp.Tracer("test").Start(context.Background(), "StreamedListObjects")
+
+	metadata := NewListObjectsResolutionMetadata()
+	progress := &streamedProgress{
+		span:            span,
+		start:           time.Now(),
+		resultsInterval: 2,
+		queriesInterval: 10,
+		metadata:        metadata,
+	}
+
+	progress.resultsSent++
+	progress.maybeRecord()
+	progress.resultsSent++
+	progress.maybeRecord() // 2 results sent
+
+	metadata.DatastoreQueryCount.Add(9)
+	progress.maybeRecord()
+	metadata.DatastoreQueryCount.Add(1)
+	progress.maybeRecord() // 10 reads made
+
+	metadata.DispatchCounter.Add(3)
+	progress.record(true)
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 3)
+
+	attributes := func(event sdktrace.Event) map[string]attribute.Value {
+		values := map[string]attribute.Value{}
+		for _, kv := range event.Attributes {
+			values[string(kv.Key)] = kv.Value
+		}
+		return values
+	}
+
+	for _, event := range events {
+		require.Equal(t, streamedProgressEventName, event.Name)
+	}
+	require.Equal(t, int64(2), attributes(events[0])["results_sent"].AsInt64())
+	require.Equal(t, int64(0), attributes(events[0])["datastore_query_count"].AsInt64())
+	require.Equal(t, int64(10), attributes(events[1])["datastore_query_count"].AsInt64())
+	require.False(t, attributes(events[1])["final"].AsBool())
+	require.Equal(t, int64(3), attributes(events[2])["dispatch_count"].AsInt64())
+	require.True(t, attributes(events[2])["final"].AsBool())
+}
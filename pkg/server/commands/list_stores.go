@@ -15,6 +15,7 @@ type ListStoresQuery struct {
 	storesBackend storage.StoresBackend
 	logger        logger.Logger
 	encoder       encoder.Encoder
+	labels        map[string]string
 }
 
 type ListStoresQueryOption func(*ListStoresQuery)
@@ -31,6 +32,13 @@ func WithListStoresQueryEncoder(e encoder.Encoder) ListStoresQueryOption {
 	}
 }
 
+// WithListStoresQueryLabels restricts the stores listed to those holding every label of labels.
+func WithListStoresQueryLabels(labels map[string]string) ListStoresQueryOption {
+	return func(q *ListStoresQuery) {
+		q.labels = labels
+	}
+}
+
 func NewListStoresQuery(storesBackend storage.StoresBackend, opts ...ListStoresQueryOption) *ListStoresQuery {
 	q := &ListStoresQuery{
 		storesBackend: storesBackend,
@@ -47,12 +55,13 @@ func NewListStoresQuery(storesBackend storage.StoresBackend, opts ...ListStoresQ
 func (q *ListStoresQuery) Execute(ctx context.Context, req *openfgav1.ListStoresRequest, storeIDs []string) (*openfgav1.ListStoresResponse, error) {
 	decodedContToken, err := q.encoder.Decode(req.GetContinuationToken())
 	if err != nil {
-		return nil, serverErrors.ErrInvalidContinuationToken
+		return nil, serverErrors.ContinuationTokenError(err)
 	}
 
 	opts := storage.ListStoresOptions{
 		IDs:        storeIDs,
 		Name:       req.GetName(),
+		Labels:     q.labels,
 		Pagination: storage.NewPaginationOptions(req.GetPageSize().GetValue(), string(decodedContToken)),
 	}
 	stores, continuationToken, err := q.storesBackend.ListStores(ctx, opts)
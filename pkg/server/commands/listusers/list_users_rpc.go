@@ -51,6 +51,7 @@ type listUsersQuery struct {
 	expandDirectDispatch       expandDirectDispatchHandler
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	saturationMonitor          *storagewrappers.LimiterSaturationMonitor
 }
 
 type expandResponse struct {
@@ -137,6 +138,13 @@ func WithListUsersDatastoreThrottler(threshold int, duration time.Duration) List
 	}
 }
 
+// WithListUsersLimiterSaturationMonitor sets the monitor that observes the wait times of the datastore concurrency limiter.
+func WithListUsersLimiterSaturationMonitor(m *storagewrappers.LimiterSaturationMonitor) ListUsersQueryOption {
+	return func(d *listUsersQuery) {
+		d.saturationMonitor = m
+	}
+}
+
 func (l *listUsersQuery) throttle(ctx context.Context, currentNumDispatch uint32) {
 	span := trace.SpanFromContext(ctx)
 
@@ -181,8 +189,9 @@ func NewListUsersQuery(ds storage.RelationshipTupleReader, contextualTuples []*o
 	}
 
 	l.datastore = storagewrappers.NewRequestStorageWrapper(ds, contextualTuples, &storagewrappers.Operation{
-		Method:      apimethod.ListUsers,
-		Concurrency: l.maxConcurrentReads,
+		Method:            apimethod.ListUsers,
+		Concurrency:       l.maxConcurrentReads,
+		SaturationMonitor: l.saturationMonitor,
 	})
 
 	return l
@@ -255,6 +264,11 @@ func (l *listUsersQuery) ListUsers(
 
 	go func() {
 		internalRequest := fromListUsersRequest(req, &dispatchCount)
+		// aliases are expanded from the relation they alias, unless the users are usersets of the alias itself,
+		// which is a userset of the object
+		if userFilter.GetType() != req.GetObject().GetType() || userFilter.GetRelation() != req.GetRelation() {
+			internalRequest.Relation = typesys.ResolveRelationAlias(req.GetObject().GetType(), req.GetRelation())
+		}
 		resp := l.expand(cancellableCtx, internalRequest, foundUsersCh)
 		if resp.err != nil {
 			expandErrCh <- resp.err
@@ -246,6 +246,82 @@ func TestListUsersComputedRelationship(t *testing.T) {
 			tuples:        []*openfgav1.TupleKey{},
 			expectedUsers: []string{"user:will", "user:maria"},
 		},
+		{
+			name: "computed_relationship_through_aliases",
+			req: &openfgav1.ListUsersRequest{
+				Object:   &openfgav1.Object{Type: "document", Id: "1"},
+				Relation: "viewer",
+				UserFilters: []*openfgav1.UserTypeFilter{
+					{
+						Type: "user",
+					},
+				},
+			},
+			model: `
+				model
+					schema 1.1
+				type user
+				type document
+					relations
+						define owner: [user]
+						define editor: owner
+						define viewer: editor`,
+			tuples: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("document:1", "owner", "user:will"),
+				tuple.NewTupleKey("document:2", "owner", "user:jon"),
+			},
+			expectedUsers: []string{"user:will"},
+		},
+		{
+			name: "computed_relationship_alias_userset_of_the_alias",
+			req: &openfgav1.ListUsersRequest{
+				Object:   &openfgav1.Object{Type: "document", Id: "1"},
+				Relation: "viewer",
+				UserFilters: []*openfgav1.UserTypeFilter{
+					{
+						Type:     "document",
+						Relation: "viewer",
+					},
+				},
+			},
+			model: `
+				model
+					schema 1.1
+				type user
+				type document
+					relations
+						define owner: [user]
+						define viewer: owner`,
+			tuples: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("document:1", "owner", "user:will"),
+			},
+			expectedUsers: []string{"document:1#viewer"},
+		},
+		{
+			name: "computed_relationship_alias_userset_of_the_aliased_relation",
+			req: &openfgav1.ListUsersRequest{
+				Object:   &openfgav1.Object{Type: "document", Id: "1"},
+				Relation: "viewer",
+				UserFilters: []*openfgav1.UserTypeFilter{
+					{
+						Type:     "document",
+						Relation: "owner",
+					},
+				},
+			},
+			model: `
+				model
+					schema 1.1
+				type user
+				type document
+					relations
+						define owner: [user]
+						define viewer: owner`,
+			tuples: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("document:1", "owner", "user:will"),
+			},
+			expectedUsers: []string{"document:1#owner"},
+		},
 	}
 	tests.runListUsersTestCases(t)
 }
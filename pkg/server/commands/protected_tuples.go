@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+// ForceDeleteHeader is the gRPC metadata key that must be set to "true" to delete a protected tuple.
+// HTTP clients send it as the "Grpc-Metadata-Openfga-Force-Delete" header.
+const ForceDeleteHeader = "openfga-force-delete"
+
+// ProtectedTuples holds the tuples that are under legal hold. Deleting one of them requires the
+// ForceDeleteHeader and a privileged principal.
+type ProtectedTuples struct {
+	patterns             []protectedTuplePattern
+	privilegedPrincipals map[string]struct{}
+}
+
+// protectedTuplePattern matches tuples on their object, and optionally their relation and user.
+// An empty field matches any value, and an object id of '*' matches any object of the type.
+type protectedTuplePattern struct {
+	objectType string
+	objectID   string
+	relation   string
+	user       string
+}
+
+// NewProtectedTuples parses the given patterns, which have the form 'type:id[#relation[@user]]'.
+// The object id may be '*' to protect every object of the type. Protected tuples can only be deleted
+// by one of privilegedPrincipals, matched against the subject or client id of the request credentials.
+func NewProtectedTuples(patterns []string, privilegedPrincipals []string) (*ProtectedTuples, error) {
+	p := &ProtectedTuples{
+		privilegedPrincipals: make(map[string]struct{}, len(privilegedPrincipals)),
+	}
+
+	for _, s := range patterns {
+		var pattern protectedTuplePattern
+
+		object, rest, hasRelation := strings.Cut(s, "#")
+		if hasRelation {
+			relation, user, hasUser := strings.Cut(rest, "@")
+			if relation == "" || (hasUser && user == "") {
+				return nil, fmt.Errorf("invalid protected tuple pattern '%s'", s)
+			}
+			pattern.relation = relation
+			pattern.user = user
+		}
+
+		objectType, objectID, ok := strings.Cut(object, ":")
+		if !ok || objectType == "" || objectID == "" {
+			return nil, fmt.Errorf("invalid protected tuple pattern '%s'", s)
+		}
+		pattern.objectType = objectType
+		if objectID != "*" {
+			pattern.objectID = objectID
+		}
+
+		p.patterns = append(p.patterns, pattern)
+	}
+
+	for _, principal := range privilegedPrincipals {
+		p.privilegedPrincipals[principal] = struct{}{}
+	}
+
+	return p, nil
+}
+
+// IsProtected reports whether the tuple matches one of the protected patterns.
+func (p *ProtectedTuples) IsProtected(tk *openfgav1.TupleKeyWithoutCondition) bool {
+	if p == nil {
+		return false
+	}
+
+	objectType, objectID := tupleUtils.SplitObject(tk.GetObject())
+	for _, pattern := range p.patterns {
+		if pattern.objectType != objectType ||
+			(pattern.objectID != "" && pattern.objectID != objectID) ||
+			(pattern.relation != "" && pattern.relation != tk.GetRelation()) ||
+			(pattern.user != "" && pattern.user != tk.GetUser()) {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// validateDelete returns a PermissionDenied error if the tuple is protected and the request is not a
+// forced delete issued by a privileged principal.
+func (p *ProtectedTuples) validateDelete(ctx context.Context, tk *openfgav1.TupleKeyWithoutCondition) error {
+	if !p.IsProtected(tk) {
+		return nil
+	}
+
+	if !forceDeleteFromContext(ctx) {
+		return status.Errorf(codes.PermissionDenied, "cannot delete protected tuple '%s' without the '%s' header", tupleUtils.TupleKeyToString(tk), ForceDeleteHeader)
+	}
+
+	if !p.isPrivileged(ctx) {
+		return status.Errorf(codes.PermissionDenied, "the credentials are not allowed to delete protected tuple '%s'", tupleUtils.TupleKeyToString(tk))
+	}
+
+	return nil
+}
+
+// validateDeleteStore returns a PermissionDenied error if the store holds a protected tuple and the request is not
+// a forced delete issued by a privileged principal.
+func (p *ProtectedTuples) validateDeleteStore(ctx context.Context, tupleReader storage.RelationshipTupleReader, store string) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, pattern := range p.patterns {
+		tk, err := firstTuple(ctx, tupleReader, store, &openfgav1.TupleKey{
+			Object:   tupleUtils.BuildObject(pattern.objectType, pattern.objectID),
+			Relation: pattern.relation,
+			User:     pattern.user,
+		})
+		if err != nil {
+			return serverErrors.HandleError("", err)
+		}
+		if tk == nil {
+			continue
+		}
+		if err := p.validateDelete(ctx, tupleUtils.TupleKeyToTupleKeyWithoutCondition(tk)); err != nil {
+			return status.Errorf(codes.PermissionDenied, "cannot delete store '%s' holding protected tuples: %s", store, status.Convert(err).Message())
+		}
+		// the request may delete any protected tuple
+		return nil
+	}
+
+	return nil
+}
+
+// firstTuple returns the first tuple of the store matching tk, or nil if there is none.
+func firstTuple(ctx context.Context, tupleReader storage.RelationshipTupleReader, store string, tk *openfgav1.TupleKey) (*openfgav1.TupleKey, error) {
+	iter, err := tupleReader.Read(ctx, store, tk, storage.ReadOptions{
+		Consistency: storage.ConsistencyOptions{Preference: openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Stop()
+
+	t, err := iter.Next(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrIteratorDone) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t.GetKey(), nil
+}
+
+func (p *ProtectedTuples) isPrivileged(ctx context.Context) bool {
+	claims, ok := authclaims.AuthClaimsFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	for _, principal := range []string{claims.Subject, claims.ClientID} {
+		if principal == "" {
+			continue
+		}
+		if _, found := p.privilegedPrincipals[principal]; found {
+			return true
+		}
+	}
+
+	return false
+}
+
+func forceDeleteFromContext(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+
+	values := md.Get(ForceDeleteHeader)
+	if len(values) == 0 {
+		return false
+	}
+
+	force, err := strconv.ParseBool(values[0])
+	return err == nil && force
+}
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	mockstorage "github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestNewProtectedTuples(t *testing.T) {
+	for _, pattern := range []string{"document", "document:", ":1", "document:1#", "document:1#viewer@"} {
+		_, err := NewProtectedTuples([]string{pattern}, nil)
+		require.Error(t, err, pattern)
+	}
+}
+
+func TestProtectedTuplesIsProtected(t *testing.T) {
+	protected, err := NewProtectedTuples([]string{
+		"group:break-glass#member",
+		"folder:*",
+		"document:1#owner@user:anne",
+	}, nil)
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"group:break-glass#member@user:anne": true,
+		"group:break-glass#owner@user:anne":  false,
+		"group:other#member@user:anne":       false,
+		"folder:x#viewer@user:bob":           true,
+		"document:1#owner@user:anne":         true,
+		"document:1#owner@user:bob":          false,
+	}
+	for tk, expected := range tests {
+		require.Equal(t, expected, protected.IsProtected(tuple.TupleKeyToTupleKeyWithoutCondition(tuple.MustParseTupleString(tk))), tk)
+	}
+
+	var unset *ProtectedTuples
+	require.False(t, unset.IsProtected(tuple.TupleKeyToTupleKeyWithoutCondition(tuple.NewTupleKey("folder:x", "viewer", "user:bob"))))
+}
+
+func TestWriteCommandProtectedTuples(t *testing.T) {
+	const storeID = "01JCC8Z5S039R3X661KQGTNAFG"
+
+	protected, err := NewProtectedTuples([]string{"group:break-glass#member"}, []string{"security-team"})
+	require.NoError(t, err)
+
+	deletes := []*openfgav1.TupleKeyWithoutCondition{
+		tuple.TupleKeyToTupleKeyWithoutCondition(tuple.NewTupleKey("group:break-glass", "member", "user:anne")),
+	}
+	req := &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Deletes: &openfgav1.WriteRequestDeletes{TupleKeys: deletes},
+	}
+	forced := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ForceDeleteHeader, "true"))
+
+	tests := map[string]struct {
+		ctx     context.Context
+		allowed bool
+	}{
+		`not_forced`: {
+			ctx: authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{ClientID: "security-team"}),
+		},
+		`forced_without_credentials`: {
+			ctx: forced,
+		},
+		`forced_by_unprivileged_principal`: {
+			ctx: authclaims.ContextWithAuthClaims(forced, &authclaims.AuthClaims{Subject: "automation"}),
+		},
+		`forced_by_privileged_principal`: {
+			ctx:     authclaims.ContextWithAuthClaims(forced, &authclaims.AuthClaims{ClientID: "security-team"}),
+			allowed: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+
+			mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+			if test.allowed {
+				mockDatastore.EXPECT().MaxTuplesPerWrite().Return(10)
+				mockDatastore.EXPECT().Write(gomock.Any(), storeID, deletes, gomock.Any()).Return(nil)
+			}
+
+			cmd := NewWriteCommand(mockDatastore, WithProtectedTuples(protected))
+			_, err := cmd.Execute(test.ctx, req)
+			if test.allowed {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, codes.PermissionDenied, status.Code(err))
+		})
+	}
+}
+
+func TestDeleteStoreCommandProtectedTuples(t *testing.T) {
+	protected, err := NewProtectedTuples([]string{"group:break-glass#member", "folder:*"}, []string{"security-team"})
+	require.NoError(t, err)
+
+	forced := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ForceDeleteHeader, "true"))
+
+	tests := map[string]struct {
+		ctx     context.Context
+		tuples  []*openfgav1.TupleKey
+		allowed bool
+	}{
+		`no_protected_tuples`: {
+			ctx:     context.Background(),
+			tuples:  []*openfgav1.TupleKey{tuple.NewTupleKey("group:other", "member", "user:anne")},
+			allowed: true,
+		},
+		`protected_tuple`: {
+			ctx:    authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{ClientID: "security-team"}),
+			tuples: []*openfgav1.TupleKey{tuple.NewTupleKey("group:break-glass", "member", "user:anne")},
+		},
+		`protected_type`: {
+			ctx:    authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{ClientID: "security-team"}),
+			tuples: []*openfgav1.TupleKey{tuple.NewTupleKey("folder:x", "viewer", "user:anne")},
+		},
+		`forced_by_unprivileged_principal`: {
+			ctx:    authclaims.ContextWithAuthClaims(forced, &authclaims.AuthClaims{Subject: "automation"}),
+			tuples: []*openfgav1.TupleKey{tuple.NewTupleKey("group:break-glass", "member", "user:anne")},
+		},
+		`forced_by_privileged_principal`: {
+			ctx:     authclaims.ContextWithAuthClaims(forced, &authclaims.AuthClaims{ClientID: "security-team"}),
+			tuples:  []*openfgav1.TupleKey{tuple.NewTupleKey("group:break-glass", "member", "user:anne")},
+			allowed: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ds := memory.New()
+			t.Cleanup(ds.Close)
+
+			store, err := ds.CreateStore(context.Background(), &openfgav1.Store{Id: ulid.Make().String(), Name: "acme"})
+			require.NoError(t, err)
+			require.NoError(t, ds.Write(context.Background(), store.GetId(), nil, test.tuples))
+
+			cmd := NewDeleteStoreCommand(ds, WithDeleteStoreCmdProtectedTuples(ds, protected))
+			_, err = cmd.Execute(test.ctx, &openfgav1.DeleteStoreRequest{StoreId: store.GetId()})
+			if test.allowed {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+			_, err = ds.GetStore(context.Background(), store.GetId())
+			require.NoError(t, err)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
@@ -23,6 +25,8 @@ type ReadQuery struct {
 	logger          logger.Logger
 	encoder         encoder.Encoder
 	tokenSerializer encoder.ContinuationTokenSerializer
+	relations       []string
+	userType        string
 }
 
 type ReadQueryOption func(*ReadQuery)
@@ -45,6 +49,22 @@ func WithReadQueryTokenSerializer(serializer encoder.ContinuationTokenSerializer
 	}
 }
 
+// WithReadQueryRelations restricts the tuples read to those whose relation is one of relations. It cannot be
+// combined with a relation in the tuple key of the request.
+func WithReadQueryRelations(relations ...string) ReadQueryOption {
+	return func(rq *ReadQuery) {
+		rq.relations = relations
+	}
+}
+
+// WithReadQueryUserType restricts the tuples read to those whose user is of the userType object type, e.g. 'user'
+// matches 'user:anne' and 'user:*', and 'group' matches 'group:eng#member'.
+func WithReadQueryUserType(userType string) ReadQueryOption {
+	return func(rq *ReadQuery) {
+		rq.userType = userType
+	}
+}
+
 // NewReadQuery creates a ReadQuery using the provided OpenFGA datastore implementation.
 func NewReadQuery(datastore storage.OpenFGADatastore, opts ...ReadQueryOption) *ReadQuery {
 	rq := &ReadQuery{
@@ -66,19 +86,13 @@ func (q *ReadQuery) Execute(ctx context.Context, req *openfgav1.ReadRequest) (*o
 	store := req.GetStoreId()
 	tk := req.GetTupleKey()
 
-	// Restrict our reads due to some compatibility issues in one of our storage implementations.
-	if tk != nil {
-		objectType, objectID := tupleUtils.SplitObject(tk.GetObject())
-		if objectType == "" || (objectID == "" && tk.GetUser() == "") {
-			return nil, serverErrors.ValidationError(
-				fmt.Errorf("the 'tuple_key' field was provided but the object type field is required and both the object id and user cannot be empty"),
-			)
-		}
+	if err := q.validateReadTupleKey(tk); err != nil {
+		return nil, err
 	}
 
 	decodedContToken, err := q.encoder.Decode(req.GetContinuationToken())
 	if err != nil {
-		return nil, serverErrors.ErrInvalidContinuationToken
+		return nil, serverErrors.ContinuationTokenError(err)
 	}
 
 	if len(decodedContToken) > 0 {
@@ -92,9 +106,11 @@ func (q *ReadQuery) Execute(ctx context.Context, req *openfgav1.ReadRequest) (*o
 	opts := storage.ReadPageOptions{
 		Pagination:  storage.NewPaginationOptions(req.GetPageSize().GetValue(), string(decodedContToken)),
 		Consistency: storage.ConsistencyOptions{Preference: req.GetConsistency()},
+		Relations:   q.relations,
+		UserType:    q.userType,
 	}
 
-	tuples, contUlid, err := q.datastore.ReadPage(ctx, store, tupleUtils.ConvertReadRequestTupleKeyToTupleKey(tk), opts)
+	tuples, contUlid, err := q.datastore.ReadPage(ctx, store, q.datastoreTupleKey(tk), opts)
 	if err != nil {
 		return nil, serverErrors.HandleError("", err)
 	}
@@ -121,3 +137,90 @@ func (q *ReadQuery) Execute(ctx context.Context, req *openfgav1.ReadRequest) (*o
 		ContinuationToken: encodedContToken,
 	}, nil
 }
+
+// ReadStreamServer is the stream the tuples of a streamed read are sent to.
+type ReadStreamServer interface {
+	Send(*openfgav1.Tuple) error
+}
+
+// ExecuteStreamed executes the ReadQuery, sending the `openfga.Tuple`(s) that match the tuple to srv as they are
+// read from the datastore, without building pages. The page size and continuation token of the request are ignored.
+// Send blocks while the client is not consuming the stream, which in turn stops the reads from the datastore.
+func (q *ReadQuery) ExecuteStreamed(ctx context.Context, req *openfgav1.ReadRequest, srv ReadStreamServer) error {
+	tk := req.GetTupleKey()
+
+	if err := q.validateReadTupleKey(tk); err != nil {
+		return err
+	}
+
+	iter, err := q.datastore.Read(ctx, req.GetStoreId(), q.datastoreTupleKey(tk), storage.ReadOptions{
+		Consistency: storage.ConsistencyOptions{Preference: req.GetConsistency()},
+	})
+	if err != nil {
+		return serverErrors.HandleError("", err)
+	}
+	defer iter.Stop()
+
+	for {
+		t, err := iter.Next(ctx)
+		if err != nil {
+			if errors.Is(err, storage.ErrIteratorDone) {
+				return nil
+			}
+			return serverErrors.HandleError("", err)
+		}
+
+		if !q.matches(t.GetKey()) {
+			continue
+		}
+
+		if err := srv.Send(t); err != nil {
+			return err
+		}
+	}
+}
+
+// datastoreTupleKey returns the tuple key to read from the datastore. A single relation filter is pushed down to it.
+func (q *ReadQuery) datastoreTupleKey(tk *openfgav1.ReadRequestTupleKey) *openfgav1.TupleKey {
+	key := tupleUtils.ConvertReadRequestTupleKeyToTupleKey(tk)
+	if len(q.relations) == 1 {
+		key.Relation = q.relations[0]
+	}
+	return key
+}
+
+// matches returns true if tk passes the relation and user type filters of the query.
+func (q *ReadQuery) matches(tk *openfgav1.TupleKey) bool {
+	if len(q.relations) > 0 && !slices.Contains(q.relations, tk.GetRelation()) {
+		return false
+	}
+	if q.userType != "" {
+		userObject, _ := tupleUtils.SplitObjectRelation(tk.GetUser())
+		if tupleUtils.GetType(userObject) != q.userType {
+			return false
+		}
+	}
+	return true
+}
+
+// validateReadTupleKey restricts our reads due to some compatibility issues in one of our storage implementations.
+func (q *ReadQuery) validateReadTupleKey(tk *openfgav1.ReadRequestTupleKey) error {
+	if len(q.relations) > 0 && tk.GetRelation() != "" {
+		return serverErrors.ValidationError(
+			fmt.Errorf("the 'tuple_key' relation cannot be combined with a relations filter"),
+		)
+	}
+
+	if tk == nil {
+		return nil
+	}
+
+	objectType, objectID := tupleUtils.SplitObject(tk.GetObject())
+	if objectType == "" || (objectID == "" && tk.GetUser() == "") {
+		return serverErrors.ValidationError(
+			fmt.Errorf("the 'tuple_key' field was provided but the object type field is required and both the object id and user cannot be empty"),
+		)
+	}
+
+	return nil
+}
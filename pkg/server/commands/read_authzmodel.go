@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"google.golang.org/protobuf/proto"
+
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
 
 	"github.com/openfga/openfga/pkg/logger"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
@@ -50,3 +53,28 @@ func (q *ReadAuthorizationModelQuery) Execute(ctx context.Context, req *openfgav
 		AuthorizationModel: azm,
 	}, nil
 }
+
+// ExecuteDSL returns the model of the request rendered in the FGA DSL.
+func (q *ReadAuthorizationModelQuery) ExecuteDSL(ctx context.Context, req *openfgav1.ReadAuthorizationModelRequest) (string, error) {
+	res, err := q.Execute(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	// the models written in memory, rather than read from their serialized form, may hold direct relations without
+	// their empty message, which the DSL transformer rejects
+	serialized, err := proto.Marshal(res.GetAuthorizationModel())
+	if err != nil {
+		return "", serverErrors.HandleError("", err)
+	}
+	model := &openfgav1.AuthorizationModel{}
+	if err := proto.Unmarshal(serialized, model); err != nil {
+		return "", serverErrors.HandleError("", err)
+	}
+
+	dsl, err := parser.TransformJSONProtoToDSL(model)
+	if err != nil {
+		return "", serverErrors.HandleError("", err)
+	}
+	return dsl, nil
+}
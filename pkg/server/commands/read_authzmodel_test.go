@@ -13,6 +13,7 @@ import (
 	"google.golang.org/protobuf/testing/protocmp"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
 
 	mockstorage "github.com/openfga/openfga/internal/mocks"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
@@ -118,3 +119,36 @@ func TestReadAuthorizationModelQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestReadAuthorizationModelQueryDSL(t *testing.T) {
+	storeID := ulid.Make().String()
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user]`)
+	model.Id = ulid.Make().String()
+
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+	mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, model.GetId()).Times(1).Return(model, nil)
+
+	dsl, err := NewReadAuthorizationModelQuery(mockDatastore).ExecuteDSL(context.Background(), &openfgav1.ReadAuthorizationModelRequest{
+		StoreId: storeID,
+		Id:      model.GetId(),
+	})
+	require.NoError(t, err)
+	require.Contains(t, dsl, "schema 1.1")
+	require.Contains(t, dsl, "type document")
+	require.Contains(t, dsl, "define viewer: [user]")
+
+	// The rendered DSL describes the same model.
+	parsed, err := parser.TransformDSLToProto(dsl)
+	require.NoError(t, err)
+	require.Len(t, parsed.GetTypeDefinitions(), 2)
+}
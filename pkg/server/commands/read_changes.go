@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -22,6 +23,8 @@ type ReadChangesQuery struct {
 	encoder         encoder.Encoder
 	tokenSerializer encoder.ContinuationTokenSerializer
 	horizonOffset   time.Duration
+	objectID        string
+	user            string
 }
 
 type ReadChangesQueryOption func(*ReadChangesQuery)
@@ -52,6 +55,22 @@ func WithContinuationTokenSerializer(tokenSerializer encoder.ContinuationTokenSe
 	}
 }
 
+// WithReadChangesQueryObjectID restricts the changes read to those of the object with this id. The request must
+// have a type.
+func WithReadChangesQueryObjectID(objectID string) ReadChangesQueryOption {
+	return func(rq *ReadChangesQuery) {
+		rq.objectID = objectID
+	}
+}
+
+// WithReadChangesQueryUser restricts the changes read to those of tuples with this user, e.g. 'user:anne' or
+// 'group:eng#member'.
+func WithReadChangesQueryUser(user string) ReadChangesQueryOption {
+	return func(rq *ReadChangesQuery) {
+		rq.user = user
+	}
+}
+
 // NewReadChangesQuery creates a ReadChangesQuery with specified `ChangelogBackend`.
 func NewReadChangesQuery(backend storage.ChangelogBackend, opts ...ReadChangesQueryOption) *ReadChangesQuery {
 	rq := &ReadChangesQuery{
@@ -70,9 +89,13 @@ func NewReadChangesQuery(backend storage.ChangelogBackend, opts ...ReadChangesQu
 
 // Execute the ReadChangesQuery, returning paginated `openfga.TupleChange`(s) and a possibly non-empty continuation token.
 func (q *ReadChangesQuery) Execute(ctx context.Context, req *openfgav1.ReadChangesRequest) (*openfgav1.ReadChangesResponse, error) {
+	if q.objectID != "" && req.GetType() == "" {
+		return nil, serverErrors.ValidationError(fmt.Errorf("the object id filter requires the 'type' field"))
+	}
+
 	decodedContToken, err := q.encoder.Decode(req.GetContinuationToken())
 	if err != nil {
-		return nil, serverErrors.ErrInvalidContinuationToken
+		return nil, serverErrors.ContinuationTokenError(err)
 	}
 	token := string(decodedContToken)
 
@@ -107,6 +130,8 @@ func (q *ReadChangesQuery) Execute(ctx context.Context, req *openfgav1.ReadChang
 	}
 	filter := storage.ReadChangesFilter{
 		ObjectType:    req.GetType(),
+		ObjectID:      q.objectID,
+		User:          q.user,
 		HorizonOffset: q.horizonOffset,
 	}
 	changes, contUlid, err := q.backend.ReadChanges(ctx, req.GetStoreId(), filter, opts)
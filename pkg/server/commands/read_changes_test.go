@@ -353,4 +353,48 @@ func TestReadChangesQuery(t *testing.T) {
 		require.Empty(t, resp.GetChanges())
 		require.Equal(t, reqToken, resp.GetContinuationToken())
 	})
+	t.Run("passes_object_id_and_user_filters_to_storage", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		storeID := ulid.Make().String()
+
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		opts := storage.ReadChangesOptions{
+			Pagination: storage.PaginationOptions{
+				PageSize: storage.DefaultPageSize,
+				From:     "",
+			},
+		}
+		filter := storage.ReadChangesFilter{
+			ObjectType: "folder",
+			ObjectID:   "1",
+			User:       "user:anne",
+		}
+		mockDatastore.EXPECT().ReadChanges(gomock.Any(), storeID, filter, opts).Times(1).Return(nil, "", storage.ErrNotFound)
+
+		cmd := NewReadChangesQuery(mockDatastore,
+			WithReadChangeQueryHorizonOffset(0),
+			WithReadChangesQueryObjectID("1"),
+			WithReadChangesQueryUser("user:anne"),
+		)
+		_, err := cmd.Execute(context.Background(), &openfgav1.ReadChangesRequest{
+			StoreId: storeID,
+			Type:    "folder",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("throws_error_if_object_id_filter_has_no_type", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		cmd := NewReadChangesQuery(mockDatastore, WithReadChangesQueryObjectID("1"))
+		resp, err := cmd.Execute(context.Background(), &openfgav1.ReadChangesRequest{
+			StoreId: ulid.Make().String(),
+		})
+		require.Nil(t, resp)
+		require.ErrorIs(t, err, serverErrors.ValidationError(fmt.Errorf("the object id filter requires the 'type' field")))
+	})
 }
@@ -196,3 +196,154 @@ func TestReadCommand(t *testing.T) {
 		require.Equal(t, "user_old:maria", resp.GetTuples()[0].GetKey().GetUser())
 	})
 }
+
+type sliceReadStreamServer struct {
+	tuples []*openfgav1.Tuple
+	err    error
+}
+
+func (s *sliceReadStreamServer) Send(t *openfgav1.Tuple) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.tuples = append(s.tuples, t)
+	return nil
+}
+
+func TestReadCommandExecuteStreamed(t *testing.T) {
+	datastore := memory.New()
+	t.Cleanup(datastore.Close)
+
+	model := `
+		model
+		  schema 1.1
+
+		type user
+
+		type document
+		  relations
+		    define viewer: [user]`
+	tuples := []string{
+		"document:1#viewer@user:maria",
+		"document:1#viewer@user:jon",
+		"document:2#viewer@user:maria",
+	}
+	storeID, _ := storagetest.BootstrapFGAStore(t, datastore, model, tuples)
+
+	t.Run("throws_error_if_input_is_invalid", func(t *testing.T) {
+		srv := &sliceReadStreamServer{}
+		err := NewReadQuery(datastore).ExecuteStreamed(context.Background(), &openfgav1.ReadRequest{
+			StoreId:  storeID,
+			TupleKey: &openfgav1.ReadRequestTupleKey{Object: "document:"},
+		}, srv)
+		require.ErrorIs(t, err, serverErrors.ValidationError(
+			fmt.Errorf("the 'tuple_key' field was provided but the object type field is required and both the object id and user cannot be empty"),
+		))
+		require.Empty(t, srv.tuples)
+	})
+
+	t.Run("streams_every_matching_tuple", func(t *testing.T) {
+		srv := &sliceReadStreamServer{}
+		err := NewReadQuery(datastore).ExecuteStreamed(context.Background(), &openfgav1.ReadRequest{
+			StoreId:  storeID,
+			TupleKey: &openfgav1.ReadRequestTupleKey{Object: "document:1"},
+			PageSize: wrapperspb.Int32(1),
+		}, srv)
+		require.NoError(t, err)
+		require.Len(t, srv.tuples, 2)
+		for _, tup := range srv.tuples {
+			require.Equal(t, "document:1", tup.GetKey().GetObject())
+		}
+	})
+
+	t.Run("stops_on_send_error", func(t *testing.T) {
+		sendErr := fmt.Errorf("stream closed")
+		err := NewReadQuery(datastore).ExecuteStreamed(context.Background(), &openfgav1.ReadRequest{
+			StoreId: storeID,
+		}, &sliceReadStreamServer{err: sendErr})
+		require.ErrorIs(t, err, sendErr)
+	})
+}
+
+func TestReadCommandFilters(t *testing.T) {
+	datastore := memory.New()
+	t.Cleanup(datastore.Close)
+
+	model := `
+		model
+		  schema 1.1
+
+		type user
+
+		type group
+		  relations
+		    define member: [user]
+
+		type document
+		  relations
+		    define owner: [user]
+		    define editor: [user]
+		    define viewer: [user, group#member]`
+	tuples := []string{
+		"document:1#owner@user:anne",
+		"document:1#viewer@group:eng#member",
+		"document:1#viewer@user:maria",
+		"document:1#editor@user:jon",
+		"document:2#viewer@user:maria",
+	}
+	storeID, _ := storagetest.BootstrapFGAStore(t, datastore, model, tuples)
+
+	t.Run("throws_error_if_relation_is_combined_with_relations_filter", func(t *testing.T) {
+		resp, err := NewReadQuery(datastore, WithReadQueryRelations("viewer", "editor")).Execute(context.Background(), &openfgav1.ReadRequest{
+			StoreId:  storeID,
+			TupleKey: &openfgav1.ReadRequestTupleKey{Object: "document:1", Relation: "viewer"},
+		})
+		require.Nil(t, resp)
+		require.ErrorIs(t, err, serverErrors.ValidationError(
+			fmt.Errorf("the 'tuple_key' relation cannot be combined with a relations filter"),
+		))
+	})
+
+	t.Run("pages_through_the_tuples_matching_every_filter", func(t *testing.T) {
+		cmd := NewReadQuery(datastore, WithReadQueryRelations("viewer", "editor"), WithReadQueryUserType("user"))
+
+		var got []string
+		var continuationToken string
+		for {
+			resp, err := cmd.Execute(context.Background(), &openfgav1.ReadRequest{
+				StoreId:           storeID,
+				TupleKey:          &openfgav1.ReadRequestTupleKey{Object: "document:1"},
+				PageSize:          wrapperspb.Int32(1),
+				ContinuationToken: continuationToken,
+			})
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(resp.GetTuples()), 1)
+			for _, tup := range resp.GetTuples() {
+				got = append(got, tuple.TupleKeyToString(tup.GetKey()))
+			}
+			if resp.GetContinuationToken() == "" {
+				break
+			}
+			continuationToken = resp.GetContinuationToken()
+		}
+		require.ElementsMatch(t, []string{"document:1#viewer@user:maria", "document:1#editor@user:jon"}, got)
+	})
+
+	t.Run("streams_the_tuples_matching_every_filter", func(t *testing.T) {
+		srv := &sliceReadStreamServer{}
+		err := NewReadQuery(datastore, WithReadQueryRelations("viewer"), WithReadQueryUserType("group")).ExecuteStreamed(context.Background(), &openfgav1.ReadRequest{
+			StoreId:  storeID,
+			TupleKey: &openfgav1.ReadRequestTupleKey{Object: "document:"},
+		}, srv)
+		require.ErrorIs(t, err, serverErrors.ValidationError(
+			fmt.Errorf("the 'tuple_key' field was provided but the object type field is required and both the object id and user cannot be empty"),
+		))
+
+		err = NewReadQuery(datastore, WithReadQueryRelations("viewer"), WithReadQueryUserType("group")).ExecuteStreamed(context.Background(), &openfgav1.ReadRequest{
+			StoreId: storeID,
+		}, srv)
+		require.NoError(t, err)
+		require.Len(t, srv.tuples, 1)
+		require.Equal(t, "document:1#viewer@group:eng#member", tuple.TupleKeyToString(srv.tuples[0].GetKey()))
+	})
+}
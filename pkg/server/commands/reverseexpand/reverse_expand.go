@@ -23,6 +23,7 @@ import (
 	"github.com/openfga/openfga/internal/throttler/threshold"
 	"github.com/openfga/openfga/internal/validation"
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/redact"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/telemetry"
@@ -257,7 +258,7 @@ func (c *ReverseExpandQuery) execute(
 	ctx, span := tracer.Start(ctx, "reverseExpand.Execute", trace.WithAttributes(
 		attribute.String("target_type", req.ObjectType),
 		attribute.String("target_relation", req.Relation),
-		attribute.String("source", req.User.String()),
+		attribute.String("source", redact.User(req.User.String())),
 	))
 	defer span.End()
 
@@ -386,7 +387,7 @@ func (c *ReverseExpandQuery) reverseExpandTupleToUserset(
 ) error {
 	ctx, span := tracer.Start(ctx, "reverseExpandTupleToUserset", trace.WithAttributes(
 		attribute.String("edge", req.edge.String()),
-		attribute.String("source.user", req.User.String()),
+		attribute.String("source.user", redact.User(req.User.String())),
 	))
 	var err error
 	defer func() {
@@ -409,7 +410,7 @@ func (c *ReverseExpandQuery) reverseExpandDirect(
 ) error {
 	ctx, span := tracer.Start(ctx, "reverseExpandDirect", trace.WithAttributes(
 		attribute.String("edge", req.edge.String()),
-		attribute.String("source.user", req.User.String()),
+		attribute.String("source.user", redact.User(req.User.String())),
 	))
 	var err error
 	defer func() {
@@ -594,7 +595,7 @@ LoopOnIterator:
 
 func (c *ReverseExpandQuery) trySendCandidate(ctx context.Context, intersectionOrExclusionInPreviousEdges bool, candidateObject string, candidateChan chan<- *ReverseExpandResult) error {
 	_, span := tracer.Start(ctx, "trySendCandidate", trace.WithAttributes(
-		attribute.String("object", candidateObject),
+		attribute.String("object", redact.Object(candidateObject)),
 		attribute.Bool("sent", false),
 	))
 	defer span.End()
@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/pkg/logger"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// AssertionResult is the outcome of running an assertion.
+type AssertionResult struct {
+	Assertion *openfgav1.Assertion
+	// Allowed is the result of the Check of the assertion. It is false if Err is set.
+	Allowed bool
+	// Passed reports whether Allowed matches the expectation of the assertion. It is false if Err is set.
+	Passed bool
+	// Err is the error the Check of the assertion failed with, if any.
+	Err error
+}
+
+// RunAssertionsCommand runs the assertions of an authorization model through the Check resolver.
+type RunAssertionsCommand struct {
+	datastore     storage.OpenFGADatastore
+	checkResolver graph.CheckResolver
+	typesys       *typesystem.TypeSystem
+	logger        logger.Logger
+	checkOptions  []CheckQueryOption
+	checkTimeout  time.Duration
+	contextFunc   func(*structpb.Struct) *structpb.Struct
+}
+
+type RunAssertionsOption func(*RunAssertionsCommand)
+
+func WithRunAssertionsLogger(l logger.Logger) RunAssertionsOption {
+	return func(c *RunAssertionsCommand) {
+		c.logger = l
+	}
+}
+
+// WithRunAssertionsCheckOptions sets the options of the Check command every assertion is run with.
+func WithRunAssertionsCheckOptions(opts ...CheckQueryOption) RunAssertionsOption {
+	return func(c *RunAssertionsCommand) {
+		c.checkOptions = append(c.checkOptions, opts...)
+	}
+}
+
+// WithRunAssertionsCheckTimeout sets the timeout of the Check of every assertion, e.g. the timeout of the Check
+// method. Zero, the default, runs the checks without a timeout of their own.
+func WithRunAssertionsCheckTimeout(timeout time.Duration) RunAssertionsOption {
+	return func(c *RunAssertionsCommand) {
+		c.checkTimeout = timeout
+	}
+}
+
+// WithRunAssertionsContextFunc sets the function the context of every assertion is passed through before it is
+// checked, e.g. to set the trusted parameters of the server.
+func WithRunAssertionsContextFunc(fn func(*structpb.Struct) *structpb.Struct) RunAssertionsOption {
+	return func(c *RunAssertionsCommand) {
+		c.contextFunc = fn
+	}
+}
+
+func NewRunAssertionsCommand(datastore storage.OpenFGADatastore, checkResolver graph.CheckResolver, typesys *typesystem.TypeSystem, opts ...RunAssertionsOption) *RunAssertionsCommand {
+	cmd := &RunAssertionsCommand{
+		datastore:     datastore,
+		checkResolver: checkResolver,
+		typesys:       typesys,
+		logger:        logger.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(cmd)
+	}
+	return cmd
+}
+
+// Execute runs the assertions written for the model of the command in the store, with their contextual tuples and
+// context, and returns their results in the order of the assertions. An assertion whose Check fails does not stop
+// the others from running, unless the context is done.
+func (c *RunAssertionsCommand) Execute(ctx context.Context, storeID string) ([]AssertionResult, error) {
+	assertions, err := c.datastore.ReadAssertions(ctx, storeID, c.typesys.GetAuthorizationModelID())
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	checkQuery := NewCheckCommand(c.datastore, c.checkResolver, c.typesys,
+		append([]CheckQueryOption{WithCheckCommandLogger(c.logger)}, c.checkOptions...)...)
+
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, assertion := range assertions {
+		if err := ctx.Err(); err != nil {
+			return nil, CheckCommandErrorToServerError(err)
+		}
+
+		tk := assertion.GetTupleKey()
+		assertionCtx := assertion.GetContext()
+		if c.contextFunc != nil {
+			assertionCtx = c.contextFunc(assertionCtx)
+		}
+		resp, err := c.check(ctx, checkQuery, &CheckCommandParams{
+			StoreID:          storeID,
+			TupleKey:         tuple.NewCheckRequestTupleKey(tk.GetObject(), tk.GetRelation(), tk.GetUser()),
+			ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: assertion.GetContextualTuples()},
+			Context:          assertionCtx,
+		})
+		if err != nil {
+			results = append(results, AssertionResult{Assertion: assertion, Err: CheckCommandErrorToServerError(err)})
+			continue
+		}
+
+		results = append(results, AssertionResult{
+			Assertion: assertion,
+			Allowed:   resp.GetAllowed(),
+			Passed:    resp.GetAllowed() == assertion.GetExpectation(),
+		})
+	}
+	return results, nil
+}
+
+// check runs the Check of an assertion, within the check timeout of the command if it has one.
+func (c *RunAssertionsCommand) check(ctx context.Context, checkQuery *CheckQuery, params *CheckCommandParams) (*graph.ResolveCheckResponse, error) {
+	if c.checkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.checkTimeout)
+		defer cancel()
+	}
+
+	resp, _, err := checkQuery.Execute(ctx, params)
+	return resp, err
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/graph"
+	mockstorage "github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestRunAssertionsCommand(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user]`)
+	ts, err := typesystem.NewAndValidate(ctx, model)
+	require.NoError(t, err)
+
+	t.Run("reports_the_result_of_every_assertion", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+
+		assertions := []*openfgav1.Assertion{
+			{
+				TupleKey:    tuple.NewAssertionTupleKey("document:1", "viewer", "user:anne"),
+				Expectation: true,
+				ContextualTuples: []*openfgav1.TupleKey{
+					tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+				},
+			},
+			{
+				TupleKey:    tuple.NewAssertionTupleKey("document:1", "viewer", "user:bob"),
+				Expectation: true,
+			},
+			{
+				TupleKey:    tuple.NewAssertionTupleKey("document:1", "undefined", "user:bob"),
+				Expectation: false,
+			},
+		}
+		mockDatastore.EXPECT().ReadAssertions(gomock.Any(), storeID, model.GetId()).Times(1).Return(assertions, nil)
+		mockCheckResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).Times(2).
+			DoAndReturn(func(_ context.Context, req *graph.ResolveCheckRequest) (*graph.ResolveCheckResponse, error) {
+				allowed := len(req.GetContextualTuples()) > 0
+				return &graph.ResolveCheckResponse{Allowed: allowed}, nil
+			})
+
+		results, err := NewRunAssertionsCommand(mockDatastore, mockCheckResolver, ts).Execute(ctx, storeID)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		require.True(t, results[0].Allowed)
+		require.True(t, results[0].Passed)
+		require.NoError(t, results[0].Err)
+
+		require.False(t, results[1].Allowed)
+		require.False(t, results[1].Passed)
+		require.NoError(t, results[1].Err)
+
+		require.False(t, results[2].Passed)
+		require.ErrorContains(t, results[2].Err, "relation 'document#undefined' not found")
+	})
+
+	t.Run("fails_if_the_assertions_cannot_be_read", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+
+		mockDatastore.EXPECT().ReadAssertions(gomock.Any(), storeID, model.GetId()).Times(1).Return(nil, errors.New("internal"))
+
+		_, err := NewRunAssertionsCommand(mockDatastore, mockCheckResolver, ts).Execute(ctx, storeID)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/openfga/openfga/pkg/logger"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+const (
+	// MaxStoreLabels is the maximum number of labels of a store.
+	MaxStoreLabels = 32
+	// MaxStoreLabelValueLength is the maximum length of the value of a store label.
+	MaxStoreLabelValueLength = 255
+)
+
+var storeLabelKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]{0,62})$`)
+
+// ValidateStoreLabels returns a validation error if labels has too many labels, or a label whose key is not made
+// of at most 63 letters, digits, '.', '_' and '-' starting with a letter or digit, or whose value is too long.
+func ValidateStoreLabels(labels map[string]string) error {
+	if len(labels) > MaxStoreLabels {
+		return serverErrors.ValidationError(fmt.Errorf("a store cannot have more than %d labels", MaxStoreLabels))
+	}
+	for key, value := range labels {
+		if !storeLabelKeyRegex.MatchString(key) {
+			return serverErrors.ValidationError(fmt.Errorf("invalid store label key '%s'", key))
+		}
+		if len(value) > MaxStoreLabelValueLength {
+			return serverErrors.ValidationError(fmt.Errorf("the value of the store label '%s' cannot be longer than %d characters", key, MaxStoreLabelValueLength))
+		}
+	}
+	return nil
+}
+
+// UpdateStoreCommand replaces the labels of a store.
+type UpdateStoreCommand struct {
+	storesBackend storage.StoresBackend
+	logger        logger.Logger
+}
+
+type UpdateStoreCmdOption func(*UpdateStoreCommand)
+
+func WithUpdateStoreCmdLogger(l logger.Logger) UpdateStoreCmdOption {
+	return func(c *UpdateStoreCommand) {
+		c.logger = l
+	}
+}
+
+func NewUpdateStoreCommand(
+	storesBackend storage.StoresBackend,
+	opts ...UpdateStoreCmdOption,
+) *UpdateStoreCommand {
+	cmd := &UpdateStoreCommand{
+		storesBackend: storesBackend,
+		logger:        logger.NewNoopLogger(),
+	}
+	for _, opt := range opts {
+		opt(cmd)
+	}
+	return cmd
+}
+
+// Execute replaces the labels of the store with labels. An empty labels removes them all.
+func (s *UpdateStoreCommand) Execute(ctx context.Context, storeID string, labels map[string]string) error {
+	if err := ValidateStoreLabels(labels); err != nil {
+		return err
+	}
+
+	if err := s.storesBackend.WriteStoreLabels(ctx, storeID, labels); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return serverErrors.ErrStoreIDNotFound
+		}
+		return serverErrors.HandleError("", err)
+	}
+	return nil
+}
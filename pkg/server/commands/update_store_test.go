@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/openfga/openfga/internal/mocks"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+func TestValidateStoreLabels(t *testing.T) {
+	tooMany := make(map[string]string, MaxStoreLabels+1)
+	for i := 0; i <= MaxStoreLabels; i++ {
+		tooMany[ulid.Make().String()] = "v"
+	}
+
+	tests := map[string]struct {
+		labels        map[string]string
+		expectedError string
+	}{
+		"no_labels":          {},
+		"valid_labels":       {labels: map[string]string{"env": "prod", "team.name": "iam-core", "empty": ""}},
+		"too_many_labels":    {labels: tooMany, expectedError: "cannot have more than 32 labels"},
+		"invalid_key":        {labels: map[string]string{"-env": "prod"}, expectedError: "invalid store label key '-env'"},
+		"key_with_separator": {labels: map[string]string{"env=x": "prod"}, expectedError: "invalid store label key"},
+		"value_too_long":     {labels: map[string]string{"env": strings.Repeat("a", MaxStoreLabelValueLength+1)}, expectedError: "cannot be longer than 255 characters"},
+		"key_of_63_chars":    {labels: map[string]string{strings.Repeat("a", 63): "v"}},
+		"key_longer_than_63": {labels: map[string]string{strings.Repeat("a", 64): "v"}, expectedError: "invalid store label key"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateStoreLabels(test.labels)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestUpdateStoreCommand(t *testing.T) {
+	storeID := ulid.Make().String()
+	labels := map[string]string{"env": "prod"}
+
+	t.Run("writes_the_labels", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().WriteStoreLabels(gomock.Any(), storeID, labels).Times(1).Return(nil)
+
+		err := NewUpdateStoreCommand(mockDatastore).Execute(context.Background(), storeID, labels)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid_labels_are_not_written", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+
+		err := NewUpdateStoreCommand(mockDatastore).Execute(context.Background(), storeID, map[string]string{"": "prod"})
+		require.ErrorContains(t, err, "invalid store label key")
+	})
+
+	t.Run("unknown_store_returns_store_id_not_found", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().WriteStoreLabels(gomock.Any(), storeID, labels).Times(1).Return(storage.ErrNotFound)
+
+		err := NewUpdateStoreCommand(mockDatastore).Execute(context.Background(), storeID, labels)
+		require.ErrorIs(t, err, serverErrors.ErrStoreIDNotFound)
+	})
+}
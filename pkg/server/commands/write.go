@@ -25,6 +25,9 @@ type WriteCommand struct {
 	logger                    logger.Logger
 	datastore                 storage.OpenFGADatastore
 	conditionContextByteLimit int
+	protectedTuples           *ProtectedTuples
+	maxTuplesPerWrite         int
+	typesystemResolver        typesystem.TypesystemResolverFunc
 }
 
 type WriteCommandOption func(*WriteCommand)
@@ -41,6 +44,29 @@ func WithConditionContextByteLimit(limit int) WriteCommandOption {
 	}
 }
 
+// WithProtectedTuples rejects deletes of the given protected tuples, unless forced by a privileged principal.
+func WithProtectedTuples(p *ProtectedTuples) WriteCommandOption {
+	return func(wc *WriteCommand) {
+		wc.protectedTuples = p
+	}
+}
+
+// WithWriteCmdMaxTuplesPerWrite lowers the maximum number of tuples written and deleted by a request below the limit
+// of the datastore.
+func WithWriteCmdMaxTuplesPerWrite(maxTuples int) WriteCommandOption {
+	return func(wc *WriteCommand) {
+		wc.maxTuplesPerWrite = maxTuples
+	}
+}
+
+// WithWriteCmdTypesystemResolver validates the tuples written against the models resolved by resolver, rather than
+// read from the datastore.
+func WithWriteCmdTypesystemResolver(resolver typesystem.TypesystemResolverFunc) WriteCommandOption {
+	return func(wc *WriteCommand) {
+		wc.typesystemResolver = resolver
+	}
+}
+
 // NewWriteCommand creates a WriteCommand with specified storage.OpenFGADatastore to use for storage.
 func NewWriteCommand(datastore storage.OpenFGADatastore, opts ...WriteCommandOption) *WriteCommand {
 	cmd := &WriteCommand{
@@ -80,6 +106,37 @@ func (c *WriteCommand) Execute(ctx context.Context, req *openfgav1.WriteRequest)
 	return &openfgav1.WriteResponse{}, nil
 }
 
+// resolveTypesystem returns the model the tuples written are validated against.
+func (c *WriteCommand) resolveTypesystem(ctx context.Context, store, modelID string) (*typesystem.TypeSystem, error) {
+	if c.typesystemResolver != nil {
+		typesys, err := c.typesystemResolver(ctx, store, modelID)
+		if err != nil {
+			if errors.Is(err, typesystem.ErrModelNotFound) {
+				return nil, serverErrors.AuthorizationModelNotFound(modelID)
+			}
+			if errors.Is(err, typesystem.ErrInvalidModel) {
+				return nil, serverErrors.ValidationError(err)
+			}
+			return nil, serverErrors.HandleError("", err)
+		}
+		return typesys, nil
+	}
+
+	authModel, err := c.datastore.ReadAuthorizationModel(ctx, store, modelID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, serverErrors.AuthorizationModelNotFound(modelID)
+		}
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	if !typesystem.IsSchemaVersionSupported(authModel.GetSchemaVersion()) {
+		return nil, serverErrors.ValidationError(typesystem.ErrInvalidSchemaVersion)
+	}
+
+	return typesystem.New(authModel)
+}
+
 func (c *WriteCommand) validateWriteRequest(ctx context.Context, req *openfgav1.WriteRequest) error {
 	ctx, span := tracer.Start(ctx, "validateWriteRequest")
 	defer span.End()
@@ -94,19 +151,7 @@ func (c *WriteCommand) validateWriteRequest(ctx context.Context, req *openfgav1.
 	}
 
 	if len(writes) > 0 {
-		authModel, err := c.datastore.ReadAuthorizationModel(ctx, store, modelID)
-		if err != nil {
-			if errors.Is(err, storage.ErrNotFound) {
-				return serverErrors.AuthorizationModelNotFound(modelID)
-			}
-			return serverErrors.HandleError("", err)
-		}
-
-		if !typesystem.IsSchemaVersionSupported(authModel.GetSchemaVersion()) {
-			return serverErrors.ValidationError(typesystem.ErrInvalidSchemaVersion)
-		}
-
-		typesys, err := typesystem.New(authModel)
+		typesys, err := c.resolveTypesystem(ctx, store, modelID)
 		if err != nil {
 			return err
 		}
@@ -142,6 +187,10 @@ func (c *WriteCommand) validateWriteRequest(ctx context.Context, req *openfgav1.
 				},
 			)
 		}
+
+		if err := c.protectedTuples.validateDelete(ctx, tk); err != nil {
+			return err
+		}
 	}
 
 	if err := c.validateNoDuplicatesAndCorrectSize(deletes, writes); err != nil {
@@ -174,8 +223,12 @@ func (c *WriteCommand) validateNoDuplicatesAndCorrectSize(
 		tuples[key] = struct{}{}
 	}
 
-	if len(tuples) > c.datastore.MaxTuplesPerWrite() {
-		return serverErrors.ExceededEntityLimit("write operations", c.datastore.MaxTuplesPerWrite())
+	maxTuples := c.datastore.MaxTuplesPerWrite()
+	if c.maxTuplesPerWrite > 0 && c.maxTuplesPerWrite < maxTuples {
+		maxTuples = c.maxTuplesPerWrite
+	}
+	if len(tuples) > maxTuples {
+		return serverErrors.ExceededEntityLimit("write operations", maxTuples)
 	}
 	return nil
 }
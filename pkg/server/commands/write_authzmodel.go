@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/oklog/ulid/v2"
@@ -10,6 +11,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
 
 	"github.com/openfga/openfga/pkg/logger"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
@@ -23,6 +25,7 @@ type WriteAuthorizationModelCommand struct {
 	backend                          storage.TypeDefinitionWriteBackend
 	logger                           logger.Logger
 	maxAuthorizationModelSizeInBytes int
+	dsl                              string
 }
 
 type WriteAuthModelOption func(*WriteAuthorizationModelCommand)
@@ -39,6 +42,14 @@ func WithWriteAuthModelMaxSizeInBytes(size int) WriteAuthModelOption {
 	}
 }
 
+// WithWriteAuthModelDSL makes the command write the model described by dsl, in the FGA DSL, instead of the schema
+// version, type definitions and conditions of the request, which must be left empty.
+func WithWriteAuthModelDSL(dsl string) WriteAuthModelOption {
+	return func(m *WriteAuthorizationModelCommand) {
+		m.dsl = dsl
+	}
+}
+
 func NewWriteAuthorizationModelCommand(backend storage.TypeDefinitionWriteBackend, opts ...WriteAuthModelOption) *WriteAuthorizationModelCommand {
 	model := &WriteAuthorizationModelCommand{
 		backend:                          backend,
@@ -54,9 +65,24 @@ func NewWriteAuthorizationModelCommand(backend storage.TypeDefinitionWriteBacken
 
 // Execute the command using the supplied request.
 func (w *WriteAuthorizationModelCommand) Execute(ctx context.Context, req *openfgav1.WriteAuthorizationModelRequest) (*openfgav1.WriteAuthorizationModelResponse, error) {
+	resp, _, err := w.ExecuteWithWarnings(ctx, req)
+	return resp, err
+}
+
+// ExecuteWithWarnings executes the command like Execute, and also returns the non-fatal diagnostics of the model
+// written. See typesystem.TypeSystem.Warnings.
+func (w *WriteAuthorizationModelCommand) ExecuteWithWarnings(ctx context.Context, req *openfgav1.WriteAuthorizationModelRequest) (*openfgav1.WriteAuthorizationModelResponse, []typesystem.Warning, error) {
+	if w.dsl != "" {
+		var err error
+		req, err = w.requestFromDSL(req)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Until this is solved: https://github.com/envoyproxy/protoc-gen-validate/issues/74
 	if len(req.GetTypeDefinitions()) > w.backend.MaxTypesPerAuthorizationModel() {
-		return nil, serverErrors.ExceededEntityLimit("type definitions in an authorization model", w.backend.MaxTypesPerAuthorizationModel())
+		return nil, nil, serverErrors.ExceededEntityLimit("type definitions in an authorization model", w.backend.MaxTypesPerAuthorizationModel())
 	}
 
 	// Fill in the schema version for old requests, which don't contain it, while we migrate to the new schema version.
@@ -75,24 +101,48 @@ func (w *WriteAuthorizationModelCommand) Execute(ctx context.Context, req *openf
 	modelSize := proto.Size(model)
 	if modelSize > w.maxAuthorizationModelSizeInBytes {
 		// Consider using serverErrors.ExceededEntityLimit.
-		return nil, status.Error(
+		return nil, nil, status.Error(
 			codes.Code(openfgav1.ErrorCode_exceeded_entity_limit),
 			fmt.Sprintf("model exceeds size limit: %d bytes vs %d bytes", modelSize, w.maxAuthorizationModelSizeInBytes),
 		)
 	}
 
-	_, err := typesystem.NewAndValidate(ctx, model)
+	typesys, err := typesystem.NewAndValidate(ctx, model)
 	if err != nil {
-		return nil, serverErrors.InvalidAuthorizationModelInput(err)
+		return nil, nil, serverErrors.InvalidAuthorizationModelInput(err)
 	}
 
 	err = w.backend.WriteAuthorizationModel(ctx, req.GetStoreId(), model)
 	if err != nil {
-		return nil, serverErrors.
+		return nil, nil, serverErrors.
 			HandleError("Error writing authorization model configuration", err)
 	}
 
 	return &openfgav1.WriteAuthorizationModelResponse{
 		AuthorizationModelId: model.GetId(),
-	}, nil
+	}, typesys.Warnings(), nil
+}
+
+// requestFromDSL returns req with the schema version, type definitions and conditions of the model described by
+// the DSL of the command.
+func (w *WriteAuthorizationModelCommand) requestFromDSL(req *openfgav1.WriteAuthorizationModelRequest) (*openfgav1.WriteAuthorizationModelRequest, error) {
+	if len(req.GetTypeDefinitions()) > 0 || len(req.GetConditions()) > 0 {
+		return nil, serverErrors.ValidationError(errors.New("the type definitions and conditions of the request cannot be combined with a DSL"))
+	}
+
+	parsed, err := parser.TransformDSLToProto(w.dsl)
+	if err != nil {
+		return nil, serverErrors.InvalidAuthorizationModelInput(err)
+	}
+
+	dslReq := &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:         req.GetStoreId(),
+		SchemaVersion:   parsed.GetSchemaVersion(),
+		TypeDefinitions: parsed.GetTypeDefinitions(),
+		Conditions:      parsed.GetConditions(),
+	}
+	if err := dslReq.Validate(); err != nil {
+		return nil, serverErrors.ValidationError(err)
+	}
+	return dslReq, nil
 }
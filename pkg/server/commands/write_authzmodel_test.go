@@ -1263,6 +1263,97 @@ func TestWriteAuthorizationModel(t *testing.T) {
 	}
 }
 
+func TestWriteAuthorizationModelFromDSL(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	dsl := `
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user with non_expired]
+
+		condition non_expired(expires_at: timestamp, now: timestamp) {
+			now < expires_at
+		}`
+
+	t.Run("writes_the_model_of_the_dsl", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().MaxTypesPerAuthorizationModel().AnyTimes().Return(100)
+		mockDatastore.EXPECT().WriteAuthorizationModel(gomock.Any(), storeID, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ string, model *openfgav1.AuthorizationModel) error {
+				require.Equal(t, typesystem.SchemaVersion1_1, model.GetSchemaVersion())
+				require.Len(t, model.GetTypeDefinitions(), 2)
+				require.Contains(t, model.GetConditions(), "non_expired")
+				return nil
+			})
+
+		resp, err := NewWriteAuthorizationModelCommand(mockDatastore, WithWriteAuthModelDSL(dsl)).
+			Execute(ctx, &openfgav1.WriteAuthorizationModelRequest{StoreId: storeID})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.GetAuthorizationModelId())
+	})
+
+	t.Run("invalid_dsl_is_rejected", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+
+		_, err := NewWriteAuthorizationModelCommand(mockDatastore, WithWriteAuthModelDSL("model\n  schema 1.1\ntype")).
+			Execute(ctx, &openfgav1.WriteAuthorizationModelRequest{StoreId: storeID})
+		require.Equal(t, codes.Code(openfgav1.ErrorCode_invalid_authorization_model), status.Code(err))
+	})
+
+	t.Run("dsl_cannot_be_combined_with_type_definitions", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+
+		_, err := NewWriteAuthorizationModelCommand(mockDatastore, WithWriteAuthModelDSL(dsl)).
+			Execute(ctx, &openfgav1.WriteAuthorizationModelRequest{
+				StoreId:         storeID,
+				TypeDefinitions: []*openfgav1.TypeDefinition{{Type: "user"}},
+			})
+		require.ErrorContains(t, err, "cannot be combined with a DSL")
+	})
+}
+
+func TestWriteAuthorizationModelWithWarnings(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+	mockDatastore.EXPECT().MaxTypesPerAuthorizationModel().AnyTimes().Return(100)
+	mockDatastore.EXPECT().WriteAuthorizationModel(gomock.Any(), storeID, gomock.Any()).Times(1).Return(nil)
+
+	resp, warnings, err := NewWriteAuthorizationModelCommand(mockDatastore, WithWriteAuthModelDSL(`
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user:*]
+				define can_view: viewer`)).
+		ExecuteWithWarnings(ctx, &openfgav1.WriteAuthorizationModelRequest{StoreId: storeID})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.GetAuthorizationModelId())
+	require.Equal(t, []typesystem.Warning{{
+		Kind:       typesystem.WarningBroadWildcard,
+		ObjectType: "document",
+		Relation:   "viewer",
+		Message:    "'user:*' also grants every user the relations derived from this one: document#can_view",
+	}}, warnings)
+}
+
 func buildModelWithManyTypes(maxTypesPerAuthorizationModel int) []*openfgav1.TypeDefinition {
 	items := make([]*openfgav1.TypeDefinition, maxTypesPerAuthorizationModel+1)
 	items[0] = &openfgav1.TypeDefinition{
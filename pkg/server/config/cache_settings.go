@@ -10,15 +10,21 @@ type CacheSettings struct {
 	CacheControllerTTL                 time.Duration
 	CheckQueryCacheEnabled             bool
 	CheckQueryCacheTTL                 time.Duration
+	CheckQueryCacheRevalidationWindow  time.Duration
+	CheckQueryCacheRevalidationMinHits uint32
 	CheckIteratorCacheEnabled          bool
 	CheckIteratorCacheMaxResults       uint32
 	CheckIteratorCacheTTL              time.Duration
+	CheckTupleCacheEnabled             bool
+	CheckTupleCacheTTL                 time.Duration
 	ListObjectsIteratorCacheEnabled    bool
 	ListObjectsIteratorCacheMaxResults uint32
 	ListObjectsIteratorCacheTTL        time.Duration
 	SharedIteratorEnabled              bool
 	SharedIteratorLimit                uint32
 	SharedIteratorTTL                  time.Duration
+	RequestIteratorCacheEnabled        bool
+	RequestIteratorCacheMaxResults     uint32
 }
 
 func NewDefaultCacheSettings() CacheSettings {
@@ -28,20 +34,26 @@ func NewDefaultCacheSettings() CacheSettings {
 		CacheControllerTTL:                 DefaultCacheControllerTTL,
 		CheckQueryCacheEnabled:             DefaultCheckQueryCacheEnabled,
 		CheckQueryCacheTTL:                 DefaultCheckQueryCacheTTL,
+		CheckQueryCacheRevalidationWindow:  DefaultCheckQueryCacheRevalidationWindow,
+		CheckQueryCacheRevalidationMinHits: DefaultCheckQueryCacheRevalidationMinHits,
 		CheckIteratorCacheEnabled:          DefaultCheckIteratorCacheEnabled,
 		CheckIteratorCacheMaxResults:       DefaultCheckIteratorCacheMaxResults,
 		CheckIteratorCacheTTL:              DefaultCheckIteratorCacheTTL,
+		CheckTupleCacheEnabled:             DefaultCheckTupleCacheEnabled,
+		CheckTupleCacheTTL:                 DefaultCheckTupleCacheTTL,
 		ListObjectsIteratorCacheEnabled:    DefaultListObjectsIteratorCacheEnabled,
 		ListObjectsIteratorCacheMaxResults: DefaultListObjectsIteratorCacheMaxResults,
 		ListObjectsIteratorCacheTTL:        DefaultListObjectsIteratorCacheTTL,
 		SharedIteratorEnabled:              DefaultSharedIteratorEnabled,
 		SharedIteratorLimit:                DefaultSharedIteratorLimit,
 		SharedIteratorTTL:                  DefaultSharedIteratorTTL,
+		RequestIteratorCacheEnabled:        DefaultRequestIteratorCacheEnabled,
+		RequestIteratorCacheMaxResults:     DefaultRequestIteratorCacheMaxResults,
 	}
 }
 
 func (c CacheSettings) ShouldCreateNewCache() bool {
-	return c.ShouldCacheCheckQueries() || c.ShouldCacheCheckIterators() || c.ShouldCacheListObjectsIterators()
+	return c.ShouldCacheCheckQueries() || c.ShouldCacheCheckIterators() || c.ShouldCacheCheckTuples() || c.ShouldCacheListObjectsIterators()
 }
 
 func (c CacheSettings) ShouldCreateCacheController() bool {
@@ -56,6 +68,14 @@ func (c CacheSettings) ShouldCacheCheckIterators() bool {
 	return c.CheckCacheLimit > 0 && c.CheckIteratorCacheEnabled
 }
 
+func (c CacheSettings) ShouldCacheCheckTuples() bool {
+	return c.CheckCacheLimit > 0 && c.CheckTupleCacheEnabled
+}
+
 func (c CacheSettings) ShouldCacheListObjectsIterators() bool {
 	return c.ListObjectsIteratorCacheEnabled && c.ListObjectsIteratorCacheMaxResults > 0
 }
+
+func (c CacheSettings) ShouldCacheRequestIterators() bool {
+	return c.RequestIteratorCacheEnabled && c.RequestIteratorCacheMaxResults > 0
+}
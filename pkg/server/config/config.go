@@ -6,30 +6,43 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
 )
 
 const (
 	DefaultMaxRPCMessageSizeInBytes         = 512 * 1_204 // 512 KB
 	DefaultMaxTuplesPerWrite                = 100
+	DefaultMaxPageSize                      = 100
 	DefaultMaxTypesPerAuthorizationModel    = 100
 	DefaultMaxAuthorizationModelSizeInBytes = 256 * 1_024
 	DefaultMaxAuthorizationModelCacheSize   = 100000
 	DefaultChangelogHorizonOffset           = 0
+	DefaultContinuationTokenTTL             = 0
 	DefaultResolveNodeLimit                 = 25
 	DefaultResolveNodeBreadthLimit          = 10
+	DefaultCheckDirectTupleBatchSize        = 0
+	DefaultCheckDirectTupleBatchMaxDelay    = time.Millisecond
+	DefaultCheckDispatchPoolSize            = 0
+	DefaultCheckUnionBranchOrderingEnabled  = false
 	DefaultUsersetBatchSize                 = 1000
 	DefaultListObjectsDeadline              = 3 * time.Second
 	DefaultListObjectsMaxResults            = 1000
+	DefaultListObjectsDatastoreQueryBudget  = 0
 	DefaultMaxConcurrentReadsForCheck       = math.MaxUint32
 	DefaultMaxConcurrentReadsForListObjects = math.MaxUint32
 	DefaultListUsersDeadline                = 3 * time.Second
 	DefaultListUsersMaxResults              = 1000
 	DefaultMaxConcurrentReadsForListUsers   = math.MaxUint32
 
+	DefaultContinuationTokenUnversionedWindow = 0 // 0 means the unversioned tokens are rejected once the tokens expire
+
 	DefaultWriteContextByteLimit = 32 * 1_024 // 32KB
 
 	DefaultCheckCacheLimit = 10000
@@ -37,13 +50,18 @@ const (
 	DefaultCacheControllerEnabled = false
 	DefaultCacheControllerTTL     = 10 * time.Second
 
-	DefaultCheckQueryCacheEnabled = false
-	DefaultCheckQueryCacheTTL     = 10 * time.Second
+	DefaultCheckQueryCacheEnabled             = false
+	DefaultCheckQueryCacheTTL                 = 10 * time.Second
+	DefaultCheckQueryCacheRevalidationWindow  = 0 // 0 means background revalidation is disabled
+	DefaultCheckQueryCacheRevalidationMinHits = 10
 
 	DefaultCheckIteratorCacheEnabled    = false
 	DefaultCheckIteratorCacheMaxResults = 10000
 	DefaultCheckIteratorCacheTTL        = 10 * time.Second
 
+	DefaultCheckTupleCacheEnabled = false
+	DefaultCheckTupleCacheTTL     = 10 * time.Second
+
 	DefaultListObjectsIteratorCacheEnabled    = false
 	DefaultListObjectsIteratorCacheMaxResults = 10000
 	DefaultListObjectsIteratorCacheTTL        = 10 * time.Second
@@ -63,6 +81,10 @@ const (
 	DefaultMaxConditionEvaluationCost = 100
 	DefaultInterruptCheckFrequency    = 100
 
+	DefaultMaxContextSizeBytes = 0 // 0 means no limit
+	DefaultMaxContextDepth     = 0 // 0 means no limit
+	DefaultMaxContextualTuples = 0 // 0 means no limit
+
 	DefaultCheckDispatchThrottlingEnabled          = false
 	DefaultCheckDispatchThrottlingFrequency        = 10 * time.Microsecond
 	DefaultCheckDispatchThrottlingDefaultThreshold = 100
@@ -89,6 +111,56 @@ const (
 	DefaultSharedIteratorLimit            = 1000000
 	DefaultSharedIteratorTTL              = 4 * time.Minute
 	DefaultSharedIteratorMaxAdmissionTime = 10 * time.Second
+
+	DefaultRequestIteratorCacheEnabled    = false
+	DefaultRequestIteratorCacheMaxResults = 1000
+
+	DefaultDatastoreLimiterSaturationThreshold        = 0 // 0 means saturation detection is disabled
+	DefaultDatastoreLimiterSaturationPeriod           = 30 * time.Second
+	DefaultDatastoreLimiterSaturationRatio            = 0.5
+	DefaultDatastoreLimiterSaturationReadinessEnabled = false
+
+	DefaultDatastoreReadPrioritySlots = 0 // 0 means the reads are not arbitrated by priority
+
+	DefaultCheckMembershipIndexRefreshInterval = time.Second
+	DefaultCheckMembershipIndexMaxStaleness    = 10 * time.Second
+
+	DefaultCheckMaterializedViewsRefreshInterval = 5 * time.Second
+	DefaultCheckMaterializedViewsMaxStaleness    = time.Minute
+
+	DefaultDatastoreCircuitBreakerFailureRateThreshold = 0 // 0 means the circuit breaker is disabled
+	DefaultDatastoreCircuitBreakerMinRequests          = 20
+	DefaultDatastoreCircuitBreakerWindow               = 10 * time.Second
+	DefaultDatastoreCircuitBreakerOpenDuration         = 30 * time.Second
+
+	DefaultDatastoreRetryConnectionMaxRetries    = 0 // 0 means connection errors are not retried
+	DefaultDatastoreRetrySerializationMaxRetries = 0
+	DefaultDatastoreRetryDeadlockMaxRetries      = 0
+	DefaultDatastoreRetryInitialInterval         = 20 * time.Millisecond
+	DefaultDatastoreRetryMaxInterval             = 500 * time.Millisecond
+	DefaultDatastoreRetryWrites                  = false
+
+	DefaultDatastoreFaultInjectionLatency         = 0
+	DefaultDatastoreFaultInjectionErrorRate       = 0.0
+	DefaultDatastoreFaultInjectionIteratorLatency = 0
+
+	DefaultLoadSheddingEnabled         = false
+	DefaultLoadSheddingMaxInFlightCost = 10000
+
+	DefaultLimitOverridesEnabled = false
+
+	DefaultAuthorizationModelRetentionCount         = 0 // 0 means models are kept forever
+	DefaultAuthorizationModelRetentionPruneInterval = time.Hour
+
+	DefaultTrustedContextCurrentTimePrecision = time.Second
+
+	DefaultModelSyncInterval = time.Minute
+
+	DefaultMaxConcurrentJobs = 2
+	DefaultMaxJobTuples      = 100000
+
+	DefaultTypesystemCacheTTL  = 168 * time.Hour // 7 days
+	DefaultTypesystemCacheSize = 10000
 )
 
 type DatastoreMetricsConfig struct {
@@ -96,6 +168,15 @@ type DatastoreMetricsConfig struct {
 	Enabled bool
 }
 
+// DatastoreIAMAuthConfig defines the authentication to AWS RDS and Aurora datastores with IAM.
+type DatastoreIAMAuthConfig struct {
+	// Enabled opens the connections with IAM authentication tokens, signed with the credentials of the default AWS
+	// credential chain, instead of a password.
+	Enabled bool
+	// Region is the AWS region of the datastore.
+	Region string
+}
+
 // DatastoreConfig defines OpenFGA server configurations for datastore specific settings.
 type DatastoreConfig struct {
 	// Engine is the datastore engine to use (e.g. 'memory', 'postgres', 'mysql', 'sqlite')
@@ -104,6 +185,17 @@ type DatastoreConfig struct {
 	Username string
 	Password string `json:"-"` // private field, won't be logged
 
+	// UsernameFile and PasswordFile are files holding the connection username and password, taking precedence over
+	// Username and Password. They are read again every CredentialsRefreshInterval, so that rotated credentials are
+	// used by new connections without restarting the server. Only supported by the 'postgres' and 'mysql' engines.
+	UsernameFile               string
+	PasswordFile               string
+	CredentialsRefreshInterval time.Duration
+
+	// IAMAuth is the configuration of the IAM authentication to AWS RDS and Aurora. Only supported by the 'postgres'
+	// and 'mysql' engines.
+	IAMAuth DatastoreIAMAuthConfig
+
 	// MaxCacheSize is the maximum number of authorization models that will be cached in memory.
 	MaxCacheSize int
 
@@ -120,6 +212,40 @@ type DatastoreConfig struct {
 	// ConnMaxLifetime is the maximum amount of time a connection to the datastore may be reused.
 	ConnMaxLifetime time.Duration
 
+	// HedgeDelay is how long a Check read of tuples may take before an identical read is issued. 0 disables hedging.
+	HedgeDelay time.Duration
+
+	// MaxConcurrentReads is the maximum number of concurrent tuple reads of the whole server, whatever the number of
+	// requests, under the per-request limits. 0 means no limit.
+	MaxConcurrentReads uint32
+
+	// ReadReplicaURIs are the connection uris of the read replicas serving the tuple reads that do not require
+	// HIGHER_CONSISTENCY. Only supported by the 'postgres' and 'mysql' engines.
+	ReadReplicaURIs []string `json:"-"` // private field, won't be logged
+
+	// ReadTimeout is how long a tuple read may query the datastore before it is cancelled. 0 disables the timeout.
+	ReadTimeout time.Duration
+
+	// WriteTimeout is how long a tuple write may take before it is cancelled. 0 disables the timeout.
+	WriteTimeout time.Duration
+
+	// SlowQueryThreshold is how long a tuple read or write may take before it is logged as slow. 0 disables the log.
+	SlowQueryThreshold time.Duration
+
+	// SnapshotPath is the file the 'memory' engine is persisted to, loaded on start and saved every SnapshotInterval
+	// and on shutdown. If empty, the 'memory' engine is not persisted.
+	SnapshotPath string
+
+	// SnapshotInterval is how often the 'memory' engine is saved to SnapshotPath. 0 only saves it on shutdown.
+	SnapshotInterval time.Duration
+
+	// MemoryMaxTuplesPerStore is the maximum number of tuples of each store of the 'memory' engine. 0 means no limit.
+	MemoryMaxTuplesPerStore int
+
+	// MemoryMaxBytes is the maximum estimated memory held by the tuples and changes of the 'memory' engine.
+	// 0 means no limit.
+	MemoryMaxBytes int64
+
 	// Metrics is configuration for the Datastore metrics.
 	Metrics DatastoreMetricsConfig
 }
@@ -128,6 +254,43 @@ type DatastoreConfig struct {
 type GRPCConfig struct {
 	Addr string
 	TLS  *TLSConfig
+
+	// MaxRecvMsgSizeBytes is the maximum size of the messages the gRPC server receives, e.g. of the Write requests,
+	// including those of the Connect and gRPC-Web requests of the HTTP server.
+	MaxRecvMsgSizeBytes int
+
+	// MaxSendMsgSizeBytes is the maximum size of the messages the gRPC server sends.
+	MaxSendMsgSizeBytes int
+
+	// MaxConcurrentStreams is the maximum number of concurrent streams, i.e. of calls, per client connection. 0 means
+	// no limit.
+	MaxConcurrentStreams uint32
+
+	Keepalive GRPCKeepaliveConfig
+}
+
+// GRPCKeepaliveConfig defines the keepalive settings of the gRPC server, i.e. its pings of the clients and the pings
+// it accepts from them.
+type GRPCKeepaliveConfig struct {
+	// Time is the duration a connection is idle before the server pings the client.
+	Time time.Duration
+
+	// Timeout is the duration the server waits for the response to a ping before closing the connection.
+	Timeout time.Duration
+
+	// MaxConnectionAge is the duration after which the connections are closed, so that the clients reconnect through
+	// the load balancers. 0 means no limit.
+	MaxConnectionAge time.Duration
+
+	// MaxConnectionAgeGrace is the duration the calls in flight are given to complete once MaxConnectionAge is
+	// reached. 0 means no limit.
+	MaxConnectionAgeGrace time.Duration
+
+	// MinTime is the minimum duration between the pings of a client, which is disconnected if it pings more often.
+	MinTime time.Duration
+
+	// PermitWithoutStream allows the clients to ping when they have no call in flight.
+	PermitWithoutStream bool
 }
 
 // HTTPConfig defines OpenFGA server configurations for HTTP server specific settings.
@@ -142,6 +305,13 @@ type HTTPConfig struct {
 
 	CORSAllowedOrigins []string
 	CORSAllowedHeaders []string
+
+	// ConnectEnabled serves the OpenFGA service over the Connect protocol on the HTTP server, alongside the REST
+	// mapping of the HTTP gateway.
+	ConnectEnabled bool
+
+	// GRPCWebEnabled serves the OpenFGA service over the gRPC-Web protocol on the HTTP server, for the browsers.
+	GRPCWebEnabled bool
 }
 
 // TLSConfig defines configuration specific to Transport Layer Security (TLS) settings.
@@ -155,10 +325,12 @@ type TLSConfig struct {
 type AuthnConfig struct {
 
 	// Method is the authentication method that should be enforced (e.g. 'none', 'preshared',
-	// 'oidc')
+	// 'oidc', 'storetoken', 'mtls')
 	Method                   string
 	*AuthnOIDCConfig         `mapstructure:"oidc"`
 	*AuthnPresharedKeyConfig `mapstructure:"preshared"`
+	*AuthnStoreTokenConfig   `mapstructure:"storetoken"`
+	*AuthnMTLSConfig         `mapstructure:"mtls"`
 }
 
 // AuthnOIDCConfig defines configurations for the 'oidc' method of authentication.
@@ -168,6 +340,14 @@ type AuthnOIDCConfig struct {
 	Subjects       []string
 	Audience       string
 	ClientIDClaims []string
+	// StoreIDClaim is the claim holding the store id(s) a token is restricted to. If empty,
+	// tokens are not restricted to any store.
+	StoreIDClaim string
+	// AdditionalIssuers are other OIDC issuers whose tokens are accepted, each with its own keys.
+	AdditionalIssuers []string
+	// AdditionalAudiences are the audiences of the AdditionalIssuers, paired by position: the tokens of
+	// AdditionalIssuers[i] must have the audience AdditionalAudiences[i].
+	AdditionalAudiences []string
 }
 
 // AuthnPresharedKeyConfig defines configurations for the 'preshared' method of authentication.
@@ -176,6 +356,20 @@ type AuthnPresharedKeyConfig struct {
 	Keys []string `json:"-"` // private field, won't be logged
 }
 
+// AuthnMTLSConfig defines configurations for the 'mtls' method of authentication.
+type AuthnMTLSConfig struct {
+	// ClientCAPath is the path of the PEM encoded bundle of certificate authorities trusted to issue
+	// client certificates. The bundle is reloaded when it changes.
+	ClientCAPath string `mapstructure:"clientCA"`
+}
+
+// AuthnStoreTokenConfig defines configurations for the 'storetoken' method of authentication.
+type AuthnStoreTokenConfig struct {
+	// SigningKeys define the keys used to verify store tokens. The first key is expected to be
+	// the one currently used to mint tokens; the others are accepted to allow key rotation.
+	SigningKeys []string `json:"-"` // private field, won't be logged
+}
+
 // LogConfig defines OpenFGA server configurations for log specific settings. For production, we
 // recommend using the 'json' log format.
 type LogConfig struct {
@@ -187,13 +381,76 @@ type LogConfig struct {
 
 	// Format of the timestamp in the log output (e.g. 'Unix'(default) or 'ISO8601')
 	TimestampFormat string
+
+	// Sampling limits the entries of a repeated message written per second.
+	Sampling LogSamplingConfig
+
+	// OutputPaths are the files or URLs to write the logs to, e.g. 'stdout' or '/var/log/openfga.log'.
+	OutputPaths []string
+
+	// Rotation rotates the log files of OutputPaths.
+	Rotation LogRotationConfig
+
+	// Security writes the security events, e.g. the authentication failures, to their own sink.
+	Security SecurityLogConfig
+}
+
+// SecurityLogConfig defines the sink of the security events: the authentication failures, the authorization denials
+// and the store deletions. They are written in Format to OutputPaths, instead of with the other log messages, so that
+// they can be kept separately.
+type SecurityLogConfig struct {
+	Enabled     bool
+	Format      string
+	OutputPaths []string
+}
+
+// LogRotationConfig defines the rotation of the log files: a file is rotated once it reaches MaxSize megabytes, and
+// the rotated files are removed after MaxAge or beyond MaxBackups, if set, and else compressed if Compress is set.
+type LogRotationConfig struct {
+	Enabled    bool
+	MaxSize    int
+	MaxAge     time.Duration
+	MaxBackups int
+	Compress   bool
+}
+
+// LogSamplingConfig defines the sampling of the repeated log messages: in each second, the first PerSecond entries of
+// a message are written, and then every Thereafter-th entry, or none if Thereafter is 0.
+type LogSamplingConfig struct {
+	Enabled    bool
+	PerSecond  int
+	Thereafter int
+}
+
+// AccessLogConfig defines the access log, i.e. one structured log line per request.
+type AccessLogConfig struct {
+	Enabled bool
+	// Fields are the fields of the access log lines, or all of them if empty: 'method', 'request_id', 'store_id',
+	// 'authorization_model_id', 'peer', 'latency_ms', 'code', 'datastore_query_count' and 'dispatch_count'.
+	Fields []string
+	// SkippedMethods are the methods not logged, e.g. 'Check' or 'grpc.health.v1.Health/Check'.
+	SkippedMethods []string
+}
+
+// RedactionConfig defines the redaction of the ids of the users and objects, and of the values of the condition
+// contexts, in the spans, logs and error messages: 'off', 'hash' to replace them by their keyed hash, or 'drop' to
+// replace them by '?'.
+type RedactionConfig struct {
+	Mode string
+	// HashKey is the secret key of the hashes of the 'hash' mode.
+	HashKey string `json:"-"` // private field, won't be logged
 }
 
 type TraceConfig struct {
 	Enabled     bool
 	OTLP        OTLPTraceConfig `mapstructure:"otlp"`
 	SampleRatio float64
-	ServiceName string
+	// MethodSampleRatios overrides SampleRatio for specific API methods, in the form '<method>:<ratio>', e.g.
+	// 'Check:0.01'. The method can be a prefix ending with '*', e.g. 'Write*:1'.
+	MethodSampleRatios []string
+	// SampleErrors exports the failed spans of the traces that are not sampled.
+	SampleErrors bool
+	ServiceName  string
 }
 
 type OTLPTraceConfig struct {
@@ -217,17 +474,64 @@ type ProfilerConfig struct {
 	Addr    string
 }
 
+// AdminConfig defines the admin HTTP server, serving the operational actions of the server, e.g. flushing its caches.
+type AdminConfig struct {
+	Enabled bool
+	// Addr is the address of the admin server, on the loopback interface by default. It is served with the TLS
+	// settings of the HTTP server.
+	Addr string
+	// Keys are the preshared keys the requests are authenticated with, as bearer tokens. They are distinct from
+	// the keys of the API.
+	Keys []string `json:"-"` // private field, won't be logged
+}
+
 // MetricConfig defines configurations for serving custom metrics from OpenFGA.
 type MetricConfig struct {
 	Enabled             bool
 	Addr                string
 	EnableRPCHistograms bool
+	// OTLP exports the metrics to an OTLP receiver, in addition to or instead of serving them to Prometheus.
+	OTLP OTLPMetricsConfig `mapstructure:"otlp"`
+	// StoreIDLabel labels the request duration and datastore query count histograms with the store id.
+	StoreIDLabel MetricsStoreIDLabelConfig
+}
+
+// MetricsStoreIDLabelConfig defines the configuration of the store id label of the request metrics. Its cardinality
+// is bounded by only labelling the Stores with their id or, if empty, the Limit stores with the most requests. The
+// requests of the other stores are labelled 'other'.
+type MetricsStoreIDLabelConfig struct {
+	Enabled bool
+	Stores  []string
+	Limit   int
+}
+
+// OTLPMetricsConfig defines the configuration of the export of the metrics to an OTLP receiver.
+type OTLPMetricsConfig struct {
+	Enabled  bool
+	Endpoint string
+	// Protocol is the protocol of the export, 'grpc' or 'http'.
+	Protocol string
+	// Interval is how often the metrics are exported.
+	Interval time.Duration
+	// Headers are sent with the exports, in the form '<name>=<value>', e.g. 'Authorization=Bearer <token>' to
+	// authenticate with the receiver.
+	Headers []string `json:"-"` // private field, won't be logged
+	TLS     OTLPMetricsTLSConfig
+}
+
+type OTLPMetricsTLSConfig struct {
+	Enabled bool
 }
 
 // CheckQueryCache defines configuration for caching when resolving check.
 type CheckQueryCache struct {
 	Enabled bool
 	TTL     time.Duration
+	// RevalidationWindow is the period before expiry during which a hit recomputes a frequently hit entry
+	// in the background. 0 disables background revalidation.
+	RevalidationWindow time.Duration
+	// RevalidationMinHits is the number of hits after which an entry is revalidated in the background.
+	RevalidationMinHits uint32
 }
 
 // CheckCacheConfig defines configuration for a cache that is shared across Check requests.
@@ -242,17 +546,33 @@ type IteratorCacheConfig struct {
 	TTL        time.Duration
 }
 
+// TupleCacheConfig defines configuration to cache single tuples read from storage.
+type TupleCacheConfig struct {
+	Enabled bool
+	TTL     time.Duration
+}
+
 // SharedIteratorConfig defines configuration to share storage iterator.
 type SharedIteratorConfig struct {
 	Enabled bool
 	Limit   uint32
 }
 
+// RequestIteratorCacheConfig defines configuration to memoize storage iterator results within a single request.
+type RequestIteratorCacheConfig struct {
+	Enabled    bool
+	MaxResults uint32
+}
+
 // CacheControllerConfig defines configuration to manage cache invalidation dynamically by observing whether
 // there are recent tuple changes to specified store.
 type CacheControllerConfig struct {
 	Enabled bool
 	TTL     time.Duration
+
+	// NotificationChannel is the Postgres LISTEN/NOTIFY channel on which the tuple writes are notified to every
+	// server. If empty, the cache is invalidated on TTL only.
+	NotificationChannel string
 }
 
 // DispatchThrottlingConfig defines configurations for dispatch throttling.
@@ -270,6 +590,223 @@ type DatabaseThrottleConfig struct {
 	Duration  time.Duration
 }
 
+// DatastoreLimiterSaturationConfig defines configurations for detecting sustained saturation of the
+// datastore read concurrency limiter.
+type DatastoreLimiterSaturationConfig struct {
+	// Threshold is the time waiting for the limiter above which a read is considered saturated. 0 disables detection.
+	Threshold time.Duration
+	// Period is the window over which the fraction of the reads waiting above the threshold is computed.
+	Period time.Duration
+	// Ratio is the fraction of the reads of the period which must wait above the threshold for the limiter to be
+	// reported as saturated.
+	Ratio float64
+	// ReadinessEnabled makes the server report itself as not ready while the limiter is saturated.
+	ReadinessEnabled bool
+}
+
+// DatastoreReadPriorityConfig defines the server-wide limiter sharing the datastore reads between the methods of
+// the requests by their weights.
+type DatastoreReadPriorityConfig struct {
+	// Slots is the number of concurrent datastore reads of every request. 0 disables the limiter.
+	Slots uint32
+	// Weights are the weights of the methods sharing the slots, in the form '<method>:<weight>', e.g. 'Check:8'.
+	// If empty, Check and BatchCheck have a weight of 8, and the other methods a weight of 1.
+	Weights []string
+}
+
+// MembershipIndexConfig defines the index of the transitive members of nested relations, e.g.
+// 'define member: [user, group#member]', consulted by Check before resolving the graph.
+type MembershipIndexConfig struct {
+	// Relations are the indexed relations, in the form 'type#relation', e.g. 'group#member'. If empty, the index is
+	// disabled.
+	Relations []string
+	// RefreshInterval is how often the changes of the stores are applied to the index.
+	RefreshInterval time.Duration
+	// MaxStaleness is how far behind the changes of a store its index may be for Check to consult it.
+	MaxStaleness time.Duration
+}
+
+// MaterializedViewsConfig defines the experimental views of the users of hot relations of some objects, e.g.
+// 'org:acme#member', maintained in the background and consulted by Check before resolving the graph.
+type MaterializedViewsConfig struct {
+	// Views are the materialized views, in the form 'object#relation', e.g. 'org:acme#member'. If empty, the views are
+	// disabled.
+	Views []string
+	// RefreshInterval is how often the changes of the stores are checked, and their views recomputed if needed.
+	RefreshInterval time.Duration
+	// MaxStaleness is how far behind the changes of a store its views may be for Check to consult them.
+	MaxStaleness time.Duration
+}
+
+// ReadinessConfig defines the optional components the readiness of the server depends on, in addition to its
+// datastore.
+type ReadinessConfig struct {
+	// CheckCacheEnabled makes the server not ready while its check cache does not store and return entries.
+	CheckCacheEnabled bool
+	// TupleChangeListenerEnabled makes the server not ready while the tuple change notifications are interrupted.
+	TupleChangeListenerEnabled bool
+}
+
+// DatastoreCircuitBreakerConfig defines configurations for the circuit breaker failing datastore tuple reads and
+// writes fast while the datastore is failing.
+type DatastoreCircuitBreakerConfig struct {
+	// FailureRateThreshold is the rate of failed calls, between 0 and 1, above which the breaker trips. 0 disables it.
+	FailureRateThreshold float64
+	// MinRequests is the number of calls within a window below which the breaker never trips.
+	MinRequests int
+	// Window is the period over which the failure rate is computed.
+	Window time.Duration
+	// OpenDuration is how long the breaker fails calls fast before letting a single call probe the datastore.
+	OpenDuration time.Duration
+}
+
+// DatastoreRetryConfig defines configurations for retrying datastore tuple reads and writes failing with transient
+// errors.
+type DatastoreRetryConfig struct {
+	// ConnectionMaxRetries is the number of retries of calls failing with connection errors. 0 disables them.
+	ConnectionMaxRetries int
+	// SerializationMaxRetries is the number of retries of calls failing with serialization failures. 0 disables them.
+	SerializationMaxRetries int
+	// DeadlockMaxRetries is the number of retries of calls failing with deadlocks. 0 disables them.
+	DeadlockMaxRetries int
+	// InitialInterval is the delay before the first retry, doubled with jitter on each retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between retries.
+	MaxInterval time.Duration
+	// Writes also retries the writes rolled back by serialization failures and deadlocks.
+	Writes bool
+}
+
+// DatastoreFaultInjectionConfig defines configurations for injecting faults into datastore tuple reads and writes,
+// to test the resilience of the server. It requires the 'enable-fault-injection' experimental feature.
+type DatastoreFaultInjectionConfig struct {
+	// Operations are the datastore operations faults are injected into, e.g. 'ReadUserTuple'. If empty, faults are
+	// injected into all of them.
+	Operations []string
+	// Latency is added to every call.
+	Latency time.Duration
+	// ErrorRate is the fraction, between 0 and 1, of calls failing with an injected error.
+	ErrorRate float64
+	// IteratorLatency is added to every tuple read from the iterators of the calls.
+	IteratorLatency time.Duration
+	// Policies are the faults injected into specific operations, overriding the ones above, in the form
+	// '<operation>:<latency>:<error rate>:<iterator latency>', e.g. 'ReadUserTuple:50ms:0.1:0s'.
+	Policies []string
+}
+
+// DatastoreFaultPolicy defines the faults injected into a datastore operation.
+type DatastoreFaultPolicy struct {
+	Latency         time.Duration
+	ErrorRate       float64
+	IteratorLatency time.Duration
+}
+
+// Enabled returns true if any fault is injected.
+func (c DatastoreFaultInjectionConfig) Enabled() bool {
+	return c.Latency > 0 || c.ErrorRate > 0 || c.IteratorLatency > 0 || len(c.Policies) > 0
+}
+
+// ProtectedTuplesConfig defines configurations for placing tuples under legal hold.
+type ProtectedTuplesConfig struct {
+	// Patterns are the tuples that are protected, in the form 'type:id[#relation[@user]]'.
+	// The object id may be '*' to protect every object of the type.
+	Patterns []string
+	// PrivilegedPrincipals are the subjects or client ids allowed to force the deletion of protected tuples.
+	PrivilegedPrincipals []string
+}
+
+// LoadSheddingConfig defines configurations for shedding the least important requests while the server is overloaded.
+type LoadSheddingConfig struct {
+	Enabled bool
+	// MaxInFlightCost is the number of in-flight dispatches and datastore reads at which every request is shed.
+	// Lower priority requests, such as ListObjects, are shed from a lower share of it.
+	MaxInFlightCost int
+}
+
+// LimitOverridesConfig defines configurations for letting trusted callers override the resolution limits of
+// their requests through the 'openfga-max-resolve-depth' and 'openfga-list-objects-deadline' headers.
+type LimitOverridesConfig struct {
+	Enabled bool
+	// TrustedPrincipals are the subjects or client ids allowed to override the resolution limits.
+	TrustedPrincipals []string
+	// MaxResolveDepth is the largest resolve node limit a request may ask for. 0 does not allow the override.
+	MaxResolveDepth uint32
+	// MaxListObjectsDeadline is the longest ListObjects deadline a request may ask for. 0 does not allow the override.
+	MaxListObjectsDeadline time.Duration
+}
+
+// RateLimitConfig defines configurations for rate limiting requests per caller.
+type RateLimitConfig struct {
+	Enabled bool
+	// Key is the dimension requests are rate limited by: 'caller', 'store' or 'store_and_caller'.
+	Key string
+	// Rate is the number of requests per second allowed for methods without an override. 0 leaves them unlimited.
+	Rate float64
+	// Burst is the number of requests allowed above the rate for methods without an override.
+	Burst int
+	// Methods overrides the limit of methods, in the form '<method>:<rate>:<burst>', e.g. 'Check:100:200'.
+	Methods []string
+}
+
+// AuthorizationModelRetentionConfig defines configurations for deleting the oldest authorization models of stores.
+type AuthorizationModelRetentionConfig struct {
+	// Count is the number of most recent models kept per store. 0 keeps every model.
+	Count int
+	// PruneInterval is how often the models beyond Count are deleted.
+	PruneInterval time.Duration
+}
+
+// ModelSyncConfig defines configurations for fetching the authorization models of stores from outside the server.
+type ModelSyncConfig struct {
+	// Sources are the URLs the models of stores are fetched from, in the form '<store id>:<url>'. The URLs may hold
+	// credentials.
+	Sources []string `json:"-"` // private field, won't be logged
+	// Interval is how often the models are fetched.
+	Interval time.Duration
+}
+
+// TypesystemCacheConfig defines configurations for the cache of the validated authorization models.
+type TypesystemCacheConfig struct {
+	// TTL is how long the validated models are cached.
+	TTL time.Duration
+	// Size is the maximum number of validated models cached.
+	Size int
+	// StoreTTLs overrides the TTL of the models of specific stores, in the form '<store id>:<duration>'.
+	StoreTTLs []string
+	// WarmupStores are the stores whose latest model is cached on startup.
+	WarmupStores []string
+	// WarmupMRUFile is the file the most recently used stores are persisted to, so that their latest model is also
+	// cached on the next startup. If empty, the most recently used stores are not persisted.
+	WarmupMRUFile string
+	// Redis is the Redis server the validated models are also cached in, shared by the servers.
+	Redis RedisConfig
+}
+
+// RedisConfig defines the connection to a Redis server.
+type RedisConfig struct {
+	// Addr is the 'host:port' address of the Redis server. If empty, Redis is not used.
+	Addr     string
+	Username string
+	Password string `json:"-"` // private field, won't be logged
+	DB       int
+	// TLS enables TLS on the connections.
+	TLS bool
+}
+
+// TrustedContextConfig defines the condition parameters the server sets itself in the context of requests,
+// overriding any value supplied by the client.
+type TrustedContextConfig struct {
+	// CurrentTimeParameter is the parameter set to the time the request is evaluated at, as an RFC 3339 timestamp.
+	// If empty, it is not set.
+	CurrentTimeParameter string
+	// CurrentTimePrecision is the precision the current time is truncated to, so that the checks of the same
+	// precision interval share their cache entries.
+	CurrentTimePrecision time.Duration
+	// CallerParameter is the parameter set to the subject, or else the client id, of the authenticated caller. If
+	// empty, it is not set.
+	CallerParameter string
+}
+
 // AccessControlConfig is the configuration for the access control feature.
 type AccessControlConfig struct {
 	Enabled bool
@@ -291,6 +828,11 @@ type Config struct {
 	// This is to protect the server from misuse of the ListObjects endpoints.
 	ListObjectsMaxResults uint32
 
+	// ListObjectsDatastoreQueryBudget defines the number of datastore reads after which ListObjects and
+	// StreamedListObjects requests stop resolving and return the results found so far, marked as truncated.
+	// 0 does not limit the reads.
+	ListObjectsDatastoreQueryBudget uint32
+
 	// ListUsersDeadline defines the maximum amount of time to accumulate ListUsers results
 	// before the server will respond. This is to protect the server from misuse of the
 	// ListUsers endpoints. It cannot be larger than the configured server's request timeout (RequestTimeout or HTTPConfig.UpstreamTimeout).
@@ -304,6 +846,10 @@ type Config struct {
 	// MaxTuplesPerWrite defines the maximum number of tuples per Write endpoint.
 	MaxTuplesPerWrite int
 
+	// MaxPageSize defines the maximum page size of the Read, ReadChanges, ReadAuthorizationModels and ListStores
+	// endpoints, between 1 and 100. The default page size is lowered to it if it is smaller.
+	MaxPageSize int32
+
 	// MaxChecksPerBatchCheck defines the maximum number of tuples
 	// that can be passed in each BatchCheck request.
 	MaxChecksPerBatchCheck uint32
@@ -335,10 +881,30 @@ type Config struct {
 	// MaxConditionEvaluationCost defines the maximum cost for CEL condition evaluation before a request returns an error
 	MaxConditionEvaluationCost uint64
 
+	// MaxContextSizeBytes defines the maximum size, in bytes, of the context of Check, BatchCheck, ListObjects
+	// and ListUsers requests. 0 means no limit.
+	MaxContextSizeBytes uint32
+
+	// MaxContextDepth defines the maximum nesting depth of the context of Check, BatchCheck, ListObjects
+	// and ListUsers requests. 0 means no limit.
+	MaxContextDepth uint32
+
+	// MaxContextualTuples defines the maximum number of contextual tuples of Check, BatchCheck, ListObjects,
+	// ListUsers and Expand requests, in addition to the limits of the API. 0 means no limit.
+	MaxContextualTuples uint32
+
 	// ChangelogHorizonOffset is an offset in minutes from the current time. Changes that occur
 	// after this offset will not be included in the response of ReadChanges.
 	ChangelogHorizonOffset int
 
+	// ContinuationTokenTTL is how long the continuation tokens can be used once returned. 0 means they never expire.
+	ContinuationTokenTTL time.Duration
+
+	// ContinuationTokenUnversionedWindow is how long the continuation tokens returned before their versioning, which
+	// have no expiry, are still accepted once the server started, if the continuation tokens expire. 0 means they are
+	// rejected as soon as the continuation tokens expire.
+	ContinuationTokenUnversionedWindow time.Duration
+
 	// Experimentals is a list of the experimental features to enable in the OpenFGA server.
 	Experimentals []string
 
@@ -353,25 +919,62 @@ type Config struct {
 	// concurrently in a query
 	ResolveNodeBreadthLimit uint32
 
+	// CheckDirectTupleBatchSize indicates how many direct tuples dispatched by a Check are read with a single
+	// datastore query. Values lower than 2 disable batching.
+	CheckDirectTupleBatchSize uint32
+
+	// CheckDirectTupleBatchMaxDelay is how long a direct tuple dispatched by a Check waits for its batch to fill before
+	// the batch is read. 0 waits until the batch fills or the dispatches end.
+	CheckDirectTupleBatchMaxDelay time.Duration
+
+	// CheckDispatchPoolSize is the number of workers, reused across requests, resolving the subproblems of the set
+	// operations of Check and ListObjects rather than a new goroutine each. It is raised to ResolveNodeBreadthLimit.
+	// 0 disables the pool.
+	CheckDispatchPoolSize uint32
+
+	// CheckUnionBranchOrderingEnabled resolves the branches of the unions of Check and ListObjects from the cheapest
+	// to the most expensive, as estimated from the shape of the model.
+	CheckUnionBranchOrderingEnabled bool
+
 	// RequestTimeout configures request timeout.  If both HTTP upstream timeout and request timeout are specified,
 	// request timeout will be prioritized
 	RequestTimeout time.Duration
 
+	// ShutdownDrainDelay is the duration the new requests keep being served on shutdown once the server reports itself
+	// as not ready, so that the load balancers stop routing them to the server before they are refused.
+	ShutdownDrainDelay time.Duration
+
+	// ShutdownDrainTimeout is the duration the requests in flight are given to complete on shutdown, while the new
+	// requests are refused and the server reports itself as not ready.
+	ShutdownDrainTimeout time.Duration
+
+	// MethodTimeouts overrides RequestTimeout for specific API methods, in the form '<method>:<duration>',
+	// e.g. 'Check:500ms'. A method timeout may be longer than RequestTimeout.
+	MethodTimeouts []string
+
 	// ContextPropagationToDatastore enables propagation of a requests context to the datastore,
 	// thereby receiving API cancellation signals
 	ContextPropagationToDatastore bool
 
+	// ResolutionMetadataHeaders returns the number of datastore queries, dispatches and check cache hits of
+	// Check, ListObjects and ListUsers requests in response headers.
+	ResolutionMetadataHeaders bool
+
 	Datastore                     DatastoreConfig
 	GRPC                          GRPCConfig
 	HTTP                          HTTPConfig
 	Authn                         AuthnConfig
 	Log                           LogConfig
+	AccessLog                     AccessLogConfig
+	Redaction                     RedactionConfig
 	Trace                         TraceConfig
 	Playground                    PlaygroundConfig
 	Profiler                      ProfilerConfig
 	Metrics                       MetricConfig
+	Admin                         AdminConfig
 	CheckCache                    CheckCacheConfig
 	CheckIteratorCache            IteratorCacheConfig
+	CheckTupleCache               TupleCacheConfig
 	CheckQueryCache               CheckQueryCache
 	CacheController               CacheControllerConfig
 	CheckDispatchThrottling       DispatchThrottlingConfig
@@ -382,6 +985,31 @@ type Config struct {
 	ListUsersDatabaseThrottle     DatabaseThrottleConfig
 	ListObjectsIteratorCache      IteratorCacheConfig
 	SharedIterator                SharedIteratorConfig
+	RequestIteratorCache          RequestIteratorCacheConfig
+	CheckMembershipIndex          MembershipIndexConfig
+	CheckMaterializedViews        MaterializedViewsConfig
+	DatastoreLimiterSaturation    DatastoreLimiterSaturationConfig
+	DatastoreReadPriority         DatastoreReadPriorityConfig
+	Readiness                     ReadinessConfig
+	DatastoreCircuitBreaker       DatastoreCircuitBreakerConfig
+	DatastoreRetry                DatastoreRetryConfig
+	DatastoreFaultInjection       DatastoreFaultInjectionConfig
+	ProtectedTuples               ProtectedTuplesConfig
+	RateLimit                     RateLimitConfig
+	LoadShedding                  LoadSheddingConfig
+	LimitOverrides                LimitOverridesConfig
+	AuthorizationModelRetention   AuthorizationModelRetentionConfig
+	ModelSync                     ModelSyncConfig
+	TrustedContext                TrustedContextConfig
+	TypesystemCache               TypesystemCacheConfig
+
+	// MaxConcurrentJobs is the maximum number of jobs, e.g. imports of tuples, run at once by the server. The other
+	// jobs wait until one completes.
+	MaxConcurrentJobs int
+
+	// MaxJobTuples is the maximum number of tuples written by an import job or read by an export job, both of which
+	// persist their tuples in the job.
+	MaxJobTuples int
 
 	RequestDurationDatastoreQueryCountBuckets []string
 	RequestDurationDispatchCountBuckets       []string
@@ -403,6 +1031,26 @@ func (cfg *Config) VerifyServerSettings() error {
 		return fmt.Errorf("config 'maxConcurrentReadsForListUsers' cannot be 0")
 	}
 
+	if cfg.ContinuationTokenTTL < 0 {
+		return errors.New("config 'continuationTokenTTL' must be non-negative")
+	}
+
+	if cfg.ContinuationTokenUnversionedWindow < 0 {
+		return errors.New("config 'continuationTokenUnversionedWindow' must be non-negative")
+	}
+
+	if cfg.TrustedContext.CurrentTimePrecision < 0 {
+		return errors.New("config 'trustedContext.currentTimePrecision' must be non-negative")
+	}
+
+	if cfg.MaxTuplesPerWrite <= 0 {
+		return errors.New("config 'maxTuplesPerWrite' must be positive")
+	}
+
+	if cfg.MaxPageSize < 1 || cfg.MaxPageSize > DefaultMaxPageSize {
+		return fmt.Errorf("config 'maxPageSize' must be between 1 and %d", DefaultMaxPageSize)
+	}
+
 	if err := cfg.verifyRequestDurationDatastoreQueryCountBuckets(); err != nil {
 		return err
 	}
@@ -428,6 +1076,64 @@ func (cfg *Config) VerifyServerSettings() error {
 		return err
 	}
 
+	err = cfg.VerifyDatastoreLimiterSaturationConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, err := ParseMethodWeights(cfg.DatastoreReadPriority.Weights); err != nil {
+		return err
+	}
+
+	err = cfg.VerifyDatastoreCircuitBreakerConfig()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.VerifyDatastoreRetryConfig()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.VerifyDatastoreFaultInjectionConfig()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.VerifyDatastoreReadReplicasConfig()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.VerifyDatastoreCredentialsConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Datastore.ReadTimeout < 0 || cfg.Datastore.WriteTimeout < 0 {
+		return errors.New("'datastore.readTimeout' and 'datastore.writeTimeout' must be non-negative time durations")
+	}
+
+	if cfg.Datastore.SlowQueryThreshold < 0 {
+		return errors.New("'datastore.slowQueryThreshold' must be a non-negative time duration")
+	}
+
+	if cfg.Datastore.SnapshotPath != "" && cfg.Datastore.Engine != "memory" {
+		return fmt.Errorf("'datastore.snapshotPath' is not supported by the '%s' engine", cfg.Datastore.Engine)
+	}
+
+	if cfg.Datastore.SnapshotInterval < 0 {
+		return errors.New("'datastore.snapshotInterval' must be a non-negative time duration")
+	}
+
+	if cfg.Datastore.MemoryMaxTuplesPerStore < 0 || cfg.Datastore.MemoryMaxBytes < 0 {
+		return errors.New("'datastore.memoryMaxTuplesPerStore' and 'datastore.memoryMaxBytes' must be non-negative")
+	}
+
+	if (cfg.Datastore.MemoryMaxTuplesPerStore > 0 || cfg.Datastore.MemoryMaxBytes > 0) && cfg.Datastore.Engine != "memory" {
+		return fmt.Errorf("'datastore.memoryMaxTuplesPerStore' and 'datastore.memoryMaxBytes' are not supported by the '%s' engine", cfg.Datastore.Engine)
+	}
+
 	err = cfg.VerifyDatabaseThrottlesConfig()
 	if err != nil {
 		return err
@@ -437,6 +1143,10 @@ func (cfg *Config) VerifyServerSettings() error {
 		return errors.New("listObjectsDeadline must be non-negative time duration")
 	}
 
+	if cfg.CheckDirectTupleBatchMaxDelay < 0 {
+		return errors.New("checkDirectTupleBatchMaxDelay must be non-negative time duration")
+	}
+
 	if cfg.ListUsersDeadline < 0 {
 		return errors.New("listUsersDeadline must be non-negative time duration")
 	}
@@ -465,6 +1175,44 @@ func (cfg *Config) VerifyBinarySettings() error {
 		)
 	}
 
+	if cfg.Log.Sampling.Enabled {
+		if cfg.Log.Sampling.PerSecond <= 0 {
+			return errors.New("'log.sampling.perSecond' must be greater than zero")
+		}
+		if cfg.Log.Sampling.Thereafter < 0 {
+			return errors.New("'log.sampling.thereafter' must be non-negative")
+		}
+	}
+
+	if len(cfg.Log.OutputPaths) == 0 {
+		return errors.New("'log.outputPaths' must not be empty")
+	}
+
+	if cfg.Log.Rotation.Enabled {
+		if cfg.Log.Rotation.MaxSize <= 0 {
+			return errors.New("'log.rotation.maxSize' must be greater than zero")
+		}
+		if cfg.Log.Rotation.MaxAge < 0 {
+			return errors.New("'log.rotation.maxAge' must be a non-negative time duration")
+		}
+		if cfg.Log.Rotation.MaxBackups < 0 {
+			return errors.New("'log.rotation.maxBackups' must be non-negative")
+		}
+	}
+
+	if cfg.Log.Security.Enabled {
+		if cfg.Log.Security.Format != "text" && cfg.Log.Security.Format != "json" {
+			return errors.New("config 'log.security.format' must be one of ['text', 'json']")
+		}
+		if len(cfg.Log.Security.OutputPaths) == 0 {
+			return errors.New("'log.security.outputPaths' must not be empty")
+		}
+	}
+
+	if cfg.Redaction.Mode != "off" && cfg.Redaction.Mode != "hash" && cfg.Redaction.Mode != "drop" {
+		return errors.New("config 'redaction.mode' must be one of ['off', 'hash', 'drop']")
+	}
+
 	if cfg.Log.Level == "none" {
 		fmt.Println("WARNING: Logging is not enabled. It is highly recommended to enable logging in production environments to avoid masking attacker operations.")
 	}
@@ -495,6 +1243,132 @@ func (cfg *Config) VerifyBinarySettings() error {
 		}
 	}
 
+	if cfg.Admin.Enabled && len(cfg.Admin.Keys) == 0 {
+		return errors.New("config 'admin.keys' must be set when the admin server is enabled")
+	}
+
+	if cfg.ShutdownDrainDelay < 0 {
+		return errors.New("config 'shutdownDrainDelay' must be non-negative")
+	}
+
+	if cfg.ShutdownDrainTimeout < 0 {
+		return errors.New("config 'shutdownDrainTimeout' must be non-negative")
+	}
+
+	if cfg.GRPC.MaxRecvMsgSizeBytes <= 0 {
+		return errors.New("config 'grpc.maxRecvMsgSizeBytes' must be greater than zero")
+	}
+	if cfg.GRPC.MaxSendMsgSizeBytes <= 0 {
+		return errors.New("config 'grpc.maxSendMsgSizeBytes' must be greater than zero")
+	}
+	// the gRPC server raises shorter durations to 1s
+	if cfg.GRPC.Keepalive.Time < time.Second {
+		return errors.New("config 'grpc.keepalive.time' must be at least 1s")
+	}
+	if cfg.GRPC.Keepalive.Timeout <= 0 {
+		return errors.New("config 'grpc.keepalive.timeout' must be greater than zero")
+	}
+	if cfg.GRPC.Keepalive.MaxConnectionAge < 0 || cfg.GRPC.Keepalive.MaxConnectionAgeGrace < 0 ||
+		cfg.GRPC.Keepalive.MinTime < 0 {
+		return errors.New("configs 'grpc.keepalive.maxConnectionAge', 'grpc.keepalive.maxConnectionAgeGrace' and 'grpc.keepalive.minTime' must be non-negative")
+	}
+
+	if cfg.Authn.Method == "oidc" && len(cfg.Authn.AdditionalIssuers) != len(cfg.Authn.AdditionalAudiences) {
+		return errors.New("configs 'authn.oidc.additionalIssuers' and 'authn.oidc.additionalAudiences' must have the same length, the audience of each additional issuer")
+	}
+
+	if cfg.Authn.Method == "mtls" {
+		if !cfg.GRPC.TLS.Enabled || cfg.Authn.ClientCAPath == "" {
+			return errors.New("the 'mtls' authn method requires 'grpc.tls.enabled' and 'authn.mtls.clientCA' to be set")
+		}
+		// requests proxied by the HTTP gateway would be authenticated as the gateway itself
+		if cfg.HTTP.Enabled {
+			return errors.New("the 'mtls' authn method only applies to the gRPC API, 'http.enabled' must be false")
+		}
+	}
+
+	if cfg.RateLimit.Enabled {
+		switch cfg.RateLimit.Key {
+		case "caller", "store", "store_and_caller":
+		default:
+			return errors.New("config 'rateLimit.key' must be one of ['caller', 'store', 'store_and_caller']")
+		}
+		if cfg.RateLimit.Rate < 0 {
+			return errors.New("'rateLimit.rate' must be non-negative")
+		}
+		if cfg.RateLimit.Rate > 0 && cfg.RateLimit.Burst <= 0 {
+			return errors.New("'rateLimit.burst' must be greater than zero when 'rateLimit.rate' is set")
+		}
+	}
+
+	if cfg.Metrics.OTLP.Enabled {
+		if cfg.Metrics.OTLP.Protocol != "grpc" && cfg.Metrics.OTLP.Protocol != "http" {
+			return errors.New("config 'metrics.otlp.protocol' must be one of ['grpc', 'http']")
+		}
+		if cfg.Metrics.OTLP.Interval <= 0 {
+			return errors.New("'metrics.otlp.interval' must be greater than zero")
+		}
+		if _, err := ParseOTLPHeaders(cfg.Metrics.OTLP.Headers); err != nil {
+			return err
+		}
+	}
+
+	if _, err := ParseMethodSampleRatios(cfg.Trace.MethodSampleRatios); err != nil {
+		return err
+	}
+
+	if cfg.Metrics.StoreIDLabel.Enabled && len(cfg.Metrics.StoreIDLabel.Stores) == 0 && cfg.Metrics.StoreIDLabel.Limit <= 0 {
+		return errors.New("'metrics.storeIDLabel.limit' must be greater than zero when 'metrics.storeIDLabel.stores' is empty")
+	}
+
+	if cfg.LoadShedding.Enabled && cfg.LoadShedding.MaxInFlightCost <= 0 {
+		return errors.New("'loadShedding.maxInFlightCost' must be greater than zero")
+	}
+
+	if cfg.TypesystemCache.TTL <= 0 {
+		return errors.New("'typesystemCache.ttl' must be greater than zero")
+	}
+
+	if cfg.TypesystemCache.Size <= 0 {
+		return errors.New("'typesystemCache.size' must be greater than zero")
+	}
+
+	if _, err := ParseStoreTTLs(cfg.TypesystemCache.StoreTTLs); err != nil {
+		return err
+	}
+
+	if cfg.TypesystemCache.Redis.DB < 0 {
+		return errors.New("'typesystemCache.redis.db' must be non-negative")
+	}
+
+	if cfg.MaxConcurrentJobs < 1 {
+		return errors.New("'maxConcurrentJobs' must be greater than zero")
+	}
+
+	if cfg.MaxJobTuples < 1 {
+		return errors.New("'maxJobTuples' must be greater than zero")
+	}
+
+	if cfg.AuthorizationModelRetention.Count < 0 {
+		return errors.New("'authorizationModelRetention.count' must be non-negative")
+	}
+
+	if cfg.AuthorizationModelRetention.Count > 0 && cfg.AuthorizationModelRetention.PruneInterval <= 0 {
+		return errors.New("'authorizationModelRetention.pruneInterval' must be greater than zero")
+	}
+
+	if _, err := ParseModelSyncSources(cfg.ModelSync.Sources); err != nil {
+		return err
+	}
+
+	if len(cfg.ModelSync.Sources) > 0 && cfg.ModelSync.Interval <= 0 {
+		return errors.New("'modelSync.interval' must be greater than zero")
+	}
+
+	if cfg.LimitOverrides.Enabled && len(cfg.LimitOverrides.TrustedPrincipals) == 0 {
+		return errors.New("'limitOverrides.trustedPrincipals' must be set when limit overrides are enabled")
+	}
+
 	if cfg.RequestTimeout < 0 {
 		return errors.New("requestTimeout must be a non-negative time duration")
 	}
@@ -503,6 +1377,10 @@ func (cfg *Config) VerifyBinarySettings() error {
 		return errors.New("http.upstreamTimeout must be a non-negative time duration")
 	}
 
+	if _, err := ParseMethodTimeouts(cfg.MethodTimeouts); err != nil {
+		return err
+	}
+
 	if viper.IsSet("cache.limit") && !viper.IsSet("checkCache.limit") {
 		fmt.Println("WARNING: flag `check-query-cache-limit` is deprecated. Please set --check-cache-limit instead.")
 	}
@@ -510,13 +1388,166 @@ func (cfg *Config) VerifyBinarySettings() error {
 	return nil
 }
 
+// ParseMethodTimeouts parses timeouts of specific API methods, in the form '<method>:<duration>', e.g. 'Check:500ms'.
+// It returns an error if a method is not the name of an API method, so that a typo does not go unnoticed.
+func ParseMethodTimeouts(values []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(values))
+	for _, value := range values {
+		method, rawTimeout, ok := strings.Cut(value, ":")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid method timeout '%s', expected '<method>:<duration>'", value)
+		}
+		if !apimethod.APIMethod(method).IsValid() {
+			return nil, fmt.Errorf("invalid method timeout '%s', unknown method '%s'", value, method)
+		}
+		timeout, err := time.ParseDuration(rawTimeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid method timeout '%s', the timeout must be a positive duration", value)
+		}
+		timeouts[method] = timeout
+	}
+	return timeouts, nil
+}
+
+// ParseDatastoreFaultPolicies parses the faults injected into specific datastore operations, in the form
+// '<operation>:<latency>:<error rate>:<iterator latency>', e.g. 'ReadUserTuple:50ms:0.1:0s'.
+func ParseDatastoreFaultPolicies(values []string) (map[string]DatastoreFaultPolicy, error) {
+	policies := make(map[string]DatastoreFaultPolicy, len(values))
+	for _, value := range values {
+		parts := strings.Split(value, ":")
+		if len(parts) != 4 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid datastore fault policy '%s', expected '<operation>:<latency>:<error rate>:<iterator latency>'", value)
+		}
+		latency, err := time.ParseDuration(parts[1])
+		if err != nil || latency < 0 {
+			return nil, fmt.Errorf("invalid datastore fault policy '%s', the latency must be a non-negative duration", value)
+		}
+		errorRate, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || errorRate < 0 || errorRate > 1 {
+			return nil, fmt.Errorf("invalid datastore fault policy '%s', the error rate must be between 0 and 1", value)
+		}
+		iteratorLatency, err := time.ParseDuration(parts[3])
+		if err != nil || iteratorLatency < 0 {
+			return nil, fmt.Errorf("invalid datastore fault policy '%s', the iterator latency must be a non-negative duration", value)
+		}
+		if _, found := policies[parts[0]]; found {
+			return nil, fmt.Errorf("the datastore operation '%s' has more than one fault policy", parts[0])
+		}
+		policies[parts[0]] = DatastoreFaultPolicy{
+			Latency:         latency,
+			ErrorRate:       errorRate,
+			IteratorLatency: iteratorLatency,
+		}
+	}
+	return policies, nil
+}
+
+// ParseStoreTTLs parses TTLs of specific stores, in the form '<store id>:<duration>', e.g. '01HVMMBCMGZNT3SED4Z17ECXCA:1h'.
+func ParseStoreTTLs(values []string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(values))
+	for _, value := range values {
+		storeID, rawTTL, ok := strings.Cut(value, ":")
+		if !ok || storeID == "" {
+			return nil, fmt.Errorf("invalid store TTL '%s', expected '<store id>:<duration>'", value)
+		}
+		ttl, err := time.ParseDuration(rawTTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid store TTL '%s', the TTL must be a positive duration", value)
+		}
+		ttls[storeID] = ttl
+	}
+	return ttls, nil
+}
+
+// ParseModelSyncSources parses the URLs the models of stores are fetched from, in the form '<store id>:<url>', e.g.
+// '01HVMMBCMGZNT3SED4Z17ECXCA:https://example.com/model.fga', keyed by store id.
+func ParseModelSyncSources(values []string) (map[string]string, error) {
+	sources := make(map[string]string, len(values))
+	for _, value := range values {
+		storeID, rawURL, ok := strings.Cut(value, ":")
+		if !ok || storeID == "" || rawURL == "" {
+			return nil, fmt.Errorf("invalid model sync source for the store '%s', expected '<store id>:<url>'", storeID)
+		}
+		if _, found := sources[storeID]; found {
+			return nil, fmt.Errorf("the store '%s' has more than one model sync source", storeID)
+		}
+		sources[storeID] = rawURL
+	}
+	return sources, nil
+}
+
+// ParseMethodSampleRatios parses trace sampling ratios of specific API methods, in the form '<method>:<ratio>', e.g.
+// 'Check:0.01' or 'Write*:1'.
+func ParseMethodSampleRatios(values []string) (map[string]float64, error) {
+	ratios := make(map[string]float64, len(values))
+	for _, value := range values {
+		method, rawRatio, ok := strings.Cut(value, ":")
+		if !ok || method == "" || method == "*" {
+			return nil, fmt.Errorf("invalid method sample ratio '%s', expected '<method>:<ratio>'", value)
+		}
+		ratio, err := strconv.ParseFloat(rawRatio, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid method sample ratio '%s', the ratio must be between 0 and 1", value)
+		}
+		ratios[method] = ratio
+	}
+	return ratios, nil
+}
+
+// ParseOTLPHeaders parses the headers of the OTLP exports, in the form '<name>=<value>', e.g.
+// 'Authorization=Bearer <token>'.
+func ParseOTLPHeaders(values []string) (map[string]string, error) {
+	headers := make(map[string]string, len(values))
+	for _, value := range values {
+		name, headerValue, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			// the value may be a secret
+			return nil, fmt.Errorf("invalid otlp header '%s', expected '<name>=<value>'", name)
+		}
+		headers[name] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
+
+// ParseMethodWeights parses the weights of API methods, in the form '<method>:<weight>', e.g. 'Check:8'.
+func ParseMethodWeights(values []string) (map[string]uint32, error) {
+	weights := make(map[string]uint32, len(values))
+	for _, value := range values {
+		method, rawWeight, ok := strings.Cut(value, ":")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid method weight '%s', expected '<method>:<weight>'", value)
+		}
+		weight, err := strconv.ParseUint(rawWeight, 10, 32)
+		if err != nil || weight == 0 {
+			return nil, fmt.Errorf("invalid method weight '%s', the weight must be a positive integer", value)
+		}
+		weights[method] = uint32(weight)
+	}
+	return weights, nil
+}
+
+// MaxRequestTimeout returns the longest timeout of a request, i.e. the longest of the requestTimeout and the
+// method timeouts, or 0 if requests have no timeout.
+func MaxRequestTimeout(config *Config) time.Duration {
+	if config.RequestTimeout <= 0 {
+		return 0
+	}
+	maxTimeout := config.RequestTimeout
+	methodTimeouts, _ := ParseMethodTimeouts(config.MethodTimeouts)
+	for _, timeout := range methodTimeouts {
+		maxTimeout = max(maxTimeout, timeout)
+	}
+	return maxTimeout
+}
+
 // DefaultContextTimeout returns the runtime DefaultContextTimeout.
 // If requestTimeout > 0, we should let the middleware take care of the timeout and the
 // runtime.DefaultContextTimeout is used as last resort.
 // Otherwise, use the http upstream timeout if http is enabled.
 func DefaultContextTimeout(config *Config) time.Duration {
 	if config.RequestTimeout > 0 {
-		return config.RequestTimeout + additionalUpstreamTimeout
+		return MaxRequestTimeout(config) + additionalUpstreamTimeout
 	}
 	if config.HTTP.Enabled && config.HTTP.UpstreamTimeout > 0 {
 		return config.HTTP.UpstreamTimeout
@@ -564,6 +1595,112 @@ func (cfg *Config) VerifyDispatchThrottlingConfig() error {
 	return nil
 }
 
+// VerifyDatastoreLimiterSaturationConfig ensures the datastore limiter saturation settings are consistent.
+func (cfg *Config) VerifyDatastoreLimiterSaturationConfig() error {
+	if cfg.DatastoreLimiterSaturation.Threshold < 0 {
+		return errors.New("'datastoreLimiterSaturation.threshold' must be non-negative")
+	}
+	if cfg.DatastoreLimiterSaturation.Threshold > 0 && cfg.DatastoreLimiterSaturation.Period <= 0 {
+		return errors.New("'datastoreLimiterSaturation.period' must be greater than zero")
+	}
+	if cfg.DatastoreLimiterSaturation.Threshold > 0 && (cfg.DatastoreLimiterSaturation.Ratio <= 0 || cfg.DatastoreLimiterSaturation.Ratio > 1) {
+		return errors.New("'datastoreLimiterSaturation.ratio' must be greater than 0 and at most 1")
+	}
+	if cfg.DatastoreLimiterSaturation.ReadinessEnabled && cfg.DatastoreLimiterSaturation.Threshold == 0 {
+		return errors.New("'datastoreLimiterSaturation.readinessEnabled' requires 'datastoreLimiterSaturation.threshold' to be greater than zero")
+	}
+	return nil
+}
+
+// VerifyDatastoreCircuitBreakerConfig ensures the datastore circuit breaker settings are consistent.
+func (cfg *Config) VerifyDatastoreCircuitBreakerConfig() error {
+	breaker := cfg.DatastoreCircuitBreaker
+	if breaker.FailureRateThreshold < 0 || breaker.FailureRateThreshold > 1 {
+		return errors.New("'datastoreCircuitBreaker.failureRateThreshold' must be between 0 and 1")
+	}
+	if breaker.FailureRateThreshold == 0 {
+		return nil
+	}
+	if breaker.MinRequests < 1 {
+		return errors.New("'datastoreCircuitBreaker.minRequests' must be greater than zero")
+	}
+	if breaker.Window <= 0 {
+		return errors.New("'datastoreCircuitBreaker.window' must be greater than zero")
+	}
+	if breaker.OpenDuration <= 0 {
+		return errors.New("'datastoreCircuitBreaker.openDuration' must be greater than zero")
+	}
+	return nil
+}
+
+// VerifyDatastoreRetryConfig ensures the datastore retry settings are consistent.
+func (cfg *Config) VerifyDatastoreRetryConfig() error {
+	retry := cfg.DatastoreRetry
+	if retry.ConnectionMaxRetries < 0 || retry.SerializationMaxRetries < 0 || retry.DeadlockMaxRetries < 0 {
+		return errors.New("'datastoreRetry' max retries must be non-negative")
+	}
+	if retry.ConnectionMaxRetries == 0 && retry.SerializationMaxRetries == 0 && retry.DeadlockMaxRetries == 0 {
+		return nil
+	}
+	if retry.InitialInterval <= 0 || retry.MaxInterval < retry.InitialInterval {
+		return errors.New("'datastoreRetry.initialInterval' must be greater than zero and not greater than 'datastoreRetry.maxInterval'")
+	}
+	return nil
+}
+
+// VerifyDatastoreFaultInjectionConfig ensures the datastore fault injection settings are consistent.
+func (cfg *Config) VerifyDatastoreFaultInjectionConfig() error {
+	faults := cfg.DatastoreFaultInjection
+	if faults.Latency < 0 || faults.IteratorLatency < 0 {
+		return errors.New("'datastoreFaultInjection' latencies must be non-negative")
+	}
+	if faults.ErrorRate < 0 || faults.ErrorRate > 1 {
+		return errors.New("'datastoreFaultInjection.errorRate' must be between 0 and 1")
+	}
+	if _, err := ParseDatastoreFaultPolicies(faults.Policies); err != nil {
+		return err
+	}
+	if faults.Enabled() && !slices.Contains(cfg.Experimentals, "enable-fault-injection") {
+		return errors.New("'datastoreFaultInjection' requires the 'enable-fault-injection' experimental feature")
+	}
+	return nil
+}
+
+// VerifyDatastoreCredentialsConfig ensures credentials files and IAM authentication are only configured for engines
+// supporting them, and not together.
+func (cfg *Config) VerifyDatastoreCredentialsConfig() error {
+	if cfg.Datastore.IAMAuth.Enabled {
+		if cfg.Datastore.Engine != "postgres" && cfg.Datastore.Engine != "mysql" {
+			return fmt.Errorf("'datastore.iamAuth' is not supported by the '%s' engine", cfg.Datastore.Engine)
+		}
+		if cfg.Datastore.IAMAuth.Region == "" {
+			return errors.New("'datastore.iamAuth.region' is required")
+		}
+		if cfg.Datastore.UsernameFile != "" || cfg.Datastore.PasswordFile != "" {
+			return errors.New("'datastore.iamAuth' cannot be combined with 'datastore.usernameFile' and 'datastore.passwordFile'")
+		}
+	}
+
+	if cfg.Datastore.UsernameFile == "" && cfg.Datastore.PasswordFile == "" {
+		return nil
+	}
+	if cfg.Datastore.Engine != "postgres" && cfg.Datastore.Engine != "mysql" {
+		return fmt.Errorf("'datastore.usernameFile' and 'datastore.passwordFile' are not supported by the '%s' engine", cfg.Datastore.Engine)
+	}
+	if cfg.Datastore.CredentialsRefreshInterval <= 0 {
+		return errors.New("'datastore.credentialsRefreshInterval' must be greater than zero")
+	}
+	return nil
+}
+
+// VerifyDatastoreReadReplicasConfig ensures read replicas are only configured for engines supporting them.
+func (cfg *Config) VerifyDatastoreReadReplicasConfig() error {
+	if len(cfg.Datastore.ReadReplicaURIs) > 0 && cfg.Datastore.Engine != "postgres" && cfg.Datastore.Engine != "mysql" {
+		return fmt.Errorf("'datastore.readReplicaURIs' is not supported by the '%s' engine", cfg.Datastore.Engine)
+	}
+	return nil
+}
+
 // VerifyDatabaseThrottlesConfig ensures VerifyDatabaseThrottlesConfig is called so that the right values are verified.
 func (cfg *Config) VerifyDatabaseThrottlesConfig() error {
 	if cfg.CheckDatabaseThrottle.Enabled {
@@ -610,6 +1747,13 @@ func (cfg *Config) verifyDeadline() error {
 			cfg.ListUsersDeadline,
 		)
 	}
+	if cfg.LimitOverrides.Enabled && cfg.LimitOverrides.MaxListObjectsDeadline > configuredTimeout {
+		return fmt.Errorf(
+			"configured request timeout (%s) cannot be lower than 'limitOverrides.maxListObjectsDeadline' config (%s)",
+			configuredTimeout,
+			cfg.LimitOverrides.MaxListObjectsDeadline,
+		)
+	}
 	return nil
 }
 
@@ -632,6 +1776,10 @@ func (cfg *Config) verifyCacheConfig() error {
 	if cfg.CheckQueryCache.Enabled && cfg.CheckQueryCache.TTL <= 0 {
 		return errors.New("'checkQueryCache.ttl' must be greater than zero")
 	}
+	if cfg.CheckQueryCache.RevalidationWindow < 0 ||
+		(cfg.CheckQueryCache.Enabled && cfg.CheckQueryCache.RevalidationWindow > 0 && cfg.CheckQueryCache.RevalidationWindow >= cfg.CheckQueryCache.TTL) {
+		return errors.New("'checkQueryCache.revalidationWindow' must be non-negative and smaller than 'checkQueryCache.ttl'")
+	}
 	if cfg.CheckIteratorCache.Enabled {
 		if cfg.CheckIteratorCache.TTL <= 0 {
 			return errors.New("'checkIteratorCache.ttl' must be greater than zero")
@@ -640,6 +1788,9 @@ func (cfg *Config) verifyCacheConfig() error {
 			return errors.New("'checkIteratorCache.maxResults' must be greater than zero")
 		}
 	}
+	if cfg.CheckTupleCache.Enabled && cfg.CheckTupleCache.TTL <= 0 {
+		return errors.New("'checkTupleCache.ttl' must be greater than zero")
+	}
 	if cfg.ListObjectsIteratorCache.Enabled {
 		if cfg.ListObjectsIteratorCache.TTL <= 0 {
 			return errors.New("'listObjectsIteratorCache.ttl' must be greater than zero")
@@ -648,9 +1799,20 @@ func (cfg *Config) verifyCacheConfig() error {
 			return errors.New("'listObjectsIteratorCache.maxResults' must be greater than zero")
 		}
 	}
+	if cfg.RequestIteratorCache.Enabled && cfg.RequestIteratorCache.MaxResults <= 0 {
+		return errors.New("'requestIteratorCache.maxResults' must be greater than zero")
+	}
 	if cfg.CacheController.Enabled && cfg.CacheController.TTL <= 0 {
 		return errors.New("'cacheController.ttl' must be greater than zero")
 	}
+	if cfg.CacheController.NotificationChannel != "" {
+		if !cfg.CacheController.Enabled {
+			return errors.New("'cacheController.notificationChannel' requires the cache controller to be enabled")
+		}
+		if cfg.Datastore.Engine != "postgres" {
+			return errors.New("'cacheController.notificationChannel' requires the 'postgres' datastore engine")
+		}
+	}
 	return nil
 }
 
@@ -663,6 +1825,7 @@ func MaxConditionEvaluationCost() uint64 {
 func DefaultConfig() *Config {
 	return &Config{
 		MaxTuplesPerWrite:                         DefaultMaxTuplesPerWrite,
+		MaxPageSize:                               DefaultMaxPageSize,
 		MaxTypesPerAuthorizationModel:             DefaultMaxTypesPerAuthorizationModel,
 		MaxAuthorizationModelSizeInBytes:          DefaultMaxAuthorizationModelSizeInBytes,
 		MaxChecksPerBatchCheck:                    DefaultMaxChecksPerBatchCheck,
@@ -671,13 +1834,23 @@ func DefaultConfig() *Config {
 		MaxConcurrentReadsForListObjects:          DefaultMaxConcurrentReadsForListObjects,
 		MaxConcurrentReadsForListUsers:            DefaultMaxConcurrentReadsForListUsers,
 		MaxConditionEvaluationCost:                DefaultMaxConditionEvaluationCost,
+		MaxContextSizeBytes:                       DefaultMaxContextSizeBytes,
+		MaxContextDepth:                           DefaultMaxContextDepth,
+		MaxContextualTuples:                       DefaultMaxContextualTuples,
 		ChangelogHorizonOffset:                    DefaultChangelogHorizonOffset,
+		ContinuationTokenTTL:                      DefaultContinuationTokenTTL,
+		ContinuationTokenUnversionedWindow:        DefaultContinuationTokenUnversionedWindow,
 		ResolveNodeLimit:                          DefaultResolveNodeLimit,
 		ResolveNodeBreadthLimit:                   DefaultResolveNodeBreadthLimit,
+		CheckDirectTupleBatchSize:                 DefaultCheckDirectTupleBatchSize,
+		CheckDirectTupleBatchMaxDelay:             DefaultCheckDirectTupleBatchMaxDelay,
+		CheckDispatchPoolSize:                     DefaultCheckDispatchPoolSize,
+		CheckUnionBranchOrderingEnabled:           DefaultCheckUnionBranchOrderingEnabled,
 		Experimentals:                             []string{},
 		AccessControl:                             AccessControlConfig{Enabled: false, StoreID: "", ModelID: ""},
 		ListObjectsDeadline:                       DefaultListObjectsDeadline,
 		ListObjectsMaxResults:                     DefaultListObjectsMaxResults,
+		ListObjectsDatastoreQueryBudget:           DefaultListObjectsDatastoreQueryBudget,
 		ListUsersMaxResults:                       DefaultListUsersMaxResults,
 		ListUsersDeadline:                         DefaultListUsersDeadline,
 		RequestDurationDatastoreQueryCountBuckets: []string{"50", "200"},
@@ -687,10 +1860,24 @@ func DefaultConfig() *Config {
 			MaxCacheSize: DefaultMaxAuthorizationModelCacheSize,
 			MaxIdleConns: 10,
 			MaxOpenConns: 30,
+
+			CredentialsRefreshInterval: time.Minute,
+			SnapshotInterval:           time.Minute,
 		},
 		GRPC: GRPCConfig{
-			Addr: "0.0.0.0:8081",
-			TLS:  &TLSConfig{Enabled: false},
+			Addr:                 "0.0.0.0:8081",
+			TLS:                  &TLSConfig{Enabled: false},
+			MaxRecvMsgSizeBytes:  DefaultMaxRPCMessageSizeInBytes,
+			MaxSendMsgSizeBytes:  math.MaxInt32,
+			MaxConcurrentStreams: 0,
+			Keepalive: GRPCKeepaliveConfig{
+				Time:                  2 * time.Hour,
+				Timeout:               20 * time.Second,
+				MaxConnectionAge:      0,
+				MaxConnectionAgeGrace: 0,
+				MinTime:               5 * time.Minute,
+				PermitWithoutStream:   false,
+			},
 		},
 		HTTP: HTTPConfig{
 			Enabled:            true,
@@ -699,16 +1886,47 @@ func DefaultConfig() *Config {
 			UpstreamTimeout:    5 * time.Second,
 			CORSAllowedOrigins: []string{"*"},
 			CORSAllowedHeaders: []string{"*"},
+			ConnectEnabled:     false,
+			GRPCWebEnabled:     false,
 		},
 		Authn: AuthnConfig{
 			Method:                  "none",
 			AuthnPresharedKeyConfig: &AuthnPresharedKeyConfig{},
 			AuthnOIDCConfig:         &AuthnOIDCConfig{},
+			AuthnStoreTokenConfig:   &AuthnStoreTokenConfig{},
+			AuthnMTLSConfig:         &AuthnMTLSConfig{},
 		},
 		Log: LogConfig{
 			Format:          "text",
 			Level:           "info",
 			TimestampFormat: "Unix",
+			Sampling: LogSamplingConfig{
+				Enabled:    false,
+				PerSecond:  10,
+				Thereafter: 100,
+			},
+			OutputPaths: []string{"stdout"},
+			Rotation: LogRotationConfig{
+				Enabled:    false,
+				MaxSize:    100,
+				MaxAge:     0,
+				MaxBackups: 0,
+				Compress:   false,
+			},
+			Security: SecurityLogConfig{
+				Enabled:     false,
+				Format:      "json",
+				OutputPaths: []string{"stdout"},
+			},
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:        false,
+			Fields:         []string{},
+			SkippedMethods: []string{"grpc.health.v1.Health/Check", "grpc.health.v1.Health/Watch"},
+		},
+		Redaction: RedactionConfig{
+			Mode:    "off",
+			HashKey: "",
 		},
 		Trace: TraceConfig{
 			Enabled: false,
@@ -718,8 +1936,10 @@ func DefaultConfig() *Config {
 					Enabled: false,
 				},
 			},
-			SampleRatio: 0.2,
-			ServiceName: "openfga",
+			SampleRatio:        0.2,
+			MethodSampleRatios: []string{},
+			SampleErrors:       false,
+			ServiceName:        "openfga",
 		},
 		Playground: PlaygroundConfig{
 			Enabled: true,
@@ -729,19 +1949,42 @@ func DefaultConfig() *Config {
 			Enabled: false,
 			Addr:    ":3001",
 		},
+		Admin: AdminConfig{
+			Enabled: false,
+			Addr:    "127.0.0.1:8082",
+			Keys:    []string{},
+		},
 		Metrics: MetricConfig{
 			Enabled:             true,
 			Addr:                "0.0.0.0:2112",
 			EnableRPCHistograms: false,
+			OTLP: OTLPMetricsConfig{
+				Enabled:  false,
+				Endpoint: "0.0.0.0:4317",
+				Protocol: "grpc",
+				Interval: 30 * time.Second,
+				Headers:  []string{},
+			},
+			StoreIDLabel: MetricsStoreIDLabelConfig{
+				Enabled: false,
+				Stores:  []string{},
+				Limit:   100,
+			},
 		},
 		CheckIteratorCache: IteratorCacheConfig{
 			Enabled:    DefaultCheckIteratorCacheEnabled,
 			MaxResults: DefaultCheckIteratorCacheMaxResults,
 			TTL:        DefaultCheckIteratorCacheTTL,
 		},
+		CheckTupleCache: TupleCacheConfig{
+			Enabled: DefaultCheckTupleCacheEnabled,
+			TTL:     DefaultCheckTupleCacheTTL,
+		},
 		CheckQueryCache: CheckQueryCache{
-			Enabled: DefaultCheckQueryCacheEnabled,
-			TTL:     DefaultCheckQueryCacheTTL,
+			Enabled:             DefaultCheckQueryCacheEnabled,
+			TTL:                 DefaultCheckQueryCacheTTL,
+			RevalidationWindow:  DefaultCheckQueryCacheRevalidationWindow,
+			RevalidationMinHits: DefaultCheckQueryCacheRevalidationMinHits,
 		},
 		CheckCache: CheckCacheConfig{
 			Limit: DefaultCheckCacheLimit,
@@ -750,6 +1993,10 @@ func DefaultConfig() *Config {
 			Enabled: DefaultSharedIteratorEnabled,
 			Limit:   DefaultSharedIteratorLimit,
 		},
+		RequestIteratorCache: RequestIteratorCacheConfig{
+			Enabled:    DefaultRequestIteratorCacheEnabled,
+			MaxResults: DefaultRequestIteratorCacheMaxResults,
+		},
 		CacheController: CacheControllerConfig{
 			Enabled: DefaultCacheControllerConfigEnabled,
 			TTL:     DefaultCacheControllerConfigTTL,
@@ -792,8 +2039,98 @@ func DefaultConfig() *Config {
 			Threshold: 0,
 			Duration:  0,
 		},
+		DatastoreLimiterSaturation: DatastoreLimiterSaturationConfig{
+			Threshold:        DefaultDatastoreLimiterSaturationThreshold,
+			Period:           DefaultDatastoreLimiterSaturationPeriod,
+			Ratio:            DefaultDatastoreLimiterSaturationRatio,
+			ReadinessEnabled: DefaultDatastoreLimiterSaturationReadinessEnabled,
+		},
+		CheckMembershipIndex: MembershipIndexConfig{
+			Relations:       []string{},
+			RefreshInterval: DefaultCheckMembershipIndexRefreshInterval,
+			MaxStaleness:    DefaultCheckMembershipIndexMaxStaleness,
+		},
+		CheckMaterializedViews: MaterializedViewsConfig{
+			Views:           []string{},
+			RefreshInterval: DefaultCheckMaterializedViewsRefreshInterval,
+			MaxStaleness:    DefaultCheckMaterializedViewsMaxStaleness,
+		},
+		DatastoreReadPriority: DatastoreReadPriorityConfig{
+			Slots:   DefaultDatastoreReadPrioritySlots,
+			Weights: []string{},
+		},
+		Readiness: ReadinessConfig{
+			CheckCacheEnabled:          false,
+			TupleChangeListenerEnabled: false,
+		},
+		DatastoreCircuitBreaker: DatastoreCircuitBreakerConfig{
+			FailureRateThreshold: DefaultDatastoreCircuitBreakerFailureRateThreshold,
+			MinRequests:          DefaultDatastoreCircuitBreakerMinRequests,
+			Window:               DefaultDatastoreCircuitBreakerWindow,
+			OpenDuration:         DefaultDatastoreCircuitBreakerOpenDuration,
+		},
+		DatastoreRetry: DatastoreRetryConfig{
+			ConnectionMaxRetries:    DefaultDatastoreRetryConnectionMaxRetries,
+			SerializationMaxRetries: DefaultDatastoreRetrySerializationMaxRetries,
+			DeadlockMaxRetries:      DefaultDatastoreRetryDeadlockMaxRetries,
+			InitialInterval:         DefaultDatastoreRetryInitialInterval,
+			MaxInterval:             DefaultDatastoreRetryMaxInterval,
+			Writes:                  DefaultDatastoreRetryWrites,
+		},
+		DatastoreFaultInjection: DatastoreFaultInjectionConfig{
+			Operations:      []string{},
+			Latency:         DefaultDatastoreFaultInjectionLatency,
+			ErrorRate:       DefaultDatastoreFaultInjectionErrorRate,
+			IteratorLatency: DefaultDatastoreFaultInjectionIteratorLatency,
+			Policies:        []string{},
+		},
+		ProtectedTuples: ProtectedTuplesConfig{
+			Patterns:             []string{},
+			PrivilegedPrincipals: []string{},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: false,
+			Key:     "caller",
+			Rate:    0,
+			Burst:   0,
+			Methods: []string{},
+		},
+		LoadShedding: LoadSheddingConfig{
+			Enabled:         DefaultLoadSheddingEnabled,
+			MaxInFlightCost: DefaultLoadSheddingMaxInFlightCost,
+		},
+		LimitOverrides: LimitOverridesConfig{
+			Enabled:           DefaultLimitOverridesEnabled,
+			TrustedPrincipals: []string{},
+		},
+		AuthorizationModelRetention: AuthorizationModelRetentionConfig{
+			Count:         DefaultAuthorizationModelRetentionCount,
+			PruneInterval: DefaultAuthorizationModelRetentionPruneInterval,
+		},
+		ModelSync: ModelSyncConfig{
+			Sources:  []string{},
+			Interval: DefaultModelSyncInterval,
+		},
+		TrustedContext: TrustedContextConfig{
+			CurrentTimeParameter: "",
+			CurrentTimePrecision: DefaultTrustedContextCurrentTimePrecision,
+			CallerParameter:      "",
+		},
+		TypesystemCache: TypesystemCacheConfig{
+			TTL:           DefaultTypesystemCacheTTL,
+			Size:          DefaultTypesystemCacheSize,
+			StoreTTLs:     []string{},
+			WarmupStores:  []string{},
+			WarmupMRUFile: "",
+		},
+		MaxConcurrentJobs:             DefaultMaxConcurrentJobs,
+		MaxJobTuples:                  DefaultMaxJobTuples,
 		RequestTimeout:                DefaultRequestTimeout,
+		ShutdownDrainDelay:            0,
+		ShutdownDrainTimeout:          10 * time.Second,
+		MethodTimeouts:                []string{},
 		ContextPropagationToDatastore: false,
+		ResolutionMetadataHeaders:     false,
 	}
 }
 
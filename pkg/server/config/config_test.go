@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,6 +41,30 @@ func TestVerifyConfig(t *testing.T) {
 		require.EqualError(t, err, "config 'maxConcurrentReadsForListUsers' cannot be 0")
 	})
 
+	t.Run("negative_continuation_token_ttl", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.ContinuationTokenTTL = -time.Second
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "config 'continuationTokenTTL' must be non-negative")
+	})
+
+	t.Run("non_positive_max_tuples_per_write", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.MaxTuplesPerWrite = 0
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "config 'maxTuplesPerWrite' must be positive")
+	})
+
+	t.Run("max_page_size_out_of_range", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.MaxPageSize = 101
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "config 'maxPageSize' must be between 1 and 100")
+	})
+
 	t.Run("failing_to_set_http_cert_path_will_not_allow_server_to_start", func(t *testing.T) {
 		cfg := DefaultConfig()
 		cfg.HTTP.TLS = &TLSConfig{
@@ -338,6 +363,14 @@ func TestVerifyConfig(t *testing.T) {
 			err := cfg.Verify()
 			require.Error(t, err)
 		})
+		t.Run("revalidation_window_not_smaller_than_ttl", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.CheckQueryCache.Enabled = true
+			cfg.CheckQueryCache.TTL = 2 * time.Second
+			cfg.CheckQueryCache.RevalidationWindow = 2 * time.Second
+			err := cfg.Verify()
+			require.Error(t, err)
+		})
 		t.Run("enable_and_ttl_positive", func(t *testing.T) {
 			cfg := DefaultConfig()
 			cfg.CheckQueryCache.Enabled = true
@@ -764,6 +797,118 @@ func TestVerifyServerSettings(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("cache_controller_notification_channel", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.CacheController.NotificationChannel = "openfga_tuple_changes"
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'cacheController.notificationChannel' requires the cache controller to be enabled")
+
+		cfg.CacheController.Enabled = true
+		err = cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'cacheController.notificationChannel' requires the 'postgres' datastore engine")
+
+		cfg.Datastore.Engine = "postgres"
+		require.NoError(t, cfg.VerifyServerSettings())
+	})
+
+	t.Run("negative_datastore_timeout", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Datastore.WriteTimeout = -time.Second
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.readTimeout' and 'datastore.writeTimeout' must be non-negative time durations")
+	})
+
+	t.Run("negative_slow_query_threshold", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Datastore.SlowQueryThreshold = -time.Second
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.slowQueryThreshold' must be a non-negative time duration")
+	})
+
+	t.Run("snapshots", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Datastore.SnapshotPath = "/var/lib/openfga/snapshot.json"
+		require.NoError(t, cfg.VerifyServerSettings())
+
+		cfg.Datastore.SnapshotInterval = -time.Second
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.snapshotInterval' must be a non-negative time duration")
+
+		cfg.Datastore.SnapshotInterval = time.Minute
+		cfg.Datastore.Engine = "postgres"
+		err = cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.snapshotPath' is not supported by the 'postgres' engine")
+	})
+
+	t.Run("memory_capacity_limits", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Datastore.MemoryMaxTuplesPerStore = 1000
+		cfg.Datastore.MemoryMaxBytes = 1 << 30
+		require.NoError(t, cfg.VerifyServerSettings())
+
+		cfg.Datastore.MemoryMaxBytes = -1
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.memoryMaxTuplesPerStore' and 'datastore.memoryMaxBytes' must be non-negative")
+
+		cfg.Datastore.MemoryMaxBytes = 0
+		cfg.Datastore.Engine = "mysql"
+		err = cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.memoryMaxTuplesPerStore' and 'datastore.memoryMaxBytes' are not supported by the 'mysql' engine")
+	})
+
+	t.Run("credentials_files", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Datastore.PasswordFile = "/var/run/secrets/datastore/password"
+		cfg.Datastore.Engine = "sqlite"
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.usernameFile' and 'datastore.passwordFile' are not supported by the 'sqlite' engine")
+
+		cfg.Datastore.Engine = "postgres"
+		cfg.Datastore.CredentialsRefreshInterval = 0
+		err = cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.credentialsRefreshInterval' must be greater than zero")
+
+		cfg.Datastore.CredentialsRefreshInterval = time.Minute
+		require.NoError(t, cfg.VerifyServerSettings())
+	})
+
+	t.Run("iam_auth", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Datastore.IAMAuth.Enabled = true
+		cfg.Datastore.Engine = "postgres"
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.iamAuth.region' is required")
+
+		cfg.Datastore.IAMAuth.Region = "us-east-1"
+		cfg.Datastore.PasswordFile = "/var/run/secrets/datastore/password"
+		err = cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.iamAuth' cannot be combined with 'datastore.usernameFile' and 'datastore.passwordFile'")
+
+		cfg.Datastore.PasswordFile = ""
+		require.NoError(t, cfg.VerifyServerSettings())
+
+		cfg.Datastore.Engine = "memory"
+		err = cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.iamAuth' is not supported by the 'memory' engine")
+	})
+
+	t.Run("read_replicas_unsupported_engine", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Datastore.ReadReplicaURIs = []string{"file:/tmp/replica.db"}
+		cfg.Datastore.Engine = "sqlite"
+
+		err := cfg.VerifyServerSettings()
+		require.EqualError(t, err, "'datastore.readReplicaURIs' is not supported by the 'sqlite' engine")
+
+		cfg.Datastore.Engine = "postgres"
+		require.NoError(t, cfg.VerifyServerSettings())
+	})
+
 	t.Run("does_not_print_warning_when_log_level_is_not_none", func(t *testing.T) {
 		cfg := DefaultConfig()
 		cfg.Log.Level = "info"
@@ -789,7 +934,319 @@ func TestVerifyServerSettings(t *testing.T) {
 	})
 }
 
+func TestParseMethodTimeouts(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		timeouts, err := ParseMethodTimeouts([]string{"Check:500ms", "ListObjects:30s"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]time.Duration{
+			"Check":       500 * time.Millisecond,
+			"ListObjects": 30 * time.Second,
+		}, timeouts)
+	})
+
+	t.Run("unknown_method", func(t *testing.T) {
+		_, err := ParseMethodTimeouts([]string{"Chek:1s"})
+		require.ErrorContains(t, err, "unknown method 'Chek'")
+	})
+
+	for _, invalid := range []string{"Check", ":1s", "Check:abc", "Check:0s", "Check:-1s", "check:1s", "Chek:1s"} {
+		t.Run(invalid, func(t *testing.T) {
+			_, err := ParseMethodTimeouts([]string{invalid})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseDatastoreFaultPolicies(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		policies, err := ParseDatastoreFaultPolicies([]string{"ReadUserTuple:50ms:0.1:0s", "Read:0s:0:5ms"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]DatastoreFaultPolicy{
+			"ReadUserTuple": {Latency: 50 * time.Millisecond, ErrorRate: 0.1},
+			"Read":          {IteratorLatency: 5 * time.Millisecond},
+		}, policies)
+	})
+
+	for _, invalid := range [][]string{
+		{"ReadUserTuple:50ms:0.1"},
+		{":50ms:0.1:0s"},
+		{"ReadUserTuple:abc:0.1:0s"},
+		{"ReadUserTuple:-1s:0.1:0s"},
+		{"ReadUserTuple:50ms:1.5:0s"},
+		{"ReadUserTuple:50ms:0.1:abc"},
+		{"ReadUserTuple:50ms:0.1:0s", "ReadUserTuple:0s:0.5:0s"},
+	} {
+		t.Run(strings.Join(invalid, ","), func(t *testing.T) {
+			_, err := ParseDatastoreFaultPolicies(invalid)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseStoreTTLs(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		ttls, err := ParseStoreTTLs([]string{"01HVMMBCMGZNT3SED4Z17ECXCA:1h", "01HVMMBD123456789ABCDEFGHJ:30s"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]time.Duration{
+			"01HVMMBCMGZNT3SED4Z17ECXCA": time.Hour,
+			"01HVMMBD123456789ABCDEFGHJ": 30 * time.Second,
+		}, ttls)
+	})
+
+	for _, invalid := range []string{"01HVMMBCMGZNT3SED4Z17ECXCA", ":1h", "01HVMMBCMGZNT3SED4Z17ECXCA:abc", "01HVMMBCMGZNT3SED4Z17ECXCA:0s"} {
+		t.Run(invalid, func(t *testing.T) {
+			_, err := ParseStoreTTLs([]string{invalid})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseModelSyncSources(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		sources, err := ParseModelSyncSources([]string{
+			"01HVMMBCMGZNT3SED4Z17ECXCA:https://example.com/model.fga",
+			"01HVMMBD123456789ABCDEFGHJ:git+https://github.com/acme/models.git//model.fga?ref=main",
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"01HVMMBCMGZNT3SED4Z17ECXCA": "https://example.com/model.fga",
+			"01HVMMBD123456789ABCDEFGHJ": "git+https://github.com/acme/models.git//model.fga?ref=main",
+		}, sources)
+	})
+
+	for _, invalid := range [][]string{
+		{"01HVMMBCMGZNT3SED4Z17ECXCA"},
+		{":https://example.com/model.fga"},
+		{"01HVMMBCMGZNT3SED4Z17ECXCA:"},
+		{"01HVMMBCMGZNT3SED4Z17ECXCA:https://example.com/a.fga", "01HVMMBCMGZNT3SED4Z17ECXCA:https://example.com/b.fga"},
+	} {
+		t.Run(strings.Join(invalid, ","), func(t *testing.T) {
+			_, err := ParseModelSyncSources(invalid)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseMethodWeights(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		weights, err := ParseMethodWeights([]string{"Check:8", "ListObjects:1"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]uint32{
+			"Check":       8,
+			"ListObjects": 1,
+		}, weights)
+	})
+
+	for _, invalid := range []string{"Check", ":1", "Check:abc", "Check:0", "Check:-1"} {
+		t.Run(invalid, func(t *testing.T) {
+			_, err := ParseMethodWeights([]string{invalid})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseMethodSampleRatios(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		ratios, err := ParseMethodSampleRatios([]string{"Check:0.01", "Write*:1"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{
+			"Check":  0.01,
+			"Write*": 1,
+		}, ratios)
+	})
+
+	for _, invalid := range []string{"Check", ":1", "*:1", "Check:abc", "Check:-0.1", "Check:1.5"} {
+		t.Run(invalid, func(t *testing.T) {
+			_, err := ParseMethodSampleRatios([]string{invalid})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseOTLPHeaders(t *testing.T) {
+	headers, err := ParseOTLPHeaders([]string{"Authorization=Bearer a=b", " x-api-key = secret"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"Authorization": "Bearer a=b",
+		"x-api-key":     "secret",
+	}, headers)
+
+	for _, invalid := range []string{"Authorization", "=secret"} {
+		t.Run(invalid, func(t *testing.T) {
+			_, err := ParseOTLPHeaders([]string{invalid})
+			require.Error(t, err)
+			require.NotContains(t, err.Error(), "secret")
+		})
+	}
+}
+
 func TestVerifyBinarySettings(t *testing.T) {
+	t.Run("log_output", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Log.Rotation.Enabled = true
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.Log.Rotation.MaxBackups = -1
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'log.rotation.maxBackups' must be non-negative")
+
+		cfg.Log.Rotation.MaxAge = -time.Hour
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'log.rotation.maxAge' must be a non-negative time duration")
+
+		cfg.Log.Rotation.MaxSize = 0
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'log.rotation.maxSize' must be greater than zero")
+
+		cfg.Log.OutputPaths = nil
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'log.outputPaths' must not be empty")
+	})
+
+	t.Run("log_sampling", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Log.Sampling.Enabled = true
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.Log.Sampling.Thereafter = -1
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'log.sampling.thereafter' must be non-negative")
+
+		cfg.Log.Sampling.PerSecond = 0
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'log.sampling.perSecond' must be greater than zero")
+	})
+
+	t.Run("admin_keys", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Admin.Enabled = true
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'admin.keys' must be set when the admin server is enabled")
+
+		cfg.Admin.Keys = []string{"KEY"}
+		require.NoError(t, cfg.VerifyBinarySettings())
+	})
+
+	t.Run("oidc_additional_issuers", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Playground.Enabled = false
+		cfg.Authn.Method = "oidc"
+		cfg.Authn.AdditionalIssuers = []string{"https://machine.example.com"}
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "configs 'authn.oidc.additionalIssuers' and 'authn.oidc.additionalAudiences' must have the same length, the audience of each additional issuer")
+
+		cfg.Authn.AdditionalAudiences = []string{"openfga"}
+		require.NoError(t, cfg.VerifyBinarySettings())
+	})
+
+	t.Run("shutdown_drain_delay", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.ShutdownDrainDelay = 5 * time.Second
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.ShutdownDrainDelay = -time.Second
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'shutdownDrainDelay' must be non-negative")
+	})
+
+	t.Run("shutdown_drain_timeout", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.ShutdownDrainTimeout = 0
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.ShutdownDrainTimeout = -time.Second
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'shutdownDrainTimeout' must be non-negative")
+	})
+
+	t.Run("grpc_server_options", func(t *testing.T) {
+		cfg := DefaultConfig()
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.GRPC.MaxRecvMsgSizeBytes = 0
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'grpc.maxRecvMsgSizeBytes' must be greater than zero")
+
+		cfg = DefaultConfig()
+		cfg.GRPC.MaxSendMsgSizeBytes = -1
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'grpc.maxSendMsgSizeBytes' must be greater than zero")
+
+		cfg = DefaultConfig()
+		cfg.GRPC.Keepalive.Time = 500 * time.Millisecond
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'grpc.keepalive.time' must be at least 1s")
+
+		cfg = DefaultConfig()
+		cfg.GRPC.Keepalive.Timeout = 0
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'grpc.keepalive.timeout' must be greater than zero")
+
+		cfg = DefaultConfig()
+		cfg.GRPC.Keepalive.MaxConnectionAge = -time.Second
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "configs 'grpc.keepalive.maxConnectionAge', 'grpc.keepalive.maxConnectionAgeGrace' and 'grpc.keepalive.minTime' must be non-negative")
+	})
+
+	t.Run("redaction", func(t *testing.T) {
+		cfg := DefaultConfig()
+		for _, mode := range []string{"off", "hash", "drop"} {
+			cfg.Redaction.Mode = mode
+			require.NoError(t, cfg.VerifyBinarySettings())
+		}
+
+		cfg.Redaction.Mode = "mask"
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'redaction.mode' must be one of ['off', 'hash', 'drop']")
+	})
+
+	t.Run("log_security", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Log.Security.Enabled = true
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.Log.Security.OutputPaths = nil
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'log.security.outputPaths' must not be empty")
+
+		cfg.Log.Security.Format = "xml"
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'log.security.format' must be one of ['text', 'json']")
+	})
+
+	t.Run("otlp_metrics", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Metrics.OTLP.Enabled = true
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.Metrics.OTLP.Protocol = "thrift"
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "config 'metrics.otlp.protocol' must be one of ['grpc', 'http']")
+
+		cfg.Metrics.OTLP.Protocol = "http"
+		cfg.Metrics.OTLP.Interval = 0
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'metrics.otlp.interval' must be greater than zero")
+
+		cfg.Metrics.OTLP.Interval = time.Minute
+		cfg.Metrics.OTLP.Headers = []string{"Authorization"}
+		err = cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "invalid otlp header 'Authorization', expected '<name>=<value>'")
+	})
+
+	t.Run("metrics_store_id_label", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Metrics.StoreIDLabel.Enabled = true
+		require.NoError(t, cfg.VerifyBinarySettings())
+
+		cfg.Metrics.StoreIDLabel.Limit = 0
+		err := cfg.VerifyBinarySettings()
+		require.EqualError(t, err, "'metrics.storeIDLabel.limit' must be greater than zero when 'metrics.storeIDLabel.stores' is empty")
+
+		cfg.Metrics.StoreIDLabel.Stores = []string{"01HSTORE"}
+		require.NoError(t, cfg.VerifyBinarySettings())
+	})
+
 	t.Run("failing_to_set_http_cert_path_will_not_allow_server_to_start", func(t *testing.T) {
 		cfg := DefaultConfig()
 		cfg.HTTP.TLS = &TLSConfig{
@@ -972,6 +1429,13 @@ func TestDefaultContextTimeout(t *testing.T) {
 			},
 			expectedContextTimeout: 5*time.Second + additionalUpstreamTimeout,
 		},
+		"method_timeout_longer_than_request_timeout": {
+			config: Config{
+				RequestTimeout: 5 * time.Second,
+				MethodTimeouts: []string{"Check:500ms", "ListObjects:30s"},
+			},
+			expectedContextTimeout: 30*time.Second + additionalUpstreamTimeout,
+		},
 		"only_http_config_timeout": {
 			config: Config{
 				HTTP: HTTPConfig{
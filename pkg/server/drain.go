@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errDraining is returned to the requests received while the server drains.
+var errDraining = status.Error(codes.Unavailable, "the server is shutting down")
+
+// drainer tracks the requests in flight, so that the server can wait for them to complete before it shuts down.
+type drainer struct {
+	mu       sync.Mutex
+	notReady bool
+	draining bool
+	inflight int
+
+	// idle is closed once the server drains and no request is in flight.
+	idle chan struct{}
+}
+
+// acquire registers a request in flight. It returns false if the server drains, in which case the request must be
+// refused.
+func (d *drainer) acquire() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inflight++
+	return true
+}
+
+// release unregisters a request acquired before.
+func (d *drainer) release() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inflight--
+	if d.draining && d.inflight == 0 {
+		close(d.idle)
+	}
+}
+
+// stopReadiness reports the server as not ready, while it keeps serving the new requests.
+func (d *drainer) stopReadiness() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notReady = true
+}
+
+// drain starts draining and returns a channel closed once no request is in flight.
+func (d *drainer) drain() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.draining {
+		d.draining = true
+		d.idle = make(chan struct{})
+		if d.inflight == 0 {
+			close(d.idle)
+		}
+	}
+	return d.idle
+}
+
+func (d *drainer) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.notReady || d.draining
+}
+
+// Drain prepares the server to shut down: it reports itself as not ready, keeps serving the new requests for the
+// delay set by WithDrainDelay, so that the load balancers stop routing them to the server, then refuses them with an
+// Unavailable error and waits for the requests in flight to complete. It returns the error of ctx if they do not
+// complete before ctx is done. The requests are tracked by the interceptors returned by NewDrainUnaryInterceptor and
+// NewDrainStreamingInterceptor, which must be installed on the gRPC server.
+//
+// The server still serves the requests in flight once Drain returns, Close must be called to release its resources.
+func (s *Server) Drain(ctx context.Context) error {
+	s.drainer.stopReadiness()
+	if s.drainDelay > 0 {
+		timer := time.NewTimer(s.drainDelay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	idle := s.drainer.drain()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsDraining returns true once Drain was called.
+func (s *Server) IsDraining() bool {
+	return s.drainer.isDraining()
+}
+
+// isDrainedMethod returns true if fullMethod is a method of the services of the server, as opposed to e.g. the
+// health checks, which must keep being served while the server drains.
+func isDrainedMethod(fullMethod string) bool {
+	return !strings.HasPrefix(fullMethod, "/grpc.")
+}
+
+// NewDrainUnaryInterceptor returns an interceptor tracking the unary requests of s in flight, and refusing them once
+// s drains.
+func NewDrainUnaryInterceptor(s *Server) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isDrainedMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if !s.drainer.acquire() {
+			return nil, errDraining
+		}
+		defer s.drainer.release()
+		return handler(ctx, req)
+	}
+}
+
+// NewDrainStreamingInterceptor returns an interceptor tracking the streaming requests of s in flight, and refusing
+// them once s drains.
+func NewDrainStreamingInterceptor(s *Server) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isDrainedMethod(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		if !s.drainer.acquire() {
+			return errDraining
+		}
+		defer s.drainer.release()
+		return handler(srv, stream)
+	}
+}
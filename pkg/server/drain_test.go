@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDrain(t *testing.T) {
+	s := &Server{}
+	interceptor := NewDrainUnaryInterceptor(s)
+	checkInfo := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}
+	healthInfo := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), nil, checkInfo, func(context.Context, any) (any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		done <- err
+	}()
+	<-started
+
+	require.False(t, s.IsDraining())
+
+	// the request in flight keeps the server from draining
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.Drain(ctx), context.DeadlineExceeded)
+	require.True(t, s.IsDraining())
+
+	// the new requests are refused, but the health checks
+	_, err := interceptor(context.Background(), nil, checkInfo, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = interceptor(context.Background(), nil, healthInfo, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, s.Drain(context.Background()))
+}
+
+func TestDrainDelay(t *testing.T) {
+	s := &Server{drainDelay: 100 * time.Millisecond}
+	interceptor := NewDrainUnaryInterceptor(s)
+	checkInfo := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}
+	check := func() error {
+		_, err := interceptor(context.Background(), nil, checkInfo, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- s.Drain(context.Background())
+	}()
+
+	// the server is not ready, but keeps serving the new requests during the delay
+	require.Eventually(t, s.IsDraining, time.Second, time.Millisecond)
+	require.NoError(t, check())
+
+	require.NoError(t, <-drained)
+	require.Equal(t, codes.Unavailable, status.Code(check()))
+
+	t.Run("cancelled_during_the_delay", func(t *testing.T) {
+		s := &Server{drainDelay: time.Hour}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, s.Drain(ctx), context.DeadlineExceeded)
+		require.True(t, s.IsDraining())
+	})
+}
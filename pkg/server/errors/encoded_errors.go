@@ -22,6 +22,8 @@ const (
 type ErrorResponse struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// Reason is the reason of the google.rpc.ErrorInfo detail of the error, e.g. "MODEL_NOT_FOUND".
+	Reason  string `json:"reason,omitempty"`
 	codeInt int32
 }
 
@@ -48,7 +50,11 @@ func (e *EncodedError) HTTPStatus() int {
 }
 
 func (e *EncodedError) GRPCStatus() *status.Status {
-	return status.New(e.GRPCStatusCode, e.Error())
+	st := status.New(e.GRPCStatusCode, e.Error())
+	if e.ActualError.Reason == "" {
+		return st
+	}
+	return status.Convert(withReason(st, e.ActualError.Reason, e.Code()))
 }
 
 // Code returns the encoded code in string.
@@ -130,6 +136,15 @@ func NewEncodedError(errorCode int32, message string) *EncodedError {
 	}
 }
 
+// EncodeError returns the encoded error of the error returned by a gRPC handler, with the reason of its
+// google.rpc.ErrorInfo detail.
+func EncodeError(err error) *EncodedError {
+	st := status.Convert(err)
+	encoded := NewEncodedError(ConvertToEncodedErrorCode(st), err.Error())
+	encoded.ActualError.Reason = Reason(st)
+	return encoded
+}
+
 // IsValidEncodedError returns whether the error code is a valid encoded error.
 func IsValidEncodedError(errorCode int32) bool {
 	return errorCode >= cFirstAuthenticationErrorCode
@@ -0,0 +1,216 @@
+package errors
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+// ErrorInfoDomain is the domain of the google.rpc.ErrorInfo details attached to the errors of the server.
+const ErrorInfoDomain = "openfga.dev"
+
+// errorInfoCodeKey is the key of the metadata of the google.rpc.ErrorInfo details holding the OpenFGA error code,
+// e.g. "authorization_model_not_found".
+const errorInfoCodeKey = "code"
+
+// The reasons of the google.rpc.ErrorInfo details attached to the errors of the server, to branch on them. Unlike the
+// OpenFGA error codes, there are few of them and they are stable, e.g. all the validation errors of a request have the
+// reason VALIDATION_ERROR.
+const (
+	ReasonValidationError           = "VALIDATION_ERROR"
+	ReasonStoreNotFound             = "STORE_NOT_FOUND"
+	ReasonModelNotFound             = "MODEL_NOT_FOUND"
+	ReasonTypeNotFound              = "TYPE_NOT_FOUND"
+	ReasonRelationNotFound          = "RELATION_NOT_FOUND"
+	ReasonInvalidModel              = "INVALID_MODEL"
+	ReasonInvalidTuple              = "INVALID_TUPLE"
+	ReasonInvalidContinuationToken  = "INVALID_CONTINUATION_TOKEN"
+	ReasonConditionEvaluationFailed = "CONDITION_EVALUATION_FAILED"
+	ReasonResolutionTooComplex      = "RESOLUTION_TOO_COMPLEX"
+	ReasonLimitExceeded             = "LIMIT_EXCEEDED"
+	ReasonContextualTuplesExceeded  = "CONTEXTUAL_TUPLES_LIMIT_EXCEEDED"
+	ReasonContextTooLarge           = "CONTEXT_TOO_LARGE"
+	ReasonContextTooDeep            = "CONTEXT_TOO_DEEP"
+	ReasonThrottled                 = "THROTTLED"
+	ReasonUnauthenticated           = "UNAUTHENTICATED"
+	ReasonPermissionDenied          = "PERMISSION_DENIED"
+	ReasonConflict                  = "CONFLICT"
+	ReasonCancelled                 = "CANCELLED"
+	ReasonDeadlineExceeded          = "DEADLINE_EXCEEDED"
+	ReasonUnavailable               = "UNAVAILABLE"
+	ReasonNotFound                  = "NOT_FOUND"
+	ReasonUnimplemented             = "UNIMPLEMENTED"
+	ReasonInternal                  = "INTERNAL"
+)
+
+// ConditionEvaluationError returns the error of a condition which could not be evaluated, e.g. as a parameter is
+// missing from the context. It is a validation error with the reason CONDITION_EVALUATION_FAILED.
+func ConditionEvaluationError(cause error) error {
+	return withReason(
+		status.New(codes.Code(openfgav1.ErrorCode_validation_error), cause.Error()),
+		ReasonConditionEvaluationFailed,
+		openfgav1.ErrorCode_validation_error.String(),
+	)
+}
+
+// ContextualTuplesLimitExceeded returns the error of a request with more contextual tuples than the limit, with the
+// reason CONTEXTUAL_TUPLES_LIMIT_EXCEEDED.
+func ContextualTuplesLimitExceeded(count int, limit uint32) error {
+	return withReason(
+		status.New(codes.Code(openfgav1.ErrorCode_exceeded_entity_limit), fmt.Sprintf("the number of contextual tuples %d exceeds the allowed limit of %d", count, limit)),
+		ReasonContextualTuplesExceeded,
+		openfgav1.ErrorCode_exceeded_entity_limit.String(),
+	)
+}
+
+// ContextTooLarge returns the error of a request whose context exceeds the size limit, with the reason
+// CONTEXT_TOO_LARGE.
+func ContextTooLarge(cause error) error {
+	return withReason(
+		status.New(codes.Code(openfgav1.ErrorCode_validation_error), cause.Error()),
+		ReasonContextTooLarge,
+		openfgav1.ErrorCode_validation_error.String(),
+	)
+}
+
+// ContextTooDeep returns the error of a request whose context exceeds the depth limit, with the reason
+// CONTEXT_TOO_DEEP.
+func ContextTooDeep(cause error) error {
+	return withReason(
+		status.New(codes.Code(openfgav1.ErrorCode_validation_error), cause.Error()),
+		ReasonContextTooDeep,
+		openfgav1.ErrorCode_validation_error.String(),
+	)
+}
+
+// withReason returns the error of the status with a google.rpc.ErrorInfo detail of the reason and the OpenFGA error
+// code.
+func withReason(st *status.Status, reason, code string) error {
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorInfoDomain,
+		Metadata: map[string]string{errorInfoCodeKey: code},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// WithErrorInfo returns the error with a google.rpc.ErrorInfo detail of its reason, unless it already has one. Its
+// code and message are preserved.
+func WithErrorInfo(err error) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	if errorInfo(st) != nil {
+		return err
+	}
+	return withReason(st, Reason(st), NewEncodedError(ConvertToEncodedErrorCode(st), st.Message()).Code())
+}
+
+// errorInfo returns the google.rpc.ErrorInfo detail of the status, if any.
+func errorInfo(st *status.Status) *errdetails.ErrorInfo {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorInfoDomain {
+			return info
+		}
+	}
+	return nil
+}
+
+// Reason returns the reason of the google.rpc.ErrorInfo detail of the status or, if it has none, the reason of its
+// code.
+func Reason(st *status.Status) string {
+	if info := errorInfo(st); info != nil {
+		return info.GetReason()
+	}
+
+	switch st.Code() {
+	case codes.OK:
+		return ""
+	case codes.PermissionDenied:
+		return ReasonPermissionDenied
+	case codes.Aborted:
+		return ReasonConflict
+	}
+
+	code := ConvertToEncodedErrorCode(st)
+	switch {
+	case code >= cFirstAuthenticationErrorCode && code < cFirstValidationErrorCode:
+		if code == int32(openfgav1.AuthErrorCode_forbidden) {
+			return ReasonPermissionDenied
+		}
+		return ReasonUnauthenticated
+	case code >= cFirstValidationErrorCode && code < cFirstThrottlingErrorCode:
+		return validationReason(openfgav1.ErrorCode(code))
+	case code >= cFirstThrottlingErrorCode && code < cFirstInternalErrorCode:
+		return ReasonThrottled
+	case code >= cFirstInternalErrorCode && code < cFirstUnknownEndpointErrorCode:
+		switch openfgav1.InternalErrorCode(code) {
+		case openfgav1.InternalErrorCode_deadline_exceeded:
+			return ReasonDeadlineExceeded
+		case openfgav1.InternalErrorCode_resource_exhausted:
+			return ReasonThrottled
+		case openfgav1.InternalErrorCode_unavailable:
+			return ReasonUnavailable
+		case openfgav1.InternalErrorCode_already_exists, openfgav1.InternalErrorCode_aborted:
+			return ReasonConflict
+		default:
+			return ReasonInternal
+		}
+	case code >= cFirstUnknownEndpointErrorCode:
+		switch openfgav1.NotFoundErrorCode(code) {
+		case openfgav1.NotFoundErrorCode_store_id_not_found:
+			return ReasonStoreNotFound
+		case openfgav1.NotFoundErrorCode_unimplemented:
+			return ReasonUnimplemented
+		default:
+			return ReasonNotFound
+		}
+	default:
+		return ReasonInternal
+	}
+}
+
+// validationReason returns the reason of a validation error code.
+func validationReason(code openfgav1.ErrorCode) string {
+	switch code {
+	case openfgav1.ErrorCode_authorization_model_not_found,
+		openfgav1.ErrorCode_latest_authorization_model_not_found,
+		openfgav1.ErrorCode_authorization_model_assertions_not_found:
+		return ReasonModelNotFound
+	case openfgav1.ErrorCode_type_not_found:
+		return ReasonTypeNotFound
+	case openfgav1.ErrorCode_relation_not_found, openfgav1.ErrorCode_unknown_relation:
+		return ReasonRelationNotFound
+	case openfgav1.ErrorCode_invalid_authorization_model,
+		openfgav1.ErrorCode_unsupported_schema_version,
+		openfgav1.ErrorCode_cannot_allow_duplicate_types_in_one_request,
+		openfgav1.ErrorCode_cannot_allow_multiple_references_to_one_relation,
+		openfgav1.ErrorCode_empty_relation_definition:
+		return ReasonInvalidModel
+	case openfgav1.ErrorCode_invalid_tuple,
+		openfgav1.ErrorCode_invalid_contextual_tuple,
+		openfgav1.ErrorCode_duplicate_contextual_tuple,
+		openfgav1.ErrorCode_cannot_allow_duplicate_tuples_in_one_request,
+		openfgav1.ErrorCode_write_failed_due_to_invalid_input:
+		return ReasonInvalidTuple
+	case openfgav1.ErrorCode_invalid_continuation_token,
+		openfgav1.ErrorCode_query_string_type_continuation_token_mismatch:
+		return ReasonInvalidContinuationToken
+	case openfgav1.ErrorCode_authorization_model_resolution_too_complex:
+		return ReasonResolutionTooComplex
+	case openfgav1.ErrorCode_exceeded_entity_limit:
+		return ReasonLimitExceeded
+	case openfgav1.ErrorCode_cancelled:
+		return ReasonCancelled
+	default:
+		return ReasonValidationError
+	}
+}
@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+func TestWithErrorInfo(t *testing.T) {
+	tests := map[string]struct {
+		err            error
+		expectedReason string
+		expectedCode   string
+	}{
+		`model_not_found`: {
+			err:            AuthorizationModelNotFound("01JQ0000000000000000000000"),
+			expectedReason: ReasonModelNotFound,
+			expectedCode:   openfgav1.ErrorCode_authorization_model_not_found.String(),
+		},
+		`latest_model_not_found`: {
+			err:            LatestAuthorizationModelNotFound("01JQ0000000000000000000000"),
+			expectedReason: ReasonModelNotFound,
+			expectedCode:   openfgav1.ErrorCode_latest_authorization_model_not_found.String(),
+		},
+		`store_not_found`: {
+			err:            ErrStoreIDNotFound,
+			expectedReason: ReasonStoreNotFound,
+			expectedCode:   openfgav1.NotFoundErrorCode_store_id_not_found.String(),
+		},
+		`throttled_timeout`: {
+			err:            ErrThrottledTimeout,
+			expectedReason: ReasonThrottled,
+			expectedCode:   openfgav1.UnprocessableContentErrorCode_throttled_timeout_error.String(),
+		},
+		`transaction_throttled`: {
+			err:            ErrTransactionThrottled,
+			expectedReason: ReasonThrottled,
+			expectedCode:   openfgav1.InternalErrorCode_resource_exhausted.String(),
+		},
+		`condition_evaluation_failed`: {
+			err:            ConditionEvaluationError(errors.New("missing context parameters")),
+			expectedReason: ReasonConditionEvaluationFailed,
+			expectedCode:   openfgav1.ErrorCode_validation_error.String(),
+		},
+		`validation_error`: {
+			err:            status.Error(codes.InvalidArgument, "invalid CheckRequest.StoreId: value does not match regex pattern"),
+			expectedReason: ReasonValidationError,
+			expectedCode:   openfgav1.ErrorCode_validation_error.String(),
+		},
+		`permission_denied`: {
+			err:            status.Error(codes.PermissionDenied, "the credentials are not allowed to access this store"),
+			expectedReason: ReasonPermissionDenied,
+			expectedCode:   openfgav1.InternalErrorCode_internal_error.String(),
+		},
+		`datastore_unavailable`: {
+			err:            ErrDatastoreUnavailable,
+			expectedReason: ReasonUnavailable,
+			expectedCode:   openfgav1.InternalErrorCode_unavailable.String(),
+		},
+		`deadline_exceeded`: {
+			err:            ErrRequestDeadlineExceeded,
+			expectedReason: ReasonDeadlineExceeded,
+			expectedCode:   openfgav1.InternalErrorCode_deadline_exceeded.String(),
+		},
+		`internal`: {
+			err:            NewInternalError("", errors.New("internal")),
+			expectedReason: ReasonInternal,
+			expectedCode:   openfgav1.InternalErrorCode_internal_error.String(),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := WithErrorInfo(test.err)
+
+			st := status.Convert(err)
+			require.Equal(t, status.Convert(test.err).Code(), st.Code())
+			require.Equal(t, status.Convert(test.err).Message(), st.Message())
+			require.Equal(t, test.expectedReason, Reason(st))
+
+			require.Len(t, st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			require.Equal(t, ErrorInfoDomain, info.GetDomain())
+			require.Equal(t, test.expectedReason, info.GetReason())
+			require.Equal(t, test.expectedCode, info.GetMetadata()["code"])
+
+			// the detail is not attached twice
+			require.Len(t, status.Convert(WithErrorInfo(err)).Details(), 1)
+		})
+	}
+
+	require.NoError(t, WithErrorInfo(nil))
+}
+
+func TestEncodeError(t *testing.T) {
+	encoded := EncodeError(WithErrorInfo(AuthorizationModelNotFound("01JQ0000000000000000000000")))
+	require.Equal(t, http.StatusBadRequest, encoded.HTTPStatus())
+	require.Equal(t, openfgav1.ErrorCode_authorization_model_not_found.String(), encoded.Code())
+	require.Equal(t, ReasonModelNotFound, encoded.ActualError.Reason)
+
+	// the gateway streams the reason of the errors too
+	require.Equal(t, ReasonModelNotFound, Reason(encoded.GRPCStatus()))
+	require.Equal(t, codes.InvalidArgument, encoded.GRPCStatus().Code())
+}
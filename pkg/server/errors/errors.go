@@ -11,6 +11,7 @@ import (
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/pkg/encoder"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/tuple"
 )
@@ -22,6 +23,8 @@ var (
 	ErrAuthorizationModelResolutionTooComplex = status.Error(codes.Code(openfgav1.ErrorCode_authorization_model_resolution_too_complex), "Authorization Model resolution required too many rewrite rules to be resolved. Check your authorization model for infinite recursion or too much nesting")
 	ErrInvalidWriteInput                      = status.Error(codes.Code(openfgav1.ErrorCode_invalid_write_input), "Invalid input. Make sure you provide at least one write, or at least one delete")
 	ErrInvalidContinuationToken               = status.Error(codes.Code(openfgav1.ErrorCode_invalid_continuation_token), "Invalid continuation token")
+	ErrContinuationTokenExpired               = status.Error(codes.Code(openfgav1.ErrorCode_invalid_continuation_token), "Continuation token expired, restart the pagination without it")
+	ErrIncompatibleContinuationToken          = status.Error(codes.Code(openfgav1.ErrorCode_invalid_continuation_token), "Continuation token is incompatible with this server version, restart the pagination without it")
 	ErrInvalidStartTime                       = status.Error(codes.Code(openfgav1.ErrorCode_invalid_start_time), "Invalid start time")
 	ErrInvalidExpandInput                     = status.Error(codes.Code(openfgav1.ErrorCode_invalid_expand_input), "Invalid input. Make sure you provide an object and a relation")
 	ErrUnsupportedUserSet                     = status.Error(codes.Code(openfgav1.ErrorCode_unsupported_user_set), "Userset is not supported (right now)")
@@ -33,6 +36,15 @@ var (
 
 	// ErrTransactionThrottled can apply when a limit is hit at the database level.
 	ErrTransactionThrottled = status.Error(codes.ResourceExhausted, "transaction was throttled by the datastore")
+
+	// ErrDatastoreUnavailable applies while the datastore circuit breaker fails calls fast.
+	ErrDatastoreUnavailable = status.Error(codes.Unavailable, "the datastore is unavailable, retry later")
+
+	// ErrDatastoreDeadlineExceeded applies when a datastore query is cancelled after exceeding its statement timeout.
+	ErrDatastoreDeadlineExceeded = status.Error(codes.DeadlineExceeded, "datastore deadline exceeded")
+
+	// ErrDatastoreCapacityExceeded applies when a write would exceed the capacity limits of the datastore.
+	ErrDatastoreCapacityExceeded = status.Error(codes.ResourceExhausted, "the datastore capacity is exceeded")
 )
 
 type InternalError struct {
@@ -105,6 +117,11 @@ func ExceededEntityLimit(entity string, limit int) error {
 		fmt.Sprintf("The number of %s exceeds the allowed limit of %d", entity, limit))
 }
 
+// PageSizeTooLarge returns the error of a paginated request whose page size exceeds the maximum page size.
+func PageSizeTooLarge(pageSize, maxPageSize int32) error {
+	return status.Error(codes.Code(openfgav1.ErrorCode_page_size_invalid), fmt.Sprintf("page size %d exceeds the maximum page size of %d", pageSize, maxPageSize))
+}
+
 func DuplicateTupleInWrite(tk tuple.TupleWithoutCondition) error {
 	return status.Error(codes.Code(openfgav1.ErrorCode_cannot_allow_duplicate_tuples_in_one_request), fmt.Sprintf("duplicate tuple in write: user: '%s', relation: '%s', object: '%s'", tk.GetUser(), tk.GetRelation(), tk.GetObject()))
 }
@@ -123,6 +140,13 @@ func HandleError(public string, err error) error {
 	switch {
 	case errors.Is(err, storage.ErrTransactionThrottled):
 		return ErrTransactionThrottled
+	case errors.Is(err, storage.ErrCircuitOpen):
+		return ErrDatastoreUnavailable
+	case errors.Is(err, storage.ErrDatastoreDeadlineExceeded):
+		// checked before context.DeadlineExceeded, which it wraps
+		return ErrDatastoreDeadlineExceeded
+	case errors.Is(err, storage.ErrCapacityExceeded):
+		return ErrDatastoreCapacityExceeded
 	case errors.Is(err, context.Canceled):
 		// cancel by a client is not an "internal server error"
 		return ErrRequestCancelled
@@ -137,6 +161,19 @@ func HandleError(public string, err error) error {
 	}
 }
 
+// ContinuationTokenError returns the error of a continuation token which could not be decoded, telling the expired
+// and the incompatible tokens apart from the invalid ones.
+func ContinuationTokenError(err error) error {
+	switch {
+	case errors.Is(err, encoder.ErrContinuationTokenExpired):
+		return ErrContinuationTokenExpired
+	case errors.Is(err, encoder.ErrContinuationTokenIncompatible):
+		return ErrIncompatibleContinuationToken
+	default:
+		return ErrInvalidContinuationToken
+	}
+}
+
 // HandleTupleValidateError provide common routines for handling tuples validation error.
 func HandleTupleValidateError(err error) error {
 	switch t := err.(type) {
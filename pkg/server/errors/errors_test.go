@@ -72,6 +72,18 @@ func TestHandleErrors(t *testing.T) {
 			storageErr:              storage.ErrTransactionThrottled,
 			expectedTranslatedError: ErrTransactionThrottled,
 		},
+		`circuit_open`: {
+			storageErr:              storage.ErrCircuitOpen,
+			expectedTranslatedError: ErrDatastoreUnavailable,
+		},
+		`datastore_deadline_exceeded`: {
+			storageErr:              fmt.Errorf("%w: Read: %w", storage.ErrDatastoreDeadlineExceeded, context.DeadlineExceeded),
+			expectedTranslatedError: ErrDatastoreDeadlineExceeded,
+		},
+		`capacity_exceeded`: {
+			storageErr:              fmt.Errorf("%w: store '01H' would hold 11 tuples, above the limit of 10", storage.ErrCapacityExceeded),
+			expectedTranslatedError: ErrDatastoreCapacityExceeded,
+		},
 	}
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
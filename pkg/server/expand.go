@@ -2,26 +2,35 @@ package server
 
 import (
 	"context"
+	"fmt"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/utils/apimethod"
 	"github.com/openfga/openfga/pkg/middleware/validator"
+	"github.com/openfga/openfga/pkg/redact"
 	"github.com/openfga/openfga/pkg/server/commands"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/telemetry"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
 func (s *Server) Expand(ctx context.Context, req *openfgav1.ExpandRequest) (*openfgav1.ExpandResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.Expand)
+	defer cancel()
+
 	tk := req.GetTupleKey()
 	ctx, span := tracer.Start(ctx, apimethod.Expand.String(), trace.WithAttributes(
 		attribute.KeyValue{Key: "store_id", Value: attribute.StringValue(req.GetStoreId())},
-		attribute.KeyValue{Key: "object", Value: attribute.StringValue(tk.GetObject())},
+		attribute.KeyValue{Key: "object", Value: attribute.StringValue(redact.Object(tk.GetObject()))},
 		attribute.KeyValue{Key: "relation", Value: attribute.StringValue(tk.GetRelation())},
 		attribute.KeyValue{Key: "consistency", Value: attribute.StringValue(req.GetConsistency().String())},
 	))
@@ -50,7 +59,22 @@ func (s *Server) Expand(ctx context.Context, req *openfgav1.ExpandRequest) (*ope
 		return nil, err
 	}
 
-	q := commands.NewExpandQuery(s.datastore, commands.WithExpandQueryLogger(s.logger))
+	reqCtx, err := expandContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRequestLimits(apimethod.Expand, reqCtx, len(req.GetContextualTuples().GetTupleKeys())); err != nil {
+		return nil, err
+	}
+	if reqCtx != nil {
+		// Without a context, Expand does not evaluate conditions, so there is nothing to trust.
+		reqCtx = s.withTrustedContext(ctx, reqCtx)
+	}
+
+	q := commands.NewExpandQuery(s.datastore,
+		commands.WithExpandQueryLogger(s.logger),
+		commands.WithExpandQueryContext(reqCtx),
+	)
 	return q.Execute(
 		typesystem.ContextWithTypesystem(ctx, typesys),
 		&openfgav1.ExpandRequest{
@@ -60,3 +84,22 @@ func (s *Server) Expand(ctx context.Context, req *openfgav1.ExpandRequest) (*ope
 			ContextualTuples: req.GetContextualTuples(),
 		})
 }
+
+// expandContext returns the context requested in the metadata of ctx through the ExpandContextHeader key, if any.
+func expandContext(ctx context.Context) (*structpb.Struct, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	values := md.Get(ExpandContextHeader)
+	if len(values) == 0 || values[0] == "" {
+		return nil, nil
+	}
+
+	reqCtx := &structpb.Struct{}
+	if err := protojson.Unmarshal([]byte(values[0]), reqCtx); err != nil {
+		return nil, serverErrors.ValidationError(fmt.Errorf("invalid %s: %w", ExpandContextHeader, err))
+	}
+	return reqCtx, nil
+}
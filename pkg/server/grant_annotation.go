@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/concurrency"
+	"github.com/openfga/openfga/pkg/gateway"
+	"github.com/openfga/openfga/pkg/server/commands"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// maxAnnotatedGrants is the number of results of a request annotated with how they were granted.
+const maxAnnotatedGrants = 100
+
+// grantAnnotationRequested reports whether the results of the request are to be annotated with how they were
+// granted, through the AnnotateGrantsHeader request metadata key.
+func grantAnnotationRequested(ctx context.Context) (bool, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false, nil
+	}
+	values := md.Get(AnnotateGrantsHeader)
+	if len(values) == 0 || values[0] == "" {
+		return false, nil
+	}
+
+	requested, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, serverErrors.ValidationError(fmt.Errorf("invalid %s '%s', expected a boolean", AnnotateGrantsHeader, values[0]))
+	}
+	return requested, nil
+}
+
+// setResultGrantsTrailer sets the ResultGrantsHeader trailer to how the results were granted the relation of params,
+// as comma separated 'result=kind'. Only the first maxAnnotatedGrants results are annotated, as annotating a result
+// costs a datastore read and possibly a Check. objectAndUser returns the object and the user of a result.
+func (s *Server) setResultGrantsTrailer(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	params *commands.GrantAnnotationParams,
+	results []string,
+	objectAndUser func(result string) (string, string),
+) error {
+	if len(results) > maxAnnotatedGrants {
+		results = results[:maxAnnotatedGrants]
+	}
+
+	checkQuery := commands.NewCheckCommand(s.datastore, s.checkResolver, typesys, s.checkCommandOptions(ctx)...)
+	annotator := commands.NewGrantAnnotator(s.datastore, checkQuery)
+
+	// the results are annotated as concurrently as the checks of a BatchCheck
+	grants := make([]string, len(results))
+	pool := concurrency.NewPool(ctx, int(s.maxConcurrentChecksPerBatch))
+	for i, result := range results {
+		pool.Go(func(ctx context.Context) error {
+			object, user := objectAndUser(result)
+			kind, err := annotator.Annotate(ctx, params, object, user)
+			if err != nil {
+				return err
+			}
+			grants[i] = result + "=" + string(kind)
+			return nil
+		})
+	}
+	if err := pool.Wait(); err != nil {
+		return commands.CheckCommandErrorToServerError(err)
+	}
+
+	// a trailer, unlike a header, can still be set once the results of StreamedListObjects have been sent
+	gateway.SetTrailer(ctx, s.transport, ResultGrantsHeader, strings.Join(grants, ","))
+	return nil
+}
+
+// grantRecordingStream records the objects sent to a StreamedListObjects stream, up to maxAnnotatedGrants, so that
+// they can be annotated once the stream completes.
+type grantRecordingStream struct {
+	openfgav1.OpenFGAService_StreamedListObjectsServer
+
+	objects []string
+}
+
+func (g *grantRecordingStream) Send(resp *openfgav1.StreamedListObjectsResponse) error {
+	if err := g.OpenFGAService_StreamedListObjectsServer.Send(resp); err != nil {
+		return err
+	}
+	if len(g.objects) < maxAnnotatedGrants {
+		g.objects = append(g.objects, resp.GetObject())
+	}
+	return nil
+}
@@ -3,6 +3,9 @@ package health
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"time"
 
 	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
 	"google.golang.org/grpc/codes"
@@ -15,10 +18,29 @@ type TargetService interface {
 	IsReady(ctx context.Context) (bool, error)
 }
 
+// TargetServiceFunc adapts a function to a [TargetService].
+type TargetServiceFunc func(ctx context.Context) (bool, error)
+
+// IsReady calls f.
+func (f TargetServiceFunc) IsReady(ctx context.Context) (bool, error) {
+	return f(ctx)
+}
+
+// DefaultWatchInterval is how often Watch checks the status of a service, if the Checker does not set it.
+const DefaultWatchInterval = time.Second
+
+// Checker serves the gRPC Health Checking protocol. The status of the server, i.e. of the empty service name, is
+// SERVING when all of its services are.
 type Checker struct {
 	healthv1pb.UnimplementedHealthServer
 	TargetService
 	TargetServiceName string
+
+	// Services are the other services of the server, by name, e.g. the admin service.
+	Services map[string]TargetService
+
+	// WatchInterval is how often Watch checks the status of the watched service. Defaults to DefaultWatchInterval.
+	WatchInterval time.Duration
 }
 
 var _ grpcauth.ServiceAuthFuncOverride = (*Checker)(nil)
@@ -29,23 +51,108 @@ func (o *Checker) AuthFuncOverride(ctx context.Context, fullMethodName string) (
 }
 
 func (o *Checker) Check(ctx context.Context, req *healthv1pb.HealthCheckRequest) (*healthv1pb.HealthCheckResponse, error) {
-	requestedService := req.GetService()
-	if requestedService == "" || requestedService == o.TargetServiceName {
-		ready, err := o.IsReady(ctx)
-		if err != nil {
-			return &healthv1pb.HealthCheckResponse{Status: healthv1pb.HealthCheckResponse_NOT_SERVING}, err
+	servingStatus, err := o.servingStatus(ctx, req.GetService())
+	if err != nil && servingStatus == healthv1pb.HealthCheckResponse_UNKNOWN {
+		return nil, err
+	}
+	return &healthv1pb.HealthCheckResponse{Status: servingStatus}, err
+}
+
+// Watch sends the status of the service, then each of its changes, until the client cancels the stream. The status
+// of a service which is not registered is SERVICE_UNKNOWN, as per the protocol.
+func (o *Checker) Watch(req *healthv1pb.HealthCheckRequest, server healthv1pb.Health_WatchServer) error {
+	interval := o.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ctx := server.Context()
+	last := healthv1pb.HealthCheckResponse_UNKNOWN
+	for {
+		servingStatus, err := o.servingStatus(ctx, req.GetService())
+		if status.Code(err) == codes.NotFound {
+			servingStatus = healthv1pb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		if servingStatus != last {
+			if err := server.Send(&healthv1pb.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return err
+			}
+			last = servingStatus
 		}
 
-		if !ready {
-			return &healthv1pb.HealthCheckResponse{Status: healthv1pb.HealthCheckResponse_NOT_SERVING}, nil
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// servingStatus returns the status of the service, or an error with the code NotFound if it is not registered.
+func (o *Checker) servingStatus(ctx context.Context, service string) (healthv1pb.HealthCheckResponse_ServingStatus, error) {
+	var targets []TargetService
+	switch target, ok := o.Services[service]; {
+	case service == "":
+		targets = append(targets, o.TargetService)
+		for _, target := range o.Services {
+			targets = append(targets, target)
 		}
+	case service == o.TargetServiceName:
+		targets = append(targets, o.TargetService)
+	case ok:
+		targets = append(targets, target)
+	default:
+		return healthv1pb.HealthCheckResponse_UNKNOWN, status.Errorf(codes.NotFound, "service '%s' is not registered with the Health server", service)
+	}
 
-		return &healthv1pb.HealthCheckResponse{Status: healthv1pb.HealthCheckResponse_SERVING}, nil
+	for _, target := range targets {
+		ready, err := target.IsReady(ctx)
+		if err != nil {
+			return healthv1pb.HealthCheckResponse_NOT_SERVING, err
+		}
+		if !ready {
+			return healthv1pb.HealthCheckResponse_NOT_SERVING, nil
+		}
 	}
 
-	return nil, status.Errorf(codes.NotFound, "service '%s' is not registered with the Health server", requestedService)
+	return healthv1pb.HealthCheckResponse_SERVING, nil
 }
 
-func (o *Checker) Watch(req *healthv1pb.HealthCheckRequest, server healthv1pb.Health_WatchServer) error {
-	return status.Error(codes.Unimplemented, "unimplemented streaming endpoint")
+// ComponentStatus is the readiness of one of the components a server depends on, e.g. its datastore.
+type ComponentStatus struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// ComponentsService defines an interface that services can implement to report the readiness of each of their
+// components.
+type ComponentsService interface {
+	ReadinessComponents(ctx context.Context) []ComponentStatus
+}
+
+type componentsResponse struct {
+	Ready      bool              `json:"ready"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// NewComponentsHandler returns the handler serving the readiness of each component of the service as JSON, with
+// the status code 503 if any of them is not ready.
+func NewComponentsHandler(service ComponentsService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := componentsResponse{Ready: true, Components: service.ReadinessComponents(r.Context())}
+		for _, component := range res.Components {
+			res.Ready = res.Ready && component.Ready
+		}
+
+		code := http.StatusOK
+		if !res.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(res)
+	})
 }
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthv1pb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type watchServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses chan healthv1pb.HealthCheckResponse_ServingStatus
+}
+
+func (w *watchServer) Context() context.Context {
+	return w.ctx
+}
+
+func (w *watchServer) Send(res *healthv1pb.HealthCheckResponse) error {
+	w.responses <- res.GetStatus()
+	return nil
+}
+
+func TestChecker(t *testing.T) {
+	var openfgaReady, adminReady atomic.Bool
+	checker := &Checker{
+		TargetService: TargetServiceFunc(func(context.Context) (bool, error) {
+			return openfgaReady.Load(), nil
+		}),
+		TargetServiceName: "openfga.v1.OpenFGAService",
+		Services: map[string]TargetService{
+			"openfga.admin.v1.AdminService": TargetServiceFunc(func(context.Context) (bool, error) {
+				return adminReady.Load(), nil
+			}),
+		},
+		WatchInterval: time.Millisecond,
+	}
+
+	check := func(service string) healthv1pb.HealthCheckResponse_ServingStatus {
+		res, err := checker.Check(context.Background(), &healthv1pb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return res.GetStatus()
+	}
+
+	t.Run("check", func(t *testing.T) {
+		openfgaReady.Store(true)
+		adminReady.Store(false)
+		require.Equal(t, healthv1pb.HealthCheckResponse_SERVING, check("openfga.v1.OpenFGAService"))
+		require.Equal(t, healthv1pb.HealthCheckResponse_NOT_SERVING, check("openfga.admin.v1.AdminService"))
+		require.Equal(t, healthv1pb.HealthCheckResponse_NOT_SERVING, check(""))
+
+		adminReady.Store(true)
+		require.Equal(t, healthv1pb.HealthCheckResponse_SERVING, check(""))
+
+		_, err := checker.Check(context.Background(), &healthv1pb.HealthCheckRequest{Service: "unknown"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		openfgaReady.Store(false)
+		ctx, cancel := context.WithCancel(context.Background())
+		server := &watchServer{ctx: ctx, responses: make(chan healthv1pb.HealthCheckResponse_ServingStatus, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- checker.Watch(&healthv1pb.HealthCheckRequest{Service: "openfga.v1.OpenFGAService"}, server)
+		}()
+
+		require.Equal(t, healthv1pb.HealthCheckResponse_NOT_SERVING, <-server.responses)
+		openfgaReady.Store(true)
+		require.Equal(t, healthv1pb.HealthCheckResponse_SERVING, <-server.responses)
+		openfgaReady.Store(false)
+		require.Equal(t, healthv1pb.HealthCheckResponse_NOT_SERVING, <-server.responses)
+
+		cancel()
+		require.Equal(t, codes.Canceled, status.Code(<-done))
+	})
+
+	t.Run("watch_unknown_service", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		server := &watchServer{ctx: ctx, responses: make(chan healthv1pb.HealthCheckResponse_ServingStatus, 10)}
+		go func() {
+			_ = checker.Watch(&healthv1pb.HealthCheckRequest{Service: "unknown"}, server)
+		}()
+
+		require.Equal(t, healthv1pb.HealthCheckResponse_SERVICE_UNKNOWN, <-server.responses)
+	})
+}
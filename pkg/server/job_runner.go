@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// jobUpdateTimeout bounds the update persisting the final status of a job, which outlives the job itself.
+const jobUpdateTimeout = 10 * time.Second
+
+var (
+	errJobCancelled   = errors.New("the job was cancelled")
+	errJobInterrupted = errors.New("the server shut down before the job completed")
+)
+
+// jobFunc runs the operation of a job. It calls checkpoint after each batch, which persists the progress of the job
+// and returns an error, which jobFunc must return, once the job must stop.
+type jobFunc func(ctx context.Context, job *storage.Job, checkpoint func() error) error
+
+// jobRunner runs the jobs started on this server in the background, at most maxJobs at once. The jobs wait in the
+// pending status for a slot.
+type jobRunner struct {
+	datastore storage.OpenFGADatastore
+	logger    logger.Logger
+	slots     chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]context.CancelCauseFunc // GUARDED_BY(mu), keyed by jobKey.
+}
+
+func newJobRunner(datastore storage.OpenFGADatastore, logger logger.Logger, maxJobs int) *jobRunner {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &jobRunner{
+		datastore: datastore,
+		logger:    logger,
+		slots:     make(chan struct{}, maxJobs),
+		ctx:       ctx,
+		cancel:    cancel,
+		running:   make(map[string]context.CancelCauseFunc),
+	}
+}
+
+func jobKey(storeID, jobID string) string {
+	return storeID + "|" + jobID
+}
+
+// Start runs fn for the job, which must be persisted as pending, in the background.
+func (r *jobRunner) Start(job *storage.Job, fn jobFunc) {
+	ctx, cancel := context.WithCancelCause(r.ctx)
+	key := jobKey(job.StoreID, job.ID)
+
+	r.mu.Lock()
+	r.running[key] = cancel
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			r.mu.Lock()
+			delete(r.running, key)
+			r.mu.Unlock()
+			cancel(nil)
+		}()
+
+		r.run(ctx, job, fn)
+	}()
+}
+
+// Cancel stops the job if it is run by this server. The jobs run by other servers stop at their next checkpoint.
+func (r *jobRunner) Cancel(storeID, jobID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cancel, ok := r.running[jobKey(storeID, jobID)]; ok {
+		cancel(errJobCancelled)
+	}
+}
+
+// Close interrupts the jobs being run or pending, which are marked as interrupted so that they are resumed from their
+// last checkpoint by [Server.ResumeJobs], and waits for them to stop.
+func (r *jobRunner) Close() {
+	r.cancel(errJobInterrupted)
+	r.wg.Wait()
+}
+
+func (r *jobRunner) run(ctx context.Context, job *storage.Job, fn jobFunc) {
+	select {
+	case r.slots <- struct{}{}:
+		defer func() { <-r.slots }()
+	case <-ctx.Done():
+		r.finish(ctx, job, context.Cause(ctx))
+		return
+	}
+
+	checkpoint := func() error { return r.checkpoint(ctx, job) }
+
+	job.Status = storage.JobStatusRunning
+	err := checkpoint()
+	if err == nil {
+		err = fn(ctx, job, checkpoint)
+	}
+	r.finish(ctx, job, err)
+}
+
+// checkpoint persists the progress of the job, and returns an error if the job must stop, i.e. if it was cancelled,
+// possibly through another server, or if the server is shutting down.
+func (r *jobRunner) checkpoint(ctx context.Context, job *storage.Job) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	if err := r.datastore.UpdateJob(ctx, job); err != nil {
+		return err
+	}
+
+	persisted, err := r.datastore.ReadJob(ctx, job.StoreID, job.ID)
+	if err != nil {
+		return err
+	}
+	if persisted.CancelRequested {
+		return errJobCancelled
+	}
+	return nil
+}
+
+// finish persists the final status of the job according to the error it stopped on.
+func (r *jobRunner) finish(ctx context.Context, job *storage.Job, err error) {
+	// The operations of the job fail with the cancellation of its context rather than the reason it was cancelled.
+	if ctx.Err() != nil {
+		err = context.Cause(ctx)
+	}
+
+	switch {
+	case err == nil:
+		job.Status = storage.JobStatusSucceeded
+	case errors.Is(err, errJobCancelled):
+		job.Status = storage.JobStatusCancelled
+	case errors.Is(err, errJobInterrupted):
+		job.Status = storage.JobStatusInterrupted
+	default:
+		job.Status = storage.JobStatusFailed
+		job.Error = err.Error()
+	}
+
+	updateCtx, cancel := context.WithTimeout(context.Background(), jobUpdateTimeout)
+	defer cancel()
+
+	if err := r.datastore.UpdateJob(updateCtx, job); err != nil {
+		r.logger.Error("failed to persist the status of a job",
+			zap.String("store_id", job.StoreID),
+			zap.String("job_id", job.ID),
+			zap.String("status", string(job.Status)),
+			zap.Error(err))
+		return
+	}
+
+	r.logger.Info("job completed",
+		zap.String("store_id", job.StoreID),
+		zap.String("job_id", job.ID),
+		zap.String("job_type", job.Type),
+		zap.String("status", string(job.Status)),
+		zap.Int64("progress", job.Progress))
+}
@@ -0,0 +1,417 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/telemetry"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+// JobType is the kind of operation run by a job.
+type JobType string
+
+const (
+	// JobTypeImportTuples writes tuples to a store, in batches of the maximum number of tuples per write. It is
+	// rejected if it has more than the maximum number of tuples of a job.
+	JobTypeImportTuples JobType = "import_tuples"
+	// JobTypeExportTuples reads the tuples of a store matching a filter. Its result is decoded with [ExportedTuples].
+	// The export fails once it reads more than the maximum number of tuples of a job.
+	JobTypeExportTuples JobType = "export_tuples"
+	// JobTypeDeleteTuples deletes the tuples of a store matching a filter.
+	JobTypeDeleteTuples JobType = "delete_tuples"
+)
+
+// exportPageSize is the number of tuples read per page by an export.
+const exportPageSize = 100
+
+// StartJobRequest is the operation started by StartJob.
+type StartJobRequest struct {
+	StoreID string
+	Type    JobType
+	// AuthorizationModelID is the model the tuples of an import are validated against. If empty, the latest model of
+	// the store is used.
+	AuthorizationModelID string
+	// Tuples are the tuples written by an import.
+	Tuples []*openfgav1.TupleKey
+	// Filter selects the tuples of an export or a delete, as the tuple key of a Read request does. If nil, every tuple
+	// of the store is selected.
+	Filter *openfgav1.ReadRequestTupleKey
+}
+
+// StartJob persists a job running the operation of req in the background, beyond the lifetime of the request, and
+// returns it in the pending status. Its status is then followed with GetJobStatus. Imports and deletes require the
+// permission to write the tuples of the store, and exports the permission to read them.
+func (s *Server) StartJob(ctx context.Context, req *StartJobRequest) (*storage.Job, error) {
+	method := apimethod.Write
+	if req.Type == JobTypeExportTuples {
+		method = apimethod.Read
+	}
+
+	ctx, cancel := s.withMethodTimeout(ctx, method)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "StartJob", trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.String("job_type", string(req.Type)),
+	))
+	defer span.End()
+
+	if _, err := ulid.ParseStrict(req.StoreID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid store id")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  "StartJob",
+	})
+
+	var params proto.Message
+	switch req.Type {
+	case JobTypeImportTuples:
+		if len(req.Tuples) == 0 {
+			return nil, serverErrors.ValidationError(errors.New("an import requires tuples"))
+		}
+		if len(req.Tuples) > s.maxJobTuples {
+			return nil, serverErrors.ValidationError(fmt.Errorf("an import is limited to %d tuples", s.maxJobTuples))
+		}
+
+		typesys, err := s.resolveTypesystem(ctx, req.StoreID, req.AuthorizationModelID)
+		if err != nil {
+			return nil, err
+		}
+
+		writeReq := &openfgav1.WriteRequest{
+			StoreId:              req.StoreID,
+			AuthorizationModelId: typesys.GetAuthorizationModelID(), // the resolved model id
+			Writes:               &openfgav1.WriteRequestWrites{TupleKeys: req.Tuples},
+		}
+		if err := s.checkWriteAuthz(ctx, writeReq, typesys); err != nil {
+			return nil, err
+		}
+		params = writeReq
+	case JobTypeExportTuples, JobTypeDeleteTuples:
+		if err := s.checkAuthz(ctx, req.StoreID, method); err != nil {
+			return nil, err
+		}
+
+		params = &openfgav1.ReadRequest{StoreId: req.StoreID, TupleKey: req.Filter}
+	default:
+		return nil, serverErrors.ValidationError(fmt.Errorf("unknown job type '%s'", req.Type))
+	}
+
+	encoded, err := proto.Marshal(params)
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	job := &storage.Job{
+		ID:      ulid.Make().String(),
+		StoreID: req.StoreID,
+		Type:    string(req.Type),
+		Params:  encoded,
+		Status:  storage.JobStatusPending,
+	}
+	if err := s.datastore.CreateJob(ctx, job); err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	// The runner updates its own copy of the job.
+	started := *job
+	s.jobs.Start(&started, s.jobFunc(req.Type))
+
+	return job, nil
+}
+
+// ResumeJobs claims the jobs interrupted by the shutdown of a server and runs them again in the background, from their
+// last checkpoint. It is called once the server starts.
+func (s *Server) ResumeJobs(ctx context.Context) error {
+	jobs, err := s.datastore.ClaimInterruptedJobs(ctx)
+	for _, job := range jobs {
+		run := s.jobFunc(JobType(job.Type))
+		if run == nil {
+			// The job was started by a server knowing more job types.
+			job.Status = storage.JobStatusFailed
+			job.Error = fmt.Sprintf("unknown job type '%s'", job.Type)
+			if err := s.datastore.UpdateJob(ctx, job); err != nil {
+				return err
+			}
+			continue
+		}
+
+		s.logger.Info("resuming an interrupted job",
+			zap.String("store_id", job.StoreID),
+			zap.String("job_id", job.ID),
+			zap.String("job_type", job.Type),
+			zap.Int64("progress", job.Progress))
+		s.jobs.Start(job, run)
+	}
+	return err
+}
+
+func (s *Server) jobFunc(jobType JobType) jobFunc {
+	switch jobType {
+	case JobTypeImportTuples:
+		return s.importTuples
+	case JobTypeExportTuples:
+		return s.exportTuples
+	case JobTypeDeleteTuples:
+		return s.deleteTuples
+	default:
+		return nil
+	}
+}
+
+// GetJobStatus returns a job of the store, with its status and progress. It requires the permission to read the
+// tuples of the store.
+func (s *Server) GetJobStatus(ctx context.Context, storeID, jobID string) (*storage.Job, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.Read)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "GetJobStatus", trace.WithAttributes(
+		attribute.String("store_id", storeID),
+		attribute.String("job_id", jobID),
+	))
+	defer span.End()
+
+	ctx, err := s.authorizeJobRequest(ctx, "GetJobStatus", storeID, jobID, apimethod.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.readJob(ctx, storeID, jobID)
+}
+
+// CancelJob requests a job of the store to stop and returns it. The job stops after its current batch, in the
+// cancelled status, without reverting the batches already applied. It requires the permission to write the tuples of
+// the store.
+func (s *Server) CancelJob(ctx context.Context, storeID, jobID string) (*storage.Job, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.Write)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "CancelJob", trace.WithAttributes(
+		attribute.String("store_id", storeID),
+		attribute.String("job_id", jobID),
+	))
+	defer span.End()
+
+	ctx, err := s.authorizeJobRequest(ctx, "CancelJob", storeID, jobID, apimethod.Write)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.readJob(ctx, storeID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != storage.JobStatusPending && job.Status != storage.JobStatusRunning && job.Status != storage.JobStatusInterrupted {
+		return nil, status.Errorf(codes.FailedPrecondition, "the job already completed with the status '%s'", job.Status)
+	}
+
+	if err := s.datastore.RequestJobCancellation(ctx, storeID, jobID); err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+	s.jobs.Cancel(storeID, jobID)
+
+	job.CancelRequested = true
+	return job, nil
+}
+
+// ExportedTuples returns the tuples read by a succeeded export job.
+func ExportedTuples(job *storage.Job) ([]*openfgav1.Tuple, error) {
+	if job.Type != string(JobTypeExportTuples) {
+		return nil, fmt.Errorf("the job is not an export but a '%s' job", job.Type)
+	}
+	if job.Status != storage.JobStatusSucceeded {
+		return nil, fmt.Errorf("the export has the status '%s'", job.Status)
+	}
+
+	var exported openfgav1.ReadResponse
+	if err := proto.Unmarshal(job.Result, &exported); err != nil {
+		return nil, err
+	}
+	return exported.GetTuples(), nil
+}
+
+// authorizeJobRequest validates the ids of a request to a job and checks that the caller may call method on the store.
+func (s *Server) authorizeJobRequest(ctx context.Context, rpc, storeID, jobID string, method apimethod.APIMethod) (context.Context, error) {
+	if _, err := ulid.ParseStrict(storeID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid store id")
+	}
+	if _, err := ulid.ParseStrict(jobID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid job id")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  rpc,
+	})
+
+	if err := s.checkAuthz(ctx, storeID, method); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+func (s *Server) readJob(ctx context.Context, storeID, jobID string) (*storage.Job, error) {
+	job, err := s.datastore.ReadJob(ctx, storeID, jobID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "job not found")
+		}
+		return nil, serverErrors.HandleError("", err)
+	}
+	return job, nil
+}
+
+// importTuples writes the tuples of an import in batches.
+func (s *Server) importTuples(ctx context.Context, job *storage.Job, checkpoint func() error) error {
+	var req openfgav1.WriteRequest
+	if err := proto.Unmarshal(job.Params, &req); err != nil {
+		return err
+	}
+
+	batchSize := s.datastore.MaxTuplesPerWrite()
+	if s.maxTuplesPerWrite > 0 && s.maxTuplesPerWrite < batchSize {
+		batchSize = s.maxTuplesPerWrite
+	}
+
+	cmd := s.jobWriteCommand()
+	tuples := req.GetWrites().GetTupleKeys()
+	for start := int(job.Progress); start < len(tuples); start += batchSize {
+		end := min(start+batchSize, len(tuples))
+		_, err := cmd.Execute(ctx, &openfgav1.WriteRequest{
+			StoreId:              req.GetStoreId(),
+			AuthorizationModelId: req.GetAuthorizationModelId(),
+			Writes:               &openfgav1.WriteRequestWrites{TupleKeys: tuples[start:end]},
+		})
+		if err != nil {
+			return err
+		}
+
+		job.Progress = int64(end)
+		if err := checkpoint(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportTuples reads the tuples matching the filter of an export, page by page, into its result. The result holds the
+// continuation token of the next page until the export completes, so that an interrupted export resumes from it.
+func (s *Server) exportTuples(ctx context.Context, job *storage.Job, checkpoint func() error) error {
+	var req openfgav1.ReadRequest
+	if err := proto.Unmarshal(job.Params, &req); err != nil {
+		return err
+	}
+	req.PageSize = wrapperspb.Int32(exportPageSize)
+
+	exported := &openfgav1.ReadResponse{}
+	if err := proto.Unmarshal(job.Result, exported); err != nil {
+		return err
+	}
+	req.ContinuationToken = exported.GetContinuationToken()
+
+	q := commands.NewReadQuery(s.datastore,
+		commands.WithReadQueryLogger(s.logger),
+		commands.WithReadQueryEncoder(s.encoder),
+		commands.WithReadQueryTokenSerializer(s.tokenSerializer),
+	)
+
+	for {
+		page, err := q.Execute(ctx, &req)
+		if err != nil {
+			return err
+		}
+		exported.Tuples = append(exported.Tuples, page.GetTuples()...)
+		if len(exported.GetTuples()) > s.maxJobTuples {
+			return fmt.Errorf("the export exceeds the maximum of %d tuples of a job, narrow its filter", s.maxJobTuples)
+		}
+		exported.ContinuationToken = page.GetContinuationToken()
+
+		result, err := proto.Marshal(exported)
+		if err != nil {
+			return err
+		}
+		job.Result = result
+		job.Progress = int64(len(exported.GetTuples()))
+		if exported.GetContinuationToken() == "" {
+			return nil
+		}
+		if err := checkpoint(); err != nil {
+			return err
+		}
+		req.ContinuationToken = exported.GetContinuationToken()
+	}
+}
+
+// deleteTuples deletes the tuples matching the filter of a delete, a page at a time, until none matches.
+func (s *Server) deleteTuples(ctx context.Context, job *storage.Job, checkpoint func() error) error {
+	var req openfgav1.ReadRequest
+	if err := proto.Unmarshal(job.Params, &req); err != nil {
+		return err
+	}
+
+	pageSize := s.datastore.MaxTuplesPerWrite()
+	if s.maxTuplesPerWrite > 0 && s.maxTuplesPerWrite < pageSize {
+		pageSize = s.maxTuplesPerWrite
+	}
+	req.PageSize = wrapperspb.Int32(int32(min(pageSize, exportPageSize)))
+	// The deletes must see the deletes of the previous pages.
+	req.Consistency = openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY
+
+	q := commands.NewReadQuery(s.datastore, commands.WithReadQueryLogger(s.logger))
+	cmd := s.jobWriteCommand()
+	for {
+		page, err := q.Execute(ctx, &req)
+		if err != nil {
+			return err
+		}
+		if len(page.GetTuples()) == 0 {
+			return nil
+		}
+
+		deletes := make([]*openfgav1.TupleKeyWithoutCondition, 0, len(page.GetTuples()))
+		for _, t := range page.GetTuples() {
+			deletes = append(deletes, tupleUtils.TupleKeyToTupleKeyWithoutCondition(t.GetKey()))
+		}
+		_, err = cmd.Execute(ctx, &openfgav1.WriteRequest{
+			StoreId: req.GetStoreId(),
+			Deletes: &openfgav1.WriteRequestDeletes{TupleKeys: deletes},
+		})
+		if err != nil {
+			return err
+		}
+
+		job.Progress += int64(len(deletes))
+		if err := checkpoint(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) jobWriteCommand() *commands.WriteCommand {
+	return commands.NewWriteCommand(
+		s.datastore,
+		commands.WithWriteCmdLogger(s.logger),
+		commands.WithProtectedTuples(s.protectedTuples),
+		commands.WithWriteCmdMaxTuplesPerWrite(s.maxTuplesPerWrite),
+		commands.WithWriteCmdTypesystemResolver(s.typesystemResolver),
+	)
+}
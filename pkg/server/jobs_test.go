@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestJobs(t *testing.T) {
+	ctx := context.Background()
+	ds := memory.New()
+	s := MustNewServerWithOpts(WithDatastore(ds), WithMaxTuplesPerWrite(2))
+	t.Cleanup(s.Close)
+
+	createStoreResp, err := s.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "jobs"})
+	require.NoError(t, err)
+	storeID := createStoreResp.GetId()
+
+	_, err = s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+		StoreId: storeID,
+		TypeDefinitions: parser.MustTransformDSLToProto(`
+			model
+				schema 1.1
+			type user
+			type doc
+				relations
+					define viewer: [user]`).GetTypeDefinitions(),
+		SchemaVersion: "1.1",
+	})
+	require.NoError(t, err)
+
+	waitForJob := func(t *testing.T, jobID string) *storage.Job {
+		var job *storage.Job
+		require.Eventually(t, func() bool {
+			var err error
+			job, err = s.GetJobStatus(ctx, storeID, jobID)
+			require.NoError(t, err)
+			return job.Status != storage.JobStatusPending && job.Status != storage.JobStatusRunning
+		}, 5*time.Second, 10*time.Millisecond)
+		return job
+	}
+
+	t.Run("import_export_and_delete", func(t *testing.T) {
+		job, err := s.StartJob(ctx, &StartJobRequest{
+			StoreID: storeID,
+			Type:    JobTypeImportTuples,
+			Tuples: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+				tuple.NewTupleKey("doc:1", "viewer", "user:bob"),
+				tuple.NewTupleKey("doc:1", "viewer", "user:charlie"),
+				tuple.NewTupleKey("doc:2", "viewer", "user:anne"),
+				tuple.NewTupleKey("doc:3", "viewer", "user:anne"),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, storage.JobStatusPending, job.Status)
+
+		job = waitForJob(t, job.ID)
+		require.Equal(t, storage.JobStatusSucceeded, job.Status, job.Error)
+		require.Equal(t, int64(5), job.Progress)
+
+		job, err = s.StartJob(ctx, &StartJobRequest{StoreID: storeID, Type: JobTypeDeleteTuples, Filter: &openfgav1.ReadRequestTupleKey{Object: "doc:1"}})
+		require.NoError(t, err)
+		job = waitForJob(t, job.ID)
+		require.Equal(t, storage.JobStatusSucceeded, job.Status, job.Error)
+		require.Equal(t, int64(3), job.Progress)
+
+		job, err = s.StartJob(ctx, &StartJobRequest{StoreID: storeID, Type: JobTypeExportTuples})
+		require.NoError(t, err)
+		job = waitForJob(t, job.ID)
+		require.Equal(t, storage.JobStatusSucceeded, job.Status, job.Error)
+
+		exported, err := ExportedTuples(job)
+		require.NoError(t, err)
+		require.Len(t, exported, 2)
+		for _, tk := range exported {
+			require.NotEqual(t, "doc:1", tk.GetKey().GetObject())
+		}
+
+		_, err = s.CancelJob(ctx, storeID, job.ID)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("an_invalid_import_fails", func(t *testing.T) {
+		job, err := s.StartJob(ctx, &StartJobRequest{
+			StoreID: storeID,
+			Type:    JobTypeImportTuples,
+			Tuples:  []*openfgav1.TupleKey{tuple.NewTupleKey("doc:1", "editor", "user:anne")},
+		})
+		require.NoError(t, err)
+
+		job = waitForJob(t, job.ID)
+		require.Equal(t, storage.JobStatusFailed, job.Status)
+		require.NotEmpty(t, job.Error)
+	})
+
+	t.Run("an_interrupted_import_resumes_from_its_checkpoint", func(t *testing.T) {
+		// The tuples before the checkpoint are invalid, so the import only succeeds if it skips them.
+		params, err := proto.Marshal(&openfgav1.WriteRequest{
+			StoreId: storeID,
+			Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("doc:4", "editor", "user:anne"),
+				tuple.NewTupleKey("doc:4", "editor", "user:bob"),
+				tuple.NewTupleKey("doc:4", "viewer", "user:charlie"),
+			}},
+		})
+		require.NoError(t, err)
+
+		job := &storage.Job{
+			ID:      ulid.Make().String(),
+			StoreID: storeID,
+			Type:    string(JobTypeImportTuples),
+			Params:  params,
+			Status:  storage.JobStatusPending,
+		}
+		require.NoError(t, ds.CreateJob(ctx, job))
+		job.Status = storage.JobStatusInterrupted
+		job.Progress = 2
+		require.NoError(t, ds.UpdateJob(ctx, job))
+
+		require.NoError(t, s.ResumeJobs(ctx))
+
+		job = waitForJob(t, job.ID)
+		require.Equal(t, storage.JobStatusSucceeded, job.Status, job.Error)
+		require.Equal(t, int64(3), job.Progress)
+
+		readResp, err := s.Read(ctx, &openfgav1.ReadRequest{StoreId: storeID, TupleKey: &openfgav1.ReadRequestTupleKey{Object: "doc:4"}})
+		require.NoError(t, err)
+		require.Len(t, readResp.GetTuples(), 1)
+	})
+
+	t.Run("invalid_requests", func(t *testing.T) {
+		_, err := s.StartJob(ctx, &StartJobRequest{StoreID: storeID, Type: "clone_store"})
+		require.Error(t, err)
+
+		_, err = s.StartJob(ctx, &StartJobRequest{StoreID: storeID, Type: JobTypeImportTuples})
+		require.Error(t, err)
+
+		_, err = s.GetJobStatus(ctx, storeID, ulid.Make().String())
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = s.GetJobStatus(ctx, storeID, "foo")
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestJobsMaxTuples(t *testing.T) {
+	ctx := context.Background()
+	s := MustNewServerWithOpts(WithDatastore(memory.New()), WithMaxJobTuples(2))
+	t.Cleanup(s.Close)
+
+	createStoreResp, err := s.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "jobs"})
+	require.NoError(t, err)
+	storeID := createStoreResp.GetId()
+
+	_, err = s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+		StoreId: storeID,
+		TypeDefinitions: parser.MustTransformDSLToProto(`
+			model
+				schema 1.1
+			type user
+			type doc
+				relations
+					define viewer: [user]`).GetTypeDefinitions(),
+		SchemaVersion: "1.1",
+	})
+	require.NoError(t, err)
+
+	tuples := []*openfgav1.TupleKey{
+		tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("doc:2", "viewer", "user:anne"),
+		tuple.NewTupleKey("doc:3", "viewer", "user:anne"),
+	}
+
+	_, err = s.StartJob(ctx, &StartJobRequest{StoreID: storeID, Type: JobTypeImportTuples, Tuples: tuples})
+	require.ErrorContains(t, err, "an import is limited to 2 tuples")
+
+	_, err = s.Write(ctx, &openfgav1.WriteRequest{StoreId: storeID, Writes: &openfgav1.WriteRequestWrites{TupleKeys: tuples}})
+	require.NoError(t, err)
+
+	job, err := s.StartJob(ctx, &StartJobRequest{StoreID: storeID, Type: JobTypeExportTuples})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		job, err = s.GetJobStatus(ctx, storeID, job.ID)
+		require.NoError(t, err)
+		return job.Status == storage.JobStatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Contains(t, job.Error, "the export exceeds the maximum of 2 tuples")
+}
+
+func TestJobRunner(t *testing.T) {
+	ctx := context.Background()
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	// untilStopped runs checkpoints until the job must stop.
+	untilStopped := func(ctx context.Context, job *storage.Job, checkpoint func() error) error {
+		for {
+			job.Progress++
+			if err := checkpoint(); err != nil {
+				return err
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	newJob := func(t *testing.T) *storage.Job {
+		job := &storage.Job{ID: ulid.Make().String(), StoreID: ulid.Make().String(), Type: "test", Status: storage.JobStatusPending}
+		require.NoError(t, ds.CreateJob(ctx, job))
+		return job
+	}
+
+	waitForStatus := func(t *testing.T, job *storage.Job, status storage.JobStatus) *storage.Job {
+		var persisted *storage.Job
+		require.Eventually(t, func() bool {
+			var err error
+			persisted, err = ds.ReadJob(ctx, job.StoreID, job.ID)
+			require.NoError(t, err)
+			return persisted.Status == status
+		}, 5*time.Second, time.Millisecond)
+		return persisted
+	}
+
+	t.Run("cancelled_through_the_datastore", func(t *testing.T) {
+		r := newJobRunner(ds, logger.NewNoopLogger(), 1)
+		t.Cleanup(r.Close)
+
+		job := newJob(t)
+		r.Start(job, untilStopped)
+		waitForStatus(t, job, storage.JobStatusRunning)
+
+		// e.g. by another server
+		require.NoError(t, ds.RequestJobCancellation(ctx, job.StoreID, job.ID))
+		persisted := waitForStatus(t, job, storage.JobStatusCancelled)
+		require.Positive(t, persisted.Progress)
+	})
+
+	t.Run("pending_until_a_slot_is_free", func(t *testing.T) {
+		r := newJobRunner(ds, logger.NewNoopLogger(), 1)
+		t.Cleanup(r.Close)
+
+		running, pending := newJob(t), newJob(t)
+		r.Start(running, untilStopped)
+		waitForStatus(t, running, storage.JobStatusRunning)
+		r.Start(pending, untilStopped)
+
+		// a pending job is cancelled without being run
+		r.Cancel(pending.StoreID, pending.ID)
+		persisted := waitForStatus(t, pending, storage.JobStatusCancelled)
+		require.Zero(t, persisted.Progress)
+
+		r.Cancel(running.StoreID, running.ID)
+		waitForStatus(t, running, storage.JobStatusCancelled)
+	})
+
+	t.Run("interrupted_on_close", func(t *testing.T) {
+		r := newJobRunner(ds, logger.NewNoopLogger(), 1)
+
+		job := newJob(t)
+		r.Start(job, untilStopped)
+		waitForStatus(t, job, storage.JobStatusRunning)
+
+		r.Close()
+		persisted, err := ds.ReadJob(ctx, job.StoreID, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, storage.JobStatusInterrupted, persisted.Status)
+		require.Empty(t, persisted.Error)
+		require.Positive(t, persisted.Progress)
+	})
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"time"
@@ -10,14 +11,18 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/internal/middleware/limitoverrides"
 	"github.com/openfga/openfga/internal/throttler/threshold"
 	"github.com/openfga/openfga/internal/utils"
 	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/gateway"
 	"github.com/openfga/openfga/pkg/middleware/validator"
+	"github.com/openfga/openfga/pkg/redact"
 	"github.com/openfga/openfga/pkg/server/commands"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/telemetry"
@@ -25,6 +30,9 @@ import (
 )
 
 func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequest) (*openfgav1.ListObjectsResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ListObjects)
+	defer cancel()
+
 	start := time.Now()
 
 	targetObjectType := req.GetType()
@@ -33,7 +41,7 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		attribute.String("store_id", req.GetStoreId()),
 		attribute.String("object_type", targetObjectType),
 		attribute.String("relation", req.GetRelation()),
-		attribute.String("user", req.GetUser()),
+		attribute.String("user", redact.User(req.GetUser())),
 		attribute.String("consistency", req.GetConsistency().String()),
 	))
 	defer span.End()
@@ -57,6 +65,15 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		return nil, err
 	}
 
+	if err := s.validateRequestLimits(apimethod.ListObjects, req.GetContext(), len(req.GetContextualTuples().GetTupleKeys())); err != nil {
+		return nil, err
+	}
+
+	annotateGrants, err := grantAnnotationRequested(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	storeID := req.GetStoreId()
 
 	typesys, err := s.resolveTypesystem(ctx, storeID, req.GetAuthorizationModelId())
@@ -64,28 +81,36 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		return nil, err
 	}
 
+	overrides := limitoverrides.FromContext(ctx)
+	settings := s.TunableSettings()
+
 	q, err := commands.NewListObjectsQuery(
 		s.datastore,
 		s.listObjectsCheckResolver,
 		commands.WithLogger(s.logger),
-		commands.WithListObjectsDeadline(s.listObjectsDeadline),
-		commands.WithListObjectsMaxResults(s.listObjectsMaxResults),
+		commands.WithListObjectsDeadline(cmp.Or(overrides.ListObjectsDeadline, settings.ListObjectsDeadline)),
+		commands.WithListObjectsMaxResults(settings.ListObjectsMaxResults),
 		commands.WithDispatchThrottlerConfig(threshold.Config{
 			Throttler:    s.listObjectsDispatchThrottler,
 			Enabled:      s.listObjectsDispatchThrottlingEnabled,
 			Threshold:    s.listObjectsDispatchDefaultThreshold,
 			MaxThreshold: s.listObjectsDispatchThrottlingMaxThreshold,
 		}),
-		commands.WithResolveNodeLimit(s.resolveNodeLimit),
+		commands.WithResolveNodeLimit(cmp.Or(overrides.MaxResolveDepth, s.resolveNodeLimit)),
+		commands.WithListObjectsCheckMaxResolutionDepth(overrides.MaxResolveDepth),
 		commands.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
-		commands.WithMaxConcurrentReads(s.maxConcurrentReadsForListObjects),
-		commands.WithListObjectsCache(s.sharedDatastoreResources, s.cacheSettings),
+		commands.WithMaxConcurrentReads(settings.MaxConcurrentReadsForListObjects),
+		commands.WithListObjectsCache(s.sharedDatastoreResources, s.requestCacheSettings()),
 		commands.WithListObjectsDatastoreThrottler(s.listObjectsDatastoreThrottleThreshold, s.listObjectsDatastoreThrottleDuration),
+		commands.WithListObjectsLimiterSaturationMonitor(s.datastoreLimiterSaturationMonitor),
+		commands.WithListObjectsDatastoreQueryBudget(s.listObjectsDatastoreQueryBudget),
 	)
 	if err != nil {
 		return nil, serverErrors.NewInternalError("", err)
 	}
 
+	reqCtx := s.withTrustedContext(ctx, req.GetContext())
+
 	result, err := q.Execute(
 		typesystem.ContextWithTypesystem(ctx, typesys),
 		&openfgav1.ListObjectsRequest{
@@ -95,14 +120,14 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 			Type:                 targetObjectType,
 			Relation:             req.GetRelation(),
 			User:                 req.GetUser(),
-			Context:              req.GetContext(),
+			Context:              reqCtx,
 			Consistency:          req.GetConsistency(),
 		},
 	)
 	if err != nil {
 		telemetry.TraceError(span, err)
 		if errors.Is(err, condition.ErrEvaluationFailed) {
-			return nil, serverErrors.ValidationError(err)
+			return nil, serverErrors.ConditionEvaluationError(err)
 		}
 
 		return nil, err
@@ -111,10 +136,11 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 
 	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, datastoreQueryCount)
 	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, datastoreQueryCount))
-	datastoreQueryCountHistogram.WithLabelValues(
+	telemetry.ObserveWithTraceExemplar(ctx, datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
-	).Observe(datastoreQueryCount)
+		s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+	), datastoreQueryCount)
 
 	dispatchCount := float64(result.ResolutionMetadata.DispatchCounter.Load())
 
@@ -125,19 +151,46 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		methodName,
 	).Observe(dispatchCount)
 
-	requestDurationHistogram.WithLabelValues(
+	telemetry.ObserveWithTraceExemplar(ctx, requestDurationHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
 		utils.Bucketize(uint(datastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
 		utils.Bucketize(uint(result.ResolutionMetadata.DispatchCounter.Load()), s.requestDurationByDispatchCountHistogramBuckets),
 		req.GetConsistency().String(),
-	).Observe(float64(time.Since(start).Milliseconds()))
+		s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+	), float64(time.Since(start).Milliseconds()))
 
 	wasRequestThrottled := result.ResolutionMetadata.WasThrottled.Load()
 	if wasRequestThrottled {
 		throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Inc()
 	}
 
+	s.setResolutionMetadataHeaders(ctx,
+		result.ResolutionMetadata.DatastoreQueryCount.Load(),
+		result.ResolutionMetadata.DispatchCounter.Load(),
+		result.ResolutionMetadata.CheckCacheHits.Load(),
+	)
+
+	if reason := result.ResolutionMetadata.TruncationReason(); reason != commands.NotTruncated {
+		s.recordListObjectsTruncation(ctx, methodName, reason)
+		s.transport.SetHeader(ctx, ListObjectsTruncatedHeader, reason.String())
+	}
+
+	if annotateGrants {
+		err := s.setResultGrantsTrailer(ctx, typesys, &commands.GrantAnnotationParams{
+			StoreID:          storeID,
+			Relation:         req.GetRelation(),
+			ContextualTuples: req.GetContextualTuples().GetTupleKeys(),
+			Context:          reqCtx,
+			Consistency:      req.GetConsistency(),
+		}, result.Objects, func(object string) (string, string) {
+			return object, req.GetUser()
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &openfgav1.ListObjectsResponse{
 		Objects: result.Objects,
 	}, nil
@@ -146,12 +199,14 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest, srv openfgav1.OpenFGAService_StreamedListObjectsServer) error {
 	start := time.Now()
 
-	ctx := srv.Context()
+	ctx, cancel := s.withMethodTimeout(srv.Context(), apimethod.StreamedListObjects)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.StreamedListObjects.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 		attribute.String("object_type", req.GetType()),
 		attribute.String("relation", req.GetRelation()),
-		attribute.String("user", req.GetUser()),
+		attribute.String("user", redact.User(req.GetUser())),
 		attribute.String("consistency", req.GetConsistency().String()),
 	))
 	defer span.End()
@@ -175,6 +230,15 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		return err
 	}
 
+	if err := s.validateRequestLimits(apimethod.StreamedListObjects, req.GetContext(), len(req.GetContextualTuples().GetTupleKeys())); err != nil {
+		return err
+	}
+
+	annotateGrants, err := grantAnnotationRequested(ctx)
+	if err != nil {
+		return err
+	}
+
 	storeID := req.GetStoreId()
 
 	typesys, err := s.resolveTypesystem(ctx, storeID, req.GetAuthorizationModelId())
@@ -182,27 +246,44 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		return err
 	}
 
+	overrides := limitoverrides.FromContext(ctx)
+	settings := s.TunableSettings()
+
 	q, err := commands.NewListObjectsQuery(
 		s.datastore,
 		s.listObjectsCheckResolver,
 		commands.WithLogger(s.logger),
-		commands.WithListObjectsDeadline(s.listObjectsDeadline),
+		commands.WithListObjectsDeadline(cmp.Or(overrides.ListObjectsDeadline, settings.ListObjectsDeadline)),
 		commands.WithDispatchThrottlerConfig(threshold.Config{
 			Throttler:    s.listObjectsDispatchThrottler,
 			Enabled:      s.listObjectsDispatchThrottlingEnabled,
 			Threshold:    s.listObjectsDispatchDefaultThreshold,
 			MaxThreshold: s.listObjectsDispatchThrottlingMaxThreshold,
 		}),
-		commands.WithListObjectsMaxResults(s.listObjectsMaxResults),
-		commands.WithResolveNodeLimit(s.resolveNodeLimit),
+		commands.WithListObjectsMaxResults(settings.ListObjectsMaxResults),
+		commands.WithResolveNodeLimit(cmp.Or(overrides.MaxResolveDepth, s.resolveNodeLimit)),
+		commands.WithListObjectsCheckMaxResolutionDepth(overrides.MaxResolveDepth),
 		commands.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
-		commands.WithMaxConcurrentReads(s.maxConcurrentReadsForListObjects),
+		commands.WithMaxConcurrentReads(settings.MaxConcurrentReadsForListObjects),
+		commands.WithListObjectsLimiterSaturationMonitor(s.datastoreLimiterSaturationMonitor),
+		commands.WithListObjectsDatastoreQueryBudget(s.listObjectsDatastoreQueryBudget),
 	)
 	if err != nil {
 		return serverErrors.NewInternalError("", err)
 	}
 
 	req.AuthorizationModelId = typesys.GetAuthorizationModelID() // the resolved model id
+	if s.hasTrustedContext() {
+		// the request is not modified, as it is still read by the interceptors
+		req = proto.Clone(req).(*openfgav1.StreamedListObjectsRequest)
+		req.Context = s.withTrustedContext(ctx, req.GetContext())
+	}
+
+	var recorder *grantRecordingStream
+	if annotateGrants {
+		recorder = &grantRecordingStream{OpenFGAService_StreamedListObjectsServer: srv}
+		srv = recorder
+	}
 
 	resolutionMetadata, err := q.ExecuteStreamed(
 		typesystem.ContextWithTypesystem(ctx, typesys),
@@ -217,10 +298,11 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 
 	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, datastoreQueryCount)
 	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, datastoreQueryCount))
-	datastoreQueryCountHistogram.WithLabelValues(
+	telemetry.ObserveWithTraceExemplar(ctx, datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
-	).Observe(datastoreQueryCount)
+		s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+	), datastoreQueryCount)
 
 	dispatchCount := float64(resolutionMetadata.DispatchCounter.Load())
 
@@ -231,13 +313,41 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		methodName,
 	).Observe(dispatchCount)
 
-	requestDurationHistogram.WithLabelValues(
+	telemetry.ObserveWithTraceExemplar(ctx, requestDurationHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
 		utils.Bucketize(uint(datastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
 		utils.Bucketize(uint(resolutionMetadata.DispatchCounter.Load()), s.requestDurationByDispatchCountHistogramBuckets),
 		req.GetConsistency().String(),
-	).Observe(float64(time.Since(start).Milliseconds()))
+		s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+	), float64(time.Since(start).Milliseconds()))
+
+	s.setResolutionMetadataTrailers(ctx,
+		resolutionMetadata.DatastoreQueryCount.Load(),
+		resolutionMetadata.DispatchCounter.Load(),
+		resolutionMetadata.CheckCacheHits.Load(),
+	)
+
+	if reason := resolutionMetadata.TruncationReason(); reason != commands.NotTruncated {
+		s.recordListObjectsTruncation(ctx, methodName, reason)
+		gateway.SetTrailer(ctx, s.transport, ListObjectsTruncatedHeader, reason.String())
+	}
+
+	if annotateGrants {
+		err := s.setResultGrantsTrailer(ctx, typesys, &commands.GrantAnnotationParams{
+			StoreID:          storeID,
+			Relation:         req.GetRelation(),
+			ContextualTuples: req.GetContextualTuples().GetTupleKeys(),
+			Context:          req.GetContext(),
+			Consistency:      req.GetConsistency(),
+		}, recorder.objects, func(object string) (string, string) {
+			return object, req.GetUser()
+		})
+		if err != nil {
+			telemetry.TraceError(span, err)
+			return err
+		}
+	}
 
 	wasRequestThrottled := resolutionMetadata.WasThrottled.Load()
 	if wasRequestThrottled {
@@ -246,3 +356,10 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 
 	return nil
 }
+
+// recordListObjectsTruncation records on the span and the metrics of a ListObjects or StreamedListObjects request
+// that its results may be partial.
+func (s *Server) recordListObjectsTruncation(ctx context.Context, methodName string, reason commands.TruncationReason) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("truncation_reason", reason.String()))
+	listObjectsTruncatedCounter.WithLabelValues(s.serviceName, methodName, reason.String()).Inc()
+}
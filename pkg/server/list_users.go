@@ -1,6 +1,7 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"strings"
@@ -11,15 +12,19 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/condition"
 	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/internal/middleware/limitoverrides"
 	"github.com/openfga/openfga/internal/throttler/threshold"
 	"github.com/openfga/openfga/internal/utils"
 	"github.com/openfga/openfga/internal/utils/apimethod"
 	"github.com/openfga/openfga/pkg/middleware/validator"
+	"github.com/openfga/openfga/pkg/redact"
+	"github.com/openfga/openfga/pkg/server/commands"
 	"github.com/openfga/openfga/pkg/server/commands/listusers"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/telemetry"
@@ -33,10 +38,13 @@ func (s *Server) ListUsers(
 	ctx context.Context,
 	req *openfgav1.ListUsersRequest,
 ) (*openfgav1.ListUsersResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ListUsers)
+	defer cancel()
+
 	start := time.Now()
 	ctx, span := tracer.Start(ctx, apimethod.ListUsers.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
-		attribute.String("object", tuple.BuildObject(req.GetObject().GetType(), req.GetObject().GetId())),
+		attribute.String("object", redact.Object(tuple.BuildObject(req.GetObject().GetType(), req.GetObject().GetId()))),
 		attribute.String("relation", req.GetRelation()),
 		attribute.String("user_filters", userFiltersToString(req.GetUserFilters())),
 		attribute.String("consistency", req.GetConsistency().String()),
@@ -62,6 +70,15 @@ func (s *Server) ListUsers(
 		return nil, err
 	}
 
+	if err := s.validateRequestLimits(apimethod.ListUsers, req.GetContext(), len(req.GetContextualTuples())); err != nil {
+		return nil, err
+	}
+
+	annotateGrants, err := grantAnnotationRequested(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	typesys, err := s.resolveTypesystem(ctx, req.GetStoreId(), req.GetAuthorizationModelId())
 	if err != nil {
 		return nil, err
@@ -73,15 +90,21 @@ func (s *Server) ListUsers(
 	}
 
 	ctx = typesystem.ContextWithTypesystem(ctx, typesys)
+	if s.hasTrustedContext() {
+		// the request is not modified, as it is still read by the interceptors
+		req = proto.Clone(req).(*openfgav1.ListUsersRequest)
+		req.Context = s.withTrustedContext(ctx, req.GetContext())
+	}
 
+	settings := s.TunableSettings()
 	listUsersQuery := listusers.NewListUsersQuery(s.datastore,
 		req.GetContextualTuples(),
-		listusers.WithResolveNodeLimit(s.resolveNodeLimit),
+		listusers.WithResolveNodeLimit(cmp.Or(limitoverrides.FromContext(ctx).MaxResolveDepth, s.resolveNodeLimit)),
 		listusers.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
 		listusers.WithListUsersQueryLogger(s.logger),
-		listusers.WithListUsersMaxResults(s.listUsersMaxResults),
-		listusers.WithListUsersDeadline(s.listUsersDeadline),
-		listusers.WithListUsersMaxConcurrentReads(s.maxConcurrentReadsForListUsers),
+		listusers.WithListUsersMaxResults(settings.ListUsersMaxResults),
+		listusers.WithListUsersDeadline(settings.ListUsersDeadline),
+		listusers.WithListUsersMaxConcurrentReads(settings.MaxConcurrentReadsForListUsers),
 		listusers.WithDispatchThrottlerConfig(threshold.Config{
 			Throttler:    s.listUsersDispatchThrottler,
 			Enabled:      s.listUsersDispatchThrottlingEnabled,
@@ -89,6 +112,7 @@ func (s *Server) ListUsers(
 			MaxThreshold: s.listUsersDispatchThrottlingMaxThreshold,
 		}),
 		listusers.WithListUsersDatastoreThrottler(s.listUsersDatastoreThrottleThreshold, s.listUsersDatastoreThrottleDuration),
+		listusers.WithListUsersLimiterSaturationMonitor(s.datastoreLimiterSaturationMonitor),
 	)
 
 	resp, err := listUsersQuery.ListUsers(ctx, req)
@@ -99,7 +123,7 @@ func (s *Server) ListUsers(
 		case errors.Is(err, graph.ErrResolutionDepthExceeded):
 			return nil, serverErrors.ErrAuthorizationModelResolutionTooComplex
 		case errors.Is(err, condition.ErrEvaluationFailed):
-			return nil, serverErrors.ValidationError(err)
+			return nil, serverErrors.ConditionEvaluationError(err)
 		default:
 			return nil, serverErrors.HandleError("", err)
 		}
@@ -109,10 +133,11 @@ func (s *Server) ListUsers(
 
 	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, datastoreQueryCount)
 	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, datastoreQueryCount))
-	datastoreQueryCountHistogram.WithLabelValues(
+	telemetry.ObserveWithTraceExemplar(ctx, datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
-	).Observe(datastoreQueryCount)
+		s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+	), datastoreQueryCount)
 
 	dispatchCount := float64(resp.Metadata.DispatchCounter.Load())
 	grpc_ctxtags.Extract(ctx).Set(dispatchCountHistogramName, dispatchCount)
@@ -122,19 +147,44 @@ func (s *Server) ListUsers(
 		methodName,
 	).Observe(dispatchCount)
 
-	requestDurationHistogram.WithLabelValues(
+	telemetry.ObserveWithTraceExemplar(ctx, requestDurationHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
 		utils.Bucketize(uint(datastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
 		utils.Bucketize(uint(dispatchCount), s.requestDurationByDispatchCountHistogramBuckets),
 		req.GetConsistency().String(),
-	).Observe(float64(time.Since(start).Milliseconds()))
+		s.requestMetricsStoreIDLabeler.label(req.GetStoreId()),
+	), float64(time.Since(start).Milliseconds()))
 
 	wasRequestThrottled := resp.GetMetadata().WasThrottled.Load()
 	if wasRequestThrottled {
 		throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Inc()
 	}
 
+	// ListUsers does not use the check cache
+	s.setResolutionMetadataHeaders(ctx, resp.Metadata.DatastoreQueryCount, resp.Metadata.DispatchCounter.Load(), 0)
+
+	if annotateGrants {
+		users := make([]string, 0, len(resp.GetUsers()))
+		for _, user := range resp.GetUsers() {
+			users = append(users, tuple.UserProtoToString(user))
+		}
+
+		object := tuple.BuildObject(req.GetObject().GetType(), req.GetObject().GetId())
+		err := s.setResultGrantsTrailer(ctx, typesys, &commands.GrantAnnotationParams{
+			StoreID:          req.GetStoreId(),
+			Relation:         req.GetRelation(),
+			ContextualTuples: req.GetContextualTuples(),
+			Context:          req.GetContext(),
+			Consistency:      req.GetConsistency(),
+		}, users, func(user string) (string, string) {
+			return object, user
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &openfgav1.ListUsersResponse{
 		Users: resp.GetUsers(),
 	}, nil
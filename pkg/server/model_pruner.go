@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// authorizationModelPruner periodically deletes the authorization models of every store but the most recent ones.
+type authorizationModelPruner struct {
+	datastore storage.OpenFGADatastore
+	logger    logger.Logger
+	keep      int
+	interval  time.Duration
+	// invalidate evicts the cached models of a store whose models were deleted.
+	invalidate func(storeID string)
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func newAuthorizationModelPruner(datastore storage.OpenFGADatastore, logger logger.Logger, keep int, interval time.Duration, invalidate func(storeID string)) *authorizationModelPruner {
+	p := &authorizationModelPruner{
+		datastore:  datastore,
+		logger:     logger,
+		keep:       keep,
+		interval:   interval,
+		invalidate: invalidate,
+		stop:       make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.run()
+
+	return p
+}
+
+// Close stops the pruner and waits for the current pass, if any, to be interrupted.
+func (p *authorizationModelPruner) Close() {
+	p.once.Do(func() { close(p.stop) })
+	p.wg.Wait()
+}
+
+func (p *authorizationModelPruner) run() {
+	defer p.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.prune(ctx)
+		}
+	}
+}
+
+// prune deletes the models of every store but the keep most recent ones.
+func (p *authorizationModelPruner) prune(ctx context.Context) {
+	cmd := commands.NewDeleteAuthorizationModelCommand(p.datastore, commands.WithDeleteAuthModelLogger(p.logger))
+
+	var continuationToken string
+	for {
+		stores, token, err := p.datastore.ListStores(ctx, storage.ListStoresOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, continuationToken),
+		})
+		if err != nil {
+			p.logger.Warn("failed to list the stores whose authorization models to prune", zap.Error(err))
+			return
+		}
+
+		for _, store := range stores {
+			pruned, err := cmd.Prune(ctx, store.GetId(), p.keep)
+			if pruned > 0 {
+				p.invalidate(store.GetId())
+				p.logger.Info("pruned authorization models",
+					zap.String("store_id", store.GetId()),
+					zap.Int("count", pruned))
+			}
+			if err != nil {
+				p.logger.Warn("failed to prune authorization models",
+					zap.String("store_id", store.GetId()),
+					zap.Error(err))
+			}
+		}
+
+		if token == "" {
+			return
+		}
+		continuationToken = token
+	}
+}
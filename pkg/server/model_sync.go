@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/modelsync"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// modelSyncer periodically fetches the authorization models of some stores from their sources, and writes them as
+// the latest model of their store when they differ from it.
+type modelSyncer struct {
+	datastore           storage.OpenFGADatastore
+	logger              logger.Logger
+	sources             map[string]modelsync.Source
+	interval            time.Duration
+	maxModelSizeInBytes int
+
+	// versions are the versions of the models last synchronized, by store, only accessed by run.
+	versions map[string]string
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func newModelSyncer(datastore storage.OpenFGADatastore, logger logger.Logger, sources map[string]modelsync.Source, interval time.Duration, maxModelSizeInBytes int) *modelSyncer {
+	m := &modelSyncer{
+		datastore:           datastore,
+		logger:              logger,
+		sources:             sources,
+		interval:            interval,
+		maxModelSizeInBytes: maxModelSizeInBytes,
+		versions:            make(map[string]string, len(sources)),
+		stop:                make(chan struct{}),
+	}
+
+	m.wg.Add(1)
+	go m.run()
+
+	return m
+}
+
+// Close stops the syncer and waits for the current pass, if any, to be interrupted.
+func (m *modelSyncer) Close() {
+	m.once.Do(func() { close(m.stop) })
+	m.wg.Wait()
+}
+
+func (m *modelSyncer) run() {
+	defer m.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-m.stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.sync(ctx)
+
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync synchronizes the model of every store.
+func (m *modelSyncer) sync(ctx context.Context) {
+	for storeID, source := range m.sources {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := m.syncStore(ctx, storeID, source); err != nil && ctx.Err() == nil {
+			m.logger.Warn("failed to synchronize the authorization model of a store",
+				zap.String("store_id", storeID),
+				zap.String("source", source.String()),
+				zap.Error(err))
+		}
+	}
+}
+
+// syncStore fetches the model of the store, and writes it if it differs from the latest model of the store. The
+// version of a model that cannot be written because it is invalid is not fetched again.
+func (m *modelSyncer) syncStore(ctx context.Context, storeID string, source modelsync.Source) error {
+	artifact, err := source.Fetch(ctx, m.versions[storeID])
+	if err != nil {
+		if errors.Is(err, modelsync.ErrNotModified) {
+			return nil
+		}
+		return err
+	}
+
+	model, err := modelsync.ParseModel(artifact)
+	if err != nil {
+		m.versions[storeID] = artifact.Version
+		return err
+	}
+
+	if _, err := m.datastore.GetStore(ctx, storeID); err != nil {
+		return err
+	}
+
+	latest, err := m.datastore.FindLatestAuthorizationModel(ctx, storeID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+	if latest != nil && sameAuthorizationModel(latest, model) {
+		m.versions[storeID] = artifact.Version
+		return nil
+	}
+
+	cmd := commands.NewWriteAuthorizationModelCommand(m.datastore,
+		commands.WithWriteAuthModelLogger(m.logger),
+		commands.WithWriteAuthModelMaxSizeInBytes(m.maxModelSizeInBytes),
+	)
+	resp, err := cmd.Execute(ctx, &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:         storeID,
+		SchemaVersion:   model.GetSchemaVersion(),
+		TypeDefinitions: model.GetTypeDefinitions(),
+		Conditions:      model.GetConditions(),
+	})
+	if err != nil {
+		// the datastore may succeed on the next pass
+		if code := status.Code(err); code != codes.Internal && code != codes.Unavailable {
+			m.versions[storeID] = artifact.Version
+		}
+		return err
+	}
+
+	m.versions[storeID] = artifact.Version
+	m.logger.Info("synchronized the authorization model of a store",
+		zap.String("store_id", storeID),
+		zap.String("source", source.String()),
+		zap.String("version", artifact.Version),
+		zap.String(authorizationModelIDKey, resp.GetAuthorizationModelId()))
+	return nil
+}
+
+// sameAuthorizationModel reports whether two models have the same schema version, type definitions and conditions.
+func sameAuthorizationModel(a, b *openfgav1.AuthorizationModel) bool {
+	return proto.Equal(
+		&openfgav1.AuthorizationModel{SchemaVersion: a.GetSchemaVersion(), TypeDefinitions: a.GetTypeDefinitions(), Conditions: a.GetConditions()},
+		&openfgav1.AuthorizationModel{SchemaVersion: b.GetSchemaVersion(), TypeDefinitions: b.GetTypeDefinitions(), Conditions: b.GetConditions()},
+	)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+)
+
+func TestModelSync(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	version, model := 1, `
+		model
+			schema 1.1
+		type user`
+	setModel := func(dsl string) {
+		mu.Lock()
+		defer mu.Unlock()
+		version++
+		model = dsl
+	}
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("ETag", strconv.Itoa(version))
+		_, _ = w.Write([]byte(model))
+	}))
+	t.Cleanup(registry.Close)
+
+	ds := memory.New()
+	store, err := ds.CreateStore(ctx, &openfgav1.Store{Id: ulid.Make().String(), Name: "acme"})
+	require.NoError(t, err)
+
+	s := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithModelSync(map[string]string{store.GetId(): registry.URL + "/model.fga"}, 10*time.Millisecond),
+	)
+	t.Cleanup(s.Close)
+
+	readModels := func() []*openfgav1.AuthorizationModel {
+		models, _, err := ds.ReadAuthorizationModels(ctx, store.GetId(), storage.ReadAuthorizationModelsOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, ""),
+		})
+		require.NoError(t, err)
+		return models
+	}
+
+	require.Eventually(t, func() bool { return len(readModels()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "user", readModels()[0].GetTypeDefinitions()[0].GetType())
+
+	// a new version of the same model is not written
+	setModel(`
+		model
+			schema 1.1
+		type user`)
+	// an invalid model is not written
+	setModel(`
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [group]`)
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, readModels(), 1)
+
+	setModel(`
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user]`)
+	require.Eventually(t, func() bool { return len(readModels()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	latest, err := ds.FindLatestAuthorizationModel(ctx, store.GetId())
+	require.NoError(t, err)
+	require.Len(t, latest.GetTypeDefinitions(), 2)
+
+	t.Run("invalid_sources", func(t *testing.T) {
+		_, err := NewServerWithOpts(WithDatastore(ds), WithModelSync(map[string]string{store.GetId(): "ftp://example.com/model.fga"}, time.Minute))
+		require.Error(t, err)
+
+		_, err = NewServerWithOpts(WithDatastore(ds), WithModelSync(map[string]string{"acme": registry.URL + "/model.fga"}, time.Minute))
+		require.Error(t, err)
+
+		_, err = NewServerWithOpts(WithDatastore(ds), WithModelSync(map[string]string{store.GetId(): registry.URL + "/model.fga"}, 0))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"time"
+)
+
+// Profile is a preset of the cache, concurrency, deadline and limiter options suited to a deployment mode.
+type Profile string
+
+const (
+	// ProfileSingleNode suits a single replica, which sees every write and can therefore cache aggressively.
+	ProfileSingleNode Profile = "single-node"
+	// ProfileHA suits several replicas sharing a datastore: caches are invalidated on the writes of the other
+	// replicas, expensive queries are throttled and a replica whose datastore reads are saturated reports itself
+	// as not ready, so that the load balancer sheds load to the others.
+	ProfileHA Profile = "ha"
+	// ProfileEdge suits replicas with little memory and CPU: caches are small, concurrency is bounded and
+	// deadlines are short.
+	ProfileEdge Profile = "edge"
+)
+
+var profiles = map[Profile][]OpenFGAServiceV1Option{
+	ProfileSingleNode: {
+		WithCheckQueryCacheEnabled(true),
+		WithCheckCacheLimit(100000),
+		WithCheckQueryCacheTTL(time.Minute),
+		WithCheckQueryCacheRevalidation(10*time.Second, 10),
+		WithCheckIteratorCacheEnabled(true),
+		WithCheckIteratorCacheTTL(time.Minute),
+		WithListObjectsIteratorCacheEnabled(true),
+		WithListObjectsIteratorCacheTTL(time.Minute),
+		WithSharedIteratorEnabled(true),
+	},
+	ProfileHA: {
+		WithCheckQueryCacheEnabled(true),
+		WithCheckQueryCacheTTL(10 * time.Second),
+		WithCheckIteratorCacheEnabled(true),
+		WithCheckIteratorCacheTTL(10 * time.Second),
+		WithListObjectsIteratorCacheEnabled(true),
+		WithListObjectsIteratorCacheTTL(10 * time.Second),
+		WithCacheControllerEnabled(true),
+		WithCacheControllerTTL(10 * time.Second),
+		WithSharedIteratorEnabled(true),
+		WithMaxConcurrentReadsForCheck(100),
+		WithMaxConcurrentReadsForListObjects(50),
+		WithMaxConcurrentReadsForListUsers(50),
+		WithListObjectsDispatchThrottlingEnabled(true),
+		WithListUsersDispatchThrottlingEnabled(true),
+		WithDatastoreLimiterSaturation(100*time.Millisecond, 30*time.Second, 0.5),
+		WithDatastoreLimiterSaturationReadinessEnabled(true),
+	},
+	ProfileEdge: {
+		WithCheckQueryCacheEnabled(true),
+		WithCheckCacheLimit(1000),
+		WithCheckQueryCacheTTL(10 * time.Second),
+		WithAuthorizationModelCacheSize(100),
+		WithResolveNodeBreadthLimit(5),
+		WithMaxConcurrentReadsForCheck(20),
+		WithMaxConcurrentReadsForListObjects(10),
+		WithMaxConcurrentReadsForListUsers(10),
+		WithMaxConcurrentChecksPerBatchCheck(10),
+		WithListObjectsDeadline(time.Second),
+		WithListObjectsMaxResults(100),
+		WithListUsersDeadline(time.Second),
+		WithListUsersMaxResults(100),
+	},
+}
+
+// WithProfile applies the options of a deployment profile, one of 'single-node', 'ha' or 'edge'.
+// The options given after it override those of the profile, and the resulting combination is
+// validated as a whole when the server is created.
+func WithProfile(profile Profile) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.profile = profile
+		for _, opt := range profiles[profile] {
+			opt(s)
+		}
+	}
+}
@@ -2,27 +2,33 @@ package server
 
 import (
 	"context"
+	"strings"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/utils/apimethod"
 	"github.com/openfga/openfga/pkg/middleware/validator"
+	"github.com/openfga/openfga/pkg/redact"
 	"github.com/openfga/openfga/pkg/server/commands"
 	"github.com/openfga/openfga/pkg/telemetry"
 )
 
 func (s *Server) Read(ctx context.Context, req *openfgav1.ReadRequest) (*openfgav1.ReadResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.Read)
+	defer cancel()
+
 	tk := req.GetTupleKey()
 	ctx, span := tracer.Start(ctx, apimethod.Read.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
-		attribute.KeyValue{Key: "object", Value: attribute.StringValue(tk.GetObject())},
+		attribute.KeyValue{Key: "object", Value: attribute.StringValue(redact.Object(tk.GetObject()))},
 		attribute.KeyValue{Key: "relation", Value: attribute.StringValue(tk.GetRelation())},
-		attribute.KeyValue{Key: "user", Value: attribute.StringValue(tk.GetUser())},
+		attribute.KeyValue{Key: "user", Value: attribute.StringValue(redact.User(tk.GetUser()))},
 		attribute.KeyValue{Key: "consistency", Value: attribute.StringValue(req.GetConsistency().String())},
 	))
 	defer span.End()
@@ -43,16 +49,47 @@ func (s *Server) Read(ctx context.Context, req *openfgav1.ReadRequest) (*openfga
 		return nil, err
 	}
 
-	q := commands.NewReadQuery(s.datastore,
+	pageSize, err := s.pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	q := commands.NewReadQuery(s.datastore, append([]commands.ReadQueryOption{
 		commands.WithReadQueryLogger(s.logger),
 		commands.WithReadQueryEncoder(s.encoder),
 		commands.WithReadQueryTokenSerializer(s.tokenSerializer),
-	)
+	}, readFilterOptions(ctx)...)...)
 	return q.Execute(ctx, &openfgav1.ReadRequest{
 		StoreId:           req.GetStoreId(),
 		TupleKey:          tk,
-		PageSize:          req.GetPageSize(),
+		PageSize:          pageSize,
 		ContinuationToken: req.GetContinuationToken(),
 		Consistency:       req.GetConsistency(),
 	})
 }
+
+// readFilterOptions returns the options of the relations and user type filters requested in the metadata of ctx,
+// through the ReadRelationsHeader and ReadUserTypeHeader keys.
+func readFilterOptions(ctx context.Context) []commands.ReadQueryOption {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var opts []commands.ReadQueryOption
+	var relations []string
+	for _, value := range md.Get(ReadRelationsHeader) {
+		for _, relation := range strings.Split(value, ",") {
+			if relation = strings.TrimSpace(relation); relation != "" {
+				relations = append(relations, relation)
+			}
+		}
+	}
+	if len(relations) > 0 {
+		opts = append(opts, commands.WithReadQueryRelations(relations...))
+	}
+	if values := md.Get(ReadUserTypeHeader); len(values) > 0 && values[0] != "" {
+		opts = append(opts, commands.WithReadQueryUserType(values[0]))
+	}
+	return opts
+}
@@ -6,7 +6,9 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
@@ -17,6 +19,9 @@ import (
 )
 
 func (s *Server) ReadChanges(ctx context.Context, req *openfgav1.ReadChangesRequest) (*openfgav1.ReadChangesResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ReadChanges)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.ReadChanges.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 		attribute.KeyValue{Key: "type", Value: attribute.StringValue(req.GetType())},
@@ -39,11 +44,38 @@ func (s *Server) ReadChanges(ctx context.Context, req *openfgav1.ReadChangesRequ
 		return nil, err
 	}
 
-	q := commands.NewReadChangesQuery(s.datastore,
+	pageSize, err := s.pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	if pageSize != req.GetPageSize() {
+		req = proto.Clone(req).(*openfgav1.ReadChangesRequest)
+		req.PageSize = pageSize
+	}
+
+	q := commands.NewReadChangesQuery(s.datastore, append([]commands.ReadChangesQueryOption{
 		commands.WithReadChangesQueryLogger(s.logger),
 		commands.WithReadChangesQueryEncoder(s.encoder),
 		commands.WithContinuationTokenSerializer(s.tokenSerializer),
 		commands.WithReadChangeQueryHorizonOffset(s.changelogHorizonOffset),
-	)
+	}, readChangesFilterOptions(ctx)...)...)
 	return q.Execute(ctx, req)
 }
+
+// readChangesFilterOptions returns the options of the object id and user filters requested in the metadata of ctx,
+// through the ReadChangesObjectIDHeader and ReadChangesUserHeader keys.
+func readChangesFilterOptions(ctx context.Context) []commands.ReadChangesQueryOption {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var opts []commands.ReadChangesQueryOption
+	if values := md.Get(ReadChangesObjectIDHeader); len(values) > 0 && values[0] != "" {
+		opts = append(opts, commands.WithReadChangesQueryObjectID(values[0]))
+	}
+	if values := md.Get(ReadChangesUserHeader); len(values) > 0 && values[0] != "" {
+		opts = append(opts, commands.WithReadChangesQueryUser(values[0]))
+	}
+	return opts
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/openfga/openfga/pkg/server/health"
+)
+
+// checkCacheReadinessProbeKey is the key of the entry stored and read back to probe the check cache. It cannot
+// collide with the keys of the cached checks, which are hashes.
+const checkCacheReadinessProbeKey = "openfga-readiness-probe"
+
+var _ health.ComponentsService = (*Server)(nil)
+
+// ReadinessComponents reports the readiness of each component of the server:
+//   - 'server', which is not ready while the server drains.
+//   - 'datastore', which is not ready if it cannot be reached or its schema revision is not supported.
+//   - 'datastore_limiter', if enabled with WithDatastoreLimiterSaturationReadinessEnabled.
+//   - 'check_cache', if enabled with WithCheckCacheReadinessEnabled.
+//   - 'tuple_change_listener', if enabled with WithTupleChangeListenerReadinessEnabled.
+//   - 'typesystem_cache', if warmed up with WithTypesystemCacheWarmup.
+func (s *Server) ReadinessComponents(ctx context.Context) []health.ComponentStatus {
+	components, _ := s.readinessComponents(ctx)
+	return components
+}
+
+// readinessComponents returns the readiness of each component of the server, and the error of the datastore, if it
+// could not report its readiness.
+func (s *Server) readinessComponents(ctx context.Context) ([]health.ComponentStatus, error) {
+	components := []health.ComponentStatus{{Name: "server", Ready: true}}
+	if s.IsDraining() {
+		components[0] = health.ComponentStatus{Name: "server", Ready: false, Message: "server is draining"}
+	}
+
+	status, err := s.datastore.IsReady(ctx)
+	datastore := health.ComponentStatus{Name: "datastore", Ready: status.IsReady, Message: status.Message}
+	switch {
+	case err != nil:
+		datastore.Message = err.Error()
+	case status.IsReady && status.Revision > 0:
+		datastore.Message = "at schema revision " + strconv.FormatInt(status.Revision, 10)
+	}
+	components = append(components, datastore)
+
+	if s.datastoreLimiterSaturationReadinessEnabled {
+		limiter := health.ComponentStatus{Name: "datastore_limiter", Ready: true}
+		if s.datastoreLimiterSaturationMonitor.IsSaturated() {
+			limiter = health.ComponentStatus{Name: "datastore_limiter", Ready: false, Message: "datastore read concurrency limiter is saturated"}
+		}
+		components = append(components, limiter)
+	}
+
+	if s.checkCacheReadinessEnabled {
+		components = append(components, s.checkCacheReadiness())
+	}
+
+	if s.tupleChangeListenerReadinessEnabled && s.tupleChangeSubscriber != nil {
+		listener := health.ComponentStatus{Name: "tuple_change_listener", Ready: true}
+		if err := s.tupleChangeSubscriber.Err(); err != nil {
+			listener = health.ComponentStatus{Name: "tuple_change_listener", Ready: false, Message: err.Error()}
+		}
+		components = append(components, listener)
+	}
+
+	if s.typesystemWarmer != nil {
+		components = append(components, s.typesystemWarmer.Readiness())
+	}
+
+	return components, err
+}
+
+// checkCacheReadiness probes the check cache by storing an entry and reading it back.
+func (s *Server) checkCacheReadiness() health.ComponentStatus {
+	cache := s.sharedDatastoreResources.CheckCache
+	if cache == nil {
+		return health.ComponentStatus{Name: "check_cache", Ready: true, Message: "check cache is disabled"}
+	}
+
+	probe := s.clock.Now().UnixNano()
+	cache.Set(checkCacheReadinessProbeKey, probe, time.Minute)
+	if got, ok := cache.Get(checkCacheReadinessProbeKey).(int64); !ok || got != probe {
+		return health.ComponentStatus{Name: "check_cache", Ready: false, Message: "check cache did not return the probe entry"}
+	}
+	return health.ComponentStatus{Name: "check_cache", Ready: true}
+}
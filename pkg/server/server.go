@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
@@ -18,20 +21,29 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/authz"
 	"github.com/openfga/openfga/internal/build"
 	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/internal/materializedview"
+	"github.com/openfga/openfga/internal/membershipindex"
+	"github.com/openfga/openfga/internal/modelsync"
 	"github.com/openfga/openfga/internal/shared"
 	"github.com/openfga/openfga/internal/throttler"
 	"github.com/openfga/openfga/internal/utils"
 	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/internal/validation"
 	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/encoder"
 	"github.com/openfga/openfga/pkg/gateway"
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/redact"
+	"github.com/openfga/openfga/pkg/server/commands"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/storage"
@@ -46,9 +58,65 @@ const (
 	AuthorizationModelIDHeader = "Openfga-Authorization-Model-Id"
 	authorizationModelIDKey    = "authorization_model_id"
 
+	// DatastoreQueryCountHeader, DispatchCountHeader and CheckCacheHitsHeader are the response headers and trailers
+	// holding the resolution metadata of Check, BatchCheck, ListObjects and ListUsers requests, and the trailers
+	// holding those of StreamedListObjects requests. See WithResolutionMetadataHeaders.
+	DatastoreQueryCountHeader = "Openfga-Datastore-Query-Count"
+	DispatchCountHeader       = "Openfga-Dispatch-Count"
+	CheckCacheHitsHeader      = "Openfga-Check-Cache-Hits"
+
+	// AssertionRelationCoverageHeader and AssertionTypeCoverageHeader are the response headers of ReadAssertions
+	// holding the number of relations and types exercised by at least one assertion, out of those of the model
+	// (e.g. '3/5'). UncoveredRelationsHeader lists the relations exercised by none, as comma separated 'type#relation'.
+	AssertionRelationCoverageHeader = "Openfga-Assertion-Relation-Coverage"
+	AssertionTypeCoverageHeader     = "Openfga-Assertion-Type-Coverage"
+	UncoveredRelationsHeader        = "Openfga-Uncovered-Relations"
+
+	// ReadRelationsHeader and ReadUserTypeHeader are the request metadata keys restricting the tuples returned by
+	// Read and StreamedRead to those of a comma separated list of relations, and to users of an object type. The
+	// datastore applies them to the pages read. The HTTP clients send them as the 'Openfga-Read-Relations' and
+	// 'Openfga-Read-User-Type' headers.
+	ReadRelationsHeader = "openfga-read-relations"
+	ReadUserTypeHeader  = "openfga-read-user-type"
+
+	// ReadChangesObjectIDHeader and ReadChangesUserHeader are the request metadata keys restricting the changes
+	// returned by ReadChanges to those of an object of the requested type, and to those of a user. The HTTP clients
+	// send them as the 'Openfga-Read-Changes-Object-Id' and 'Openfga-Read-Changes-User' headers.
+	ReadChangesObjectIDHeader = "openfga-read-changes-object-id"
+	ReadChangesUserHeader     = "openfga-read-changes-user"
+
+	// StoreLabelsHeader is the request metadata key holding the labels of the store created by CreateStore, or
+	// those every store listed by ListStores must hold, as comma separated 'key=value'. The HTTP clients send it as
+	// the 'Openfga-Store-Labels' header. StoreLabelsResponseHeader is the response header of GetStore holding the
+	// labels of the store in the same format.
+	StoreLabelsHeader         = "openfga-store-labels"
+	StoreLabelsResponseHeader = "Openfga-Store-Labels"
+
+	// AuthorizationModelWarningsHeader is the response header of WriteAuthorizationModel holding the non-fatal
+	// diagnostics of the model written, as '; ' separated 'type#relation: message'. It is unset if there are none.
+	AuthorizationModelWarningsHeader = "Openfga-Authorization-Model-Warnings"
+
+	// ExpandContextHeader is the request metadata key holding the context, as a JSON object, the conditions of the
+	// tuples expanded by Expand are evaluated with. The HTTP clients send it as the 'Openfga-Expand-Context' header.
+	ExpandContextHeader = "openfga-expand-context"
+
+	// AnnotateGrantsHeader is the request metadata key which, set to 'true', has ListObjects, StreamedListObjects
+	// and ListUsers set the ResultGrantsHeader response trailer to how the first 100 results were granted the
+	// relation, as comma separated 'result=kind' where kind is one of 'direct', 'wildcard' or 'userset'.
+	AnnotateGrantsHeader = "openfga-annotate-grants"
+	ResultGrantsHeader   = "Openfga-Result-Grants"
+
+	// ListObjectsTruncatedHeader is the response header of ListObjects, and the trailer of StreamedListObjects,
+	// set when the results may be partial to why the resolution was stopped: 'deadline', 'datastore_query_budget'
+	// or 'max_results'. It is unset if the resolution completed.
+	ListObjectsTruncatedHeader = "Openfga-List-Objects-Truncated"
+
 	ExperimentalCheckOptimizations       ExperimentalFeatureFlag = "enable-check-optimizations"
 	ExperimentalListObjectsOptimizations ExperimentalFeatureFlag = "enable-list-objects-optimizations"
 	ExperimentalAccessControlParams      ExperimentalFeatureFlag = "enable-access-control"
+	ExperimentalSimulation               ExperimentalFeatureFlag = "enable-simulation"
+	ExperimentalFaultInjection           ExperimentalFeatureFlag = "enable-fault-injection"
+	ExperimentalMaterializedViews        ExperimentalFeatureFlag = "enable-materialized-views"
 	allowedLabel                                                 = "allowed"
 )
 
@@ -72,24 +140,30 @@ var (
 	datastoreQueryCountHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            datastoreQueryCountHistogramName,
-		Help:                            "The number of database queries required to resolve a query (e.g. Check, ListObjects or ListUsers).",
+		Help:                            "The number of database queries required to resolve a query (e.g. Check, ListObjects or ListUsers), labeled by store id if enabled.",
 		Buckets:                         []float64{1, 5, 20, 50, 100, 150, 225, 400, 500, 750, 1000},
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"grpc_service", "grpc_method"})
+	}, []string{"grpc_service", "grpc_method", "store_id"})
 
 	requestDurationHistogramName = "request_duration_ms"
 
 	requestDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            requestDurationHistogramName,
-		Help:                            "The request duration (in ms) labeled by method and buckets of datastore query counts and number of dispatches. This allows for reporting percentiles based on the number of datastore queries and number of dispatches required to resolve the request.",
+		Help:                            "The request duration (in ms) labeled by method and buckets of datastore query counts and number of dispatches. This allows for reporting percentiles based on the number of datastore queries and number of dispatches required to resolve the request. Also labeled by store id if enabled.",
 		Buckets:                         []float64{1, 5, 10, 25, 50, 80, 100, 150, 200, 300, 1000, 2000, 5000},
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"grpc_service", "grpc_method", "datastore_query_count", "dispatch_count", "consistency"})
+	}, []string{"grpc_service", "grpc_method", "datastore_query_count", "dispatch_count", "consistency", "store_id"})
+
+	listObjectsTruncatedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "list_objects_truncated_count",
+		Help:      "The total number of ListObjects and StreamedListObjects requests whose results may be partial, partitioned by why their resolution was stopped.",
+	}, []string{"grpc_service", "grpc_method", "reason"})
 
 	throttledRequestCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
@@ -97,6 +171,12 @@ var (
 		Help:      "The total number of requests that have been throttled.",
 	}, []string{"grpc_service", "grpc_method"})
 
+	rejectedRequestContextCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "rejected_request_context_count",
+		Help:      "The total number of requests rejected because their contextual tuples or their context exceeded a limit.",
+	}, []string{"grpc_service", "grpc_method", "reason"})
+
 	checkResultCounterName = "check_result_count"
 	checkResultCounter     = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
@@ -151,13 +231,36 @@ type Server struct {
 	transport                        gateway.Transport
 	resolveNodeLimit                 uint32
 	resolveNodeBreadthLimit          uint32
+	checkDirectTupleBatchSize        uint32
+	checkDirectTupleBatchMaxDelay    time.Duration
+	checkDispatchPoolSize            uint32
+	checkUnionBranchOrdering         bool
+	checkRelationCosts               graph.RelationCosts
+	checkMembershipIndexRelations    []string
+	checkMembershipIndexRefresh      time.Duration
+	checkMembershipIndexMaxStaleness time.Duration
+	checkMembershipIndex             *membershipindex.Index
+	checkMaterializedViewSpecs       []string
+	checkMaterializedViewsRefresh    time.Duration
+	checkMaterializedViewsStaleness  time.Duration
+	checkMaterializedViews           *materializedview.Views
 	usersetBatchSize                 uint32
 	changelogHorizonOffset           int
+	continuationTokenTTL             time.Duration
+	unversionedTokenWindow           time.Duration
+	maxTuplesPerWrite                int
+	maxPageSize                      int32
+	clock                            clock.Clock
 	listObjectsDeadline              time.Duration
 	listObjectsMaxResults            uint32
+	listObjectsDatastoreQueryBudget  uint32
 	listUsersDeadline                time.Duration
 	listUsersMaxResults              uint32
 	maxChecksPerBatchCheck           uint32
+	maxContextSizeBytes              uint32
+	maxContextDepth                  uint32
+	maxContextualTuples              uint32
+	methodTimeouts                   map[string]time.Duration
 	maxConcurrentChecksPerBatch      uint32
 	maxConcurrentReadsForListObjects uint32
 	maxConcurrentReadsForCheck       uint32
@@ -165,18 +268,31 @@ type Server struct {
 	maxAuthorizationModelCacheSize   int
 	maxAuthorizationModelSizeInBytes int
 	experimentals                    []ExperimentalFeatureFlag
+	experimentalsMu                  sync.RWMutex
 	AccessControl                    serverconfig.AccessControlConfig
 	AuthnMethod                      string
 	serviceName                      string
+	profile                          Profile
+
+	resolutionMetadataHeadersEnabled bool
 
 	// NOTE don't use this directly, use function resolveTypesystem. See https://github.com/openfga/openfga/issues/1527
-	typesystemResolver     typesystem.TypesystemResolverFunc
-	typesystemResolverStop func()
+	typesystemResolver           typesystem.TypesystemResolverFunc
+	typesystemResolverInvalidate func(storeID string)
+	typesystemResolverStop       func()
+	typesystemCacheTTL           time.Duration
+	typesystemCacheStoreTTLs     map[string]time.Duration
+	typesystemCacheSize          int
+	typesystemSharedCache        typesystem.SharedModelCache
+	typesystemWarmupStores       []string
+	typesystemWarmupMRUFile      string
+	typesystemWarmer             *typesystemWarmer
 
 	// cacheSettings are given by the user
 	cacheSettings serverconfig.CacheSettings
 	// sharedDatastoreResources are created by the server
 	sharedDatastoreResources *shared.SharedDatastoreResources
+	checkCache               storage.InMemoryCache[any]
 
 	checkResolver       graph.CheckResolver
 	checkResolverCloser func()
@@ -220,6 +336,63 @@ type Server struct {
 	listUsersDatastoreThrottleThreshold   int
 	listUsersDatastoreThrottleDuration    time.Duration
 
+	datastoreLimiterSaturationThreshold        time.Duration
+	datastoreLimiterSaturationPeriod           time.Duration
+	datastoreLimiterSaturationRatio            float64
+	datastoreLimiterSaturationReadinessEnabled bool
+	datastoreLimiterSaturationMonitor          *storagewrappers.LimiterSaturationMonitor
+	datastoreCircuitBreakerConfig              storagewrappers.CircuitBreakerConfig
+	datastoreRetryConfig                       storagewrappers.RetryConfig
+	datastoreFaultPolicies                     map[string]storagewrappers.FaultPolicy
+	datastoreMiddlewares                       []storagewrappers.TupleReaderMiddleware
+	datastoreReadPrioritySlots                 uint32
+	datastoreReadPriorityWeights               map[string]uint32
+	datastoreHedgeDelay                        time.Duration
+	datastoreMaxConcurrentReads                uint32
+	datastoreReadTimeout                       time.Duration
+	datastoreWriteTimeout                      time.Duration
+	datastoreSlowQueryThreshold                time.Duration
+
+	// requestMetricsStoreIDLabeler labels the request metrics with the store id, if enabled.
+	requestMetricsStoreIDLabeler *storeIDLabeler
+
+	// drainer tracks the requests in flight, see Drain.
+	drainer drainer
+	// drainDelay is the duration Drain keeps serving the new requests once the server reports itself as not ready.
+	drainDelay time.Duration
+
+	// tunableSettings are the settings changed with UpdateTunableSettings, if any.
+	tunableSettings atomic.Pointer[TunableSettings]
+
+	protectedTuplePatterns    []string
+	privilegedTuplePrincipals []string
+	protectedTuples           *commands.ProtectedTuples
+
+	authorizationModelRetention     int
+	authorizationModelPruneInterval time.Duration
+	authorizationModelPruner        *authorizationModelPruner
+
+	modelSyncSources  map[string]string
+	modelSyncInterval time.Duration
+	modelSyncer       *modelSyncer
+
+	maxConcurrentJobs int
+	maxJobTuples      int
+	jobs              *jobRunner
+
+	tupleChangeListener   storage.TupleChangeListener
+	tupleChangeSubscriber *tupleChangeSubscriber
+
+	checkCacheReadinessEnabled          bool
+	tupleChangeListenerReadinessEnabled bool
+
+	redactionMode    redact.Mode
+	redactionHashKey string
+
+	trustedCurrentTimeParameter string
+	trustedCurrentTimePrecision time.Duration
+	trustedCallerParameter      string
+
 	authorizer authz.AuthorizerInterface
 
 	ctx                           context.Context
@@ -245,6 +418,53 @@ func WithContinuationTokenSerializer(ds encoder.ContinuationTokenSerializer) Ope
 	}
 }
 
+// WithTypesystemCacheTTL sets how long the validated authorization models are cached, and overrides it for the
+// stores of storeTTLs, keyed by store id. Defaults to 7 days.
+func WithTypesystemCacheTTL(ttl time.Duration, storeTTLs map[string]time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.typesystemCacheTTL = ttl
+		s.typesystemCacheStoreTTLs = storeTTLs
+	}
+}
+
+// WithTypesystemCacheSize sets the maximum number of validated authorization models cached. If 0, the default, the
+// default size of the cache is used.
+func WithTypesystemCacheSize(size int) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.typesystemCacheSize = size
+	}
+}
+
+// WithTypesystemSharedCache makes the validated authorization models also be cached in cache, shared by the servers,
+// e.g. Redis, so that a model is validated once rather than by every server, e.g. during rollouts.
+func WithTypesystemSharedCache(cache typesystem.SharedModelCache) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.typesystemSharedCache = cache
+	}
+}
+
+// WithTypesystemResolver makes the server resolve the authorization models with resolver, e.g. to load them from files
+// or an external registry, instead of reading them from the datastore and caching them. stop, if not nil, is called
+// when the server is closed. The typesystem cache options, except the warmup, don't apply to resolver, and
+// InvalidateTypesystemCache has no effect.
+func WithTypesystemResolver(resolver typesystem.TypesystemResolverFunc, stop func()) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.typesystemResolver = resolver
+		s.typesystemResolverStop = stop
+	}
+}
+
+// WithTypesystemCacheWarmup caches the latest authorization model of stores on startup, so that their first requests
+// don't wait for it to be read and validated. If mruFile is set, the most recently used stores are persisted to it,
+// and their latest models are also cached on the next startup. The server reports the 'typesystem_cache' component
+// as not ready until the warmup completes.
+func WithTypesystemCacheWarmup(stores []string, mruFile string) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.typesystemWarmupStores = stores
+		s.typesystemWarmupMRUFile = mruFile
+	}
+}
+
 // WithContext passes the server context to allow for graceful shutdowns.
 func WithContext(ctx context.Context) OpenFGAServiceV1Option {
 	return func(s *Server) {
@@ -265,12 +485,57 @@ func WithLogger(l logger.Logger) OpenFGAServiceV1Option {
 	}
 }
 
+// WithTokenEncoder sets the encoder of the continuation tokens, which the server prefixes with their version and
+// expiry beforehand. See [encoder.VersionedTokenEncoder].
 func WithTokenEncoder(encoder encoder.Encoder) OpenFGAServiceV1Option {
 	return func(s *Server) {
 		s.encoder = encoder
 	}
 }
 
+// WithClock sets the clock the server reads the current time from, e.g. to expire its cache entries and continuation
+// tokens, or to set the trusted current time of the requests. It lets tests control the time, e.g. with a
+// [clock.FakeClock]. Defaults to the clock of the system.
+func WithClock(c clock.Clock) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.clock = c
+	}
+}
+
+// WithMaxTuplesPerWrite defines the maximum number of tuples written and deleted by a Write request. It can only
+// lower the limit of the datastore, which is used if 0, the default.
+func WithMaxTuplesPerWrite(maxTuples int) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.maxTuplesPerWrite = maxTuples
+	}
+}
+
+// WithMaxPageSize defines the maximum page size of Read, ReadChanges, ReadAuthorizationModels and ListStores
+// requests, which the API limits to 100. The requests with a larger page size are rejected, and the default page size
+// is lowered to it if it is smaller. If 0, the default, only the API limits the page size.
+func WithMaxPageSize(maxPageSize int32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.maxPageSize = maxPageSize
+	}
+}
+
+// WithContinuationTokenTTL makes the continuation tokens expire once ttl elapsed, after which they are rejected with
+// an error telling the client to restart the pagination. If 0, the default, the tokens never expire.
+func WithContinuationTokenTTL(ttl time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.continuationTokenTTL = ttl
+	}
+}
+
+// WithUnversionedContinuationTokenWindow accepts the continuation tokens returned before their versioning, which have
+// no expiry, for window once the server started, if the continuation tokens expire. Past it, they are rejected as
+// incompatible. If 0, the default, they are rejected as soon as the continuation tokens expire.
+func WithUnversionedContinuationTokenWindow(window time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.unversionedTokenWindow = window
+	}
+}
+
 // WithTransport sets the connection transport.
 func WithTransport(t gateway.Transport) OpenFGAServiceV1Option {
 	return func(s *Server) {
@@ -300,6 +565,73 @@ func WithResolveNodeBreadthLimit(limit uint32) OpenFGAServiceV1Option {
 	}
 }
 
+// WithCheckDispatchPoolSize resolves the subproblems of the set operations of Check and ListObjects on a pool of
+// size workers reused across requests, rather than on a new goroutine each, to lower the scheduler and garbage
+// collection pressure at high request rates. It is raised to the resolve node breadth limit. The utilization of the
+// pool is reported by the check_dispatch_pool_busy_workers and check_dispatch_pool_workers metrics. 0 disables it.
+func WithCheckDispatchPoolSize(size uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkDispatchPoolSize = size
+	}
+}
+
+// WithCheckUnionBranchOrdering resolves the branches of the unions of Check and ListObjects from the cheapest to the
+// most expensive, as estimated from the shape of the model and costs, the known costs of some relations keyed by
+// 'type#relation', e.g. their average number of datastore reads. costs may be nil.
+func WithCheckUnionBranchOrdering(enabled bool, costs graph.RelationCosts) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkUnionBranchOrdering = enabled
+		s.checkRelationCosts = costs
+	}
+}
+
+// WithCheckMembershipIndex indexes, in the background, the transitive members of relations, given as 'type#relation'
+// e.g. 'group#member', which are nested by being directly related to their own usersets, e.g.
+// 'define member: [user, group#member]'. Check allows the members found in the index without resolving the graph,
+// unless it requires higher consistency. The changes of the stores are applied to the index every refreshInterval,
+// and the index of a store is not consulted while its changes were applied more than maxStaleness ago. The index is
+// held in memory, so it grows with the tuples of relations. If relations is empty, the index is disabled.
+func WithCheckMembershipIndex(relations []string, refreshInterval, maxStaleness time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkMembershipIndexRelations = relations
+		s.checkMembershipIndexRefresh = refreshInterval
+		s.checkMembershipIndexMaxStaleness = maxStaleness
+	}
+}
+
+// WithCheckMaterializedViews maintains, in the background, the users of hot relations of some objects, given as
+// 'object#relation' e.g. 'org:acme#member', as computed with the latest model of every store without conditions.
+// Check answers the relations of these views with a lookup instead of resolving the graph, unless the request has
+// contextual tuples, requires higher consistency or uses another model. The changes of the stores are checked every
+// refreshInterval, and the views of a store recomputed when it changed. They are not consulted while they were last
+// brought up to date more than maxStaleness ago. It requires the ExperimentalMaterializedViews feature flag. If views
+// is empty, the views are disabled.
+func WithCheckMaterializedViews(views []string, refreshInterval, maxStaleness time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkMaterializedViewSpecs = views
+		s.checkMaterializedViewsRefresh = refreshInterval
+		s.checkMaterializedViewsStaleness = maxStaleness
+	}
+}
+
+// WithCheckDirectTupleBatchSize sets how many direct tuples dispatched by a Check (e.g. the usersets of a relation
+// or the tupleset of a tuple to userset rewrite) are read from the datastore with a single query, instead of one
+// query each. Values lower than 2 disable batching.
+func WithCheckDirectTupleBatchSize(size uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkDirectTupleBatchSize = size
+	}
+}
+
+// WithCheckDirectTupleBatchMaxDelay sets how long a direct tuple dispatched by a Check waits for its batch to fill
+// before the batch is read, so that the batches do not delay the checks when the tuples are read slowly. 0 waits until
+// the batch fills or the dispatches end. Defaults to 1ms.
+func WithCheckDirectTupleBatchMaxDelay(delay time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkDirectTupleBatchMaxDelay = delay
+	}
+}
+
 // WithUsersetBatchSize in Check requests, configures how many usersets are collected
 // before we start processing them.
 //
@@ -349,6 +681,15 @@ func WithListObjectsMaxResults(limit uint32) OpenFGAServiceV1Option {
 	}
 }
 
+// WithListObjectsDatastoreQueryBudget affects the ListObjects and StreamedListObjects APIs.
+// It sets the number of datastore reads after which they return the results found so far, marked as truncated by
+// the ListObjectsTruncatedHeader. 0 does not limit the reads.
+func WithListObjectsDatastoreQueryBudget(budget uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.listObjectsDatastoreQueryBudget = budget
+	}
+}
+
 // WithListUsersDeadline affect the ListUsers API only.
 // It sets the maximum amount of time that the server will spend gathering results.
 func WithListUsersDeadline(deadline time.Duration) OpenFGAServiceV1Option {
@@ -423,6 +764,30 @@ func WithAccessControlParams(enabled bool, storeID string, modelID string, authn
 	}
 }
 
+// WithCheckTupleCacheEnabled enables caching of single tuples read within Check for subsequent requests.
+func WithCheckTupleCacheEnabled(enabled bool) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.cacheSettings.CheckTupleCacheEnabled = enabled
+	}
+}
+
+// WithCheckTupleCacheTTL sets the TTL of cached single tuples.
+// Needs WithCheckTupleCacheEnabled set to true.
+func WithCheckTupleCacheTTL(ttl time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.cacheSettings.CheckTupleCacheTTL = ttl
+	}
+}
+
+// WithCheckCache sets the cache of check queries, iterators and tuples, instead of an in-memory cache of
+// WithCheckCacheLimit entries. It allows, for example, a cache shared between servers.
+// The server stops the cache when it is closed.
+func WithCheckCache(cache storage.InMemoryCache[any]) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkCache = cache
+	}
+}
+
 // WithCheckQueryCacheEnabled enables caching of Check results for the Check and List objects APIs.
 // This cache is shared for all requests.
 // See also WithCheckCacheLimit and WithCheckQueryCacheTTL.
@@ -453,6 +818,16 @@ func WithCacheControllerTTL(ttl time.Duration) OpenFGAServiceV1Option {
 	}
 }
 
+// WithTupleChangeListener invalidates the cached results of a store, and its cached tuples of the notified object
+// type, as soon as the listener notifies a write to it, e.g. by another server sharing the datastore, instead of
+// waiting for the cache controller TTL to expire.
+// It requires the cache controller to be enabled.
+func WithTupleChangeListener(listener storage.TupleChangeListener) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.tupleChangeListener = listener
+	}
+}
+
 // WithCheckQueryCacheTTL sets the TTL of cached checks and list objects partial results
 // Needs WithCheckQueryCacheEnabled set to true.
 func WithCheckQueryCacheTTL(ttl time.Duration) OpenFGAServiceV1Option {
@@ -461,6 +836,16 @@ func WithCheckQueryCacheTTL(ttl time.Duration) OpenFGAServiceV1Option {
 	}
 }
 
+// WithCheckQueryCacheRevalidation recomputes cached checks in the background when they have been hit at least
+// minHits times and are hit during the last window of their TTL, so that hot checks never pay the recomputation
+// at request time. A window of 0 disables it. Needs WithCheckQueryCacheEnabled set to true.
+func WithCheckQueryCacheRevalidation(window time.Duration, minHits uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.cacheSettings.CheckQueryCacheRevalidationWindow = window
+		s.cacheSettings.CheckQueryCacheRevalidationMinHits = minHits
+	}
+}
+
 // WithCheckIteratorCacheEnabled enables caching of iterators produced within Check for subsequent requests.
 func WithCheckIteratorCacheEnabled(enabled bool) OpenFGAServiceV1Option {
 	return func(s *Server) {
@@ -529,6 +914,63 @@ func WithMaxAuthorizationModelSizeInBytes(size int) OpenFGAServiceV1Option {
 	}
 }
 
+// WithAuthorizationModelRetention keeps only the last count authorization models of every store, deleting the
+// older ones, along with their assertions, every pruneInterval. If count is 0, models are kept forever.
+func WithAuthorizationModelRetention(count int, pruneInterval time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.authorizationModelRetention = count
+		s.authorizationModelPruneInterval = pruneInterval
+	}
+}
+
+// WithModelSync makes the server fetch the authorization models of stores from sources, keyed by store id, every
+// interval, and write them as the latest model of their store when they are valid and differ from it. The sources are
+// URLs of files served over HTTPS, of files of git repositories or of OCI artifacts, see modelsync.NewSource. As
+// every server synchronizes the models, servers starting at once may write the same model more than once.
+func WithModelSync(sources map[string]string, interval time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.modelSyncSources = sources
+		s.modelSyncInterval = interval
+	}
+}
+
+// WithMaxJobTuples sets the maximum number of tuples written by an import job or read by an export job, both of which
+// persist their tuples in the job. Larger imports are rejected, and larger exports fail.
+func WithMaxJobTuples(maxTuples int) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.maxJobTuples = maxTuples
+	}
+}
+
+// WithMaxConcurrentJobs sets the maximum number of jobs, e.g. imports of tuples, run at once by the server. The other
+// jobs stay pending until one completes.
+func WithMaxConcurrentJobs(maxJobs int) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.maxConcurrentJobs = maxJobs
+	}
+}
+
+// WithTrustedContextParameters makes the server set the condition parameter currentTime to the time a request is
+// evaluated at, and the parameter caller to the subject, or else the client id, of the authenticated caller, in the
+// context of Check, BatchCheck, ListObjects, StreamedListObjects and ListUsers requests, of Expand requests with a
+// context, and of the assertions run by RunAssertions. The values supplied by the client for these parameters are
+// overridden, or removed if the server has none. An empty parameter is not set.
+func WithTrustedContextParameters(currentTime, caller string) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.trustedCurrentTimeParameter = currentTime
+		s.trustedCallerParameter = caller
+	}
+}
+
+// WithTrustedCurrentTimePrecision sets the precision the trusted current time is truncated to, see
+// WithTrustedContextParameters. As the context is part of the key of the check cache, the checks evaluated within
+// the same interval of this duration share their cache entries. 0 keeps the full precision. Defaults to a second.
+func WithTrustedCurrentTimePrecision(precision time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.trustedCurrentTimePrecision = precision
+	}
+}
+
 // WithDispatchThrottlingCheckResolverEnabled sets whether dispatch throttling is enabled for Check requests.
 // Enabling this feature will prioritize dispatched requests requiring less than the configured dispatch
 // threshold over requests whose dispatch count exceeds the configured threshold.
@@ -589,6 +1031,8 @@ func MustNewServerWithOpts(opts ...OpenFGAServiceV1Option) *Server {
 }
 
 func (s *Server) IsExperimentallyEnabled(flag ExperimentalFeatureFlag) bool {
+	s.experimentalsMu.RLock()
+	defer s.experimentalsMu.RUnlock()
 	return slices.Contains(s.experimentals, flag)
 }
 
@@ -597,6 +1041,12 @@ func (s *Server) IsAccessControlEnabled() bool {
 	return s.IsExperimentallyEnabled(ExperimentalAccessControlParams) && s.AccessControl.Enabled
 }
 
+// Authorizer returns the authorizer used to enforce access control on the API. If access control
+// is not enabled, it returns an authorizer that allows every call.
+func (s *Server) Authorizer() authz.AuthorizerInterface {
+	return s.authorizer
+}
+
 // WithListObjectsDispatchThrottlingEnabled sets whether dispatch throttling is enabled for List Objects requests.
 // Enabling this feature will prioritize dispatched requests requiring less than the configured dispatch
 // threshold over requests whose dispatch count exceeds the configured threshold.
@@ -689,6 +1139,56 @@ func WithMaxChecksPerBatchCheck(maxChecks uint32) OpenFGAServiceV1Option {
 	}
 }
 
+// WithMaxContextSizeBytes defines the maximum size, in bytes, of the context used for condition evaluation
+// in Check, BatchCheck, ListObjects, StreamedListObjects and ListUsers requests. 0 means no limit.
+func WithMaxContextSizeBytes(maxSize uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.maxContextSizeBytes = maxSize
+	}
+}
+
+// WithMaxContextDepth defines the maximum nesting depth of the context used for condition evaluation
+// in Check, BatchCheck, ListObjects, StreamedListObjects and ListUsers requests. 0 means no limit.
+func WithMaxContextDepth(maxDepth uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.maxContextDepth = maxDepth
+	}
+}
+
+// WithMaxContextualTuples defines the maximum number of contextual tuples of Check, BatchCheck, ListObjects,
+// StreamedListObjects, ListUsers and Expand requests, in addition to the limits of the API. 0 means no limit.
+func WithMaxContextualTuples(maxTuples uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.maxContextualTuples = maxTuples
+	}
+}
+
+// WithResolutionMetadataHeaders returns the number of datastore queries, the number of dispatches and the number
+// of check cache hits of Check, BatchCheck, ListObjects and ListUsers requests in response headers and trailers,
+// and of StreamedListObjects requests in response trailers, so that clients can track how expensive their requests
+// are. The counts of BatchCheck are summed over its checks.
+func WithResolutionMetadataHeaders(enabled bool) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.resolutionMetadataHeadersEnabled = enabled
+	}
+}
+
+// WithDrainDelay sets the duration Drain keeps serving the new requests once the server reports itself as not ready,
+// before it refuses them. It gives the load balancers the time to notice the server is not ready. Defaults to 0.
+func WithDrainDelay(delay time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.drainDelay = delay
+	}
+}
+
+// WithMethodTimeouts sets the timeouts of specific API methods, keyed by method name (e.g. 'Check').
+// They are enforced by the handlers, on top of any timeout of the request.
+func WithMethodTimeouts(timeouts map[string]time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.methodTimeouts = timeouts
+	}
+}
+
 func WithCheckDatabaseThrottle(threshold int, duration time.Duration) OpenFGAServiceV1Option {
 	return func(s *Server) {
 		s.checkDatastoreThrottleThreshold = threshold
@@ -710,6 +1210,167 @@ func WithListUsersDatabaseThrottle(threshold int, duration time.Duration) OpenFG
 	}
 }
 
+// WithDatastoreLimiterSaturation enables detection of sustained saturation of the datastore read concurrency limiter.
+// The limiter is reported as saturated, via the datastore_bounded_read_limiter_saturated metric, while at least ratio
+// of the reads of the last period have waited longer than threshold. A threshold of 0 disables the detection.
+func WithDatastoreLimiterSaturation(threshold, period time.Duration, ratio float64) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreLimiterSaturationThreshold = threshold
+		s.datastoreLimiterSaturationPeriod = period
+		s.datastoreLimiterSaturationRatio = ratio
+	}
+}
+
+// WithDatastoreLimiterSaturationReadinessEnabled makes the server report itself as not ready while the datastore read
+// concurrency limiter is saturated, so that load balancers can shed load. See [WithDatastoreLimiterSaturation].
+func WithDatastoreLimiterSaturationReadinessEnabled(enabled bool) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreLimiterSaturationReadinessEnabled = enabled
+	}
+}
+
+// WithCheckCacheReadinessEnabled makes the server report itself as not ready while its check cache does not store
+// and return entries.
+func WithCheckCacheReadinessEnabled(enabled bool) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.checkCacheReadinessEnabled = enabled
+	}
+}
+
+// WithRedaction redacts the ids of the users and objects, and the values of the condition contexts, in the spans, logs
+// and error messages of the process, with the given mode. The hash key is the secret key of the hashes of
+// [redact.ModeHash]. The redaction is left as is if the option is not set.
+func WithRedaction(mode redact.Mode, hashKey string) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.redactionMode = mode
+		s.redactionHashKey = hashKey
+	}
+}
+
+// WithTupleChangeListenerReadinessEnabled makes the server report itself as not ready while the tuple change
+// notifications of the datastore are interrupted, as its caches are then invalidated on expiry only. See
+// [WithTupleChangeListener].
+func WithTupleChangeListenerReadinessEnabled(enabled bool) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.tupleChangeListenerReadinessEnabled = enabled
+	}
+}
+
+// WithDatastoreCircuitBreaker makes tuple reads and writes fail fast with an Unavailable error while the datastore is
+// failing. The breaker trips once at least minRequests calls were made within window and failureRateThreshold of them
+// failed, and lets a single call probe the datastore once openDuration elapsed. A failureRateThreshold of 0 disables it.
+func WithDatastoreCircuitBreaker(failureRateThreshold float64, minRequests int, window, openDuration time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreCircuitBreakerConfig = storagewrappers.CircuitBreakerConfig{
+			FailureRateThreshold: failureRateThreshold,
+			MinRequests:          minRequests,
+			Window:               window,
+			OpenDuration:         openDuration,
+		}
+	}
+}
+
+// WithDatastoreMiddlewares wraps the tuple reads of every query path with middlewares, e.g. to add caching, tracing
+// or tenant fencing. The first middleware is the outermost one. Middlewares observe the request context, and the
+// reads after the built-in retries and circuit breaking.
+func WithDatastoreMiddlewares(middlewares ...storagewrappers.TupleReaderMiddleware) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreMiddlewares = append(s.datastoreMiddlewares, middlewares...)
+	}
+}
+
+// WithDatastoreReadPriority bounds the concurrent datastore tuple reads of every request to slots, sharing them
+// between the methods of the requests in proportion to their weights while reads wait, so that a burst of ListObjects
+// requests does not starve the Check requests. The weights are keyed by method, e.g. 'Check', and default to
+// [storagewrappers.DefaultPriorityWeights]. A slots of 0 disables the limiter.
+func WithDatastoreReadPriority(slots uint32, weights map[string]uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreReadPrioritySlots = slots
+		s.datastoreReadPriorityWeights = weights
+	}
+}
+
+// WithDatastoreFaultInjection injects latency and errors into the datastore tuple reads and writes, following the
+// policy of each operation of [storagewrappers.FaultInjectionOperations], to test the resilience of the server. It
+// requires the ExperimentalFaultInjection feature flag and must never be used against a production datastore.
+func WithDatastoreFaultInjection(policies map[string]storagewrappers.FaultPolicy) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreFaultPolicies = policies
+	}
+}
+
+// WithDatastoreHedgeDelay hedges the datastore reads made by Check and BatchCheck, including the query of the first
+// tuples of the reads returning an iterator: a read taking longer than d is issued again if the read concurrency
+// limit allows it, and the result returned first is used. A value around the P95 latency of the reads trades few
+// additional reads for a lower tail latency. 0 disables hedging.
+func WithDatastoreHedgeDelay(d time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreHedgeDelay = d
+	}
+}
+
+// WithDatastoreMaxConcurrentReads bounds the concurrent datastore tuple reads of the whole server to
+// maxConcurrentReads, whatever the number of requests, under the per-request limits such as
+// [WithMaxConcurrentReadsForCheck]. The time reads wait for it is reported by the
+// datastore_global_read_limiter_wait_ms metric, and the fraction of it in use by the
+// datastore_global_read_limiter_saturation metric. 0 does not limit the reads.
+func WithDatastoreMaxConcurrentReads(maxConcurrentReads uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreMaxConcurrentReads = maxConcurrentReads
+	}
+}
+
+// WithDatastoreStatementTimeouts cancels the datastore tuple reads after readTimeout and the tuple writes after
+// writeTimeout, so that a runaway query does not hold a connection for the whole request. Calls cancelled this way
+// fail with [storage.ErrDatastoreDeadlineExceeded], surfaced as a DeadlineExceeded error distinct from the request
+// deadline. 0 disables a timeout.
+func WithDatastoreStatementTimeouts(readTimeout, writeTimeout time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreReadTimeout = readTimeout
+		s.datastoreWriteTimeout = writeTimeout
+	}
+}
+
+// WithDatastoreSlowQueryThreshold logs the datastore tuple reads and writes taking longer than d, with their
+// operation, store and the shape of their filter, to find the hotspots and missing indexes of the datastore. 0
+// disables the log.
+func WithDatastoreSlowQueryThreshold(d time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreSlowQueryThreshold = d
+	}
+}
+
+// WithRequestMetricsStoreIDLabel labels, if enabled, the request duration and datastore query count histograms with
+// the store id of the requests, e.g. to measure the SLOs of each tenant. To bound the cardinality of the label, only
+// the stores of the allow-list are labelled with their id or, without an allow-list, the limit stores with the most
+// requests, estimated with a space-saving sketch. The requests of the other stores are labelled 'other'.
+func WithRequestMetricsStoreIDLabel(enabled bool, allowList []string, limit int) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.requestMetricsStoreIDLabeler = nil
+		if enabled {
+			s.requestMetricsStoreIDLabeler = newStoreIDLabeler(allowList, limit)
+		}
+	}
+}
+
+// WithDatastoreRetry retries the tuple reads, and optionally writes, failing with transient datastore errors with
+// exponential backoff and jitter, following the policy of the class of the error. See [storagewrappers.RetryConfig].
+func WithDatastoreRetry(config storagewrappers.RetryConfig) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.datastoreRetryConfig = config
+	}
+}
+
+// WithProtectedTuples places the tuples matching the given patterns under legal hold. Patterns have the form
+// 'type:id[#relation[@user]]', where the object id may be '*'. Deleting a protected tuple, or a store holding one,
+// requires the [commands.ForceDeleteHeader] and credentials whose subject or client id is one of privilegedPrincipals.
+func WithProtectedTuples(patterns []string, privilegedPrincipals []string) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.protectedTuplePatterns = patterns
+		s.privilegedTuplePrincipals = privilegedPrincipals
+	}
+}
+
 // WithShadowCheckResolverEnabled turns of shadow check resolver to allow result comparison.
 // Note that ShadowCheckResolver is a temporary feature and may be removed in future release.
 func WithShadowCheckResolverEnabled(enabled bool) OpenFGAServiceV1Option {
@@ -774,17 +1435,34 @@ func WithSharedIteratorTTL(ttl time.Duration) OpenFGAServiceV1Option {
 	}
 }
 
+// WithRequestIteratorCacheEnabled enables memoizing the results of iterators within a single Check request.
+func WithRequestIteratorCacheEnabled(enabled bool) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.cacheSettings.RequestIteratorCacheEnabled = enabled
+	}
+}
+
+// WithRequestIteratorCacheMaxResults sets the limit of an iterator size to memoize within a request (in items).
+// Needs WithRequestIteratorCacheEnabled set to true.
+func WithRequestIteratorCacheMaxResults(limit uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.cacheSettings.RequestIteratorCacheMaxResults = limit
+	}
+}
+
 // NewServerWithOpts returns a new server.
 // You must call Close on it after you are done using it.
 func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 	s := &Server{
 		ctx:                              context.Background(),
 		logger:                           logger.NewNoopLogger(),
+		clock:                            clock.New(),
 		encoder:                          encoder.NewBase64Encoder(),
 		transport:                        gateway.NewNoopTransport(),
 		changelogHorizonOffset:           serverconfig.DefaultChangelogHorizonOffset,
 		resolveNodeLimit:                 serverconfig.DefaultResolveNodeLimit,
 		resolveNodeBreadthLimit:          serverconfig.DefaultResolveNodeBreadthLimit,
+		checkDirectTupleBatchMaxDelay:    serverconfig.DefaultCheckDirectTupleBatchMaxDelay,
 		listObjectsDeadline:              serverconfig.DefaultListObjectsDeadline,
 		listObjectsMaxResults:            serverconfig.DefaultListObjectsMaxResults,
 		listUsersDeadline:                serverconfig.DefaultListUsersDeadline,
@@ -796,6 +1474,9 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		maxConcurrentReadsForListUsers:   serverconfig.DefaultMaxConcurrentReadsForListUsers,
 		maxAuthorizationModelSizeInBytes: serverconfig.DefaultMaxAuthorizationModelSizeInBytes,
 		maxAuthorizationModelCacheSize:   serverconfig.DefaultMaxAuthorizationModelCacheSize,
+		maxConcurrentJobs:                serverconfig.DefaultMaxConcurrentJobs,
+		maxJobTuples:                     serverconfig.DefaultMaxJobTuples,
+		trustedCurrentTimePrecision:      serverconfig.DefaultTrustedContextCurrentTimePrecision,
 		experimentals:                    make([]ExperimentalFeatureFlag, 0, 10),
 		AccessControl:                    serverconfig.AccessControlConfig{Enabled: false, StoreID: "", ModelID: ""},
 
@@ -838,6 +1519,13 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		opt(s)
 	}
 
+	encoderOpts := []encoder.VersionedTokenEncoderOpt{encoder.WithTokenTTL(s.continuationTokenTTL), encoder.WithTokenClock(s.clock.Now)}
+	if s.continuationTokenTTL > 0 {
+		// the unversioned tokens would otherwise outlive the expiry of the tokens
+		encoderOpts = append(encoderOpts, encoder.WithUnversionedTokensUntil(s.clock.Now().Add(s.unversionedTokenWindow)))
+	}
+	s.encoder = encoder.NewVersionedTokenEncoder(s.encoder, encoderOpts...)
+
 	if s.datastore == nil {
 		return nil, fmt.Errorf("a datastore option must be provided")
 	}
@@ -866,11 +1554,107 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		return nil, fmt.Errorf("ListUsers default dispatch throttling threshold must be equal or smaller than max dispatch threshold for ListUsers")
 	}
 
+	if s.datastoreLimiterSaturationThreshold > 0 && s.datastoreLimiterSaturationPeriod <= 0 {
+		return nil, fmt.Errorf("datastore limiter saturation period must be greater than zero")
+	}
+
+	if s.datastoreLimiterSaturationThreshold > 0 && (s.datastoreLimiterSaturationRatio <= 0 || s.datastoreLimiterSaturationRatio > 1) {
+		return nil, fmt.Errorf("datastore limiter saturation ratio must be greater than 0 and at most 1")
+	}
+
+	if s.datastoreLimiterSaturationReadinessEnabled && s.datastoreLimiterSaturationThreshold <= 0 {
+		return nil, fmt.Errorf("datastore limiter saturation readiness requires a datastore limiter saturation threshold")
+	}
+
+	if breaker := s.datastoreCircuitBreakerConfig; breaker.FailureRateThreshold != 0 {
+		if breaker.FailureRateThreshold < 0 || breaker.FailureRateThreshold > 1 {
+			return nil, fmt.Errorf("datastore circuit breaker failure rate threshold must be between 0 and 1")
+		}
+		if breaker.MinRequests < 1 || breaker.Window <= 0 || breaker.OpenDuration <= 0 {
+			return nil, fmt.Errorf("datastore circuit breaker min requests, window and open duration must be greater than zero")
+		}
+	}
+
+	if len(s.datastoreFaultPolicies) > 0 && !s.IsExperimentallyEnabled(ExperimentalFaultInjection) {
+		return nil, fmt.Errorf("datastore fault injection requires the '%s' experimental feature", ExperimentalFaultInjection)
+	}
+	if len(s.checkMaterializedViewSpecs) > 0 && !s.IsExperimentallyEnabled(ExperimentalMaterializedViews) {
+		return nil, fmt.Errorf("check materialized views require the '%s' experimental feature", ExperimentalMaterializedViews)
+	}
+	for operation, policy := range s.datastoreFaultPolicies {
+		if !slices.Contains(storagewrappers.FaultInjectionOperations, operation) {
+			return nil, fmt.Errorf("unknown datastore fault injection operation '%s', must be one of %v", operation, storagewrappers.FaultInjectionOperations)
+		}
+		if policy.ErrorRate < 0 || policy.ErrorRate > 1 {
+			return nil, fmt.Errorf("datastore fault injection error rate must be between 0 and 1")
+		}
+	}
+
+	revalidationWindow := s.cacheSettings.CheckQueryCacheRevalidationWindow
+	if s.cacheSettings.CheckQueryCacheEnabled && revalidationWindow > 0 && revalidationWindow >= s.cacheSettings.CheckQueryCacheTTL {
+		return nil, fmt.Errorf("check query cache revalidation window must be smaller than the check query cache TTL")
+	}
+
+	if _, ok := profiles[s.profile]; s.profile != "" && !ok {
+		return nil, fmt.Errorf("unknown profile '%s', must be one of ['%s', '%s', '%s']", s.profile, ProfileSingleNode, ProfileHA, ProfileEdge)
+	}
+
 	err := s.validateAccessControlEnabled()
 	if err != nil {
 		return nil, err
 	}
 
+	if s.authorizationModelRetention < 0 {
+		return nil, fmt.Errorf("the authorization model retention must be a non-negative number")
+	}
+	if s.drainDelay < 0 {
+		return nil, fmt.Errorf("the drain delay must be a non-negative number")
+	}
+	if s.authorizationModelRetention > 0 && s.authorizationModelPruneInterval <= 0 {
+		return nil, fmt.Errorf("the authorization model prune interval must be greater than zero")
+	}
+
+	if s.maxConcurrentJobs < 1 {
+		return nil, fmt.Errorf("the maximum number of concurrent jobs must be greater than zero")
+	}
+
+	if s.maxJobTuples < 1 {
+		return nil, fmt.Errorf("the maximum number of tuples of a job must be greater than zero")
+	}
+
+	modelSyncSources := make(map[string]modelsync.Source, len(s.modelSyncSources))
+	for storeID, rawURL := range s.modelSyncSources {
+		if _, err := ulid.ParseStrict(storeID); err != nil {
+			return nil, fmt.Errorf("invalid store id '%s' of a model sync source", storeID)
+		}
+		source, err := modelsync.NewSource(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		modelSyncSources[storeID] = source
+	}
+	if len(modelSyncSources) > 0 && s.modelSyncInterval <= 0 {
+		return nil, fmt.Errorf("the model sync interval must be greater than zero")
+	}
+
+	if s.tupleChangeListener != nil && !s.cacheSettings.ShouldCreateCacheController() {
+		return nil, fmt.Errorf("a tuple change listener requires the cache controller to be enabled")
+	}
+
+	if s.redactionMode != "" {
+		if !slices.Contains(redact.Modes, s.redactionMode) {
+			return nil, fmt.Errorf("unknown redaction mode '%s', must be one of %v", s.redactionMode, redact.Modes)
+		}
+		redact.Configure(s.redactionMode, s.redactionHashKey)
+	}
+
+	if len(s.protectedTuplePatterns) > 0 {
+		s.protectedTuples, err = commands.NewProtectedTuples(s.protectedTuplePatterns, s.privilegedTuplePrincipals)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// below this point, don't throw errors or we may leak resources in tests
 
 	checkDispatchThrottlingOptions := []graph.DispatchThrottlingCheckResolverOpt{}
@@ -886,18 +1670,78 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		}
 	}
 
+	if len(s.datastoreFaultPolicies) > 0 {
+		// Faults are injected closest to the datastore, so that every other wrapper observes them as real failures.
+		s.datastore = storagewrappers.NewFaultInjectingDatastore(s.datastore, s.datastoreFaultPolicies)
+	}
+
+	if s.datastoreReadTimeout > 0 || s.datastoreWriteTimeout > 0 {
+		// Timeouts are applied below retries, so that each attempt is bounded on its own.
+		s.datastore = storagewrappers.NewStatementTimeoutDatastore(s.datastore, s.datastoreReadTimeout, s.datastoreWriteTimeout)
+	}
+
+	if s.datastoreSlowQueryThreshold > 0 {
+		// Slow queries are logged below retries, so that each slow attempt is logged.
+		s.datastore = storagewrappers.NewSlowQueryLoggingDatastore(s.datastore, s.datastoreSlowQueryThreshold, s.logger)
+	}
+
 	if !s.contextPropagationToDatastore {
 		// Creates a new [storagewrappers.ContextTracerWrapper] that will execute datastore queries using
 		// a new background context with the current trace context.
 		s.datastore = storagewrappers.NewContextWrapper(s.datastore)
 	}
 
+	if len(s.datastoreRetryConfig.Policies) > 0 {
+		// Retries are made above the context wrapper, so that they stop backing off once the request is done, and
+		// below the circuit breaker, so that it only observes the calls failing despite them.
+		s.datastore = storagewrappers.NewRetryingDatastore(s.datastore, s.datastoreRetryConfig)
+	}
+
+	if s.datastoreCircuitBreakerConfig.FailureRateThreshold > 0 {
+		// Above the context wrapper, so that the calls failing as their request is done are told apart.
+		s.datastore = storagewrappers.NewCircuitBreakerDatastore(s.datastore, storagewrappers.NewCircuitBreaker(s.datastoreCircuitBreakerConfig))
+	}
+
+	if s.datastoreMaxConcurrentReads > 0 {
+		// Above the context wrapper, so that a read stops waiting once its request is done, and below the priority
+		// limiter, which orders the reads waiting for it.
+		s.datastore = storagewrappers.NewMiddlewareDatastore(s.datastore,
+			storagewrappers.NewGlobalLimitedTupleReaderMiddleware(storagewrappers.NewGlobalReadLimiter(s.datastoreMaxConcurrentReads)),
+		)
+	}
+
+	if s.datastoreReadPrioritySlots > 0 {
+		// The method of a read is that of the request context, so the reads are arbitrated above the context
+		// wrapper. Above retries, a read keeps its slot while it is retried.
+		s.datastore = storagewrappers.NewMiddlewareDatastore(s.datastore,
+			storagewrappers.NewPriorityLimitedTupleReaderMiddleware(
+				storagewrappers.NewPriorityLimiter(s.datastoreReadPrioritySlots, s.datastoreReadPriorityWeights),
+			),
+		)
+	}
+
+	if len(s.datastoreMiddlewares) > 0 {
+		s.datastore = storagewrappers.NewMiddlewareDatastore(s.datastore, s.datastoreMiddlewares...)
+	}
+
 	s.datastore, err = storagewrappers.NewCachedOpenFGADatastore(s.datastore, s.maxAuthorizationModelCacheSize)
 	if err != nil {
 		return nil, err
 	}
 
-	s.sharedDatastoreResources, err = shared.NewSharedDatastoreResources(s.ctx, s.singleflightGroup, s.datastore, s.cacheSettings, []shared.SharedDatastoreResourcesOpt{shared.WithLogger(s.logger)}...)
+	if s.datastoreLimiterSaturationThreshold > 0 {
+		s.datastoreLimiterSaturationMonitor = storagewrappers.NewLimiterSaturationMonitor(s.datastoreLimiterSaturationThreshold, s.datastoreLimiterSaturationPeriod, s.datastoreLimiterSaturationRatio)
+	}
+
+	sharedDatastoreResourcesOpts := []shared.SharedDatastoreResourcesOpt{
+		shared.WithLogger(s.logger),
+		shared.WithClock(s.clock),
+		shared.WithIteratorCacheTTLFunc(func() time.Duration { return s.TunableSettings().CheckIteratorCacheTTL }),
+	}
+	if s.checkCache != nil {
+		sharedDatastoreResourcesOpts = append(sharedDatastoreResourcesOpts, shared.WithCheckCache(s.checkCache))
+	}
+	s.sharedDatastoreResources, err = shared.NewSharedDatastoreResources(s.ctx, s.singleflightGroup, s.datastore, s.cacheSettings, sharedDatastoreResourcesOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -907,15 +1751,49 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		checkCacheOptions = append(checkCacheOptions,
 			graph.WithExistingCache(s.sharedDatastoreResources.CheckCache),
 			graph.WithLogger(s.logger),
-			graph.WithCacheTTL(s.cacheSettings.CheckQueryCacheTTL),
+			graph.WithCacheTTLFunc(func() time.Duration { return s.TunableSettings().CheckQueryCacheTTL }),
+			graph.WithCacheClock(s.clock),
+			graph.WithCacheRevalidation(s.cacheSettings.CheckQueryCacheRevalidationWindow, s.cacheSettings.CheckQueryCacheRevalidationMinHits, s.datastore),
 		)
 	}
 
+	var membershipIndex graph.MembershipIndex
+	if len(s.checkMembershipIndexRelations) > 0 {
+		s.checkMembershipIndex, err = membershipindex.New(s.datastore, s.checkMembershipIndexRelations,
+			membershipindex.WithLogger(s.logger),
+			membershipindex.WithRefreshInterval(s.checkMembershipIndexRefresh),
+			membershipindex.WithMaxStaleness(s.checkMembershipIndexMaxStaleness),
+		)
+		if err != nil {
+			return nil, err
+		}
+		membershipIndex = s.checkMembershipIndex
+	}
+
+	var materializedViews graph.MaterializedViews
+	if len(s.checkMaterializedViewSpecs) > 0 {
+		s.checkMaterializedViews, err = materializedview.New(s.datastore, s.checkMaterializedViewSpecs,
+			materializedview.WithLogger(s.logger),
+			materializedview.WithRefreshInterval(s.checkMaterializedViewsRefresh),
+			materializedview.WithMaxStaleness(s.checkMaterializedViewsStaleness),
+		)
+		if err != nil {
+			return nil, err
+		}
+		materializedViews = s.checkMaterializedViews
+	}
+
 	s.checkResolver, s.checkResolverCloser, err = graph.NewOrderedCheckResolvers([]graph.CheckResolverOrderedBuilderOpt{
 		graph.WithLocalCheckerOpts([]graph.LocalCheckerOption{
 			graph.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
 			graph.WithOptimizations(s.IsExperimentallyEnabled(ExperimentalCheckOptimizations)),
 			graph.WithMaxResolutionDepth(s.resolveNodeLimit),
+			graph.WithDirectTupleBatchSize(s.checkDirectTupleBatchSize),
+			graph.WithDirectTupleBatchMaxDelay(s.checkDirectTupleBatchMaxDelay),
+			graph.WithDispatchPoolSize(s.checkDispatchPoolSize),
+			graph.WithUnionBranchOrdering(s.checkUnionBranchOrdering, s.checkRelationCosts),
+			graph.WithMembershipIndex(membershipIndex),
+			graph.WithMaterializedViews(materializedViews),
 		}...),
 		graph.WithLocalShadowCheckerOpts([]graph.LocalCheckerOption{
 			graph.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
@@ -940,6 +1818,10 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 			graph.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
 			graph.WithOptimizations(s.IsExperimentallyEnabled(ExperimentalListObjectsOptimizations)),
 			graph.WithMaxResolutionDepth(s.resolveNodeLimit),
+			graph.WithDispatchPoolSize(s.checkDispatchPoolSize),
+			graph.WithUnionBranchOrdering(s.checkUnionBranchOrdering, s.checkRelationCosts),
+			graph.WithMembershipIndex(membershipIndex),
+			graph.WithMaterializedViews(materializedViews),
 		}...),
 		graph.WithLocalShadowCheckerOpts([]graph.LocalCheckerOption{
 			graph.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
@@ -968,22 +1850,80 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		s.listUsersDispatchThrottler = throttler.NewConstantRateThrottler(s.listUsersDispatchThrottlingFrequency, "list_users_dispatch_throttle")
 	}
 
-	s.typesystemResolver, s.typesystemResolverStop, err = typesystem.MemoizedTypesystemResolverFunc(s.datastore)
-	if err != nil {
-		return nil, err
+	if s.typesystemResolver != nil {
+		// the resolver is supplied by the caller
+		s.typesystemResolverInvalidate = func(string) {}
+		if s.typesystemResolverStop == nil {
+			s.typesystemResolverStop = func() {}
+		}
+	} else {
+		typesystemResolverOpts := []typesystem.MemoizedResolverOption{
+			typesystem.WithCacheStoreTTLs(s.typesystemCacheStoreTTLs),
+			typesystem.WithCacheSize(int64(s.typesystemCacheSize)),
+		}
+		if s.typesystemCacheTTL > 0 {
+			typesystemResolverOpts = append(typesystemResolverOpts, typesystem.WithCacheTTL(s.typesystemCacheTTL))
+		}
+		if s.typesystemSharedCache != nil {
+			typesystemResolverOpts = append(typesystemResolverOpts, typesystem.WithSharedCache(s.typesystemSharedCache))
+		}
+		s.typesystemResolver, s.typesystemResolverInvalidate, s.typesystemResolverStop, err = typesystem.MemoizedTypesystemResolverFuncWithInvalidation(s.datastore, typesystemResolverOpts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.typesystemWarmupStores) > 0 || s.typesystemWarmupMRUFile != "" {
+		s.typesystemWarmer = newTypesystemWarmer(s.typesystemResolver, s.logger, s.typesystemWarmupStores, s.typesystemWarmupMRUFile)
 	}
 
 	if s.IsAccessControlEnabled() {
 		s.authorizer = authz.NewAuthorizer(&authz.Config{StoreID: s.AccessControl.StoreID, ModelID: s.AccessControl.ModelID}, s, s.logger)
 	}
 
+	if s.authorizationModelRetention > 0 {
+		s.authorizationModelPruner = newAuthorizationModelPruner(s.datastore, s.logger, s.authorizationModelRetention, s.authorizationModelPruneInterval, s.typesystemResolverInvalidate)
+	}
+
+	if len(modelSyncSources) > 0 {
+		s.modelSyncer = newModelSyncer(s.datastore, s.logger, modelSyncSources, s.modelSyncInterval, s.maxAuthorizationModelSizeInBytes)
+	}
+
+	s.jobs = newJobRunner(s.datastore, s.logger, s.maxConcurrentJobs)
+
+	if s.tupleChangeListener != nil {
+		s.tupleChangeSubscriber = newTupleChangeSubscriber(s.tupleChangeListener, s.sharedDatastoreResources.CacheController, s.logger)
+	}
+
 	return s, nil
 }
 
 // Close releases the server resources.
 func (s *Server) Close() {
+	if s.authorizationModelPruner != nil {
+		s.authorizationModelPruner.Close()
+	}
+	if s.modelSyncer != nil {
+		s.modelSyncer.Close()
+	}
+	if s.jobs != nil {
+		s.jobs.Close()
+	}
+	if s.tupleChangeSubscriber != nil {
+		s.tupleChangeSubscriber.Close()
+	}
+	if s.checkMembershipIndex != nil {
+		s.checkMembershipIndex.Close()
+	}
+	if s.checkMaterializedViews != nil {
+		s.checkMaterializedViews.Close()
+	}
+
 	s.checkResolverCloser()
 	s.listObjectsCheckResolverCloser()
+	if s.typesystemWarmer != nil {
+		s.typesystemWarmer.Close()
+	}
 	s.typesystemResolverStop()
 
 	if s.listObjectsDispatchThrottler != nil {
@@ -997,29 +1937,34 @@ func (s *Server) Close() {
 	s.datastore.Close()
 }
 
-// IsReady reports whether the datastore is ready. Please see the implementation of [[storage.OpenFGADatastore.IsReady]]
-// for your datastore.
+// IsReady reports whether the server is ready, i.e. whether all its components reported by ReadinessComponents are
+// ready. Please see the implementation of [[storage.OpenFGADatastore.IsReady]] for your datastore.
 func (s *Server) IsReady(ctx context.Context) (bool, error) {
-	// for now we only depend on the datastore being ready, but in the future
-	// server readiness may also depend on other criteria in addition to the
-	// datastore being ready.
-
-	status, err := s.datastore.IsReady(ctx)
+	components, err := s.readinessComponents(ctx)
 	if err != nil {
 		return false, err
 	}
 
-	if status.IsReady {
-		return true, nil
+	ready := true
+	for _, component := range components {
+		if !component.Ready {
+			s.logger.WarnWithContext(ctx, "server component is not ready",
+				zap.String("component", component.Name),
+				zap.String("status", component.Message))
+			ready = false
+		}
 	}
 
-	s.logger.WarnWithContext(ctx, "datastore is not ready", zap.Any("status", status.Message))
-	return false, nil
+	return ready, nil
 }
 
 // resolveTypesystem resolves the underlying TypeSystem given the storeID and modelID and
 // it sets some response metadata based on the model resolution.
 func (s *Server) resolveTypesystem(ctx context.Context, storeID, modelID string) (*typesystem.TypeSystem, error) {
+	if typesys, ok := simulatedTypesystemFromContext(ctx, storeID); ok {
+		return typesys, nil
+	}
+
 	parentSpan := trace.SpanFromContext(ctx)
 	typesys, err := s.typesystemResolver(ctx, storeID, modelID)
 	if err != nil {
@@ -1045,6 +1990,11 @@ func (s *Server) resolveTypesystem(ctx context.Context, storeID, modelID string)
 	parentSpan.SetAttributes(attribute.String(authorizationModelIDKey, resolvedModelID))
 	grpc_ctxtags.Extract(ctx).Set(authorizationModelIDKey, resolvedModelID)
 	s.transport.SetHeader(ctx, AuthorizationModelIDHeader, resolvedModelID)
+	gateway.SetTrailer(ctx, s.transport, AuthorizationModelIDHeader, resolvedModelID)
+
+	if s.typesystemWarmer != nil {
+		s.typesystemWarmer.Touch(storeID)
+	}
 
 	return typesys, nil
 }
@@ -1078,13 +2028,97 @@ func (s *Server) checkAuthz(ctx context.Context, storeID string, apiMethod apime
 
 	err := s.authorizer.Authorize(ctx, storeID, apiMethod, modules...)
 	if err != nil {
-		s.logger.Info("authorization failed", zap.Error(err))
+		s.logger.WarnWithContext(ctx, "authorization failed",
+			logger.SecurityEvent(logger.SecurityEventAuthorizationDenied),
+			zap.String("store_id", storeID),
+			zap.String("method", apiMethod.String()),
+			zap.Error(err),
+		)
 		return authz.ErrUnauthorizedResponse
 	}
 
 	return nil
 }
 
+// setResolutionMetadataHeaders sets the resolution metadata of a request as response headers and trailers, if enabled.
+func (s *Server) setResolutionMetadataHeaders(ctx context.Context, datastoreQueryCount, dispatchCount, checkCacheHits uint32) {
+	if !s.resolutionMetadataHeadersEnabled {
+		return
+	}
+	for key, value := range resolutionMetadata(datastoreQueryCount, dispatchCount, checkCacheHits) {
+		s.transport.SetHeader(ctx, key, value)
+		gateway.SetTrailer(ctx, s.transport, key, value)
+	}
+}
+
+// setResolutionMetadataTrailers sets the resolution metadata of a streaming request as response trailers, if enabled,
+// its headers having been sent with its first message.
+func (s *Server) setResolutionMetadataTrailers(ctx context.Context, datastoreQueryCount, dispatchCount, checkCacheHits uint32) {
+	if !s.resolutionMetadataHeadersEnabled {
+		return
+	}
+	for key, value := range resolutionMetadata(datastoreQueryCount, dispatchCount, checkCacheHits) {
+		gateway.SetTrailer(ctx, s.transport, key, value)
+	}
+}
+
+func resolutionMetadata(datastoreQueryCount, dispatchCount, checkCacheHits uint32) map[string]string {
+	return map[string]string{
+		DatastoreQueryCountHeader: strconv.FormatUint(uint64(datastoreQueryCount), 10),
+		DispatchCountHeader:       strconv.FormatUint(uint64(dispatchCount), 10),
+		CheckCacheHitsHeader:      strconv.FormatUint(uint64(checkCacheHits), 10),
+	}
+}
+
+// withMethodTimeout returns a copy of ctx that is canceled once the timeout of apiMethod elapses, if the
+// method has one.
+func (s *Server) withMethodTimeout(ctx context.Context, apiMethod apimethod.APIMethod) (context.Context, context.CancelFunc) {
+	timeout, ok := s.methodTimeouts[apiMethod.String()]
+	if !ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// validateRequestLimits returns an error if the number of contextual tuples of a request exceeds its limit, or if its
+// context exceeds the size or depth limit.
+func (s *Server) validateRequestLimits(apiMethod apimethod.APIMethod, reqCtx *structpb.Struct, contextualTuples int) error {
+	if s.maxContextualTuples > 0 && contextualTuples > int(s.maxContextualTuples) {
+		rejectedRequestContextCounter.WithLabelValues(s.serviceName, apiMethod.String(), "contextual_tuples").Inc()
+		return serverErrors.ContextualTuplesLimitExceeded(contextualTuples, s.maxContextualTuples)
+	}
+
+	err := validation.ValidateContext(reqCtx, s.maxContextSizeBytes, s.maxContextDepth)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, validation.ErrContextTooDeep) {
+		rejectedRequestContextCounter.WithLabelValues(s.serviceName, apiMethod.String(), "depth").Inc()
+		return serverErrors.ContextTooDeep(err)
+	}
+	rejectedRequestContextCounter.WithLabelValues(s.serviceName, apiMethod.String(), "size").Inc()
+	return serverErrors.ContextTooLarge(err)
+}
+
+// pageSize returns the page size of a paginated request: its page size, or the maximum page size if it has none and
+// the default page size exceeds it. It returns a validation error if the page size exceeds the maximum.
+func (s *Server) pageSize(pageSize *wrapperspb.Int32Value) (*wrapperspb.Int32Value, error) {
+	if s.maxPageSize <= 0 {
+		return pageSize, nil
+	}
+	if pageSize == nil {
+		if storage.DefaultPageSize > s.maxPageSize {
+			return wrapperspb.Int32(s.maxPageSize), nil
+		}
+		return nil, nil
+	}
+	if pageSize.GetValue() > s.maxPageSize {
+		return nil, serverErrors.PageSizeTooLarge(pageSize.GetValue(), s.maxPageSize)
+	}
+	return pageSize, nil
+}
+
 // checkCreateStoreAuthz checks the authorization for creating a store.
 func (s *Server) checkCreateStoreAuthz(ctx context.Context) error {
 	if authclaims.SkipAuthzCheckFromContext(ctx) {
@@ -1093,7 +2127,11 @@ func (s *Server) checkCreateStoreAuthz(ctx context.Context) error {
 
 	err := s.authorizer.AuthorizeCreateStore(ctx)
 	if err != nil {
-		s.logger.Info("authorization failed", zap.Error(err))
+		s.logger.WarnWithContext(ctx, "authorization failed",
+			logger.SecurityEvent(logger.SecurityEventAuthorizationDenied),
+			zap.String("method", apimethod.CreateStore.String()),
+			zap.Error(err),
+		)
 		return authz.ErrUnauthorizedResponse
 	}
 
@@ -1109,13 +2147,21 @@ func (s *Server) getAccessibleStores(ctx context.Context) ([]string, error) {
 
 	err := s.authorizer.AuthorizeListStores(ctx)
 	if err != nil {
-		s.logger.Info("authorization failed", zap.Error(err))
+		s.logger.WarnWithContext(ctx, "authorization failed",
+			logger.SecurityEvent(logger.SecurityEventAuthorizationDenied),
+			zap.String("method", apimethod.ListStores.String()),
+			zap.Error(err),
+		)
 		return nil, authz.ErrUnauthorizedResponse
 	}
 
 	stores, err := s.authorizer.ListAuthorizedStores(ctx)
 	if err != nil {
-		s.logger.Info("authorization failed", zap.Error(err))
+		s.logger.WarnWithContext(ctx, "authorization failed",
+			logger.SecurityEvent(logger.SecurityEventAuthorizationDenied),
+			zap.String("method", apimethod.ListStores.String()),
+			zap.Error(err),
+		)
 		return nil, authz.ErrUnauthorizedResponse
 	}
 
@@ -1130,7 +2176,12 @@ func (s *Server) checkWriteAuthz(ctx context.Context, req *openfgav1.WriteReques
 
 	modules, err := s.authorizer.GetModulesForWriteRequest(ctx, req, typesys)
 	if err != nil {
-		s.logger.Info("authorization failed", zap.Error(err))
+		s.logger.WarnWithContext(ctx, "authorization failed",
+			logger.SecurityEvent(logger.SecurityEventAuthorizationDenied),
+			zap.String("store_id", req.GetStoreId()),
+			zap.String("method", apimethod.Write.String()),
+			zap.Error(err),
+		)
 		return authz.ErrUnauthorizedResponse
 	}
 
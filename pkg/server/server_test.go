@@ -9,19 +9,25 @@ import (
 	"path"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 	parser "github.com/openfga/language/pkg/go/transformer"
@@ -32,8 +38,13 @@ import (
 	"github.com/openfga/openfga/internal/cachecontroller"
 	"github.com/openfga/openfga/internal/graph"
 	mockstorage "github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/clock"
+	"github.com/openfga/openfga/pkg/redact"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/server/health"
 	"github.com/openfga/openfga/pkg/server/test"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/memory"
@@ -242,6 +253,85 @@ func TestServerPanicIfValidationsFail(t *testing.T) {
 			sqlcommon.NewDBInfo(nil, sq.StatementBuilder, nil, "invalid-dialect")
 		})
 	})
+	t.Run("unknown_profile", func(t *testing.T) {
+		require.PanicsWithError(t, "failed to construct the OpenFGA server: unknown profile 'serverless', must be one of ['single-node', 'ha', 'edge']", func() {
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+			mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+			_ = MustNewServerWithOpts(
+				WithDatastore(mockDatastore),
+				WithProfile("serverless"),
+			)
+		})
+	})
+
+	t.Run("profile_overridden_with_an_incompatible_option", func(t *testing.T) {
+		require.PanicsWithError(t, "failed to construct the OpenFGA server: datastore limiter saturation readiness requires a datastore limiter saturation threshold", func() {
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+			mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+			_ = MustNewServerWithOpts(
+				WithDatastore(mockDatastore),
+				WithProfile(ProfileHA),
+				WithDatastoreLimiterSaturation(0, 30*time.Second, 0.5),
+			)
+		})
+	})
+}
+
+func TestProfiles(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	for profile := range profiles {
+		t.Run(string(profile), func(t *testing.T) {
+			ds := memory.New()
+			t.Cleanup(ds.Close)
+
+			s, err := NewServerWithOpts(WithDatastore(ds), WithProfile(profile))
+			require.NoError(t, err)
+			t.Cleanup(s.Close)
+
+			require.Equal(t, profile, s.profile)
+		})
+	}
+
+	t.Run("later_options_override_the_profile", func(t *testing.T) {
+		ds := memory.New()
+		t.Cleanup(ds.Close)
+
+		s, err := NewServerWithOpts(WithDatastore(ds), WithProfile(ProfileEdge), WithListObjectsMaxResults(500))
+		require.NoError(t, err)
+		t.Cleanup(s.Close)
+
+		require.Equal(t, uint32(500), s.listObjectsMaxResults)
+		require.Equal(t, time.Second, s.listObjectsDeadline)
+	})
+}
+
+func TestRedaction(t *testing.T) {
+	t.Cleanup(func() {
+		redact.Configure(redact.ModeOff, "")
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	_, err := NewServerWithOpts(WithDatastore(ds), WithRedaction("mask", ""))
+	require.ErrorContains(t, err, "unknown redaction mode 'mask'")
+
+	s, err := NewServerWithOpts(WithDatastore(ds))
+	require.NoError(t, err)
+	s.Close()
+	require.False(t, redact.Enabled())
+
+	s, err = NewServerWithOpts(WithDatastore(ds), WithRedaction(redact.ModeDrop, ""))
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+	require.True(t, redact.Enabled())
+	require.Equal(t, "document:?", redact.Object("document:budget"))
 }
 
 func TestServerNotReadyDueToDatastoreRevision(t *testing.T) {
@@ -264,8 +354,106 @@ func TestServerNotReadyDueToDatastoreRevision(t *testing.T) {
 			status, _ := ds.IsReady(context.Background())
 			require.Contains(t, status.Message, fmt.Sprintf("datastore requires migrations: at revision '%d', but requires '%d'.", targetVersion, build.MinimumSupportedDatastoreSchemaRevision))
 			require.False(t, status.IsReady)
+			require.Equal(t, targetVersion, status.Revision)
+		})
+	}
+}
+
+func TestServerReadinessComponents(t *testing.T) {
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	s := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithCheckQueryCacheEnabled(true),
+		WithCheckCacheReadinessEnabled(true),
+	)
+	t.Cleanup(s.Close)
+
+	components := s.ReadinessComponents(context.Background())
+	require.Equal(t, []health.ComponentStatus{
+		{Name: "server", Ready: true},
+		{Name: "datastore", Ready: true},
+		{Name: "check_cache", Ready: true},
+	}, components)
+
+	ready, err := s.IsReady(context.Background())
+	require.NoError(t, err)
+	require.True(t, ready)
+
+	require.NoError(t, s.Drain(context.Background()))
+	require.Equal(t, health.ComponentStatus{Name: "server", Ready: false, Message: "server is draining"},
+		s.ReadinessComponents(context.Background())[0])
+
+	ready, err = s.IsReady(context.Background())
+	require.NoError(t, err)
+	require.False(t, ready)
+}
+
+func TestServerMaxPageSizeAndMaxTuplesPerWrite(t *testing.T) {
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	s := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithMaxPageSize(10),
+		WithMaxTuplesPerWrite(2),
+	)
+	t.Cleanup(s.Close)
+
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user]`)
+	modelResp, err := s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:         storeID,
+		TypeDefinitions: model.GetTypeDefinitions(),
+		SchemaVersion:   model.GetSchemaVersion(),
+	})
+	require.NoError(t, err)
+
+	t.Run("write_exceeding_the_max_tuples", func(t *testing.T) {
+		_, err := s.Write(ctx, &openfgav1.WriteRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: modelResp.GetAuthorizationModelId(),
+			Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+				tuple.NewTupleKey("document:2", "viewer", "user:anne"),
+				tuple.NewTupleKey("document:3", "viewer", "user:anne"),
+			}},
+		})
+		require.Equal(t, codes.Code(openfgav1.ErrorCode_exceeded_entity_limit), status.Code(err))
+	})
+
+	for i := 0; i < 6; i++ {
+		_, err := s.Write(ctx, &openfgav1.WriteRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: modelResp.GetAuthorizationModelId(),
+			Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+				tuple.NewTupleKey(fmt.Sprintf("document:%d", i), "viewer", "user:anne"),
+				tuple.NewTupleKey(fmt.Sprintf("document:%d", i), "viewer", "user:bob"),
+			}},
 		})
+		require.NoError(t, err)
 	}
+
+	t.Run("read_exceeding_the_max_page_size", func(t *testing.T) {
+		_, err := s.Read(ctx, &openfgav1.ReadRequest{
+			StoreId:  storeID,
+			PageSize: wrapperspb.Int32(20),
+		})
+		require.Equal(t, codes.Code(openfgav1.ErrorCode_page_size_invalid), status.Code(err))
+	})
+
+	t.Run("read_defaults_to_the_max_page_size", func(t *testing.T) {
+		resp, err := s.Read(ctx, &openfgav1.ReadRequest{StoreId: storeID})
+		require.NoError(t, err)
+		require.Len(t, resp.GetTuples(), 10)
+		require.NotEmpty(t, resp.GetContinuationToken())
+	})
 }
 
 func TestServerPanicIfEmptyRequestDurationDatastoreCountBuckets(t *testing.T) {
@@ -2531,3 +2719,1143 @@ func TestBatchCheckWithCachedIterator(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, batchCheckResponse.GetResult()[fakeID].GetAllowed())
 }
+
+type recordingTransport struct {
+	mu       sync.Mutex
+	headers  map[string]string
+	trailers map[string]string
+}
+
+func (r *recordingTransport) SetHeader(_ context.Context, key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.headers[key] = value
+}
+
+func (r *recordingTransport) SetTrailer(_ context.Context, key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trailers[key] = value
+}
+
+func TestResolutionMetadataHeaders(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	newServer := func(t *testing.T, enabled bool) (*Server, *recordingTransport, string) {
+		transport := &recordingTransport{headers: map[string]string{}, trailers: map[string]string{}}
+		s := MustNewServerWithOpts(
+			WithDatastore(memory.New()),
+			WithTransport(transport),
+			WithCheckQueryCacheEnabled(true),
+			WithResolutionMetadataHeaders(enabled),
+		)
+		t.Cleanup(s.Close)
+
+		writeModelResp, err := s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+			StoreId:       storeID,
+			SchemaVersion: typesystem.SchemaVersion1_1,
+			TypeDefinitions: parser.MustTransformDSLToProto(`
+				model
+					schema 1.1
+
+				type user
+
+				type document
+					relations
+						define viewer: [user]`).GetTypeDefinitions(),
+		})
+		require.NoError(t, err)
+
+		_, err = s.Write(ctx, &openfgav1.WriteRequest{
+			StoreId: storeID,
+			Writes: &openfgav1.WriteRequestWrites{
+				TupleKeys: []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "viewer", "user:jon")},
+			},
+		})
+		require.NoError(t, err)
+
+		return s, transport, writeModelResp.GetAuthorizationModelId()
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		s, transport, modelID := newServer(t, true)
+		req := &openfgav1.CheckRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: modelID,
+			TupleKey:             tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:jon"),
+		}
+
+		_, err := s.Check(ctx, req)
+		require.NoError(t, err)
+		require.NotEqual(t, "0", transport.headers[DatastoreQueryCountHeader])
+		require.Equal(t, "0", transport.headers[CheckCacheHitsHeader])
+		require.Contains(t, transport.headers, DispatchCountHeader)
+
+		_, err = s.Check(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, "0", transport.headers[DatastoreQueryCountHeader])
+		require.Equal(t, "1", transport.headers[CheckCacheHitsHeader])
+
+		require.Equal(t, transport.headers[DatastoreQueryCountHeader], transport.trailers[DatastoreQueryCountHeader])
+		require.Equal(t, transport.headers[DispatchCountHeader], transport.trailers[DispatchCountHeader])
+		require.Equal(t, transport.headers[CheckCacheHitsHeader], transport.trailers[CheckCacheHitsHeader])
+		require.Equal(t, modelID, transport.trailers[AuthorizationModelIDHeader])
+	})
+
+	t.Run("batch_check_sums_its_checks", func(t *testing.T) {
+		s, transport, modelID := newServer(t, true)
+		req := &openfgav1.BatchCheckRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: modelID,
+			Checks: []*openfgav1.BatchCheckItem{
+				{
+					TupleKey:      tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:jon"),
+					CorrelationId: "1",
+				},
+				{
+					TupleKey:      tuple.NewCheckRequestTupleKey("document:2", "viewer", "user:jon"),
+					CorrelationId: "2",
+				},
+			},
+		}
+
+		_, err := s.BatchCheck(ctx, req)
+		require.NoError(t, err)
+		require.NotEqual(t, "0", transport.headers[DatastoreQueryCountHeader])
+		require.Equal(t, "0", transport.headers[CheckCacheHitsHeader])
+		require.Contains(t, transport.headers, DispatchCountHeader)
+
+		_, err = s.BatchCheck(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, "2", transport.headers[CheckCacheHitsHeader])
+		require.Equal(t, transport.headers[CheckCacheHitsHeader], transport.trailers[CheckCacheHitsHeader])
+	})
+
+	t.Run("streamed_list_objects_sets_trailers", func(t *testing.T) {
+		s, transport, modelID := newServer(t, true)
+
+		err := s.StreamedListObjects(&openfgav1.StreamedListObjectsRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: modelID,
+			Type:                 "document",
+			Relation:             "viewer",
+			User:                 "user:jon",
+		}, NewMockStreamServer(ctx))
+		require.NoError(t, err)
+		require.NotContains(t, transport.headers, DatastoreQueryCountHeader)
+		require.NotEqual(t, "0", transport.trailers[DatastoreQueryCountHeader])
+		require.Contains(t, transport.trailers, DispatchCountHeader)
+		require.Contains(t, transport.trailers, CheckCacheHitsHeader)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s, transport, modelID := newServer(t, false)
+
+		_, err := s.Check(ctx, &openfgav1.CheckRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: modelID,
+			TupleKey:             tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:jon"),
+		})
+		require.NoError(t, err)
+		require.NotContains(t, transport.headers, DatastoreQueryCountHeader)
+		require.NotContains(t, transport.headers, CheckCacheHitsHeader)
+		require.NotContains(t, transport.trailers, DatastoreQueryCountHeader)
+		require.Equal(t, modelID, transport.trailers[AuthorizationModelIDHeader])
+	})
+}
+
+func TestReadAssertionsCoverageHeaders(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	transport := &recordingTransport{headers: map[string]string{}, trailers: map[string]string{}}
+	s := MustNewServerWithOpts(
+		WithDatastore(memory.New()),
+		WithTransport(transport),
+	)
+	t.Cleanup(s.Close)
+
+	writeModelResp, err := s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:       storeID,
+		SchemaVersion: typesystem.SchemaVersion1_1,
+		TypeDefinitions: parser.MustTransformDSLToProto(`
+			model
+				schema 1.1
+
+			type user
+
+			type folder
+				relations
+					define viewer: [user]
+
+			type document
+				relations
+					define editor: [user]
+					define viewer: [user] or editor`).GetTypeDefinitions(),
+	})
+	require.NoError(t, err)
+
+	_, err = s.WriteAssertions(ctx, &openfgav1.WriteAssertionsRequest{
+		StoreId:              storeID,
+		AuthorizationModelId: writeModelResp.GetAuthorizationModelId(),
+		Assertions: []*openfgav1.Assertion{{
+			TupleKey:    tuple.NewAssertionTupleKey("document:1", "viewer", "user:jon"),
+			Expectation: false,
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = s.ReadAssertions(ctx, &openfgav1.ReadAssertionsRequest{
+		StoreId:              storeID,
+		AuthorizationModelId: writeModelResp.GetAuthorizationModelId(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1/3", transport.headers[AssertionRelationCoverageHeader])
+	require.Equal(t, "1/2", transport.headers[AssertionTypeCoverageHeader])
+	require.Equal(t, "document#editor,folder#viewer", transport.headers[UncoveredRelationsHeader])
+}
+
+func TestStoreLabels(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	transport := &recordingTransport{headers: map[string]string{}, trailers: map[string]string{}}
+	s := MustNewServerWithOpts(
+		WithDatastore(memory.New()),
+		WithTransport(transport),
+	)
+	t.Cleanup(s.Close)
+
+	withLabels := func(labels string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(StoreLabelsHeader, labels))
+	}
+
+	created, err := s.CreateStore(withLabels("team=iam, env=prod"), &openfgav1.CreateStoreRequest{Name: "acme"})
+	require.NoError(t, err)
+	_, err = s.CreateStore(withLabels("env=prod"), &openfgav1.CreateStoreRequest{Name: "other"})
+	require.NoError(t, err)
+
+	_, err = s.GetStore(context.Background(), &openfgav1.GetStoreRequest{StoreId: created.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "env=prod,team=iam", transport.headers[StoreLabelsResponseHeader])
+
+	listed, err := s.ListStores(withLabels("env=prod,team=iam"), &openfgav1.ListStoresRequest{})
+	require.NoError(t, err)
+	require.Len(t, listed.GetStores(), 1)
+	require.Equal(t, created.GetId(), listed.GetStores()[0].GetId())
+
+	byName, err := s.GetStoreByName(context.Background(), "acme")
+	require.NoError(t, err)
+	require.Equal(t, created.GetId(), byName.GetId())
+
+	err = s.UpdateStoreLabels(context.Background(), created.GetId(), map[string]string{"env": "dev"})
+	require.NoError(t, err)
+	listed, err = s.ListStores(withLabels("env=prod"), &openfgav1.ListStoresRequest{})
+	require.NoError(t, err)
+	require.Len(t, listed.GetStores(), 1)
+	require.Equal(t, "other", listed.GetStores()[0].GetName())
+
+	_, err = s.CreateStore(withLabels("env"), &openfgav1.CreateStoreRequest{Name: "invalid"})
+	require.ErrorContains(t, err, "expected 'key=value'")
+
+	err = s.UpdateStoreLabels(context.Background(), ulid.Make().String(), map[string]string{"env": "dev"})
+	require.ErrorIs(t, err, serverErrors.ErrStoreIDNotFound)
+}
+
+func TestAuthorizationModelRetention(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+
+	ds := memory.New()
+	store, err := ds.CreateStore(ctx, &openfgav1.Store{Id: ulid.Make().String(), Name: "acme"})
+	require.NoError(t, err)
+
+	typeDefinitions := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+
+		type user`).GetTypeDefinitions()
+
+	s := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithAuthorizationModelRetention(2, 10*time.Millisecond),
+	)
+	t.Cleanup(s.Close)
+
+	modelIDs := make([]string, 0, 4)
+	for i := 0; i < 4; i++ {
+		resp, err := s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+			StoreId:         store.GetId(),
+			SchemaVersion:   typesystem.SchemaVersion1_1,
+			TypeDefinitions: typeDefinitions,
+		})
+		require.NoError(t, err)
+		modelIDs = append(modelIDs, resp.GetAuthorizationModelId())
+
+		// the latest model is not pruned yet, and is now cached
+		_, err = s.typesystemResolver(ctx, store.GetId(), resp.GetAuthorizationModelId())
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		models, _, err := ds.ReadAuthorizationModels(ctx, store.GetId(), storage.ReadAuthorizationModelsOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, ""),
+		})
+		require.NoError(t, err)
+		return len(models) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	err = s.DeleteAuthorizationModel(ctx, store.GetId(), modelIDs[3])
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// the pruned models are evicted from the cache
+	_, err = s.typesystemResolver(ctx, store.GetId(), modelIDs[0])
+	require.ErrorIs(t, err, typesystem.ErrModelNotFound)
+
+	err = s.DeleteAuthorizationModel(ctx, store.GetId(), modelIDs[2])
+	require.NoError(t, err)
+
+	_, err = s.typesystemResolver(ctx, store.GetId(), modelIDs[2])
+	require.ErrorIs(t, err, typesystem.ErrModelNotFound)
+
+	err = s.DeleteAuthorizationModel(ctx, store.GetId(), modelIDs[0])
+	require.ErrorIs(t, err, serverErrors.AuthorizationModelNotFound(modelIDs[0]))
+}
+
+// channelTupleChangeListener notifies the tuple changes sent to its channel.
+type channelTupleChangeListener struct {
+	notifications chan storage.TupleChangeNotification
+}
+
+func (l *channelTupleChangeListener) ListenTupleChanges(ctx context.Context, handler func(storage.TupleChangeNotification)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-l.notifications:
+			handler(notification)
+		}
+	}
+}
+
+func TestTupleChangeListener(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	t.Run("requires_the_cache_controller", func(t *testing.T) {
+		_, err := NewServerWithOpts(
+			WithDatastore(memory.New()),
+			WithTupleChangeListener(&channelTupleChangeListener{}),
+		)
+		require.EqualError(t, err, "a tuple change listener requires the cache controller to be enabled")
+	})
+
+	t.Run("notifications_invalidate_the_object_type", func(t *testing.T) {
+		listener := &channelTupleChangeListener{notifications: make(chan storage.TupleChangeNotification)}
+		s := MustNewServerWithOpts(
+			WithDatastore(memory.New()),
+			WithCheckQueryCacheEnabled(true),
+			WithCacheControllerEnabled(true),
+			WithTupleChangeListener(listener),
+		)
+		t.Cleanup(s.Close)
+
+		storeID := ulid.Make().String()
+		listener.notifications <- storage.TupleChangeNotification{StoreID: storeID, ObjectType: "document"}
+
+		require.Eventually(t, func() bool {
+			return s.sharedDatastoreResources.CheckCache.Get(storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, "document")) != nil
+		}, time.Second, 10*time.Millisecond)
+		require.NotNil(t, s.sharedDatastoreResources.CheckCache.Get(storage.GetChangelogCacheKey(storeID)))
+		require.Nil(t, s.sharedDatastoreResources.CheckCache.Get(storage.GetInvalidIteratorCacheKey(storeID)))
+	})
+}
+
+func TestSimulation(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+
+	ds := memory.New()
+	store, err := ds.CreateStore(ctx, &openfgav1.Store{Id: ulid.Make().String(), Name: "acme"})
+	require.NoError(t, err)
+
+	err = ds.Write(ctx, store.GetId(), nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "editor", "user:anne"),
+		tuple.NewTupleKey("document:2", "viewer", "user:anne"),
+	})
+	require.NoError(t, err)
+
+	candidate := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define editor: [user]
+				define viewer: [user] or editor`)
+
+	checkReq := &openfgav1.CheckRequest{
+		StoreId:  store.GetId(),
+		TupleKey: tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:anne"),
+	}
+
+	t.Run("requires_the_experimental_flag", func(t *testing.T) {
+		s := MustNewServerWithOpts(WithDatastore(ds))
+		t.Cleanup(s.Close)
+
+		_, err := s.SimulateCheck(ctx, candidate, checkReq)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	s := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithExperimentals(ExperimentalSimulation),
+	)
+	t.Cleanup(s.Close)
+
+	t.Run("check_against_the_candidate_model", func(t *testing.T) {
+		resp, err := s.SimulateCheck(ctx, candidate, checkReq)
+		require.NoError(t, err)
+		require.True(t, resp.GetAllowed())
+
+		_, err = ds.FindLatestAuthorizationModel(ctx, store.GetId())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("list_objects_against_the_candidate_model", func(t *testing.T) {
+		resp, err := s.SimulateListObjects(ctx, candidate, &openfgav1.ListObjectsRequest{
+			StoreId:  store.GetId(),
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:anne",
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"document:1", "document:2"}, resp.GetObjects())
+	})
+
+	t.Run("invalid_candidate_model", func(t *testing.T) {
+		_, err := s.SimulateCheck(ctx, &openfgav1.AuthorizationModel{
+			SchemaVersion: typesystem.SchemaVersion1_1,
+			TypeDefinitions: []*openfgav1.TypeDefinition{{
+				Type: "document",
+				Relations: map[string]*openfgav1.Userset{
+					"viewer": typesystem.ComputedUserset("undefined"),
+				},
+			}},
+		}, checkReq)
+		require.Equal(t, codes.Code(openfgav1.ErrorCode_invalid_authorization_model), status.Code(err))
+	})
+
+	t.Run("model_id_cannot_be_set", func(t *testing.T) {
+		_, err := s.SimulateCheck(ctx, candidate, &openfgav1.CheckRequest{
+			StoreId:              store.GetId(),
+			AuthorizationModelId: ulid.Make().String(),
+			TupleKey:             checkReq.GetTupleKey(),
+		})
+		require.Equal(t, codes.Code(openfgav1.ErrorCode_validation_error), status.Code(err))
+	})
+
+	t.Run("candidate_model_exceeding_the_size_limit", func(t *testing.T) {
+		s := MustNewServerWithOpts(
+			WithDatastore(ds),
+			WithExperimentals(ExperimentalSimulation),
+			WithMaxAuthorizationModelSizeInBytes(proto.Size(candidate)-1),
+		)
+		t.Cleanup(s.Close)
+
+		_, err := s.SimulateCheck(ctx, candidate, checkReq)
+		require.Equal(t, codes.Code(openfgav1.ErrorCode_exceeded_entity_limit), status.Code(err))
+	})
+}
+
+func TestRunAssertions(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+
+	ds := memory.New()
+	s := MustNewServerWithOpts(WithDatastore(ds))
+	t.Cleanup(s.Close)
+
+	store, err := s.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "acme"})
+	require.NoError(t, err)
+
+	model, err := s.WriteAuthorizationModelDSL(ctx, store.GetId(), `
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user]`)
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: store.GetId(),
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = s.WriteAssertions(ctx, &openfgav1.WriteAssertionsRequest{
+		StoreId:              store.GetId(),
+		AuthorizationModelId: model.GetAuthorizationModelId(),
+		Assertions: []*openfgav1.Assertion{
+			{TupleKey: tuple.NewAssertionTupleKey("document:1", "viewer", "user:anne"), Expectation: true},
+			{TupleKey: tuple.NewAssertionTupleKey("document:1", "viewer", "user:bob"), Expectation: true},
+			{
+				TupleKey:         tuple.NewAssertionTupleKey("document:2", "viewer", "user:bob"),
+				Expectation:      true,
+				ContextualTuples: []*openfgav1.TupleKey{tuple.NewTupleKey("document:2", "viewer", "user:bob")},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	results, err := s.RunAssertions(ctx, store.GetId(), "")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.True(t, results[0].Passed)
+	require.False(t, results[1].Passed)
+	require.True(t, results[2].Passed)
+
+	_, err = s.RunAssertions(ctx, "invalid", "")
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	t.Run("checks_within_the_check_timeout", func(t *testing.T) {
+		s := MustNewServerWithOpts(
+			WithDatastore(ds),
+			WithMethodTimeouts(map[string]time.Duration{
+				apimethod.ReadAssertions.String(): time.Nanosecond,
+				apimethod.Check.String():          time.Minute,
+			}),
+		)
+		t.Cleanup(s.Close)
+
+		results, err := s.RunAssertions(ctx, store.GetId(), "")
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		for _, result := range results {
+			require.NoError(t, result.Err)
+		}
+	})
+}
+
+func TestMethodTimeouts(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	mockController := gomock.NewController(t)
+	t.Cleanup(mockController.Finish)
+
+	mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+	mockDatastore.EXPECT().Close().AnyTimes()
+	mockDatastore.EXPECT().
+		ReadPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ *openfgav1.TupleKey, _ storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+			select {
+			case <-ctx.Done():
+				return nil, "", ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+				return nil, "", nil
+			}
+		}).
+		Times(2)
+
+	req := &openfgav1.ReadRequest{StoreId: ulid.Make().String()}
+
+	t.Run("times_out_the_method", func(t *testing.T) {
+		s := MustNewServerWithOpts(
+			WithDatastore(mockDatastore),
+			WithContextPropagationToDatastore(true),
+			WithMethodTimeouts(map[string]time.Duration{apimethod.Read.String(): 10 * time.Millisecond}),
+		)
+		t.Cleanup(s.Close)
+
+		_, err := s.Read(context.Background(), req)
+		require.ErrorIs(t, err, serverErrors.ErrRequestDeadlineExceeded)
+	})
+
+	t.Run("does_not_time_out_the_other_methods", func(t *testing.T) {
+		s := MustNewServerWithOpts(
+			WithDatastore(mockDatastore),
+			WithContextPropagationToDatastore(true),
+			WithMethodTimeouts(map[string]time.Duration{apimethod.ReadChanges.String(): 10 * time.Millisecond}),
+		)
+		t.Cleanup(s.Close)
+
+		_, err := s.Read(context.Background(), req)
+		require.NoError(t, err)
+	})
+}
+
+func TestDatastoreRetryStopsOnceTheRequestIsDone(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	mockController := gomock.NewController(t)
+	t.Cleanup(mockController.Finish)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+	mockDatastore.EXPECT().Close().AnyTimes()
+	mockDatastore.EXPECT().
+		ReadPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string, *openfgav1.TupleKey, storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+			// the request is done while the read backs off
+			time.AfterFunc(10*time.Millisecond, cancel)
+			return nil, "", &storage.TransientError{Class: storage.TransientConnection, Err: errors.New("connection reset by peer")}
+		}).
+		Times(1)
+
+	// Without the propagation of the context to the datastore, the datastore calls are made with a detached context.
+	s := MustNewServerWithOpts(
+		WithDatastore(mockDatastore),
+		WithDatastoreRetry(storagewrappers.RetryConfig{
+			Policies: map[storage.TransientErrorClass]storagewrappers.RetryPolicy{
+				storage.TransientConnection: {MaxRetries: 3, InitialInterval: time.Minute, MaxInterval: time.Minute},
+			},
+		}),
+	)
+	t.Cleanup(s.Close)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Read(ctx, &openfgav1.ReadRequest{StoreId: ulid.Make().String()})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the read kept backing off once its request was done")
+	}
+}
+
+func TestRequestLimits(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	s := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithMaxContextualTuples(1),
+		WithMaxContextSizeBytes(64),
+		WithMaxContextDepth(2),
+	)
+	t.Cleanup(s.Close)
+
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	contextualTuples := []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("document:2", "viewer", "user:anne"),
+	}
+	largeContext, err := structpb.NewStruct(map[string]any{"x": strings.Repeat("a", 100)})
+	require.NoError(t, err)
+	deepContext, err := structpb.NewStruct(map[string]any{"x": map[string]any{"y": map[string]any{"z": 1}}})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		method         apimethod.APIMethod
+		call           func() error
+		expectedReason string
+		metricReason   string
+	}{
+		`check_too_many_contextual_tuples`: {
+			method: apimethod.Check,
+			call: func() error {
+				_, err := s.Check(ctx, &openfgav1.CheckRequest{
+					StoreId:          storeID,
+					TupleKey:         tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:anne"),
+					ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: contextualTuples},
+				})
+				return err
+			},
+			expectedReason: serverErrors.ReasonContextualTuplesExceeded,
+			metricReason:   "contextual_tuples",
+		},
+		`batch_check_too_many_contextual_tuples`: {
+			method: apimethod.BatchCheck,
+			call: func() error {
+				_, err := s.BatchCheck(ctx, &openfgav1.BatchCheckRequest{
+					StoreId: storeID,
+					Checks: []*openfgav1.BatchCheckItem{{
+						TupleKey:         &openfgav1.CheckRequestTupleKey{Object: "document:1", Relation: "viewer", User: "user:anne"},
+						ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: contextualTuples},
+						CorrelationId:    "1",
+					}},
+				})
+				return err
+			},
+			expectedReason: serverErrors.ReasonContextualTuplesExceeded,
+			metricReason:   "contextual_tuples",
+		},
+		`list_objects_too_many_contextual_tuples`: {
+			method: apimethod.ListObjects,
+			call: func() error {
+				_, err := s.ListObjects(ctx, &openfgav1.ListObjectsRequest{
+					StoreId:          storeID,
+					Type:             "document",
+					Relation:         "viewer",
+					User:             "user:anne",
+					ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: contextualTuples},
+				})
+				return err
+			},
+			expectedReason: serverErrors.ReasonContextualTuplesExceeded,
+			metricReason:   "contextual_tuples",
+		},
+		`list_users_too_many_contextual_tuples`: {
+			method: apimethod.ListUsers,
+			call: func() error {
+				_, err := s.ListUsers(ctx, &openfgav1.ListUsersRequest{
+					StoreId:          storeID,
+					Object:           &openfgav1.Object{Type: "document", Id: "1"},
+					Relation:         "viewer",
+					UserFilters:      []*openfgav1.UserTypeFilter{{Type: "user"}},
+					ContextualTuples: contextualTuples,
+				})
+				return err
+			},
+			expectedReason: serverErrors.ReasonContextualTuplesExceeded,
+			metricReason:   "contextual_tuples",
+		},
+		`list_objects_context_too_large`: {
+			method: apimethod.ListObjects,
+			call: func() error {
+				_, err := s.ListObjects(ctx, &openfgav1.ListObjectsRequest{
+					StoreId:  storeID,
+					Type:     "document",
+					Relation: "viewer",
+					User:     "user:anne",
+					Context:  largeContext,
+				})
+				return err
+			},
+			expectedReason: serverErrors.ReasonContextTooLarge,
+			metricReason:   "size",
+		},
+		`check_context_too_deep`: {
+			method: apimethod.Check,
+			call: func() error {
+				_, err := s.Check(ctx, &openfgav1.CheckRequest{
+					StoreId:  storeID,
+					TupleKey: tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:anne"),
+					Context:  deepContext,
+				})
+				return err
+			},
+			expectedReason: serverErrors.ReasonContextTooDeep,
+			metricReason:   "depth",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			counter := rejectedRequestContextCounter.WithLabelValues(s.serviceName, test.method.String(), test.metricReason)
+			before := testutil.ToFloat64(counter)
+
+			err := test.call()
+			require.Equal(t, test.expectedReason, serverErrors.Reason(status.Convert(err)))
+			require.InDelta(t, before+1, testutil.ToFloat64(counter), 0)
+		})
+	}
+}
+
+func TestTrustedContextParameters(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+
+	ds := memory.New()
+	untrusted := MustNewServerWithOpts(WithDatastore(ds))
+	t.Cleanup(untrusted.Close)
+	trusted := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithTrustedContextParameters("current_time", "caller"),
+	)
+	t.Cleanup(trusted.Close)
+
+	store, err := untrusted.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "acme"})
+	require.NoError(t, err)
+
+	model, err := untrusted.WriteAuthorizationModelDSL(ctx, store.GetId(), `
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user with not_expired, user with is_owner]
+
+		condition not_expired(current_time: timestamp, expires_at: timestamp) {
+			current_time < expires_at
+		}
+
+		condition is_owner(caller: string, owner: string) {
+			caller == owner
+		}`)
+	require.NoError(t, err)
+
+	_, err = untrusted.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: store.GetId(),
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKeyWithCondition("document:1", "viewer", "user:anne", "not_expired",
+				testutils.MustNewStruct(t, map[string]interface{}{"expires_at": "2020-01-01T00:00:00Z"})),
+			tuple.NewTupleKeyWithCondition("document:2", "viewer", "user:anne", "is_owner",
+				testutils.MustNewStruct(t, map[string]interface{}{"owner": "anne"})),
+		}},
+	})
+	require.NoError(t, err)
+
+	check := func(t *testing.T, ctx context.Context, s *Server, object string, reqCtx map[string]interface{}) bool {
+		resp, err := s.Check(ctx, &openfgav1.CheckRequest{
+			StoreId:  store.GetId(),
+			TupleKey: tuple.NewCheckRequestTupleKey(object, "viewer", "user:anne"),
+			Context:  testutils.MustNewStruct(t, reqCtx),
+		})
+		require.NoError(t, err)
+		return resp.GetAllowed()
+	}
+
+	t.Run("current_time_overrides_the_client_value", func(t *testing.T) {
+		reqCtx := map[string]interface{}{"current_time": "2019-01-01T00:00:00Z"}
+		require.True(t, check(t, ctx, untrusted, "document:1", reqCtx))
+		require.False(t, check(t, ctx, trusted, "document:1", reqCtx))
+	})
+
+	t.Run("caller_overrides_the_client_value", func(t *testing.T) {
+		bob := authclaims.ContextWithAuthClaims(ctx, &authclaims.AuthClaims{Subject: "bob"})
+		require.True(t, check(t, bob, untrusted, "document:2", map[string]interface{}{"caller": "anne"}))
+		require.False(t, check(t, bob, trusted, "document:2", map[string]interface{}{"caller": "anne"}))
+
+		anne := authclaims.ContextWithAuthClaims(ctx, &authclaims.AuthClaims{ClientID: "anne"})
+		require.True(t, check(t, anne, trusted, "document:2", map[string]interface{}{}))
+	})
+
+	t.Run("caller_is_removed_without_claims", func(t *testing.T) {
+		resp, err := trusted.Check(ctx, &openfgav1.CheckRequest{
+			StoreId:  store.GetId(),
+			TupleKey: tuple.NewCheckRequestTupleKey("document:2", "viewer", "user:anne"),
+			Context:  testutils.MustNewStruct(t, map[string]interface{}{"caller": "anne"}),
+		})
+		require.Error(t, err)
+		require.False(t, resp.GetAllowed())
+	})
+
+	t.Run("current_time_is_truncated", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 10, 30, 15, 123456789, time.UTC)
+		for precision, expected := range map[time.Duration]string{
+			time.Second: "2024-05-01T10:30:15Z",
+			time.Minute: "2024-05-01T10:30:00Z",
+			0:           "2024-05-01T10:30:15.123456789Z",
+		} {
+			s := MustNewServerWithOpts(
+				WithDatastore(ds),
+				WithClock(clock.NewFake(now)),
+				WithTrustedContextParameters("current_time", ""),
+				WithTrustedCurrentTimePrecision(precision),
+			)
+			reqCtx := s.withTrustedContext(ctx, nil)
+			s.Close()
+			require.Equal(t, expected, reqCtx.GetFields()["current_time"].GetStringValue())
+		}
+	})
+
+	t.Run("requests_are_not_modified", func(t *testing.T) {
+		reqCtx := testutils.MustNewStruct(t, map[string]interface{}{"current_time": "2019-01-01T00:00:00Z"})
+
+		listUsersReq := &openfgav1.ListUsersRequest{
+			StoreId:     store.GetId(),
+			Object:      &openfgav1.Object{Type: "document", Id: "1"},
+			Relation:    "viewer",
+			UserFilters: []*openfgav1.UserTypeFilter{{Type: "user"}},
+			Context:     reqCtx,
+		}
+		resp, err := trusted.ListUsers(ctx, listUsersReq)
+		require.NoError(t, err)
+		require.Empty(t, resp.GetUsers())
+		require.Same(t, reqCtx, listUsersReq.GetContext())
+
+		batchCheckReq := &openfgav1.BatchCheckRequest{
+			StoreId: store.GetId(),
+			Checks: []*openfgav1.BatchCheckItem{{
+				TupleKey:      tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:anne"),
+				Context:       reqCtx,
+				CorrelationId: "1",
+			}},
+		}
+		batchResp, err := trusted.BatchCheck(ctx, batchCheckReq)
+		require.NoError(t, err)
+		require.False(t, batchResp.GetResult()["1"].GetAllowed())
+		require.Same(t, reqCtx, batchCheckReq.GetChecks()[0].GetContext())
+		require.Len(t, reqCtx.GetFields(), 1)
+	})
+
+	t.Run("assertions_are_run_with_the_trusted_context", func(t *testing.T) {
+		_, err := trusted.WriteAssertions(ctx, &openfgav1.WriteAssertionsRequest{
+			StoreId:              store.GetId(),
+			AuthorizationModelId: model.GetAuthorizationModelId(),
+			Assertions: []*openfgav1.Assertion{{
+				TupleKey:    tuple.NewAssertionTupleKey("document:1", "viewer", "user:anne"),
+				Context:     testutils.MustNewStruct(t, map[string]interface{}{"current_time": "2019-01-01T00:00:00Z"}),
+				Expectation: true,
+			}},
+		})
+		require.NoError(t, err)
+
+		results, err := untrusted.RunAssertions(ctx, store.GetId(), model.GetAuthorizationModelId())
+		require.NoError(t, err)
+		require.True(t, results[0].Passed)
+
+		results, err = trusted.RunAssertions(ctx, store.GetId(), model.GetAuthorizationModelId())
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+		require.False(t, results[0].Allowed)
+	})
+}
+
+func TestResultGrantsTrailer(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+
+	transport := &recordingTransport{headers: map[string]string{}, trailers: map[string]string{}}
+	s := MustNewServerWithOpts(
+		WithDatastore(memory.New()),
+		WithTransport(transport),
+	)
+	t.Cleanup(s.Close)
+
+	store, err := s.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "acme"})
+	require.NoError(t, err)
+
+	_, err = s.WriteAuthorizationModelDSL(ctx, store.GetId(), `
+		model
+			schema 1.1
+
+		type user
+
+		type group
+			relations
+				define member: [user]
+
+		type document
+			relations
+				define viewer: [user, user:*, group#member]`)
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: store.GetId(),
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:2", "viewer", "user:*"),
+			tuple.NewTupleKey("document:3", "viewer", "group:eng#member"),
+			tuple.NewTupleKey("group:eng", "member", "user:anne"),
+			tuple.NewTupleKey("document:3", "viewer", "user:bob"),
+		}},
+	})
+	require.NoError(t, err)
+
+	annotated := metadata.NewIncomingContext(ctx, metadata.Pairs(AnnotateGrantsHeader, "true"))
+
+	parseGrants := func(t *testing.T) map[string]string {
+		grants := map[string]string{}
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		for _, grant := range strings.Split(transport.trailers[ResultGrantsHeader], ",") {
+			result, kind, found := strings.Cut(grant, "=")
+			require.True(t, found)
+			grants[result] = kind
+		}
+		return grants
+	}
+
+	t.Run("list_objects", func(t *testing.T) {
+		_, err := s.ListObjects(annotated, &openfgav1.ListObjectsRequest{
+			StoreId:  store.GetId(),
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:anne",
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"document:1": "direct",
+			"document:2": "wildcard",
+			"document:3": "userset",
+		}, parseGrants(t))
+	})
+
+	t.Run("streamed_list_objects", func(t *testing.T) {
+		delete(transport.trailers, ResultGrantsHeader)
+		err := s.StreamedListObjects(&openfgav1.StreamedListObjectsRequest{
+			StoreId:  store.GetId(),
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:anne",
+		}, NewMockStreamServer(annotated))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"document:1": "direct",
+			"document:2": "wildcard",
+			"document:3": "userset",
+		}, parseGrants(t))
+	})
+
+	t.Run("list_users", func(t *testing.T) {
+		_, err := s.ListUsers(annotated, &openfgav1.ListUsersRequest{
+			StoreId:     store.GetId(),
+			Object:      &openfgav1.Object{Type: "document", Id: "3"},
+			Relation:    "viewer",
+			UserFilters: []*openfgav1.UserTypeFilter{{Type: "user"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"user:anne": "userset",
+			"user:bob":  "direct",
+		}, parseGrants(t))
+	})
+
+	t.Run("results_are_capped", func(t *testing.T) {
+		writes := make([]*openfgav1.TupleKey, 0, maxAnnotatedGrants+10)
+		for i := 0; i < maxAnnotatedGrants+10; i++ {
+			writes = append(writes, tuple.NewTupleKey(fmt.Sprintf("document:many-%d", i), "viewer", "user:carl"))
+		}
+		for _, batch := range [][]*openfgav1.TupleKey{writes[:maxAnnotatedGrants/2], writes[maxAnnotatedGrants/2:]} {
+			_, err := s.Write(ctx, &openfgav1.WriteRequest{
+				StoreId: store.GetId(),
+				Writes:  &openfgav1.WriteRequestWrites{TupleKeys: batch},
+			})
+			require.NoError(t, err)
+		}
+
+		resp, err := s.ListObjects(annotated, &openfgav1.ListObjectsRequest{
+			StoreId:  store.GetId(),
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:carl",
+		})
+		require.NoError(t, err)
+		require.Greater(t, len(resp.GetObjects()), maxAnnotatedGrants)
+
+		grants := parseGrants(t)
+		require.Len(t, grants, maxAnnotatedGrants)
+		for _, object := range resp.GetObjects()[:maxAnnotatedGrants] {
+			require.Contains(t, []string{"direct", "wildcard"}, grants[object])
+		}
+	})
+
+	t.Run("not_requested", func(t *testing.T) {
+		delete(transport.trailers, ResultGrantsHeader)
+		_, err := s.ListObjects(ctx, &openfgav1.ListObjectsRequest{
+			StoreId:  store.GetId(),
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:anne",
+		})
+		require.NoError(t, err)
+		require.NotContains(t, transport.trailers, ResultGrantsHeader)
+	})
+
+	t.Run("invalid_value", func(t *testing.T) {
+		_, err := s.ListObjects(metadata.NewIncomingContext(ctx, metadata.Pairs(AnnotateGrantsHeader, "maybe")), &openfgav1.ListObjectsRequest{
+			StoreId:  store.GetId(),
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:anne",
+		})
+		require.Error(t, err)
+		require.Equal(t, codes.Code(openfgav1.ErrorCode_validation_error), status.Code(err))
+	})
+}
+
+func TestWithTypesystemResolver(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	store, err := ds.CreateStore(ctx, &openfgav1.Store{Id: ulid.Make().String(), Name: "acme"})
+	require.NoError(t, err)
+
+	// the model is never written to the datastore
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+
+		type user
+		type document
+			relations
+				define viewer: [user]`)
+	typesys, err := typesystem.NewAndValidate(ctx, model)
+	require.NoError(t, err)
+
+	stopped := false
+	s := MustNewServerWithOpts(
+		WithDatastore(ds),
+		WithTypesystemResolver(func(ctx context.Context, storeID, modelID string) (*typesystem.TypeSystem, error) {
+			if modelID != "" && modelID != model.GetId() {
+				return nil, typesystem.ErrModelNotFound
+			}
+			return typesys, nil
+		}, func() { stopped = true }),
+	)
+
+	_, err = s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: store.GetId(),
+		Writes: &openfgav1.WriteRequestWrites{
+			TupleKeys: []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "viewer", "user:anne")},
+		},
+	})
+	require.NoError(t, err)
+
+	checkResp, err := s.Check(ctx, &openfgav1.CheckRequest{
+		StoreId:  store.GetId(),
+		TupleKey: tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:anne"),
+	})
+	require.NoError(t, err)
+	require.True(t, checkResp.GetAllowed())
+
+	_, err = s.Check(ctx, &openfgav1.CheckRequest{
+		StoreId:              store.GetId(),
+		AuthorizationModelId: ulid.Make().String(),
+		TupleKey:             tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:anne"),
+	})
+	require.Equal(t, codes.Code(openfgav1.ErrorCode_authorization_model_not_found), status.Code(err))
+
+	s.InvalidateTypesystemCache(store.GetId())
+
+	s.Close()
+	require.True(t, stopped)
+}
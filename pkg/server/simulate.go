@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+type simulatedTypesystemKey struct{}
+
+type simulatedTypesystem struct {
+	storeID string
+	typesys *typesystem.TypeSystem
+}
+
+// contextWithSimulatedTypesystem returns a context in which the requests to the store are evaluated against typesys,
+// whatever the authorization model they target.
+func contextWithSimulatedTypesystem(ctx context.Context, storeID string, typesys *typesystem.TypeSystem) context.Context {
+	return context.WithValue(ctx, simulatedTypesystemKey{}, &simulatedTypesystem{storeID: storeID, typesys: typesys})
+}
+
+func simulatedTypesystemFromContext(ctx context.Context, storeID string) (*typesystem.TypeSystem, bool) {
+	simulated, ok := ctx.Value(simulatedTypesystemKey{}).(*simulatedTypesystem)
+	if !ok || simulated.storeID != storeID {
+		return nil, false
+	}
+	return simulated.typesys, true
+}
+
+// SimulateCheck evaluates the Check request against the tuples of the store as if model, which is never written,
+// were its authorization model. It requires the ExperimentalSimulation feature flag. It is a library method, for the
+// servers embedding OpenFGA: it is not exposed by the gRPC and HTTP APIs.
+func (s *Server) SimulateCheck(ctx context.Context, model *openfgav1.AuthorizationModel, req *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	ctx, err := s.simulate(ctx, req.GetStoreId(), req.GetAuthorizationModelId(), model)
+	if err != nil {
+		return nil, err
+	}
+	return s.Check(ctx, req)
+}
+
+// SimulateListObjects evaluates the ListObjects request against the tuples of the store as if model, which is never
+// written, were its authorization model. It requires the ExperimentalSimulation feature flag. It is a library method,
+// for the servers embedding OpenFGA: it is not exposed by the gRPC and HTTP APIs.
+func (s *Server) SimulateListObjects(ctx context.Context, model *openfgav1.AuthorizationModel, req *openfgav1.ListObjectsRequest) (*openfgav1.ListObjectsResponse, error) {
+	ctx, err := s.simulate(ctx, req.GetStoreId(), req.GetAuthorizationModelId(), model)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListObjects(ctx, req)
+}
+
+// simulate validates the candidate model, within the limits the models written by WriteAuthorizationModel are held to,
+// and returns a context evaluating the requests to the store against it.
+func (s *Server) simulate(ctx context.Context, storeID, modelID string, model *openfgav1.AuthorizationModel) (context.Context, error) {
+	if !s.IsExperimentallyEnabled(ExperimentalSimulation) {
+		return nil, status.Error(codes.Unimplemented, "simulation is not enabled. It can be enabled for experimental use by passing the `--experimentals enable-simulation` configuration option when running OpenFGA server")
+	}
+
+	if modelID != "" {
+		return nil, serverErrors.ValidationError(errors.New("a simulated request cannot target an authorization model id"))
+	}
+	if model == nil {
+		return nil, serverErrors.ValidationError(errors.New("a candidate authorization model is required"))
+	}
+
+	// The candidate gets an id of its own, so that the results cached for it never mix with those of a stored model.
+	candidate := proto.Clone(model).(*openfgav1.AuthorizationModel)
+	candidate.Id = ulid.Make().String()
+	if candidate.GetSchemaVersion() == "" {
+		candidate.SchemaVersion = typesystem.SchemaVersion1_1
+	}
+
+	if len(candidate.GetTypeDefinitions()) > s.datastore.MaxTypesPerAuthorizationModel() {
+		return nil, serverErrors.ExceededEntityLimit("type definitions in an authorization model", s.datastore.MaxTypesPerAuthorizationModel())
+	}
+	if modelSize := proto.Size(candidate); modelSize > s.maxAuthorizationModelSizeInBytes {
+		return nil, status.Error(
+			codes.Code(openfgav1.ErrorCode_exceeded_entity_limit),
+			fmt.Sprintf("model exceeds size limit: %d bytes vs %d bytes", modelSize, s.maxAuthorizationModelSizeInBytes),
+		)
+	}
+
+	typesys, err := typesystem.NewAndValidate(ctx, candidate)
+	if err != nil {
+		return nil, serverErrors.InvalidAuthorizationModelInput(err)
+	}
+
+	return contextWithSimulatedTypesystem(ctx, storeID, typesys), nil
+}
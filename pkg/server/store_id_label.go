@@ -0,0 +1,115 @@
+package server
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// otherStoresLabel labels the request metrics of the stores beyond the cardinality limit of the store id label.
+	otherStoresLabel = "other"
+
+	// storeIDSketchFactor is the number of stores counted by the sketch of a storeIDLabeler per store labelled, so
+	// that the stores with the most requests are told apart from the ones requested in between.
+	storeIDSketchFactor = 10
+)
+
+// storeIDLabeler returns the store id label of the request metrics, bounding its cardinality: the stores of the
+// allow-list are labelled with their id and, when there is no allow-list, so are the limit stores with the most
+// requests. The requests of the other stores are labelled 'other'.
+//
+// The stores with the most requests are estimated with a space-saving sketch: the requests of limit *
+// storeIDSketchFactor stores are counted and, when a store that is not counted is requested, it replaces the store
+// with the fewest requests, inheriting its count. The stores are kept sorted by count, so that a store is labelled
+// with its id while it is among the first limit ones.
+type storeIDLabeler struct {
+	allowList map[string]struct{}
+	limit     int
+
+	mu       sync.Mutex
+	counters []*storeIDCounter          // GUARDED_BY(mu), sorted by decreasing count
+	index    map[string]*storeIDCounter // GUARDED_BY(mu)
+	capacity int
+}
+
+// storeIDCounter counts the requests of a store in the sketch of a storeIDLabeler.
+type storeIDCounter struct {
+	storeID  string
+	count    uint64
+	position int
+}
+
+func newStoreIDLabeler(allowList []string, limit int) *storeIDLabeler {
+	l := &storeIDLabeler{
+		limit:    limit,
+		index:    make(map[string]*storeIDCounter),
+		capacity: limit * storeIDSketchFactor,
+	}
+
+	if len(allowList) > 0 {
+		l.allowList = make(map[string]struct{}, len(allowList))
+		for _, storeID := range allowList {
+			l.allowList[storeID] = struct{}{}
+		}
+	}
+
+	return l
+}
+
+// label counts a request of the store and returns its store id label. A nil labeler returns an empty label, i.e. no
+// store id label.
+func (l *storeIDLabeler) label(storeID string) string {
+	if l == nil {
+		return ""
+	}
+
+	if l.allowList != nil {
+		if _, ok := l.allowList[storeID]; ok {
+			return storeID
+		}
+		return otherStoresLabel
+	}
+
+	if l.limit <= 0 {
+		return otherStoresLabel
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.increment(storeID) < l.limit {
+		return storeID
+	}
+	return otherStoresLabel
+}
+
+// increment counts a request of the store and returns its position in the sketch. The caller must hold mu.
+func (l *storeIDLabeler) increment(storeID string) int {
+	counter, ok := l.index[storeID]
+	switch {
+	case ok:
+	case len(l.counters) < l.capacity:
+		counter = &storeIDCounter{storeID: storeID, position: len(l.counters)}
+		l.counters = append(l.counters, counter)
+		l.index[storeID] = counter
+	default:
+		// the store replaces the one with the fewest requests, inheriting its count
+		counter = l.counters[len(l.counters)-1]
+		delete(l.index, counter.storeID)
+		counter.storeID = storeID
+		l.index[storeID] = counter
+	}
+
+	// the counter moves before the ones it now outnumbers, i.e. the first one with its previous count
+	first := sort.Search(counter.position, func(i int) bool {
+		return l.counters[i].count <= counter.count
+	})
+	if first < counter.position {
+		other := l.counters[first]
+		l.counters[first], l.counters[counter.position] = counter, other
+		other.position, counter.position = counter.position, first
+	}
+	counter.count++
+
+	return counter.position
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreIDLabeler(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		var labeler *storeIDLabeler
+		require.Empty(t, labeler.label("01HSTORE1"))
+	})
+
+	t.Run("allow_list", func(t *testing.T) {
+		labeler := newStoreIDLabeler([]string{"01HSTORE1"}, 1)
+		require.Equal(t, "01HSTORE1", labeler.label("01HSTORE1"))
+		require.Equal(t, otherStoresLabel, labeler.label("01HSTORE2"))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		labeler := newStoreIDLabeler(nil, 2)
+		require.Equal(t, "01HSTORE1", labeler.label("01HSTORE1"))
+		require.Equal(t, "01HSTORE1", labeler.label("01HSTORE1"))
+		require.Equal(t, "01HSTORE2", labeler.label("01HSTORE2"))
+		require.Equal(t, otherStoresLabel, labeler.label("01HSTORE3"))
+
+		// the stores with the most requests are labelled
+		require.Equal(t, "01HSTORE3", labeler.label("01HSTORE3"))
+		require.Equal(t, otherStoresLabel, labeler.label("01HSTORE2"))
+		require.Equal(t, "01HSTORE1", labeler.label("01HSTORE1"))
+	})
+
+	t.Run("top_stores", func(t *testing.T) {
+		labeler := newStoreIDLabeler(nil, 2)
+
+		// more stores than the sketch counts are requested once, between the requests of the top stores
+		for i := 0; i < 10*storeIDSketchFactor; i++ {
+			labeler.label("01HSTORE1")
+			labeler.label("01HSTORE2")
+			labeler.label(fmt.Sprintf("01HOTHER%d", i))
+			labeler.label(fmt.Sprintf("01HOTHER%d", i))
+		}
+
+		require.Equal(t, "01HSTORE1", labeler.label("01HSTORE1"))
+		require.Equal(t, "01HSTORE2", labeler.label("01HSTORE2"))
+		require.Equal(t, otherStoresLabel, labeler.label("01HOTHER0"))
+		require.Len(t, labeler.counters, 2*storeIDSketchFactor)
+		for i := 1; i < len(labeler.counters); i++ {
+			require.GreaterOrEqual(t, labeler.counters[i-1].count, labeler.counters[i].count)
+			require.Equal(t, i, labeler.counters[i].position)
+		}
+	})
+}
@@ -2,24 +2,37 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
+	"github.com/oklog/ulid/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/logger"
 	httpmiddleware "github.com/openfga/openfga/pkg/middleware/http"
 	"github.com/openfga/openfga/pkg/middleware/validator"
 	"github.com/openfga/openfga/pkg/server/commands"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/telemetry"
 )
 
 func (s *Server) CreateStore(ctx context.Context, req *openfgav1.CreateStoreRequest) (*openfgav1.CreateStoreResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.CreateStore)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.CreateStore.String())
 	defer span.End()
 
@@ -39,7 +52,15 @@ func (s *Server) CreateStore(ctx context.Context, req *openfgav1.CreateStoreRequ
 		return nil, err
 	}
 
-	c := commands.NewCreateStoreCommand(s.datastore, commands.WithCreateStoreCmdLogger(s.logger))
+	labels, err := storeLabelsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := commands.NewCreateStoreCommand(s.datastore,
+		commands.WithCreateStoreCmdLogger(s.logger),
+		commands.WithCreateStoreCmdLabels(labels),
+	)
 	res, err := c.Execute(ctx, req)
 	if err != nil {
 		return nil, err
@@ -51,6 +72,9 @@ func (s *Server) CreateStore(ctx context.Context, req *openfgav1.CreateStoreRequ
 }
 
 func (s *Server) DeleteStore(ctx context.Context, req *openfgav1.DeleteStoreRequest) (*openfgav1.DeleteStoreResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.DeleteStore)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.DeleteStore.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 	))
@@ -72,18 +96,30 @@ func (s *Server) DeleteStore(ctx context.Context, req *openfgav1.DeleteStoreRequ
 		return nil, err
 	}
 
-	cmd := commands.NewDeleteStoreCommand(s.datastore, commands.WithDeleteStoreCmdLogger(s.logger))
+	cmd := commands.NewDeleteStoreCommand(
+		s.datastore,
+		commands.WithDeleteStoreCmdLogger(s.logger),
+		commands.WithDeleteStoreCmdProtectedTuples(s.datastore, s.protectedTuples),
+	)
 	res, err := cmd.Execute(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	s.logger.InfoWithContext(ctx, "store deleted",
+		logger.SecurityEvent(logger.SecurityEventStoreDeleted),
+		zap.String("store_id", req.GetStoreId()),
+	)
+
 	s.transport.SetHeader(ctx, httpmiddleware.XHttpCode, strconv.Itoa(http.StatusNoContent))
 
 	return res, nil
 }
 
 func (s *Server) GetStore(ctx context.Context, req *openfgav1.GetStoreRequest) (*openfgav1.GetStoreResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.GetStore)
+	defer cancel()
+
 	ctx, span := tracer.Start(ctx, apimethod.GetStore.String(), trace.WithAttributes(
 		attribute.String("store_id", req.GetStoreId()),
 	))
@@ -106,10 +142,90 @@ func (s *Server) GetStore(ctx context.Context, req *openfgav1.GetStoreRequest) (
 	}
 
 	q := commands.NewGetStoreQuery(s.datastore, commands.WithGetStoreQueryLogger(s.logger))
-	return q.Execute(ctx, req)
+	res, err := q.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setStoreLabelsHeader(ctx, res.GetId()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetStoreByName returns the store named name, among the stores the caller can access. It fails with a
+// FailedPrecondition error if more than one of them has this name. It is a library method, for the servers embedding
+// OpenFGA: it is not exposed by the gRPC and HTTP APIs.
+func (s *Server) GetStoreByName(ctx context.Context, name string) (*openfgav1.GetStoreResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.GetStore)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "GetStoreByName", trace.WithAttributes(
+		attribute.String("store_name", name),
+	))
+	defer span.End()
+
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "the store name cannot be empty")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.GetStore.String(),
+	})
+
+	storeIDs, err := s.getAccessibleStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := commands.NewGetStoreQuery(s.datastore, commands.WithGetStoreQueryLogger(s.logger))
+	res, err := q.ExecuteByName(ctx, name, storeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setStoreLabelsHeader(ctx, res.GetId()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateStoreLabels replaces the labels of a store. Empty labels remove them all. It is a library method, for the
+// servers embedding OpenFGA: it is not exposed by the gRPC and HTTP APIs.
+func (s *Server) UpdateStoreLabels(ctx context.Context, storeID string, labels map[string]string) error {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.UpdateStore)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, apimethod.UpdateStore.String(), trace.WithAttributes(
+		attribute.String("store_id", storeID),
+	))
+	defer span.End()
+
+	if _, err := ulid.ParseStrict(storeID); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid store id")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.UpdateStore.String(),
+	})
+
+	err := s.checkAuthz(ctx, storeID, apimethod.UpdateStore)
+	if err != nil {
+		return err
+	}
+
+	cmd := commands.NewUpdateStoreCommand(s.datastore, commands.WithUpdateStoreCmdLogger(s.logger))
+	return cmd.Execute(ctx, storeID, labels)
 }
 
 func (s *Server) ListStores(ctx context.Context, req *openfgav1.ListStoresRequest) (*openfgav1.ListStoresResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.ListStores)
+	defer cancel()
+
 	method := "ListStores"
 	ctx, span := tracer.Start(ctx, method)
 	defer span.End()
@@ -130,10 +246,68 @@ func (s *Server) ListStores(ctx context.Context, req *openfgav1.ListStoresReques
 		return nil, err
 	}
 
+	labels, err := storeLabelsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize, err := s.pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	if pageSize != req.GetPageSize() {
+		req = proto.Clone(req).(*openfgav1.ListStoresRequest)
+		req.PageSize = pageSize
+	}
+
 	// even though we have the list of store IDs, we need to call ListStoresQuery to fetch the entire metadata of the store.
 	q := commands.NewListStoresQuery(s.datastore,
 		commands.WithListStoresQueryLogger(s.logger),
 		commands.WithListStoresQueryEncoder(s.encoder),
+		commands.WithListStoresQueryLabels(labels),
 	)
 	return q.Execute(ctx, req, storeIDs)
 }
+
+// setStoreLabelsHeader sets the StoreLabelsResponseHeader to the labels of the store, if it has any.
+func (s *Server) setStoreLabelsHeader(ctx context.Context, storeID string) error {
+	labels, err := s.datastore.ReadStoreLabels(ctx, storeID)
+	if err != nil {
+		return serverErrors.HandleError("", err)
+	}
+	if len(labels) > 0 {
+		s.transport.SetHeader(ctx, StoreLabelsResponseHeader, formatStoreLabels(labels))
+	}
+	return nil
+}
+
+// storeLabelsFromContext parses the store labels of the StoreLabelsHeader request metadata.
+func storeLabelsFromContext(ctx context.Context) (map[string]string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	values := md.Get(StoreLabelsHeader)
+	if len(values) == 0 || values[0] == "" {
+		return nil, nil
+	}
+
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(values[0], ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || key == "" {
+			return nil, serverErrors.ValidationError(fmt.Errorf("invalid store label '%s', expected 'key=value'", pair))
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// formatStoreLabels formats labels as comma separated 'key=value', sorted by key.
+func formatStoreLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
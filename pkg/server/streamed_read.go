@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/middleware/validator"
+	"github.com/openfga/openfga/pkg/redact"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+const (
+	// StreamedReadServiceName is the name of the service serving the StreamedRead RPC.
+	StreamedReadServiceName = "openfga.v1.StreamedReadService"
+	// StreamedReadFullMethodName is the full name of the StreamedRead RPC.
+	StreamedReadFullMethodName = "/" + StreamedReadServiceName + "/StreamedRead"
+
+	// streamedReadProtoPath is the path of the file describing the service in the protobuf registry.
+	streamedReadProtoPath = "openfga/v1/streamed_read.proto"
+)
+
+// streamedReadFileDescriptor describes the service serving the StreamedRead RPC, as it would be declared by:
+//
+//	syntax = "proto3";
+//	package openfga.v1;
+//
+//	service StreamedReadService {
+//	  rpc StreamedRead(ReadRequest) returns (stream Tuple) {
+//	    option (google.api.http) = {
+//	      post: "/stores/{store_id}/streamed-read"
+//	      body: "*"
+//	    };
+//	  }
+//	}
+//
+// It is registered in the protobuf registry, so that the gRPC reflection service describes the service.
+func streamedReadFileDescriptor() *descriptorpb.FileDescriptorProto {
+	options := &descriptorpb.MethodOptions{}
+	proto.SetExtension(options, annotations.E_Http, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Post{Post: "/stores/{store_id}/streamed-read"},
+		Body:    "*",
+	})
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(streamedReadProtoPath),
+		Package: proto.String("openfga.v1"),
+		Syntax:  proto.String("proto3"),
+		Dependency: []string{
+			"google/api/annotations.proto",
+			openfgav1.File_openfga_v1_openfga_proto.Path(),
+			openfgav1.File_openfga_v1_openfga_service_proto.Path(),
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("StreamedReadService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:            proto.String(apimethod.StreamedRead.String()),
+				InputType:       proto.String(".openfga.v1.ReadRequest"),
+				OutputType:      proto.String(".openfga.v1.Tuple"),
+				ServerStreaming: proto.Bool(true),
+				Options:         options,
+			}},
+		}},
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/openfga/openfga/pkg/server"),
+		},
+	}
+}
+
+func init() {
+	file, err := protodesc.NewFile(streamedReadFileDescriptor(), protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(file); err != nil {
+		panic(err)
+	}
+}
+
+// StreamedReadServiceServer is the server API of the StreamedRead RPC.
+type StreamedReadServiceServer interface {
+	StreamedRead(*openfgav1.ReadRequest, StreamedReadServer) error
+}
+
+// StreamedReadServer is the server side stream of the StreamedRead RPC.
+type StreamedReadServer interface {
+	Send(*openfgav1.Tuple) error
+	grpc.ServerStream
+}
+
+type streamedReadServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamedReadServer) Send(m *openfgav1.Tuple) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func streamedReadHandler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(openfgav1.ReadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamedReadServiceServer).StreamedRead(m, &streamedReadServer{stream})
+}
+
+// StreamedReadServiceDesc is the grpc.ServiceDesc of the service serving the StreamedRead RPC. It takes a
+// ReadRequest and streams back the matching Tuples. It is served apart from the OpenFGAService, and exposed through
+// the HTTP gateway by RegisterStreamedReadServiceHandler.
+var StreamedReadServiceDesc = grpc.ServiceDesc{
+	ServiceName: StreamedReadServiceName,
+	HandlerType: (*StreamedReadServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    apimethod.StreamedRead.String(),
+			Handler:       streamedReadHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: streamedReadProtoPath,
+}
+
+// RegisterStreamedReadServiceServer registers the StreamedRead RPC of srv on s.
+func RegisterStreamedReadServiceServer(s grpc.ServiceRegistrar, srv StreamedReadServiceServer) {
+	s.RegisterService(&StreamedReadServiceDesc, srv)
+}
+
+// StreamedReadServiceClient is the client API of the StreamedRead RPC.
+type StreamedReadServiceClient interface {
+	StreamedRead(ctx context.Context, in *openfgav1.ReadRequest, opts ...grpc.CallOption) (StreamedReadClient, error)
+}
+
+// StreamedReadClient is the client side stream of the StreamedRead RPC.
+type StreamedReadClient interface {
+	Recv() (*openfgav1.Tuple, error)
+	grpc.ClientStream
+}
+
+type streamedReadServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewStreamedReadServiceClient returns a client of the StreamedRead RPC served on cc.
+func NewStreamedReadServiceClient(cc grpc.ClientConnInterface) StreamedReadServiceClient {
+	return &streamedReadServiceClient{cc: cc}
+}
+
+func (c *streamedReadServiceClient) StreamedRead(ctx context.Context, in *openfgav1.ReadRequest, opts ...grpc.CallOption) (StreamedReadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamedReadServiceDesc.Streams[0], StreamedReadFullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamedReadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type streamedReadClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamedReadClient) Recv() (*openfgav1.Tuple, error) {
+	m := new(openfgav1.Tuple)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamedRead streams the tuples matching the filters of a Read request directly from the datastore iterator,
+// without building pages. The page size and continuation token of the request are ignored.
+func (s *Server) StreamedRead(req *openfgav1.ReadRequest, srv StreamedReadServer) error {
+	ctx, cancel := s.withMethodTimeout(srv.Context(), apimethod.StreamedRead)
+	defer cancel()
+
+	tk := req.GetTupleKey()
+	ctx, span := tracer.Start(ctx, apimethod.StreamedRead.String(), trace.WithAttributes(
+		attribute.String("store_id", req.GetStoreId()),
+		attribute.KeyValue{Key: "object", Value: attribute.StringValue(redact.Object(tk.GetObject()))},
+		attribute.KeyValue{Key: "relation", Value: attribute.StringValue(tk.GetRelation())},
+		attribute.KeyValue{Key: "user", Value: attribute.StringValue(redact.User(tk.GetUser()))},
+		attribute.KeyValue{Key: "consistency", Value: attribute.StringValue(req.GetConsistency().String())},
+	))
+	defer span.End()
+
+	if !validator.RequestIsValidatedFromContext(ctx) {
+		if err := req.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.StreamedRead.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.GetStoreId(), apimethod.StreamedRead)
+	if err != nil {
+		return err
+	}
+
+	q := commands.NewReadQuery(s.datastore, append([]commands.ReadQueryOption{
+		commands.WithReadQueryLogger(s.logger),
+	}, readFilterOptions(ctx)...)...)
+	return q.ExecuteStreamed(ctx, req, srv)
+}
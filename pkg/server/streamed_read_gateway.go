@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+// streamedReadPattern is the HTTP route of the StreamedRead RPC, POST /stores/{store_id}/streamed-read.
+var streamedReadPattern = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 1, 0, 4, 1, 5, 1, 2, 2}, []string{"stores", "store_id", "streamed-read"}, ""))
+
+// RegisterStreamedReadServiceHandler registers the HTTP route of the StreamedRead RPC on mux, forwarding the
+// requests to the gRPC server on conn. The tuples are streamed back as the newline delimited 'result' of JSON
+// objects, as the routes of the other streaming RPCs are.
+func RegisterStreamedReadServiceHandler(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterStreamedReadServiceHandlerClient(ctx, mux, NewStreamedReadServiceClient(conn))
+}
+
+// RegisterStreamedReadServiceHandlerClient registers the HTTP route of the StreamedRead RPC on mux, forwarding the
+// requests to client.
+func RegisterStreamedReadServiceHandlerClient(_ context.Context, mux *runtime.ServeMux, client StreamedReadServiceClient) error {
+	mux.Handle(http.MethodPost, streamedReadPattern, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, StreamedReadFullMethodName, runtime.WithHTTPPathPattern("/stores/{store_id}/streamed-read"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := requestStreamedRead(annotatedContext, inboundMarshaler, client, req, pathParams)
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+
+		runtime.ForwardResponseStream(annotatedContext, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+	})
+	return nil
+}
+
+// requestStreamedRead decodes the ReadRequest of the body and path of req, and starts the StreamedRead RPC.
+func requestStreamedRead(ctx context.Context, marshaler runtime.Marshaler, client StreamedReadServiceClient, req *http.Request, pathParams map[string]string) (StreamedReadClient, runtime.ServerMetadata, error) {
+	var protoReq openfgav1.ReadRequest
+	var metadata runtime.ServerMetadata
+
+	if err := marshaler.NewDecoder(req.Body).Decode(&protoReq); err != nil && err != io.EOF {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	storeID, ok := pathParams["store_id"]
+	if !ok {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "store_id")
+	}
+	protoReq.StoreId = storeID
+
+	stream, err := client.StreamedRead(ctx, &protoReq)
+	if err != nil {
+		return nil, metadata, err
+	}
+	header, err := stream.Header()
+	if err != nil {
+		return nil, metadata, err
+	}
+	metadata.HeaderMD = header
+	return stream, metadata, nil
+}
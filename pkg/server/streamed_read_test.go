@@ -0,0 +1,34 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+func TestStreamedReadServiceDescriptor(t *testing.T) {
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(StreamedReadServiceName)
+	require.NoError(t, err)
+
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	require.True(t, ok)
+	require.Equal(t, StreamedReadServiceDesc.Metadata, service.ParentFile().Path())
+
+	method := service.Methods().ByName("StreamedRead")
+	require.NotNil(t, method)
+	require.True(t, method.IsStreamingServer())
+	require.False(t, method.IsStreamingClient())
+	require.Equal(t, (&openfgav1.ReadRequest{}).ProtoReflect().Descriptor().FullName(), method.Input().FullName())
+	require.Equal(t, (&openfgav1.Tuple{}).ProtoReflect().Descriptor().FullName(), method.Output().FullName())
+
+	rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+	require.True(t, ok)
+	require.Equal(t, "/stores/{store_id}/streamed-read", rule.GetPost())
+	require.Equal(t, "*", rule.GetBody())
+}
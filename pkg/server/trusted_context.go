@@ -0,0 +1,52 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+// hasTrustedContext reports whether the server sets trusted parameters in the context of the requests.
+func (s *Server) hasTrustedContext() bool {
+	return s.trustedCurrentTimeParameter != "" || s.trustedCallerParameter != ""
+}
+
+// withTrustedContext returns a copy of reqCtx holding the trusted parameters of the server, see
+// WithTrustedContextParameters. It returns reqCtx itself if the server has no trusted parameters.
+func (s *Server) withTrustedContext(ctx context.Context, reqCtx *structpb.Struct) *structpb.Struct {
+	if !s.hasTrustedContext() {
+		return reqCtx
+	}
+
+	trusted := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	if reqCtx != nil {
+		trusted = proto.Clone(reqCtx).(*structpb.Struct)
+		if trusted.Fields == nil {
+			trusted.Fields = map[string]*structpb.Value{}
+		}
+	}
+
+	if s.trustedCurrentTimeParameter != "" {
+		now := s.clock.Now().UTC()
+		if s.trustedCurrentTimePrecision > 0 {
+			now = now.Truncate(s.trustedCurrentTimePrecision)
+		}
+		trusted.Fields[s.trustedCurrentTimeParameter] = structpb.NewStringValue(now.Format(time.RFC3339Nano))
+	}
+
+	if s.trustedCallerParameter != "" {
+		delete(trusted.Fields, s.trustedCallerParameter)
+		if claims, ok := authclaims.AuthClaimsFromContext(ctx); ok {
+			if caller := cmp.Or(claims.Subject, claims.ClientID); caller != "" {
+				trusted.Fields[s.trustedCallerParameter] = structpb.NewStringValue(caller)
+			}
+		}
+	}
+
+	return trusted
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+
+	serverconfig "github.com/openfga/openfga/pkg/server/config"
+)
+
+// TunableSettings are the settings of the server that can be changed while it runs, see UpdateTunableSettings. They
+// are read by each request, the requests in flight keep the settings they started with.
+type TunableSettings struct {
+	ListObjectsDeadline              time.Duration
+	ListObjectsMaxResults            uint32
+	ListUsersDeadline                time.Duration
+	ListUsersMaxResults              uint32
+	MaxChecksPerBatchCheck           uint32
+	MaxConcurrentReadsForCheck       uint32
+	MaxConcurrentReadsForListObjects uint32
+	MaxConcurrentReadsForListUsers   uint32
+
+	// The TTLs of the caches apply to the entries cached from then on.
+	CheckQueryCacheTTL          time.Duration
+	CheckIteratorCacheTTL       time.Duration
+	CheckTupleCacheTTL          time.Duration
+	ListObjectsIteratorCacheTTL time.Duration
+}
+
+// TunableSettings returns the current tunable settings of the server.
+func (s *Server) TunableSettings() TunableSettings {
+	if settings := s.tunableSettings.Load(); settings != nil {
+		return *settings
+	}
+	return TunableSettings{
+		ListObjectsDeadline:              s.listObjectsDeadline,
+		ListObjectsMaxResults:            s.listObjectsMaxResults,
+		ListUsersDeadline:                s.listUsersDeadline,
+		ListUsersMaxResults:              s.listUsersMaxResults,
+		MaxChecksPerBatchCheck:           s.maxChecksPerBatchCheck,
+		MaxConcurrentReadsForCheck:       s.maxConcurrentReadsForCheck,
+		MaxConcurrentReadsForListObjects: s.maxConcurrentReadsForListObjects,
+		MaxConcurrentReadsForListUsers:   s.maxConcurrentReadsForListUsers,
+		CheckQueryCacheTTL:               s.cacheSettings.CheckQueryCacheTTL,
+		CheckIteratorCacheTTL:            s.cacheSettings.CheckIteratorCacheTTL,
+		CheckTupleCacheTTL:               s.cacheSettings.CheckTupleCacheTTL,
+		ListObjectsIteratorCacheTTL:      s.cacheSettings.ListObjectsIteratorCacheTTL,
+	}
+}
+
+// requestCacheSettings returns the cache settings of a request, with the current TTLs of the caches.
+func (s *Server) requestCacheSettings() serverconfig.CacheSettings {
+	settings := s.TunableSettings()
+	cacheSettings := s.cacheSettings
+	cacheSettings.CheckQueryCacheTTL = settings.CheckQueryCacheTTL
+	cacheSettings.CheckIteratorCacheTTL = settings.CheckIteratorCacheTTL
+	cacheSettings.CheckTupleCacheTTL = settings.CheckTupleCacheTTL
+	cacheSettings.ListObjectsIteratorCacheTTL = settings.ListObjectsIteratorCacheTTL
+	return cacheSettings
+}
+
+// UpdateTunableSettings replaces the tunable settings of the server, for the requests received from then on, and
+// logs each setting that changes.
+func (s *Server) UpdateTunableSettings(settings TunableSettings) error {
+	if settings.MaxConcurrentReadsForCheck == 0 || settings.MaxConcurrentReadsForListObjects == 0 ||
+		settings.MaxConcurrentReadsForListUsers == 0 {
+		return errors.New("the max concurrent reads cannot be 0")
+	}
+	if settings.ListObjectsDeadline < 0 || settings.ListUsersDeadline < 0 {
+		return errors.New("the deadlines must be non-negative")
+	}
+	if (s.cacheSettings.ShouldCacheCheckQueries() && settings.CheckQueryCacheTTL <= 0) ||
+		(s.cacheSettings.ShouldCacheCheckIterators() && settings.CheckIteratorCacheTTL <= 0) ||
+		(s.cacheSettings.ShouldCacheCheckTuples() && settings.CheckTupleCacheTTL <= 0) ||
+		(s.cacheSettings.ShouldCacheListObjectsIterators() && settings.ListObjectsIteratorCacheTTL <= 0) {
+		return errors.New("the TTLs of the enabled caches must be positive")
+	}
+	if window := s.cacheSettings.CheckQueryCacheRevalidationWindow; s.cacheSettings.CheckQueryCacheEnabled && window > 0 &&
+		window >= settings.CheckQueryCacheTTL {
+		return errors.New("check query cache revalidation window must be smaller than the check query cache TTL")
+	}
+
+	previous := s.TunableSettings()
+	s.tunableSettings.Store(&settings)
+
+	before, after := reflect.ValueOf(previous), reflect.ValueOf(settings)
+	for i := 0; i < before.NumField(); i++ {
+		if before.Field(i).Interface() != after.Field(i).Interface() {
+			s.logger.Info("server setting changed",
+				zap.String("setting", before.Type().Field(i).Name),
+				zap.String("previous", fmt.Sprint(before.Field(i).Interface())),
+				zap.String("current", fmt.Sprint(after.Field(i).Interface())))
+		}
+	}
+	return nil
+}
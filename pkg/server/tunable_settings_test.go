@@ -0,0 +1,61 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/logger"
+	serverconfig "github.com/openfga/openfga/pkg/server/config"
+)
+
+func TestUpdateTunableSettings(t *testing.T) {
+	s := &Server{
+		logger:                           logger.NewNoopLogger(),
+		listObjectsDeadline:              time.Second,
+		listObjectsMaxResults:            100,
+		maxConcurrentReadsForCheck:       10,
+		maxConcurrentReadsForListObjects: 10,
+		maxConcurrentReadsForListUsers:   10,
+		cacheSettings:                    serverconfig.NewDefaultCacheSettings(),
+	}
+	s.cacheSettings.CheckQueryCacheEnabled = true
+	s.cacheSettings.CheckQueryCacheRevalidationWindow = 2 * time.Second
+
+	settings := s.TunableSettings()
+	require.Equal(t, time.Second, settings.ListObjectsDeadline)
+	require.EqualValues(t, 100, settings.ListObjectsMaxResults)
+
+	settings.ListObjectsDeadline = 5 * time.Second
+	settings.MaxConcurrentReadsForCheck = 20
+	require.NoError(t, s.UpdateTunableSettings(settings))
+	require.Equal(t, settings, s.TunableSettings())
+
+	invalid := settings
+	invalid.MaxConcurrentReadsForListUsers = 0
+	require.Error(t, s.UpdateTunableSettings(invalid))
+
+	invalid = settings
+	invalid.ListUsersDeadline = -time.Second
+	require.Error(t, s.UpdateTunableSettings(invalid))
+	require.Equal(t, settings, s.TunableSettings())
+
+	t.Run("cache_ttls", func(t *testing.T) {
+		settings := s.TunableSettings()
+		settings.CheckQueryCacheTTL = time.Minute
+		settings.CheckTupleCacheTTL = 30 * time.Second
+		require.NoError(t, s.UpdateTunableSettings(settings))
+		require.Equal(t, time.Minute, s.requestCacheSettings().CheckQueryCacheTTL)
+		require.Equal(t, 30*time.Second, s.requestCacheSettings().CheckTupleCacheTTL)
+
+		invalid := settings
+		invalid.CheckQueryCacheTTL = 0
+		require.Error(t, s.UpdateTunableSettings(invalid))
+
+		// the check query cache TTL cannot be shorter than its revalidation window
+		invalid.CheckQueryCacheTTL = time.Second
+		require.Error(t, s.UpdateTunableSettings(invalid))
+		require.Equal(t, settings, s.TunableSettings())
+	})
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/internal/cachecontroller"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// tupleChangeSubscriberRetryInterval is how long the subscriber waits before listening again once the
+// notifications were interrupted.
+const tupleChangeSubscriberRetryInterval = 5 * time.Second
+
+// tupleChangeSubscriber invalidates the caches of the stores whose tuples are written by any server, as notified
+// by the datastore.
+type tupleChangeSubscriber struct {
+	listener        storage.TupleChangeListener
+	cacheController cachecontroller.CacheController
+	logger          logger.Logger
+
+	// interrupted holds the error which interrupted the notifications, until they resume.
+	interrupted atomic.Pointer[error]
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func newTupleChangeSubscriber(listener storage.TupleChangeListener, cacheController cachecontroller.CacheController, logger logger.Logger) *tupleChangeSubscriber {
+	s := &tupleChangeSubscriber{
+		listener:        listener,
+		cacheController: cacheController,
+		logger:          logger,
+		stop:            make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.run()
+
+	return s
+}
+
+// Close stops listening to the notifications.
+func (s *tupleChangeSubscriber) Close() {
+	s.once.Do(func() { close(s.stop) })
+	s.wg.Wait()
+}
+
+func (s *tupleChangeSubscriber) run() {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+
+	for {
+		// the notifications are considered resumed as soon as listening again, as it fails fast if the datastore
+		// cannot be reached
+		s.interrupted.Store(nil)
+		err := s.listener.ListenTupleChanges(ctx, func(notification storage.TupleChangeNotification) {
+			if notification.ObjectType == "" {
+				s.cacheController.InvalidateStore(notification.StoreID)
+				return
+			}
+			s.cacheController.InvalidateObjectType(notification.StoreID, notification.ObjectType)
+		})
+		if err != nil {
+			s.interrupted.Store(&err)
+			s.logger.Warn("tuple change notifications interrupted, the caches are invalidated on expiry until they resume", zap.Error(err))
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(tupleChangeSubscriberRetryInterval):
+		}
+	}
+}
+
+// Err returns the error which interrupted the notifications, or nil while they are listened to.
+func (s *tupleChangeSubscriber) Err() error {
+	if err := s.interrupted.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/server/health"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+const (
+	// typesystemWarmupTimeout bounds the resolution of the latest model of every store warmed up.
+	typesystemWarmupTimeout = 10 * time.Second
+	// typesystemWarmupMRUStores is the number of most recently used stores persisted to be warmed up on the next
+	// startup.
+	typesystemWarmupMRUStores = 100
+	// typesystemWarmupPersistInterval is how often the most recently used stores are persisted, if they changed.
+	typesystemWarmupPersistInterval = time.Minute
+)
+
+// typesystemWarmer caches the latest model of some stores on startup, so that the first requests to them don't wait
+// for their model to be read and validated. The stores are those configured, followed by the most recently used
+// ones persisted to a file by the previous run, if any.
+type typesystemWarmer struct {
+	resolver typesystem.TypesystemResolverFunc
+	logger   logger.Logger
+	stores   []string
+	mruFile  string
+
+	warmed atomic.Bool
+
+	mu       sync.Mutex
+	lastUsed map[string]time.Time // GUARDED_BY(mu).
+	changed  bool                 // GUARDED_BY(mu).
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func newTypesystemWarmer(resolver typesystem.TypesystemResolverFunc, logger logger.Logger, stores []string, mruFile string) *typesystemWarmer {
+	w := &typesystemWarmer{
+		resolver: resolver,
+		logger:   logger,
+		stores:   stores,
+		mruFile:  mruFile,
+		lastUsed: make(map[string]time.Time),
+		stop:     make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w
+}
+
+// Close stops the warmer, interrupting the warmup if it is still running, and persists the most recently used stores.
+func (w *typesystemWarmer) Close() {
+	w.once.Do(func() { close(w.stop) })
+	w.wg.Wait()
+	w.persist()
+}
+
+// Touch records that storeID was just used.
+func (w *typesystemWarmer) Touch(storeID string) {
+	if w.mruFile == "" {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastUsed[storeID] = time.Now()
+	w.changed = true
+	// The least recently used stores are trimmed once there are twice as many as persisted.
+	if len(w.lastUsed) > 2*typesystemWarmupMRUStores {
+		for _, store := range w.mostRecentlyUsed()[typesystemWarmupMRUStores:] {
+			delete(w.lastUsed, store)
+		}
+	}
+}
+
+// Readiness reports the warmup as not ready until the models of every store were resolved, or failed to.
+func (w *typesystemWarmer) Readiness() health.ComponentStatus {
+	if !w.warmed.Load() {
+		return health.ComponentStatus{Name: "typesystem_cache", Ready: false, Message: "the authorization models are being warmed up"}
+	}
+	return health.ComponentStatus{Name: "typesystem_cache", Ready: true}
+}
+
+func (w *typesystemWarmer) run() {
+	defer w.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-w.stop
+		cancel()
+	}()
+
+	w.warmup(ctx)
+	w.warmed.Store(true)
+
+	if w.mruFile == "" {
+		return
+	}
+
+	ticker := time.NewTicker(typesystemWarmupPersistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.persist()
+		}
+	}
+}
+
+// warmup resolves the latest model of the configured and of the persisted most recently used stores.
+func (w *typesystemWarmer) warmup(ctx context.Context) {
+	stores := slices.Clone(w.stores)
+	for _, store := range w.readMRUFile() {
+		if !slices.Contains(stores, store) {
+			stores = append(stores, store)
+		}
+	}
+
+	warmed := 0
+	for _, store := range stores {
+		if ctx.Err() != nil {
+			return
+		}
+
+		resolveCtx, cancel := context.WithTimeout(ctx, typesystemWarmupTimeout)
+		_, err := w.resolver(resolveCtx, store, "")
+		cancel()
+		if err != nil {
+			w.logger.Warn("failed to warm up the authorization model of a store", zap.String("store_id", store), zap.Error(err))
+			continue
+		}
+		warmed++
+	}
+
+	if len(stores) > 0 {
+		w.logger.Info("warmed up the authorization models", zap.Int("stores", warmed), zap.Int("failed", len(stores)-warmed))
+	}
+}
+
+func (w *typesystemWarmer) readMRUFile() []string {
+	if w.mruFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(w.mruFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			w.logger.Warn("failed to read the most recently used stores", zap.String("file", w.mruFile), zap.Error(err))
+		}
+		return nil
+	}
+
+	var stores []string
+	if err := json.Unmarshal(data, &stores); err != nil {
+		w.logger.Warn("failed to decode the most recently used stores", zap.String("file", w.mruFile), zap.Error(err))
+		return nil
+	}
+	return stores
+}
+
+// persist writes the most recently used stores to the file, if they changed since they were last persisted.
+func (w *typesystemWarmer) persist() {
+	if w.mruFile == "" {
+		return
+	}
+
+	w.mu.Lock()
+	if !w.changed {
+		w.mu.Unlock()
+		return
+	}
+	stores := w.mostRecentlyUsed()
+	w.changed = false
+	w.mu.Unlock()
+
+	if len(stores) > typesystemWarmupMRUStores {
+		stores = stores[:typesystemWarmupMRUStores]
+	}
+
+	if err := writeFileAtomically(w.mruFile, stores); err != nil {
+		w.logger.Warn("failed to persist the most recently used stores", zap.String("file", w.mruFile), zap.Error(err))
+	}
+}
+
+// mostRecentlyUsed returns the stores used, from the most to the least recently used. It must be called with mu held.
+func (w *typesystemWarmer) mostRecentlyUsed() []string {
+	return slices.SortedFunc(maps.Keys(w.lastUsed), func(a, b string) int {
+		return cmp.Compare(w.lastUsed[b].UnixNano(), w.lastUsed[a].UnixNano())
+	})
+}
+
+// writeFileAtomically writes stores as JSON to a temporary file renamed to file, so that file is never left partially
+// written.
+func writeFileAtomically(file string, stores []string) error {
+	data, err := json.Marshal(stores)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
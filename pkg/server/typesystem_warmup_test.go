@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestTypesystemWarmer(t *testing.T) {
+	var mu sync.Mutex
+	var resolved []string
+	resolver := func(ctx context.Context, storeID, modelID string) (*typesystem.TypeSystem, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = append(resolved, storeID)
+		return nil, nil
+	}
+
+	mruFile := filepath.Join(t.TempDir(), "mru.json")
+	data, err := json.Marshal([]string{"store-2", "store-3"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(mruFile, data, 0o600))
+
+	w := newTypesystemWarmer(resolver, logger.NewNoopLogger(), []string{"store-1", "store-2"}, mruFile)
+	require.Eventually(t, func() bool { return w.Readiness().Ready }, 5*time.Second, time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, []string{"store-1", "store-2", "store-3"}, resolved)
+	mu.Unlock()
+
+	w.Touch("store-4")
+	w.Touch("store-5")
+	w.Close()
+
+	data, err = os.ReadFile(mruFile)
+	require.NoError(t, err)
+	var persisted []string
+	require.NoError(t, json.Unmarshal(data, &persisted))
+	require.Equal(t, []string{"store-5", "store-4"}, persisted)
+}
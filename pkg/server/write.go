@@ -20,6 +20,9 @@ import (
 )
 
 func (s *Server) Write(ctx context.Context, req *openfgav1.WriteRequest) (*openfgav1.WriteResponse, error) {
+	ctx, cancel := s.withMethodTimeout(ctx, apimethod.Write)
+	defer cancel()
+
 	start := time.Now()
 
 	ctx, span := tracer.Start(ctx, apimethod.Write.String(), trace.WithAttributes(
@@ -53,6 +56,9 @@ func (s *Server) Write(ctx context.Context, req *openfgav1.WriteRequest) (*openf
 	cmd := commands.NewWriteCommand(
 		s.datastore,
 		commands.WithWriteCmdLogger(s.logger),
+		commands.WithProtectedTuples(s.protectedTuples),
+		commands.WithWriteCmdMaxTuplesPerWrite(s.maxTuplesPerWrite),
+		commands.WithWriteCmdTypesystemResolver(s.typesystemResolver),
 	)
 	resp, err := cmd.Execute(ctx, &openfgav1.WriteRequest{
 		StoreId:              storeID,
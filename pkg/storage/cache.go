@@ -8,7 +8,9 @@ import (
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Yiling-J/theine-go"
@@ -54,6 +56,13 @@ type CacheItem interface {
 	CacheEntityType() string
 }
 
+// RemovalObservedCacheItem is a [CacheItem] notified when an [InMemoryLRUCache] removes it, with the reason of the
+// removal: "evicted", "expired" or "removed".
+type RemovalObservedCacheItem interface {
+	CacheItem
+	CacheItemRemoved(reason string)
+}
+
 // InMemoryCache is a general purpose cache to store things in memory.
 type InMemoryCache[T any] interface {
 	// Get If the key exists, returns the value. If the key didn't exist, returns nil.
@@ -69,9 +78,10 @@ type InMemoryCache[T any] interface {
 // Specific implementation
 
 type InMemoryLRUCache[T any] struct {
-	client      *theine.Cache[string, T]
-	maxElements int64
-	stopOnce    *sync.Once
+	client          *theine.Cache[string, T]
+	maxElements     int64
+	stopOnce        *sync.Once
+	removalListener func(key string, value T)
 }
 
 type InMemoryLRUCacheOpt[T any] func(i *InMemoryLRUCache[T])
@@ -82,6 +92,14 @@ func WithMaxCacheSize[T any](maxElements int64) InMemoryLRUCacheOpt[T] {
 	}
 }
 
+// WithRemovalListener sets a function called with every item removed from the cache, whether it was evicted,
+// expired or deleted.
+func WithRemovalListener[T any](fn func(key string, value T)) InMemoryLRUCacheOpt[T] {
+	return func(i *InMemoryLRUCache[T]) {
+		i.removalListener = fn
+	}
+}
+
 var _ InMemoryCache[any] = (*InMemoryLRUCache[any])(nil)
 
 func NewInMemoryLRUCache[T any](opts ...InMemoryLRUCacheOpt[T]) (*InMemoryLRUCache[T], error) {
@@ -119,6 +137,14 @@ func NewInMemoryLRUCache[T any](opts ...InMemoryLRUCacheOpt[T]) (*InMemoryLRUCac
 
 		cacheItemCount.WithLabelValues(entityLabel).Dec()
 		cacheItemRemovedCount.WithLabelValues(entityLabel, reasonLabel).Inc()
+
+		if item, ok := any(value).(RemovalObservedCacheItem); ok {
+			item.CacheItemRemoved(reasonLabel)
+		}
+
+		if t.removalListener != nil {
+			t.removalListener(key, value)
+		}
 	})
 
 	var err error
@@ -160,12 +186,75 @@ func (i InMemoryLRUCache[T]) Delete(key string) {
 	i.client.Delete(key)
 }
 
+// DeletePrefix removes the entries whose key starts with prefix, notifying their removal.
+func (i InMemoryLRUCache[T]) DeletePrefix(prefix string) {
+	// the keys are deleted once ranged over, as the shards are locked while ranging
+	var keys []string
+	i.client.Range(func(key string, _ T) bool {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	for _, key := range keys {
+		i.client.Delete(key)
+	}
+}
+
 func (i InMemoryLRUCache[T]) Stop() {
 	i.stopOnce.Do(func() {
 		i.client.Close()
 	})
 }
 
+// FlushableCache is an [InMemoryCache] which can be flushed, e.g. by an operator. Its keys are prefixed with a
+// generation, which Flush increments: the previous entries are no longer found, and are then removed, so that the
+// metrics of the removed entries are updated, if the underlying cache can delete keys by prefix. Otherwise, they are
+// removed as they expire or get evicted.
+type FlushableCache[T any] struct {
+	cache      InMemoryCache[T]
+	generation atomic.Uint64
+}
+
+var _ InMemoryCache[any] = (*FlushableCache[any])(nil)
+
+// NewFlushableCache returns a flushable cache storing its entries in cache.
+func NewFlushableCache[T any](cache InMemoryCache[T]) *FlushableCache[T] {
+	return &FlushableCache[T]{cache: cache}
+}
+
+func (f *FlushableCache[T]) key(key string) string {
+	return generationPrefix(f.generation.Load()) + key
+}
+
+func generationPrefix(generation uint64) string {
+	return strconv.FormatUint(generation, 10) + "/"
+}
+
+func (f *FlushableCache[T]) Get(key string) T {
+	return f.cache.Get(f.key(key))
+}
+
+func (f *FlushableCache[T]) Set(key string, value T, ttl time.Duration) {
+	f.cache.Set(f.key(key), value, ttl)
+}
+
+func (f *FlushableCache[T]) Delete(key string) {
+	f.cache.Delete(f.key(key))
+}
+
+// Flush discards all the entries of the cache.
+func (f *FlushableCache[T]) Flush() {
+	previous := f.generation.Add(1) - 1
+	if cache, ok := f.cache.(interface{ DeletePrefix(prefix string) }); ok {
+		cache.DeletePrefix(generationPrefix(previous))
+	}
+}
+
+func (f *FlushableCache[T]) Stop() {
+	f.cache.Stop()
+}
+
 var (
 	_ CacheItem = (*ChangelogCacheEntry)(nil)
 	_ CacheItem = (*InvalidEntityCacheEntry)(nil)
@@ -200,6 +289,10 @@ func GetInvalidIteratorByObjectRelationCacheKey(storeID, object, relation string
 	return invalidIteratorCachePrefix + storeID + "-or/" + object + "#" + relation
 }
 
+func GetInvalidIteratorByObjectTypeCacheKey(storeID, objectType string) string {
+	return invalidIteratorCachePrefix + storeID + "-ot/" + objectType
+}
+
 func GetInvalidIteratorByUserObjectTypeCacheKeys(storeID string, users []string, objectType string) []string {
 	res := make([]string, len(users))
 	var i int
@@ -231,6 +324,10 @@ func GetReadCacheKey(store, tuple string) string {
 	return iteratorCachePrefix + "r/" + store + "/" + tuple
 }
 
+func GetReadUserTupleCacheKey(store, tuple string) string {
+	return iteratorCachePrefix + "ut/" + store + "/" + tuple
+}
+
 // ErrUnexpectedStructValue is an error used to indicate that
 // an unexpected structpb.Value kind was encountered.
 var ErrUnexpectedStructValue = errors.New("unexpected structpb value encountered")
@@ -7,6 +7,8 @@ import (
 	"io"
 	"math"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -421,6 +423,25 @@ func TestInMemoryCache(t *testing.T) {
 		require.NotEqual(t, "value", result)
 	})
 
+	t.Run("removal_listener", func(t *testing.T) {
+		var removed sync.Map
+		cache, err := NewInMemoryLRUCache[string](WithRemovalListener(func(key string, value string) {
+			removed.Store(key, value)
+		}))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			goleak.VerifyNone(t)
+		})
+		defer cache.Stop()
+
+		cache.Set("key", "value", 1*time.Second)
+		cache.Delete("key")
+		require.Eventually(t, func() bool {
+			value, ok := removed.Load("key")
+			return ok && value == "value"
+		}, time.Second, 10*time.Millisecond)
+	})
+
 	t.Run("stop_multiple_times", func(t *testing.T) {
 		cache, err := NewInMemoryLRUCache[string]()
 		require.NoError(t, err)
@@ -453,6 +474,43 @@ func TestInMemoryCache(t *testing.T) {
 	})
 }
 
+func TestFlushableCache(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	lru, err := NewInMemoryLRUCache[string]()
+	require.NoError(t, err)
+	cache := NewFlushableCache[string](lru)
+	defer cache.Stop()
+
+	cache.Set("key", "value", time.Minute)
+	require.Equal(t, "value", cache.Get("key"))
+
+	cache.Flush()
+	require.Empty(t, cache.Get("key"))
+
+	cache.Set("key", "other", time.Minute)
+	require.Equal(t, "other", cache.Get("key"))
+	cache.Delete("key")
+	require.Empty(t, cache.Get("key"))
+
+	t.Run("flushed_entries_are_removed", func(t *testing.T) {
+		var removed atomic.Int32
+		lru, err := NewInMemoryLRUCache[string](WithRemovalListener(func(string, string) { removed.Add(1) }))
+		require.NoError(t, err)
+		cache := NewFlushableCache[string](lru)
+		defer cache.Stop()
+
+		cache.Set("key1", "value", time.Minute)
+		cache.Set("key2", "value", time.Minute)
+		cache.Flush()
+		cache.Set("key1", "other", time.Minute)
+
+		require.Eventually(t, func() bool { return removed.Load() == 2 }, time.Second, 10*time.Millisecond)
+		require.Equal(t, "other", cache.Get("key1"))
+	})
+}
+
 func MustGetCheckCacheKey(params *CheckCacheKeyParams) string {
 	w := &strings.Builder{}
 
@@ -0,0 +1,72 @@
+// Package engine provides a registry of datastore engines, so that the server can run against datastores
+// implemented outside of this module. An engine registered under a name is used when the 'datastore-engine'
+// configuration option is set to that name.
+//
+// Implementations should be validated with the conformance tests of [github.com/openfga/openfga/pkg/storage/test].
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/openfga/openfga/pkg/encoder"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/sqlcommon"
+)
+
+// BuiltinEngines are the names of the datastore engines of this module, which cannot be registered.
+var BuiltinEngines = []string{"memory", "postgres", "mysql", "sqlite"}
+
+// Engine defines how to create the datastore of an engine.
+type Engine struct {
+	// New returns a datastore connected to uri. cfg holds the credentials, connection pool and limits configured for
+	// the server.
+	New func(uri string, cfg *sqlcommon.Config) (storage.OpenFGADatastore, error)
+
+	// ContinuationTokenSerializer serializes the continuation tokens of ReadChanges. If nil, the serializer of the
+	// SQL engines is used.
+	ContinuationTokenSerializer encoder.ContinuationTokenSerializer
+}
+
+var (
+	mu      sync.RWMutex
+	engines = map[string]Engine{}
+)
+
+// Register makes an engine available under name. It is meant to be called from the init function of the package
+// implementing the engine, and panics if name is empty or already used, or if the engine cannot create a datastore.
+func Register(name string, engine Engine) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name == "" || engine.New == nil {
+		panic("engine: Register requires a name and a New function")
+	}
+	if _, ok := engines[name]; ok || slices.Contains(BuiltinEngines, name) {
+		panic(fmt.Sprintf("engine: Register called twice for engine '%s'", name))
+	}
+	engines[name] = engine
+}
+
+// Lookup returns the engine registered under name.
+func Lookup(name string) (Engine, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	engine, ok := engines[name]
+	return engine, ok
+}
+
+// Names returns the sorted names of the registered engines.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
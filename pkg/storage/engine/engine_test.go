@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/storage/sqlcommon"
+)
+
+func TestRegister(t *testing.T) {
+	newMemory := func(string, *sqlcommon.Config) (storage.OpenFGADatastore, error) {
+		return memory.New(), nil
+	}
+
+	Register("test-engine", Engine{New: newMemory})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(engines, "test-engine")
+	})
+
+	registered, ok := Lookup("test-engine")
+	require.True(t, ok)
+	ds, err := registered.New("", sqlcommon.NewConfig())
+	require.NoError(t, err)
+	t.Cleanup(ds.Close)
+	require.Contains(t, Names(), "test-engine")
+
+	_, ok = Lookup("unknown")
+	require.False(t, ok)
+
+	require.Panics(t, func() { Register("test-engine", Engine{New: newMemory}) })
+	require.Panics(t, func() { Register("postgres", Engine{New: newMemory}) })
+	require.Panics(t, func() { Register("", Engine{New: newMemory}) })
+	require.Panics(t, func() { Register("other-engine", Engine{}) })
+}
@@ -31,6 +31,15 @@ var (
 
 	// ErrNotFound is returned when the object does not exist.
 	ErrNotFound = errors.New("not found")
+
+	// ErrCircuitOpen is returned without calling the datastore while its circuit breaker is open.
+	ErrCircuitOpen = errors.New("datastore circuit breaker is open")
+
+	// ErrDatastoreDeadlineExceeded is returned when a datastore call is cancelled after exceeding its statement timeout.
+	ErrDatastoreDeadlineExceeded = errors.New("datastore deadline exceeded")
+
+	// ErrCapacityExceeded is returned when a write would exceed the capacity limits of the datastore.
+	ErrCapacityExceeded = errors.New("datastore capacity exceeded")
 )
 
 // InvalidWriteInputError generates an error for invalid operations in a tuple store.
@@ -60,3 +69,39 @@ func InvalidWriteInputError(tk tuple.TupleWithoutCondition, operation openfgav1.
 		return nil
 	}
 }
+
+// TransientErrorClass classifies the errors of a datastore that may not recur if the call is retried.
+type TransientErrorClass string
+
+const (
+	// TransientConnection is a failure of the connection to the datastore, e.g. a connection reset. A write failing
+	// with it may or may not have been committed.
+	TransientConnection TransientErrorClass = "connection"
+	// TransientSerialization is a transaction rolled back because it could not be serialized with concurrent ones.
+	TransientSerialization TransientErrorClass = "serialization"
+	// TransientDeadlock is a transaction rolled back to break a deadlock with concurrent ones.
+	TransientDeadlock TransientErrorClass = "deadlock"
+)
+
+// TransientError is returned by datastores for errors that may not recur if the call is retried.
+type TransientError struct {
+	Class TransientErrorClass
+	Err   error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// TransientErrorClassOf returns the class of err if it wraps a [TransientError].
+func TransientErrorClassOf(err error) (TransientErrorClass, bool) {
+	var transientErr *TransientError
+	if errors.As(err, &transientErr) {
+		return transientErr.Class, true
+	}
+	return "", false
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+const (
+	// entryOverhead approximates the memory held by a tuple or a change besides its strings and condition context:
+	// the record itself, its ulid and timestamps, and the slices and maps referencing it.
+	entryOverhead = 256
+
+	// maxTuplesGaugeStores bounds the cardinality of the store_id label of tuplesGauge: the stores written once this
+	// number of stores is labelled are counted together under otherStoresLabel.
+	maxTuplesGaugeStores = 100
+
+	otherStoresLabel = "other"
+)
+
+var (
+	tuplesGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "memory_datastore_tuples",
+		Help:      "The number of tuples held by the memory datastore, per store. The stores beyond the first 100 are counted under the 'other' store_id.",
+	}, []string{"store_id"})
+
+	estimatedBytesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "memory_datastore_estimated_bytes",
+		Help:      "The estimated memory held by the tuples and changes of the memory datastore.",
+	})
+)
+
+// WithMaxTuplesPerStore returns a [StorageOption] that limits the number of tuples of each store. A write that would
+// exceed it fails with [storage.ErrCapacityExceeded]. 0 means no limit.
+func WithMaxTuplesPerStore(n int) StorageOption {
+	return func(ds *MemoryBackend) { ds.maxTuplesPerStore = n }
+}
+
+// WithMaxEstimatedBytes returns a [StorageOption] that limits the estimated memory held by the tuples and changes of
+// all the stores. A write that would exceed it fails with [storage.ErrCapacityExceeded], instead of growing until the
+// process runs out of memory. 0 means no limit.
+func WithMaxEstimatedBytes(n int64) StorageOption {
+	return func(ds *MemoryBackend) { ds.maxEstimatedBytes = n }
+}
+
+// checkCapacity returns an error if applying the write to the store would exceed the capacity limits of the backend.
+// The deletes of the write are assumed valid. The caller must hold mutexTuples.
+func (s *MemoryBackend) checkCapacity(store string, deletes storage.Deletes, writes storage.Writes) error {
+	if s.maxTuplesPerStore > 0 {
+		count := len(s.tuples[store]) - len(deletes) + len(writes)
+		if len(writes) > len(deletes) && count > s.maxTuplesPerStore {
+			return fmt.Errorf("%w: store '%s' would hold %d tuples, above the limit of %d",
+				storage.ErrCapacityExceeded, store, count, s.maxTuplesPerStore)
+		}
+	}
+
+	if s.maxEstimatedBytes > 0 && len(writes) > 0 {
+		// a delete frees a tuple at least as large as the change it records, so only the writes are counted
+		var added int64
+		for _, tk := range writes {
+			size := estimateSize(tk.GetObject(), tk.GetRelation(), tk.GetUser(), tk.GetCondition().GetName(), tk.GetCondition().GetContext())
+			added += 2 * size // the tuple and its change
+		}
+		if s.estimatedBytes+added > s.maxEstimatedBytes {
+			return fmt.Errorf("%w: the memory datastore would hold an estimated %d bytes, above the limit of %d",
+				storage.ErrCapacityExceeded, s.estimatedBytes+added, s.maxEstimatedBytes)
+		}
+	}
+
+	return nil
+}
+
+// estimateSize returns the estimated memory held by a tuple or a change with the given fields.
+func estimateSize(object, relation, user, conditionName string, conditionContext *structpb.Struct) int64 {
+	size := entryOverhead + len(object) + len(relation) + len(user) + len(conditionName)
+	if conditionContext != nil {
+		size += proto.Size(conditionContext)
+	}
+	return int64(size)
+}
+
+func estimateRecordSize(record *storage.TupleRecord) int64 {
+	return estimateSize(tupleUtils.BuildObject(record.ObjectType, record.ObjectID), record.Relation, record.User, record.ConditionName, record.ConditionContext)
+}
+
+func estimateChangeSize(change *openfgav1.TupleChange) int64 {
+	tk := change.GetTupleKey()
+	return estimateSize(tk.GetObject(), tk.GetRelation(), tk.GetUser(), tk.GetCondition().GetName(), tk.GetCondition().GetContext())
+}
+
+// setTuplesGauge updates tuplesGauge for the store, whose tuple count changed from before to after. The store is
+// labelled with its id if it already is, or if it had no tuples and fewer than maxTuplesGaugeStores stores are
+// labelled, and counted under otherStoresLabel otherwise. The series of a store is deleted once it has no tuples.
+// The caller must hold mutexTuples.
+func (s *MemoryBackend) setTuplesGauge(store string, before, after int) {
+	if _, ok := s.gaugedStores[store]; ok {
+		if after == 0 {
+			tuplesGauge.DeleteLabelValues(store)
+			delete(s.gaugedStores, store)
+			return
+		}
+		tuplesGauge.WithLabelValues(store).Set(float64(after))
+		return
+	}
+
+	if before == 0 && after > 0 && len(s.gaugedStores) < maxTuplesGaugeStores {
+		s.gaugedStores[store] = struct{}{}
+		tuplesGauge.WithLabelValues(store).Set(float64(after))
+		return
+	}
+
+	if after != before {
+		s.otherStoresTuples += after - before
+		tuplesGauge.WithLabelValues(otherStoresLabel).Add(float64(after - before))
+	}
+}
+
+// resetTuplesGauge removes the tuple counts of the backend from tuplesGauge. The caller must hold mutexTuples.
+func (s *MemoryBackend) resetTuplesGauge() {
+	for store := range s.gaugedStores {
+		tuplesGauge.DeleteLabelValues(store)
+	}
+	s.gaugedStores = make(map[string]struct{})
+	if s.otherStoresTuples != 0 {
+		tuplesGauge.WithLabelValues(otherStoresLabel).Sub(float64(s.otherStoresTuples))
+		s.otherStoresTuples = 0
+	}
+}
+
+// recomputeUsage recomputes the estimated memory of the backend and the tuple count of every store, e.g. after
+// restoring a snapshot. The caller must hold mutexTuples.
+func (s *MemoryBackend) recomputeUsage() {
+	s.estimatedBytes = 0
+	s.resetTuplesGauge()
+	for store, records := range s.tuples {
+		for _, record := range records {
+			s.estimatedBytes += estimateRecordSize(record)
+		}
+		s.setTuplesGauge(store, 0, len(records))
+	}
+	for _, changes := range s.changes {
+		for _, change := range changes {
+			s.estimatedBytes += estimateChangeSize(change.Change)
+		}
+	}
+	estimatedBytesGauge.Set(float64(s.estimatedBytes))
+}
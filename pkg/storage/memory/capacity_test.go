@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestCapacityLimits(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("max_tuples_per_store", func(t *testing.T) {
+		ds := New(WithMaxTuplesPerStore(2))
+		store := ulid.Make().String()
+
+		require.NoError(t, ds.Write(ctx, store, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("doc:2", "viewer", "user:anne"),
+		}))
+		require.InDelta(t, 2, testutil.ToFloat64(tuplesGauge.WithLabelValues(store)), 0)
+
+		err := ds.Write(ctx, store, nil, []*openfgav1.TupleKey{tuple.NewTupleKey("doc:3", "viewer", "user:anne")})
+		require.ErrorIs(t, err, storage.ErrCapacityExceeded)
+
+		// replacing a tuple does not grow the store
+		require.NoError(t, ds.Write(ctx, store,
+			[]*openfgav1.TupleKeyWithoutCondition{tuple.TupleKeyToTupleKeyWithoutCondition(tuple.NewTupleKey("doc:1", "viewer", "user:anne"))},
+			[]*openfgav1.TupleKey{tuple.NewTupleKey("doc:3", "viewer", "user:anne")}))
+
+		// other stores have their own limit
+		require.NoError(t, ds.Write(ctx, ulid.Make().String(), nil, []*openfgav1.TupleKey{tuple.NewTupleKey("doc:1", "viewer", "user:anne")}))
+	})
+
+	t.Run("max_estimated_bytes", func(t *testing.T) {
+		tk := tuple.NewTupleKey("doc:1", "viewer", "user:anne")
+		size := estimateSize(tk.GetObject(), tk.GetRelation(), tk.GetUser(), "", nil)
+
+		ds := New(WithMaxEstimatedBytes(3 * size))
+		store := ulid.Make().String()
+
+		require.NoError(t, ds.Write(ctx, store, nil, []*openfgav1.TupleKey{tk}))
+		require.Equal(t, 2*size, ds.(*MemoryBackend).estimatedBytes)
+
+		err := ds.Write(ctx, store, nil, []*openfgav1.TupleKey{tuple.NewTupleKey("doc:2", "viewer", "user:anne")})
+		require.ErrorIs(t, err, storage.ErrCapacityExceeded)
+
+		// the deleted tuple is freed, its change is kept
+		require.NoError(t, ds.Write(ctx, store, []*openfgav1.TupleKeyWithoutCondition{tuple.TupleKeyToTupleKeyWithoutCondition(tk)}, nil))
+		require.Equal(t, 2*size, ds.(*MemoryBackend).estimatedBytes)
+	})
+
+	t.Run("delete_store_releases_its_tuples", func(t *testing.T) {
+		tk := tuple.NewTupleKey("doc:1", "viewer", "user:anne")
+		size := estimateSize(tk.GetObject(), tk.GetRelation(), tk.GetUser(), "", nil)
+
+		ds := New(WithMaxEstimatedBytes(2*size), WithMaxTuplesPerStore(1))
+		store := ulid.Make().String()
+
+		require.NoError(t, ds.Write(ctx, store, nil, []*openfgav1.TupleKey{tk}))
+		require.ErrorIs(t, ds.Write(ctx, ulid.Make().String(), nil, []*openfgav1.TupleKey{tk}), storage.ErrCapacityExceeded)
+
+		require.NoError(t, ds.DeleteStore(ctx, store))
+		require.Zero(t, ds.(*MemoryBackend).estimatedBytes)
+		require.False(t, tuplesGauge.DeleteLabelValues(store))
+		require.NoError(t, ds.Write(ctx, ulid.Make().String(), nil, []*openfgav1.TupleKey{tk}))
+	})
+
+	t.Run("tuples_gauge_cardinality", func(t *testing.T) {
+		ds := New()
+		tk := tuple.NewTupleKey("doc:1", "viewer", "user:anne")
+
+		stores := make([]string, maxTuplesGaugeStores+2)
+		for i := range stores {
+			stores[i] = ulid.Make().String()
+			require.NoError(t, ds.Write(ctx, stores[i], nil, []*openfgav1.TupleKey{tk}))
+		}
+		require.InDelta(t, 1, testutil.ToFloat64(tuplesGauge.WithLabelValues(stores[0])), 0)
+		require.InDelta(t, 2, testutil.ToFloat64(tuplesGauge.WithLabelValues(otherStoresLabel)), 0)
+
+		require.NoError(t, ds.DeleteStore(ctx, stores[len(stores)-1]))
+		require.InDelta(t, 1, testutil.ToFloat64(tuplesGauge.WithLabelValues(otherStoresLabel)), 0)
+
+		for _, store := range stores {
+			require.NoError(t, ds.DeleteStore(ctx, store))
+			require.False(t, tuplesGauge.DeleteLabelValues(store))
+		}
+		require.Zero(t, testutil.ToFloat64(tuplesGauge.WithLabelValues(otherStoresLabel)))
+	})
+}
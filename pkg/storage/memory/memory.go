@@ -3,12 +3,11 @@ package memory
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"sort"
 	"strconv"
-	"strings"
 	"sync"
-	"time"
 
 	"github.com/oklog/ulid/v2"
 	"go.opentelemetry.io/otel"
@@ -17,6 +16,7 @@ import (
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/telemetry"
 	tupleUtils "github.com/openfga/openfga/pkg/tuple"
@@ -56,6 +56,28 @@ func match(t *storage.TupleRecord, target *openfgav1.TupleKey) bool {
 	return true
 }
 
+// hasFilters returns true if options restrict the tuples read to some relations or to a user type.
+func hasFilters(options *storage.ReadPageOptions) bool {
+	return options != nil && (len(options.Relations) > 0 || options.UserType != "")
+}
+
+// matchFilters returns true if t passes the relations and user type filters of options, if any.
+func matchFilters(t *storage.TupleRecord, options *storage.ReadPageOptions) bool {
+	if !hasFilters(options) {
+		return true
+	}
+	if len(options.Relations) > 0 && !slices.Contains(options.Relations, t.Relation) {
+		return false
+	}
+	if options.UserType != "" {
+		userObject, _ := tupleUtils.SplitObjectRelation(t.User)
+		if tupleUtils.GetType(userObject) != options.UserType {
+			return false
+		}
+	}
+	return true
+}
+
 // Next see [storage.Iterator].Next.
 func (s *staticIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
 	if ctx.Err() != nil {
@@ -121,6 +143,8 @@ const (
 type MemoryBackend struct {
 	maxTuplesPerWrite             int
 	maxTypesPerAuthorizationModel int
+	maxTuplesPerStore             int
+	maxEstimatedBytes             int64
 
 	// TupleBackend
 	// map: store => set of tuples
@@ -130,6 +154,13 @@ type MemoryBackend struct {
 	// ChangelogBackend
 	// map: store => set of changes
 	changes map[string][]*tupleChangeRec // GUARDED_BY(mutexTuples).
+	// map: store => object type => set of changes, so that reading the changes of one type does not scan the others
+	changesByObjectType map[string]map[string][]*tupleChangeRec // GUARDED_BY(mutexTuples).
+	// estimated memory held by the tuples and changes of all the stores
+	estimatedBytes int64 // GUARDED_BY(mutexTuples).
+	// set of the stores labelled in tuplesGauge, and number of tuples of the others
+	gaugedStores      map[string]struct{} // GUARDED_BY(mutexTuples).
+	otherStoresTuples int                 // GUARDED_BY(mutexTuples).
 
 	// AuthorizationModelBackend
 	// map: store = > map: type definition id => type definition
@@ -137,12 +168,24 @@ type MemoryBackend struct {
 	mutexModels         sync.RWMutex
 
 	// map: store id => store data
-	stores      map[string]*openfgav1.Store // GUARDED_BY(mutexStores).
+	stores map[string]*openfgav1.Store // GUARDED_BY(mutexStores).
+	// map: store id => labels
+	storeLabels map[string]map[string]string // GUARDED_BY(mutexStores).
 	mutexStores sync.RWMutex
 
 	// map: store id | authz model id => assertions
 	assertions      map[string][]*openfgav1.Assertion // GUARDED_BY(mutexAssertions).
 	mutexAssertions sync.RWMutex
+
+	// map: store id | job id => job
+	jobs      map[string]*storage.Job // GUARDED_BY(mutexJobs).
+	mutexJobs sync.RWMutex
+
+	// snapshotter persists the backend to disk, if created by NewWithSnapshots.
+	snapshotter *snapshotter
+
+	// clock timestamps the tuples, their changes and the stores.
+	clock clock.Clock
 }
 
 // Ensures that [MemoryBackend] implements the [storage.OpenFGADatastore] interface.
@@ -162,9 +205,14 @@ func New(opts ...StorageOption) storage.OpenFGADatastore {
 		maxTypesPerAuthorizationModel: defaultMaxTypesPerAuthorizationModel,
 		tuples:                        make(map[string][]*storage.TupleRecord, 0),
 		changes:                       make(map[string][]*tupleChangeRec, 0),
+		changesByObjectType:           make(map[string]map[string][]*tupleChangeRec, 0),
+		gaugedStores:                  make(map[string]struct{}),
 		authorizationModels:           make(map[string]map[string]*AuthorizationModelEntry),
 		stores:                        make(map[string]*openfgav1.Store, 0),
+		storeLabels:                   make(map[string]map[string]string, 0),
 		assertions:                    make(map[string][]*openfgav1.Assertion, 0),
+		jobs:                          make(map[string]*storage.Job, 0),
+		clock:                         clock.New(),
 	}
 
 	for _, opt := range opts {
@@ -181,6 +229,12 @@ func WithMaxTuplesPerWrite(n int) StorageOption {
 	return func(ds *MemoryBackend) { ds.maxTuplesPerWrite = n }
 }
 
+// WithClock returns a [StorageOption] that sets the clock timestamping the tuples, their changes and the stores, and
+// against which the horizon offset of ReadChanges is computed.
+func WithClock(c clock.Clock) StorageOption {
+	return func(ds *MemoryBackend) { ds.clock = c }
+}
+
 // WithMaxTypesPerAuthorizationModel returns a [StorageOption] that sets the maximum number of types allowed per authorization model.
 // This configuration is particularly useful for limiting the complexity or size of an authorization model in a MemoryBackend instance,
 // ensuring that models remain manageable and within predefined resource constraints.
@@ -188,8 +242,11 @@ func WithMaxTypesPerAuthorizationModel(n int) StorageOption {
 	return func(ds *MemoryBackend) { ds.maxTypesPerAuthorizationModel = n }
 }
 
-// Close does not do anything for [MemoryBackend].
-func (s *MemoryBackend) Close() {}
+// Close saves the final snapshot of a [MemoryBackend] created by [NewWithSnapshots]. It does not do anything
+// otherwise.
+func (s *MemoryBackend) Close() {
+	_ = s.closeSnapshotter()
+}
 
 // Read see [storage.RelationshipTupleReader].Read.
 func (s *MemoryBackend) Read(ctx context.Context, store string, key *openfgav1.TupleKey, _ storage.ReadOptions) (storage.TupleIterator, error) {
@@ -232,22 +289,31 @@ func (s *MemoryBackend) ReadChanges(ctx context.Context, store string, filter st
 	objectType := filter.ObjectType
 	horizonOffset := filter.HorizonOffset
 
+	changes := s.changes[store]
+	if objectType != "" {
+		changes = s.changesByObjectType[store][objectType]
+	}
+
 	var allChanges []*tupleChangeRec
-	now := time.Now().UTC()
-	for _, changeRec := range s.changes[store] {
-		if objectType == "" || (strings.HasPrefix(changeRec.Change.GetTupleKey().GetObject(), objectType+":")) {
-			if changeRec.Change.GetTimestamp().AsTime().After(now.Add(-horizonOffset)) {
-				break
-			}
-			if from != nil {
-				if !options.SortDesc && changeRec.Ulid.Compare(*from) <= 0 {
-					continue
-				} else if options.SortDesc && changeRec.Ulid.Compare(*from) >= 0 {
-					continue
-				}
+	now := s.clock.Now().UTC()
+	for _, changeRec := range changes {
+		if changeRec.Change.GetTimestamp().AsTime().After(now.Add(-horizonOffset)) {
+			break
+		}
+		if filter.ObjectID != "" && changeRec.Change.GetTupleKey().GetObject() != tupleUtils.BuildObject(objectType, filter.ObjectID) {
+			continue
+		}
+		if filter.User != "" && changeRec.Change.GetTupleKey().GetUser() != filter.User {
+			continue
+		}
+		if from != nil {
+			if !options.SortDesc && changeRec.Ulid.Compare(*from) <= 0 {
+				continue
+			} else if options.SortDesc && changeRec.Ulid.Compare(*from) >= 0 {
+				continue
 			}
-			allChanges = append(allChanges, changeRec)
 		}
+		allChanges = append(allChanges, changeRec)
 	}
 	if len(allChanges) == 0 {
 		return nil, "", storage.ErrNotFound
@@ -290,12 +356,12 @@ func (s *MemoryBackend) read(ctx context.Context, store string, tk *openfgav1.Tu
 	defer s.mutexTuples.RUnlock()
 
 	var matches []*storage.TupleRecord
-	if tk.GetObject() == "" && tk.GetRelation() == "" && tk.GetUser() == "" {
+	if tk.GetObject() == "" && tk.GetRelation() == "" && tk.GetUser() == "" && !hasFilters(options) {
 		matches = make([]*storage.TupleRecord, len(s.tuples[store]))
 		copy(matches, s.tuples[store])
 	} else {
 		for _, t := range s.tuples[store] {
-			if match(t, tk) {
+			if match(t, tk) && matchFilters(t, options) {
 				matches = append(matches, t)
 			}
 		}
@@ -339,12 +405,16 @@ func (s *MemoryBackend) Write(ctx context.Context, store string, deletes storage
 	s.mutexTuples.Lock()
 	defer s.mutexTuples.Unlock()
 
-	now := timestamppb.Now()
+	now := timestamppb.New(s.clock.Now())
 
 	if err := validateTuples(s.tuples[store], deletes, writes); err != nil {
 		return err
 	}
 
+	if err := s.checkCapacity(store, deletes, writes); err != nil {
+		return err
+	}
+
 	var records []*storage.TupleRecord
 	entropy := ulid.DefaultEntropy()
 Delete:
@@ -353,17 +423,15 @@ Delete:
 		tk := t.GetKey()
 		for _, k := range deletes {
 			if match(tr, tupleUtils.TupleKeyWithoutConditionToTupleKey(k)) {
-				s.changes[store] = append(
-					s.changes[store],
-					&tupleChangeRec{
-						Change: &openfgav1.TupleChange{
-							TupleKey:  tupleUtils.NewTupleKey(tk.GetObject(), tk.GetRelation(), tk.GetUser()), // Redact the condition info.
-							Operation: openfgav1.TupleOperation_TUPLE_OPERATION_DELETE,
-							Timestamp: now,
-						},
-						Ulid: ulid.MustNew(ulid.Timestamp(now.AsTime()), entropy),
+				s.appendChange(store, tr.ObjectType, &tupleChangeRec{
+					Change: &openfgav1.TupleChange{
+						TupleKey:  tupleUtils.NewTupleKey(tk.GetObject(), tk.GetRelation(), tk.GetUser()), // Redact the condition info.
+						Operation: openfgav1.TupleOperation_TUPLE_OPERATION_DELETE,
+						Timestamp: now,
 					},
-				)
+					Ulid: ulid.MustNew(ulid.Timestamp(now.AsTime()), entropy),
+				})
+				s.estimatedBytes -= estimateRecordSize(tr)
 				continue Delete
 			}
 		}
@@ -387,7 +455,7 @@ Write:
 
 		objectType, objectID := tupleUtils.SplitObject(t.GetObject())
 
-		records = append(records, &storage.TupleRecord{
+		record := &storage.TupleRecord{
 			Store:            store,
 			ObjectType:       objectType,
 			ObjectID:         objectID,
@@ -397,7 +465,9 @@ Write:
 			ConditionContext: conditionContext,
 			Ulid:             ulid.MustNew(ulid.Timestamp(now.AsTime()), ulid.DefaultEntropy()).String(),
 			InsertedAt:       now.AsTime(),
-		})
+		}
+		records = append(records, record)
+		s.estimatedBytes += estimateRecordSize(record)
 
 		tk := tupleUtils.NewTupleKeyWithCondition(
 			tupleUtils.BuildObject(objectType, objectID),
@@ -407,7 +477,7 @@ Write:
 			conditionContext,
 		)
 
-		s.changes[store] = append(s.changes[store], &tupleChangeRec{
+		s.appendChange(store, objectType, &tupleChangeRec{
 			Change: &openfgav1.TupleChange{
 				TupleKey:  tk,
 				Operation: openfgav1.TupleOperation_TUPLE_OPERATION_WRITE,
@@ -416,10 +486,25 @@ Write:
 			Ulid: ulid.MustNew(ulid.Timestamp(now.AsTime()), entropy),
 		})
 	}
+	s.setTuplesGauge(store, len(s.tuples[store]), len(records))
 	s.tuples[store] = records
+
+	estimatedBytesGauge.Set(float64(s.estimatedBytes))
 	return nil
 }
 
+// appendChange records a change in the changelog of the store and in that of its object type.
+// The caller must hold mutexTuples.
+func (s *MemoryBackend) appendChange(store, objectType string, change *tupleChangeRec) {
+	s.changes[store] = append(s.changes[store], change)
+
+	if _, ok := s.changesByObjectType[store]; !ok {
+		s.changesByObjectType[store] = make(map[string][]*tupleChangeRec)
+	}
+	s.changesByObjectType[store][objectType] = append(s.changesByObjectType[store][objectType], change)
+	s.estimatedBytes += estimateChangeSize(change.Change)
+}
+
 func validateTuples(
 	records []*storage.TupleRecord,
 	deletes []*openfgav1.TupleKeyWithoutCondition,
@@ -430,8 +515,11 @@ func validateTuples(
 			return storage.InvalidWriteInputError(tk, openfgav1.TupleOperation_TUPLE_OPERATION_DELETE)
 		}
 	}
+	// like the SQL datastores, the deletes are applied before the writes, so a tuple may be deleted and written again
 	for _, tk := range writes {
-		if find(records, tk) {
+		if find(records, tk) && !slices.ContainsFunc(deletes, func(deleted *openfgav1.TupleKeyWithoutCondition) bool {
+			return tupleUtils.TupleKeyToString(deleted) == tupleUtils.TupleKeyToString(tk)
+		}) {
 			return storage.InvalidWriteInputError(tk, openfgav1.TupleOperation_TUPLE_OPERATION_WRITE)
 		}
 	}
@@ -466,6 +554,27 @@ func (s *MemoryBackend) ReadUserTuple(ctx context.Context, store string, key *op
 	return nil, storage.ErrNotFound
 }
 
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (s *MemoryBackend) ReadUserTuples(ctx context.Context, store string, keys []*openfgav1.TupleKey, _ storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	_, span := tracer.Start(ctx, "memory.ReadUserTuples")
+	defer span.End()
+
+	s.mutexTuples.RLock()
+	defer s.mutexTuples.RUnlock()
+
+	var tuples []*openfgav1.Tuple
+	for _, t := range s.tuples[store] {
+		for _, key := range keys {
+			if match(t, key) {
+				tuples = append(tuples, t.AsTuple())
+				break
+			}
+		}
+	}
+
+	return tuples, nil
+}
+
 // ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
 func (s *MemoryBackend) ReadUsersetTuples(
 	ctx context.Context,
@@ -696,11 +805,57 @@ func (s *MemoryBackend) WriteAuthorizationModel(ctx context.Context, store strin
 	return nil
 }
 
+// DeleteAuthorizationModel see [storage.TypeDefinitionWriteBackend].DeleteAuthorizationModel.
+func (s *MemoryBackend) DeleteAuthorizationModel(ctx context.Context, store string, id string) error {
+	_, span := tracer.Start(ctx, "memory.DeleteAuthorizationModel")
+	defer span.End()
+
+	s.mutexModels.Lock()
+	defer s.mutexModels.Unlock()
+
+	entry, ok := s.authorizationModels[store][id]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	delete(s.authorizationModels[store], id)
+
+	if entry.latest {
+		// The latest model is the one with the greatest ULID.
+		var latest *AuthorizationModelEntry
+		for modelID, other := range s.authorizationModels[store] {
+			if latest == nil || modelID > latest.model.GetId() {
+				latest = other
+			}
+		}
+		if latest != nil {
+			latest.latest = true
+		}
+	}
+
+	s.mutexAssertions.Lock()
+	defer s.mutexAssertions.Unlock()
+	delete(s.assertions, fmt.Sprintf("%s|%s", store, id))
+
+	return nil
+}
+
 // CreateStore adds a new store to the [MemoryBackend].
 func (s *MemoryBackend) CreateStore(ctx context.Context, newStore *openfgav1.Store) (*openfgav1.Store, error) {
 	_, span := tracer.Start(ctx, "memory.CreateStore")
 	defer span.End()
 
+	return s.createStore(newStore, nil)
+}
+
+// CreateStoreWithLabels see [storage.StoresBackend].CreateStoreWithLabels.
+func (s *MemoryBackend) CreateStoreWithLabels(ctx context.Context, newStore *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
+	_, span := tracer.Start(ctx, "memory.CreateStoreWithLabels")
+	defer span.End()
+
+	return s.createStore(newStore, labels)
+}
+
+func (s *MemoryBackend) createStore(newStore *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
 	s.mutexStores.Lock()
 	defer s.mutexStores.Unlock()
 
@@ -708,13 +863,16 @@ func (s *MemoryBackend) CreateStore(ctx context.Context, newStore *openfgav1.Sto
 		return nil, storage.ErrCollision
 	}
 
-	now := timestamppb.New(time.Now().UTC())
+	now := timestamppb.New(s.clock.Now().UTC())
 	s.stores[newStore.GetId()] = &openfgav1.Store{
 		Id:        newStore.GetId(),
 		Name:      newStore.GetName(),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	if len(labels) > 0 {
+		s.storeLabels[newStore.GetId()] = maps.Clone(labels)
+	}
 
 	return s.stores[newStore.GetId()], nil
 }
@@ -724,13 +882,159 @@ func (s *MemoryBackend) DeleteStore(ctx context.Context, id string) error {
 	_, span := tracer.Start(ctx, "memory.DeleteStore")
 	defer span.End()
 
+	s.mutexStores.Lock()
+	delete(s.stores, id)
+	delete(s.storeLabels, id)
+	s.mutexStores.Unlock()
+
+	// the tuples and changes of the store are released, so that they no longer count towards the capacity limits
+	s.mutexTuples.Lock()
+	defer s.mutexTuples.Unlock()
+
+	for _, record := range s.tuples[id] {
+		s.estimatedBytes -= estimateRecordSize(record)
+	}
+	for _, change := range s.changes[id] {
+		s.estimatedBytes -= estimateChangeSize(change.Change)
+	}
+	s.setTuplesGauge(id, len(s.tuples[id]), 0)
+	delete(s.tuples, id)
+	delete(s.changes, id)
+	delete(s.changesByObjectType, id)
+	estimatedBytesGauge.Set(float64(s.estimatedBytes))
+	return nil
+}
+
+// WriteStoreLabels see [storage.StoresBackend].WriteStoreLabels.
+func (s *MemoryBackend) WriteStoreLabels(ctx context.Context, id string, labels map[string]string) error {
+	_, span := tracer.Start(ctx, "memory.WriteStoreLabels")
+	defer span.End()
+
 	s.mutexStores.Lock()
 	defer s.mutexStores.Unlock()
 
-	delete(s.stores, id)
+	if s.stores[id] == nil {
+		return storage.ErrNotFound
+	}
+
+	s.storeLabels[id] = maps.Clone(labels)
+	return nil
+}
+
+// ReadStoreLabels see [storage.StoresBackend].ReadStoreLabels.
+func (s *MemoryBackend) ReadStoreLabels(ctx context.Context, id string) (map[string]string, error) {
+	_, span := tracer.Start(ctx, "memory.ReadStoreLabels")
+	defer span.End()
+
+	s.mutexStores.RLock()
+	defer s.mutexStores.RUnlock()
+
+	labels := maps.Clone(s.storeLabels[id])
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return labels, nil
+}
+
+// hasLabels returns true if labels holds every label of wanted.
+func hasLabels(labels, wanted map[string]string) bool {
+	for key, value := range wanted {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateJob see [storage.JobsBackend].CreateJob.
+func (s *MemoryBackend) CreateJob(ctx context.Context, job *storage.Job) error {
+	_, span := tracer.Start(ctx, "memory.CreateJob")
+	defer span.End()
+
+	s.mutexJobs.Lock()
+	defer s.mutexJobs.Unlock()
+
+	now := s.clock.Now().UTC()
+	job.CreatedAt, job.UpdatedAt = now, now
+	stored := *job
+	s.jobs[job.StoreID+"|"+job.ID] = &stored
+	return nil
+}
+
+// ReadJob see [storage.JobsBackend].ReadJob.
+func (s *MemoryBackend) ReadJob(ctx context.Context, store, id string) (*storage.Job, error) {
+	_, span := tracer.Start(ctx, "memory.ReadJob")
+	defer span.End()
+
+	s.mutexJobs.RLock()
+	defer s.mutexJobs.RUnlock()
+
+	job, ok := s.jobs[store+"|"+id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	read := *job
+	return &read, nil
+}
+
+// UpdateJob see [storage.JobsBackend].UpdateJob.
+func (s *MemoryBackend) UpdateJob(ctx context.Context, job *storage.Job) error {
+	_, span := tracer.Start(ctx, "memory.UpdateJob")
+	defer span.End()
+
+	s.mutexJobs.Lock()
+	defer s.mutexJobs.Unlock()
+
+	stored, ok := s.jobs[job.StoreID+"|"+job.ID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	job.UpdatedAt = s.clock.Now().UTC()
+	stored.Status = job.Status
+	stored.Progress = job.Progress
+	stored.Result = job.Result
+	stored.Error = job.Error
+	stored.UpdatedAt = job.UpdatedAt
+	return nil
+}
+
+// RequestJobCancellation see [storage.JobsBackend].RequestJobCancellation.
+func (s *MemoryBackend) RequestJobCancellation(ctx context.Context, store, id string) error {
+	_, span := tracer.Start(ctx, "memory.RequestJobCancellation")
+	defer span.End()
+
+	s.mutexJobs.Lock()
+	defer s.mutexJobs.Unlock()
+
+	job, ok := s.jobs[store+"|"+id]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	job.CancelRequested = true
 	return nil
 }
 
+// ClaimInterruptedJobs see [storage.JobsBackend].ClaimInterruptedJobs.
+func (s *MemoryBackend) ClaimInterruptedJobs(ctx context.Context) ([]*storage.Job, error) {
+	_, span := tracer.Start(ctx, "memory.ClaimInterruptedJobs")
+	defer span.End()
+
+	s.mutexJobs.Lock()
+	defer s.mutexJobs.Unlock()
+
+	var claimed []*storage.Job
+	for _, job := range s.jobs {
+		if job.Status != storage.JobStatusInterrupted {
+			continue
+		}
+		job.Status = storage.JobStatusPending
+		job.UpdatedAt = s.clock.Now().UTC()
+		read := *job
+		claimed = append(claimed, &read)
+	}
+	return claimed, nil
+}
+
 // WriteAssertions see [storage.AssertionsBackend].WriteAssertions.
 func (s *MemoryBackend) WriteAssertions(ctx context.Context, store, modelID string, assertions []*openfgav1.Assertion) error {
 	_, span := tracer.Start(ctx, "memory.WriteAssertions")
@@ -821,6 +1125,16 @@ func (s *MemoryBackend) ListStores(ctx context.Context, options storage.ListStor
 		stores = filteredStores
 	}
 
+	if len(options.Labels) > 0 {
+		filteredStores := make([]*openfgav1.Store, 0, len(stores))
+		for _, store := range stores {
+			if hasLabels(s.storeLabels[store.GetId()], options.Labels) {
+				filteredStores = append(filteredStores, store)
+			}
+		}
+		stores = filteredStores
+	}
+
 	// From oldest to newest.
 	sort.SliceStable(stores, func(i, j int) bool {
 		return stores[i].GetId() < stores[j].GetId()
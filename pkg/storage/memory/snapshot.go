@@ -0,0 +1,394 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+// snapshotVersion is the version of the snapshot format, checked when a snapshot is loaded.
+const snapshotVersion = 1
+
+// snapshot is the content of a [MemoryBackend] as persisted to disk. Protobuf messages are encoded with protojson.
+type snapshot struct {
+	Version     int                                 `json:"version"`
+	Stores      map[string]json.RawMessage          `json:"stores"`
+	StoreLabels map[string]map[string]string        `json:"store_labels"`
+	Tuples      map[string][]snapshotTuple          `json:"tuples"`
+	Changes     map[string][]snapshotChange         `json:"changes"`
+	Models      map[string]map[string]snapshotModel `json:"models"`
+	Assertions  map[string][]json.RawMessage        `json:"assertions"`
+}
+
+type snapshotTuple struct {
+	ObjectType       string          `json:"object_type"`
+	ObjectID         string          `json:"object_id"`
+	Relation         string          `json:"relation"`
+	User             string          `json:"user"`
+	ConditionName    string          `json:"condition_name,omitempty"`
+	ConditionContext json.RawMessage `json:"condition_context,omitempty"`
+	Ulid             string          `json:"ulid"`
+	InsertedAt       time.Time       `json:"inserted_at"`
+}
+
+type snapshotChange struct {
+	Change json.RawMessage `json:"change"`
+	Ulid   string          `json:"ulid"`
+}
+
+type snapshotModel struct {
+	Model  json.RawMessage `json:"model"`
+	Latest bool            `json:"latest"`
+}
+
+// snapshotter periodically saves a [MemoryBackend] to its snapshot file.
+type snapshotter struct {
+	path     string
+	interval time.Duration
+
+	mu   sync.Mutex // serializes the saves
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewWithSnapshots returns a [MemoryBackend] persisted to the snapshot file at path, so that it survives restarts.
+// The backend is loaded from the file if it exists, then saved to it every interval, unless interval is 0, and when
+// it is closed. It is meant for single node evaluation environments: the writes made since the last snapshot are
+// lost if the process does not shut down gracefully.
+func NewWithSnapshots(path string, interval time.Duration, opts ...StorageOption) (storage.OpenFGADatastore, error) {
+	ds := New(opts...).(*MemoryBackend)
+
+	if err := ds.LoadSnapshot(path); err != nil {
+		return nil, err
+	}
+
+	ds.snapshotter = &snapshotter{
+		path:     path,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	if interval > 0 {
+		ds.snapshotter.wg.Add(1)
+		go ds.saveSnapshots()
+	}
+
+	return ds, nil
+}
+
+// saveSnapshots saves the backend every interval until it is closed.
+func (s *MemoryBackend) saveSnapshots() {
+	defer s.snapshotter.wg.Done()
+
+	ticker := time.NewTicker(s.snapshotter.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.snapshotter.stop:
+			return
+		case <-ticker.C:
+			// a failed snapshot is retried on the next tick and on close
+			_ = s.SaveSnapshot(s.snapshotter.path)
+		}
+	}
+}
+
+// closeSnapshotter stops the periodic snapshots and saves the final one.
+func (s *MemoryBackend) closeSnapshotter() error {
+	if s.snapshotter == nil {
+		return nil
+	}
+	s.snapshotter.once.Do(func() { close(s.snapshotter.stop) })
+	s.snapshotter.wg.Wait()
+	return s.SaveSnapshot(s.snapshotter.path)
+}
+
+// SaveSnapshot saves the content of the backend to the file at path, atomically replacing it.
+func (s *MemoryBackend) SaveSnapshot(path string) error {
+	if s.snapshotter != nil {
+		s.snapshotter.mu.Lock()
+		defer s.snapshotter.mu.Unlock()
+	}
+
+	snap, err := s.snapshot()
+	if err != nil {
+		return fmt.Errorf("memory snapshot: %w", err)
+	}
+
+	content, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("memory snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("memory snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("memory snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("memory snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("memory snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("memory snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot replaces the content of the backend with the snapshot file at path. It does nothing if the file does
+// not exist.
+func (s *MemoryBackend) LoadSnapshot(path string) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load memory snapshot: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(content, &snap); err != nil {
+		return fmt.Errorf("load memory snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("load memory snapshot: unsupported version %d", snap.Version)
+	}
+
+	if err := s.restore(&snap); err != nil {
+		return fmt.Errorf("load memory snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshot returns the content of the backend. Each kind of data is read under its own lock, so that the snapshot
+// does not block every operation at once.
+func (s *MemoryBackend) snapshot() (*snapshot, error) {
+	snap := &snapshot{
+		Version:     snapshotVersion,
+		Stores:      map[string]json.RawMessage{},
+		StoreLabels: map[string]map[string]string{},
+		Tuples:      map[string][]snapshotTuple{},
+		Changes:     map[string][]snapshotChange{},
+		Models:      map[string]map[string]snapshotModel{},
+		Assertions:  map[string][]json.RawMessage{},
+	}
+
+	s.mutexStores.RLock()
+	for id, store := range s.stores {
+		raw, err := protojson.Marshal(store)
+		if err != nil {
+			s.mutexStores.RUnlock()
+			return nil, err
+		}
+		snap.Stores[id] = raw
+	}
+	for id, labels := range s.storeLabels {
+		snap.StoreLabels[id] = labels
+	}
+	s.mutexStores.RUnlock()
+
+	s.mutexTuples.RLock()
+	for store, records := range s.tuples {
+		tuples := make([]snapshotTuple, 0, len(records))
+		for _, record := range records {
+			t := snapshotTuple{
+				ObjectType:    record.ObjectType,
+				ObjectID:      record.ObjectID,
+				Relation:      record.Relation,
+				User:          record.User,
+				ConditionName: record.ConditionName,
+				Ulid:          record.Ulid,
+				InsertedAt:    record.InsertedAt,
+			}
+			if record.ConditionContext != nil {
+				raw, err := protojson.Marshal(record.ConditionContext)
+				if err != nil {
+					s.mutexTuples.RUnlock()
+					return nil, err
+				}
+				t.ConditionContext = raw
+			}
+			tuples = append(tuples, t)
+		}
+		snap.Tuples[store] = tuples
+	}
+	for store, changes := range s.changes {
+		snapshotChanges := make([]snapshotChange, 0, len(changes))
+		for _, change := range changes {
+			raw, err := protojson.Marshal(change.Change)
+			if err != nil {
+				s.mutexTuples.RUnlock()
+				return nil, err
+			}
+			snapshotChanges = append(snapshotChanges, snapshotChange{Change: raw, Ulid: change.Ulid.String()})
+		}
+		snap.Changes[store] = snapshotChanges
+	}
+	s.mutexTuples.RUnlock()
+
+	s.mutexModels.RLock()
+	for store, entries := range s.authorizationModels {
+		models := make(map[string]snapshotModel, len(entries))
+		for id, entry := range entries {
+			raw, err := protojson.Marshal(entry.model)
+			if err != nil {
+				s.mutexModels.RUnlock()
+				return nil, err
+			}
+			models[id] = snapshotModel{Model: raw, Latest: entry.latest}
+		}
+		snap.Models[store] = models
+	}
+	s.mutexModels.RUnlock()
+
+	s.mutexAssertions.RLock()
+	for id, assertions := range s.assertions {
+		raws := make([]json.RawMessage, 0, len(assertions))
+		for _, assertion := range assertions {
+			raw, err := protojson.Marshal(assertion)
+			if err != nil {
+				s.mutexAssertions.RUnlock()
+				return nil, err
+			}
+			raws = append(raws, raw)
+		}
+		snap.Assertions[id] = raws
+	}
+	s.mutexAssertions.RUnlock()
+
+	return snap, nil
+}
+
+// restore replaces the content of the backend with the snapshot.
+func (s *MemoryBackend) restore(snap *snapshot) error {
+	stores := make(map[string]*openfgav1.Store, len(snap.Stores))
+	for id, raw := range snap.Stores {
+		store := &openfgav1.Store{}
+		if err := unmarshal(raw, store); err != nil {
+			return err
+		}
+		stores[id] = store
+	}
+
+	tuples := make(map[string][]*storage.TupleRecord, len(snap.Tuples))
+	for store, snapshotTuples := range snap.Tuples {
+		records := make([]*storage.TupleRecord, 0, len(snapshotTuples))
+		for _, t := range snapshotTuples {
+			record := &storage.TupleRecord{
+				Store:         store,
+				ObjectType:    t.ObjectType,
+				ObjectID:      t.ObjectID,
+				Relation:      t.Relation,
+				User:          t.User,
+				ConditionName: t.ConditionName,
+				Ulid:          t.Ulid,
+				InsertedAt:    t.InsertedAt,
+			}
+			if len(t.ConditionContext) > 0 {
+				record.ConditionContext = &structpb.Struct{}
+				if err := unmarshal(t.ConditionContext, record.ConditionContext); err != nil {
+					return err
+				}
+			}
+			records = append(records, record)
+		}
+		tuples[store] = records
+	}
+
+	changes := make(map[string][]*tupleChangeRec, len(snap.Changes))
+	for store, snapshotChanges := range snap.Changes {
+		for _, c := range snapshotChanges {
+			change := &openfgav1.TupleChange{}
+			if err := unmarshal(c.Change, change); err != nil {
+				return err
+			}
+			id, err := ulid.Parse(c.Ulid)
+			if err != nil {
+				return err
+			}
+			changes[store] = append(changes[store], &tupleChangeRec{Change: change, Ulid: id})
+		}
+	}
+
+	models := make(map[string]map[string]*AuthorizationModelEntry, len(snap.Models))
+	for store, snapshotModels := range snap.Models {
+		models[store] = make(map[string]*AuthorizationModelEntry, len(snapshotModels))
+		for id, m := range snapshotModels {
+			model := &openfgav1.AuthorizationModel{}
+			if err := unmarshal(m.Model, model); err != nil {
+				return err
+			}
+			models[store][id] = &AuthorizationModelEntry{model: model, latest: m.Latest}
+		}
+	}
+
+	assertions := make(map[string][]*openfgav1.Assertion, len(snap.Assertions))
+	for id, raws := range snap.Assertions {
+		for _, raw := range raws {
+			assertion := &openfgav1.Assertion{}
+			if err := unmarshal(raw, assertion); err != nil {
+				return err
+			}
+			assertions[id] = append(assertions[id], assertion)
+		}
+	}
+
+	s.mutexStores.Lock()
+	s.stores = stores
+	s.storeLabels = make(map[string]map[string]string, len(snap.StoreLabels))
+	for id, labels := range snap.StoreLabels {
+		s.storeLabels[id] = labels
+	}
+	s.mutexStores.Unlock()
+
+	s.mutexTuples.Lock()
+	s.tuples = tuples
+	s.changes = make(map[string][]*tupleChangeRec, len(changes))
+	s.changesByObjectType = make(map[string]map[string][]*tupleChangeRec, len(changes))
+	for store, storeChanges := range changes {
+		for _, change := range storeChanges {
+			s.appendChange(store, tupleUtils.GetType(change.Change.GetTupleKey().GetObject()), change)
+		}
+	}
+	s.recomputeUsage()
+	s.mutexTuples.Unlock()
+
+	s.mutexModels.Lock()
+	s.authorizationModels = models
+	s.mutexModels.Unlock()
+
+	s.mutexAssertions.Lock()
+	s.assertions = assertions
+	s.mutexAssertions.Unlock()
+
+	return nil
+}
+
+func unmarshal(raw json.RawMessage, m proto.Message) error {
+	return protojson.Unmarshal(raw, m)
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestSnapshots(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	t.Run("missing_snapshot_starts_empty", func(t *testing.T) {
+		ds, err := NewWithSnapshots(path, 0)
+		require.NoError(t, err)
+
+		stores, _, err := ds.ListStores(ctx, storage.ListStoresOptions{})
+		require.NoError(t, err)
+		require.Empty(t, stores)
+		ds.Close()
+	})
+
+	storeID := ulid.Make().String()
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+		type user
+		type doc
+			relations
+				define viewer: [user]`)
+	model.Id = ulid.Make().String()
+	conditionContext := testutils.MustNewStruct(t, map[string]any{"x": "1"})
+
+	t.Run("saved_on_close", func(t *testing.T) {
+		ds, err := NewWithSnapshots(path, 0)
+		require.NoError(t, err)
+
+		_, err = ds.CreateStore(ctx, &openfgav1.Store{Id: storeID, Name: "demo"})
+		require.NoError(t, err)
+		require.NoError(t, ds.WriteAuthorizationModel(ctx, storeID, model))
+		require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+			tuple.NewTupleKeyWithCondition("doc:2", "viewer", "user:bob", "cond", conditionContext),
+		}))
+		require.NoError(t, ds.WriteAssertions(ctx, storeID, model.GetId(), []*openfgav1.Assertion{
+			{TupleKey: tuple.NewAssertionTupleKey("doc:1", "viewer", "user:anne"), Expectation: true},
+		}))
+		ds.Close()
+
+		_, err = os.Stat(path)
+		require.NoError(t, err)
+	})
+
+	t.Run("loaded_on_start", func(t *testing.T) {
+		ds, err := NewWithSnapshots(path, 0)
+		require.NoError(t, err)
+		defer ds.Close()
+
+		store, err := ds.GetStore(ctx, storeID)
+		require.NoError(t, err)
+		require.Equal(t, "demo", store.GetName())
+
+		latest, err := ds.FindLatestAuthorizationModel(ctx, storeID)
+		require.NoError(t, err)
+		require.Equal(t, model.GetId(), latest.GetId())
+
+		tk, err := ds.ReadUserTuple(ctx, storeID, tuple.NewTupleKey("doc:2", "viewer", "user:bob"), storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "cond", tk.GetKey().GetCondition().GetName())
+		require.Equal(t, conditionContext.AsMap(), tk.GetKey().GetCondition().GetContext().AsMap())
+
+		changes, _, err := ds.ReadChanges(ctx, storeID, storage.ReadChangesFilter{ObjectType: "doc"}, storage.ReadChangesOptions{})
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+
+		assertions, err := ds.ReadAssertions(ctx, storeID, model.GetId())
+		require.NoError(t, err)
+		require.Len(t, assertions, 1)
+	})
+
+	t.Run("unsupported_version", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "snapshot.json")
+		require.NoError(t, os.WriteFile(invalid, []byte(`{"version": 0}`), 0o600))
+
+		_, err := NewWithSnapshots(invalid, 0)
+		require.EqualError(t, err, "load memory snapshot: unsupported version 0")
+	})
+}
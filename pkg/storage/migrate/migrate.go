@@ -23,6 +23,10 @@ type MigrationConfig struct {
 	Verbose       bool
 	Username      string
 	Password      string
+
+	// PostgresTuplePartitions is the number of partitions to hash-partition the tuple table of a Postgres database
+	// into by store, when migrating it to version 9 or later. If 0, the tuple table is not partitioned.
+	PostgresTuplePartitions uint
 }
 
 // RunMigrations runs the migrations for the given config. This function is exposed to allow embedding openFGA
@@ -35,6 +39,11 @@ type MigrationConfig struct {
 // The function handles migrations for multiple database engines (postgres, mysql, sqlite) and supports
 // both upgrading and downgrading to specific versions.
 func RunMigrations(cfg MigrationConfig) error {
+	if cfg.PostgresTuplePartitions > 0 && (cfg.Engine != "postgres" ||
+		(cfg.TargetVersion != 0 && cfg.TargetVersion < postgresTuplePartitionsVersion)) {
+		return fmt.Errorf("partitioning the tuple table requires migrating a postgres datastore to version %d or later", postgresTuplePartitionsVersion)
+	}
+
 	goose.SetLogger(goose.NopLogger())
 	goose.SetVerbose(cfg.Verbose)
 
@@ -84,6 +93,13 @@ func RunMigrations(cfg MigrationConfig) error {
 
 		// Replace CLI uri with the one we just updated.
 		uri = dbURI.String()
+
+		// goose runs all the registered Go migrations, so they are only registered while migrating postgres
+		goose.ResetGlobalMigrations()
+		defer goose.ResetGlobalMigrations()
+		if err := goose.SetGlobalMigrations(postgresGoMigrations(cfg.PostgresTuplePartitions)...); err != nil {
+			return err
+		}
 	case "sqlite":
 		driver = "sqlite"
 		migrationsPath = assets.SqliteMigrationDir
@@ -128,6 +144,11 @@ func RunMigrations(cfg MigrationConfig) error {
 		if err := goose.Up(db, migrationsPath); err != nil {
 			return fmt.Errorf("failed to run migrations: %w", err)
 		}
+		if cfg.PostgresTuplePartitions > 0 {
+			if err := verifyPostgresTuplePartitions(context.Background(), db, cfg.PostgresTuplePartitions); err != nil {
+				return err
+			}
+		}
 		log.Println("migration done")
 		return nil
 	}
@@ -146,7 +167,15 @@ func RunMigrations(cfg MigrationConfig) error {
 		}
 	default:
 		log.Println("nothing to do")
-		return nil
+		if cfg.PostgresTuplePartitions == 0 {
+			return nil
+		}
+	}
+
+	if cfg.PostgresTuplePartitions > 0 && targetInt64Version >= postgresTuplePartitionsVersion {
+		if err := verifyPostgresTuplePartitions(context.Background(), db, cfg.PostgresTuplePartitions); err != nil {
+			return err
+		}
 	}
 
 	log.Println("migration done")
@@ -1,13 +1,20 @@
 package migrate_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/oklog/ulid/v2"
+	"github.com/pressly/goose/v3"
 	"github.com/stretchr/testify/require"
 
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
 	"github.com/openfga/openfga/cmd/util"
+	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/migrate"
+	"github.com/openfga/openfga/pkg/tuple"
 )
 
 func TestMigrateCommandRollbacks(t *testing.T) {
@@ -43,3 +50,50 @@ func TestMigrateCommandRollbacks(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresTuplePartitions(t *testing.T) {
+	_, ds, uri := util.MustBootstrapDatastore(t, "postgres")
+	ctx := context.Background()
+
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+	require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk}))
+
+	cfg := migrate.MigrationConfig{
+		Engine:                  "postgres",
+		URI:                     uri,
+		Timeout:                 5 * time.Second,
+		PostgresTuplePartitions: 4,
+	}
+	require.NoError(t, migrate.RunMigrations(cfg))
+
+	// partitioning again is a no-op
+	require.NoError(t, migrate.RunMigrations(cfg))
+
+	// the existing tuples were copied into the partitions
+	_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+	require.NoError(t, err)
+
+	// the ulids are unique across the partitions
+	db, err := goose.OpenDBWithDriver("pgx", uri)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.ExecContext(ctx, `INSERT INTO tuple (store, object_type, object_id, relation, _user, user_type, ulid, inserted_at)
+		SELECT $1, object_type, object_id, relation, _user, user_type, ulid, inserted_at FROM tuple WHERE store = $2`,
+		ulid.Make().String(), storeID)
+	require.ErrorContains(t, err, "idx_tuple_ulid")
+
+	cfg.PostgresTuplePartitions = 8
+	require.ErrorContains(t, migrate.RunMigrations(cfg), "repartitioning it into 8 is not supported")
+
+	// the partitions cannot be merged back
+	require.ErrorContains(t, migrate.RunMigrations(migrate.MigrationConfig{
+		Engine:        "postgres",
+		URI:           uri,
+		TargetVersion: 8,
+		Timeout:       5 * time.Second,
+	}), "merging them back is not supported")
+
+	cfg.Engine = "mysql"
+	require.Error(t, migrate.RunMigrations(cfg))
+}
@@ -0,0 +1,438 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	// postgresTuplePartitionsVersion is the version of the migration partitioning the tuple table of the postgres
+	// datastores, following the SQL migrations of assets.PostgresMigrationDir.
+	postgresTuplePartitionsVersion = 9
+
+	// postgresTuplePartitionsBatchSize is the number of tuples copied into the partitions per statement.
+	postgresTuplePartitionsBatchSize = 10000
+)
+
+// indexDefinition matches the definitions of the indexes returned by pg_indexes, e.g.
+// 'CREATE UNIQUE INDEX idx_tuple_ulid ON public.tuple USING btree (ulid)'.
+var indexDefinition = regexp.MustCompile(`^CREATE (UNIQUE )?INDEX (\S+) ON \S+ USING (.*)$`)
+
+// postgresGoMigrations returns the Go migrations of the postgres datastores, run after their SQL migrations. They
+// are registered for the postgres migrations only, since goose runs the registered Go migrations whatever the
+// directory of the SQL ones.
+func postgresGoMigrations(tuplePartitions uint) []*goose.Migration {
+	partitions := goose.NewGoMigration(postgresTuplePartitionsVersion,
+		&goose.GoFunc{
+			RunDB: func(ctx context.Context, db *sql.DB) error {
+				return partitionPostgresTupleTable(ctx, db, tuplePartitions)
+			},
+			Mode: goose.TransactionDisabled,
+		},
+		&goose.GoFunc{
+			RunDB: unpartitionPostgresTupleTable,
+			Mode:  goose.TransactionDisabled,
+		},
+	)
+	partitions.Source = "009_partition_tuple_table.go"
+	return []*goose.Migration{partitions}
+}
+
+// partitionPostgresTupleTable hash-partitions the tuple table by store into the given number of partitions, named
+// tuple_p0 to tuple_p<partitions-1>, so that each of them can be maintained (e.g. vacuumed and reindexed) on its own.
+// Since every tuple query filters by store, it only reads the partition of the store. It does nothing if partitions
+// is 0.
+//
+// The partitioned table gets the keys and indexes of the tuple table, with the partition key added to its unique
+// indexes as Postgres requires, their uniqueness across the partitions being enforced by triggers. The existing
+// tuples are copied into it in batches, each of them only locking the tuples it copies, while the writes to the
+// tuple table are mirrored into it by a trigger. The tables are then swapped in a short transaction.
+//
+// It fails if the table is already partitioned, since repartitioning is not supported.
+func partitionPostgresTupleTable(ctx context.Context, db *sql.DB, partitions uint) error {
+	if partitions == 0 {
+		return nil
+	}
+
+	existing, err := postgresTuplePartitions(ctx, db)
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return fmt.Errorf("the tuple table is already partitioned into %d partitions, repartitioning it into %d is not supported", existing, partitions)
+	}
+
+	log.Printf("partitioning the tuple table into %d partitions", partitions)
+
+	primaryKey, err := postgresTuplePrimaryKey(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	// the leftovers of an interrupted partitioning
+	statements := []string{
+		`DROP TRIGGER IF EXISTS tuple_partitioning_mirror ON tuple`,
+		`DROP FUNCTION IF EXISTS tuple_partitioning_mirror()`,
+		`DROP TABLE IF EXISTS tuple_partitioned`,
+		`CREATE TABLE tuple_partitioned (LIKE tuple INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY HASH (store)`,
+	}
+	for i := uint(0); i < partitions; i++ {
+		statements = append(statements, fmt.Sprintf(
+			`CREATE TABLE tuple_p%d PARTITION OF tuple_partitioned FOR VALUES WITH (MODULUS %d, REMAINDER %d)`, i, partitions, i))
+	}
+
+	keys, renames, uniqueTriggers, err := postgresTupleKeysAndIndexes(ctx, db)
+	if err != nil {
+		return err
+	}
+	statements = append(statements, keys...)
+
+	columns := strings.Join(primaryKey, ", ")
+	oldColumns := "OLD." + strings.Join(primaryKey, ", OLD.")
+	statements = append(statements,
+		fmt.Sprintf(`CREATE FUNCTION tuple_partitioning_mirror() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		DELETE FROM tuple_partitioned WHERE (%s) = (%s);
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		INSERT INTO tuple_partitioned VALUES (NEW.*) ON CONFLICT DO NOTHING;
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`, columns, oldColumns),
+		`CREATE TRIGGER tuple_partitioning_mirror AFTER INSERT OR UPDATE OR DELETE ON tuple
+			FOR EACH ROW EXECUTE FUNCTION tuple_partitioning_mirror()`,
+	)
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to partition the tuple table: %w", err)
+		}
+	}
+
+	copied, err := copyPostgresTuples(ctx, db, primaryKey)
+	if err != nil {
+		return err
+	}
+	log.Printf("copied %d tuples into the partitions", copied)
+
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
+
+	statements = []string{
+		`DROP TRIGGER tuple_partitioning_mirror ON tuple`,
+		`DROP FUNCTION tuple_partitioning_mirror()`,
+		`DROP TABLE tuple`,
+		`ALTER TABLE tuple_partitioned RENAME TO tuple`,
+	}
+	statements = append(statements, renames...)
+	statements = append(statements, uniqueTriggers...)
+	for _, statement := range statements {
+		if _, err := txn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to partition the tuple table: %w", err)
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to partition the tuple table: %w", err)
+	}
+
+	log.Println("tuple table partitioned")
+	return nil
+}
+
+// unpartitionPostgresTupleTable reverts partitionPostgresTupleTable. It does nothing if the tuple table is not
+// partitioned, and fails otherwise since merging the partitions back is not supported.
+func unpartitionPostgresTupleTable(ctx context.Context, db *sql.DB) error {
+	existing, err := postgresTuplePartitions(ctx, db)
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return fmt.Errorf("the tuple table is partitioned into %d partitions, merging them back is not supported", existing)
+	}
+	return nil
+}
+
+// verifyPostgresTuplePartitions returns an error if the tuple table is not partitioned into the given number of
+// partitions, e.g. if the partitioning migration ran before without partitioning it.
+func verifyPostgresTuplePartitions(ctx context.Context, db *sql.DB, partitions uint) error {
+	existing, err := postgresTuplePartitions(ctx, db)
+	if err != nil {
+		return err
+	}
+	if existing == 0 {
+		return fmt.Errorf("the tuple table is not partitioned: migrate it down to version %d then up again to partition it", postgresTuplePartitionsVersion-1)
+	}
+	if existing != int(partitions) {
+		return fmt.Errorf("the tuple table is already partitioned into %d partitions, repartitioning it into %d is not supported", existing, partitions)
+	}
+	return nil
+}
+
+// postgresTuplePartitions returns the number of partitions of the tuple table, 0 if it is not partitioned.
+func postgresTuplePartitions(ctx context.Context, db *sql.DB) (int, error) {
+	var partitions int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pg_inherits
+		JOIN pg_partitioned_table ON pg_partitioned_table.partrelid = pg_inherits.inhparent
+		WHERE pg_inherits.inhparent = 'tuple'::regclass`).Scan(&partitions)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the partitions of the tuple table: %w", err)
+	}
+	return partitions, nil
+}
+
+// postgresTuplePrimaryKey returns the columns of the primary key of the tuple table, in order.
+func postgresTuplePrimaryKey(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT pg_attribute.attname FROM pg_index
+		JOIN pg_attribute ON pg_attribute.attrelid = pg_index.indrelid AND pg_attribute.attnum = ANY(pg_index.indkey)
+		WHERE pg_index.indrelid = 'tuple'::regclass AND pg_index.indisprimary
+		ORDER BY array_position(pg_index.indkey, pg_attribute.attnum)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the primary key of the tuple table: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to read the primary key of the tuple table: %w", err)
+		}
+		columns = append(columns, quoteIdentifier(column))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the primary key of the tuple table: %w", err)
+	}
+	if len(columns) == 0 {
+		return nil, errors.New("the tuple table has no primary key")
+	}
+	return columns, nil
+}
+
+// postgresTupleKeysAndIndexes returns the statements creating the keys and indexes of the tuple table, as of its
+// latest migration, on the tuple_partitioned table, suffixed with '_partitioned', and the statements renaming them
+// once the tuple table is replaced. The store is added to the unique keys and indexes, and the uniqueTriggers
+// statements enforce their uniqueness across the partitions.
+func postgresTupleKeysAndIndexes(ctx context.Context, db *sql.DB) (keys, renames, uniqueTriggers []string, err error) {
+	rows, err := db.QueryContext(ctx, `SELECT conname, pg_get_constraintdef(oid) FROM pg_constraint
+		WHERE conrelid = 'tuple'::regclass AND contype IN ('p', 'u')`)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read the keys of the tuple table: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read the keys of the tuple table: %w", err)
+		}
+
+		// e.g. 'PRIMARY KEY (store, object_type, object_id, relation, _user)'
+		definition, columns, err := withPartitionKey(definition)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to partition the key %s of the tuple table: %w", name, err)
+		}
+		keys = append(keys, fmt.Sprintf(`ALTER TABLE tuple_partitioned ADD CONSTRAINT %s %s`, quoteIdentifier(name+"_partitioned"), definition))
+		renames = append(renames, fmt.Sprintf(`ALTER TABLE tuple RENAME CONSTRAINT %s TO %s`, quoteIdentifier(name+"_partitioned"), quoteIdentifier(name)))
+		if columns != nil {
+			uniqueTriggers = append(uniqueTriggers, uniqueAcrossPartitions(name, columns)...)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read the keys of the tuple table: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, `SELECT indexname, indexdef FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = 'tuple'
+		AND indexname NOT IN (SELECT conname FROM pg_constraint WHERE conrelid = 'tuple'::regclass)`)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read the indexes of the tuple table: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read the indexes of the tuple table: %w", err)
+		}
+
+		match := indexDefinition.FindStringSubmatch(definition)
+		if match == nil {
+			return nil, nil, nil, fmt.Errorf("failed to partition the index %s of the tuple table: unsupported definition '%s'", name, definition)
+		}
+		unique, method := match[1] != "", match[3]
+
+		var columns []string
+		if unique {
+			// e.g. 'btree (ulid)'
+			if method, columns, err = withPartitionKey(method); err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to partition the index %s of the tuple table: %w", name, err)
+			}
+		}
+		keys = append(keys, fmt.Sprintf(`CREATE %sINDEX %s ON tuple_partitioned USING %s`, match[1], quoteIdentifier(name+"_partitioned"), method))
+		renames = append(renames, fmt.Sprintf(`ALTER INDEX %s RENAME TO %s`, quoteIdentifier(name+"_partitioned"), quoteIdentifier(name)))
+		if columns != nil {
+			uniqueTriggers = append(uniqueTriggers, uniqueAcrossPartitions(name, columns)...)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read the indexes of the tuple table: %w", err)
+	}
+
+	return keys, renames, uniqueTriggers, nil
+}
+
+// withPartitionKey adds the store to the first list of columns of the definition of a unique key or index, e.g.
+// 'btree (ulid)', as Postgres requires for the partitioned tables. It returns the columns of the definition if it
+// did not include the store, nil otherwise.
+func withPartitionKey(definition string) (string, []string, error) {
+	start := strings.Index(definition, "(")
+	if start < 0 {
+		return "", nil, fmt.Errorf("unsupported definition '%s'", definition)
+	}
+
+	var columns []string
+	depth, column := 0, start+1
+	for i := start; i < len(definition); i++ {
+		switch definition[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			columns = append(columns, strings.TrimSpace(definition[column:i]))
+			for _, c := range columns {
+				if c == "store" {
+					return definition, nil, nil
+				}
+				if !isIdentifier(c) {
+					return "", nil, fmt.Errorf("unsupported column '%s' in a unique definition", c)
+				}
+			}
+			return definition[:i] + ", store" + definition[i:], columns, nil
+		case ',':
+			if depth == 1 {
+				columns = append(columns, strings.TrimSpace(definition[column:i]))
+				column = i + 1
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("unsupported definition '%s'", definition)
+}
+
+// uniqueAcrossPartitions returns the statements creating the trigger enforcing that the columns of the unique key or
+// index name of the tuple table stay unique across the stores, and so across the partitions. The writes of the same
+// values are serialized by an advisory lock, so that concurrent writes to different partitions cannot both succeed.
+func uniqueAcrossPartitions(name string, columns []string) []string {
+	newColumns := "NEW." + strings.Join(columns, ", NEW.")
+	function := quoteIdentifier(name + "_unique")
+	return []string{
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+BEGIN
+	PERFORM pg_advisory_xact_lock(hashtextextended(concat_ws(',', %s), 0));
+	IF EXISTS (SELECT 1 FROM tuple WHERE (%s) = (%s) AND store <> NEW.store) THEN
+		RAISE unique_violation USING MESSAGE = format('duplicate key value violates unique constraint "%%s"', %s);
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`, function, newColumns, strings.Join(columns, ", "), newColumns, quoteLiteral(name)),
+		fmt.Sprintf(`CREATE TRIGGER %s AFTER INSERT OR UPDATE ON tuple FOR EACH ROW EXECUTE FUNCTION %s()`, function, function),
+	}
+}
+
+// copyPostgresTuples copies the tuples of the tuple table into the tuple_partitioned table in batches, in the order
+// of the primary key, and returns the number of tuples copied. Each batch locks the tuples it copies, so that they
+// cannot be deleted before they are copied.
+func copyPostgresTuples(ctx context.Context, db *sql.DB, primaryKey []string) (int64, error) {
+	columns := strings.Join(primaryKey, ", ")
+	placeholders := make([]string, len(primaryKey))
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	batch := func(after string) string {
+		return fmt.Sprintf(`WITH batch AS (
+				SELECT * FROM tuple %s ORDER BY %s LIMIT %d FOR SHARE
+			), copied AS (
+				INSERT INTO tuple_partitioned SELECT * FROM batch ON CONFLICT DO NOTHING
+			)
+			SELECT (SELECT COUNT(*) FROM batch), %s FROM batch ORDER BY %s DESC LIMIT 1`,
+			after, columns, postgresTuplePartitionsBatchSize, columns, strings.Join(descending(primaryKey), ", "))
+	}
+	first := batch("")
+	next := batch(fmt.Sprintf("WHERE (%s) > (%s)", columns, strings.Join(placeholders, ", ")))
+
+	var copied int64
+	var last []any
+	for {
+		query, args := first, []any(nil)
+		if last != nil {
+			query, args = next, last
+		}
+
+		var count int64
+		values := make([]any, len(primaryKey))
+		dest := []any{&count}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		err := db.QueryRowContext(ctx, query, args...).Scan(dest...)
+		if errors.Is(err, sql.ErrNoRows) {
+			return copied, nil
+		}
+		if err != nil {
+			return copied, fmt.Errorf("failed to copy the tuples into the partitions: %w", err)
+		}
+
+		copied += count
+		last = values
+		if count < postgresTuplePartitionsBatchSize {
+			return copied, nil
+		}
+	}
+}
+
+// descending returns the columns suffixed with DESC.
+func descending(columns []string) []string {
+	desc := make([]string, len(columns))
+	for i, column := range columns {
+		desc[i] = column + " DESC"
+	}
+	return desc
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func quoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func quoteLiteral(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `''`) + `'`
+}
@@ -0,0 +1,27 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPartitionKey(t *testing.T) {
+	definition, columns, err := withPartitionKey("btree (ulid)")
+	require.NoError(t, err)
+	require.Equal(t, "btree (ulid, store)", definition)
+	require.Equal(t, []string{"ulid"}, columns)
+
+	definition, columns, err = withPartitionKey("btree (object_type, lower(object_id)) WHERE (user_type = 'user'::text)")
+	require.ErrorContains(t, err, "unsupported column 'lower(object_id)'")
+	require.Empty(t, definition)
+	require.Nil(t, columns)
+
+	definition, columns, err = withPartitionKey("PRIMARY KEY (store, object_type, object_id, relation, _user)")
+	require.NoError(t, err)
+	require.Equal(t, "PRIMARY KEY (store, object_type, object_id, relation, _user)", definition)
+	require.Nil(t, columns)
+
+	_, _, err = withPartitionKey("btree")
+	require.Error(t, err)
+}
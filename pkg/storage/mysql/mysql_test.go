@@ -8,6 +8,8 @@ import (
 
 	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -47,6 +49,30 @@ func TestMySQLDatastoreAfterCloseIsNotReady(t *testing.T) {
 	require.False(t, status.IsReady)
 }
 
+func TestMySQLNewWithDBUnregistersCollectorsOnReplicaError(t *testing.T) {
+	testDatastore := storagefixtures.RunDatastoreTestContainer(t, "mysql")
+
+	uri := testDatastore.GetConnectionURI(true)
+	db, err := sql.Open("mysql", uri)
+	require.NoError(t, err)
+	replica, err := sql.Open("mysql", uri)
+	require.NoError(t, err)
+
+	// the collector of the replica cannot be registered
+	conflicting := collectors.NewDBStatsCollector(replica, sqlcommon.ReplicaMetricsName(0))
+	require.NoError(t, prometheus.Register(conflicting))
+
+	cfg := sqlcommon.NewConfig(sqlcommon.WithMetrics())
+	_, err = NewWithDB(db, cfg, replica)
+	require.ErrorContains(t, err, "read replica")
+
+	// the collector of the primary was unregistered, so that the next attempt succeeds
+	prometheus.Unregister(conflicting)
+	ds, err := NewWithDB(db, cfg, replica)
+	require.NoError(t, err)
+	ds.Close()
+}
+
 // TestReadEnsureNoOrder asserts that the read response is not ordered by ulid.
 func TestReadEnsureNoOrder(t *testing.T) {
 	tests := []struct {
@@ -568,6 +594,16 @@ func TestHandleSQLError(t *testing.T) {
 		err := HandleSQLError(sql.ErrNoRows)
 		require.ErrorIs(t, err, storage.ErrNotFound)
 	})
+
+	t.Run("transient_errors_are_classified", func(t *testing.T) {
+		class, ok := storage.TransientErrorClassOf(HandleSQLError(&mysqldriver.MySQLError{Number: 1213}))
+		require.True(t, ok)
+		require.Equal(t, storage.TransientDeadlock, class)
+
+		class, ok = storage.TransientErrorClassOf(HandleSQLError(mysqldriver.ErrInvalidConn))
+		require.True(t, ok)
+		require.Equal(t, storage.TransientConnection, class)
+	})
 }
 
 func TestNew(t *testing.T) {
@@ -1,2 +1,10 @@
 // Package postgres contains an implementation of the storage interface that works with Postgres.
+//
+// The datastore uses the pgx driver, which prepares and caches the statements of each connection and exchanges
+// their arguments and results in the binary format. The cache can be tuned with the 'statement_cache_capacity'
+// and 'default_query_exec_mode' parameters of the connection uri, e.g. 'default_query_exec_mode=exec' behind
+// connection poolers not supporting prepared statements. The statements of a write are sent in a single batch.
+//
+// The tuple table can be hash-partitioned by store with the 'postgres-tuple-partitions' flag of the migrate command,
+// by migration 9. Every tuple query filters by store, so that it only reads the partition of the store.
 package postgres
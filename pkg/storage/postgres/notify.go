@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/storage"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+// Ensures that Datastore implements the TupleChangeListener interface.
+var _ storage.TupleChangeListener = (*Datastore)(nil)
+
+// tupleChangePayload is the payload of the notifications published on the notification channel.
+type tupleChangePayload struct {
+	StoreID    string `json:"store_id"`
+	ObjectType string `json:"object_type"`
+}
+
+// tupleChangePayloads returns the payloads of the notifications of a write, one per object type of its tuples, if a
+// notification channel is configured. They are published by the transaction of the write, so that they are only
+// delivered once it is committed, and always are.
+func (s *Datastore) tupleChangePayloads(store string, deletes storage.Deletes, writes storage.Writes) ([]string, error) {
+	if s.notificationChannel == "" {
+		return nil, nil
+	}
+
+	objectTypes := make(map[string]struct{})
+	for _, tk := range deletes {
+		objectTypes[tupleUtils.GetType(tk.GetObject())] = struct{}{}
+	}
+	for _, tk := range writes {
+		objectTypes[tupleUtils.GetType(tk.GetObject())] = struct{}{}
+	}
+
+	payloads := make([]string, 0, len(objectTypes))
+	for _, objectType := range slices.Sorted(maps.Keys(objectTypes)) {
+		payload, err := json.Marshal(tupleChangePayload{StoreID: store, ObjectType: objectType})
+		if err != nil {
+			return nil, fmt.Errorf("encode tuple change notification: %w", err)
+		}
+		payloads = append(payloads, string(payload))
+	}
+	return payloads, nil
+}
+
+// ListenTupleChanges see [storage.TupleChangeListener].ListenTupleChanges. The notifications are received on a
+// dedicated connection of the pool, listening to the notification channel.
+func (s *Datastore) ListenTupleChanges(ctx context.Context, handler func(storage.TupleChangeNotification)) error {
+	if s.notificationChannel == "" {
+		return errors.New("postgres notification channel is not configured")
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return HandleSQLError(err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+pgx.Identifier{s.notificationChannel}.Sanitize()); err != nil {
+			return fmt.Errorf("listen to postgres notification channel: %w", err)
+		}
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("wait for postgres notification: %w", err)
+			}
+
+			var payload tupleChangePayload
+			if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+				s.logger.Warn("ignoring malformed tuple change notification", zap.String("payload", notification.Payload))
+				continue
+			}
+			handler(storage.TupleChangeNotification{StoreID: payload.StoreID, ObjectType: payload.ObjectType})
+		}
+	})
+}
@@ -3,18 +3,24 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/cenkalti/backoff/v4"
-	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver.
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
@@ -32,16 +38,20 @@ import (
 var tracer = otel.Tracer("openfga/pkg/storage/postgres")
 
 func startTrace(ctx context.Context, name string) (context.Context, trace.Span) {
-	return tracer.Start(ctx, "postgres."+name)
+	return tracer.Start(ctx, "postgres."+name, trace.WithAttributes(attribute.String(sqlcommon.OperationAttribute, name)))
 }
 
 // Datastore provides a PostgreSQL based implementation of [storage.OpenFGADatastore].
 type Datastore struct {
 	stbl                   sq.StatementBuilderType
+	readReplicas           *sqlcommon.ReadReplicas
 	db                     *sql.DB
+	replicaDBs             []*sql.DB
 	dbInfo                 *sqlcommon.DBInfo
 	logger                 logger.Logger
-	dbStatsCollector       prometheus.Collector
+	dbStatsCollectors      []prometheus.Collector
+	queryMetrics           *sqlcommon.QueryMetrics
+	notificationChannel    string
 	maxTuplesPerWriteField int
 	maxTypesPerModelField  int
 }
@@ -51,6 +61,33 @@ var _ storage.OpenFGADatastore = (*Datastore)(nil)
 
 // New creates a new [Datastore] storage.
 func New(uri string, cfg *sqlcommon.Config) (*Datastore, error) {
+	db, err := open(uri, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]*sql.DB, 0, len(cfg.ReadReplicaURIs))
+	for _, replicaURI := range cfg.ReadReplicaURIs {
+		replica, err := open(replicaURI, cfg)
+		if err != nil {
+			db.Close()
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
+		replicas = append(replicas, replica)
+	}
+
+	return NewWithDB(db, cfg, replicas...)
+}
+
+// open opens a connection to uri, with the username and password of cfg if set.
+func open(uri string, cfg *sqlcommon.Config) (*sql.DB, error) {
+	if cfg.CredentialsProvider != nil || cfg.AuthTokenProvider != nil {
+		return openWithCredentialsProvider(uri, cfg)
+	}
+
 	if cfg.Username != "" || cfg.Password != "" {
 		parsed, err := url.Parse(uri)
 		if err != nil {
@@ -84,11 +121,102 @@ func New(uri string, cfg *sqlcommon.Config) (*Datastore, error) {
 	if err != nil {
 		return nil, fmt.Errorf("initialize postgres connection: %w", err)
 	}
-	return NewWithDB(db, cfg)
+	return db, nil
+}
+
+// openWithCredentialsProvider opens a connection to uri whose connections are opened with the current credentials of
+// cfg, and a new authentication token as password if cfg has an AuthTokenProvider. The connections opened with
+// previous credentials are closed instead of being reused once released, so that the requests using them complete.
+func openWithCredentialsProvider(uri string, cfg *sqlcommon.Config) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres connection uri: %w", err)
+	}
+
+	opts := []stdlib.OptionOpenDB{
+		stdlib.OptionBeforeConnect(func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+			credentials := cfg.CurrentCredentials()
+			if credentials.Username != "" {
+				connConfig.User = credentials.Username
+			}
+			if credentials.Password != "" {
+				connConfig.Password = credentials.Password
+			}
+
+			endpoint := net.JoinHostPort(connConfig.Host, strconv.Itoa(int(connConfig.Port)))
+			password, err := cfg.ConnectPassword(ctx, endpoint, connConfig.User, connConfig.Password)
+			if err != nil {
+				return err
+			}
+			connConfig.Password = password
+			return nil
+		}),
+	}
+	if cfg.CredentialsProvider != nil {
+		opts = append(opts, stdlib.OptionResetSession(func(_ context.Context, conn *pgx.Conn) error {
+			credentials := cfg.CurrentCredentials()
+			connConfig := conn.Config()
+			if (credentials.Username != "" && credentials.Username != connConfig.User) ||
+				(credentials.Password != "" && credentials.Password != connConfig.Password) {
+				return driver.ErrBadConn
+			}
+			return nil
+		}))
+	}
+	return stdlib.OpenDB(*connConfig, opts...), nil
+}
+
+// NewWithDB creates a new [Datastore] storage with the provided database connection. The tuple reads not requiring
+// HIGHER_CONSISTENCY are spread across the replicas connections, if any.
+func NewWithDB(db *sql.DB, cfg *sqlcommon.Config, replicas ...*sql.DB) (*Datastore, error) {
+	collector, err := prepareDB(db, cfg, "openfga")
+	if err != nil {
+		return nil, err
+	}
+	statsCollectors := []prometheus.Collector{collector}
+
+	replicaStbls := make([]sq.StatementBuilderType, 0, len(replicas))
+	for i, replica := range replicas {
+		collector, err := prepareDB(replica, cfg, sqlcommon.ReplicaMetricsName(i))
+		if err != nil {
+			// so that the collectors can be registered again by the next attempt
+			unregisterCollectors(statsCollectors)
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
+		statsCollectors = append(statsCollectors, collector)
+		replicaStbls = append(replicaStbls, sq.StatementBuilder.PlaceholderFormat(sq.Dollar).RunWith(replica))
+	}
+
+	stbl := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).RunWith(db)
+	dbInfo := sqlcommon.NewDBInfo(db, stbl, HandleSQLError, "postgres")
+
+	return &Datastore{
+		stbl:                   stbl,
+		readReplicas:           sqlcommon.NewReadReplicas(stbl, replicaStbls...),
+		db:                     db,
+		replicaDBs:             replicas,
+		dbInfo:                 dbInfo,
+		logger:                 cfg.Logger,
+		dbStatsCollectors:      statsCollectors,
+		queryMetrics:           sqlcommon.NewQueryMetrics("postgres", cfg),
+		notificationChannel:    cfg.NotificationChannel,
+		maxTuplesPerWriteField: cfg.MaxTuplesPerWriteField,
+		maxTypesPerModelField:  cfg.MaxTypesPerModelField,
+	}, nil
+}
+
+// unregisterCollectors unregisters the collectors of the pool statistics returned by prepareDB.
+func unregisterCollectors(collectors []prometheus.Collector) {
+	for _, collector := range collectors {
+		if collector != nil {
+			prometheus.Unregister(collector)
+		}
+	}
 }
 
-// NewWithDB creates a new [Datastore] storage with the provided database connection.
-func NewWithDB(db *sql.DB, cfg *sqlcommon.Config) (*Datastore, error) {
+// prepareDB configures the connection pool of db, waits for the database to be reachable and, if metrics are enabled,
+// registers a collector of the pool statistics labelled with name.
+func prepareDB(db *sql.DB, cfg *sqlcommon.Config, name string) (prometheus.Collector, error) {
 	if cfg.MaxOpenConns != 0 {
 		db.SetMaxOpenConns(cfg.MaxOpenConns)
 	}
@@ -111,7 +239,7 @@ func NewWithDB(db *sql.DB, cfg *sqlcommon.Config) (*Datastore, error) {
 	err := backoff.Retry(func() error {
 		err := db.PingContext(context.Background())
 		if err != nil {
-			cfg.Logger.Info("waiting for database", zap.Int("attempt", attempt))
+			cfg.Logger.Info("waiting for database", zap.String("db", name), zap.Int("attempt", attempt))
 			attempt++
 			return err
 		}
@@ -121,32 +249,22 @@ func NewWithDB(db *sql.DB, cfg *sqlcommon.Config) (*Datastore, error) {
 		return nil, fmt.Errorf("ping db: %w", err)
 	}
 
-	var collector prometheus.Collector
-	if cfg.ExportMetrics {
-		collector = collectors.NewDBStatsCollector(db, "openfga")
-		if err := prometheus.Register(collector); err != nil {
-			return nil, fmt.Errorf("initialize metrics: %w", err)
-		}
+	if !cfg.ExportMetrics {
+		return nil, nil
 	}
 
-	stbl := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).RunWith(db)
-	dbInfo := sqlcommon.NewDBInfo(db, stbl, HandleSQLError, "postgres")
-
-	return &Datastore{
-		stbl:                   stbl,
-		db:                     db,
-		dbInfo:                 dbInfo,
-		logger:                 cfg.Logger,
-		dbStatsCollector:       collector,
-		maxTuplesPerWriteField: cfg.MaxTuplesPerWriteField,
-		maxTypesPerModelField:  cfg.MaxTypesPerModelField,
-	}, nil
+	collector := collectors.NewDBStatsCollector(db, name)
+	if err := prometheus.Register(collector); err != nil {
+		return nil, fmt.Errorf("initialize metrics: %w", err)
+	}
+	return collector, nil
 }
 
 // Close see [storage.OpenFGADatastore].Close.
 func (s *Datastore) Close() {
-	if s.dbStatsCollector != nil {
-		prometheus.Unregister(s.dbStatsCollector)
+	unregisterCollectors(s.dbStatsCollectors)
+	for _, replica := range s.replicaDBs {
+		replica.Close()
 	}
 	s.db.Close()
 }
@@ -156,33 +274,48 @@ func (s *Datastore) Read(
 	ctx context.Context,
 	store string,
 	tupleKey *openfgav1.TupleKey,
-	_ storage.ReadOptions,
+	options storage.ReadOptions,
 ) (storage.TupleIterator, error) {
 	ctx, span := startTrace(ctx, "Read")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
 
-	return s.read(ctx, store, tupleKey, nil)
+	iter, err := s.read(ctx, store, tupleKey, options.Consistency.Preference, nil)
+	if err != nil {
+		return nil, err
+	}
+	return iter.WithQueryMetrics(s.queryMetrics, sqlcommon.QueryOperationRead), nil
 }
 
 // ReadPage see [storage.RelationshipTupleReader].ReadPage.
 func (s *Datastore) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
 	ctx, span := startTrace(ctx, "ReadPage")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadPage, time.Now())
 
-	iter, err := s.read(ctx, store, tupleKey, &options)
+	iter, err := s.read(ctx, store, tupleKey, options.Consistency.Preference, &options)
 	if err != nil {
 		return nil, "", err
 	}
 	defer iter.Stop()
 
-	return iter.ToArray(ctx, options.Pagination)
+	tuples, token, err := iter.ToArray(ctx, options.Pagination)
+	if err != nil {
+		return nil, "", err
+	}
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(tuples)))
+
+	return tuples, token, nil
 }
 
-func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options *storage.ReadPageOptions) (*sqlcommon.SQLTupleIterator, error) {
+func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, consistency openfgav1.ConsistencyPreference, options *storage.ReadPageOptions) (*sqlcommon.SQLTupleIterator, error) {
 	_, span := startTrace(ctx, "read")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
 
-	sb := s.stbl.
+	sb := s.readReplicas.StatementBuilder(consistency).
 		Select(
 			"store", "object_type", "object_id", "relation",
 			"_user",
@@ -207,6 +340,12 @@ func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.
 	if tupleKey.GetUser() != "" {
 		sb = sb.Where(sq.Eq{"_user": tupleKey.GetUser()})
 	}
+	if options != nil && len(options.Relations) > 0 {
+		sb = sb.Where(sq.Eq{"relation": options.Relations})
+	}
+	if options != nil && options.UserType != "" {
+		sb = sb.Where(sqlcommon.UserTypeClause(options.UserType))
+	}
 
 	if options != nil && options.Pagination.From != "" {
 		sb = sb.Where(sq.GtOrEq{"ulid": options.Pagination.From})
@@ -227,14 +366,22 @@ func (s *Datastore) Write(
 ) error {
 	ctx, span := startTrace(ctx, "Write")
 	defer span.End()
-
-	return sqlcommon.Write(ctx, s.dbInfo, store, deletes, writes, time.Now().UTC())
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(
+		attribute.Int(sqlcommon.DeletesAttribute, len(deletes)),
+		attribute.Int(sqlcommon.WritesAttribute, len(writes)),
+	)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationWrite, time.Now())
+
+	return s.write(ctx, store, deletes, writes, time.Now().UTC())
 }
 
 // ReadUserTuple see [storage.RelationshipTupleReader].ReadUserTuple.
-func (s *Datastore) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, _ storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+func (s *Datastore) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
 	ctx, span := startTrace(ctx, "ReadUserTuple")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadUserTuple, time.Now())
 
 	objectType, objectID := tupleUtils.SplitObject(tupleKey.GetObject())
 	userType := tupleUtils.GetUserTypeFromUser(tupleKey.GetUser())
@@ -243,7 +390,7 @@ func (s *Datastore) ReadUserTuple(ctx context.Context, store string, tupleKey *o
 	var conditionContext []byte
 	var record storage.TupleRecord
 
-	err := s.stbl.
+	err := s.readReplicas.StatementBuilder(options.Consistency.Preference).
 		Select(
 			"object_type", "object_id", "relation",
 			"_user",
@@ -286,17 +433,69 @@ func (s *Datastore) ReadUserTuple(ctx context.Context, store string, tupleKey *o
 	return record.AsTuple(), nil
 }
 
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (s *Datastore) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	ctx, span := startTrace(ctx, "ReadUserTuples")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(attribute.Int(sqlcommon.TupleKeysAttribute, len(tupleKeys)))
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadUserTuples, time.Now())
+
+	if len(tupleKeys) == 0 {
+		return nil, nil
+	}
+
+	orConditions := sq.Or{}
+	for _, tupleKey := range tupleKeys {
+		objectType, objectID := tupleUtils.SplitObject(tupleKey.GetObject())
+		orConditions = append(orConditions, sq.Eq{
+			"object_type": objectType,
+			"object_id":   objectID,
+			"relation":    tupleKey.GetRelation(),
+			"_user":       tupleKey.GetUser(),
+			"user_type":   tupleUtils.GetUserTypeFromUser(tupleKey.GetUser()),
+		})
+	}
+
+	sb := s.readReplicas.StatementBuilder(options.Consistency.Preference).
+		Select(
+			"store", "object_type", "object_id", "relation",
+			"_user",
+			"condition_name", "condition_context", "ulid", "inserted_at",
+		).
+		From("tuple").
+		Where(sq.Eq{"store": store}).
+		Where(orConditions)
+
+	iter := sqlcommon.NewSQLTupleIterator(sb, HandleSQLError)
+	defer iter.Stop()
+
+	var tuples []*openfgav1.Tuple
+	for {
+		t, err := iter.Next(ctx)
+		if err != nil {
+			if errors.Is(err, storage.ErrIteratorDone) {
+				span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(tuples)))
+				return tuples, nil
+			}
+			return nil, err
+		}
+		tuples = append(tuples, t)
+	}
+}
+
 // ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
 func (s *Datastore) ReadUsersetTuples(
 	ctx context.Context,
 	store string,
 	filter storage.ReadUsersetTuplesFilter,
-	_ storage.ReadUsersetTuplesOptions,
+	options storage.ReadUsersetTuplesOptions,
 ) (storage.TupleIterator, error) {
 	_, span := startTrace(ctx, "ReadUsersetTuples")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleUtils.NewTupleKey(filter.Object, filter.Relation, ""))...)
 
-	sb := s.stbl.
+	sb := s.readReplicas.StatementBuilder(options.Consistency.Preference).
 		Select(
 			"store", "object_type", "object_id", "relation",
 			"_user",
@@ -333,7 +532,7 @@ func (s *Datastore) ReadUsersetTuples(
 		sb = sb.Where(orConditions)
 	}
 
-	return sqlcommon.NewSQLTupleIterator(sb, HandleSQLError), nil
+	return sqlcommon.NewSQLTupleIterator(sb, HandleSQLError).WithQueryMetrics(s.queryMetrics, sqlcommon.QueryOperationReadUsersetTuples), nil
 }
 
 // ReadStartingWithUser see [storage.RelationshipTupleReader].ReadStartingWithUser.
@@ -341,10 +540,15 @@ func (s *Datastore) ReadStartingWithUser(
 	ctx context.Context,
 	store string,
 	filter storage.ReadStartingWithUserFilter,
-	_ storage.ReadStartingWithUserOptions,
+	options storage.ReadStartingWithUserOptions,
 ) (storage.TupleIterator, error) {
 	_, span := startTrace(ctx, "ReadStartingWithUser")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(
+		attribute.String(sqlcommon.ObjectTypeAttribute, filter.ObjectType),
+		attribute.String(sqlcommon.RelationAttribute, filter.Relation),
+	)
 
 	var targetUsersArg []string
 	for _, u := range filter.UserFilter {
@@ -355,7 +559,7 @@ func (s *Datastore) ReadStartingWithUser(
 		targetUsersArg = append(targetUsersArg, targetUser)
 	}
 
-	builder := s.stbl.
+	builder := s.readReplicas.StatementBuilder(options.Consistency.Preference).
 		Select(
 			"store", "object_type", "object_id", "relation",
 			"_user",
@@ -373,7 +577,7 @@ func (s *Datastore) ReadStartingWithUser(
 		builder = builder.Where(sq.Eq{"object_id": filter.ObjectIDs.Values()})
 	}
 
-	return sqlcommon.NewSQLTupleIterator(builder, HandleSQLError), nil
+	return sqlcommon.NewSQLTupleIterator(builder, HandleSQLError).WithQueryMetrics(s.queryMetrics, sqlcommon.QueryOperationReadStartingWithUser), nil
 }
 
 // MaxTuplesPerWrite see [storage.RelationshipTupleWriter].MaxTuplesPerWrite.
@@ -385,6 +589,7 @@ func (s *Datastore) MaxTuplesPerWrite() int {
 func (s *Datastore) ReadAuthorizationModel(ctx context.Context, store string, modelID string) (*openfgav1.AuthorizationModel, error) {
 	ctx, span := startTrace(ctx, "ReadAuthorizationModel")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	return sqlcommon.ReadAuthorizationModel(ctx, s.dbInfo, store, modelID)
 }
@@ -393,6 +598,8 @@ func (s *Datastore) ReadAuthorizationModel(ctx context.Context, store string, mo
 func (s *Datastore) ReadAuthorizationModels(ctx context.Context, store string, options storage.ReadAuthorizationModelsOptions) ([]*openfgav1.AuthorizationModel, string, error) {
 	ctx, span := startTrace(ctx, "ReadAuthorizationModels")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
 
 	sb := s.stbl.
 		Select("authorization_model_id").
@@ -449,6 +656,8 @@ func (s *Datastore) ReadAuthorizationModels(ctx context.Context, store string, o
 		models = append(models, model)
 	}
 
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(models)))
+
 	return models, token, nil
 }
 
@@ -456,6 +665,7 @@ func (s *Datastore) ReadAuthorizationModels(ctx context.Context, store string, o
 func (s *Datastore) FindLatestAuthorizationModel(ctx context.Context, store string) (*openfgav1.AuthorizationModel, error) {
 	ctx, span := startTrace(ctx, "FindLatestAuthorizationModel")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	return sqlcommon.FindLatestAuthorizationModel(ctx, s.dbInfo, store)
 }
@@ -469,29 +679,69 @@ func (s *Datastore) MaxTypesPerAuthorizationModel() int {
 func (s *Datastore) WriteAuthorizationModel(ctx context.Context, store string, model *openfgav1.AuthorizationModel) error {
 	ctx, span := startTrace(ctx, "WriteAuthorizationModel")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	return sqlcommon.WriteAuthorizationModel(ctx, s.dbInfo, store, model)
 }
 
+// DeleteAuthorizationModel see [storage.TypeDefinitionWriteBackend].DeleteAuthorizationModel.
+func (s *Datastore) DeleteAuthorizationModel(ctx context.Context, store string, id string) error {
+	ctx, span := startTrace(ctx, "DeleteAuthorizationModel")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+
+	return sqlcommon.DeleteAuthorizationModel(ctx, s.dbInfo, store, id)
+}
+
 // CreateStore adds a new store to storage.
 func (s *Datastore) CreateStore(ctx context.Context, store *openfgav1.Store) (*openfgav1.Store, error) {
 	ctx, span := startTrace(ctx, "CreateStore")
 	defer span.End()
 
+	return s.createStore(ctx, store, nil)
+}
+
+// CreateStoreWithLabels see [storage.StoresBackend].CreateStoreWithLabels.
+func (s *Datastore) CreateStoreWithLabels(ctx context.Context, store *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
+	ctx, span := startTrace(ctx, "CreateStoreWithLabels")
+	defer span.End()
+
+	return s.createStore(ctx, store, labels)
+}
+
+// createStore inserts the store and its labels in a single transaction.
+func (s *Datastore) createStore(ctx context.Context, store *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
 	var id, name string
 	var createdAt, updatedAt time.Time
 
-	err := s.stbl.
+	txn, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, HandleSQLError(err)
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
+
+	err = s.stbl.
 		Insert("store").
 		Columns("id", "name", "created_at", "updated_at").
 		Values(store.GetId(), store.GetName(), sq.Expr("NOW()"), sq.Expr("NOW()")).
 		Suffix("returning id, name, created_at, updated_at").
+		RunWith(txn).
 		QueryRowContext(ctx).
 		Scan(&id, &name, &createdAt, &updatedAt)
 	if err != nil {
 		return nil, HandleSQLError(err)
 	}
 
+	if err := sqlcommon.InsertStoreLabels(ctx, s.stbl, txn, id, labels); err != nil {
+		return nil, HandleSQLError(err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, HandleSQLError(err)
+	}
+
 	return &openfgav1.Store{
 		Id:        id,
 		Name:      name,
@@ -504,6 +754,7 @@ func (s *Datastore) CreateStore(ctx context.Context, store *openfgav1.Store) (*o
 func (s *Datastore) GetStore(ctx context.Context, id string) (*openfgav1.Store, error) {
 	ctx, span := startTrace(ctx, "GetStore")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
 
 	row := s.stbl.
 		Select("id", "name", "created_at", "updated_at").
@@ -536,6 +787,7 @@ func (s *Datastore) GetStore(ctx context.Context, id string) (*openfgav1.Store,
 func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOptions) ([]*openfgav1.Store, string, error) {
 	ctx, span := startTrace(ctx, "ListStores")
 	defer span.End()
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
 
 	whereClause := sq.And{
 		sq.Eq{"deleted_at": nil},
@@ -549,6 +801,10 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 		whereClause = append(whereClause, sq.Eq{"name": options.Name})
 	}
 
+	if len(options.Labels) > 0 {
+		whereClause = append(whereClause, sqlcommon.StoreLabelsClause(options.Labels))
+	}
+
 	if options.Pagination.From != "" {
 		whereClause = append(whereClause, sq.GtOrEq{"id": options.Pagination.From})
 	}
@@ -592,8 +848,10 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 	}
 
 	if len(stores) > options.Pagination.PageSize {
+		span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, options.Pagination.PageSize))
 		return stores[:options.Pagination.PageSize], id, nil
 	}
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(stores)))
 
 	return stores, "", nil
 }
@@ -602,23 +860,104 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 func (s *Datastore) DeleteStore(ctx context.Context, id string) error {
 	ctx, span := startTrace(ctx, "DeleteStore")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
+
+	txn, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return HandleSQLError(err)
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
 
-	_, err := s.stbl.
+	_, err = s.stbl.
 		Update("store").
 		Set("deleted_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": id}).
+		RunWith(txn).
 		ExecContext(ctx)
 	if err != nil {
 		return HandleSQLError(err)
 	}
 
+	if err := sqlcommon.DeleteStoreLabels(ctx, s.stbl, txn, id); err != nil {
+		return HandleSQLError(err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return HandleSQLError(err)
+	}
+
 	return nil
 }
 
+// WriteStoreLabels see [storage.StoresBackend].WriteStoreLabels.
+func (s *Datastore) WriteStoreLabels(ctx context.Context, id string, labels map[string]string) error {
+	ctx, span := startTrace(ctx, "WriteStoreLabels")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
+
+	return sqlcommon.WriteStoreLabels(ctx, s.dbInfo, id, labels)
+}
+
+// ReadStoreLabels see [storage.StoresBackend].ReadStoreLabels.
+func (s *Datastore) ReadStoreLabels(ctx context.Context, id string) (map[string]string, error) {
+	ctx, span := startTrace(ctx, "ReadStoreLabels")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
+
+	return sqlcommon.ReadStoreLabels(ctx, s.dbInfo, id)
+}
+
+// CreateJob see [storage.JobsBackend].CreateJob.
+func (s *Datastore) CreateJob(ctx context.Context, job *storage.Job) error {
+	ctx, span := startTrace(ctx, "CreateJob")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(job.StoreID)...)
+
+	return sqlcommon.CreateJob(ctx, s.dbInfo, job)
+}
+
+// ReadJob see [storage.JobsBackend].ReadJob.
+func (s *Datastore) ReadJob(ctx context.Context, store, id string) (*storage.Job, error) {
+	ctx, span := startTrace(ctx, "ReadJob")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+
+	return sqlcommon.ReadJob(ctx, s.dbInfo, store, id)
+}
+
+// UpdateJob see [storage.JobsBackend].UpdateJob.
+func (s *Datastore) UpdateJob(ctx context.Context, job *storage.Job) error {
+	ctx, span := startTrace(ctx, "UpdateJob")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(job.StoreID)...)
+
+	return sqlcommon.UpdateJob(ctx, s.dbInfo, job)
+}
+
+// RequestJobCancellation see [storage.JobsBackend].RequestJobCancellation.
+func (s *Datastore) RequestJobCancellation(ctx context.Context, store, id string) error {
+	ctx, span := startTrace(ctx, "RequestJobCancellation")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+
+	return sqlcommon.RequestJobCancellation(ctx, s.dbInfo, store, id)
+}
+
+// ClaimInterruptedJobs see [storage.JobsBackend].ClaimInterruptedJobs.
+func (s *Datastore) ClaimInterruptedJobs(ctx context.Context) ([]*storage.Job, error) {
+	ctx, span := startTrace(ctx, "ClaimInterruptedJobs")
+	defer span.End()
+
+	return sqlcommon.ClaimInterruptedJobs(ctx, s.dbInfo)
+}
+
 // WriteAssertions see [storage.AssertionsBackend].WriteAssertions.
 func (s *Datastore) WriteAssertions(ctx context.Context, store, modelID string, assertions []*openfgav1.Assertion) error {
 	ctx, span := startTrace(ctx, "WriteAssertions")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	marshalledAssertions, err := proto.Marshal(&openfgav1.Assertions{Assertions: assertions})
 	if err != nil {
@@ -642,6 +981,7 @@ func (s *Datastore) WriteAssertions(ctx context.Context, store, modelID string,
 func (s *Datastore) ReadAssertions(ctx context.Context, store, modelID string) ([]*openfgav1.Assertion, error) {
 	ctx, span := startTrace(ctx, "ReadAssertions")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	var marshalledAssertions []byte
 	err := s.stbl.
@@ -673,6 +1013,10 @@ func (s *Datastore) ReadAssertions(ctx context.Context, store, modelID string) (
 func (s *Datastore) ReadChanges(ctx context.Context, store string, filter storage.ReadChangesFilter, options storage.ReadChangesOptions) ([]*openfgav1.TupleChange, string, error) {
 	ctx, span := startTrace(ctx, "ReadChanges")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(attribute.String(sqlcommon.ObjectTypeAttribute, filter.ObjectType))
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadChanges, time.Now())
 
 	objectTypeFilter := filter.ObjectType
 	horizonOffset := filter.HorizonOffset
@@ -697,6 +1041,12 @@ func (s *Datastore) ReadChanges(ctx context.Context, store string, filter storag
 	if objectTypeFilter != "" {
 		sb = sb.Where(sq.Eq{"object_type": objectTypeFilter})
 	}
+	if filter.ObjectID != "" {
+		sb = sb.Where(sq.Eq{"object_id": filter.ObjectID})
+	}
+	if filter.User != "" {
+		sb = sb.Where(sq.Eq{"_user": filter.User})
+	}
 	if options.Pagination.From != "" {
 		sb = sqlcommon.AddFromUlid(sb, options.Pagination.From, options.SortDesc)
 	}
@@ -762,6 +1112,8 @@ func (s *Datastore) ReadChanges(ctx context.Context, store string, filter storag
 		return nil, "", storage.ErrNotFound
 	}
 
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(changes)))
+
 	return changes, ulid, nil
 }
 
@@ -786,5 +1138,19 @@ func HandleSQLError(err error, args ...interface{}) error {
 		return storage.ErrCollision
 	}
 
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001": // serialization_failure
+			return fmt.Errorf("sql error: %w", &storage.TransientError{Class: storage.TransientSerialization, Err: err})
+		case "40P01": // deadlock_detected
+			return fmt.Errorf("sql error: %w", &storage.TransientError{Class: storage.TransientDeadlock, Err: err})
+		}
+	}
+
+	if sqlcommon.IsConnectionError(err) {
+		return fmt.Errorf("sql error: %w", &storage.TransientError{Class: storage.TransientConnection, Err: err})
+	}
+
 	return fmt.Errorf("sql error: %w", err)
 }
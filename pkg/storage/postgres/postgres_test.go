@@ -3,11 +3,15 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
@@ -44,6 +48,103 @@ func TestPostgresDatastoreAfterCloseIsNotReady(t *testing.T) {
 	require.False(t, status.IsReady)
 }
 
+func TestPostgresNewWithDBUnregistersCollectorsOnReplicaError(t *testing.T) {
+	testDatastore := storagefixtures.RunDatastoreTestContainer(t, "postgres")
+
+	uri := testDatastore.GetConnectionURI(true)
+	db, err := sql.Open("pgx", uri)
+	require.NoError(t, err)
+	replica, err := sql.Open("pgx", uri)
+	require.NoError(t, err)
+
+	// the collector of the replica cannot be registered
+	conflicting := collectors.NewDBStatsCollector(replica, sqlcommon.ReplicaMetricsName(0))
+	require.NoError(t, prometheus.Register(conflicting))
+
+	cfg := sqlcommon.NewConfig(sqlcommon.WithMetrics())
+	_, err = NewWithDB(db, cfg, replica)
+	require.ErrorContains(t, err, "read replica")
+
+	// the collector of the primary was unregistered, so that the next attempt succeeds
+	prometheus.Unregister(conflicting)
+	ds, err := NewWithDB(db, cfg, replica)
+	require.NoError(t, err)
+	ds.Close()
+}
+
+func TestWriteBatch(t *testing.T) {
+	testDatastore := storagefixtures.RunDatastoreTestContainer(t, "postgres")
+
+	uri := testDatastore.GetConnectionURI(true)
+	ds, err := New(uri, sqlcommon.NewConfig())
+	require.NoError(t, err)
+	defer ds.Close()
+
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	writes := []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKeyWithCondition("document:2", "viewer", "user:anne", "condX", nil),
+	}
+	require.NoError(t, ds.Write(ctx, storeID, nil, writes))
+
+	// the tuples and their changes share their ulid
+	var mismatches int
+	err = ds.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tuple t LEFT JOIN changelog c ON c.store = t.store AND c.ulid = t.ulid
+		WHERE t.store = $1 AND c.ulid IS NULL`, storeID).Scan(&mismatches)
+	require.NoError(t, err)
+	require.Zero(t, mismatches)
+
+	t.Run("failed_statement_rolls_back_the_batch", func(t *testing.T) {
+		err := ds.Write(ctx, storeID,
+			[]*openfgav1.TupleKeyWithoutCondition{tuple.TupleKeyToTupleKeyWithoutCondition(writes[0])},
+			[]*openfgav1.TupleKey{writes[1]})
+		require.ErrorIs(t, err, storage.ErrInvalidWriteInput)
+
+		_, err = ds.ReadUserTuple(ctx, storeID, writes[0], storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func TestListenTupleChanges(t *testing.T) {
+	testDatastore := storagefixtures.RunDatastoreTestContainer(t, "postgres")
+
+	uri := testDatastore.GetConnectionURI(true)
+	ds, err := New(uri, sqlcommon.NewConfig(sqlcommon.WithNotificationChannel("openfga_tuple_changes")))
+	require.NoError(t, err)
+	defer ds.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	notifications := make(chan storage.TupleChangeNotification, 1)
+	listening := make(chan error, 1)
+	go func() {
+		listening <- ds.ListenTupleChanges(ctx, func(notification storage.TupleChangeNotification) {
+			select {
+			case notifications <- notification:
+			default:
+			}
+		})
+	}()
+
+	storeID := ulid.Make().String()
+	// the listener may not be listening yet, so writes are retried until a notification is received
+	var received storage.TupleChangeNotification
+	require.Eventually(t, func() bool {
+		err := ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tuple.NewTupleKey("document:"+ulid.Make().String(), "viewer", "user:anne")})
+		require.NoError(t, err)
+		select {
+		case received = <-notifications:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Equal(t, storage.TupleChangeNotification{StoreID: storeID, ObjectType: "document"}, received)
+
+	cancel()
+	require.NoError(t, <-listening)
+}
+
 // TestReadEnsureNoOrder asserts that the read response is not ordered by ulid.
 func TestReadEnsureNoOrder(t *testing.T) {
 	tests := []struct {
@@ -556,4 +657,19 @@ func TestHandleSQLError(t *testing.T) {
 		err := HandleSQLError(sql.ErrNoRows)
 		require.ErrorIs(t, err, storage.ErrNotFound)
 	})
+
+	t.Run("transient_errors_are_classified", func(t *testing.T) {
+		for err, expected := range map[error]storage.TransientErrorClass{
+			&pgconn.PgError{Code: "40001"}: storage.TransientSerialization,
+			&pgconn.PgError{Code: "40P01"}: storage.TransientDeadlock,
+			driver.ErrBadConn:              storage.TransientConnection,
+		} {
+			class, ok := storage.TransientErrorClassOf(HandleSQLError(err))
+			require.True(t, ok)
+			require.Equal(t, expected, class)
+		}
+
+		_, ok := storage.TransientErrorClassOf(HandleSQLError(&pgconn.PgError{Code: "23503"}))
+		require.False(t, ok)
+	})
 }
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/oklog/ulid/v2"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/sqlcommon"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+// The statements of a write. Their text does not depend on the tuples, so that pgx prepares each of them once per
+// connection and then only sends their arguments, in the binary format.
+const (
+	deleteTupleStatement = `DELETE FROM tuple
+		WHERE store = $1 AND object_type = $2 AND object_id = $3 AND relation = $4 AND _user = $5 AND user_type = $6`
+	insertTupleStatement = `INSERT INTO tuple
+		(store, object_type, object_id, relation, _user, user_type, condition_name, condition_context, ulid, inserted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())`
+	insertChangeStatement = `INSERT INTO changelog
+		(store, object_type, object_id, relation, _user, condition_name, condition_context, operation, ulid, inserted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())`
+	notifyTupleChangeStatement = `SELECT pg_notify($1, $2)`
+)
+
+// write deletes and writes the tuples in a single transaction, sending all of its statements to the database in a
+// single pgx batch, i.e. in one round trip instead of one per tuple. It is equivalent to [sqlcommon.Write], except
+// that the transaction also notifies the changes on the notification channel, if any.
+func (s *Datastore) write(ctx context.Context, store string, deletes storage.Deletes, writes storage.Writes, now time.Time) error {
+	if len(deletes) == 0 && len(writes) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, tk := range deletes {
+		objectType, objectID := tupleUtils.SplitObject(tk.GetObject())
+		batch.Queue(deleteTupleStatement,
+			store, objectType, objectID, tk.GetRelation(), tk.GetUser(), string(tupleUtils.GetUserTypeFromUser(tk.GetUser())))
+	}
+
+	changes := make([]*pgx.QueuedQuery, 0, len(deletes)+len(writes))
+	for _, tk := range deletes {
+		id := ulid.MustNew(ulid.Timestamp(now), ulid.DefaultEntropy()).String()
+		objectType, objectID := tupleUtils.SplitObject(tk.GetObject())
+		// Redact condition info for deletes since we only need the base triplet (object, relation, user).
+		changes = append(changes, &pgx.QueuedQuery{SQL: insertChangeStatement, Arguments: []any{
+			store, objectType, objectID, tk.GetRelation(), tk.GetUser(), "", nil,
+			int32(openfgav1.TupleOperation_TUPLE_OPERATION_DELETE), id,
+		}})
+	}
+
+	for _, tk := range writes {
+		id := ulid.MustNew(ulid.Timestamp(now), ulid.DefaultEntropy()).String()
+		objectType, objectID := tupleUtils.SplitObject(tk.GetObject())
+
+		conditionName, conditionContext, err := sqlcommon.MarshalRelationshipCondition(tk.GetCondition())
+		if err != nil {
+			return err
+		}
+
+		batch.Queue(insertTupleStatement,
+			store, objectType, objectID, tk.GetRelation(), tk.GetUser(), string(tupleUtils.GetUserTypeFromUser(tk.GetUser())),
+			conditionName, conditionContext, id)
+		changes = append(changes, &pgx.QueuedQuery{SQL: insertChangeStatement, Arguments: []any{
+			store, objectType, objectID, tk.GetRelation(), tk.GetUser(), conditionName, conditionContext,
+			int32(openfgav1.TupleOperation_TUPLE_OPERATION_WRITE), id,
+		}})
+	}
+
+	for _, change := range changes {
+		batch.Queue(change.SQL, change.Arguments...)
+	}
+
+	payloads, err := s.tupleChangePayloads(store, deletes, writes)
+	if err != nil {
+		return err
+	}
+	for _, payload := range payloads {
+		batch.Queue(notifyTupleChangeStatement, s.notificationChannel, payload)
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return HandleSQLError(err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		txn, err := driverConn.(*stdlib.Conn).Conn().Begin(ctx)
+		if err != nil {
+			return HandleSQLError(err)
+		}
+		defer func() {
+			_ = txn.Rollback(ctx)
+		}()
+
+		results := txn.SendBatch(ctx, batch)
+		if err := checkWriteResults(results, deletes, writes, len(payloads)); err != nil {
+			_ = results.Close()
+			return err
+		}
+		if err := results.Close(); err != nil {
+			return HandleSQLError(err)
+		}
+
+		if err := txn.Commit(ctx); err != nil {
+			return HandleSQLError(err)
+		}
+		return nil
+	})
+}
+
+// checkWriteResults reads the results of the statements queued by write, in order, and returns the error of the
+// first one failing.
+func checkWriteResults(results pgx.BatchResults, deletes storage.Deletes, writes storage.Writes, notifications int) error {
+	for _, tk := range deletes {
+		tag, err := results.Exec()
+		if err != nil {
+			return HandleSQLError(err, tk)
+		}
+		if tag.RowsAffected() != 1 {
+			return storage.InvalidWriteInputError(tk, openfgav1.TupleOperation_TUPLE_OPERATION_DELETE)
+		}
+	}
+
+	for _, tk := range writes {
+		if _, err := results.Exec(); err != nil {
+			return HandleSQLError(err, tk)
+		}
+	}
+
+	for range len(deletes) + len(writes) + notifications {
+		if _, err := results.Exec(); err != nil {
+			return HandleSQLError(err)
+		}
+	}
+	return nil
+}
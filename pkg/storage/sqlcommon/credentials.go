@@ -0,0 +1,218 @@
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+// Credentials are the username and password connecting to a datastore. Empty fields fall back to the username and
+// password of the [Config], then to the ones of the connection uri.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// CredentialsProvider provides the credentials of a datastore which may be rotated while the server runs, e.g. by a
+// secret manager such as Vault. New connections are opened with the current credentials, while the open ones are
+// retired once they are no longer in use, without failing the requests using them.
+type CredentialsProvider interface {
+	// Credentials returns the current credentials. It is called whenever a connection is opened.
+	Credentials() Credentials
+
+	// Subscribe registers fn to be called whenever the credentials change, until unsubscribe is called.
+	Subscribe(fn func()) (unsubscribe func())
+}
+
+// CurrentCredentials returns the credentials to open a connection with, i.e. the ones of the credentials provider if
+// any, falling back to the username and password of the Config.
+func (c *Config) CurrentCredentials() Credentials {
+	credentials := Credentials{Username: c.Username, Password: c.Password}
+	if c.CredentialsProvider == nil {
+		return credentials
+	}
+
+	provided := c.CredentialsProvider.Credentials()
+	if provided.Username != "" {
+		credentials.Username = provided.Username
+	}
+	if provided.Password != "" {
+		credentials.Password = provided.Password
+	}
+	return credentials
+}
+
+// WithCredentialsProvider returns a DatastoreOption that sets
+// the provider of rotated credentials in the Config.
+func WithCredentialsProvider(provider CredentialsProvider) DatastoreOption {
+	return func(cfg *Config) {
+		cfg.CredentialsProvider = provider
+	}
+}
+
+// AuthTokenProvider provides the short-lived authentication tokens the connections to a datastore are opened with
+// instead of a password, e.g. the IAM authentication tokens of AWS RDS. A token is only checked when a connection is
+// opened, so the open connections keep working once it expired.
+type AuthTokenProvider interface {
+	// AuthToken returns a token to open a connection to the endpoint (host:port) as username. It is called whenever
+	// a connection is opened.
+	AuthToken(ctx context.Context, endpoint, username string) (string, error)
+}
+
+// WithAuthTokenProvider returns a DatastoreOption that sets
+// the provider of authentication tokens in the Config.
+func WithAuthTokenProvider(provider AuthTokenProvider) DatastoreOption {
+	return func(cfg *Config) {
+		cfg.AuthTokenProvider = provider
+	}
+}
+
+// ConnectPassword returns the password to open a connection to the endpoint as username: a new authentication token
+// if the Config has an AuthTokenProvider, else password.
+func (c *Config) ConnectPassword(ctx context.Context, endpoint, username, password string) (string, error) {
+	if c.AuthTokenProvider == nil {
+		return password, nil
+	}
+
+	token, err := c.AuthTokenProvider.AuthToken(ctx, endpoint, username)
+	if err != nil {
+		return "", fmt.Errorf("get datastore authentication token: %w", err)
+	}
+	return token, nil
+}
+
+// CloseIdleConnections closes the idle connections of db, so that it opens new ones with the current credentials.
+// maxIdleConns is the configured maximum of idle connections, which is restored afterward.
+func CloseIdleConnections(db *sql.DB, maxIdleConns int) {
+	db.SetMaxIdleConns(-1)
+	if maxIdleConns == 0 {
+		// the default of database/sql
+		maxIdleConns = 2
+	}
+	db.SetMaxIdleConns(maxIdleConns)
+}
+
+// FileCredentialsProvider is a [CredentialsProvider] reading the username and password from files, such as the ones
+// of a mounted Kubernetes secret or written by a Vault agent, and reloading them when they change.
+type FileCredentialsProvider struct {
+	usernameFile string
+	passwordFile string
+
+	mu          sync.RWMutex
+	credentials Credentials
+	subscribers map[int]func()
+	nextID      int
+}
+
+var _ CredentialsProvider = (*FileCredentialsProvider)(nil)
+
+// NewFileCredentialsProvider returns a [FileCredentialsProvider] reading the username from usernameFile and the
+// password from passwordFile. Either may be empty, to only read the other one.
+func NewFileCredentialsProvider(usernameFile, passwordFile string) (*FileCredentialsProvider, error) {
+	p := &FileCredentialsProvider{
+		usernameFile: usernameFile,
+		passwordFile: passwordFile,
+		subscribers:  map[int]func(){},
+	}
+	if _, err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Credentials see [CredentialsProvider].Credentials.
+func (p *FileCredentialsProvider) Credentials() Credentials {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.credentials
+}
+
+// Subscribe see [CredentialsProvider].Subscribe.
+func (p *FileCredentialsProvider) Subscribe(fn func()) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := p.nextID
+	p.nextID++
+	p.subscribers[id] = fn
+
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.subscribers, id)
+	}
+}
+
+// Reload reads the files again, notifying the subscribers and returning true if the credentials changed. On error,
+// the previous credentials are kept.
+func (p *FileCredentialsProvider) Reload() (bool, error) {
+	var credentials Credentials
+	var err error
+	if credentials.Username, err = readCredentialFile(p.usernameFile); err != nil {
+		return false, err
+	}
+	if credentials.Password, err = readCredentialFile(p.passwordFile); err != nil {
+		return false, err
+	}
+
+	p.mu.Lock()
+	if credentials == p.credentials {
+		p.mu.Unlock()
+		return false, nil
+	}
+	p.credentials = credentials
+	subscribers := make([]func(), 0, len(p.subscribers))
+	for _, fn := range p.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	p.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn()
+	}
+	return true, nil
+}
+
+// Watch reloads the files every interval until ctx is done.
+func (p *FileCredentialsProvider) Watch(ctx context.Context, interval time.Duration, logger logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := p.Reload()
+		if err != nil {
+			logger.Warn("failed to reload the datastore credentials, the previous ones are kept", zap.Error(err))
+			continue
+		}
+		if changed {
+			logger.Info("datastore credentials reloaded")
+		}
+	}
+}
+
+// readCredentialFile returns the content of path, without its trailing newline, or an empty string if path is empty.
+func readCredentialFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read datastore credentials file: %w", err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
@@ -0,0 +1,64 @@
+package sqlcommon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCredentialsProvider(t *testing.T) {
+	dir := t.TempDir()
+	usernameFile := filepath.Join(dir, "username")
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(usernameFile, []byte("openfga\n"), 0o600))
+	require.NoError(t, os.WriteFile(passwordFile, []byte("secret-1\n"), 0o600))
+
+	provider, err := NewFileCredentialsProvider(usernameFile, passwordFile)
+	require.NoError(t, err)
+	require.Equal(t, Credentials{Username: "openfga", Password: "secret-1"}, provider.Credentials())
+
+	notified := 0
+	unsubscribe := provider.Subscribe(func() { notified++ })
+
+	t.Run("unchanged_files_do_not_notify", func(t *testing.T) {
+		changed, err := provider.Reload()
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Equal(t, 0, notified)
+	})
+
+	t.Run("rotated_password_notifies", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(passwordFile, []byte("secret-2"), 0o600))
+
+		changed, err := provider.Reload()
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(t, 1, notified)
+		require.Equal(t, Credentials{Username: "openfga", Password: "secret-2"}, provider.Credentials())
+	})
+
+	t.Run("unreadable_files_keep_the_previous_credentials", func(t *testing.T) {
+		require.NoError(t, os.Remove(passwordFile))
+
+		_, err := provider.Reload()
+		require.Error(t, err)
+		require.Equal(t, Credentials{Username: "openfga", Password: "secret-2"}, provider.Credentials())
+	})
+
+	t.Run("unsubscribed_functions_are_not_notified", func(t *testing.T) {
+		unsubscribe()
+		require.NoError(t, os.WriteFile(passwordFile, []byte("secret-3"), 0o600))
+
+		changed, err := provider.Reload()
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(t, 1, notified)
+	})
+
+	t.Run("provided_credentials_take_precedence", func(t *testing.T) {
+		cfg := NewConfig(WithUsername("admin"), WithPassword("static"), WithCredentialsProvider(provider))
+		require.Equal(t, Credentials{Username: "openfga", Password: "secret-3"}, cfg.CurrentCredentials())
+	})
+}
@@ -0,0 +1,58 @@
+package sqlcommon
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openfga/openfga/internal/build"
+)
+
+// The operations of the queries measured by [QueryMetrics].
+const (
+	QueryOperationRead                 = "Read"
+	QueryOperationReadPage             = "ReadPage"
+	QueryOperationReadUserTuple        = "ReadUserTuple"
+	QueryOperationReadUserTuples       = "ReadUserTuples"
+	QueryOperationReadUsersetTuples    = "ReadUsersetTuples"
+	QueryOperationReadStartingWithUser = "ReadStartingWithUser"
+	QueryOperationReadChanges          = "ReadChanges"
+	QueryOperationWrite                = "Write"
+)
+
+var queryDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace:                       build.ProjectName,
+	Name:                            "datastore_query_duration_ms",
+	Help:                            "The duration of the queries of the SQL datastores, partitioned by engine and operation. The queries of iterators are measured until their rows are available.",
+	Buckets:                         []float64{1, 3, 5, 10, 25, 50, 100, 250, 500, 1000, 5000},
+	NativeHistogramBucketFactor:     1.1,
+	NativeHistogramMaxBucketNumber:  100,
+	NativeHistogramMinResetDuration: time.Hour,
+}, []string{"engine", "operation"})
+
+// QueryMetrics records the duration of the queries of a datastore, if the export of metrics is enabled. Together with
+// the connection pool statistics, it tells a slow database apart from an exhausted connection pool.
+//
+// A nil *QueryMetrics is valid and records nothing.
+type QueryMetrics struct {
+	engine string
+}
+
+// NewQueryMetrics returns the [QueryMetrics] of a datastore of the engine, or nil if cfg does not export metrics.
+func NewQueryMetrics(engine string, cfg *Config) *QueryMetrics {
+	if !cfg.ExportMetrics {
+		return nil
+	}
+	return &QueryMetrics{engine: engine}
+}
+
+// Observe records the duration of the query of operation, one of the QueryOperation constants, started at start, e.g.
+//
+//	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadUserTuple, time.Now())
+func (m *QueryMetrics) Observe(operation string, start time.Time) {
+	if m == nil {
+		return
+	}
+	queryDurationHistogram.WithLabelValues(m.engine, operation).Observe(float64(time.Since(start).Milliseconds()))
+}
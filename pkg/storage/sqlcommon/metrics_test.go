@@ -0,0 +1,37 @@
+package sqlcommon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMetrics(t *testing.T) {
+	sampleCount := func(t *testing.T, engine, operation string) uint64 {
+		metric := &dto.Metric{}
+		require.NoError(t, queryDurationHistogram.WithLabelValues(engine, operation).(prometheus.Histogram).Write(metric))
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	t.Run("disabled_without_metrics_export", func(t *testing.T) {
+		queryMetrics := NewQueryMetrics("test_disabled", NewConfig())
+		require.Nil(t, queryMetrics)
+
+		queryMetrics.Observe(QueryOperationReadUserTuple, time.Now())
+		require.Zero(t, sampleCount(t, "test_disabled", "ReadUserTuple"))
+	})
+
+	t.Run("observes_the_queries_by_operation", func(t *testing.T) {
+		queryMetrics := NewQueryMetrics("test_enabled", NewConfig(WithMetrics()))
+
+		queryMetrics.Observe(QueryOperationReadUserTuple, time.Now())
+		queryMetrics.Observe(QueryOperationReadUserTuple, time.Now())
+		queryMetrics.Observe(QueryOperationWrite, time.Now())
+
+		require.Equal(t, uint64(2), sampleCount(t, "test_enabled", "ReadUserTuple"))
+		require.Equal(t, uint64(1), sampleCount(t, "test_enabled", "Write"))
+	})
+}
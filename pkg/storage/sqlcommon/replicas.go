@@ -0,0 +1,43 @@
+package sqlcommon
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	sq "github.com/Masterminds/squirrel"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+// ReadReplicas routes the tuple reads of a datastore to the statement builders of its read replicas, in turn.
+// Reads requesting HIGHER_CONSISTENCY always go to the primary, since replicas may lag behind it.
+type ReadReplicas struct {
+	primary  sq.StatementBuilderType
+	replicas []sq.StatementBuilderType
+	next     atomic.Uint64
+}
+
+// NewReadReplicas returns a [ReadReplicas] over the statement builders of the primary and the replicas.
+// Without replicas, every read goes to the primary.
+func NewReadReplicas(primary sq.StatementBuilderType, replicas ...sq.StatementBuilderType) *ReadReplicas {
+	return &ReadReplicas{
+		primary:  primary,
+		replicas: replicas,
+	}
+}
+
+// StatementBuilder returns the statement builder to read with the given consistency preference.
+func (r *ReadReplicas) StatementBuilder(preference openfgav1.ConsistencyPreference) sq.StatementBuilderType {
+	if len(r.replicas) == 0 || preference == openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY {
+		return r.primary
+	}
+
+	i := r.next.Add(1) - 1
+	return r.replicas[i%uint64(len(r.replicas))]
+}
+
+// ReplicaMetricsName returns the name labelling the connection pool metrics of the i-th read replica,
+// the ones of the primary being labelled 'openfga'.
+func ReplicaMetricsName(i int) string {
+	return fmt.Sprintf("openfga_replica_%d", i)
+}
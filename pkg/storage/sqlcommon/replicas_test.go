@@ -0,0 +1,40 @@
+package sqlcommon
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+func TestReadReplicas(t *testing.T) {
+	// the placeholder formats tell the statement builders apart
+	primary := sq.StatementBuilder.PlaceholderFormat(sq.Question)
+	replicaA := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	replicaB := sq.StatementBuilder.PlaceholderFormat(sq.AtP)
+
+	sql := func(builder sq.StatementBuilderType) string {
+		query, _, err := builder.Select("*").From("tuple").Where(sq.Eq{"store": "1"}).ToSql()
+		require.NoError(t, err)
+		return query
+	}
+
+	t.Run("without_replicas", func(t *testing.T) {
+		replicas := NewReadReplicas(primary)
+		require.Equal(t, sql(primary), sql(replicas.StatementBuilder(openfgav1.ConsistencyPreference_MINIMIZE_LATENCY)))
+	})
+
+	t.Run("replicas_in_turn", func(t *testing.T) {
+		replicas := NewReadReplicas(primary, replicaA, replicaB)
+		require.Equal(t, sql(replicaA), sql(replicas.StatementBuilder(openfgav1.ConsistencyPreference_UNSPECIFIED)))
+		require.Equal(t, sql(replicaB), sql(replicas.StatementBuilder(openfgav1.ConsistencyPreference_MINIMIZE_LATENCY)))
+		require.Equal(t, sql(replicaA), sql(replicas.StatementBuilder(openfgav1.ConsistencyPreference_UNSPECIFIED)))
+	})
+
+	t.Run("higher_consistency_reads_the_primary", func(t *testing.T) {
+		replicas := NewReadReplicas(primary, replicaA, replicaB)
+		require.Equal(t, sql(primary), sql(replicas.StatementBuilder(openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY)))
+	})
+}
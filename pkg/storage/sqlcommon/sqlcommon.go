@@ -3,16 +3,22 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"io"
+	"maps"
+	"slices"
 	"strconv"
 	"sync"
+	"syscall"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/oklog/ulid/v2"
 	"github.com/pressly/goose/v3"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -43,6 +49,22 @@ type Config struct {
 	ConnMaxLifetime time.Duration
 
 	ExportMetrics bool
+
+	// ReadReplicaURIs are the connection uris of read replicas serving the tuple reads that do not require
+	// HIGHER_CONSISTENCY. The username and password overrides apply to them as well.
+	ReadReplicaURIs []string
+
+	// NotificationChannel is the channel on which the tuple writes are notified to the other servers, for the
+	// datastores supporting it. If empty, writes are not notified.
+	NotificationChannel string
+
+	// CredentialsProvider provides rotated credentials, taking precedence over Username and Password. The read
+	// replicas use them as well.
+	CredentialsProvider CredentialsProvider
+
+	// AuthTokenProvider provides the authentication tokens the connections are opened with instead of a password,
+	// e.g. with AWS RDS IAM authentication. The read replicas use them as well.
+	AuthTokenProvider AuthTokenProvider
 }
 
 // DatastoreOption defines a function type
@@ -126,6 +148,22 @@ func WithMetrics() DatastoreOption {
 	}
 }
 
+// WithReadReplicaURIs returns a DatastoreOption that sets
+// the connection uris of the read replicas in the Config.
+func WithReadReplicaURIs(uris ...string) DatastoreOption {
+	return func(cfg *Config) {
+		cfg.ReadReplicaURIs = uris
+	}
+}
+
+// WithNotificationChannel returns a DatastoreOption that sets
+// the channel on which the tuple writes are notified in the Config.
+func WithNotificationChannel(channel string) DatastoreOption {
+	return func(cfg *Config) {
+		cfg.NotificationChannel = channel
+	}
+}
+
 // NewConfig creates a new Config instance with default values
 // and applies any provided DatastoreOption modifications.
 func NewConfig(opts ...DatastoreOption) *Config {
@@ -201,6 +239,13 @@ type SQLTupleIterator struct {
 	// will use this item instead. Otherwise, the first item will be lost.
 	firstRow *storage.TupleRecord // GUARDED_BY(mu)
 	mu       sync.Mutex
+
+	// span traces the query and the reading of its rows, until the iterator is done or stopped.
+	span     trace.Span // GUARDED_BY(mu)
+	rowsRead int        // GUARDED_BY(mu)
+
+	queryMetrics *QueryMetrics
+	operation    string
 }
 
 // Ensures that SQLTupleIterator implements the TupleIterator interface.
@@ -217,17 +262,39 @@ func NewSQLTupleIterator(sb sq.SelectBuilder, errHandler errorHandlerFn) *SQLTup
 	}
 }
 
+// WithQueryMetrics records the duration of the query of the iterator as one of operation.
+func (t *SQLTupleIterator) WithQueryMetrics(queryMetrics *QueryMetrics, operation string) *SQLTupleIterator {
+	t.queryMetrics = queryMetrics
+	t.operation = operation
+	return t
+}
+
+// fetchBuffer runs the query of the iterator. Its span is ended by endSpan once the rows are read. The caller must
+// hold mu.
 func (t *SQLTupleIterator) fetchBuffer(ctx context.Context) error {
-	ctx, span := tracer.Start(ctx, "sqlcommon.fetchBuffer", trace.WithAttributes())
-	defer span.End()
+	ctx, span := tracer.Start(ctx, "sqlcommon.fetchBuffer", trace.WithAttributes(attribute.String(OperationAttribute, t.operation)))
+	defer t.queryMetrics.Observe(t.operation, time.Now())
 	rows, err := t.sb.QueryContext(ctx)
 	if err != nil {
+		span.RecordError(err)
+		span.End()
 		return t.handleSQLError(err)
 	}
 	t.rows = rows
+	t.span = span
 	return nil
 }
 
+// endSpan ends the span of the query, recording the number of rows read. The caller must hold mu.
+func (t *SQLTupleIterator) endSpan() {
+	if t.span == nil {
+		return
+	}
+	t.span.SetAttributes(attribute.Int(RowsAttribute, t.rowsRead))
+	t.span.End()
+	t.span = nil
+}
+
 func (t *SQLTupleIterator) next(ctx context.Context) (*storage.TupleRecord, error) {
 	t.mu.Lock()
 
@@ -256,6 +323,7 @@ func (t *SQLTupleIterator) next(ctx context.Context) (*storage.TupleRecord, erro
 
 	if !t.rows.Next() {
 		err := t.rows.Err()
+		t.endSpan()
 		t.mu.Unlock()
 		if err != nil {
 			return nil, t.handleSQLError(err)
@@ -277,6 +345,9 @@ func (t *SQLTupleIterator) next(ctx context.Context) (*storage.TupleRecord, erro
 		&record.Ulid,
 		&record.InsertedAt,
 	)
+	if err == nil {
+		t.rowsRead++
+	}
 	t.mu.Unlock()
 
 	if err != nil {
@@ -321,6 +392,7 @@ func (t *SQLTupleIterator) head(ctx context.Context) (*storage.TupleRecord, erro
 	}
 
 	if !t.rows.Next() {
+		t.endSpan()
 		if err := t.rows.Err(); err != nil {
 			return nil, t.handleSQLError(err)
 		}
@@ -344,6 +416,7 @@ func (t *SQLTupleIterator) head(ctx context.Context) (*storage.TupleRecord, erro
 	if err != nil {
 		return nil, t.handleSQLError(err)
 	}
+	t.rowsRead++
 
 	record.ConditionName = conditionName.String
 
@@ -425,6 +498,7 @@ func (t *SQLTupleIterator) Stop() {
 	if t.rows != nil {
 		_ = t.rows.Close()
 	}
+	t.endSpan()
 }
 
 // DBInfo encapsulates DB information for use in common method.
@@ -609,6 +683,306 @@ func WriteAuthorizationModel(
 	return nil
 }
 
+// DeleteAuthorizationModel deletes the rows of a model and of its assertions in a single transaction.
+func DeleteAuthorizationModel(ctx context.Context, dbInfo *DBInfo, store, id string) error {
+	txn, err := dbInfo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
+
+	res, err := dbInfo.stbl.
+		Delete("authorization_model").
+		Where(sq.Eq{"store": store, "authorization_model_id": id}).
+		RunWith(txn). // Part of a txn.
+		ExecContext(ctx)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+
+	_, err = dbInfo.stbl.
+		Delete("assertion").
+		Where(sq.Eq{"store": store, "authorization_model_id": id}).
+		RunWith(txn). // Part of a txn.
+		ExecContext(ctx)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	return nil
+}
+
+// WriteStoreLabels see [storage.StoresBackend].WriteStoreLabels.
+func WriteStoreLabels(ctx context.Context, dbInfo *DBInfo, store string, labels map[string]string) error {
+	txn, err := dbInfo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
+
+	var storeID string
+	err = dbInfo.stbl.
+		Select("id").
+		From("store").
+		Where(sq.Eq{"id": store, "deleted_at": nil}).
+		RunWith(txn). // Part of a txn.
+		QueryRowContext(ctx).
+		Scan(&storeID)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	if err := DeleteStoreLabels(ctx, dbInfo.stbl, txn, store); err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	if err := InsertStoreLabels(ctx, dbInfo.stbl, txn, store, labels); err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	return nil
+}
+
+// InsertStoreLabels inserts the labels of a store within txn, e.g. the transaction creating the store.
+func InsertStoreLabels(ctx context.Context, stbl sq.StatementBuilderType, txn *sql.Tx, store string, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	insertBuilder := stbl.
+		Insert("store_label").
+		Columns("store", "label_key", "label_value")
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		insertBuilder = insertBuilder.Values(store, key, labels[key])
+	}
+	_, err := insertBuilder.
+		RunWith(txn). // Part of a txn.
+		ExecContext(ctx)
+	return err
+}
+
+// DeleteStoreLabels deletes the labels of a store within txn, e.g. the transaction deleting the store.
+func DeleteStoreLabels(ctx context.Context, stbl sq.StatementBuilderType, txn *sql.Tx, store string) error {
+	_, err := stbl.
+		Delete("store_label").
+		Where(sq.Eq{"store": store}).
+		RunWith(txn). // Part of a txn.
+		ExecContext(ctx)
+	return err
+}
+
+// ReadStoreLabels see [storage.StoresBackend].ReadStoreLabels.
+func ReadStoreLabels(ctx context.Context, dbInfo *DBInfo, store string) (map[string]string, error) {
+	rows, err := dbInfo.stbl.
+		Select("label_key", "label_value").
+		From("store_label").
+		Where(sq.Eq{"store": store}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+	defer rows.Close()
+
+	labels := map[string]string{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, dbInfo.HandleSQLError(err)
+		}
+		labels[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+
+	return labels, nil
+}
+
+// CreateJob see [storage.JobsBackend].CreateJob.
+func CreateJob(ctx context.Context, dbInfo *DBInfo, job *storage.Job) error {
+	now := time.Now().UTC()
+	_, err := dbInfo.stbl.
+		Insert("job").
+		Columns("store", "id", "type", "params", "status", "progress", "error", "cancel_requested", "created_at", "updated_at").
+		Values(job.StoreID, job.ID, job.Type, job.Params, string(job.Status), job.Progress, job.Error, false, now, now).
+		ExecContext(ctx)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	job.CreatedAt, job.UpdatedAt = now, now
+	return nil
+}
+
+// ReadJob see [storage.JobsBackend].ReadJob.
+func ReadJob(ctx context.Context, dbInfo *DBInfo, store, id string) (*storage.Job, error) {
+	job := &storage.Job{StoreID: store, ID: id}
+	var status string
+	var jobError sql.NullString
+	err := dbInfo.stbl.
+		Select("type", "params", "status", "progress", "result", "error", "cancel_requested", "created_at", "updated_at").
+		From("job").
+		Where(sq.Eq{"store": store, "id": id}).
+		QueryRowContext(ctx).
+		Scan(&job.Type, &job.Params, &status, &job.Progress, &job.Result, &jobError, &job.CancelRequested, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+
+	job.Status = storage.JobStatus(status)
+	job.Error = jobError.String
+	return job, nil
+}
+
+// UpdateJob see [storage.JobsBackend].UpdateJob.
+func UpdateJob(ctx context.Context, dbInfo *DBInfo, job *storage.Job) error {
+	now := time.Now().UTC()
+	res, err := dbInfo.stbl.
+		Update("job").
+		Set("status", string(job.Status)).
+		Set("progress", job.Progress).
+		Set("result", job.Result).
+		Set("error", job.Error).
+		Set("updated_at", now).
+		Where(sq.Eq{"store": job.StoreID, "id": job.ID}).
+		ExecContext(ctx)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+	if rowsAffected == 0 {
+		// MySQL does not count the rows left unchanged, e.g. by an update within the same second
+		if _, err := ReadJob(ctx, dbInfo, job.StoreID, job.ID); err != nil {
+			return err
+		}
+	}
+
+	job.UpdatedAt = now
+	return nil
+}
+
+// RequestJobCancellation see [storage.JobsBackend].RequestJobCancellation.
+func RequestJobCancellation(ctx context.Context, dbInfo *DBInfo, store, id string) error {
+	res, err := dbInfo.stbl.
+		Update("job").
+		Set("cancel_requested", true).
+		Where(sq.Eq{"store": store, "id": id}).
+		ExecContext(ctx)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+	if rowsAffected == 0 {
+		// MySQL does not count the rows left unchanged, e.g. of a job whose cancellation was already requested
+		if _, err := ReadJob(ctx, dbInfo, store, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimInterruptedJobs see [storage.JobsBackend].ClaimInterruptedJobs.
+func ClaimInterruptedJobs(ctx context.Context, dbInfo *DBInfo) ([]*storage.Job, error) {
+	rows, err := dbInfo.stbl.
+		Select("store", "id").
+		From("job").
+		Where(sq.Eq{"status": string(storage.JobStatusInterrupted)}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+	defer rows.Close()
+
+	var keys [][2]string
+	for rows.Next() {
+		var store, id string
+		if err := rows.Scan(&store, &id); err != nil {
+			return nil, dbInfo.HandleSQLError(err)
+		}
+		keys = append(keys, [2]string{store, id})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+	rows.Close()
+
+	var claimed []*storage.Job
+	for _, key := range keys {
+		// The update only applies to a job still interrupted, so that a job claimed by another server is skipped.
+		res, err := dbInfo.stbl.
+			Update("job").
+			Set("status", string(storage.JobStatusPending)).
+			Set("updated_at", time.Now().UTC()).
+			Where(sq.Eq{"store": key[0], "id": key[1], "status": string(storage.JobStatusInterrupted)}).
+			ExecContext(ctx)
+		if err != nil {
+			return claimed, dbInfo.HandleSQLError(err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return claimed, dbInfo.HandleSQLError(err)
+		}
+		if rowsAffected == 0 {
+			continue
+		}
+
+		job, err := ReadJob(ctx, dbInfo, key[0], key[1])
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, job)
+	}
+	return claimed, nil
+}
+
+// StoreLabelsClause returns the condition restricting the stores listed to those holding every label of labels.
+func StoreLabelsClause(labels map[string]string) sq.And {
+	clause := sq.And{}
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		clause = append(clause, sq.Expr("id IN (SELECT store FROM store_label WHERE label_key = ? AND label_value = ?)", key, labels[key]))
+	}
+	return clause
+}
+
+// UserTypeClause returns the condition restricting the tuples read to those whose '_user' is of the userType object
+// type. It compares the users to the range of 'userType:' to 'userType;', ';' following ':', so that it uses the
+// indexes on '_user'.
+func UserTypeClause(userType string) sq.And {
+	return sq.And{
+		sq.GtOrEq{"_user": userType + ":"},
+		sq.Lt{"_user": userType + ";"},
+	}
+}
+
 // constructAuthorizationModelFromSQLRows tries first to read and return a model that was written in one row (the new format).
 // If it can't find one, it will then look for a model that was written across multiple rows (the old format).
 func constructAuthorizationModelFromSQLRows(rows *sql.Rows) (*openfgav1.AuthorizationModel, error) {
@@ -751,11 +1125,13 @@ func IsReady(ctx context.Context, db *sql.DB) (storage.ReadinessStatus, error) {
 				"', but requires '" +
 				strconv.FormatInt(build.MinimumSupportedDatastoreSchemaRevision, 10) +
 				"'. Run 'openfga migrate'.",
-			IsReady: false,
+			Revision: revision,
+			IsReady:  false,
 		}, nil
 	}
 	return storage.ReadinessStatus{
-		IsReady: true,
+		Revision: revision,
+		IsReady:  true,
 	}, nil
 }
 
@@ -765,3 +1141,13 @@ func AddFromUlid(sb sq.SelectBuilder, fromUlid string, sortDescending bool) sq.S
 	}
 	return sb.Where(sq.Gt{"ulid": fromUlid})
 }
+
+// IsConnectionError reports whether err is a failure of the connection to the database rather than of the statement,
+// which may not recur on another connection.
+func IsConnectionError(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
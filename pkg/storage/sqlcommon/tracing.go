@@ -0,0 +1,50 @@
+package sqlcommon
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+// The attributes of the spans of the SQL datastores. They describe the shape of a query, never the object ids, users
+// or contexts it reads or writes.
+const (
+	OperationAttribute    = "db.operation"
+	StoreIDAttribute      = "store_id"
+	ObjectTypeAttribute   = "object_type"
+	RelationAttribute     = "relation"
+	PageSizeAttribute     = "page_size"
+	ContinuationAttribute = "continuation"
+	RowsAttribute         = "rows"
+	TupleKeysAttribute    = "tuple_keys"
+	DeletesAttribute      = "deletes"
+	WritesAttribute       = "writes"
+)
+
+// StoreAttributes returns the span attributes of an operation on the store.
+func StoreAttributes(store string) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String(StoreIDAttribute, store)}
+}
+
+// TupleFilterAttributes returns the span attributes of a read of the store filtered by tupleKey: the store, and the
+// object type and relation of the filter. The object id and the user of the filter are omitted.
+func TupleFilterAttributes(store string, tupleKey *openfgav1.TupleKey) []attribute.KeyValue {
+	objectType, _ := tupleUtils.SplitObject(tupleKey.GetObject())
+	return []attribute.KeyValue{
+		attribute.String(StoreIDAttribute, store),
+		attribute.String(ObjectTypeAttribute, objectType),
+		attribute.String(RelationAttribute, tupleKey.GetRelation()),
+	}
+}
+
+// PaginationAttributes returns the span attributes of the pagination of a read: its page size and whether it
+// continues a previous read. The continuation token itself is omitted.
+func PaginationAttributes(pagination storage.PaginationOptions) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int(PageSizeAttribute, pagination.PageSize),
+		attribute.Bool(ContinuationAttribute, pagination.From != ""),
+	}
+}
@@ -0,0 +1,87 @@
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	_ "modernc.org/sqlite"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestTupleFilterAttributes(t *testing.T) {
+	attributes := TupleFilterAttributes("01STORE", tuple.NewTupleKey("document:secret", "viewer", "user:anne"))
+
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String(StoreIDAttribute, "01STORE"),
+		attribute.String(ObjectTypeAttribute, "document"),
+		attribute.String(RelationAttribute, "viewer"),
+	}, attributes)
+	for _, kv := range attributes {
+		require.NotContains(t, kv.Value.Emit(), "secret")
+		require.NotContains(t, kv.Value.Emit(), "anne")
+	}
+}
+
+func TestPaginationAttributes(t *testing.T) {
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Int(PageSizeAttribute, 50),
+		attribute.Bool(ContinuationAttribute, false),
+	}, PaginationAttributes(storage.NewPaginationOptions(50, "")))
+
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Int(PageSizeAttribute, 50),
+		attribute.Bool(ContinuationAttribute, true),
+	}, PaginationAttributes(storage.NewPaginationOptions(50, "01TOKEN")))
+}
+
+func TestSQLTupleIteratorSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, `CREATE TABLE tuple (store TEXT, object_type TEXT, object_id TEXT, relation TEXT,
+		_user TEXT, condition_name TEXT, condition_context BLOB, ulid TEXT, inserted_at TIMESTAMP)`)
+	require.NoError(t, err)
+	for _, objectID := range []string{"1", "2", "3"} {
+		_, err = db.ExecContext(ctx, `INSERT INTO tuple VALUES ('store', 'document', ?, 'viewer', 'user:anne', NULL, NULL, ?, ?)`,
+			objectID, "01ULID"+objectID, time.Now())
+		require.NoError(t, err)
+	}
+
+	iter := NewSQLTupleIterator(sq.StatementBuilder.RunWith(db).
+		Select("store", "object_type", "object_id", "relation", "_user", "condition_name", "condition_context", "ulid", "inserted_at").
+		From("tuple"), func(err error, _ ...interface{}) error { return err }).
+		WithQueryMetrics(nil, "Read")
+
+	_, err = iter.Head(ctx)
+	require.NoError(t, err)
+	_, err = iter.Next(ctx)
+	require.NoError(t, err)
+	_, err = iter.Next(ctx)
+	require.NoError(t, err)
+	require.Empty(t, recorder.Ended())
+
+	iter.Stop()
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "sqlcommon.fetchBuffer", spans[0].Name())
+	require.Contains(t, spans[0].Attributes(), attribute.String(OperationAttribute, "Read"))
+	require.Contains(t, spans[0].Attributes(), attribute.Int(RowsAttribute, 2))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -32,7 +33,7 @@ import (
 var tracer = otel.Tracer("openfga/pkg/storage/sqlite")
 
 func startTrace(ctx context.Context, name string) (context.Context, trace.Span) {
-	return tracer.Start(ctx, "sqlite."+name)
+	return tracer.Start(ctx, "sqlite."+name, trace.WithAttributes(attribute.String(sqlcommon.OperationAttribute, name)))
 }
 
 // Datastore provides a SQLite based implementation of [storage.OpenFGADatastore].
@@ -42,6 +43,7 @@ type Datastore struct {
 	dbInfo                 *sqlcommon.DBInfo
 	logger                 logger.Logger
 	dbStatsCollector       prometheus.Collector
+	queryMetrics           *sqlcommon.QueryMetrics
 	maxTuplesPerWriteField int
 	maxTypesPerModelField  int
 }
@@ -120,6 +122,7 @@ func New(uri string, cfg *sqlcommon.Config) (*Datastore, error) {
 		dbInfo:                 dbInfo,
 		logger:                 cfg.Logger,
 		dbStatsCollector:       collector,
+		queryMetrics:           sqlcommon.NewQueryMetrics("sqlite", cfg),
 		maxTuplesPerWriteField: cfg.MaxTuplesPerWriteField,
 		maxTypesPerModelField:  cfg.MaxTypesPerModelField,
 	}, nil
@@ -142,6 +145,8 @@ func (s *Datastore) Read(
 ) (storage.TupleIterator, error) {
 	ctx, span := startTrace(ctx, "Read")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationRead, time.Now())
 
 	return s.read(ctx, store, tupleKey, nil)
 }
@@ -150,6 +155,9 @@ func (s *Datastore) Read(
 func (s *Datastore) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
 	ctx, span := startTrace(ctx, "ReadPage")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadPage, time.Now())
 
 	iter, err := s.read(ctx, store, tupleKey, &options)
 	if err != nil {
@@ -157,12 +165,19 @@ func (s *Datastore) ReadPage(ctx context.Context, store string, tupleKey *openfg
 	}
 	defer iter.Stop()
 
-	return iter.ToArray(options.Pagination)
+	tuples, token, err := iter.ToArray(options.Pagination)
+	if err != nil {
+		return nil, "", err
+	}
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(tuples)))
+
+	return tuples, token, nil
 }
 
 func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options *storage.ReadPageOptions) (*SQLTupleIterator, error) {
 	ctx, span := startTrace(ctx, "read")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
 
 	sb := s.stbl.
 		Select(
@@ -194,6 +209,12 @@ func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.
 			"user_relation":    userRelation,
 		})
 	}
+	if options != nil && len(options.Relations) > 0 {
+		sb = sb.Where(sq.Eq{"relation": options.Relations})
+	}
+	if options != nil && options.UserType != "" {
+		sb = sb.Where(sq.Eq{"user_object_type": options.UserType})
+	}
 	if options != nil && options.Pagination.From != "" {
 		token := options.Pagination.From
 		sb = sb.Where(sq.GtOrEq{"ulid": token})
@@ -207,7 +228,7 @@ func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.
 		return nil, HandleSQLError(err)
 	}
 
-	return NewSQLTupleIterator(rows, HandleSQLError), nil
+	return NewSQLTupleIterator(rows, HandleSQLError).traced(ctx, "read"), nil
 }
 
 // Write see [storage.RelationshipTupleWriter].Write.
@@ -219,6 +240,12 @@ func (s *Datastore) Write(
 ) error {
 	ctx, span := startTrace(ctx, "Write")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(
+		attribute.Int(sqlcommon.DeletesAttribute, len(deletes)),
+		attribute.Int(sqlcommon.WritesAttribute, len(writes)),
+	)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationWrite, time.Now())
 
 	return s.write(ctx, store, deletes, writes, time.Now().UTC())
 }
@@ -409,6 +436,8 @@ func (s *Datastore) write(
 func (s *Datastore) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, _ storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
 	ctx, span := startTrace(ctx, "ReadUserTuple")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleKey)...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadUserTuple, time.Now())
 
 	objectType, objectID := tupleUtils.SplitObject(tupleKey.GetObject())
 	userType := tupleUtils.GetUserTypeFromUser(tupleKey.GetUser())
@@ -465,6 +494,64 @@ func (s *Datastore) ReadUserTuple(ctx context.Context, store string, tupleKey *o
 	return record.AsTuple(), nil
 }
 
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (s *Datastore) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, _ storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	ctx, span := startTrace(ctx, "ReadUserTuples")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(attribute.Int(sqlcommon.TupleKeysAttribute, len(tupleKeys)))
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadUserTuples, time.Now())
+
+	if len(tupleKeys) == 0 {
+		return nil, nil
+	}
+
+	orConditions := sq.Or{}
+	for _, tupleKey := range tupleKeys {
+		objectType, objectID := tupleUtils.SplitObject(tupleKey.GetObject())
+		userObjectType, userObjectID, userRelation := tupleUtils.ToUserParts(tupleKey.GetUser())
+		orConditions = append(orConditions, sq.Eq{
+			"object_type":      objectType,
+			"object_id":        objectID,
+			"relation":         tupleKey.GetRelation(),
+			"user_object_type": userObjectType,
+			"user_object_id":   userObjectID,
+			"user_relation":    userRelation,
+			"user_type":        tupleUtils.GetUserTypeFromUser(tupleKey.GetUser()),
+		})
+	}
+
+	rows, err := s.stbl.
+		Select(
+			"store", "object_type", "object_id", "relation",
+			"user_object_type", "user_object_id", "user_relation",
+			"condition_name", "condition_context", "ulid", "inserted_at",
+		).
+		From("tuple").
+		Where(sq.Eq{"store": store}).
+		Where(orConditions).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, HandleSQLError(err)
+	}
+
+	iter := NewSQLTupleIterator(rows, HandleSQLError).traced(ctx, "ReadUserTuples")
+	defer iter.Stop()
+
+	var tuples []*openfgav1.Tuple
+	for {
+		t, err := iter.Next(ctx)
+		if err != nil {
+			if errors.Is(err, storage.ErrIteratorDone) {
+				span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(tuples)))
+				return tuples, nil
+			}
+			return nil, err
+		}
+		tuples = append(tuples, t)
+	}
+}
+
 // ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
 func (s *Datastore) ReadUsersetTuples(
 	ctx context.Context,
@@ -474,6 +561,8 @@ func (s *Datastore) ReadUsersetTuples(
 ) (storage.TupleIterator, error) {
 	ctx, span := startTrace(ctx, "ReadUsersetTuples")
 	defer span.End()
+	span.SetAttributes(sqlcommon.TupleFilterAttributes(store, tupleUtils.NewTupleKey(filter.Object, filter.Relation, ""))...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadUsersetTuples, time.Now())
 
 	sb := s.stbl.
 		Select(
@@ -518,7 +607,7 @@ func (s *Datastore) ReadUsersetTuples(
 		return nil, HandleSQLError(err)
 	}
 
-	return NewSQLTupleIterator(rows, HandleSQLError), nil
+	return NewSQLTupleIterator(rows, HandleSQLError).traced(ctx, "ReadUsersetTuples"), nil
 }
 
 // ReadStartingWithUser see [storage.RelationshipTupleReader].ReadStartingWithUser.
@@ -530,6 +619,12 @@ func (s *Datastore) ReadStartingWithUser(
 ) (storage.TupleIterator, error) {
 	ctx, span := startTrace(ctx, "ReadStartingWithUser")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(
+		attribute.String(sqlcommon.ObjectTypeAttribute, filter.ObjectType),
+		attribute.String(sqlcommon.RelationAttribute, filter.Relation),
+	)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadStartingWithUser, time.Now())
 
 	var targetUsersArg sq.Or
 	for _, u := range filter.UserFilter {
@@ -567,7 +662,7 @@ func (s *Datastore) ReadStartingWithUser(
 		return nil, HandleSQLError(err)
 	}
 
-	return NewSQLTupleIterator(rows, HandleSQLError), nil
+	return NewSQLTupleIterator(rows, HandleSQLError).traced(ctx, "ReadStartingWithUser"), nil
 }
 
 // MaxTuplesPerWrite see [storage.RelationshipTupleWriter].MaxTuplesPerWrite.
@@ -605,6 +700,7 @@ func constructAuthorizationModelFromSQLRows(rows *sql.Rows) (*openfgav1.Authoriz
 func (s *Datastore) ReadAuthorizationModel(ctx context.Context, store string, modelID string) (*openfgav1.AuthorizationModel, error) {
 	ctx, span := startTrace(ctx, "ReadAuthorizationModel")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	rows, err := s.stbl.
 		Select("authorization_model_id", "schema_version", "serialized_protobuf").
@@ -626,6 +722,8 @@ func (s *Datastore) ReadAuthorizationModel(ctx context.Context, store string, mo
 func (s *Datastore) ReadAuthorizationModels(ctx context.Context, store string, options storage.ReadAuthorizationModelsOptions) ([]*openfgav1.AuthorizationModel, string, error) {
 	ctx, span := startTrace(ctx, "ReadAuthorizationModels")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
 
 	sb := s.stbl.
 		Select("authorization_model_id", "schema_version", "serialized_protobuf").
@@ -675,6 +773,8 @@ func (s *Datastore) ReadAuthorizationModels(ctx context.Context, store string, o
 		return nil, "", HandleSQLError(err)
 	}
 
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(models)))
+
 	return models, token, nil
 }
 
@@ -682,6 +782,7 @@ func (s *Datastore) ReadAuthorizationModels(ctx context.Context, store string, o
 func (s *Datastore) FindLatestAuthorizationModel(ctx context.Context, store string) (*openfgav1.AuthorizationModel, error) {
 	ctx, span := startTrace(ctx, "FindLatestAuthorizationModel")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	rows, err := s.stbl.
 		Select("authorization_model_id", "schema_version", "serialized_protobuf").
@@ -707,6 +808,7 @@ func (s *Datastore) MaxTypesPerAuthorizationModel() int {
 func (s *Datastore) WriteAuthorizationModel(ctx context.Context, store string, model *openfgav1.AuthorizationModel) error {
 	ctx, span := startTrace(ctx, "WriteAuthorizationModel")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	schemaVersion := model.GetSchemaVersion()
 	typeDefinitions := model.GetTypeDefinitions()
@@ -735,22 +837,64 @@ func (s *Datastore) WriteAuthorizationModel(ctx context.Context, store string, m
 	return nil
 }
 
+// DeleteAuthorizationModel see [storage.TypeDefinitionWriteBackend].DeleteAuthorizationModel.
+func (s *Datastore) DeleteAuthorizationModel(ctx context.Context, store string, id string) error {
+	ctx, span := startTrace(ctx, "DeleteAuthorizationModel")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+
+	return busyRetry(func() error {
+		return sqlcommon.DeleteAuthorizationModel(ctx, s.dbInfo, store, id)
+	})
+}
+
 // CreateStore adds a new store to storage.
 func (s *Datastore) CreateStore(ctx context.Context, store *openfgav1.Store) (*openfgav1.Store, error) {
 	ctx, span := startTrace(ctx, "CreateStore")
 	defer span.End()
 
+	return s.createStore(ctx, store, nil)
+}
+
+// CreateStoreWithLabels see [storage.StoresBackend].CreateStoreWithLabels.
+func (s *Datastore) CreateStoreWithLabels(ctx context.Context, store *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
+	ctx, span := startTrace(ctx, "CreateStoreWithLabels")
+	defer span.End()
+
+	return s.createStore(ctx, store, labels)
+}
+
+// createStore inserts the store and its labels in a single transaction.
+func (s *Datastore) createStore(ctx context.Context, store *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error) {
 	var id, name string
 	var createdAt, updatedAt time.Time
 
 	err := busyRetry(func() error {
-		return s.stbl.
+		txn, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = txn.Rollback()
+		}()
+
+		err = s.stbl.
 			Insert("store").
 			Columns("id", "name", "created_at", "updated_at").
 			Values(store.GetId(), store.GetName(), sq.Expr("datetime('subsec')"), sq.Expr("datetime('subsec')")).
 			Suffix("returning id, name, created_at, updated_at").
+			RunWith(txn).
 			QueryRowContext(ctx).
 			Scan(&id, &name, &createdAt, &updatedAt)
+		if err != nil {
+			return err
+		}
+
+		if err := sqlcommon.InsertStoreLabels(ctx, s.stbl, txn, id, labels); err != nil {
+			return err
+		}
+
+		return txn.Commit()
 	})
 	if err != nil {
 		return nil, HandleSQLError(err)
@@ -768,6 +912,7 @@ func (s *Datastore) CreateStore(ctx context.Context, store *openfgav1.Store) (*o
 func (s *Datastore) GetStore(ctx context.Context, id string) (*openfgav1.Store, error) {
 	ctx, span := startTrace(ctx, "GetStore")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
 
 	row := s.stbl.
 		Select("id", "name", "created_at", "updated_at").
@@ -800,6 +945,7 @@ func (s *Datastore) GetStore(ctx context.Context, id string) (*openfgav1.Store,
 func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOptions) ([]*openfgav1.Store, string, error) {
 	ctx, span := startTrace(ctx, "ListStores")
 	defer span.End()
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
 
 	whereClause := sq.And{
 		sq.Eq{"deleted_at": nil},
@@ -813,6 +959,10 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 		whereClause = append(whereClause, sq.Eq{"name": options.Name})
 	}
 
+	if len(options.Labels) > 0 {
+		whereClause = append(whereClause, sqlcommon.StoreLabelsClause(options.Labels))
+	}
+
 	if options.Pagination.From != "" {
 		whereClause = append(whereClause, sq.GtOrEq{"id": options.Pagination.From})
 	}
@@ -856,8 +1006,10 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 	}
 
 	if len(stores) > options.Pagination.PageSize {
+		span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, options.Pagination.PageSize))
 		return stores[:options.Pagination.PageSize], id, nil
 	}
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(stores)))
 
 	return stores, "", nil
 }
@@ -866,12 +1018,33 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 func (s *Datastore) DeleteStore(ctx context.Context, id string) error {
 	ctx, span := startTrace(ctx, "DeleteStore")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
+
+	err := busyRetry(func() error {
+		txn, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = txn.Rollback()
+		}()
+
+		_, err = s.stbl.
+			Update("store").
+			Set("deleted_at", sq.Expr("datetime('subsec')")).
+			Where(sq.Eq{"id": id}).
+			RunWith(txn).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
 
-	_, err := s.stbl.
-		Update("store").
-		Set("deleted_at", sq.Expr("datetime('subsec')")).
-		Where(sq.Eq{"id": id}).
-		ExecContext(ctx)
+		if err := sqlcommon.DeleteStoreLabels(ctx, s.stbl, txn, id); err != nil {
+			return err
+		}
+
+		return txn.Commit()
+	})
 	if err != nil {
 		return HandleSQLError(err)
 	}
@@ -879,10 +1052,82 @@ func (s *Datastore) DeleteStore(ctx context.Context, id string) error {
 	return nil
 }
 
+// WriteStoreLabels see [storage.StoresBackend].WriteStoreLabels.
+func (s *Datastore) WriteStoreLabels(ctx context.Context, id string, labels map[string]string) error {
+	ctx, span := startTrace(ctx, "WriteStoreLabels")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
+
+	return busyRetry(func() error {
+		return sqlcommon.WriteStoreLabels(ctx, s.dbInfo, id, labels)
+	})
+}
+
+// ReadStoreLabels see [storage.StoresBackend].ReadStoreLabels.
+func (s *Datastore) ReadStoreLabels(ctx context.Context, id string) (map[string]string, error) {
+	ctx, span := startTrace(ctx, "ReadStoreLabels")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(id)...)
+
+	return sqlcommon.ReadStoreLabels(ctx, s.dbInfo, id)
+}
+
+// CreateJob see [storage.JobsBackend].CreateJob.
+func (s *Datastore) CreateJob(ctx context.Context, job *storage.Job) error {
+	ctx, span := startTrace(ctx, "CreateJob")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(job.StoreID)...)
+
+	return busyRetry(func() error {
+		return sqlcommon.CreateJob(ctx, s.dbInfo, job)
+	})
+}
+
+// ReadJob see [storage.JobsBackend].ReadJob.
+func (s *Datastore) ReadJob(ctx context.Context, store, id string) (*storage.Job, error) {
+	ctx, span := startTrace(ctx, "ReadJob")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+
+	return sqlcommon.ReadJob(ctx, s.dbInfo, store, id)
+}
+
+// UpdateJob see [storage.JobsBackend].UpdateJob.
+func (s *Datastore) UpdateJob(ctx context.Context, job *storage.Job) error {
+	ctx, span := startTrace(ctx, "UpdateJob")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(job.StoreID)...)
+
+	return busyRetry(func() error {
+		return sqlcommon.UpdateJob(ctx, s.dbInfo, job)
+	})
+}
+
+// RequestJobCancellation see [storage.JobsBackend].RequestJobCancellation.
+func (s *Datastore) RequestJobCancellation(ctx context.Context, store, id string) error {
+	ctx, span := startTrace(ctx, "RequestJobCancellation")
+	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+
+	return busyRetry(func() error {
+		return sqlcommon.RequestJobCancellation(ctx, s.dbInfo, store, id)
+	})
+}
+
+// ClaimInterruptedJobs see [storage.JobsBackend].ClaimInterruptedJobs.
+func (s *Datastore) ClaimInterruptedJobs(ctx context.Context) ([]*storage.Job, error) {
+	ctx, span := startTrace(ctx, "ClaimInterruptedJobs")
+	defer span.End()
+
+	// Not retried on busy errors, as a retry would lose the jobs claimed before the error.
+	return sqlcommon.ClaimInterruptedJobs(ctx, s.dbInfo)
+}
+
 // WriteAssertions see [storage.AssertionsBackend].WriteAssertions.
 func (s *Datastore) WriteAssertions(ctx context.Context, store, modelID string, assertions []*openfgav1.Assertion) error {
 	ctx, span := startTrace(ctx, "WriteAssertions")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	marshalledAssertions, err := proto.Marshal(&openfgav1.Assertions{Assertions: assertions})
 	if err != nil {
@@ -909,6 +1154,7 @@ func (s *Datastore) WriteAssertions(ctx context.Context, store, modelID string,
 func (s *Datastore) ReadAssertions(ctx context.Context, store, modelID string) ([]*openfgav1.Assertion, error) {
 	ctx, span := startTrace(ctx, "ReadAssertions")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
 
 	var marshalledAssertions []byte
 	err := s.stbl.
@@ -940,6 +1186,10 @@ func (s *Datastore) ReadAssertions(ctx context.Context, store, modelID string) (
 func (s *Datastore) ReadChanges(ctx context.Context, store string, filter storage.ReadChangesFilter, options storage.ReadChangesOptions) ([]*openfgav1.TupleChange, string, error) {
 	ctx, span := startTrace(ctx, "ReadChanges")
 	defer span.End()
+	span.SetAttributes(sqlcommon.StoreAttributes(store)...)
+	span.SetAttributes(attribute.String(sqlcommon.ObjectTypeAttribute, filter.ObjectType))
+	span.SetAttributes(sqlcommon.PaginationAttributes(options.Pagination)...)
+	defer s.queryMetrics.Observe(sqlcommon.QueryOperationReadChanges, time.Now())
 
 	objectTypeFilter := filter.ObjectType
 	horizonOffset := filter.HorizonOffset
@@ -964,6 +1214,17 @@ func (s *Datastore) ReadChanges(ctx context.Context, store string, filter storag
 	if objectTypeFilter != "" {
 		sb = sb.Where(sq.Eq{"object_type": objectTypeFilter})
 	}
+	if filter.ObjectID != "" {
+		sb = sb.Where(sq.Eq{"object_id": filter.ObjectID})
+	}
+	if filter.User != "" {
+		userObjectType, userObjectID, userRelation := tupleUtils.ToUserParts(filter.User)
+		sb = sb.Where(sq.Eq{
+			"user_object_type": userObjectType,
+			"user_object_id":   userObjectID,
+			"user_relation":    userRelation,
+		})
+	}
 	if options.Pagination.From != "" {
 		sb = sqlcommon.AddFromUlid(sb, options.Pagination.From, options.SortDesc)
 	}
@@ -1031,6 +1292,8 @@ func (s *Datastore) ReadChanges(ctx context.Context, store string, filter storag
 		return nil, "", storage.ErrNotFound
 	}
 
+	span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, len(changes)))
+
 	return changes, ulid, nil
 }
 
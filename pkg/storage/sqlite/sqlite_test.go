@@ -5,7 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
@@ -185,3 +189,101 @@ func TestReadPageEnsureOrder(t *testing.T) {
 	require.Equal(t, secondTuple, tuples[0].GetKey())
 	require.Equal(t, firstTuple, tuples[1].GetKey())
 }
+
+func TestReadRowsSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	testDatastore := storagefixtures.RunDatastoreTestContainer(t, "sqlite")
+
+	uri := testDatastore.GetConnectionURI(true)
+	ds, err := New(uri, sqlcommon.NewConfig())
+	require.NoError(t, err)
+	defer ds.Close()
+
+	ctx := context.Background()
+	require.NoError(t, ds.Write(ctx, "store", nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("doc:2", "viewer", "user:anne"),
+	}))
+
+	rowsRead := func() []int64 {
+		var rows []int64
+		for _, span := range recorder.Ended() {
+			if span.Name() != "sqlite.readRows" {
+				continue
+			}
+			for _, kv := range span.Attributes() {
+				if kv.Key == sqlcommon.RowsAttribute {
+					rows = append(rows, kv.Value.AsInt64())
+				}
+			}
+		}
+		return rows
+	}
+
+	// the span ends once the iterator is done
+	iter, err := ds.Read(ctx, "store", tuple.NewTupleKey("doc:", "viewer", ""), storage.ReadOptions{})
+	require.NoError(t, err)
+	_, err = iter.Next(ctx)
+	require.NoError(t, err)
+	require.Empty(t, rowsRead())
+	_, err = iter.Next(ctx)
+	require.NoError(t, err)
+	_, err = iter.Next(ctx)
+	require.ErrorIs(t, err, storage.ErrIteratorDone)
+	require.Equal(t, []int64{2}, rowsRead())
+	iter.Stop()
+
+	// or once it is stopped
+	iter, err = ds.Read(ctx, "store", tuple.NewTupleKey("doc:", "viewer", ""), storage.ReadOptions{})
+	require.NoError(t, err)
+	_, err = iter.Head(ctx)
+	require.NoError(t, err)
+	iter.Stop()
+	require.Equal(t, []int64{2, 1}, rowsRead())
+}
+
+func TestQueryMetrics(t *testing.T) {
+	testDatastore := storagefixtures.RunDatastoreTestContainer(t, "sqlite")
+
+	uri := testDatastore.GetConnectionURI(true)
+	ds, err := New(uri, sqlcommon.NewConfig(sqlcommon.WithMetrics()))
+	require.NoError(t, err)
+	defer ds.Close()
+
+	sampleCount := func(operation string) uint64 {
+		families, err := prometheus.DefaultGatherer.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() != "openfga_datastore_query_duration_ms" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				if labels["engine"] == "sqlite" && labels["operation"] == operation {
+					return metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+		return 0
+	}
+
+	ctx := context.Background()
+	tk := tuple.NewTupleKey("doc:1", "viewer", "user:anne")
+	require.NoError(t, ds.Write(ctx, "store", nil, []*openfgav1.TupleKey{tk}))
+	_, err = ds.ReadUserTuple(ctx, "store", tk, storage.ReadUserTupleOptions{})
+	require.NoError(t, err)
+	iter, err := ds.Read(ctx, "store", tuple.NewTupleKey("doc:", "viewer", ""), storage.ReadOptions{})
+	require.NoError(t, err)
+	iter.Stop()
+
+	require.Equal(t, uint64(1), sampleCount(sqlcommon.QueryOperationWrite))
+	require.Equal(t, uint64(1), sampleCount(sqlcommon.QueryOperationReadUserTuple))
+	require.Equal(t, uint64(1), sampleCount(sqlcommon.QueryOperationRead))
+}
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/sqlcommon"
 )
 
 type errorHandlerFn func(error, ...interface{}) error
@@ -27,6 +30,10 @@ type SQLTupleIterator struct {
 	// will use this item instead. Otherwise, the first item will be lost.
 	firstRow *storage.TupleRecord // GUARDED_BY(mu)
 	mu       sync.Mutex
+
+	// span traces the reading of the rows, if traced, until the iterator is done or stopped.
+	span     trace.Span // GUARDED_BY(mu)
+	rowsRead int        // GUARDED_BY(mu)
 }
 
 // Ensures that SQLTupleIterator implements the TupleIterator interface.
@@ -42,6 +49,23 @@ func NewSQLTupleIterator(rows *sql.Rows, errHandler errorHandlerFn) *SQLTupleIte
 	}
 }
 
+// traced traces the reading of the rows of the query of operation, recording their number once the iterator is done
+// or stopped.
+func (t *SQLTupleIterator) traced(ctx context.Context, operation string) *SQLTupleIterator {
+	_, t.span = tracer.Start(ctx, "sqlite.readRows", trace.WithAttributes(attribute.String(sqlcommon.OperationAttribute, operation)))
+	return t
+}
+
+// endSpan ends the span of the rows, recording their number. The caller must hold mu.
+func (t *SQLTupleIterator) endSpan() {
+	if t.span == nil {
+		return
+	}
+	t.span.SetAttributes(attribute.Int(sqlcommon.RowsAttribute, t.rowsRead))
+	t.span.End()
+	t.span = nil
+}
+
 func (t *SQLTupleIterator) next() (*storage.TupleRecord, error) {
 	t.mu.Lock()
 
@@ -63,6 +87,7 @@ func (t *SQLTupleIterator) next() (*storage.TupleRecord, error) {
 
 	if !t.rows.Next() {
 		err := t.rows.Err()
+		t.endSpan()
 		t.mu.Unlock()
 		if err != nil {
 			return nil, t.handleSQLError(err)
@@ -86,6 +111,9 @@ func (t *SQLTupleIterator) next() (*storage.TupleRecord, error) {
 		&record.Ulid,
 		&record.InsertedAt,
 	)
+	if err == nil {
+		t.rowsRead++
+	}
 	t.mu.Unlock()
 
 	if err != nil {
@@ -124,6 +152,7 @@ func (t *SQLTupleIterator) head() (*storage.TupleRecord, error) {
 	}
 
 	if !t.rows.Next() {
+		t.endSpan()
 		if err := t.rows.Err(); err != nil {
 			return nil, t.handleSQLError(err)
 		}
@@ -149,6 +178,7 @@ func (t *SQLTupleIterator) head() (*storage.TupleRecord, error) {
 	if err != nil {
 		return nil, t.handleSQLError(err)
 	}
+	t.rowsRead++
 
 	record.ConditionName = conditionName.String
 
@@ -225,5 +255,8 @@ func (t *SQLTupleIterator) Head(ctx context.Context) (*openfgav1.Tuple, error) {
 
 // Stop terminates iteration.
 func (t *SQLTupleIterator) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	_ = t.rows.Close()
+	t.endSpan()
 }
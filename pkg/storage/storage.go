@@ -88,7 +88,9 @@ type ListStoresOptions struct {
 	// IDs is a list of store IDs to filter the results.
 	IDs []string
 	// Name is used to filter the results. If left empty no filter is applied.
-	Name       string
+	Name string
+	// Labels is used to filter the results to the stores holding all of these labels. If left empty no filter is applied.
+	Labels     map[string]string
 	Pagination PaginationOptions
 }
 
@@ -104,6 +106,11 @@ type ReadChangesOptions struct {
 type ReadPageOptions struct {
 	Pagination  PaginationOptions
 	Consistency ConsistencyOptions
+	// Relations, if set, restricts the tuples read to those whose relation is one of Relations.
+	Relations []string
+	// UserType, if set, restricts the tuples read to those whose user is of the UserType object type, e.g. 'user'
+	// matches 'user:anne' and 'user:*', and 'group' matches 'group:eng#member'.
+	UserType string
 }
 
 // ConsistencyOptions represents the options that can
@@ -176,6 +183,17 @@ type RelationshipTupleReader interface {
 		options ReadUserTupleOptions,
 	) (*openfgav1.Tuple, error)
 
+	// ReadUserTuples is the batched form of ReadUserTuple. It returns the tuples that match
+	// any of the provided keys exactly, using a single round-trip to the datastore where possible.
+	// Keys without a matching tuple are omitted from the result rather than returning [ErrNotFound].
+	// There is NO guarantee on the order of the returned tuples.
+	ReadUserTuples(
+		ctx context.Context,
+		store string,
+		tupleKeys []*openfgav1.TupleKey,
+		options ReadUserTupleOptions,
+	) ([]*openfgav1.Tuple, error)
+
 	// ReadUsersetTuples returns all userset tuples for a specified object and relation.
 	// For example, given the following relationship tuples:
 	//	document:doc1, viewer, user:*
@@ -274,6 +292,10 @@ type TypeDefinitionWriteBackend interface {
 	// WriteAuthorizationModel writes an authorization model for the given store.
 	// If the model has zero types, the datastore may choose to do nothing and return no error.
 	WriteAuthorizationModel(ctx context.Context, store string, model *openfgav1.AuthorizationModel) error
+
+	// DeleteAuthorizationModel deletes the model of the given store, along with its assertions.
+	// If the model is not found, it must return ErrNotFound.
+	DeleteAuthorizationModel(ctx context.Context, store string, id string) error
 }
 
 // AuthorizationModelBackend provides an read/write interface for managing models and their type definitions.
@@ -287,7 +309,12 @@ type StoresBackend interface {
 	// If the store ID already existed it must return ErrCollision.
 	CreateStore(ctx context.Context, store *openfgav1.Store) (*openfgav1.Store, error)
 
-	// DeleteStore must delete the store by either setting its DeletedAt field or removing the entry.
+	// CreateStoreWithLabels creates a store as CreateStore does, with labels, atomically: the store is never visible
+	// without its labels.
+	CreateStoreWithLabels(ctx context.Context, store *openfgav1.Store, labels map[string]string) (*openfgav1.Store, error)
+
+	// DeleteStore must delete the store by either setting its DeletedAt field or removing the entry. It must delete
+	// the labels of the store along with it.
 	DeleteStore(ctx context.Context, id string) error
 
 	// GetStore must return ErrNotFound if the store is not found or its DeletedAt is set.
@@ -297,6 +324,13 @@ type StoresBackend interface {
 	// In addition to the stores, it returns a continuation token that can be used to fetch the next page of results.
 	// If no stores are found, it is expected to return an empty list and an empty continuation token.
 	ListStores(ctx context.Context, options ListStoresOptions) ([]*openfgav1.Store, string, error)
+
+	// WriteStoreLabels replaces the labels of a store. It must return ErrNotFound if the store is not found or
+	// its DeletedAt is set.
+	WriteStoreLabels(ctx context.Context, id string, labels map[string]string) error
+
+	// ReadStoreLabels returns the labels of a store. If no labels were ever written, it must return an empty map.
+	ReadStoreLabels(ctx context.Context, id string) (map[string]string, error)
 }
 
 // AssertionsBackend is an interface that defines the set of methods for reading and writing assertions.
@@ -309,8 +343,75 @@ type AssertionsBackend interface {
 	ReadAssertions(ctx context.Context, store, modelID string) ([]*openfgav1.Assertion, error)
 }
 
+// JobStatus is the status of a [Job].
+type JobStatus string
+
+const (
+	// JobStatusPending is the status of a job waiting to be run.
+	JobStatusPending JobStatus = "pending"
+	// JobStatusRunning is the status of a job being run.
+	JobStatusRunning JobStatus = "running"
+	// JobStatusSucceeded is the status of a job which completed.
+	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusFailed is the status of a job which stopped on an error.
+	JobStatusFailed JobStatus = "failed"
+	// JobStatusCancelled is the status of a job which stopped as its cancellation was requested.
+	JobStatusCancelled JobStatus = "cancelled"
+	// JobStatusInterrupted is the status of a job which stopped as its server shut down. It is resumed from its
+	// Progress once claimed with ClaimInterruptedJobs.
+	JobStatusInterrupted JobStatus = "interrupted"
+)
+
+// Job is an operation on the tuples of a store run in the background, e.g. an import of tuples, whose status is
+// persisted so that it can be followed beyond the request which started it.
+type Job struct {
+	ID      string
+	StoreID string
+	// Type is the kind of operation, e.g. 'import_tuples'.
+	Type string
+	// Params are the parameters of the operation, encoded according to its type.
+	Params []byte
+	Status JobStatus
+	// Progress is the number of tuples processed so far.
+	Progress int64
+	// Result is the outcome of the operation once it succeeded, or its partial outcome so far, encoded according to
+	// its type.
+	Result []byte
+	// Error is the reason the operation failed.
+	Error string
+	// CancelRequested is set once the operation is requested to stop.
+	CancelRequested bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// JobsBackend is an interface that defines the set of methods for persisting the jobs of the stores.
+type JobsBackend interface {
+	// CreateJob persists a new job, setting its CreatedAt and UpdatedAt.
+	CreateJob(ctx context.Context, job *Job) error
+
+	// ReadJob returns a job of a store. It must return ErrNotFound if the job is not found.
+	ReadJob(ctx context.Context, store, id string) (*Job, error)
+
+	// UpdateJob persists the Status, Progress, Result and Error of a job, and sets its UpdatedAt. It must return
+	// ErrNotFound if the job is not found.
+	UpdateJob(ctx context.Context, job *Job) error
+
+	// RequestJobCancellation sets the CancelRequested of a job. It must return ErrNotFound if the job is not found.
+	RequestJobCancellation(ctx context.Context, store, id string) error
+
+	// ClaimInterruptedJobs sets the interrupted jobs of every store back to pending and returns them. Each job must be
+	// returned to a single caller, even if several claim the interrupted jobs at once. On error, it returns the jobs
+	// claimed before the error along with it.
+	ClaimInterruptedJobs(ctx context.Context) ([]*Job, error)
+}
+
 type ReadChangesFilter struct {
-	ObjectType    string
+	ObjectType string
+	// ObjectID restricts the changes to those of the object with this id. It is only set along with ObjectType.
+	ObjectID string
+	// User restricts the changes to those of tuples with this user, e.g. 'user:anne' or 'group:eng#member'.
+	User          string
 	HorizonOffset time.Duration
 }
 
@@ -334,6 +435,7 @@ type OpenFGADatastore interface {
 	StoresBackend
 	AssertionsBackend
 	ChangelogBackend
+	JobsBackend
 
 	// IsReady reports whether the datastore is ready to accept traffic.
 	IsReady(ctx context.Context) (ReadinessStatus, error)
@@ -347,5 +449,22 @@ type ReadinessStatus struct {
 	// Message is a human-friendly status message for the current datastore status.
 	Message string
 
+	// Revision is the schema revision of the datastore, if it has migrations.
+	Revision int64
+
 	IsReady bool
 }
+
+// TupleChangeNotification identifies the tuples of an object type written to a store.
+type TupleChangeNotification struct {
+	StoreID    string
+	ObjectType string
+}
+
+// TupleChangeListener is implemented by datastores able to notify the tuple writes of every server
+// sharing them, e.g. to invalidate the caches of the servers that did not perform the write.
+type TupleChangeListener interface {
+	// ListenTupleChanges calls handler for every notified tuple write until ctx is done or the
+	// notifications are interrupted, in which case it returns the error.
+	ListenTupleChanges(ctx context.Context, handler func(TupleChangeNotification)) error
+}
@@ -2,6 +2,7 @@ package storagewrappers
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -13,8 +14,10 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/loadshedding"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/telemetry"
 )
 
 const timeWaitingAttribute = "datastore_time_waiting"
@@ -63,11 +66,18 @@ type BoundedTupleReader struct {
 	threshold    int
 	throttleTime time.Duration
 	throttled    atomic.Bool
+
+	saturationMonitor *LimiterSaturationMonitor
+	hedgeDelay        time.Duration
 }
 
 // NewBoundedTupleReader returns a wrapper over a datastore that makes sure that there are, at most,
 // "concurrency" concurrent calls to Read, ReadUserTuple and ReadUsersetTuples.
 // Consumers can then rest assured that one client will not hoard all the database connections available.
+//
+// If the operation has a hedge delay, a read taking longer than it is hedged: an identical read is issued if the
+// limiter has room for it, and the result of whichever returns first is used. The reads returning an iterator include
+// the query of its first tuples.
 func NewBoundedTupleReader(wrapped storage.RelationshipTupleReader, op *Operation) *BoundedTupleReader {
 	return &BoundedTupleReader{
 		RelationshipTupleReader: wrapped,
@@ -77,6 +87,9 @@ func NewBoundedTupleReader(wrapped storage.RelationshipTupleReader, op *Operatio
 		method:       string(op.Method),
 		threshold:    op.ThrottleThreshold,
 		throttleTime: op.ThrottleDuration,
+
+		saturationMonitor: op.SaturationMonitor,
+		hedgeDelay:        op.HedgeDelay,
 	}
 }
 
@@ -94,24 +107,59 @@ func (b *BoundedTupleReader) ReadUserTuple(
 	tupleKey *openfgav1.TupleKey,
 	options storage.ReadUserTupleOptions,
 ) (*openfgav1.Tuple, error) {
+	defer loadshedding.Track(ctx)()
+
 	err := b.bound(ctx, storagewrappersutil.OperationReadUserTuple)
 	if err != nil {
 		return nil, err
 	}
 
 	defer b.done()
-	return b.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	return hedge(ctx, b, storagewrappersutil.OperationReadUserTuple, func(ctx context.Context) (*openfgav1.Tuple, error) {
+		return b.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	})
+}
+
+// ReadUserTuples returns the tuples that match any of the provided keys exactly.
+func (b *BoundedTupleReader) ReadUserTuples(
+	ctx context.Context,
+	store string,
+	tupleKeys []*openfgav1.TupleKey,
+	options storage.ReadUserTupleOptions,
+) ([]*openfgav1.Tuple, error) {
+	defer loadshedding.Track(ctx)()
+
+	err := b.bound(ctx, storagewrappersutil.OperationReadUserTuples)
+	if err != nil {
+		return nil, err
+	}
+
+	defer b.done()
+	return hedge(ctx, b, storagewrappersutil.OperationReadUserTuples, func(ctx context.Context) ([]*openfgav1.Tuple, error) {
+		return b.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	})
 }
 
 // Read the set of tuples associated with `store` and `TupleKey`, which may be nil or partially filled.
 func (b *BoundedTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	// the read is load until its iterator is stopped
+	untrack := loadshedding.Track(ctx)
+
 	err := b.bound(ctx, storagewrappersutil.OperationRead)
 	if err != nil {
+		untrack()
 		return nil, err
 	}
 
 	defer b.done()
-	return b.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	iter, err := hedgeIterator(ctx, b, storagewrappersutil.OperationRead, func(ctx context.Context) (storage.TupleIterator, error) {
+		return b.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	})
+	if err != nil {
+		untrack()
+		return nil, err
+	}
+	return newTrackedTupleIterator(iter, untrack), nil
 }
 
 // ReadUsersetTuples returns all userset tuples for a specified object and relation.
@@ -121,13 +169,24 @@ func (b *BoundedTupleReader) ReadUsersetTuples(
 	filter storage.ReadUsersetTuplesFilter,
 	options storage.ReadUsersetTuplesOptions,
 ) (storage.TupleIterator, error) {
+	// the read is load until its iterator is stopped
+	untrack := loadshedding.Track(ctx)
+
 	err := b.bound(ctx, storagewrappersutil.OperationReadUsersetTuples)
 	if err != nil {
+		untrack()
 		return nil, err
 	}
 
 	defer b.done()
-	return b.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	iter, err := hedgeIterator(ctx, b, storagewrappersutil.OperationReadUsersetTuples, func(ctx context.Context) (storage.TupleIterator, error) {
+		return b.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	})
+	if err != nil {
+		untrack()
+		return nil, err
+	}
+	return newTrackedTupleIterator(iter, untrack), nil
 }
 
 // ReadStartingWithUser performs a reverse read of relationship tuples starting at one or
@@ -138,18 +197,51 @@ func (b *BoundedTupleReader) ReadStartingWithUser(
 	filter storage.ReadStartingWithUserFilter,
 	options storage.ReadStartingWithUserOptions,
 ) (storage.TupleIterator, error) {
+	// the read is load until its iterator is stopped
+	untrack := loadshedding.Track(ctx)
+
 	err := b.bound(ctx, storagewrappersutil.OperationReadStartingWithUser)
 	if err != nil {
+		untrack()
 		return nil, err
 	}
 
 	defer b.done()
+	iter, err := hedgeIterator(ctx, b, storagewrappersutil.OperationReadStartingWithUser, func(ctx context.Context) (storage.TupleIterator, error) {
+		return b.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	})
+	if err != nil {
+		untrack()
+		return nil, err
+	}
+	return newTrackedTupleIterator(iter, untrack), nil
+}
 
-	return b.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+// trackedTupleIterator is a [storage.TupleIterator] recording the end of its read with the admission controller once
+// it is stopped, so that the reads count as load for as long as they are iterated.
+type trackedTupleIterator struct {
+	storage.TupleIterator
+	untrack func()
+	once    sync.Once
+}
+
+func newTrackedTupleIterator(iter storage.TupleIterator, untrack func()) storage.TupleIterator {
+	if iter == nil {
+		// There is no iterator to stop, and a wrapped nil iterator would not be skipped as one by the callers.
+		untrack()
+		return nil
+	}
+	return &trackedTupleIterator{TupleIterator: iter, untrack: untrack}
+}
+
+// Stop see [storage.Iterator].Stop.
+func (t *trackedTupleIterator) Stop() {
+	t.TupleIterator.Stop()
+	t.once.Do(t.untrack)
 }
 
 func (b *BoundedTupleReader) instrument(ctx context.Context, op string, d time.Duration, vec *prometheus.HistogramVec) {
-	vec.WithLabelValues(op, b.method).Observe(float64(d))
+	telemetry.ObserveWithTraceExemplar(ctx, vec.WithLabelValues(op, b.method), float64(d))
 
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(attribute.Int64(timeWaitingAttribute, d.Milliseconds()))
@@ -163,9 +255,11 @@ func (b *BoundedTupleReader) bound(ctx context.Context, op string) error {
 		return err
 	}
 
-	if c := time.Since(startTime); c > concurrentTimeWaitingThreshold {
+	c := time.Since(startTime)
+	if c > concurrentTimeWaitingThreshold {
 		b.instrument(ctx, op, c, concurrentReadDelayMsHistogram)
 	}
+	b.saturationMonitor.Observe(c)
 
 	reads := b.increaseReads()
 
@@ -193,6 +287,17 @@ func (b *BoundedTupleReader) waitForLimiter(ctx context.Context) error {
 	return nil
 }
 
+// tryBound acquires a slot of the limiter without waiting for one, and reports whether it did.
+func (b *BoundedTupleReader) tryBound() bool {
+	select {
+	case b.limiter <- struct{}{}:
+		b.increaseReads()
+		return true
+	default:
+		return false
+	}
+}
+
 func (b *BoundedTupleReader) done() {
 	select {
 	case <-b.limiter:
@@ -13,6 +13,7 @@ import (
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/internal/loadshedding"
 	"github.com/openfga/openfga/internal/mocks"
 	"github.com/openfga/openfga/internal/utils/apimethod"
 	"github.com/openfga/openfga/pkg/storage"
@@ -189,3 +190,65 @@ func TestBoundedConcurrencyWrapper_Exits_Early_If_Context_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestBoundedTupleReaderTracksReadsUntilTheirIteratorIsStopped(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	store := ulid.Make().String()
+
+	// every low priority request is shed while a read is in flight
+	admissionController := loadshedding.NewAdmissionController(1)
+	ctx := loadshedding.ContextWithAdmissionController(context.Background(), admissionController)
+	dut := NewBoundedTupleReader(ds, &Operation{Concurrency: 1, Method: apimethod.Check})
+
+	var testCases = map[string]func() (storage.TupleIterator, error){
+		`read`: func() (storage.TupleIterator, error) {
+			return dut.Read(ctx, store, &openfgav1.TupleKey{Object: "document:1"}, storage.ReadOptions{})
+		},
+		`read_userset_tuples`: func() (storage.TupleIterator, error) {
+			return dut.ReadUsersetTuples(ctx, store, storage.ReadUsersetTuplesFilter{Object: "document:1", Relation: "viewer"}, storage.ReadUsersetTuplesOptions{})
+		},
+		`read_starting_with_user`: func() (storage.TupleIterator, error) {
+			return dut.ReadStartingWithUser(ctx, store, storage.ReadStartingWithUserFilter{
+				ObjectType: "document",
+				Relation:   "viewer",
+				UserFilter: []*openfgav1.ObjectRelation{{Object: "user:anne"}},
+			}, storage.ReadStartingWithUserOptions{})
+		},
+	}
+
+	for testName, read := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			iter, err := read()
+			require.NoError(t, err)
+			require.Error(t, admissionController.Admit(apimethod.ListObjects.String()))
+
+			iter.Stop()
+			require.NoError(t, admissionController.Admit(apimethod.ListObjects.String()))
+
+			// stopping the iterator again does not record the end of the read twice
+			iter.Stop()
+			iter, err = read()
+			require.NoError(t, err)
+			require.Error(t, admissionController.Admit(apimethod.ListObjects.String()))
+			iter.Stop()
+		})
+	}
+
+	t.Run("nil_iterator", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().Read(gomock.Any(), store, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		dut := NewBoundedTupleReader(mockDatastore, &Operation{Concurrency: 1, Method: apimethod.Check})
+		iter, err := dut.Read(ctx, store, &openfgav1.TupleKey{Object: "document:1"}, storage.ReadOptions{})
+		require.NoError(t, err)
+		require.Nil(t, iter)
+		require.NoError(t, admissionController.Admit(apimethod.ListObjects.String()))
+	})
+}
@@ -3,6 +3,7 @@ package storagewrappers
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +19,7 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
@@ -77,6 +79,13 @@ func WithCachedDatastoreLogger(logger logger.Logger) CachedDatastoreOpt {
 	}
 }
 
+// WithCachedDatastoreClock sets the clock the cached iterators are timestamped with.
+func WithCachedDatastoreClock(clock clock.Clock) CachedDatastoreOpt {
+	return func(b *CachedDatastore) {
+		b.clock = clock
+	}
+}
+
 // WithCachedDatastoreMethodName is used in metric differentiation to tell us if this was Check or ListObjects.
 func WithCachedDatastoreMethodName(method string) CachedDatastoreOpt {
 	return func(b *CachedDatastore) {
@@ -102,6 +111,7 @@ type CachedDatastore struct {
 	wg *sync.WaitGroup
 
 	logger logger.Logger
+	clock  clock.Clock
 
 	method string // Whether this datastore is for Check or ListObjects
 }
@@ -126,6 +136,7 @@ func NewCachedDatastore(
 		sf:                      sf,
 		wg:                      wg,
 		logger:                  logger.NewNoopLogger(),
+		clock:                   clock.New(),
 		method:                  "",
 	}
 
@@ -363,6 +374,13 @@ func (c *CachedDatastore) newCachedIterator(
 	span.SetAttributes(attribute.String("cache_key", cacheKey))
 	tuplesCacheTotalCounter.WithLabelValues(operation, c.method).Inc()
 
+	// the entries are also invalidated by the writes of tuples of their object type notified by other servers
+	var objectTypeKey string
+	if objectType != "" {
+		objectTypeKey = storage.GetInvalidIteratorByObjectTypeCacheKey(store, objectType)
+		invalidEntityKeys = append(slices.Clip(invalidEntityKeys), objectTypeKey)
+	}
+
 	if cacheEntry, ok := findInCache(c.cache, store, cacheKey, invalidEntityKeys, c.logger); ok {
 		tuplesCacheHitCounter.WithLabelValues(operation, c.method).Inc()
 		span.SetAttributes(attribute.Bool("cached", true))
@@ -395,6 +413,7 @@ func (c *CachedDatastore) newCachedIterator(
 		tuples:            make([]*openfgav1.Tuple, 0, c.maxResultSize/2),
 		cacheKey:          cacheKey,
 		invalidEntityKeys: invalidEntityKeys,
+		objectTypeKey:     objectTypeKey,
 		cache:             c.cache,
 		maxResultSize:     c.maxResultSize,
 		ttl:               c.ttl,
@@ -405,6 +424,7 @@ func (c *CachedDatastore) newCachedIterator(
 		userType:          userType,
 		wg:                c.wg,
 		logger:            c.logger,
+		clock:             c.clock,
 	}, nil
 }
 
@@ -416,8 +436,12 @@ type cachedIterator struct {
 	method            string
 	cacheKey          string
 	invalidEntityKeys []string
-	cache             storage.InMemoryCache[any]
-	ttl               time.Duration
+	// objectTypeKey is the invalid entity key of the object type, shared by the entries of other objects, so that it
+	// is not deleted when the entry is flushed.
+	objectTypeKey string
+	cache         storage.InMemoryCache[any]
+	ttl           time.Duration
+	clock         clock.Clock
 
 	objectID   string
 	objectType string
@@ -575,16 +599,10 @@ func (c *cachedIterator) Head(ctx context.Context) (*openfgav1.Tuple, error) {
 	return c.iter.Head(ctx)
 }
 
-// addToBuffer converts a proto tuple into a simpler storage.TupleRecord, removes
-// any already known fields and adds it to the buffer if not yet full.
-func (c *cachedIterator) addToBuffer(t *openfgav1.Tuple) bool {
-	if c.tuples == nil {
-		return false
-	}
-
+// newTupleRecord converts a proto tuple into a simpler storage.TupleRecord.
+func newTupleRecord(t *openfgav1.Tuple) *storage.TupleRecord {
 	tk := t.GetKey()
-	object := tk.GetObject()
-	objectType, objectID := tuple.SplitObject(object)
+	objectType, objectID := tuple.SplitObject(tk.GetObject())
 	userObjectType, userObjectID, userRelation := tuple.ToUserParts(tk.GetUser())
 
 	record := &storage.TupleRecord{
@@ -605,6 +623,18 @@ func (c *cachedIterator) addToBuffer(t *openfgav1.Tuple) bool {
 		record.ConditionContext = condition.GetContext()
 	}
 
+	return record
+}
+
+// addToBuffer converts a proto tuple into a simpler storage.TupleRecord, removes
+// any already known fields and adds it to the buffer if not yet full.
+func (c *cachedIterator) addToBuffer(t *openfgav1.Tuple) bool {
+	if c.tuples == nil {
+		return false
+	}
+
+	record := newTupleRecord(t)
+
 	// Remove any fields that are duplicated and known by iterator
 	if c.objectID != "" && c.objectID == record.ObjectID {
 		record.ObjectID = ""
@@ -648,9 +678,11 @@ func (c *cachedIterator) flush() {
 	c.records = nil
 
 	c.logger.Debug("cachedIterator flush and update cache for ", zap.String("cacheKey", c.cacheKey))
-	c.cache.Set(c.cacheKey, &storage.TupleIteratorCacheEntry{Tuples: records, LastModified: time.Now()}, c.ttl)
+	c.cache.Set(c.cacheKey, &storage.TupleIteratorCacheEntry{Tuples: records, LastModified: c.clock.Now()}, c.ttl)
 	for _, k := range c.invalidEntityKeys {
-		c.cache.Delete(k)
+		if k != c.objectTypeKey {
+			c.cache.Delete(k)
+		}
 	}
 	tuplesCacheSizeHistogram.WithLabelValues(c.operation, c.method).Observe(float64(len(records)))
 }
@@ -20,6 +20,7 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/clock"
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/testutils"
@@ -253,6 +254,7 @@ func TestReadStartingWithUser(t *testing.T) {
 				mockCache.EXPECT().Get(storage.GetInvalidIteratorCacheKey(storeID)).Return(nil),
 				mockCache.EXPECT().Get(invalidEntityKeys[0]).Return(nil),
 				mockCache.EXPECT().Get(invalidEntityKeys[1]).Return(nil),
+				mockCache.EXPECT().Get(storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, filter.ObjectType)).Return(nil),
 			)
 
 			iter, err := ds.ReadStartingWithUser(ctx, storeID, filter, options)
@@ -298,6 +300,7 @@ func TestReadStartingWithUser(t *testing.T) {
 				// in the invalidation record keys when calling invalidateIteratorCacheByUserAndObjectType
 				mockCache.EXPECT().Get(invalidEntityKeysWithRelation[0]).Return(nil),
 				mockCache.EXPECT().Get(invalidEntityKeysWithRelation[1]).Return(nil),
+				mockCache.EXPECT().Get(storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, filterWithUserRelation.ObjectType)).Return(nil),
 			)
 
 			iter, err := ds.ReadStartingWithUser(ctx, storeID, filterWithUserRelation, options)
@@ -505,6 +508,7 @@ func TestReadUsersetTuples(t *testing.T) {
 			mockCache.EXPECT().Get(gomock.Any()).Return(&storage.TupleIteratorCacheEntry{Tuples: cachedTuples}),
 			mockCache.EXPECT().Get(storage.GetInvalidIteratorCacheKey(storeID)).Return(nil),
 			mockCache.EXPECT().Get(storage.GetInvalidIteratorByObjectRelationCacheKey(storeID, filter.Object, filter.Relation)).Return(nil),
+			mockCache.EXPECT().Get(storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, tuple.GetType(filter.Object))).Return(nil),
 		)
 
 		iter, err := ds.ReadUsersetTuples(ctx, storeID, filter, options)
@@ -701,6 +705,7 @@ func TestRead(t *testing.T) {
 			mockCache.EXPECT().Get(gomock.Any()).Return(&storage.TupleIteratorCacheEntry{Tuples: cachedTuples}),
 			mockCache.EXPECT().Get(storage.GetInvalidIteratorCacheKey(storeID)).Return(nil),
 			mockCache.EXPECT().Get(storage.GetInvalidIteratorByObjectRelationCacheKey(storeID, tk.GetObject(), tk.GetRelation())),
+			mockCache.EXPECT().Get(storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, tuple.GetType(tk.GetObject()))),
 		)
 
 		iter, err := ds.Read(ctx, storeID, tk, storage.ReadOptions{})
@@ -941,6 +946,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		_, err = iter.Next(ctx)
@@ -982,6 +988,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		_, err = iter.Next(ctx)
@@ -1017,6 +1024,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		var actual []*openfgav1.Tuple
@@ -1074,6 +1082,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		iter.Stop()
@@ -1112,6 +1121,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		var actual []*openfgav1.Tuple
@@ -1175,6 +1185,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		iter.Stop()
@@ -1219,6 +1230,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		cancelledCtx, cancel := context.WithCancel(context.Background())
@@ -1284,6 +1296,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		wg.Add(1)
@@ -1347,6 +1360,7 @@ func TestCachedIterator(t *testing.T) {
 			relation:          "",
 			userType:          "",
 			logger:            logger.NewNoopLogger(),
+			clock:             clock.New(),
 		}
 
 		wg.Add(1)
@@ -1404,6 +1418,7 @@ func TestCachedIterator(t *testing.T) {
 				relation:          "",
 				userType:          "",
 				logger:            logger.NewNoopLogger(),
+				clock:             clock.New(),
 			}
 
 			mockedIter2 := &mockCalledTupleIterator{
@@ -1427,6 +1442,7 @@ func TestCachedIterator(t *testing.T) {
 				relation:          "",
 				userType:          "",
 				logger:            logger.NewNoopLogger(),
+				clock:             clock.New(),
 			}
 
 			wg.Add(2)
@@ -1487,6 +1503,7 @@ func TestCachedIterator(t *testing.T) {
 			sf:                &singleflight.Group{},
 			wg:                wg,
 			logger:            mockLogger,
+			clock:             clock.New(),
 		}
 
 		iter.tuples = []*openfgav1.Tuple{{}}
@@ -0,0 +1,129 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/clock"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+var _ storage.RelationshipTupleReader = (*CachedTupleReader)(nil)
+
+type CachedTupleReaderOpt func(*CachedTupleReader)
+
+// WithCachedTupleReaderLogger sets the logger for the CachedTupleReader.
+func WithCachedTupleReaderLogger(logger logger.Logger) CachedTupleReaderOpt {
+	return func(c *CachedTupleReader) {
+		c.logger = logger
+	}
+}
+
+// WithCachedTupleReaderClock sets the clock the cached tuples are timestamped with.
+func WithCachedTupleReaderClock(clock clock.Clock) CachedTupleReaderOpt {
+	return func(c *CachedTupleReader) {
+		c.clock = clock
+	}
+}
+
+// WithCachedTupleReaderMethodName is used in metric differentiation to tell us which API method read the tuples.
+func WithCachedTupleReaderMethodName(method string) CachedTupleReaderOpt {
+	return func(c *CachedTupleReader) {
+		c.method = method
+	}
+}
+
+// CachedTupleReader is a wrapper over a datastore that caches the results of ReadUserTuple, including the tuples
+// which were not found, across requests. Cached results are invalidated alongside the iterators of
+// [CachedDatastore] reading the same object and relation.
+type CachedTupleReader struct {
+	storage.RelationshipTupleReader
+
+	cache  storage.InMemoryCache[any]
+	ttl    time.Duration
+	logger logger.Logger
+	clock  clock.Clock
+	method string
+}
+
+// NewCachedTupleReader returns a wrapper over a datastore that caches the results of ReadUserTuple for ttl.
+func NewCachedTupleReader(
+	inner storage.RelationshipTupleReader,
+	cache storage.InMemoryCache[any],
+	ttl time.Duration,
+	opts ...CachedTupleReaderOpt,
+) *CachedTupleReader {
+	c := &CachedTupleReader{
+		RelationshipTupleReader: inner,
+		cache:                   cache,
+		ttl:                     ttl,
+		logger:                  logger.NewNoopLogger(),
+		clock:                   clock.New(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// ReadUserTuple see [storage.RelationshipTupleReader].ReadUserTuple.
+func (c *CachedTupleReader) ReadUserTuple(
+	ctx context.Context,
+	store string,
+	tupleKey *openfgav1.TupleKey,
+	options storage.ReadUserTupleOptions,
+) (*openfgav1.Tuple, error) {
+	ctx, span := tracer.Start(
+		ctx,
+		"cache.ReadUserTuple",
+		trace.WithAttributes(attribute.Bool("cached", false)),
+	)
+	defer span.End()
+
+	if options.Consistency.Preference == openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY {
+		return c.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	}
+
+	cacheKey := storagewrappersutil.ReadUserTupleKey(store, tupleKey)
+	invalidEntityKeys := []string{
+		storage.GetInvalidIteratorByObjectRelationCacheKey(store, tupleKey.GetObject(), tupleKey.GetRelation()),
+		storage.GetInvalidIteratorByObjectTypeCacheKey(store, tuple.GetType(tupleKey.GetObject())),
+	}
+	tuplesCacheTotalCounter.WithLabelValues(storagewrappersutil.OperationReadUserTuple, c.method).Inc()
+
+	if cacheEntry, ok := findInCache(c.cache, store, cacheKey, invalidEntityKeys, c.logger); ok {
+		tuplesCacheHitCounter.WithLabelValues(storagewrappersutil.OperationReadUserTuple, c.method).Inc()
+		span.SetAttributes(attribute.Bool("cached", true))
+
+		if len(cacheEntry.Tuples) == 0 {
+			return nil, storage.ErrNotFound
+		}
+		return cacheEntry.Tuples[0].AsTuple(), nil
+	}
+
+	// The entry is as old as the read, so that writes made while reading invalidate it.
+	readAt := c.clock.Now()
+	t, err := c.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	var records []*storage.TupleRecord
+	if t != nil {
+		records = []*storage.TupleRecord{newTupleRecord(t)}
+	}
+	c.cache.Set(cacheKey, &storage.TupleIteratorCacheEntry{Tuples: records, LastModified: readAt}, c.ttl)
+
+	return t, err
+}
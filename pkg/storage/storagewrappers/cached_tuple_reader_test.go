@@ -0,0 +1,127 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestCachedTupleReader(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKeyWithCondition("document:1", "viewer", "user:anne", "in_office", nil)
+	expected := &openfgav1.Tuple{Key: tk, Timestamp: timestamppb.New(time.Unix(1700000000, 0))}
+
+	newReader := func(t *testing.T) (*mocks.MockOpenFGADatastore, *CachedTupleReader) {
+		mockController := gomock.NewController(t)
+		t.Cleanup(mockController.Finish)
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+
+		cache, err := storage.NewInMemoryLRUCache[any]()
+		require.NoError(t, err)
+		t.Cleanup(cache.Stop)
+		return mockDatastore, NewCachedTupleReader(mockDatastore, cache, time.Hour)
+	}
+
+	t.Run("caches_found_tuples", func(t *testing.T) {
+		mockDatastore, reader := newReader(t)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(expected, nil)
+
+		for range 2 {
+			got, err := reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+			require.NoError(t, err)
+			require.Empty(t, cmp.Diff(expected, got, protocmp.Transform()))
+		}
+	})
+
+	t.Run("caches_tuples_not_found", func(t *testing.T) {
+		mockDatastore, reader := newReader(t)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(nil, storage.ErrNotFound)
+
+		for range 2 {
+			_, err := reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+			require.ErrorIs(t, err, storage.ErrNotFound)
+		}
+	})
+
+	t.Run("does_not_cache_errors", func(t *testing.T) {
+		mockDatastore, reader := newReader(t)
+		gomock.InOrder(
+			mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(nil, context.DeadlineExceeded),
+			mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(expected, nil),
+		)
+
+		_, err := reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		got, err := reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Empty(t, cmp.Diff(expected, got, protocmp.Transform()))
+	})
+
+	t.Run("invalidated_entries_are_read_again", func(t *testing.T) {
+		mockDatastore, reader := newReader(t)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(2).Return(expected, nil)
+
+		_, err := reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		reader.cache.Set(
+			storage.GetInvalidIteratorByObjectRelationCacheKey(storeID, tk.GetObject(), tk.GetRelation()),
+			&storage.InvalidEntityCacheEntry{LastModified: time.Now().Add(time.Second)},
+			time.Hour,
+		)
+		_, err = reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("entries_of_an_invalidated_object_type_are_read_again", func(t *testing.T) {
+		mockDatastore, reader := newReader(t)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(2).Return(expected, nil)
+
+		_, err := reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		reader.cache.Set(
+			storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, "group"),
+			&storage.InvalidEntityCacheEntry{LastModified: time.Now().Add(time.Second)},
+			time.Hour,
+		)
+		_, err = reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		reader.cache.Set(
+			storage.GetInvalidIteratorByObjectTypeCacheKey(storeID, tuple.GetType(tk.GetObject())),
+			&storage.InvalidEntityCacheEntry{LastModified: time.Now().Add(time.Second)},
+			time.Hour,
+		)
+		_, err = reader.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("higher_consistency_skips_cache", func(t *testing.T) {
+		mockDatastore, reader := newReader(t)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(2).Return(expected, nil)
+
+		options := storage.ReadUserTupleOptions{
+			Consistency: storage.ConsistencyOptions{Preference: openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY},
+		}
+		for range 2 {
+			_, err := reader.ReadUserTuple(ctx, storeID, tk, options)
+			require.NoError(t, err)
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+var (
+	_ storage.RelationshipTupleReader = (*CircuitBreakerTupleReader)(nil)
+	_ storage.RelationshipTupleWriter = (*CircuitBreakerTupleWriter)(nil)
+
+	circuitBreakerStateGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_circuit_breaker_state",
+		Help:      "The state of the datastore circuit breaker: 0 if closed, 1 if half-open and 2 if open.",
+	})
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+// CircuitBreakerConfig defines when a [CircuitBreaker] trips and how long it stays open.
+type CircuitBreakerConfig struct {
+	// FailureRateThreshold is the rate of failed calls, between 0 and 1, at or above which the breaker trips.
+	FailureRateThreshold float64
+	// MinRequests is the number of calls within a window below which the breaker never trips.
+	MinRequests int
+	// Window is the period over which the failure rate is computed.
+	Window time.Duration
+	// OpenDuration is how long the breaker fails calls fast before letting a single call probe the datastore.
+	OpenDuration time.Duration
+}
+
+// CircuitBreaker stops calls to a degraded datastore from waiting for their full timeout. It counts the calls and
+// the failures of each window, and trips once the failure rate reaches the configured threshold. While open, calls
+// fail fast with [storage.ErrCircuitOpen]. Once the open duration elapsed the breaker is half-open: a single call
+// probes the datastore, closing the breaker if it succeeds and opening it again otherwise.
+//
+// Errors describing the request rather than the health of the datastore, such as [storage.ErrNotFound] or a
+// cancellation by the client, are not failures. Errors returned by the iterators of reads are not observed.
+//
+// A nil *CircuitBreaker is valid and never trips.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	mu          sync.Mutex
+	state       circuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+}
+
+// NewCircuitBreaker returns a closed [CircuitBreaker].
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	circuitBreakerStateGauge.Set(float64(circuitClosed))
+	return &CircuitBreaker{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// allow returns [storage.ErrCircuitOpen] if the call must fail fast. Otherwise the outcome of the call must be
+// reported to done, along with whether the call probes the datastore.
+func (b *CircuitBreaker) allow() (bool, error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.config.OpenDuration {
+			return false, storage.ErrCircuitOpen
+		}
+		b.setState(circuitHalfOpen)
+		b.probing = true
+		return true, nil
+	case circuitHalfOpen:
+		if b.probing {
+			return false, storage.ErrCircuitOpen
+		}
+		b.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// done records the outcome of a call allowed by allow, made with ctx.
+func (b *CircuitBreaker) done(ctx context.Context, probe bool, err error) {
+	if b == nil {
+		return
+	}
+
+	now := b.now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+		switch {
+		case isInconclusive(ctx, err):
+			// Let the next call probe again.
+		case isDatastoreFailure(err):
+			b.open(now)
+		default:
+			b.setState(circuitClosed)
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		return
+	}
+
+	if b.state != circuitClosed || isInconclusive(ctx, err) {
+		// The call was allowed before the breaker tripped, or tells nothing about the datastore.
+		return
+	}
+
+	if now.Sub(b.windowStart) >= b.config.Window {
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+	b.requests++
+	if isDatastoreFailure(err) {
+		b.failures++
+	}
+	if b.requests >= b.config.MinRequests && float64(b.failures)/float64(b.requests) >= b.config.FailureRateThreshold {
+		b.open(now)
+	}
+}
+
+func (b *CircuitBreaker) open(now time.Time) {
+	b.openedAt = now
+	b.setState(circuitOpen)
+}
+
+func (b *CircuitBreaker) setState(state circuitState) {
+	b.state = state
+	circuitBreakerStateGauge.Set(float64(state))
+}
+
+// isInconclusive reports whether the error of a call made with ctx tells nothing about the health of the datastore,
+// because the call was canceled or the request it was made for was done, e.g. it timed out, or its client went away.
+func isInconclusive(ctx context.Context, err error) bool {
+	return err != nil && (errors.Is(err, context.Canceled) || ctx.Err() != nil)
+}
+
+// isDatastoreFailure reports whether err is a sign that the datastore is degraded.
+func isDatastoreFailure(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, storage.ErrNotFound),
+		errors.Is(err, storage.ErrCollision),
+		errors.Is(err, storage.ErrInvalidWriteInput),
+		errors.Is(err, storage.ErrTransactionalWriteFailed),
+		errors.Is(err, storage.ErrInvalidContinuationToken),
+		errors.Is(err, storage.ErrInvalidStartTime):
+		return false
+	default:
+		return true
+	}
+}
+
+// guard calls fn, which runs with ctx, unless the breaker is open, and records its outcome.
+func guard[T any](ctx context.Context, b *CircuitBreaker, fn func() (T, error)) (T, error) {
+	probe, err := b.allow()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	res, err := fn()
+	b.done(ctx, probe, err)
+	return res, err
+}
+
+// CircuitBreakerTupleReader is a wrapper over a datastore failing its tuple reads fast while the breaker is open.
+type CircuitBreakerTupleReader struct {
+	storage.RelationshipTupleReader
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerTupleReader returns a wrapper over a datastore whose tuple reads go through the breaker, which
+// may be shared with other wrappers over the same datastore.
+func NewCircuitBreakerTupleReader(wrapped storage.RelationshipTupleReader, breaker *CircuitBreaker) *CircuitBreakerTupleReader {
+	return &CircuitBreakerTupleReader{
+		RelationshipTupleReader: wrapped,
+		breaker:                 breaker,
+	}
+}
+
+// Read see [storage.RelationshipTupleReader].Read.
+func (c *CircuitBreakerTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	return guard(ctx, c.breaker, func() (storage.TupleIterator, error) {
+		return c.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	})
+}
+
+// ReadPage see [storage.RelationshipTupleReader].ReadPage.
+func (c *CircuitBreakerTupleReader) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	var continuationToken string
+	tuples, err := guard(ctx, c.breaker, func() ([]*openfgav1.Tuple, error) {
+		tuples, token, err := c.RelationshipTupleReader.ReadPage(ctx, store, tupleKey, options)
+		continuationToken = token
+		return tuples, err
+	})
+	return tuples, continuationToken, err
+}
+
+// ReadUserTuple see [storage.RelationshipTupleReader].ReadUserTuple.
+func (c *CircuitBreakerTupleReader) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	return guard(ctx, c.breaker, func() (*openfgav1.Tuple, error) {
+		return c.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	})
+}
+
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (c *CircuitBreakerTupleReader) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	return guard(ctx, c.breaker, func() ([]*openfgav1.Tuple, error) {
+		return c.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	})
+}
+
+// ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
+func (c *CircuitBreakerTupleReader) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+	return guard(ctx, c.breaker, func() (storage.TupleIterator, error) {
+		return c.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	})
+}
+
+// ReadStartingWithUser see [storage.RelationshipTupleReader].ReadStartingWithUser.
+func (c *CircuitBreakerTupleReader) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	return guard(ctx, c.breaker, func() (storage.TupleIterator, error) {
+		return c.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	})
+}
+
+// CircuitBreakerTupleWriter is a wrapper over a datastore failing its tuple writes fast while the breaker is open.
+type CircuitBreakerTupleWriter struct {
+	storage.RelationshipTupleWriter
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerTupleWriter returns a wrapper over a datastore whose tuple writes go through the breaker, which
+// may be shared with other wrappers over the same datastore.
+func NewCircuitBreakerTupleWriter(wrapped storage.RelationshipTupleWriter, breaker *CircuitBreaker) *CircuitBreakerTupleWriter {
+	return &CircuitBreakerTupleWriter{
+		RelationshipTupleWriter: wrapped,
+		breaker:                 breaker,
+	}
+}
+
+// Write see [storage.RelationshipTupleWriter].Write.
+func (c *CircuitBreakerTupleWriter) Write(ctx context.Context, store string, d storage.Deletes, w storage.Writes) error {
+	_, err := guard(ctx, c.breaker, func() (struct{}, error) {
+		return struct{}{}, c.RelationshipTupleWriter.Write(ctx, store, d, w)
+	})
+	return err
+}
+
+// NewCircuitBreakerDatastore returns a wrapper over a datastore whose tuple reads and writes go through the breaker.
+func NewCircuitBreakerDatastore(inner storage.OpenFGADatastore, breaker *CircuitBreaker) storage.OpenFGADatastore {
+	return newWrappedDatastore(inner, NewCircuitBreakerTupleReader(inner, breaker), NewCircuitBreakerTupleWriter(inner, breaker))
+}
@@ -0,0 +1,186 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errDatastore := errors.New("connection refused")
+
+	newBreaker := func() (*CircuitBreaker, *time.Time) {
+		now := time.Now()
+		b := NewCircuitBreaker(CircuitBreakerConfig{
+			FailureRateThreshold: 0.5,
+			MinRequests:          4,
+			Window:               10 * time.Second,
+			OpenDuration:         30 * time.Second,
+		})
+		b.now = func() time.Time { return now }
+		return b, &now
+	}
+
+	callWithContext := func(ctx context.Context, b *CircuitBreaker, err error) error {
+		_, callErr := guard(ctx, b, func() (struct{}, error) {
+			return struct{}{}, err
+		})
+		return callErr
+	}
+
+	call := func(b *CircuitBreaker, err error) error {
+		return callWithContext(context.Background(), b, err)
+	}
+
+	t.Run("nil_breaker_never_trips", func(t *testing.T) {
+		var b *CircuitBreaker
+		for range 10 {
+			require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		}
+	})
+
+	t.Run("trips_once_the_failure_rate_is_reached", func(t *testing.T) {
+		b, _ := newBreaker()
+
+		require.NoError(t, call(b, nil))
+		require.NoError(t, call(b, nil))
+		require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		require.Equal(t, circuitClosed, b.state)
+
+		require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		require.Equal(t, circuitOpen, b.state)
+		require.ErrorIs(t, call(b, nil), storage.ErrCircuitOpen)
+	})
+
+	t.Run("does_not_trip_below_min_requests", func(t *testing.T) {
+		b, _ := newBreaker()
+
+		for range 3 {
+			require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		}
+		require.Equal(t, circuitClosed, b.state)
+	})
+
+	t.Run("request_errors_are_not_failures", func(t *testing.T) {
+		b, _ := newBreaker()
+
+		for _, err := range []error{storage.ErrNotFound, storage.ErrInvalidWriteInput, context.Canceled, storage.ErrTransactionalWriteFailed} {
+			require.ErrorIs(t, call(b, err), err)
+		}
+		require.Equal(t, circuitClosed, b.state)
+	})
+
+	t.Run("errors_of_done_requests_are_not_failures", func(t *testing.T) {
+		b, _ := newBreaker()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		for range 4 {
+			require.ErrorIs(t, callWithContext(ctx, b, errDatastore), errDatastore)
+		}
+		require.Equal(t, circuitClosed, b.state)
+		require.Zero(t, b.requests)
+	})
+
+	t.Run("failures_of_a_past_window_are_forgotten", func(t *testing.T) {
+		b, now := newBreaker()
+
+		for range 3 {
+			require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		}
+		*now = now.Add(10 * time.Second)
+		require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		require.Equal(t, circuitClosed, b.state)
+	})
+
+	t.Run("half_opens_to_probe_recovery", func(t *testing.T) {
+		b, now := newBreaker()
+		for range 4 {
+			require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		}
+		require.Equal(t, circuitOpen, b.state)
+
+		*now = now.Add(30 * time.Second)
+		probe, err := b.allow()
+		require.NoError(t, err)
+		require.True(t, probe)
+		require.Equal(t, circuitHalfOpen, b.state)
+
+		// A single call probes the datastore at a time.
+		require.ErrorIs(t, call(b, nil), storage.ErrCircuitOpen)
+
+		b.done(context.Background(), probe, nil)
+		require.Equal(t, circuitClosed, b.state)
+		require.NoError(t, call(b, nil))
+	})
+
+	t.Run("probe_of_a_done_request_is_inconclusive", func(t *testing.T) {
+		b, now := newBreaker()
+		for range 4 {
+			require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		}
+
+		*now = now.Add(30 * time.Second)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, callWithContext(ctx, b, errDatastore), errDatastore)
+		require.Equal(t, circuitHalfOpen, b.state)
+
+		require.NoError(t, call(b, nil))
+		require.Equal(t, circuitClosed, b.state)
+	})
+
+	t.Run("failed_probe_opens_again", func(t *testing.T) {
+		b, now := newBreaker()
+		for range 4 {
+			require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		}
+
+		*now = now.Add(30 * time.Second)
+		require.ErrorIs(t, call(b, errDatastore), errDatastore)
+		require.Equal(t, circuitOpen, b.state)
+		require.ErrorIs(t, call(b, nil), storage.ErrCircuitOpen)
+	})
+}
+
+func TestCircuitBreakerDatastore(t *testing.T) {
+	_, mockDatastore := newMockDatastore(t)
+
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+	errDatastore := errors.New("connection refused")
+
+	ds := NewCircuitBreakerDatastore(mockDatastore, NewCircuitBreaker(CircuitBreakerConfig{
+		FailureRateThreshold: 1,
+		MinRequests:          2,
+		Window:               time.Minute,
+		OpenDuration:         time.Minute,
+	}))
+
+	mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(nil, errDatastore)
+	mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).Return(errDatastore)
+
+	_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+	require.ErrorIs(t, err, errDatastore)
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk})
+	require.ErrorIs(t, err, errDatastore)
+
+	// The breaker is shared by reads and writes, and fails both fast once open.
+	_, err = ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+	require.ErrorIs(t, err, storage.ErrCircuitOpen)
+	_, err = ds.Read(ctx, storeID, tk, storage.ReadOptions{})
+	require.ErrorIs(t, err, storage.ErrCircuitOpen)
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk})
+	require.ErrorIs(t, err, storage.ErrCircuitOpen)
+}
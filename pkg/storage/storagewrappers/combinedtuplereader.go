@@ -104,6 +104,37 @@ func (c *CombinedTupleReader) ReadUserTuple(
 	return c.RelationshipTupleReader.ReadUserTuple(ctx, store, tk, options)
 }
 
+// ReadUserTuples see [storage.RelationshipTupleReader.ReadUserTuples].
+func (c *CombinedTupleReader) ReadUserTuples(
+	ctx context.Context,
+	store string,
+	tupleKeys []*openfgav1.TupleKey,
+	options storage.ReadUserTupleOptions,
+) ([]*openfgav1.Tuple, error) {
+	var tuples []*openfgav1.Tuple
+	remaining := make([]*openfgav1.TupleKey, 0, len(tupleKeys))
+
+	for _, tk := range tupleKeys {
+		filteredContextualTuples := filterTuples(c.contextualTuplesOrderedByObjectID, tk.GetObject(), tk.GetRelation(), []string{tk.GetUser()})
+		if len(filteredContextualTuples) > 0 {
+			tuples = append(tuples, filteredContextualTuples[0])
+			continue
+		}
+		remaining = append(remaining, tk)
+	}
+
+	if len(remaining) == 0 {
+		return tuples, nil
+	}
+
+	persisted, err := c.RelationshipTupleReader.ReadUserTuples(ctx, store, remaining, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tuples, persisted...), nil
+}
+
 // ReadUsersetTuples see [storage.RelationshipTupleReader.ReadUsersetTuples].
 func (c *CombinedTupleReader) ReadUsersetTuples(
 	ctx context.Context,
@@ -59,6 +59,13 @@ func (c *ContextTracerWrapper) ReadUserTuple(ctx context.Context, store string,
 	return c.OpenFGADatastore.ReadUserTuple(queryCtx, store, tupleKey, options)
 }
 
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (c *ContextTracerWrapper) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	queryCtx := queryContext(ctx)
+
+	return c.OpenFGADatastore.ReadUserTuples(queryCtx, store, tupleKeys, options)
+}
+
 // ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
 func (c *ContextTracerWrapper) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
 	queryCtx := queryContext(ctx)
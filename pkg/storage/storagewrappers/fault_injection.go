@@ -0,0 +1,177 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+)
+
+var (
+	_ storage.OpenFGADatastore = (*faultInjectingDatastore)(nil)
+	_ storage.TupleIterator    = (*slowTupleIterator)(nil)
+
+	// ErrInjectedFault is the error of the datastore calls failed by fault injection. It is returned as a
+	// [storage.TransientConnection] error, so that retries and circuit breaking are exercised as they would be by an
+	// unreachable datastore.
+	ErrInjectedFault = errors.New("injected datastore fault")
+
+	injectedFaultCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_injected_fault_count",
+		Help:      "The total number of faults injected into datastore calls, partitioned by operation and kind of fault.",
+	}, []string{"operation", "fault"})
+)
+
+// FaultInjectionOperations are the datastore operations faults can be injected into.
+var FaultInjectionOperations = []string{
+	storagewrappersutil.OperationRead,
+	storagewrappersutil.OperationReadPage,
+	storagewrappersutil.OperationReadUserTuple,
+	storagewrappersutil.OperationReadUserTuples,
+	storagewrappersutil.OperationReadUsersetTuples,
+	storagewrappersutil.OperationReadStartingWithUser,
+	storagewrappersutil.OperationWrite,
+}
+
+// FaultPolicy defines the faults injected into the calls of a datastore operation.
+type FaultPolicy struct {
+	// Latency is added to every call, before it is made.
+	Latency time.Duration
+	// ErrorRate is the fraction, between 0 and 1, of calls failing with ErrInjectedFault instead of being made.
+	ErrorRate float64
+	// IteratorLatency is added to every item read from the iterators returned by the operation.
+	IteratorLatency time.Duration
+}
+
+type faultInjector struct {
+	policies map[string]FaultPolicy
+	// random returns a number in [0, 1). It is injectable for tests.
+	random func() float64
+}
+
+// inject applies the policy of op, and returns the error the call must fail with, if any.
+func (f *faultInjector) inject(ctx context.Context, op string) error {
+	policy := f.policies[op]
+	if policy.Latency > 0 {
+		injectedFaultCounter.WithLabelValues(op, "latency").Inc()
+		if err := sleepContext(ctx, policy.Latency); err != nil {
+			return err
+		}
+	}
+	if policy.ErrorRate > 0 && f.random() < policy.ErrorRate {
+		injectedFaultCounter.WithLabelValues(op, "error").Inc()
+		return &storage.TransientError{Class: storage.TransientConnection, Err: ErrInjectedFault}
+	}
+	return nil
+}
+
+func (f *faultInjector) iterator(op string, iter storage.TupleIterator, err error) (storage.TupleIterator, error) {
+	if err != nil || f.policies[op].IteratorLatency <= 0 {
+		return iter, err
+	}
+	return &slowTupleIterator{TupleIterator: iter, op: op, latency: f.policies[op].IteratorLatency}, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// slowTupleIterator adds latency to every item read from the iterator it wraps.
+type slowTupleIterator struct {
+	storage.TupleIterator
+	op      string
+	latency time.Duration
+}
+
+// Next see [storage.Iterator].Next.
+func (s *slowTupleIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
+	injectedFaultCounter.WithLabelValues(s.op, "iterator_latency").Inc()
+	if err := sleepContext(ctx, s.latency); err != nil {
+		return nil, err
+	}
+	return s.TupleIterator.Next(ctx)
+}
+
+type faultInjectingDatastore struct {
+	storage.OpenFGADatastore
+	injector *faultInjector
+}
+
+// NewFaultInjectingDatastore returns a wrapper over a datastore injecting latency and errors into its tuple reads and
+// writes, following the policy of each operation of [FaultInjectionOperations]. It is meant for resilience testing
+// only, and must never wrap a production datastore.
+func NewFaultInjectingDatastore(inner storage.OpenFGADatastore, policies map[string]FaultPolicy) storage.OpenFGADatastore {
+	return &faultInjectingDatastore{
+		OpenFGADatastore: inner,
+		injector:         &faultInjector{policies: policies, random: rand.Float64},
+	}
+}
+
+func (f *faultInjectingDatastore) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	if err := f.injector.inject(ctx, storagewrappersutil.OperationRead); err != nil {
+		return nil, err
+	}
+	iter, err := f.OpenFGADatastore.Read(ctx, store, tupleKey, options)
+	return f.injector.iterator(storagewrappersutil.OperationRead, iter, err)
+}
+
+func (f *faultInjectingDatastore) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	if err := f.injector.inject(ctx, storagewrappersutil.OperationReadPage); err != nil {
+		return nil, "", err
+	}
+	return f.OpenFGADatastore.ReadPage(ctx, store, tupleKey, options)
+}
+
+func (f *faultInjectingDatastore) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	if err := f.injector.inject(ctx, storagewrappersutil.OperationReadUserTuple); err != nil {
+		return nil, err
+	}
+	return f.OpenFGADatastore.ReadUserTuple(ctx, store, tupleKey, options)
+}
+
+func (f *faultInjectingDatastore) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	if err := f.injector.inject(ctx, storagewrappersutil.OperationReadUserTuples); err != nil {
+		return nil, err
+	}
+	return f.OpenFGADatastore.ReadUserTuples(ctx, store, tupleKeys, options)
+}
+
+func (f *faultInjectingDatastore) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+	if err := f.injector.inject(ctx, storagewrappersutil.OperationReadUsersetTuples); err != nil {
+		return nil, err
+	}
+	iter, err := f.OpenFGADatastore.ReadUsersetTuples(ctx, store, filter, options)
+	return f.injector.iterator(storagewrappersutil.OperationReadUsersetTuples, iter, err)
+}
+
+func (f *faultInjectingDatastore) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	if err := f.injector.inject(ctx, storagewrappersutil.OperationReadStartingWithUser); err != nil {
+		return nil, err
+	}
+	iter, err := f.OpenFGADatastore.ReadStartingWithUser(ctx, store, filter, options)
+	return f.injector.iterator(storagewrappersutil.OperationReadStartingWithUser, iter, err)
+}
+
+func (f *faultInjectingDatastore) Write(ctx context.Context, store string, d storage.Deletes, w storage.Writes) error {
+	if err := f.injector.inject(ctx, storagewrappersutil.OperationWrite); err != nil {
+		return err
+	}
+	return f.OpenFGADatastore.Write(ctx, store, d, w)
+}
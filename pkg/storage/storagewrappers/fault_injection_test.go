@@ -0,0 +1,97 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestFaultInjectingDatastore(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+	expected := &openfgav1.Tuple{Key: tk}
+
+	newDatastore := func(t *testing.T, policies map[string]FaultPolicy, random float64) (*mocks.MockOpenFGADatastore, storage.OpenFGADatastore) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewFaultInjectingDatastore(mockDatastore, policies).(*faultInjectingDatastore)
+		ds.injector.random = func() float64 { return random }
+		return mockDatastore, ds
+	}
+
+	t.Run("injects_errors", func(t *testing.T) {
+		mockDatastore, ds := newDatastore(t, map[string]FaultPolicy{
+			storagewrappersutil.OperationReadUserTuple: {ErrorRate: 0.5},
+		}, 0.4)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, ErrInjectedFault)
+		class, ok := storage.TransientErrorClassOf(err)
+		require.True(t, ok)
+		require.Equal(t, storage.TransientConnection, class)
+	})
+
+	t.Run("lets_calls_above_the_error_rate_through", func(t *testing.T) {
+		mockDatastore, ds := newDatastore(t, map[string]FaultPolicy{
+			storagewrappersutil.OperationReadUserTuple: {ErrorRate: 0.5},
+		}, 0.6)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(expected, nil)
+
+		got, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+	})
+
+	t.Run("only_injects_into_configured_operations", func(t *testing.T) {
+		mockDatastore, ds := newDatastore(t, map[string]FaultPolicy{
+			storagewrappersutil.OperationWrite: {ErrorRate: 1},
+		}, 0)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(expected, nil)
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.ErrorIs(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk}), ErrInjectedFault)
+	})
+
+	t.Run("latency_is_bounded_by_the_context", func(t *testing.T) {
+		mockDatastore, ds := newDatastore(t, map[string]FaultPolicy{
+			storagewrappersutil.OperationReadUserTuple: {Latency: time.Hour},
+		}, 0)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("slows_iterators_down", func(t *testing.T) {
+		mockDatastore, ds := newDatastore(t, map[string]FaultPolicy{
+			storagewrappersutil.OperationReadUsersetTuples: {IteratorLatency: 20 * time.Millisecond},
+		}, 0)
+		mockDatastore.EXPECT().ReadUsersetTuples(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).
+			Return(storage.NewStaticTupleIterator([]*openfgav1.Tuple{expected}), nil)
+
+		iter, err := ds.ReadUsersetTuples(ctx, storeID, storage.ReadUsersetTuplesFilter{Object: "document:1", Relation: "viewer"}, storage.ReadUsersetTuplesOptions{})
+		require.NoError(t, err)
+		defer iter.Stop()
+
+		start := time.Now()
+		got, err := iter.Next(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+		require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+}
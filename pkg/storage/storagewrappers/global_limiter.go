@@ -0,0 +1,132 @@
+package storagewrappers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+var (
+	_ storage.RelationshipTupleReader = (*GlobalLimitedTupleReader)(nil)
+
+	globalReadWaitMsHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:                       build.ProjectName,
+		Name:                            "datastore_global_read_limiter_wait_ms",
+		Help:                            "Time datastore reads spent waiting for a slot of the server-wide read concurrency limiter, partitioned by operation.",
+		Buckets:                         []float64{1, 3, 5, 10, 25, 50, 100, 1000, 5000}, // Milliseconds. Upper bound is config.UpstreamTimeout.
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: time.Hour,
+	}, []string{"operation"})
+
+	globalReadSaturationGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_global_read_limiter_saturation",
+		Help:      "The fraction, between 0 and 1, of the slots of the server-wide datastore read concurrency limiter in use.",
+	})
+)
+
+// GlobalReadLimiter bounds the number of concurrent datastore reads of the whole server, whatever the number of
+// requests, under the per-request limit of [BoundedTupleReader]. Reads wait for a slot in the order they asked for it.
+type GlobalReadLimiter struct {
+	slots chan struct{}
+}
+
+// NewGlobalReadLimiter returns a [GlobalReadLimiter] of maxConcurrentReads slots.
+func NewGlobalReadLimiter(maxConcurrentReads uint32) *GlobalReadLimiter {
+	return &GlobalReadLimiter{slots: make(chan struct{}, maxConcurrentReads)}
+}
+
+// acquire waits until a slot is available or ctx is done, and records the time waited.
+func (l *GlobalReadLimiter) acquire(ctx context.Context, op string) error {
+	start := time.Now()
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	telemetry.ObserveWithTraceExemplar(ctx, globalReadWaitMsHistogram.WithLabelValues(op), float64(time.Since(start).Milliseconds()))
+	l.recordSaturation()
+	return nil
+}
+
+func (l *GlobalReadLimiter) release() {
+	<-l.slots
+	l.recordSaturation()
+}
+
+func (l *GlobalReadLimiter) recordSaturation() {
+	globalReadSaturationGauge.Set(float64(len(l.slots)) / float64(cap(l.slots)))
+}
+
+// GlobalLimitedTupleReader is a wrapper over a tuple reader bounding the concurrency of its reads with a
+// [GlobalReadLimiter]. The reads returning iterators hold their slot until the iterator is returned.
+type GlobalLimitedTupleReader struct {
+	storage.RelationshipTupleReader
+	limiter *GlobalReadLimiter
+}
+
+// NewGlobalLimitedTupleReaderMiddleware returns a [TupleReaderMiddleware] bounding the concurrency of the reads
+// with limiter.
+func NewGlobalLimitedTupleReaderMiddleware(limiter *GlobalReadLimiter) TupleReaderMiddleware {
+	return func(inner storage.RelationshipTupleReader) storage.RelationshipTupleReader {
+		return &GlobalLimitedTupleReader{RelationshipTupleReader: inner, limiter: limiter}
+	}
+}
+
+// withGlobalLimit calls fn once the limiter granted it a slot.
+func withGlobalLimit[T any](ctx context.Context, l *GlobalReadLimiter, op string, fn func() (T, error)) (T, error) {
+	if err := l.acquire(ctx, op); err != nil {
+		var zero T
+		return zero, err
+	}
+	defer l.release()
+	return fn()
+}
+
+func (g *GlobalLimitedTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	return withGlobalLimit(ctx, g.limiter, storagewrappersutil.OperationRead, func() (storage.TupleIterator, error) {
+		return g.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	})
+}
+
+func (g *GlobalLimitedTupleReader) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	if err := g.limiter.acquire(ctx, storagewrappersutil.OperationReadPage); err != nil {
+		return nil, "", err
+	}
+	defer g.limiter.release()
+	return g.RelationshipTupleReader.ReadPage(ctx, store, tupleKey, options)
+}
+
+func (g *GlobalLimitedTupleReader) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	return withGlobalLimit(ctx, g.limiter, storagewrappersutil.OperationReadUserTuple, func() (*openfgav1.Tuple, error) {
+		return g.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	})
+}
+
+func (g *GlobalLimitedTupleReader) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	return withGlobalLimit(ctx, g.limiter, storagewrappersutil.OperationReadUserTuples, func() ([]*openfgav1.Tuple, error) {
+		return g.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	})
+}
+
+func (g *GlobalLimitedTupleReader) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+	return withGlobalLimit(ctx, g.limiter, storagewrappersutil.OperationReadUsersetTuples, func() (storage.TupleIterator, error) {
+		return g.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	})
+}
+
+func (g *GlobalLimitedTupleReader) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	return withGlobalLimit(ctx, g.limiter, storagewrappersutil.OperationReadStartingWithUser, func() (storage.TupleIterator, error) {
+		return g.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	})
+}
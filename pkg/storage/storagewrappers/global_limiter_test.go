@@ -0,0 +1,49 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestGlobalReadLimiter(t *testing.T) {
+	t.Run("bounds_the_concurrent_reads", func(t *testing.T) {
+		l := NewGlobalReadLimiter(2)
+		require.NoError(t, l.acquire(context.Background(), storagewrappersutil.OperationRead))
+		require.InDelta(t, 0.5, testutil.ToFloat64(globalReadSaturationGauge), 0)
+		require.NoError(t, l.acquire(context.Background(), storagewrappersutil.OperationRead))
+		require.InDelta(t, 1, testutil.ToFloat64(globalReadSaturationGauge), 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, l.acquire(ctx, storagewrappersutil.OperationRead), context.DeadlineExceeded)
+
+		l.release()
+		require.InDelta(t, 0.5, testutil.ToFloat64(globalReadSaturationGauge), 0)
+		require.NoError(t, l.acquire(context.Background(), storagewrappersutil.OperationRead))
+		l.release()
+		l.release()
+		require.InDelta(t, 0, testutil.ToFloat64(globalReadSaturationGauge), 0)
+	})
+
+	t.Run("reads_through_the_middleware", func(t *testing.T) {
+		ds := memory.New()
+		t.Cleanup(ds.Close)
+		l := NewGlobalReadLimiter(1)
+		reader := NewGlobalLimitedTupleReaderMiddleware(l)(ds)
+
+		_, err := reader.ReadUserTuple(context.Background(), "store", tuple.NewTupleKey("document:1", "viewer", "user:anne"), storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// the slot was released
+		require.Empty(t, l.slots)
+	})
+}
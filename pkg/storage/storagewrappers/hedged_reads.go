@@ -0,0 +1,171 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+var (
+	hedgedReadCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_hedged_read_count",
+		Help:      "The total number of datastore reads hedged by an identical read after taking longer than the hedge delay.",
+	}, []string{"operation", "method"})
+
+	hedgedReadWonCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_hedged_read_won_count",
+		Help:      "The total number of hedged datastore reads whose hedge returned before the original read.",
+	}, []string{"operation", "method"})
+)
+
+// hedge calls read, and calls it again if it takes longer than the hedge delay of b and the limiter of b has room
+// for another read. It returns the result of the call returning first, and cancels the other.
+func hedge[T any](ctx context.Context, b *BoundedTupleReader, op string, read func(context.Context) (T, error)) (T, error) {
+	if b.hedgeDelay <= 0 {
+		return read(ctx)
+	}
+
+	type result struct {
+		value  T
+		err    error
+		hedged bool
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so that the call returning last does not block once its result is discarded.
+	results := make(chan result, 2)
+	go func() {
+		value, err := read(ctx)
+		results <- result{value: value, err: err}
+	}()
+
+	timer := time.NewTimer(b.hedgeDelay)
+	defer timer.Stop()
+
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-timer.C:
+	}
+
+	if b.tryBound() {
+		hedgedReadCounter.WithLabelValues(op, b.method).Inc()
+		go func() {
+			defer b.done()
+			value, err := read(ctx)
+			results <- result{value: value, err: err, hedged: true}
+		}()
+	}
+
+	r := <-results
+	if r.hedged {
+		hedgedReadWonCounter.WithLabelValues(op, b.method).Inc()
+	}
+	return r.value, r.err
+}
+
+// hedgeIterator hedges a read returning an iterator like hedge does, the read including the query of the first
+// tuples of its iterator, as the datastores query lazily. The iterator of the read returning first is used, and the
+// other read is cancelled and its iterator stopped. The read used is cancelled once its iterator is stopped, so that
+// it keeps querying the datastore until then.
+func hedgeIterator(ctx context.Context, b *BoundedTupleReader, op string, read func(context.Context) (storage.TupleIterator, error)) (storage.TupleIterator, error) {
+	if b.hedgeDelay <= 0 {
+		return read(ctx)
+	}
+
+	type result struct {
+		iter   storage.TupleIterator
+		err    error
+		hedged bool
+	}
+
+	// readFirst reads and queries the first tuples of the iterator.
+	readFirst := func(ctx context.Context, hedged bool) result {
+		iter, err := read(ctx)
+		if err != nil {
+			return result{err: err, hedged: hedged}
+		}
+		if _, err := iter.Head(ctx); err != nil && !errors.Is(err, storage.ErrIteratorDone) {
+			iter.Stop()
+			return result{err: err, hedged: hedged}
+		}
+		return result{iter: iter, hedged: hedged}
+	}
+
+	readCtx, cancelRead := context.WithCancel(ctx)
+	hedgeCtx, cancelHedge := context.WithCancel(ctx)
+
+	// use returns the iterator of r, whose read is cancelled once it is stopped.
+	use := func(r result, cancel context.CancelFunc) (storage.TupleIterator, error) {
+		if r.err != nil {
+			cancel()
+			return nil, r.err
+		}
+		return &cancelOnStopTupleIterator{TupleIterator: r.iter, cancel: cancel}, nil
+	}
+
+	// Buffered so that the read returning last does not block once its result is discarded.
+	results := make(chan result, 2)
+	go func() {
+		results <- readFirst(readCtx, false)
+	}()
+
+	timer := time.NewTimer(b.hedgeDelay)
+	defer timer.Stop()
+
+	select {
+	case r := <-results:
+		cancelHedge()
+		return use(r, cancelRead)
+	case <-timer.C:
+	}
+
+	if !b.tryBound() {
+		cancelHedge()
+		return use(<-results, cancelRead)
+	}
+
+	hedgedReadCounter.WithLabelValues(op, b.method).Inc()
+	go func() {
+		defer b.done()
+		results <- readFirst(hedgeCtx, true)
+	}()
+
+	r := <-results
+	cancel, cancelOther := cancelRead, cancelHedge
+	if r.hedged {
+		hedgedReadWonCounter.WithLabelValues(op, b.method).Inc()
+		cancel, cancelOther = cancelHedge, cancelRead
+	}
+
+	cancelOther()
+	go func() {
+		if other := <-results; other.iter != nil {
+			other.iter.Stop()
+		}
+	}()
+
+	return use(r, cancel)
+}
+
+// cancelOnStopTupleIterator is a [storage.TupleIterator] cancelling the context of its read once it is stopped.
+type cancelOnStopTupleIterator struct {
+	storage.TupleIterator
+	cancel context.CancelFunc
+}
+
+// Stop see [storage.Iterator].Stop.
+func (c *cancelOnStopTupleIterator) Stop() {
+	c.TupleIterator.Stop()
+	c.cancel()
+}
@@ -0,0 +1,129 @@
+package storagewrappers
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestHedgedReads(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+	store := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+	expected := &openfgav1.Tuple{Key: tk}
+
+	// slowFirstRead blocks the first read until it is cancelled, and returns the tuple right away on the others.
+	slowFirstRead := func(calls *atomic.Int32) func(context.Context, string, *openfgav1.TupleKey, storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+		return func(ctx context.Context, _ string, _ *openfgav1.TupleKey, _ storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return expected, nil
+		}
+	}
+
+	t.Run("hedge_returns_first", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+
+		var calls atomic.Int32
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), store, tk, gomock.Any()).Times(2).DoAndReturn(slowFirstRead(&calls))
+
+		reader := NewBoundedTupleReader(mockDatastore, &Operation{Method: apimethod.Check, Concurrency: 2, HedgeDelay: 10 * time.Millisecond})
+		got, err := reader.ReadUserTuple(ctx, store, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+		require.Equal(t, uint32(2), reader.GetMetadata().DatastoreQueryCount)
+	})
+
+	t.Run("fast_read_is_not_hedged", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), store, tk, gomock.Any()).Times(1).Return(expected, nil)
+
+		reader := NewBoundedTupleReader(mockDatastore, &Operation{Method: apimethod.Check, Concurrency: 2, HedgeDelay: time.Second})
+		got, err := reader.ReadUserTuple(ctx, store, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+	})
+
+	t.Run("hedge_is_bounded_by_the_limiter", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), store, tk, gomock.Any()).Times(1).
+			DoAndReturn(func(context.Context, string, *openfgav1.TupleKey, storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+				time.Sleep(50 * time.Millisecond)
+				return expected, nil
+			})
+
+		reader := NewBoundedTupleReader(mockDatastore, &Operation{Method: apimethod.Check, Concurrency: 1, HedgeDelay: 10 * time.Millisecond})
+		got, err := reader.ReadUserTuple(ctx, store, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+		require.Equal(t, uint32(1), reader.GetMetadata().DatastoreQueryCount)
+	})
+
+	t.Run("iterator_read_is_hedged_until_its_first_tuples", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+
+		slowIter := &slowHeadTupleIterator{TupleIterator: storage.NewStaticTupleIterator(nil)}
+		var calls atomic.Int32
+		mockDatastore.EXPECT().Read(gomock.Any(), store, tk, gomock.Any()).Times(2).
+			DoAndReturn(func(context.Context, string, *openfgav1.TupleKey, storage.ReadOptions) (storage.TupleIterator, error) {
+				if calls.Add(1) == 1 {
+					return slowIter, nil
+				}
+				return storage.NewStaticTupleIterator([]*openfgav1.Tuple{expected}), nil
+			})
+
+		reader := NewBoundedTupleReader(mockDatastore, &Operation{Method: apimethod.Check, Concurrency: 2, HedgeDelay: 10 * time.Millisecond})
+		iter, err := reader.Read(ctx, store, tk, storage.ReadOptions{})
+		require.NoError(t, err)
+		got, err := iter.Next(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+		iter.Stop()
+		require.Equal(t, uint32(2), reader.GetMetadata().DatastoreQueryCount)
+		require.Eventually(t, slowIter.stopped.Load, time.Second, time.Millisecond)
+	})
+}
+
+// slowHeadTupleIterator is a [storage.TupleIterator] whose Head blocks until its context is cancelled.
+type slowHeadTupleIterator struct {
+	storage.TupleIterator
+	stopped atomic.Bool
+}
+
+func (s *slowHeadTupleIterator) Head(ctx context.Context) (*openfgav1.Tuple, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *slowHeadTupleIterator) Stop() {
+	s.stopped.Store(true)
+	s.TupleIterator.Stop()
+}
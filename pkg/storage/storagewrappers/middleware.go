@@ -0,0 +1,41 @@
+package storagewrappers
+
+import (
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+var _ storage.OpenFGADatastore = (*wrappedDatastore)(nil)
+
+// TupleReaderMiddleware wraps a tuple reader with custom behavior, e.g. caching, tracing or tenant fencing.
+type TupleReaderMiddleware func(storage.RelationshipTupleReader) storage.RelationshipTupleReader
+
+// NewMiddlewareDatastore returns a wrapper over a datastore reading tuples through middlewares. The first middleware
+// is the outermost one, i.e. it is called first and returns last.
+func NewMiddlewareDatastore(inner storage.OpenFGADatastore, middlewares ...TupleReaderMiddleware) storage.OpenFGADatastore {
+	var reader storage.RelationshipTupleReader = inner
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		reader = middlewares[i](reader)
+	}
+	return newWrappedDatastore(inner, reader, inner)
+}
+
+// wrappedDatastore is a datastore reading and writing tuples through wrappers of its reader and writer, e.g. to retry
+// them, its other methods being those of the datastore.
+type wrappedDatastore struct {
+	storage.RelationshipTupleReader
+	storage.RelationshipTupleWriter
+	// the datastore is embedded one level deeper, so that its tuple reads and writes are those of the wrappers
+	datastore
+}
+
+type datastore struct {
+	storage.OpenFGADatastore
+}
+
+func newWrappedDatastore(inner storage.OpenFGADatastore, reader storage.RelationshipTupleReader, writer storage.RelationshipTupleWriter) storage.OpenFGADatastore {
+	return &wrappedDatastore{
+		RelationshipTupleReader: reader,
+		RelationshipTupleWriter: writer,
+		datastore:               datastore{OpenFGADatastore: inner},
+	}
+}
@@ -0,0 +1,72 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// recordingReader records its name in calls when reading a tuple.
+type recordingReader struct {
+	storage.RelationshipTupleReader
+	name  string
+	calls *[]string
+}
+
+func (r *recordingReader) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	*r.calls = append(*r.calls, r.name)
+	return r.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+}
+
+// newMockDatastore returns a mock datastore for the wrappers to wrap, and its controller.
+func newMockDatastore(t *testing.T) (*gomock.Controller, *mocks.MockOpenFGADatastore) {
+	mockController := gomock.NewController(t)
+	t.Cleanup(mockController.Finish)
+	return mockController, mocks.NewMockOpenFGADatastore(mockController)
+}
+
+func TestMiddlewareDatastore(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+
+	_, mockDatastore := newMockDatastore(t)
+
+	var calls []string
+	recording := func(name string) TupleReaderMiddleware {
+		return func(inner storage.RelationshipTupleReader) storage.RelationshipTupleReader {
+			return &recordingReader{RelationshipTupleReader: inner, name: name, calls: &calls}
+		}
+	}
+	ds := NewMiddlewareDatastore(mockDatastore, recording("outer"), recording("inner"))
+
+	t.Run("reads_go_through_middlewares_in_order", func(t *testing.T) {
+		expected := &openfgav1.Tuple{Key: tk}
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(expected, nil)
+
+		got, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+		require.Equal(t, []string{"outer", "inner"}, calls)
+	})
+
+	t.Run("other_calls_bypass_middlewares", func(t *testing.T) {
+		mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mockDatastore.EXPECT().MaxTuplesPerWrite().Times(1).Return(100)
+		mockDatastore.EXPECT().Close().Times(1)
+
+		require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk}))
+		require.Equal(t, 100, ds.MaxTuplesPerWrite())
+		ds.Close()
+		require.Equal(t, []string{"outer", "inner"}, calls)
+	})
+}
@@ -77,6 +77,17 @@ func (c *cachedOpenFGADatastore) FindLatestAuthorizationModel(ctx context.Contex
 	return v.(*openfgav1.AuthorizationModel), nil
 }
 
+// DeleteAuthorizationModel deletes the model and evicts it from the cache.
+func (c *cachedOpenFGADatastore) DeleteAuthorizationModel(ctx context.Context, storeID, modelID string) error {
+	err := c.OpenFGADatastore.DeleteAuthorizationModel(ctx, storeID, modelID)
+	if err != nil {
+		return err
+	}
+
+	c.cache.Delete(fmt.Sprintf("%s:%s", storeID, modelID))
+	return nil
+}
+
 // Close closes the datastore and cleans up any residual resources.
 func (c *cachedOpenFGADatastore) Close() {
 	c.cache.Stop()
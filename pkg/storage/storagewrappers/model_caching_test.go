@@ -15,6 +15,7 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
@@ -126,3 +127,39 @@ func TestSingleFlightFindLatestAuthorizationModel(t *testing.T) {
 	err = wg.Wait()
 	require.NoError(t, err)
 }
+
+func TestDeleteAuthorizationModelEvictsCachedModel(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	mockController := gomock.NewController(t)
+	mockController.Finish()
+
+	mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+	cachingBackend, err := NewCachedOpenFGADatastore(mockDatastore, 5)
+	require.NoError(t, err)
+	t.Cleanup(cachingBackend.Close)
+
+	model := &openfgav1.AuthorizationModel{
+		Id:            ulid.Make().String(),
+		SchemaVersion: typesystem.SchemaVersion1_1,
+	}
+	storeID := ulid.Make().String()
+	gomock.InOrder(
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, model.GetId()).Times(1).Return(model, nil),
+		mockDatastore.EXPECT().DeleteAuthorizationModel(gomock.Any(), storeID, model.GetId()).Times(1).Return(nil),
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, model.GetId()).Times(1).Return(nil, storage.ErrNotFound),
+		mockDatastore.EXPECT().Close().Times(1),
+	)
+
+	_, err = cachingBackend.ReadAuthorizationModel(ctx, storeID, model.GetId())
+	require.NoError(t, err)
+
+	err = cachingBackend.DeleteAuthorizationModel(ctx, storeID, model.GetId())
+	require.NoError(t, err)
+
+	// The model is no longer served from the cache.
+	_, err = cachingBackend.ReadAuthorizationModel(ctx, storeID, model.GetId())
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,102 @@
+package storagewrappers
+
+import (
+	"context"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// PrefetchedUserTupleReader is a [storage.RelationshipTupleReader] that answers ReadUserTuple
+// for a known set of tuple keys from the result of a single, earlier [storage.RelationshipTupleReader.ReadUserTuples]
+// call. Any other read is delegated to the wrapped reader.
+type PrefetchedUserTupleReader struct {
+	storage.RelationshipTupleReader
+	store string
+
+	// prefetched maps the string form of each requested tuple key to the tuple that was found,
+	// or to nil if it is known not to exist.
+	prefetched map[string]*openfgav1.Tuple
+}
+
+var _ storage.RelationshipTupleReader = (*PrefetchedUserTupleReader)(nil)
+
+// NewPrefetchedUserTupleReader returns a [PrefetchedUserTupleReader] for the given store. The requested
+// keys are the ones that were passed to ReadUserTuples, and found is the result of that call.
+func NewPrefetchedUserTupleReader(
+	wrapped storage.RelationshipTupleReader,
+	store string,
+	requested []*openfgav1.TupleKey,
+	found []*openfgav1.Tuple,
+) *PrefetchedUserTupleReader {
+	prefetched := make(map[string]*openfgav1.Tuple, len(requested))
+	for _, tk := range requested {
+		prefetched[tuple.TupleKeyToString(tk)] = nil
+	}
+	for _, t := range found {
+		prefetched[tuple.TupleKeyToString(t.GetKey())] = t
+	}
+
+	return &PrefetchedUserTupleReader{
+		RelationshipTupleReader: wrapped,
+		store:                   store,
+		prefetched:              prefetched,
+	}
+}
+
+// ReadUserTuple see [storage.RelationshipTupleReader.ReadUserTuple].
+func (p *PrefetchedUserTupleReader) ReadUserTuple(
+	ctx context.Context,
+	store string,
+	tk *openfgav1.TupleKey,
+	options storage.ReadUserTupleOptions,
+) (*openfgav1.Tuple, error) {
+	if store == p.store {
+		if t, ok := p.prefetched[tuple.TupleKeyToString(tk)]; ok {
+			if t == nil {
+				return nil, storage.ErrNotFound
+			}
+			return t, nil
+		}
+	}
+
+	return p.RelationshipTupleReader.ReadUserTuple(ctx, store, tk, options)
+}
+
+// ReadUserTuples see [storage.RelationshipTupleReader.ReadUserTuples].
+func (p *PrefetchedUserTupleReader) ReadUserTuples(
+	ctx context.Context,
+	store string,
+	tupleKeys []*openfgav1.TupleKey,
+	options storage.ReadUserTupleOptions,
+) ([]*openfgav1.Tuple, error) {
+	if store != p.store {
+		return p.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	}
+
+	var tuples []*openfgav1.Tuple
+	remaining := make([]*openfgav1.TupleKey, 0, len(tupleKeys))
+	for _, tk := range tupleKeys {
+		t, ok := p.prefetched[tuple.TupleKeyToString(tk)]
+		if !ok {
+			remaining = append(remaining, tk)
+			continue
+		}
+		if t != nil {
+			tuples = append(tuples, t)
+		}
+	}
+
+	if len(remaining) == 0 {
+		return tuples, nil
+	}
+
+	fetched, err := p.RelationshipTupleReader.ReadUserTuples(ctx, store, remaining, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tuples, fetched...), nil
+}
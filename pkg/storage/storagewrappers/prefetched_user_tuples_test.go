@@ -0,0 +1,64 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestPrefetchedUserTupleReader(t *testing.T) {
+	ctx := context.Background()
+	storeID := "01JA7QGP1A6FQ7M0T0CPWHNZ1J"
+
+	requested := tuple.MustParseTupleStrings("group:1#member@user:11", "group:1#member@user:99")
+	found := []*openfgav1.Tuple{testTuples["group:1#member@user:11"]}
+
+	t.Run("prefetched_keys_are_served_without_reading", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockReader := mocks.NewMockRelationshipTupleReader(mockController)
+		reader := NewPrefetchedUserTupleReader(mockReader, storeID, requested, found)
+
+		got, err := reader.ReadUserTuple(ctx, storeID, requested[0], storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, found[0], got)
+
+		_, err = reader.ReadUserTuple(ctx, storeID, requested[1], storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		tuples, err := reader.ReadUserTuples(ctx, storeID, requested, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, found, tuples)
+	})
+
+	t.Run("unknown_keys_are_delegated", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		other := tuple.NewTupleKey("group:2", "member", "user:21")
+		mockReader := mocks.NewMockRelationshipTupleReader(mockController)
+		mockReader.EXPECT().ReadUserTuple(gomock.Any(), storeID, other, gomock.Any()).
+			Return(testTuples["group:2#member@user:21"], nil)
+		mockReader.EXPECT().ReadUserTuples(gomock.Any(), storeID, []*openfgav1.TupleKey{other}, gomock.Any()).
+			Return([]*openfgav1.Tuple{testTuples["group:2#member@user:21"]}, nil)
+
+		reader := NewPrefetchedUserTupleReader(mockReader, storeID, requested, found)
+
+		got, err := reader.ReadUserTuple(ctx, storeID, other, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, testTuples["group:2#member@user:21"], got)
+
+		tuples, err := reader.ReadUserTuples(ctx, storeID, []*openfgav1.TupleKey{requested[0], other}, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Len(t, tuples, 2)
+	})
+}
@@ -0,0 +1,237 @@
+package storagewrappers
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+// priorityStride is the virtual time a read of weight 1 advances its method by. A read of weight w advances it by
+// priorityStride / w, so that methods are granted slots in proportion to their weights while they all wait.
+const priorityStride = 1 << 20
+
+var (
+	_ storage.RelationshipTupleReader = (*PriorityLimitedTupleReader)(nil)
+
+	priorityLimiterWaitingGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_priority_limiter_waiting",
+		Help:      "The number of datastore reads waiting for a slot of the priority limiter, partitioned by method.",
+	}, []string{"method"})
+)
+
+// DefaultPriorityWeights are the weights of the methods of a [PriorityLimiter] created without any, which favor the
+// interactive Check and BatchCheck reads over those of the ListObjects, StreamedListObjects and ListUsers requests.
+var DefaultPriorityWeights = map[string]uint32{
+	"check":               8,
+	"batchcheck":          8,
+	"listobjects":         1,
+	"streamedlistobjects": 1,
+	"listusers":           1,
+}
+
+// PriorityLimiter bounds the number of concurrent datastore reads of every request. While the reads wait for a slot,
+// the slots are shared between the methods of the requests, as given by [telemetry.RPCInfo], in proportion to their
+// weights (weighted fair queuing), and between the reads of a method in the order they started waiting. A burst of
+// reads of a low weight method therefore cannot starve the reads of a high weight one.
+type PriorityLimiter struct {
+	slots   int
+	weights map[string]uint32
+
+	mu      sync.Mutex
+	inUse   int
+	waiting int
+	// queues are the waiting reads of each method, and passes the virtual time of each method.
+	queues map[string]*list.List
+	passes map[string]uint64
+	// pass is the virtual time of the last read granted a slot.
+	pass uint64
+}
+
+type priorityWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// NewPriorityLimiter returns a [PriorityLimiter] of slots concurrent reads, sharing them by the weights of the
+// methods, keyed by their case-insensitive name. The methods without a weight, or with a weight of 0, have a weight
+// of 1. If weights is empty, [DefaultPriorityWeights] are used.
+func NewPriorityLimiter(slots uint32, weights map[string]uint32) *PriorityLimiter {
+	if len(weights) == 0 {
+		weights = DefaultPriorityWeights
+	}
+
+	l := &PriorityLimiter{
+		slots:   int(slots),
+		weights: make(map[string]uint32, len(weights)),
+		queues:  make(map[string]*list.List),
+		passes:  make(map[string]uint64),
+	}
+	for method, weight := range weights {
+		l.weights[strings.ToLower(method)] = weight
+	}
+	return l
+}
+
+func (l *PriorityLimiter) weight(method string) uint32 {
+	if weight := l.weights[method]; weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// advance charges a read of method, which is granted a slot, to the virtual time of the method.
+func (l *PriorityLimiter) advance(method string) {
+	// a method which was idle starts from the current virtual time, so that it does not gain credit while idle
+	pass := max(l.passes[method], l.pass)
+	l.pass = pass
+	l.passes[method] = pass + priorityStride/uint64(l.weight(method))
+}
+
+// Acquire waits until a slot is available to a read of the method of ctx, or ctx is done.
+func (l *PriorityLimiter) Acquire(ctx context.Context) error {
+	method := strings.ToLower(telemetry.RPCInfoFromContext(ctx).Method)
+
+	l.mu.Lock()
+	if l.inUse < l.slots && l.waiting == 0 {
+		l.inUse++
+		l.advance(method)
+		l.mu.Unlock()
+		return nil
+	}
+
+	w := &priorityWaiter{ready: make(chan struct{})}
+	queue, ok := l.queues[method]
+	if !ok {
+		queue = list.New()
+		l.queues[method] = queue
+	}
+	elem := queue.PushBack(w)
+	l.waiting++
+	l.mu.Unlock()
+
+	gauge := priorityLimiterWaitingGauge.WithLabelValues(method)
+	gauge.Inc()
+	defer gauge.Dec()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		if w.granted {
+			// the slot was granted while ctx was done, it is handed over to the next read
+			l.mu.Unlock()
+			l.Release()
+			return ctx.Err()
+		}
+		queue.Remove(elem)
+		l.waiting--
+		if queue.Len() == 0 {
+			delete(l.queues, method)
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Release frees the slot of a read, granting it to the waiting read of the method with the lowest virtual time.
+func (l *PriorityLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	next := ""
+	for method := range l.queues {
+		if next == "" || l.passes[method] < l.passes[next] || (l.passes[method] == l.passes[next] && method < next) {
+			next = method
+		}
+	}
+	if next == "" {
+		l.inUse--
+		return
+	}
+
+	queue := l.queues[next]
+	w := queue.Remove(queue.Front()).(*priorityWaiter)
+	if queue.Len() == 0 {
+		delete(l.queues, next)
+	}
+	l.waiting--
+	l.advance(next)
+	w.granted = true
+	close(w.ready)
+}
+
+// PriorityLimitedTupleReader is a wrapper over a tuple reader bounding the concurrency of its reads with a
+// [PriorityLimiter]. The reads returning iterators hold their slot until the iterator is returned, like those of
+// [BoundedTupleReader].
+type PriorityLimitedTupleReader struct {
+	storage.RelationshipTupleReader
+	limiter *PriorityLimiter
+}
+
+// NewPriorityLimitedTupleReaderMiddleware returns a [TupleReaderMiddleware] bounding the concurrency of the reads
+// with limiter.
+func NewPriorityLimitedTupleReaderMiddleware(limiter *PriorityLimiter) TupleReaderMiddleware {
+	return func(inner storage.RelationshipTupleReader) storage.RelationshipTupleReader {
+		return &PriorityLimitedTupleReader{RelationshipTupleReader: inner, limiter: limiter}
+	}
+}
+
+// withPriority calls fn once the limiter granted it a slot.
+func withPriority[T any](ctx context.Context, l *PriorityLimiter, fn func() (T, error)) (T, error) {
+	if err := l.Acquire(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+	defer l.Release()
+	return fn()
+}
+
+func (p *PriorityLimitedTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	return withPriority(ctx, p.limiter, func() (storage.TupleIterator, error) {
+		return p.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	})
+}
+
+func (p *PriorityLimitedTupleReader) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	if err := p.limiter.Acquire(ctx); err != nil {
+		return nil, "", err
+	}
+	defer p.limiter.Release()
+	return p.RelationshipTupleReader.ReadPage(ctx, store, tupleKey, options)
+}
+
+func (p *PriorityLimitedTupleReader) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	return withPriority(ctx, p.limiter, func() (*openfgav1.Tuple, error) {
+		return p.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	})
+}
+
+func (p *PriorityLimitedTupleReader) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	return withPriority(ctx, p.limiter, func() ([]*openfgav1.Tuple, error) {
+		return p.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	})
+}
+
+func (p *PriorityLimitedTupleReader) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+	return withPriority(ctx, p.limiter, func() (storage.TupleIterator, error) {
+		return p.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	})
+}
+
+func (p *PriorityLimitedTupleReader) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	return withPriority(ctx, p.limiter, func() (storage.TupleIterator, error) {
+		return p.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	})
+}
@@ -0,0 +1,85 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/telemetry"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func methodContext(method string) context.Context {
+	return telemetry.ContextWithRPCInfo(context.Background(), telemetry.RPCInfo{Method: method})
+}
+
+func waitForWaiting(t *testing.T, l *PriorityLimiter, waiting int) {
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.waiting == waiting
+	}, time.Second, time.Millisecond)
+}
+
+func TestPriorityLimiter(t *testing.T) {
+	t.Run("favors_the_methods_of_higher_weight", func(t *testing.T) {
+		l := NewPriorityLimiter(1, map[string]uint32{"Check": 8, "listobjects": 1})
+		require.NoError(t, l.Acquire(methodContext("listobjects")))
+
+		granted := make(chan string, 6)
+		acquire := func(method string) {
+			go func() {
+				if l.Acquire(methodContext(method)) == nil {
+					granted <- method
+				}
+			}()
+		}
+		for i := 0; i < 4; i++ {
+			acquire("listobjects")
+			waitForWaiting(t, l, i+1)
+		}
+		acquire("Check")
+		acquire("Check")
+		waitForWaiting(t, l, 6)
+
+		var order []string
+		for i := 0; i < 6; i++ {
+			l.Release()
+			order = append(order, <-granted)
+		}
+		// the checks waited last, yet are granted the slot first
+		require.Equal(t, []string{"Check", "Check", "listobjects", "listobjects", "listobjects", "listobjects"}, order)
+	})
+
+	t.Run("slots_are_granted_without_waiting", func(t *testing.T) {
+		l := NewPriorityLimiter(2, nil)
+		require.NoError(t, l.Acquire(methodContext("check")))
+		require.NoError(t, l.Acquire(methodContext("listobjects")))
+
+		ctx, cancel := context.WithTimeout(methodContext("check"), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, l.Acquire(ctx), context.DeadlineExceeded)
+		waitForWaiting(t, l, 0)
+
+		l.Release()
+		require.NoError(t, l.Acquire(methodContext("check")))
+	})
+
+	t.Run("reads_through_the_middleware", func(t *testing.T) {
+		ds := memory.New()
+		t.Cleanup(ds.Close)
+		l := NewPriorityLimiter(1, nil)
+		reader := NewPriorityLimitedTupleReaderMiddleware(l)(ds)
+
+		ctx := methodContext("check")
+		_, err := reader.ReadUserTuple(ctx, "store", tuple.NewTupleKey("document:1", "viewer", "user:anne"), storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// the slot was released
+		require.NoError(t, l.Acquire(ctx))
+	})
+}
@@ -19,6 +19,12 @@ type Operation struct {
 	Concurrency       uint32
 	ThrottleThreshold int
 	ThrottleDuration  time.Duration
+
+	// SaturationMonitor, if set, observes the time spent waiting for the concurrency limiter.
+	SaturationMonitor *LimiterSaturationMonitor
+
+	// HedgeDelay, if positive, is how long a read may take before an identical read is issued. See [BoundedTupleReader].
+	HedgeDelay time.Duration
 }
 
 // RequestStorageWrapper uses the decorator pattern to wrap a RelationshipTupleReader with various functionalities,
@@ -41,6 +47,17 @@ func NewRequestStorageWrapperWithCache(
 	instrumented := NewBoundedTupleReader(ds, op) // to rate-limit reads
 	var tupleReader storage.RelationshipTupleReader
 	tupleReader = instrumented
+	if op.Method == apimethod.Check && cacheSettings.ShouldCacheCheckTuples() {
+		// Reads single tuples from cache where possible
+		tupleReader = NewCachedTupleReader(
+			tupleReader,
+			resources.CheckCache,
+			cacheSettings.CheckTupleCacheTTL,
+			WithCachedTupleReaderLogger(resources.Logger),
+			WithCachedTupleReaderClock(resources.Clock),
+			WithCachedTupleReaderMethodName(string(op.Method)),
+		)
+	}
 	if op.Method == apimethod.Check && cacheSettings.ShouldCacheCheckIterators() {
 		// Reads tuples from cache where possible
 		tupleReader = NewCachedDatastore(
@@ -52,6 +69,7 @@ func NewRequestStorageWrapperWithCache(
 			resources.SingleflightGroup,
 			resources.WaitGroup,
 			WithCachedDatastoreLogger(resources.Logger),
+			WithCachedDatastoreClock(resources.Clock),
 			WithCachedDatastoreMethodName(string(op.Method)),
 		)
 	} else if op.Method == apimethod.ListObjects && cacheSettings.ShouldCacheListObjectsIterators() {
@@ -64,6 +82,7 @@ func NewRequestStorageWrapperWithCache(
 			resources.SingleflightGroup,
 			resources.WaitGroup,
 			WithCachedDatastoreLogger(resources.Logger),
+			WithCachedDatastoreClock(resources.Clock),
 			WithCachedDatastoreMethodName(string(op.Method)),
 		)
 	}
@@ -77,6 +96,10 @@ func NewRequestStorageWrapperWithCache(
 			sharediterator.WithMaxTTL(cacheSettings.SharedIteratorTTL),
 			sharediterator.WithIteratorTargetSize(iteratorTargetSize))
 	}
+	if op.Method == apimethod.Check && cacheSettings.ShouldCacheRequestIterators() {
+		// Reads tuples already read by another branch of the request where possible
+		tupleReader = NewRequestIteratorCache(tupleReader, string(op.Method), int(cacheSettings.RequestIteratorCacheMaxResults))
+	}
 	combinedTupleReader := NewCombinedTupleReader(tupleReader, requestContextualTuples) // to read the contextual tuples
 
 	return &RequestStorageWrapper{
@@ -0,0 +1,122 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+)
+
+var (
+	_ storage.RelationshipTupleReader = (*RequestIteratorCache)(nil)
+	_ storage.TupleIterator           = (*requestCachingIterator)(nil)
+
+	requestIteratorCacheHitCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "request_iterator_cache_hit_count",
+		Help:      "The total number of datastore reads served by the iterator cache of a single request.",
+	}, []string{"operation", "method"})
+)
+
+// RequestIteratorCache is a wrapper over a datastore memoizing the tuples returned by ReadUsersetTuples, so that
+// the branches of a single request issuing the same query read the datastore once. It must not outlive the request.
+type RequestIteratorCache struct {
+	storage.RelationshipTupleReader
+	method     string
+	maxResults int
+
+	mu      sync.Mutex
+	entries map[string][]*openfgav1.Tuple
+}
+
+// NewRequestIteratorCache returns a wrapper over a datastore memoizing the tuples of the iterators it returns. The
+// results of a query are memoized once its iterator is fully consumed, unless they exceed maxResults tuples.
+func NewRequestIteratorCache(wrapped storage.RelationshipTupleReader, method string, maxResults int) *RequestIteratorCache {
+	return &RequestIteratorCache{
+		RelationshipTupleReader: wrapped,
+		method:                  method,
+		maxResults:              maxResults,
+		entries:                 map[string][]*openfgav1.Tuple{},
+	}
+}
+
+// ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
+func (c *RequestIteratorCache) ReadUsersetTuples(
+	ctx context.Context,
+	store string,
+	filter storage.ReadUsersetTuplesFilter,
+	options storage.ReadUsersetTuplesOptions,
+) (storage.TupleIterator, error) {
+	key := storagewrappersutil.ReadUsersetTuplesKey(store, filter)
+
+	c.mu.Lock()
+	tuples, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok {
+		requestIteratorCacheHitCounter.WithLabelValues(storagewrappersutil.OperationReadUsersetTuples, c.method).Inc()
+		return storage.NewStaticTupleIterator(tuples), nil
+	}
+
+	iter, err := c.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requestCachingIterator{
+		TupleIterator: iter,
+		cache:         c,
+		key:           key,
+		tuples:        make([]*openfgav1.Tuple, 0),
+	}, nil
+}
+
+func (c *RequestIteratorCache) store(key string, tuples []*openfgav1.Tuple) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = tuples
+}
+
+// requestCachingIterator records the tuples of the iterator it wraps, and memoizes them in its cache once the
+// iterator is done.
+type requestCachingIterator struct {
+	storage.TupleIterator
+	cache *RequestIteratorCache
+	key   string
+
+	mu sync.Mutex
+	// tuples is nil once the results are not to be memoized, either because they are incomplete, exceed the size cap,
+	// or were memoized already.
+	tuples []*openfgav1.Tuple
+}
+
+// Next see [storage.Iterator].Next.
+func (r *requestCachingIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, err := r.TupleIterator.Next(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrIteratorDone) && r.tuples != nil {
+			r.cache.store(r.key, r.tuples)
+		}
+		r.tuples = nil
+		return nil, err
+	}
+
+	if r.tuples != nil {
+		if len(r.tuples) >= r.cache.maxResults {
+			r.tuples = nil
+		} else {
+			r.tuples = append(r.tuples, t)
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,100 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestRequestIteratorCache(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	ctx := context.Background()
+	store := ulid.Make().String()
+	filter := storage.ReadUsersetTuplesFilter{
+		Object:                      "document:1",
+		Relation:                    "viewer",
+		AllowedUserTypeRestrictions: []*openfgav1.RelationReference{{Type: "group", RelationOrWildcard: &openfgav1.RelationReference_Relation{Relation: "member"}}},
+	}
+	tuples := []*openfgav1.Tuple{
+		{Key: tuple.NewTupleKey("document:1", "viewer", "group:1#member")},
+		{Key: tuple.NewTupleKey("document:1", "viewer", "group:2#member")},
+	}
+
+	drain := func(t *testing.T, iter storage.TupleIterator) []*openfgav1.Tuple {
+		defer iter.Stop()
+		var got []*openfgav1.Tuple
+		for {
+			tk, err := iter.Next(ctx)
+			if err != nil {
+				require.ErrorIs(t, err, storage.ErrIteratorDone)
+				return got
+			}
+			got = append(got, tk)
+		}
+	}
+
+	t.Run("consumed_iterator_is_memoized", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadUsersetTuples(gomock.Any(), store, filter, gomock.Any()).Times(1).
+			Return(storage.NewStaticTupleIterator(tuples), nil)
+
+		cache := NewRequestIteratorCache(mockDatastore, "Check", 10)
+		for range 2 {
+			iter, err := cache.ReadUsersetTuples(ctx, store, filter, storage.ReadUsersetTuplesOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tuples, drain(t, iter))
+		}
+	})
+
+	t.Run("partially_consumed_iterator_is_not_memoized", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadUsersetTuples(gomock.Any(), store, filter, gomock.Any()).Times(2).
+			DoAndReturn(func(context.Context, string, storage.ReadUsersetTuplesFilter, storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+				return storage.NewStaticTupleIterator(tuples), nil
+			})
+
+		cache := NewRequestIteratorCache(mockDatastore, "Check", 10)
+		iter, err := cache.ReadUsersetTuples(ctx, store, filter, storage.ReadUsersetTuplesOptions{})
+		require.NoError(t, err)
+		_, err = iter.Next(ctx)
+		require.NoError(t, err)
+		iter.Stop()
+
+		iter, err = cache.ReadUsersetTuples(ctx, store, filter, storage.ReadUsersetTuplesOptions{})
+		require.NoError(t, err)
+		require.Equal(t, tuples, drain(t, iter))
+	})
+
+	t.Run("results_above_the_cap_are_not_memoized", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+		mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadUsersetTuples(gomock.Any(), store, filter, gomock.Any()).Times(2).
+			DoAndReturn(func(context.Context, string, storage.ReadUsersetTuplesFilter, storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+				return storage.NewStaticTupleIterator(tuples), nil
+			})
+
+		cache := NewRequestIteratorCache(mockDatastore, "Check", 1)
+		for range 2 {
+			iter, err := cache.ReadUsersetTuples(ctx, store, filter, storage.ReadUsersetTuplesOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tuples, drain(t, iter))
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package storagewrappers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+)
+
+var (
+	_ storage.RelationshipTupleReader = (*RetryingTupleReader)(nil)
+	_ storage.RelationshipTupleWriter = (*RetryingTupleWriter)(nil)
+
+	datastoreRetryCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_retry_count",
+		Help:      "The total number of datastore calls retried after a transient error, partitioned by operation and error class.",
+	}, []string{"operation", "error_class"})
+)
+
+// RetryPolicy defines how calls failing with a class of transient errors are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a call is retried. 0 disables retries.
+	MaxRetries int
+	// InitialInterval is the delay before the first retry. The delay doubles on each retry, with jitter.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between retries.
+	MaxInterval time.Duration
+}
+
+// RetryConfig defines which transient errors of a datastore are retried.
+type RetryConfig struct {
+	// Policies are the retry policies of each class of [storage.TransientError]. Classes without a policy are not
+	// retried.
+	Policies map[storage.TransientErrorClass]RetryPolicy
+	// RetryWrites also retries writes, on the transient errors after which the datastore rolled the transaction back.
+	// Writes failing with a [storage.TransientConnection] error are never retried, since they may have been committed.
+	RetryWrites bool
+}
+
+type retrier struct {
+	config RetryConfig
+}
+
+// retry calls fn until it succeeds, fails with an error which is not to be retried, or ctx is done.
+func retry[T any](ctx context.Context, r *retrier, op string, fn func() (T, error)) (T, error) {
+	var (
+		retries  map[storage.TransientErrorClass]int
+		backoffs map[storage.TransientErrorClass]*backoff.ExponentialBackOff
+	)
+	for {
+		res, err := fn()
+		if err == nil {
+			return res, nil
+		}
+
+		class, ok := storage.TransientErrorClassOf(err)
+		if !ok || (op == storagewrappersutil.OperationWrite && (!r.config.RetryWrites || class == storage.TransientConnection)) {
+			return res, err
+		}
+		policy, ok := r.config.Policies[class]
+		if !ok || retries[class] >= policy.MaxRetries {
+			return res, err
+		}
+
+		if retries == nil {
+			retries = map[storage.TransientErrorClass]int{}
+			backoffs = map[storage.TransientErrorClass]*backoff.ExponentialBackOff{}
+		}
+		b, ok := backoffs[class]
+		if !ok {
+			b = backoff.NewExponentialBackOff()
+			b.InitialInterval = policy.InitialInterval
+			b.MaxInterval = policy.MaxInterval
+			b.MaxElapsedTime = 0
+			b.Reset()
+			backoffs[class] = b
+		}
+		retries[class]++
+		datastoreRetryCounter.WithLabelValues(op, string(class)).Inc()
+
+		timer := time.NewTimer(b.NextBackOff())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, err
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryingTupleReader is a wrapper over a datastore retrying its tuple reads failing with transient errors.
+type RetryingTupleReader struct {
+	storage.RelationshipTupleReader
+	retrier *retrier
+}
+
+// NewRetryingTupleReader returns a wrapper over a datastore retrying its tuple reads as configured. Errors returned
+// by the iterators of reads are not retried.
+func NewRetryingTupleReader(wrapped storage.RelationshipTupleReader, config RetryConfig) *RetryingTupleReader {
+	return &RetryingTupleReader{
+		RelationshipTupleReader: wrapped,
+		retrier:                 &retrier{config: config},
+	}
+}
+
+// Read see [storage.RelationshipTupleReader].Read.
+func (r *RetryingTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	return retry(ctx, r.retrier, storagewrappersutil.OperationRead, func() (storage.TupleIterator, error) {
+		return r.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	})
+}
+
+// ReadPage see [storage.RelationshipTupleReader].ReadPage.
+func (r *RetryingTupleReader) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	var continuationToken string
+	tuples, err := retry(ctx, r.retrier, storagewrappersutil.OperationReadPage, func() ([]*openfgav1.Tuple, error) {
+		tuples, token, err := r.RelationshipTupleReader.ReadPage(ctx, store, tupleKey, options)
+		continuationToken = token
+		return tuples, err
+	})
+	return tuples, continuationToken, err
+}
+
+// ReadUserTuple see [storage.RelationshipTupleReader].ReadUserTuple.
+func (r *RetryingTupleReader) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	return retry(ctx, r.retrier, storagewrappersutil.OperationReadUserTuple, func() (*openfgav1.Tuple, error) {
+		return r.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	})
+}
+
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (r *RetryingTupleReader) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	return retry(ctx, r.retrier, storagewrappersutil.OperationReadUserTuples, func() ([]*openfgav1.Tuple, error) {
+		return r.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	})
+}
+
+// ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
+func (r *RetryingTupleReader) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+	return retry(ctx, r.retrier, storagewrappersutil.OperationReadUsersetTuples, func() (storage.TupleIterator, error) {
+		return r.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	})
+}
+
+// ReadStartingWithUser see [storage.RelationshipTupleReader].ReadStartingWithUser.
+func (r *RetryingTupleReader) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	return retry(ctx, r.retrier, storagewrappersutil.OperationReadStartingWithUser, func() (storage.TupleIterator, error) {
+		return r.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	})
+}
+
+// RetryingTupleWriter is a wrapper over a datastore retrying its tuple writes failing with transient errors, if
+// [RetryConfig].RetryWrites is set.
+type RetryingTupleWriter struct {
+	storage.RelationshipTupleWriter
+	retrier *retrier
+}
+
+// NewRetryingTupleWriter returns a wrapper over a datastore retrying its tuple writes as configured.
+func NewRetryingTupleWriter(wrapped storage.RelationshipTupleWriter, config RetryConfig) *RetryingTupleWriter {
+	return &RetryingTupleWriter{
+		RelationshipTupleWriter: wrapped,
+		retrier:                 &retrier{config: config},
+	}
+}
+
+// Write see [storage.RelationshipTupleWriter].Write.
+func (r *RetryingTupleWriter) Write(ctx context.Context, store string, d storage.Deletes, w storage.Writes) error {
+	_, err := retry(ctx, r.retrier, storagewrappersutil.OperationWrite, func() (struct{}, error) {
+		return struct{}{}, r.RelationshipTupleWriter.Write(ctx, store, d, w)
+	})
+	return err
+}
+
+// NewRetryingDatastore returns a wrapper over a datastore retrying its tuple reads and writes as configured.
+func NewRetryingDatastore(inner storage.OpenFGADatastore, config RetryConfig) storage.OpenFGADatastore {
+	return newWrappedDatastore(inner, NewRetryingTupleReader(inner, config), NewRetryingTupleWriter(inner, config))
+}
@@ -0,0 +1,117 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestRetryingDatastore(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+
+	policy := RetryPolicy{MaxRetries: 2, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+	config := RetryConfig{
+		Policies: map[storage.TransientErrorClass]RetryPolicy{
+			storage.TransientConnection:    policy,
+			storage.TransientSerialization: policy,
+		},
+	}
+
+	connectionErr := &storage.TransientError{Class: storage.TransientConnection, Err: errors.New("connection reset by peer")}
+	serializationErr := &storage.TransientError{Class: storage.TransientSerialization, Err: errors.New("could not serialize access")}
+	deadlockErr := &storage.TransientError{Class: storage.TransientDeadlock, Err: errors.New("deadlock detected")}
+
+	t.Run("retries_reads_until_they_succeed", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewRetryingDatastore(mockDatastore, config)
+		expected := &openfgav1.Tuple{Key: tk}
+		gomock.InOrder(
+			mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(nil, connectionErr),
+			mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(expected, nil),
+		)
+
+		got, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+	})
+
+	t.Run("gives_up_after_max_retries", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewRetryingDatastore(mockDatastore, config)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(3).Return(nil, connectionErr)
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, connectionErr)
+	})
+
+	t.Run("does_not_retry_classes_without_policy", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewRetryingDatastore(mockDatastore, config)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(nil, deadlockErr)
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, deadlockErr)
+	})
+
+	t.Run("does_not_retry_other_errors", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewRetryingDatastore(mockDatastore, config)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(nil, storage.ErrNotFound)
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("does_not_retry_writes_by_default", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewRetryingDatastore(mockDatastore, config)
+		mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).Return(serializationErr)
+
+		err := ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk})
+		require.ErrorIs(t, err, serializationErr)
+	})
+
+	t.Run("retries_rolled_back_writes_if_enabled", func(t *testing.T) {
+		config := config
+		config.RetryWrites = true
+
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewRetryingDatastore(mockDatastore, config)
+		gomock.InOrder(
+			mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).Return(serializationErr),
+			mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).Return(nil),
+		)
+		require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk}))
+
+		// A write failing with a connection error may have been committed.
+		mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).Return(connectionErr)
+		require.ErrorIs(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk}), connectionErr)
+	})
+
+	t.Run("stops_retrying_once_the_context_is_done", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewRetryingDatastore(mockDatastore, RetryConfig{
+			Policies: map[storage.TransientErrorClass]RetryPolicy{
+				storage.TransientConnection: {MaxRetries: 10, InitialInterval: time.Hour, MaxInterval: time.Hour},
+			},
+		})
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(nil, connectionErr)
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, connectionErr)
+	})
+}
@@ -0,0 +1,127 @@
+package storagewrappers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openfga/openfga/internal/build"
+)
+
+var limiterSaturatedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: build.ProjectName,
+	Name:      "datastore_bounded_read_limiter_saturated",
+	Help:      "Set to 1 while the time spent waiting for the datastore read concurrency limiter has exceeded the configured threshold for a sustained period, and 0 otherwise.",
+})
+
+const (
+	// saturationBuckets is the number of buckets the window of a LimiterSaturationMonitor slides by.
+	saturationBuckets = 10
+	// minSaturationWaits is the number of waits a window must hold for the limiter to be reported as saturated, so
+	// that a few slow reads of an idle server don't.
+	minSaturationWaits = 10
+)
+
+// LimiterSaturationMonitor detects sustained saturation of the bounded-concurrency limiter used by
+// [BoundedTupleReader]. The limiter is considered saturated while the fraction of the waits observed during the last
+// period which exceeded the configured threshold is at least the configured ratio. As reads keep acquiring the
+// limiter as others release it, some waits are short even while it is saturated.
+//
+// A nil *LimiterSaturationMonitor is valid and never reports saturation.
+type LimiterSaturationMonitor struct {
+	threshold time.Duration
+	period    time.Duration
+	ratio     float64
+	now       func() time.Time
+
+	mu        sync.Mutex
+	buckets   [saturationBuckets]saturationBucket // GUARDED_BY(mu).
+	saturated bool                                // GUARDED_BY(mu).
+}
+
+// saturationBucket counts the waits observed during a slice of the period.
+type saturationBucket struct {
+	start time.Time
+	waits int
+	slow  int
+}
+
+// NewLimiterSaturationMonitor returns a [LimiterSaturationMonitor] that reports saturation while at least ratio of the
+// limiter wait times of the last period exceed threshold.
+func NewLimiterSaturationMonitor(threshold, period time.Duration, ratio float64) *LimiterSaturationMonitor {
+	return &LimiterSaturationMonitor{
+		threshold: threshold,
+		period:    period,
+		ratio:     ratio,
+		now:       time.Now,
+	}
+}
+
+// Observe records the time a read spent waiting for the limiter.
+func (m *LimiterSaturationMonitor) Observe(wait time.Duration) {
+	if m == nil {
+		return
+	}
+
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	width := max(m.period/saturationBuckets, 1)
+	start := now.Truncate(width)
+	bucket := &m.buckets[(start.UnixNano()/int64(width))%saturationBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = saturationBucket{start: start}
+	}
+
+	bucket.waits++
+	if wait > m.threshold {
+		bucket.slow++
+	}
+
+	m.update(now)
+}
+
+// IsSaturated reports whether the limiter is currently saturated.
+func (m *LimiterSaturationMonitor) IsSaturated() bool {
+	if m == nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the waits leave the window even if no read waits anymore
+	m.update(m.now())
+
+	return m.saturated
+}
+
+// update reports the limiter as saturated if enough of the waits of the window ending at now were slow. It must be
+// called with mu held.
+func (m *LimiterSaturationMonitor) update(now time.Time) {
+	var waits, slow int
+	for _, bucket := range m.buckets {
+		if now.Sub(bucket.start) < m.period {
+			waits += bucket.waits
+			slow += bucket.slow
+		}
+	}
+
+	m.setSaturated(waits >= minSaturationWaits && float64(slow) >= m.ratio*float64(waits))
+}
+func (m *LimiterSaturationMonitor) setSaturated(saturated bool) {
+	if m.saturated == saturated {
+		return
+	}
+
+	m.saturated = saturated
+	if saturated {
+		limiterSaturatedGauge.Set(1)
+	} else {
+		limiterSaturatedGauge.Set(0)
+	}
+}
@@ -0,0 +1,80 @@
+package storagewrappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterSaturationMonitor(t *testing.T) {
+	newMonitor := func() (*LimiterSaturationMonitor, *time.Time) {
+		now := time.Now()
+		m := NewLimiterSaturationMonitor(10*time.Millisecond, time.Second, 0.5)
+		m.now = func() time.Time { return now }
+		return m, &now
+	}
+
+	observe := func(m *LimiterSaturationMonitor, slow, fast int) {
+		for i := 0; i < slow; i++ {
+			m.Observe(20 * time.Millisecond)
+		}
+		for i := 0; i < fast; i++ {
+			m.Observe(time.Millisecond)
+		}
+	}
+
+	t.Run("nil_monitor_is_never_saturated", func(t *testing.T) {
+		var m *LimiterSaturationMonitor
+		m.Observe(time.Hour)
+		require.False(t, m.IsSaturated())
+	})
+
+	t.Run("saturated_while_enough_waits_are_slow", func(t *testing.T) {
+		m, _ := newMonitor()
+
+		observe(m, 4, 6)
+		require.False(t, m.IsSaturated())
+
+		// short waits interleaved with slow ones do not clear the saturation
+		observe(m, 6, 2)
+		require.True(t, m.IsSaturated())
+		observe(m, 0, 1)
+		require.True(t, m.IsSaturated())
+	})
+
+	t.Run("few_waits_are_not_saturation", func(t *testing.T) {
+		m, _ := newMonitor()
+
+		observe(m, minSaturationWaits-1, 0)
+		require.False(t, m.IsSaturated())
+
+		observe(m, 1, 0)
+		require.True(t, m.IsSaturated())
+	})
+
+	t.Run("slow_waits_leave_the_window", func(t *testing.T) {
+		m, now := newMonitor()
+
+		observe(m, 10, 0)
+		require.True(t, m.IsSaturated())
+
+		*now = now.Add(600 * time.Millisecond)
+		observe(m, 0, 10)
+		require.True(t, m.IsSaturated())
+
+		*now = now.Add(500 * time.Millisecond)
+		observe(m, 0, 1)
+		require.False(t, m.IsSaturated())
+	})
+
+	t.Run("saturation_expires_without_waits", func(t *testing.T) {
+		m, now := newMonitor()
+
+		observe(m, 10, 0)
+		require.True(t, m.IsSaturated())
+
+		*now = now.Add(2 * time.Second)
+		require.False(t, m.IsSaturated())
+	})
+}
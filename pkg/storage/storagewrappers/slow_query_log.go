@@ -0,0 +1,226 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/redact"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+var (
+	_ storage.RelationshipTupleReader = (*SlowQueryLoggingTupleReader)(nil)
+	_ storage.RelationshipTupleWriter = (*SlowQueryLoggingTupleWriter)(nil)
+	_ storage.TupleIterator           = (*slowQueryLoggingIterator)(nil)
+)
+
+// slowQueryLogger logs the datastore calls taking longer than a threshold.
+type slowQueryLogger struct {
+	threshold time.Duration
+	logger    logger.Logger
+}
+
+// log logs the call of operation on store, started at start, if it took longer than the threshold. The fields
+// describe the shape of its filter, without the ids of the objects and users.
+func (l *slowQueryLogger) log(ctx context.Context, operation, store string, start time.Time, err error, fields ...zap.Field) {
+	duration := time.Since(start)
+	if duration < l.threshold {
+		return
+	}
+
+	fields = append(fields,
+		zap.String("operation", operation),
+		zap.String("store_id", store),
+		zap.Duration("duration", duration),
+	)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) && !errors.Is(err, storage.ErrIteratorDone) {
+		fields = append(fields, zap.Error(redact.Error(err)))
+	}
+	l.logger.WarnWithContext(ctx, "slow datastore query", fields...)
+}
+
+// tupleKeyFields returns the shape of a tuple key filter.
+func tupleKeyFields(tupleKey *openfgav1.TupleKey) []zap.Field {
+	return []zap.Field{
+		zap.String("object", redact.ObjectShape(tupleKey.GetObject())),
+		zap.String("relation", tupleKey.GetRelation()),
+		zap.String("user", redact.UserShape(tupleKey.GetUser())),
+	}
+}
+
+// slowQueryLoggingIterator logs the query of an iterator, executed on its first Next or Head, if it was slow.
+type slowQueryLoggingIterator struct {
+	storage.TupleIterator
+	logger    *slowQueryLogger
+	operation string
+	store     string
+	fields    []zap.Field
+	queried   atomic.Bool
+}
+
+func (s *slowQueryLoggingIterator) logFirst(ctx context.Context, start time.Time, err error) {
+	if s.queried.CompareAndSwap(false, true) {
+		s.logger.log(ctx, s.operation, s.store, start, err, s.fields...)
+	}
+}
+
+// Next see [storage.Iterator].Next.
+func (s *slowQueryLoggingIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
+	start := time.Now()
+	t, err := s.TupleIterator.Next(ctx)
+	s.logFirst(ctx, start, err)
+	return t, err
+}
+
+// Head see [storage.Iterator].Head.
+func (s *slowQueryLoggingIterator) Head(ctx context.Context) (*openfgav1.Tuple, error) {
+	start := time.Now()
+	t, err := s.TupleIterator.Head(ctx)
+	s.logFirst(ctx, start, err)
+	return t, err
+}
+
+// SlowQueryLoggingTupleReader is a wrapper over a datastore logging its tuple reads taking longer than a threshold,
+// to find the hotspots and missing indexes of the datastore.
+type SlowQueryLoggingTupleReader struct {
+	storage.RelationshipTupleReader
+	logger *slowQueryLogger
+}
+
+// NewSlowQueryLoggingTupleReader returns a wrapper over a datastore logging its tuple reads taking longer than
+// threshold, with the shape of their filter. The queries of the iterators returned by reads are measured on the first
+// call to their Next or Head, which executes them.
+func NewSlowQueryLoggingTupleReader(wrapped storage.RelationshipTupleReader, threshold time.Duration, logger logger.Logger) *SlowQueryLoggingTupleReader {
+	return &SlowQueryLoggingTupleReader{
+		RelationshipTupleReader: wrapped,
+		logger:                  &slowQueryLogger{threshold: threshold, logger: logger},
+	}
+}
+
+func (s *SlowQueryLoggingTupleReader) iterator(iter storage.TupleIterator, err error, operation, store string, fields ...zap.Field) (storage.TupleIterator, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &slowQueryLoggingIterator{
+		TupleIterator: iter,
+		logger:        s.logger,
+		operation:     operation,
+		store:         store,
+		fields:        fields,
+	}, nil
+}
+
+// Read see [storage.RelationshipTupleReader].Read.
+func (s *SlowQueryLoggingTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	iter, err := s.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	return s.iterator(iter, err, storagewrappersutil.OperationRead, store, tupleKeyFields(tupleKey)...)
+}
+
+// ReadPage see [storage.RelationshipTupleReader].ReadPage.
+func (s *SlowQueryLoggingTupleReader) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	start := time.Now()
+	tuples, continuationToken, err := s.RelationshipTupleReader.ReadPage(ctx, store, tupleKey, options)
+	s.logger.log(ctx, storagewrappersutil.OperationReadPage, store, start, err,
+		append(tupleKeyFields(tupleKey), zap.Int("page_size", options.Pagination.PageSize))...)
+	return tuples, continuationToken, err
+}
+
+// ReadUserTuple see [storage.RelationshipTupleReader].ReadUserTuple.
+func (s *SlowQueryLoggingTupleReader) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	start := time.Now()
+	t, err := s.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	s.logger.log(ctx, storagewrappersutil.OperationReadUserTuple, store, start, err, tupleKeyFields(tupleKey)...)
+	return t, err
+}
+
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (s *SlowQueryLoggingTupleReader) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	start := time.Now()
+	tuples, err := s.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	fields := []zap.Field{zap.Int("tuple_keys", len(tupleKeys))}
+	if len(tupleKeys) > 0 {
+		fields = append(fields, tupleKeyFields(tupleKeys[0])...)
+	}
+	s.logger.log(ctx, storagewrappersutil.OperationReadUserTuples, store, start, err, fields...)
+	return tuples, err
+}
+
+// ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
+func (s *SlowQueryLoggingTupleReader) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+	iter, err := s.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+
+	userTypes := make([]string, 0, len(filter.AllowedUserTypeRestrictions))
+	for _, restriction := range filter.AllowedUserTypeRestrictions {
+		userType := restriction.GetType()
+		switch {
+		case restriction.GetRelation() != "":
+			userType += "#" + restriction.GetRelation()
+		case restriction.GetWildcard() != nil:
+			userType += ":" + tuple.Wildcard
+		}
+		userTypes = append(userTypes, userType)
+	}
+	return s.iterator(iter, err, storagewrappersutil.OperationReadUsersetTuples, store,
+		zap.String("object", redact.ObjectShape(filter.Object)),
+		zap.String("relation", filter.Relation),
+		zap.Strings("user_types", userTypes),
+	)
+}
+
+// ReadStartingWithUser see [storage.RelationshipTupleReader].ReadStartingWithUser.
+func (s *SlowQueryLoggingTupleReader) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	iter, err := s.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+
+	users := make([]string, 0, len(filter.UserFilter))
+	for _, user := range filter.UserFilter {
+		users = append(users, redact.UserShape(tuple.GetObjectRelationAsString(user)))
+	}
+	objectIDs := 0
+	if filter.ObjectIDs != nil {
+		objectIDs = filter.ObjectIDs.Size()
+	}
+	return s.iterator(iter, err, storagewrappersutil.OperationReadStartingWithUser, store,
+		zap.String("object_type", filter.ObjectType),
+		zap.String("relation", filter.Relation),
+		zap.Strings("users", users),
+		zap.Int("object_ids", objectIDs),
+	)
+}
+
+// SlowQueryLoggingTupleWriter is a wrapper over a datastore logging its tuple writes taking longer than a threshold.
+type SlowQueryLoggingTupleWriter struct {
+	storage.RelationshipTupleWriter
+	logger *slowQueryLogger
+}
+
+// NewSlowQueryLoggingTupleWriter returns a wrapper over a datastore logging its tuple writes taking longer than
+// threshold, with their number of deletes and writes.
+func NewSlowQueryLoggingTupleWriter(wrapped storage.RelationshipTupleWriter, threshold time.Duration, logger logger.Logger) *SlowQueryLoggingTupleWriter {
+	return &SlowQueryLoggingTupleWriter{
+		RelationshipTupleWriter: wrapped,
+		logger:                  &slowQueryLogger{threshold: threshold, logger: logger},
+	}
+}
+
+// Write see [storage.RelationshipTupleWriter].Write.
+func (s *SlowQueryLoggingTupleWriter) Write(ctx context.Context, store string, d storage.Deletes, w storage.Writes) error {
+	start := time.Now()
+	err := s.RelationshipTupleWriter.Write(ctx, store, d, w)
+	s.logger.log(ctx, storagewrappersutil.OperationWrite, store, start, err, zap.Int("deletes", len(d)), zap.Int("writes", len(w)))
+	return err
+}
+
+// NewSlowQueryLoggingDatastore returns a wrapper over a datastore logging its tuple reads and writes taking longer
+// than threshold.
+func NewSlowQueryLoggingDatastore(inner storage.OpenFGADatastore, threshold time.Duration, logger logger.Logger) storage.OpenFGADatastore {
+	return newWrappedDatastore(inner, NewSlowQueryLoggingTupleReader(inner, threshold, logger), NewSlowQueryLoggingTupleWriter(inner, threshold, logger))
+}
@@ -0,0 +1,126 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestSlowQueryLoggingDatastore(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "group:eng#member")
+
+	newDatastore := func(t *testing.T, threshold time.Duration) (*gomock.Controller, *mocks.MockOpenFGADatastore, storage.OpenFGADatastore, *observer.ObservedLogs) {
+		mockController, mockDatastore := newMockDatastore(t)
+		observerLogger, logs := observer.New(zap.WarnLevel)
+		ds := NewSlowQueryLoggingDatastore(mockDatastore, threshold, &logger.ZapLogger{Logger: zap.New(observerLogger)})
+		return mockController, mockDatastore, ds, logs
+	}
+
+	slow := func() {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Run("logs_slow_reads_with_a_sanitized_filter", func(t *testing.T) {
+		_, mockDatastore, ds, logs := newDatastore(t, 10*time.Millisecond)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).
+			DoAndReturn(func(context.Context, string, *openfgav1.TupleKey, storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+				slow()
+				return nil, storage.ErrNotFound
+			})
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		entries := logs.All()
+		require.Len(t, entries, 1)
+		require.Equal(t, "slow datastore query", entries[0].Message)
+		fields := entries[0].ContextMap()
+		require.Equal(t, "ReadUserTuple", fields["operation"])
+		require.Equal(t, storeID, fields["store_id"])
+		require.Equal(t, "document:?", fields["object"])
+		require.Equal(t, "viewer", fields["relation"])
+		require.Equal(t, "group:?#member", fields["user"])
+		require.NotContains(t, fields, "error")
+	})
+
+	t.Run("does_not_log_fast_reads", func(t *testing.T) {
+		_, mockDatastore, ds, logs := newDatastore(t, time.Minute)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(&openfgav1.Tuple{Key: tk}, nil)
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Empty(t, logs.All())
+	})
+
+	t.Run("logs_the_query_of_iterators_once", func(t *testing.T) {
+		mockController, mockDatastore, ds, logs := newDatastore(t, 10*time.Millisecond)
+		mockIterator := mocks.NewMockIterator[*openfgav1.Tuple](mockController)
+		mockDatastore.EXPECT().ReadUsersetTuples(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).Return(mockIterator, nil)
+		gomock.InOrder(
+			mockIterator.EXPECT().Next(gomock.Any()).Times(1).DoAndReturn(func(context.Context) (*openfgav1.Tuple, error) {
+				slow()
+				return &openfgav1.Tuple{Key: tk}, nil
+			}),
+			mockIterator.EXPECT().Next(gomock.Any()).Times(1).DoAndReturn(func(context.Context) (*openfgav1.Tuple, error) {
+				slow()
+				return nil, storage.ErrIteratorDone
+			}),
+			mockIterator.EXPECT().Stop().Times(1),
+		)
+
+		iter, err := ds.ReadUsersetTuples(ctx, storeID, storage.ReadUsersetTuplesFilter{
+			Object:   "document:1",
+			Relation: "viewer",
+			AllowedUserTypeRestrictions: []*openfgav1.RelationReference{
+				typesystem.DirectRelationReference("group", "member"),
+			},
+		}, storage.ReadUsersetTuplesOptions{})
+		require.NoError(t, err)
+		defer iter.Stop()
+
+		_, err = iter.Next(ctx)
+		require.NoError(t, err)
+		_, err = iter.Next(ctx)
+		require.ErrorIs(t, err, storage.ErrIteratorDone)
+
+		entries := logs.All()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		require.Equal(t, "ReadUsersetTuples", fields["operation"])
+		require.Equal(t, []interface{}{"group#member"}, fields["user_types"])
+	})
+
+	t.Run("logs_slow_writes_with_their_size", func(t *testing.T) {
+		_, mockDatastore, ds, logs := newDatastore(t, 10*time.Millisecond)
+		mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(context.Context, string, storage.Deletes, storage.Writes) error {
+				slow()
+				return nil
+			})
+
+		require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk}))
+
+		entries := logs.All()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		require.Equal(t, "Write", fields["operation"])
+		require.EqualValues(t, 0, fields["deletes"])
+		require.EqualValues(t, 1, fields["writes"])
+	})
+}
@@ -0,0 +1,220 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+)
+
+var (
+	_ storage.RelationshipTupleReader = (*StatementTimeoutTupleReader)(nil)
+	_ storage.RelationshipTupleWriter = (*StatementTimeoutTupleWriter)(nil)
+	_ storage.TupleIterator           = (*statementTimeoutIterator)(nil)
+
+	datastoreDeadlineExceededCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_deadline_exceeded_count",
+		Help:      "The total number of datastore calls which exceeded their statement timeout, partitioned by operation.",
+	}, []string{"operation"})
+)
+
+// errStatementTimeout is the cause of the cancellation of the contexts of the calls exceeding their statement timeout.
+var errStatementTimeout = errors.New("statement timeout exceeded")
+
+// withStatementTimeout calls fn with a context expiring after timeout, unless timeout is 0. If the call fails once
+// that context expired while ctx did not, the error wraps [storage.ErrDatastoreDeadlineExceeded].
+func withStatementTimeout[T any](ctx context.Context, timeout time.Duration, op string, fn func(context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	timeoutCtx, cancel := context.WithTimeoutCause(ctx, timeout, errStatementTimeout)
+	defer cancel()
+
+	res, err := fn(timeoutCtx)
+	return res, statementTimeoutError(ctx, timeoutCtx, op, err)
+}
+
+// statementTimeoutError returns err, wrapping [storage.ErrDatastoreDeadlineExceeded] if it was caused by the
+// statement timeout of timeoutCtx rather than by the end of its parent ctx.
+func statementTimeoutError(ctx, timeoutCtx context.Context, op string, err error) error {
+	if err == nil || errors.Is(err, storage.ErrIteratorDone) || ctx.Err() != nil || !errors.Is(context.Cause(timeoutCtx), errStatementTimeout) {
+		return err
+	}
+	datastoreDeadlineExceededCounter.WithLabelValues(op).Inc()
+	return fmt.Errorf("%w: %s: %w", storage.ErrDatastoreDeadlineExceeded, op, err)
+}
+
+// statementTimeoutIterator bounds every call to Next and Head of an iterator to the statement timeout. As the
+// query of an iterator is executed on its first call, and its rows are then read until Stop with the context of that
+// call, the context is only cancelled if a call exceeds the timeout, rather than once the timeout elapsed since the
+// first call, so that the rows of a query can be consumed for longer than the timeout.
+type statementTimeoutIterator struct {
+	storage.TupleIterator
+	timeout time.Duration
+	op      string
+
+	mu       sync.Mutex
+	ctx      context.Context         // GUARDED_BY(mu).
+	queryCtx context.Context         // GUARDED_BY(mu).
+	cancel   context.CancelCauseFunc // GUARDED_BY(mu).
+}
+
+func newStatementTimeoutIterator(iter storage.TupleIterator, timeout time.Duration, op string) *statementTimeoutIterator {
+	return &statementTimeoutIterator{
+		TupleIterator: iter,
+		timeout:       timeout,
+		op:            op,
+	}
+}
+
+// call calls fn with the context of the query of the iterator, cancelling it if fn does not return within the
+// statement timeout.
+func (s *statementTimeoutIterator) call(ctx context.Context, fn func(context.Context) (*openfgav1.Tuple, error)) (*openfgav1.Tuple, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	if s.queryCtx == nil {
+		s.ctx = ctx
+		s.queryCtx, s.cancel = context.WithCancelCause(ctx)
+	}
+	parentCtx, queryCtx, cancel := s.ctx, s.queryCtx, s.cancel
+	s.mu.Unlock()
+
+	timer := time.AfterFunc(s.timeout, func() { cancel(errStatementTimeout) })
+	t, err := fn(queryCtx)
+	timer.Stop()
+
+	return t, statementTimeoutError(parentCtx, queryCtx, s.op, err)
+}
+
+// Next see [storage.Iterator].Next.
+func (s *statementTimeoutIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
+	return s.call(ctx, s.TupleIterator.Next)
+}
+
+// Head see [storage.Iterator].Head.
+func (s *statementTimeoutIterator) Head(ctx context.Context) (*openfgav1.Tuple, error) {
+	return s.call(ctx, s.TupleIterator.Head)
+}
+
+// Stop see [storage.Iterator].Stop.
+func (s *statementTimeoutIterator) Stop() {
+	s.TupleIterator.Stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel(nil)
+	}
+}
+
+// StatementTimeoutTupleReader is a wrapper over a datastore bounding the duration of its tuple reads, so that a
+// runaway query does not hold a connection for longer than the timeout.
+type StatementTimeoutTupleReader struct {
+	storage.RelationshipTupleReader
+	timeout time.Duration
+}
+
+// NewStatementTimeoutTupleReader returns a wrapper over a datastore cancelling the queries of its tuple reads after
+// timeout. Every call to the Next or Head of the iterators returned by reads is bound to the timeout, their queries
+// being executed by their first call. Reads cancelled this way fail with [storage.ErrDatastoreDeadlineExceeded]. 0
+// disables the timeout.
+func NewStatementTimeoutTupleReader(wrapped storage.RelationshipTupleReader, timeout time.Duration) *StatementTimeoutTupleReader {
+	return &StatementTimeoutTupleReader{
+		RelationshipTupleReader: wrapped,
+		timeout:                 timeout,
+	}
+}
+
+func (s *StatementTimeoutTupleReader) iterator(iter storage.TupleIterator, err error, op string) (storage.TupleIterator, error) {
+	if err != nil || s.timeout <= 0 {
+		return iter, err
+	}
+	return newStatementTimeoutIterator(iter, s.timeout, op), nil
+}
+
+// Read see [storage.RelationshipTupleReader].Read.
+func (s *StatementTimeoutTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	iter, err := s.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	return s.iterator(iter, err, storagewrappersutil.OperationRead)
+}
+
+// ReadPage see [storage.RelationshipTupleReader].ReadPage.
+func (s *StatementTimeoutTupleReader) ReadPage(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	var continuationToken string
+	tuples, err := withStatementTimeout(ctx, s.timeout, storagewrappersutil.OperationReadPage, func(ctx context.Context) ([]*openfgav1.Tuple, error) {
+		tuples, token, err := s.RelationshipTupleReader.ReadPage(ctx, store, tupleKey, options)
+		continuationToken = token
+		return tuples, err
+	})
+	return tuples, continuationToken, err
+}
+
+// ReadUserTuple see [storage.RelationshipTupleReader].ReadUserTuple.
+func (s *StatementTimeoutTupleReader) ReadUserTuple(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+	return withStatementTimeout(ctx, s.timeout, storagewrappersutil.OperationReadUserTuple, func(ctx context.Context) (*openfgav1.Tuple, error) {
+		return s.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	})
+}
+
+// ReadUserTuples see [storage.RelationshipTupleReader].ReadUserTuples.
+func (s *StatementTimeoutTupleReader) ReadUserTuples(ctx context.Context, store string, tupleKeys []*openfgav1.TupleKey, options storage.ReadUserTupleOptions) ([]*openfgav1.Tuple, error) {
+	return withStatementTimeout(ctx, s.timeout, storagewrappersutil.OperationReadUserTuples, func(ctx context.Context) ([]*openfgav1.Tuple, error) {
+		return s.RelationshipTupleReader.ReadUserTuples(ctx, store, tupleKeys, options)
+	})
+}
+
+// ReadUsersetTuples see [storage.RelationshipTupleReader].ReadUsersetTuples.
+func (s *StatementTimeoutTupleReader) ReadUsersetTuples(ctx context.Context, store string, filter storage.ReadUsersetTuplesFilter, options storage.ReadUsersetTuplesOptions) (storage.TupleIterator, error) {
+	iter, err := s.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	return s.iterator(iter, err, storagewrappersutil.OperationReadUsersetTuples)
+}
+
+// ReadStartingWithUser see [storage.RelationshipTupleReader].ReadStartingWithUser.
+func (s *StatementTimeoutTupleReader) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	iter, err := s.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	return s.iterator(iter, err, storagewrappersutil.OperationReadStartingWithUser)
+}
+
+// StatementTimeoutTupleWriter is a wrapper over a datastore bounding the duration of its tuple writes.
+type StatementTimeoutTupleWriter struct {
+	storage.RelationshipTupleWriter
+	timeout time.Duration
+}
+
+// NewStatementTimeoutTupleWriter returns a wrapper over a datastore cancelling its tuple writes after timeout, which
+// then fail with [storage.ErrDatastoreDeadlineExceeded]. 0 disables the timeout.
+func NewStatementTimeoutTupleWriter(wrapped storage.RelationshipTupleWriter, timeout time.Duration) *StatementTimeoutTupleWriter {
+	return &StatementTimeoutTupleWriter{
+		RelationshipTupleWriter: wrapped,
+		timeout:                 timeout,
+	}
+}
+
+// Write see [storage.RelationshipTupleWriter].Write.
+func (s *StatementTimeoutTupleWriter) Write(ctx context.Context, store string, d storage.Deletes, w storage.Writes) error {
+	_, err := withStatementTimeout(ctx, s.timeout, storagewrappersutil.OperationWrite, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, s.RelationshipTupleWriter.Write(ctx, store, d, w)
+	})
+	return err
+}
+
+// NewStatementTimeoutDatastore returns a wrapper over a datastore cancelling its tuple reads after readTimeout and
+// its tuple writes after writeTimeout. 0 disables a timeout.
+func NewStatementTimeoutDatastore(inner storage.OpenFGADatastore, readTimeout, writeTimeout time.Duration) storage.OpenFGADatastore {
+	return newWrappedDatastore(inner, NewStatementTimeoutTupleReader(inner, readTimeout), NewStatementTimeoutTupleWriter(inner, writeTimeout))
+}
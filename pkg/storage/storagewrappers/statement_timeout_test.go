@@ -0,0 +1,143 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestStatementTimeoutDatastore(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	tk := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+
+	untilDone := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("reads_exceeding_the_timeout_fail_with_datastore_deadline_exceeded", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewStatementTimeoutDatastore(mockDatastore, 10*time.Millisecond, 0)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).
+			DoAndReturn(func(ctx context.Context, _ string, _ *openfgav1.TupleKey, _ storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+				return nil, untilDone(ctx)
+			})
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrDatastoreDeadlineExceeded)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("the_request_deadline_is_not_a_datastore_deadline", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewStatementTimeoutDatastore(mockDatastore, time.Minute, 0)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).
+			DoAndReturn(func(ctx context.Context, _ string, _ *openfgav1.TupleKey, _ storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+				return nil, untilDone(ctx)
+			})
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotErrorIs(t, err, storage.ErrDatastoreDeadlineExceeded)
+	})
+
+	t.Run("writes_use_the_write_timeout", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewStatementTimeoutDatastore(mockDatastore, time.Minute, 10*time.Millisecond)
+		mockDatastore.EXPECT().Write(gomock.Any(), storeID, gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(ctx context.Context, _ string, _ storage.Deletes, _ storage.Writes) error {
+				return untilDone(ctx)
+			})
+
+		err := ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tk})
+		require.ErrorIs(t, err, storage.ErrDatastoreDeadlineExceeded)
+	})
+
+	t.Run("every_iterator_call_is_bound_to_the_timeout", func(t *testing.T) {
+		mockController, mockDatastore := newMockDatastore(t)
+		ds := NewStatementTimeoutDatastore(mockDatastore, 20*time.Millisecond, 0)
+		mockIterator := mocks.NewMockIterator[*openfgav1.Tuple](mockController)
+		mockDatastore.EXPECT().Read(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(mockIterator, nil)
+
+		var queryCtx context.Context
+		gomock.InOrder(
+			mockIterator.EXPECT().Next(gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context) (*openfgav1.Tuple, error) {
+				queryCtx = ctx
+				return &openfgav1.Tuple{Key: tk}, nil
+			}),
+			mockIterator.EXPECT().Next(gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context) (*openfgav1.Tuple, error) {
+				// the rows of the query are read with the context of the first call
+				require.Equal(t, queryCtx, ctx)
+				return &openfgav1.Tuple{Key: tk}, nil
+			}),
+			mockIterator.EXPECT().Next(gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context) (*openfgav1.Tuple, error) {
+				return nil, untilDone(ctx)
+			}),
+			mockIterator.EXPECT().Stop().Times(1),
+		)
+
+		iter, err := ds.Read(ctx, storeID, tk, storage.ReadOptions{})
+		require.NoError(t, err)
+		defer iter.Stop()
+
+		_, err = iter.Next(ctx)
+		require.NoError(t, err)
+
+		// the rows may be consumed for longer than the timeout
+		time.Sleep(40 * time.Millisecond)
+		require.NoError(t, queryCtx.Err())
+		_, err = iter.Next(ctx)
+		require.NoError(t, err)
+
+		_, err = iter.Next(ctx)
+		require.ErrorIs(t, err, storage.ErrDatastoreDeadlineExceeded)
+	})
+
+	t.Run("iterator_calls_of_a_done_request_are_not_datastore_deadlines", func(t *testing.T) {
+		mockController, mockDatastore := newMockDatastore(t)
+		ds := NewStatementTimeoutDatastore(mockDatastore, time.Minute, 0)
+		mockIterator := mocks.NewMockIterator[*openfgav1.Tuple](mockController)
+		mockDatastore.EXPECT().Read(gomock.Any(), storeID, tk, gomock.Any()).Times(1).Return(mockIterator, nil)
+		mockIterator.EXPECT().Next(gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context) (*openfgav1.Tuple, error) {
+			return nil, untilDone(ctx)
+		})
+		mockIterator.EXPECT().Stop().Times(1)
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		iter, err := ds.Read(ctx, storeID, tk, storage.ReadOptions{})
+		require.NoError(t, err)
+		defer iter.Stop()
+
+		_, err = iter.Next(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotErrorIs(t, err, storage.ErrDatastoreDeadlineExceeded)
+	})
+
+	t.Run("zero_disables_the_timeout", func(t *testing.T) {
+		_, mockDatastore := newMockDatastore(t)
+		ds := NewStatementTimeoutDatastore(mockDatastore, 0, 0)
+		mockDatastore.EXPECT().ReadUserTuple(gomock.Any(), storeID, tk, gomock.Any()).Times(1).
+			DoAndReturn(func(ctx context.Context, _ string, _ *openfgav1.TupleKey, _ storage.ReadUserTupleOptions) (*openfgav1.Tuple, error) {
+				_, ok := ctx.Deadline()
+				require.False(t, ok)
+				return nil, storage.ErrNotFound
+			})
+
+		_, err := ds.ReadUserTuple(ctx, storeID, tk, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -14,9 +14,12 @@ import (
 
 const (
 	OperationRead                 = "Read"
+	OperationReadPage             = "ReadPage"
 	OperationReadStartingWithUser = "ReadStartingWithUser"
 	OperationReadUsersetTuples    = "ReadUsersetTuples"
 	OperationReadUserTuple        = "ReadUserTuple"
+	OperationReadUserTuples       = "ReadUserTuples"
+	OperationWrite                = "Write"
 )
 
 func ReadStartingWithUserKey(
@@ -87,3 +90,7 @@ func ReadKey(store string, tupleKey *openfgav1.TupleKey) string {
 	)
 	return b.String()
 }
+
+func ReadUserTupleKey(store string, tupleKey *openfgav1.TupleKey) string {
+	return storage.GetReadUserTupleCacheKey(store, tuple.TupleKeyToString(tupleKey))
+}
@@ -287,3 +287,60 @@ func FindLatestAuthorizationModelTest(t *testing.T, datastore storage.OpenFGADat
 		}
 	})
 }
+
+func DeleteAuthorizationModelTest(t *testing.T, datastore storage.OpenFGADatastore) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	writeModel := func() *openfgav1.AuthorizationModel {
+		model := &openfgav1.AuthorizationModel{
+			Id:              ulid.Make().String(),
+			SchemaVersion:   typesystem.SchemaVersion1_1,
+			TypeDefinitions: []*openfgav1.TypeDefinition{{Type: "folder"}},
+		}
+		err := datastore.WriteAuthorizationModel(ctx, storeID, model)
+		require.NoError(t, err)
+		return model
+	}
+
+	older := writeModel()
+	latest := writeModel()
+
+	t.Run("delete_model_deletes_it_and_its_assertions", func(t *testing.T) {
+		err := datastore.WriteAssertions(ctx, storeID, older.GetId(), []*openfgav1.Assertion{{
+			TupleKey:    &openfgav1.AssertionTupleKey{Object: "folder:1", Relation: "viewer", User: "user:anne"},
+			Expectation: true,
+		}})
+		require.NoError(t, err)
+
+		err = datastore.DeleteAuthorizationModel(ctx, storeID, older.GetId())
+		require.NoError(t, err)
+
+		_, err = datastore.ReadAuthorizationModel(ctx, storeID, older.GetId())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		assertions, err := datastore.ReadAssertions(ctx, storeID, older.GetId())
+		require.NoError(t, err)
+		require.Empty(t, assertions)
+
+		got, err := datastore.FindLatestAuthorizationModel(ctx, storeID)
+		require.NoError(t, err)
+		require.Equal(t, latest.GetId(), got.GetId())
+	})
+
+	t.Run("delete_non-existent_model_returns_not_found", func(t *testing.T) {
+		err := datastore.DeleteAuthorizationModel(ctx, storeID, older.GetId())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("delete_latest_model_makes_the_previous_one_the_latest", func(t *testing.T) {
+		newest := writeModel()
+
+		err := datastore.DeleteAuthorizationModel(ctx, storeID, newest.GetId())
+		require.NoError(t, err)
+
+		got, err := datastore.FindLatestAuthorizationModel(ctx, storeID)
+		require.NoError(t, err)
+		require.Equal(t, latest.GetId(), got.GetId())
+	})
+}
@@ -0,0 +1,13 @@
+// Package test contains the conformance tests every [storage.OpenFGADatastore] implementation must pass, including
+// the ones implemented outside of this module and registered with the [engine] package. Run them with RunAllTests
+// against an empty datastore:
+//
+//	func TestDatastore(t *testing.T) {
+//		ds := mydatastore.New(...)
+//		defer ds.Close()
+//		test.RunAllTests(t, ds)
+//	}
+//
+// [storage.OpenFGADatastore]: https://pkg.go.dev/github.com/openfga/openfga/pkg/storage#OpenFGADatastore
+// [engine]: https://pkg.go.dev/github.com/openfga/openfga/pkg/storage/engine
+package test
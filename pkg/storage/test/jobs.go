@@ -0,0 +1,142 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+func JobsTest(t *testing.T, datastore storage.OpenFGADatastore) {
+	ctx := context.Background()
+
+	t.Run("creating_reading_and_updating_a_job_succeeds", func(t *testing.T) {
+		job := &storage.Job{
+			ID:      ulid.Make().String(),
+			StoreID: ulid.Make().String(),
+			Type:    "import_tuples",
+			Params:  []byte("params"),
+			Status:  storage.JobStatusPending,
+		}
+		err := datastore.CreateJob(ctx, job)
+		require.NoError(t, err)
+		require.False(t, job.CreatedAt.IsZero())
+
+		got, err := datastore.ReadJob(ctx, job.StoreID, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, job.Type, got.Type)
+		require.Equal(t, job.Params, got.Params)
+		require.Equal(t, storage.JobStatusPending, got.Status)
+		require.Empty(t, got.Error)
+		require.False(t, got.CancelRequested)
+
+		job.Status = storage.JobStatusFailed
+		job.Progress = 42
+		job.Result = []byte("result")
+		job.Error = "boom"
+		err = datastore.UpdateJob(ctx, job)
+		require.NoError(t, err)
+
+		got, err = datastore.ReadJob(ctx, job.StoreID, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, storage.JobStatusFailed, got.Status)
+		require.Equal(t, int64(42), got.Progress)
+		require.Equal(t, []byte("result"), got.Result)
+		require.Equal(t, "boom", got.Error)
+
+		// Updating a job doesn't change it if nothing changed.
+		err = datastore.UpdateJob(ctx, job)
+		require.NoError(t, err)
+	})
+
+	t.Run("requesting_the_cancellation_of_a_job_succeeds", func(t *testing.T) {
+		job := &storage.Job{
+			ID:      ulid.Make().String(),
+			StoreID: ulid.Make().String(),
+			Type:    "export_tuples",
+			Status:  storage.JobStatusRunning,
+		}
+		require.NoError(t, datastore.CreateJob(ctx, job))
+
+		err := datastore.RequestJobCancellation(ctx, job.StoreID, job.ID)
+		require.NoError(t, err)
+
+		got, err := datastore.ReadJob(ctx, job.StoreID, job.ID)
+		require.NoError(t, err)
+		require.True(t, got.CancelRequested)
+		require.Equal(t, storage.JobStatusRunning, got.Status)
+	})
+
+	t.Run("interrupted_jobs_are_claimed_once", func(t *testing.T) {
+		interrupted := &storage.Job{
+			ID:      ulid.Make().String(),
+			StoreID: ulid.Make().String(),
+			Type:    "import_tuples",
+			Status:  storage.JobStatusPending,
+		}
+		require.NoError(t, datastore.CreateJob(ctx, interrupted))
+		interrupted.Status = storage.JobStatusInterrupted
+		interrupted.Progress = 7
+		require.NoError(t, datastore.UpdateJob(ctx, interrupted))
+
+		running := &storage.Job{
+			ID:      ulid.Make().String(),
+			StoreID: interrupted.StoreID,
+			Type:    "import_tuples",
+			Status:  storage.JobStatusRunning,
+		}
+		require.NoError(t, datastore.CreateJob(ctx, running))
+
+		claimed, err := datastore.ClaimInterruptedJobs(ctx)
+		require.NoError(t, err)
+		claimedIDs := make([]string, 0, len(claimed))
+		for _, job := range claimed {
+			claimedIDs = append(claimedIDs, job.ID)
+			if job.ID == interrupted.ID {
+				require.Equal(t, storage.JobStatusPending, job.Status)
+				require.Equal(t, int64(7), job.Progress)
+			}
+		}
+		require.Contains(t, claimedIDs, interrupted.ID)
+		require.NotContains(t, claimedIDs, running.ID)
+
+		got, err := datastore.ReadJob(ctx, interrupted.StoreID, interrupted.ID)
+		require.NoError(t, err)
+		require.Equal(t, storage.JobStatusPending, got.Status)
+
+		claimed, err = datastore.ClaimInterruptedJobs(ctx)
+		require.NoError(t, err)
+		for _, job := range claimed {
+			require.NotEqual(t, interrupted.ID, job.ID)
+		}
+	})
+
+	t.Run("jobs_are_scoped_to_their_store", func(t *testing.T) {
+		job := &storage.Job{
+			ID:      ulid.Make().String(),
+			StoreID: ulid.Make().String(),
+			Type:    "delete_tuples",
+			Status:  storage.JobStatusPending,
+		}
+		require.NoError(t, datastore.CreateJob(ctx, job))
+
+		_, err := datastore.ReadJob(ctx, ulid.Make().String(), job.ID)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("non-existent_jobs_are_not_found", func(t *testing.T) {
+		store, id := ulid.Make().String(), ulid.Make().String()
+
+		_, err := datastore.ReadJob(ctx, store, id)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		err = datastore.UpdateJob(ctx, &storage.Job{ID: id, StoreID: store, Status: storage.JobStatusRunning})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		err = datastore.RequestJobCancellation(ctx, store, id)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -43,12 +43,16 @@ func RunAllTests(t *testing.T, ds storage.OpenFGADatastore) {
 	t.Run("TestWriteAndReadAuthorizationModel", func(t *testing.T) { WriteAndReadAuthorizationModelTest(t, ds) })
 	t.Run("TestReadAuthorizationModels", func(t *testing.T) { ReadAuthorizationModelsTest(t, ds) })
 	t.Run("TestFindLatestAuthorizationModel", func(t *testing.T) { FindLatestAuthorizationModelTest(t, ds) })
+	t.Run("TestDeleteAuthorizationModel", func(t *testing.T) { DeleteAuthorizationModelTest(t, ds) })
 
 	// Assertions.
 	t.Run("TestWriteAndReadAssertions", func(t *testing.T) { AssertionsTest(t, ds) })
 
 	// Stores.
 	t.Run("TestStore", func(t *testing.T) { StoreTest(t, ds) })
+
+	// Jobs.
+	t.Run("TestJobs", func(t *testing.T) { JobsTest(t, ds) })
 }
 
 // BootstrapFGAStore is a utility to write an FGA model and relationship tuples to a datastore.
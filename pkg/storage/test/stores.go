@@ -169,6 +169,79 @@ func StoreTest(t *testing.T, datastore storage.OpenFGADatastore) {
 		verifyStore(t, expected2, gotStores[1])
 	})
 
+	t.Run("store_labels_can_be_written_read_and_filtered_on", func(t *testing.T) {
+		labelled := stores[3]
+
+		labels, err := datastore.ReadStoreLabels(ctx, labelled.GetId())
+		require.NoError(t, err)
+		require.Empty(t, labels)
+
+		err = datastore.WriteStoreLabels(ctx, labelled.GetId(), map[string]string{"env": "prod", "team": "iam"})
+		require.NoError(t, err)
+		err = datastore.WriteStoreLabels(ctx, stores[4].GetId(), map[string]string{"env": "prod"})
+		require.NoError(t, err)
+
+		labels, err = datastore.ReadStoreLabels(ctx, labelled.GetId())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod", "team": "iam"}, labels)
+
+		gotStores, _, err := datastore.ListStores(ctx, storage.ListStoresOptions{
+			Pagination: storage.NewPaginationOptions(10, ""),
+			Labels:     map[string]string{"env": "prod", "team": "iam"},
+		})
+		require.NoError(t, err)
+		require.Len(t, gotStores, 1)
+		verifyStore(t, labelled, gotStores[0])
+
+		gotStores, _, err = datastore.ListStores(ctx, storage.ListStoresOptions{
+			Pagination: storage.NewPaginationOptions(10, ""),
+			Labels:     map[string]string{"env": "prod"},
+		})
+		require.NoError(t, err)
+		require.Len(t, gotStores, 2)
+
+		// Writing the labels replaces them.
+		err = datastore.WriteStoreLabels(ctx, labelled.GetId(), map[string]string{"env": "dev"})
+		require.NoError(t, err)
+		labels, err = datastore.ReadStoreLabels(ctx, labelled.GetId())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "dev"}, labels)
+
+		err = datastore.WriteStoreLabels(ctx, labelled.GetId(), nil)
+		require.NoError(t, err)
+		labels, err = datastore.ReadStoreLabels(ctx, labelled.GetId())
+		require.NoError(t, err)
+		require.Empty(t, labels)
+	})
+
+	t.Run("write_store_labels_of_non-existent_store_returns_not_found", func(t *testing.T) {
+		err := datastore.WriteStoreLabels(ctx, ulid.Make().String(), map[string]string{"env": "prod"})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("store_labels_are_created_and_deleted_with_the_store", func(t *testing.T) {
+		store := &openfgav1.Store{Id: ulid.Make().String(), Name: "labelled"}
+		_, err := datastore.CreateStoreWithLabels(ctx, store, map[string]string{"env": "prod", "team": "iam"})
+		require.NoError(t, err)
+
+		labels, err := datastore.ReadStoreLabels(ctx, store.GetId())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod", "team": "iam"}, labels)
+
+		// A store colliding with an existing one does not write its labels.
+		_, err = datastore.CreateStoreWithLabels(ctx, store, map[string]string{"env": "dev"})
+		require.ErrorIs(t, err, storage.ErrCollision)
+		labels, err = datastore.ReadStoreLabels(ctx, store.GetId())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod", "team": "iam"}, labels)
+
+		err = datastore.DeleteStore(ctx, store.GetId())
+		require.NoError(t, err)
+		labels, err = datastore.ReadStoreLabels(ctx, store.GetId())
+		require.NoError(t, err)
+		require.Empty(t, labels)
+	})
+
 	t.Run("get_store_succeeds", func(t *testing.T) {
 		store := stores[0]
 		gotStore, err := datastore.GetStore(ctx, store.GetId())
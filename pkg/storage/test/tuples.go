@@ -308,6 +308,71 @@ func ReadChangesTest(t *testing.T, datastore storage.OpenFGADatastore) {
 		}
 	})
 
+	t.Run("read_changes_with_object_type_pages_through_interleaved_changes_of_that_type", func(t *testing.T) {
+		storeID := ulid.Make().String()
+
+		folder1 := tuple.NewTupleKey("folder:1", "viewer", "user:bob")
+		folder2 := tuple.NewTupleKey("folder:2", "viewer", "user:bob")
+		var expectedChanges []*openfgav1.TupleChange
+		for i, tk := range []*openfgav1.TupleKey{folder1, folder2} {
+			err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+				tk,
+				tuple.NewTupleKey(fmt.Sprintf("document:%d", i), "viewer", "user:bob"),
+			})
+			require.NoError(t, err)
+			expectedChanges = append(expectedChanges, &openfgav1.TupleChange{
+				TupleKey:  tk,
+				Operation: openfgav1.TupleOperation_TUPLE_OPERATION_WRITE,
+			})
+		}
+		err := datastore.Write(ctx, storeID, []*openfgav1.TupleKeyWithoutCondition{
+			tuple.TupleKeyToTupleKeyWithoutCondition(folder1),
+		}, nil)
+		require.NoError(t, err)
+		expectedChanges = append(expectedChanges, &openfgav1.TupleChange{
+			TupleKey:  folder1,
+			Operation: openfgav1.TupleOperation_TUPLE_OPERATION_DELETE,
+		})
+
+		changes := readChangesWithPageSize(t, datastore, storeID, 1, "folder")
+		if diff := cmp.Diff(expectedChanges, changes, cmpIgnoreTimestamp...); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("read_changes_with_object_id_and_user_should_only_read_those_changes", func(t *testing.T) {
+		storeID := ulid.Make().String()
+
+		err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("folder:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("folder:1", "viewer", "group:eng#member"),
+			tuple.NewTupleKey("folder:2", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		})
+		require.NoError(t, err)
+
+		opts := storage.ReadChangesOptions{Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, "")}
+
+		changes, _, err := datastore.ReadChanges(ctx, storeID, storage.ReadChangesFilter{ObjectType: "folder", ObjectID: "1"}, opts)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		for _, change := range changes {
+			require.Equal(t, "folder:1", change.GetTupleKey().GetObject())
+		}
+
+		changes, _, err = datastore.ReadChanges(ctx, storeID, storage.ReadChangesFilter{ObjectType: "folder", User: "group:eng#member"}, opts)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "folder:1#viewer@group:eng#member", tuple.TupleKeyToString(changes[0].GetTupleKey()))
+
+		changes, _, err = datastore.ReadChanges(ctx, storeID, storage.ReadChangesFilter{User: "user:anne"}, opts)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+
+		_, _, err = datastore.ReadChanges(ctx, storeID, storage.ReadChangesFilter{ObjectType: "folder", ObjectID: "3"}, opts)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
 	t.Run("read_changes_returns_deterministic_ordering_and_no_duplicates", func(t *testing.T) {
 		storeID := ulid.Make().String()
 
@@ -879,6 +944,39 @@ func TupleWritingAndReadingTest(t *testing.T, datastore storage.OpenFGADatastore
 		require.ErrorIs(t, err, storage.ErrNotFound)
 	})
 
+	t.Run("reading_user_tuples_in_batch_returns_only_existing_tuples", func(t *testing.T) {
+		storeID := ulid.Make().String()
+		tuple1 := tuple.NewTupleKey("doc:readme", "owner", "user:jon")
+		tuple2 := tuple.NewTupleKey("doc:readme", "viewer", "doc:other#viewer")
+		tuple3 := tuple.NewTupleKeyWithCondition("doc:readme", "viewer", "user:anne", "condition", nil)
+		missing := tuple.NewTupleKey("doc:readme", "owner", "user:maria")
+
+		err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tuple1, tuple2, tuple3})
+		require.NoError(t, err)
+
+		gotTuples, err := datastore.ReadUserTuples(ctx, storeID, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("doc:readme", "owner", "user:jon"),
+			tuple.NewTupleKey("doc:readme", "viewer", "doc:other#viewer"),
+			tuple.NewTupleKey("doc:readme", "viewer", "user:anne"),
+			missing,
+		}, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+
+		gotKeys := make([]*openfgav1.TupleKey, 0, len(gotTuples))
+		for _, gotTuple := range gotTuples {
+			gotKeys = append(gotKeys, gotTuple.GetKey())
+		}
+
+		expected := []*openfgav1.TupleKey{tuple1, tuple2, tuple3}
+		if diff := cmp.Diff(expected, gotKeys, cmpSortTupleKeys...); diff != "" {
+			require.FailNowf(t, "mismatch (-want +got):\n%s", diff)
+		}
+
+		gotTuples, err = datastore.ReadUserTuples(ctx, storeID, []*openfgav1.TupleKey{missing}, storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Empty(t, gotTuples)
+	})
+
 	t.Run("reading_userset_tuples_that_exists_succeeds", func(t *testing.T) {
 		storeID := ulid.Make().String()
 		tks := []*openfgav1.TupleKey{
@@ -1272,6 +1370,51 @@ func TupleWritingAndReadingTest(t *testing.T, datastore storage.OpenFGADatastore
 		require.Nil(t, changes[1].GetTupleKey().GetCondition())
 	})
 
+	t.Run("read_page_filtered_by_relations_and_user_type", func(t *testing.T) {
+		storeID := ulid.Make().String()
+
+		err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "owner", "user:anne"),
+			tuple.NewTupleKey("document:1", "viewer", "group:eng#member"),
+			tuple.NewTupleKey("document:1", "viewer", "user:maria"),
+			tuple.NewTupleKey("document:1", "viewer", "user:*"),
+			tuple.NewTupleKey("document:1", "viewer", "users:bob"),
+			tuple.NewTupleKey("document:1", "editor", "user:jon"),
+			tuple.NewTupleKey("document:2", "viewer", "user:maria"),
+		})
+		require.NoError(t, err)
+
+		var got []string
+		opts := storage.ReadPageOptions{
+			Pagination: storage.NewPaginationOptions(1, ""),
+			Relations:  []string{"viewer", "editor"},
+			UserType:   "user",
+		}
+		for {
+			tuples, contToken, err := datastore.ReadPage(ctx, storeID, tuple.NewTupleKey("document:1", "", ""), opts)
+			require.NoError(t, err)
+			require.Len(t, tuples, 1)
+			got = append(got, tuple.TupleKeyToString(tuples[0].GetKey()))
+			if contToken == "" {
+				break
+			}
+			opts.Pagination.From = contToken
+		}
+		require.ElementsMatch(t, []string{
+			"document:1#viewer@user:maria",
+			"document:1#viewer@user:*",
+			"document:1#editor@user:jon",
+		}, got)
+
+		tuples, _, err := datastore.ReadPage(ctx, storeID, &openfgav1.TupleKey{}, storage.ReadPageOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, ""),
+			UserType:   "group",
+		})
+		require.NoError(t, err)
+		require.Len(t, tuples, 1)
+		require.Equal(t, "document:1#viewer@group:eng#member", tuple.TupleKeyToString(tuples[0].GetKey()))
+	})
+
 	t.Run("normalize_empty_context", func(t *testing.T) {
 		// This test ensures we normalize nil or empty context as empty context in all reads.
 		storeID := ulid.Make().String()
@@ -0,0 +1,23 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceIDExemplarLabel is the exemplar label of the trace id, the one expected by Grafana to link to the trace.
+const traceIDExemplarLabel = "trace_id"
+
+// ObserveWithTraceExemplar observes the value with the observer and, if the trace of the context is sampled,
+// attaches its id as an exemplar, so that an outlier of the histogram can be followed to the trace that produced it.
+// The exemplars are only exposed in the OpenMetrics format.
+func ObserveWithTraceExemplar(ctx context.Context, observer prometheus.Observer, value float64) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && spanCtx.IsSampled() {
+		exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{traceIDExemplarLabel: spanCtx.TraceID().String()})
+		return
+	}
+	observer.Observe(value)
+}
@@ -0,0 +1,58 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestObserveWithTraceExemplar(t *testing.T) {
+	traceID := trace.TraceID{0x01, 0x02, 0x03}
+
+	exemplars := func(t *testing.T, ctx context.Context) []*dto.Exemplar {
+		histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_duration_ms", Buckets: []float64{10}})
+		ObserveWithTraceExemplar(ctx, histogram, 5)
+
+		var m dto.Metric
+		require.NoError(t, histogram.Write(&m))
+		require.Equal(t, uint64(1), m.GetHistogram().GetSampleCount())
+
+		var res []*dto.Exemplar
+		for _, bucket := range m.GetHistogram().GetBucket() {
+			if bucket.GetExemplar() != nil {
+				res = append(res, bucket.GetExemplar())
+			}
+		}
+		return res
+	}
+
+	t.Run("sampled_trace", func(t *testing.T) {
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{0x01},
+			TraceFlags: trace.FlagsSampled,
+		}))
+
+		res := exemplars(t, ctx)
+		require.Len(t, res, 1)
+		require.Equal(t, traceIDExemplarLabel, res[0].GetLabel()[0].GetName())
+		require.Equal(t, traceID.String(), res[0].GetLabel()[0].GetValue())
+	})
+
+	t.Run("unsampled_trace", func(t *testing.T) {
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  trace.SpanID{0x01},
+		}))
+
+		require.Empty(t, exemplars(t, ctx))
+	})
+
+	t.Run("no_trace", func(t *testing.T) {
+		require.Empty(t, exemplars(t, context.Background()))
+	})
+}
@@ -0,0 +1,193 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	prometheusbridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+const (
+	// MetricsProtocolGRPC exports the metrics with the OTLP/gRPC protocol.
+	MetricsProtocolGRPC = "grpc"
+	// MetricsProtocolHTTP exports the metrics with the OTLP/HTTP protocol, in the binary protobuf encoding.
+	MetricsProtocolHTTP = "http"
+
+	defaultMetricsExportInterval = 30 * time.Second
+	metricsExportTimeout         = 10 * time.Second
+)
+
+type MetricsExporterOption func(e *MetricsExporter)
+
+// WithMetricsOTLPEndpoint sets the host:port of the OTLP receiver the metrics are exported to.
+func WithMetricsOTLPEndpoint(endpoint string) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.endpoint = endpoint
+	}
+}
+
+// WithMetricsOTLPInsecure exports the metrics without TLS.
+func WithMetricsOTLPInsecure() MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.insecure = true
+	}
+}
+
+// WithMetricsOTLPProtocol sets the protocol the metrics are exported with, [MetricsProtocolGRPC] by default.
+func WithMetricsOTLPProtocol(protocol string) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.protocol = protocol
+	}
+}
+
+// WithMetricsOTLPHeaders sets the headers sent with the exports, e.g. 'Authorization' to authenticate with the OTLP
+// receiver. If not set, the headers of the OTEL_EXPORTER_OTLP_METRICS_HEADERS or OTEL_EXPORTER_OTLP_HEADERS
+// environment variable are sent.
+func WithMetricsOTLPHeaders(headers map[string]string) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.headers = headers
+	}
+}
+
+// WithMetricsExportInterval sets how often the metrics are exported.
+func WithMetricsExportInterval(interval time.Duration) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.interval = interval
+	}
+}
+
+// WithMetricsAttributes sets the attributes of the resource the metrics are exported for, e.g. the service name.
+func WithMetricsAttributes(attrs ...attribute.KeyValue) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.attributes = attrs
+	}
+}
+
+// WithMetricsGatherer sets the gatherer of the exported metrics, [prometheus.DefaultGatherer] by default.
+func WithMetricsGatherer(gatherer prometheus.Gatherer) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.gatherer = gatherer
+	}
+}
+
+// WithMetricsLogger sets the logger of the export failures.
+func WithMetricsLogger(logger logger.Logger) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.logger = logger
+	}
+}
+
+// MetricsExporter periodically exports the metrics of a Prometheus registry to an OTLP receiver with the OpenTelemetry
+// metrics SDK, so that they can be ingested by an OpenTelemetry pipeline, in addition to or instead of being scraped.
+// The metrics are converted by the Prometheus bridge: counters are exported as cumulative monotonic sums starting at
+// their creation or else at the start of the process, gauges as gauges, histograms as cumulative explicit bucket
+// histograms and summaries as summaries.
+type MetricsExporter struct {
+	endpoint   string
+	insecure   bool
+	protocol   string
+	headers    map[string]string
+	interval   time.Duration
+	attributes []attribute.KeyValue
+	gatherer   prometheus.Gatherer
+	logger     logger.Logger
+
+	provider *sdkmetric.MeterProvider
+}
+
+// NewMetricsExporter returns a [MetricsExporter] exporting the metrics every interval until it is closed.
+func NewMetricsExporter(opts ...MetricsExporterOption) (*MetricsExporter, error) {
+	e := &MetricsExporter{
+		protocol: MetricsProtocolGRPC,
+		interval: defaultMetricsExportInterval,
+		gatherer: prometheus.DefaultGatherer,
+		logger:   logger.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	exporter, err := e.newExporter()
+	if err != nil {
+		return nil, err
+	}
+
+	reader := sdkmetric.NewPeriodicReader(loggingMetricsExporter{Exporter: exporter, logger: e.logger},
+		sdkmetric.WithInterval(e.interval),
+		sdkmetric.WithTimeout(metricsExportTimeout),
+		sdkmetric.WithProducer(prometheusbridge.NewMetricProducer(prometheusbridge.WithGatherer(e.gatherer))),
+	)
+	// the provider has no instruments, its reader only collects the metrics of the bridge
+	e.provider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(resource.NewSchemaless(e.attributes...)),
+	)
+
+	return e, nil
+}
+
+func (e *MetricsExporter) newExporter() (exporter sdkmetric.Exporter, err error) {
+	switch e.protocol {
+	case MetricsProtocolGRPC:
+		options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(e.endpoint)}
+		if e.insecure {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		}
+		if len(e.headers) > 0 {
+			options = append(options, otlpmetricgrpc.WithHeaders(e.headers))
+		}
+		exporter, err = otlpmetricgrpc.New(context.Background(), options...)
+	case MetricsProtocolHTTP:
+		options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(e.endpoint)}
+		if e.insecure {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		}
+		if len(e.headers) > 0 {
+			options = append(options, otlpmetrichttp.WithHeaders(e.headers))
+		}
+		exporter, err = otlpmetrichttp.New(context.Background(), options...)
+	default:
+		return nil, fmt.Errorf("unsupported otlp metrics protocol '%s'", e.protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the otlp metrics exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// Close stops the periodic exports, exports the metrics one last time, then closes the connection to the receiver.
+func (e *MetricsExporter) Close(ctx context.Context) error {
+	return e.provider.Shutdown(ctx)
+}
+
+// Export gathers the metrics and exports them. The export failures are logged.
+func (e *MetricsExporter) Export(ctx context.Context) error {
+	return e.provider.ForceFlush(ctx)
+}
+
+// loggingMetricsExporter logs the export failures, rather than reporting them to the global OpenTelemetry error
+// handler.
+type loggingMetricsExporter struct {
+	sdkmetric.Exporter
+	logger logger.Logger
+}
+
+func (e loggingMetricsExporter) Export(ctx context.Context, metrics *metricdata.ResourceMetrics) error {
+	if err := e.Exporter.Export(ctx, metrics); err != nil && !errors.Is(err, context.Canceled) {
+		e.logger.Warn("failed to export metrics", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMetricsExporter(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_count", Help: "A test counter."}, []string{"method"})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_duration_ms", Buckets: []float64{10, 100}})
+	registry.MustRegister(counter, histogram)
+
+	counter.WithLabelValues("Check").Add(3)
+	histogram.Observe(5)
+	histogram.Observe(50)
+	histogram.Observe(500)
+
+	requests := make(chan *colmetricspb.ExportMetricsServiceRequest, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/metrics" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		req := &colmetricspb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- req
+	}))
+	defer receiver.Close()
+
+	beforeExporter := time.Now()
+	exporter, err := NewMetricsExporter(
+		WithMetricsOTLPEndpoint(strings.TrimPrefix(receiver.URL, "http://")),
+		WithMetricsOTLPInsecure(),
+		WithMetricsOTLPProtocol(MetricsProtocolHTTP),
+		WithMetricsOTLPHeaders(map[string]string{"Authorization": "Bearer secret"}),
+		WithMetricsGatherer(registry),
+		WithMetricsAttributes(attribute.String("service.name", "openfga")),
+	)
+	require.NoError(t, err)
+
+	// the metrics are exported one last time on close
+	require.NoError(t, exporter.Close(context.Background()))
+	req := <-requests
+
+	require.Len(t, req.GetResourceMetrics(), 1)
+	resourceMetrics := req.GetResourceMetrics()[0]
+	require.Equal(t, "service.name", resourceMetrics.GetResource().GetAttributes()[0].GetKey())
+	require.Equal(t, "openfga", resourceMetrics.GetResource().GetAttributes()[0].GetValue().GetStringValue())
+
+	metrics := map[string]*metricspb.Metric{}
+	for _, metric := range resourceMetrics.GetScopeMetrics()[0].GetMetrics() {
+		metrics[metric.GetName()] = metric
+	}
+
+	sum := metrics["test_count"].GetSum()
+	require.True(t, sum.GetIsMonotonic())
+	require.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.GetAggregationTemporality())
+	require.InDelta(t, 3, sum.GetDataPoints()[0].GetAsDouble(), 0)
+	require.Equal(t, "method", sum.GetDataPoints()[0].GetAttributes()[0].GetKey())
+	require.Equal(t, "Check", sum.GetDataPoints()[0].GetAttributes()[0].GetValue().GetStringValue())
+	// the sums start when the counters were created, not when the exporter was
+	require.Less(t, sum.GetDataPoints()[0].GetStartTimeUnixNano(), uint64(beforeExporter.UnixNano()))
+
+	point := metrics["test_duration_ms"].GetHistogram().GetDataPoints()[0]
+	require.Equal(t, uint64(3), point.GetCount())
+	require.InDelta(t, 555, point.GetSum(), 0)
+	require.Equal(t, []float64{10, 100}, point.GetExplicitBounds())
+	require.Equal(t, []uint64{1, 1, 1}, point.GetBucketCounts())
+}
+
+func TestMetricsExporterUnsupportedProtocol(t *testing.T) {
+	_, err := NewMetricsExporter(WithMetricsOTLPProtocol("thrift"))
+	require.EqualError(t, err, "unsupported otlp metrics protocol 'thrift'")
+}
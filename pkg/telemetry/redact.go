@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"slices"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+
+	"github.com/openfga/openfga/pkg/redact"
+)
+
+// redactSpanProcessor forwards the spans to its SpanProcessor with the ids of their status description and of the
+// messages of their exception events redacted, when the redaction is enabled.
+type redactSpanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+// OnEnd implements [sdktrace.SpanProcessor].
+func (p redactSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if redact.Enabled() {
+		s = redactedSpan{ReadOnlySpan: s}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// redactedSpan redacts the status description and the exception messages of a span.
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s redactedSpan) Status() sdktrace.Status {
+	status := s.ReadOnlySpan.Status()
+	status.Description = redact.Text(status.Description)
+	return status
+}
+
+func (s redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	redacted := make([]sdktrace.Event, len(events))
+	for i, event := range events {
+		// the attributes are shared with the span, so they are copied
+		event.Attributes = slices.Clone(event.Attributes)
+		for j, attr := range event.Attributes {
+			if attr.Key == semconv.ExceptionMessageKey {
+				event.Attributes[j] = semconv.ExceptionMessageKey.String(redact.Text(attr.Value.AsString()))
+			}
+		}
+		redacted[i] = event
+	}
+	return redacted
+}
@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+
+	"github.com/openfga/openfga/pkg/redact"
+)
+
+func TestRedactSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(redactSpanProcessor{SpanProcessor: recorder}))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+
+	endSpan := func() sdktrace.ReadOnlySpan {
+		_, span := tp.Tracer("test").Start(context.Background(), "openfga.v1.OpenFGAService/Check")
+		err := errors.New("rpc error: code = InvalidArgument desc = type 'document' of 'document:budget' is not defined")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+
+		ended := recorder.Ended()
+		return ended[len(ended)-1]
+	}
+
+	exceptionMessage := func(span sdktrace.ReadOnlySpan) string {
+		for _, attr := range span.Events()[0].Attributes {
+			if attr.Key == semconv.ExceptionMessageKey {
+				return attr.Value.AsString()
+			}
+		}
+		return ""
+	}
+
+	t.Run("off", func(t *testing.T) {
+		redact.Configure(redact.ModeOff, "")
+
+		span := endSpan()
+		require.Contains(t, span.Status().Description, "'document:budget'")
+		require.Contains(t, exceptionMessage(span), "'document:budget'")
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		redact.Configure(redact.ModeDrop, "")
+		t.Cleanup(func() {
+			redact.Configure(redact.ModeOff, "")
+		})
+
+		span := endSpan()
+		require.Equal(t, codes.Error, span.Status().Code)
+		require.Contains(t, span.Status().Description, "'document:?'")
+		require.Contains(t, exceptionMessage(span), "'document:?'")
+		require.NotContains(t, exceptionMessage(span), "budget")
+	})
+}
@@ -0,0 +1,149 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// methodSampler samples the traces of the API methods with the ratio of their method, or with a default ratio. A
+// method is either an exact name, e.g. 'Check', or a prefix ending with '*', e.g. 'Write*', the exact names and then
+// the longest prefixes taking precedence.
+//
+// The decision is taken by the root spans and the spans with a remote parent, e.g. the gRPC server spans, and followed
+// by their local children. As the ratios are applied to the trace id, a trace sampled with a ratio is also sampled
+// with any greater ratio, so the spans of the HTTP gateway and of the gRPC method they call agree whenever the ratio of
+// the method is greater than the default ratio.
+//
+// If recordUnsampled is set, the spans of unsampled traces are recorded rather than dropped, so that
+// [errorSpanProcessor] can export those that fail.
+type methodSampler struct {
+	defaultSampler  sdktrace.Sampler
+	methods         map[string]sdktrace.Sampler
+	prefixes        []methodPrefixSampler
+	recordUnsampled bool
+}
+
+type methodPrefixSampler struct {
+	prefix  string
+	sampler sdktrace.Sampler
+}
+
+var _ sdktrace.Sampler = (*methodSampler)(nil)
+
+func newMethodSampler(defaultRatio float64, methodRatios map[string]float64, recordUnsampled bool) *methodSampler {
+	s := &methodSampler{
+		defaultSampler:  sdktrace.TraceIDRatioBased(defaultRatio),
+		methods:         make(map[string]sdktrace.Sampler, len(methodRatios)),
+		recordUnsampled: recordUnsampled,
+	}
+	for method, ratio := range methodRatios {
+		if prefix, ok := strings.CutSuffix(method, "*"); ok {
+			s.prefixes = append(s.prefixes, methodPrefixSampler{prefix: prefix, sampler: sdktrace.TraceIDRatioBased(ratio)})
+			continue
+		}
+		s.methods[method] = sdktrace.TraceIDRatioBased(ratio)
+	}
+	sort.Slice(s.prefixes, func(i, j int) bool {
+		return len(s.prefixes[i].prefix) > len(s.prefixes[j].prefix)
+	})
+	return s
+}
+
+// ShouldSample implements [sdktrace.Sampler].
+func (s *methodSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanContextFromContext(p.ParentContext)
+
+	var result sdktrace.SamplingResult
+	switch {
+	case parent.IsValid() && !parent.IsRemote():
+		result = sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: parent.TraceState()}
+		if parent.IsSampled() {
+			result.Decision = sdktrace.RecordAndSample
+		}
+	default:
+		result = s.samplerOf(methodOf(p)).ShouldSample(p)
+	}
+
+	if result.Decision == sdktrace.Drop && s.recordUnsampled {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+// Description implements [sdktrace.Sampler].
+func (s *methodSampler) Description() string {
+	methods := make([]string, 0, len(s.methods)+len(s.prefixes))
+	for method, sampler := range s.methods {
+		methods = append(methods, method+":"+sampler.Description())
+	}
+	for _, prefix := range s.prefixes {
+		methods = append(methods, prefix.prefix+"*:"+prefix.sampler.Description())
+	}
+	sort.Strings(methods)
+	return fmt.Sprintf("MethodSampler{default:%s,methods:[%s],recordUnsampled:%t}",
+		s.defaultSampler.Description(), strings.Join(methods, ","), s.recordUnsampled)
+}
+
+func (s *methodSampler) samplerOf(method string) sdktrace.Sampler {
+	if method == "" {
+		return s.defaultSampler
+	}
+	if sampler, ok := s.methods[method]; ok {
+		return sampler
+	}
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(method, prefix.prefix) {
+			return prefix.sampler
+		}
+	}
+	return s.defaultSampler
+}
+
+// methodOf returns the API method of the span, from its 'rpc.method' attribute or else from its name if it is a full
+// gRPC method name, e.g. 'openfga.v1.OpenFGAService/Check'.
+func methodOf(p sdktrace.SamplingParameters) string {
+	for _, attr := range p.Attributes {
+		if attr.Key == semconv.RPCMethodKey && attr.Value.Type() == attribute.STRING {
+			return attr.Value.AsString()
+		}
+	}
+	if _, method, ok := strings.Cut(p.Name, "/"); ok {
+		return method
+	}
+	return ""
+}
+
+// errorSpanProcessor forwards the spans of sampled traces to its SpanProcessor, and also the failed spans of
+// unsampled traces, which are recorded but not sampled by a [methodSampler] with recordUnsampled set. Only the failed
+// spans of unsampled traces are exported, not the whole traces.
+type errorSpanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+// OnEnd implements [sdktrace.SpanProcessor].
+func (p errorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		if s.Status().Code != codes.Error {
+			return
+		}
+		s = sampledSpan{ReadOnlySpan: s}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// sampledSpan marks a recorded span as sampled, so that it is exported.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
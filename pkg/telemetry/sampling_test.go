@@ -0,0 +1,109 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMethodSampler(t *testing.T) {
+	newTracer := func(t *testing.T, sampleErrors bool) (trace.Tracer, *tracetest.InMemoryExporter) {
+		exporter := tracetest.NewInMemoryExporter()
+		var processor sdktrace.SpanProcessor = sdktrace.NewSimpleSpanProcessor(exporter)
+		if sampleErrors {
+			processor = errorSpanProcessor{SpanProcessor: processor}
+		}
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(newMethodSampler(0, map[string]float64{
+				"Check":      0,
+				"Write*":     1,
+				"WriteAsser": 0,
+				"WriteAsse*": 0,
+			}, sampleErrors)),
+			sdktrace.WithSpanProcessor(processor),
+		)
+		t.Cleanup(func() {
+			_ = tp.Shutdown(context.Background())
+		})
+		return tp.Tracer("test"), exporter
+	}
+
+	startRPC := func(tracer trace.Tracer, method string) (context.Context, trace.Span) {
+		return tracer.Start(context.Background(), "openfga.v1.OpenFGAService/"+method,
+			trace.WithAttributes(semconv.RPCMethodKey.String(method)))
+	}
+
+	t.Run("samples_by_method", func(t *testing.T) {
+		tracer, exporter := newTracer(t, false)
+
+		for _, method := range []string{"Check", "Write", "WriteAuthorizationModel", "WriteAssertions", "ListObjects"} {
+			_, span := startRPC(tracer, method)
+			span.End()
+		}
+
+		var names []string
+		for _, span := range exporter.GetSpans() {
+			names = append(names, span.Name)
+		}
+		require.Equal(t, []string{
+			"openfga.v1.OpenFGAService/Write",
+			"openfga.v1.OpenFGAService/WriteAuthorizationModel",
+		}, names)
+	})
+
+	t.Run("local_children_follow_their_parent", func(t *testing.T) {
+		tracer, exporter := newTracer(t, false)
+
+		ctx, span := startRPC(tracer, "Write")
+		_, child := tracer.Start(ctx, "Check")
+		child.End()
+		span.End()
+
+		ctx, span = startRPC(tracer, "Check")
+		_, child = tracer.Start(ctx, "openfga.v1.OpenFGAService/Write")
+		child.End()
+		span.End()
+
+		require.Len(t, exporter.GetSpans(), 2)
+		for _, span := range exporter.GetSpans() {
+			require.True(t, span.SpanContext.IsSampled())
+		}
+	})
+
+	t.Run("exports_the_failed_spans_of_unsampled_traces", func(t *testing.T) {
+		tracer, exporter := newTracer(t, true)
+
+		ctx, span := startRPC(tracer, "Check")
+		require.True(t, span.IsRecording())
+		require.False(t, span.SpanContext().IsSampled())
+
+		_, child := tracer.Start(ctx, "resolver")
+		child.End()
+		TraceError(span, errors.New("boom"))
+		span.End()
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		require.Equal(t, "openfga.v1.OpenFGAService/Check", spans[0].Name)
+		require.Equal(t, codes.Error, spans[0].Status.Code)
+		require.True(t, spans[0].SpanContext.IsSampled())
+	})
+
+	t.Run("drops_unsampled_traces_without_error_sampling", func(t *testing.T) {
+		tracer, exporter := newTracer(t, false)
+
+		_, span := startRPC(tracer, "Check")
+		require.False(t, span.IsRecording())
+		TraceError(span, errors.New("boom"))
+		span.End()
+
+		require.Empty(t, exporter.GetSpans())
+	})
+}
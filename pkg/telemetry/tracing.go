@@ -16,6 +16,8 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+
+	"github.com/openfga/openfga/pkg/redact"
 )
 
 type TracerOption func(d *customTracer)
@@ -38,6 +40,22 @@ func WithSamplingRatio(samplingRatio float64) TracerOption {
 	}
 }
 
+// WithMethodSamplingRatios sets the fraction of the traces of specific API methods to sample, overriding the sampling
+// ratio, keyed by method name (e.g. 'Check') or by method name prefix ending with '*' (e.g. 'Write*').
+func WithMethodSamplingRatios(ratios map[string]float64) TracerOption {
+	return func(d *customTracer) {
+		d.methodSamplingRatios = ratios
+	}
+}
+
+// WithErrorSampling exports the failed spans of the traces that are not sampled. The spans of all the traces are then
+// recorded, which costs some CPU and memory even if they are not exported.
+func WithErrorSampling() TracerOption {
+	return func(d *customTracer) {
+		d.sampleErrors = true
+	}
+}
+
 func WithAttributes(attrs ...attribute.KeyValue) TracerOption {
 	return func(d *customTracer) {
 		d.attributes = attrs
@@ -49,7 +67,9 @@ type customTracer struct {
 	insecure   bool
 	attributes []attribute.KeyValue
 
-	samplingRatio float64
+	samplingRatio        float64
+	methodSamplingRatios map[string]float64
+	sampleErrors         bool
 }
 
 func MustNewTracerProvider(opts ...TracerOption) *sdktrace.TracerProvider {
@@ -91,10 +111,17 @@ func MustNewTracerProvider(opts ...TracerOption) *sdktrace.TracerProvider {
 		panic(fmt.Sprintf("failed to establish a connection with the otlp exporter: %v", err))
 	}
 
+	var processor sdktrace.SpanProcessor = sdktrace.NewBatchSpanProcessor(exp)
+	if tracer.sampleErrors {
+		processor = errorSpanProcessor{SpanProcessor: processor}
+	}
+	// the statuses and errors recorded by the instrumentations, e.g. otelgrpc, may contain ids
+	processor = redactSpanProcessor{SpanProcessor: processor}
+
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(tracer.samplingRatio)),
+		sdktrace.WithSampler(newMethodSampler(tracer.samplingRatio, tracer.methodSamplingRatios, tracer.sampleErrors)),
 		sdktrace.WithResource(res),
-		sdktrace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(exp)),
+		sdktrace.WithSpanProcessor(processor),
 	)
 
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
@@ -110,6 +137,7 @@ func TraceError(span trace.Span, err error) {
 	if errors.Is(err, context.Canceled) {
 		return
 	}
+	err = redact.Error(err)
 	span.RecordError(err)
 	span.SetStatus(codes.Error, err.Error())
 }
@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/internal/condition"
 	"github.com/openfga/openfga/pkg/storage"
 )
 
@@ -24,6 +28,55 @@ const (
 
 type TypesystemResolverFunc func(ctx context.Context, storeID, modelID string) (*TypeSystem, error)
 
+// SharedModelCache is a cache shared by the servers, e.g. Redis, in which the memoized TypesystemResolverFunc stores
+// the models it validated, so that the other servers don't validate them again.
+type SharedModelCache interface {
+	// Get returns the value of key, or nil if key is not in the cache.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set sets the value of key, expiring after ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+type memoizedResolverConfig struct {
+	ttl         time.Duration
+	storeTTLs   map[string]time.Duration
+	size        int64
+	sharedCache SharedModelCache
+}
+
+// MemoizedResolverOption configures the cache of a memoized TypesystemResolverFunc.
+type MemoizedResolverOption func(*memoizedResolverConfig)
+
+// WithCacheTTL sets how long the validated models are cached. Defaults to 7 days.
+func WithCacheTTL(ttl time.Duration) MemoizedResolverOption {
+	return func(c *memoizedResolverConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithCacheStoreTTLs overrides the time the validated models of some stores are cached, keyed by store id.
+func WithCacheStoreTTLs(ttls map[string]time.Duration) MemoizedResolverOption {
+	return func(c *memoizedResolverConfig) {
+		c.storeTTLs = ttls
+	}
+}
+
+// WithCacheSize sets the maximum number of validated models cached. Defaults to that of [storage.InMemoryLRUCache].
+func WithCacheSize(size int64) MemoizedResolverOption {
+	return func(c *memoizedResolverConfig) {
+		c.size = size
+	}
+}
+
+// WithSharedCache makes the validated models also be cached in cache, keyed by store and model id, and read from
+// it before the datastore. Reading or writing the shared cache never fails a resolution, which falls back to the
+// datastore. A model deleted from the datastore may still be resolved from the shared cache until it expires.
+func WithSharedCache(cache SharedModelCache) MemoizedResolverOption {
+	return func(c *memoizedResolverConfig) {
+		c.sharedCache = cache
+	}
+}
+
 // MemoizedTypesystemResolverFunc does several things.
 //
 // If given a model ID: validates the model ID, and tries to fetch it from the cache.
@@ -31,13 +84,70 @@ type TypesystemResolverFunc func(ctx context.Context, storeID, modelID string) (
 //
 // If not given a model ID: fetches the latest model ID from the datastore, then sees if the model ID is in the cache.
 // If it is, returns it. Else, validates it and returns it.
-func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBackend) (TypesystemResolverFunc, func(), error) {
+//
+// The models found to be invalid are also cached, so that they are not validated again.
+func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBackend, opts ...MemoizedResolverOption) (TypesystemResolverFunc, func(), error) {
+	resolver, _, stop, err := MemoizedTypesystemResolverFuncWithInvalidation(datastore, opts...)
+	return resolver, stop, err
+}
+
+// MemoizedTypesystemResolverFuncWithInvalidation is MemoizedTypesystemResolverFunc, which also returns a function
+// invalidating the models of a store in cache, so that they are read again from the datastore.
+func MemoizedTypesystemResolverFuncWithInvalidation(datastore storage.AuthorizationModelReadBackend, opts ...MemoizedResolverOption) (TypesystemResolverFunc, func(storeID string), func(), error) {
+	cfg := &memoizedResolverConfig{ttl: typesystemCacheTTL}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	ttl := func(storeID string) time.Duration {
+		if storeTTL, ok := cfg.storeTTLs[storeID]; ok {
+			return storeTTL
+		}
+		return cfg.ttl
+	}
+
 	lookupGroup := singleflight.Group{}
 
+	// generations holds the generation of the cache keys of each invalidated store, as the cache cannot delete keys
+	// by prefix. The entries of the previous generations are evicted over time.
+	var generations sync.Map
+	generation := func(storeID string) uint64 {
+		if gen, ok := generations.Load(storeID); ok {
+			return gen.(*atomic.Uint64).Load()
+		}
+		return 0
+	}
+	invalidate := func(storeID string) {
+		gen, _ := generations.LoadOrStore(storeID, &atomic.Uint64{})
+		gen.(*atomic.Uint64).Add(1)
+	}
+
+	// programs holds the compiled conditions of the models in cache, which are evicted along with their model.
+	programs := condition.NewProgramCache()
+
 	// cache holds models that have already been validated.
-	cache, err := storage.NewInMemoryLRUCache[*TypeSystem]()
+	cacheOpts := []storage.InMemoryLRUCacheOpt[*TypeSystem]{
+		storage.WithRemovalListener(func(_ string, typesys *TypeSystem) {
+			programs.Evict(typesys.GetAuthorizationModelID())
+		}),
+	}
+	if cfg.size > 0 {
+		cacheOpts = append(cacheOpts, storage.WithMaxCacheSize[*TypeSystem](cfg.size))
+	}
+	cache, err := storage.NewInMemoryLRUCache[*TypeSystem](cacheOpts...)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	// invalidModels holds the validation errors of the models found to be invalid. As models are immutable, they
+	// never become valid.
+	invalidModels, err := storage.NewInMemoryLRUCache[error]()
+	if err != nil {
+		cache.Stop()
+		return nil, nil, nil, err
+	}
+	stop := func() {
+		cache.Stop()
+		invalidModels.Stop()
 	}
 
 	return func(ctx context.Context, storeID, modelID string) (*TypeSystem, error) {
@@ -75,12 +185,29 @@ func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBack
 			modelID = model.GetId()
 		}
 
-		key = fmt.Sprintf("%s/%s", storeID, modelID)
+		key = fmt.Sprintf("%s/%d/%s", storeID, generation(storeID), modelID)
 		item := cache.Get(key)
 		if item != nil {
 			return item, nil
 		}
 
+		if err := invalidModels.Get(key); err != nil {
+			return nil, err
+		}
+
+		sharedKey := sharedModelCacheKey(storeID, modelID)
+		if cfg.sharedCache != nil {
+			if shared := readSharedModel(ctx, cfg.sharedCache, sharedKey); shared != nil {
+				typesys, err := New(shared)
+				if err == nil {
+					typesys.withProgramCache(programs)
+					span.SetAttributes(attribute.Bool("shared_cache_hit", true))
+					cache.Set(key, typesys, ttl(storeID))
+					return typesys, nil
+				}
+			}
+		}
+
 		if model == nil {
 			v, err, _ := lookupGroup.Do(fmt.Sprintf("ReadAuthorizationModel:%s/%s", storeID, modelID), func() (interface{}, error) {
 				return datastore.ReadAuthorizationModel(ctx, storeID, modelID)
@@ -96,13 +223,53 @@ func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBack
 			model = v.(*openfgav1.AuthorizationModel)
 		}
 
-		typesys, err := NewAndValidate(ctx, model)
+		typesys, err := NewAndValidate(condition.ContextWithProgramCache(ctx, programs), model)
 		if err != nil {
-			return nil, fmt.Errorf("%w: %v", ErrInvalidModel, err)
+			err = fmt.Errorf("%w: %v", ErrInvalidModel, err)
+			invalidModels.Set(key, err, ttl(storeID))
+			return nil, err
 		}
 
-		cache.Set(key, typesys, typesystemCacheTTL)
+		cache.Set(key, typesys, ttl(storeID))
+		if cfg.sharedCache != nil {
+			writeSharedModel(ctx, cfg.sharedCache, sharedKey, model, ttl(storeID))
+		}
 
 		return typesys, nil
-	}, cache.Stop, nil
+	}, invalidate, stop, nil
+}
+
+// sharedModelCacheKey returns the key of the model of a store in a SharedModelCache.
+func sharedModelCacheKey(storeID, modelID string) string {
+	return fmt.Sprintf("openfga/authorization_model/%s/%s", storeID, modelID)
+}
+
+// readSharedModel returns the model of key in cache, or nil if it isn't in the cache or cannot be read.
+func readSharedModel(ctx context.Context, cache SharedModelCache, key string) *openfgav1.AuthorizationModel {
+	data, err := cache.Get(ctx, key)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+
+	model := &openfgav1.AuthorizationModel{}
+	if err := proto.Unmarshal(data, model); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return nil
+	}
+	return model
+}
+
+// writeSharedModel writes a validated model to cache.
+func writeSharedModel(ctx context.Context, cache SharedModelCache, key string, model *openfgav1.AuthorizationModel, ttl time.Duration) {
+	data, err := proto.Marshal(model)
+	if err == nil {
+		err = cache.Set(ctx, key, data, ttl)
+	}
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
 }
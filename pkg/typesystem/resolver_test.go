@@ -3,6 +3,7 @@ package typesystem
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/stretchr/testify/require"
@@ -188,6 +189,148 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 		require.Equal(t, modelID, typesys.GetAuthorizationModelID())
 	})
 
+	t.Run("invalidated_store_reads_model_again", func(t *testing.T) {
+		store := ulid.Make().String()
+		otherStore := ulid.Make().String()
+		modelID := ulid.Make().String()
+		model := &openfgav1.AuthorizationModel{
+			Id:            modelID,
+			SchemaVersion: SchemaVersion1_1,
+		}
+
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), store, modelID).Return(model, nil).Times(2)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), otherStore, modelID).Return(model, nil).Times(1)
+
+		resolver, invalidate, resolverStop, err := MemoizedTypesystemResolverFuncWithInvalidation(mockDatastore)
+		require.NoError(t, err)
+		defer resolverStop()
+
+		for _, storeID := range []string{store, otherStore} {
+			_, err = resolver(context.Background(), storeID, modelID)
+			require.NoError(t, err)
+		}
+
+		// only the model of the invalidated store is read again, as asserted by the Times above
+		invalidate(store)
+		for _, storeID := range []string{store, otherStore} {
+			_, err = resolver(context.Background(), storeID, modelID)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("store_ttl_overrides_the_cache_ttl", func(t *testing.T) {
+		store := ulid.Make().String()
+		otherStore := ulid.Make().String()
+		modelID := ulid.Make().String()
+		model := &openfgav1.AuthorizationModel{
+			Id:            modelID,
+			SchemaVersion: SchemaVersion1_1,
+		}
+
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), store, modelID).Return(model, nil).Times(2)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), otherStore, modelID).Return(model, nil).Times(1)
+
+		resolver, resolverStop, err := MemoizedTypesystemResolverFunc(mockDatastore,
+			WithCacheTTL(time.Hour),
+			WithCacheStoreTTLs(map[string]time.Duration{store: 10 * time.Millisecond}),
+			WithCacheSize(10),
+		)
+		require.NoError(t, err)
+		defer resolverStop()
+
+		for _, storeID := range []string{store, otherStore} {
+			_, err = resolver(context.Background(), storeID, modelID)
+			require.NoError(t, err)
+		}
+
+		// only the model of the store with the short TTL expired, as asserted by the Times above
+		time.Sleep(50 * time.Millisecond)
+		for _, storeID := range []string{store, otherStore} {
+			_, err = resolver(context.Background(), storeID, modelID)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("invalid_model_is_not_validated_again", func(t *testing.T) {
+		store := ulid.Make().String()
+		modelID := ulid.Make().String()
+
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), store, modelID).
+			Return(&openfgav1.AuthorizationModel{Id: modelID, SchemaVersion: "0.9"}, nil).
+			Times(1)
+
+		resolver, resolverStop, err := MemoizedTypesystemResolverFunc(mockDatastore)
+		require.NoError(t, err)
+		defer resolverStop()
+
+		_, err = resolver(context.Background(), store, modelID)
+		require.ErrorIs(t, err, ErrInvalidModel)
+
+		// second call from the cache of invalid models asserted by the Times(1) above
+		_, err = resolver(context.Background(), store, modelID)
+		require.ErrorIs(t, err, ErrInvalidModel)
+	})
+
+	t.Run("shared_cache_is_read_before_the_datastore", func(t *testing.T) {
+		store := ulid.Make().String()
+		model := testutils.MustTransformDSLToProtoWithID(`
+			model
+				schema 1.1
+
+			type user
+			type doc
+				relations
+					define viewer: [user with is_public]
+
+			condition is_public(public: bool) {
+				public
+			}`)
+		sharedCache := &mapSharedModelCache{values: make(map[string][]byte)}
+
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), store, model.GetId()).
+			Return(model, nil).
+			Times(1)
+
+		resolver, resolverStop, err := MemoizedTypesystemResolverFunc(mockDatastore, WithSharedCache(sharedCache))
+		require.NoError(t, err)
+		defer resolverStop()
+
+		_, err = resolver(context.Background(), store, model.GetId())
+		require.NoError(t, err)
+		require.Len(t, sharedCache.values, 1)
+
+		// e.g. another server, which doesn't read the model asserted by the Times(1) above
+		otherResolver, otherResolverStop, err := MemoizedTypesystemResolverFunc(mockDatastore, WithSharedCache(sharedCache))
+		require.NoError(t, err)
+		defer otherResolverStop()
+
+		typesys, err := otherResolver(context.Background(), store, model.GetId())
+		require.NoError(t, err)
+		require.Equal(t, model.GetId(), typesys.GetAuthorizationModelID())
+
+		typeRestrictions, err := typesys.GetDirectlyRelatedUserTypes("doc", "viewer")
+		require.NoError(t, err)
+		require.Equal(t, "is_public", typeRestrictions[0].GetCondition())
+		_, ok := typesys.GetConditions()["is_public"]
+		require.True(t, ok)
+	})
+
 	t.Run("two_calls_without_model_id_returns_second_from_cache", func(t *testing.T) {
 		store := ulid.Make().String()
 		model := testutils.MustTransformDSLToProtoWithID(`
@@ -259,3 +402,17 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 		require.Equal(t, modelTwo.GetId(), typesys.GetAuthorizationModelID())
 	})
 }
+
+// mapSharedModelCache is a SharedModelCache ignoring expirations.
+type mapSharedModelCache struct {
+	values map[string][]byte
+}
+
+func (c *mapSharedModelCache) Get(_ context.Context, key string) ([]byte, error) {
+	return c.values[key], nil
+}
+
+func (c *mapSharedModelCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.values[key] = value
+	return nil
+}
@@ -172,6 +172,8 @@ type TypeSystem struct {
 	conditions map[string]*condition.EvaluableCondition
 	// [objectType] => [relationName] => TTU relation.
 	ttuRelations map[string]map[string][]*openfgav1.TupleToUserset
+	// [objectType] => [alias] => the relation the alias resolves to.
+	relationAliases map[string]map[string]string
 
 	computedRelations sync.Map
 
@@ -243,6 +245,7 @@ func New(model *openfgav1.AuthorizationModel) (*TypeSystem, error) {
 		relations:               relations,
 		conditions:              uncompiledConditions,
 		ttuRelations:            ttuRelations,
+		relationAliases:         resolveRelationAliases(relations),
 		authorizationModelGraph: authorizationModelGraph,
 		authzWeightedGraph:      weightedGraph,
 	}, nil
@@ -339,6 +342,59 @@ func (t *TypeSystem) GetRelation(objectType, relation string) (*openfgav1.Relati
 	return r, nil
 }
 
+// ResolveRelationAlias returns the relation that the relation of objectType is an alias of, or the relation itself
+// if it is not an alias. A relation is an alias when it is solely defined as another relation of the same type
+// (e.g. `define can_view: viewer`). Aliases are resolved when the TypeSystem is created, so that evaluating an alias
+// costs no more than evaluating the relation it aliases. It is used by the Check, ListObjects and ListUsers
+// resolution, whose results do not depend on it; Expand returns aliases as computed usersets.
+func (t *TypeSystem) ResolveRelationAlias(objectType, relation string) string {
+	if target, ok := t.relationAliases[objectType][relation]; ok {
+		return target
+	}
+	return relation
+}
+
+// resolveRelationAliases returns, for each type, its relations that are aliases mapped to the relation they
+// ultimately resolve to, following chains of aliases.
+func resolveRelationAliases(relations map[string]map[string]*openfgav1.Relation) map[string]map[string]string {
+	aliases := make(map[string]map[string]string, len(relations))
+	for objectType, typeRelations := range relations {
+		for name := range typeRelations {
+			target, ok := aliasTarget(typeRelations, name)
+			if !ok {
+				continue
+			}
+			if aliases[objectType] == nil {
+				aliases[objectType] = map[string]string{}
+			}
+			aliases[objectType][name] = target
+		}
+	}
+	return aliases
+}
+
+// aliasTarget returns the relation that name ultimately resolves to, and false if name is not an alias or its
+// chain of aliases is broken, e.g. by a cycle or an undefined relation.
+func aliasTarget(relations map[string]*openfgav1.Relation, name string) (string, bool) {
+	seen := map[string]struct{}{name: {}}
+	target := name
+	for {
+		next := relations[target].GetRewrite().GetComputedUserset().GetRelation()
+		if next == "" {
+			break
+		}
+		if _, ok := relations[next]; !ok {
+			return "", false
+		}
+		if _, ok := seen[next]; ok {
+			return "", false
+		}
+		seen[next] = struct{}{}
+		target = next
+	}
+	return target, target != name
+}
+
 // GetCondition searches for an EvaluableCondition in the TypeSystem by its name.
 func (t *TypeSystem) GetCondition(name string) (*condition.EvaluableCondition, bool) {
 	if _, ok := t.conditions[name]; !ok {
@@ -1304,6 +1360,9 @@ func NewAndValidate(ctx context.Context, model *openfgav1.AuthorizationModel) (*
 	if err != nil {
 		return nil, err
 	}
+	if programs, ok := condition.ProgramCacheFromContext(ctx); ok {
+		t.withProgramCache(programs)
+	}
 	schemaVersion := t.GetSchemaVersion()
 
 	if !IsSchemaVersionSupported(schemaVersion) {
@@ -1355,6 +1414,17 @@ func NewAndValidate(ctx context.Context, model *openfgav1.AuthorizationModel) (*
 	return t, nil
 }
 
+// withProgramCache makes the conditions of the model reuse the programs compiled for those of the same name of the
+// model, if it has an id.
+func (t *TypeSystem) withProgramCache(programs *condition.ProgramCache) {
+	if t.GetAuthorizationModelID() == "" {
+		return
+	}
+	for _, c := range t.conditions {
+		c.WithProgramCache(programs, t.GetAuthorizationModelID())
+	}
+}
+
 // validateRelation applies all the validation rules to a relation definition in a model. A relation
 // must meet all the rewrite validation, type restriction validation, and entrypoint validation criteria
 // for it to be valid. Otherwise, an error is returned.
@@ -11,6 +11,7 @@ import (
 	"github.com/openfga/language/pkg/go/graph"
 	parser "github.com/openfga/language/pkg/go/transformer"
 
+	"github.com/openfga/openfga/internal/condition"
 	"github.com/openfga/openfga/pkg/testutils"
 	"github.com/openfga/openfga/pkg/tuple"
 )
@@ -1029,6 +1030,48 @@ func TestResolveComputedRelation(t *testing.T) {
 	}
 }
 
+func TestResolveRelationAlias(t *testing.T) {
+	ts, err := New(testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type folder
+			relations
+				define viewer: [user]
+		type document
+			relations
+				define parent: [folder]
+				define viewer: [user]
+				define can_view: viewer
+				define can_read: can_view
+				define can_view_parent: viewer from parent
+				define can_edit: [user] or viewer
+				define cycle_a: cycle_b
+				define cycle_b: cycle_a`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		objectType string
+		relation   string
+		expected   string
+	}{
+		{"document", "can_view", "viewer"},
+		{"document", "can_read", "viewer"},
+		{"document", "viewer", "viewer"},
+		{"document", "can_view_parent", "can_view_parent"},
+		{"document", "can_edit", "can_edit"},
+		{"document", "cycle_a", "cycle_a"},
+		{"document", "undefined", "undefined"},
+		{"folder", "viewer", "viewer"},
+		{"undefined", "can_view", "can_view"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.objectType+"#"+tt.relation, func(t *testing.T) {
+			require.Equal(t, tt.expected, ts.ResolveRelationAlias(tt.objectType, tt.relation))
+		})
+	}
+}
+
 func TestHasCycle(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -7097,3 +7140,161 @@ func BenchmarkNewAndValidate(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    string
+		expected []Warning
+	}{
+		{
+			name: "no_warnings",
+			model: `
+				model
+					schema 1.1
+				type user
+				type group
+					relations
+						define member: [user, group#member]
+				type folder
+					relations
+						define viewer: [user, group#member]
+				type document
+					relations
+						define parent: [folder]
+						define viewer: [user] or viewer from parent`,
+		},
+		{
+			name: "tuple_to_userset_through_a_type_not_defining_the_relation",
+			model: `
+				model
+					schema 1.1
+				type user
+				type org
+				type folder
+					relations
+						define viewer: [user]
+				type document
+					relations
+						define parent: [folder, org]
+						define viewer: viewer from parent`,
+			expected: []Warning{{
+				Kind:       WarningUnreachableRelation,
+				ObjectType: "document",
+				Relation:   "viewer",
+				Message:    "'viewer from parent' can never be satisfied through 'org', which does not define 'viewer'",
+			}},
+		},
+		{
+			name: "intersection_of_disjoint_user_types",
+			model: `
+				model
+					schema 1.1
+				type user
+				type org
+				type document
+					relations
+						define editor: [org]
+						define viewer: [user]
+						define can_view: viewer and editor`,
+			expected: []Warning{{
+				Kind:       WarningUnresolvableRelation,
+				ObjectType: "document",
+				Relation:   "can_view",
+				Message:    "no user can ever have this relation",
+			}},
+		},
+		{
+			name: "difference_subtracting_its_base",
+			model: `
+				model
+					schema 1.1
+				type user
+				type document
+					relations
+						define blocked: [user]
+						define viewer: blocked but not blocked`,
+			expected: []Warning{{
+				Kind:       WarningUnresolvableRelation,
+				ObjectType: "document",
+				Relation:   "viewer",
+				Message:    "no user can ever have this relation",
+			}},
+		},
+		{
+			name: "wildcard_on_a_relation_others_derive_from",
+			model: `
+				model
+					schema 1.1
+				type user
+				type document
+					relations
+						define viewer: [user, user:*]
+						define can_view: viewer
+						define owner: [user:*]`,
+			expected: []Warning{{
+				Kind:       WarningBroadWildcard,
+				ObjectType: "document",
+				Relation:   "viewer",
+				Message:    "'user:*' also grants every user the relations derived from this one: document#can_view",
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts, err := New(testutils.MustTransformDSLToProtoWithID(test.model))
+			require.NoError(t, err)
+			require.Equal(t, test.expected, ts.Warnings())
+		})
+	}
+
+	t.Run("diamond_shaped_rewrites", func(t *testing.T) {
+		// every relation is reached through 2^depth paths, which are resolved once
+		const depth = 64
+		dsl := `
+			model
+				schema 1.1
+			type user
+			type document
+				relations
+					define a0: [user]
+					define b0: [user]`
+		for i := 1; i <= depth; i++ {
+			dsl += fmt.Sprintf(`
+					define a%[1]d: a%[2]d or b%[2]d
+					define b%[1]d: a%[2]d and b%[2]d`, i, i-1)
+		}
+
+		ts, err := New(testutils.MustTransformDSLToProtoWithID(dsl))
+		require.NoError(t, err)
+		require.Empty(t, ts.Warnings())
+	})
+}
+
+func TestNewAndValidateWithProgramCache(t *testing.T) {
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define viewer: [user with in_region]
+
+		condition in_region(region: string) {
+			region == "eu"
+		}`)
+
+	programs := condition.NewProgramCache()
+	ctx := condition.ContextWithProgramCache(context.Background(), programs)
+
+	_, err := NewAndValidate(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, 1, programs.Len())
+
+	_, err = NewAndValidate(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, 1, programs.Len())
+}
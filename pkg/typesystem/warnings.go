@@ -0,0 +1,288 @@
+package typesystem
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// WarningKind is the kind of a non-fatal diagnostic of an authorization model.
+type WarningKind string
+
+const (
+	// WarningUnreachableRelation is reported for a tuple to userset rewrite going through a type that does not
+	// define the computed relation, so that the tuples of that type never grant anything through it.
+	WarningUnreachableRelation WarningKind = "unreachable_relation"
+	// WarningUnresolvableRelation is reported for a relation that no user can ever have, for instance the
+	// intersection of relations assignable to different user types.
+	WarningUnresolvableRelation WarningKind = "unresolvable_relation"
+	// WarningBroadWildcard is reported for a relation assignable to a public wildcard that other relations are
+	// derived from, so that the wildcard grants every user of the type these relations too.
+	WarningBroadWildcard WarningKind = "broad_wildcard"
+)
+
+// Warning is a non-fatal diagnostic of a relation of an authorization model.
+type Warning struct {
+	Kind       WarningKind
+	ObjectType string
+	Relation   string
+	Message    string
+}
+
+// String returns the warning as 'type#relation: message'.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", tuple.ToObjectRelationString(w.ObjectType, w.Relation), w.Message)
+}
+
+// Warnings returns the non-fatal diagnostics of the model, ordered by type and relation. Unlike the rules of
+// NewAndValidate, they do not make the model invalid, but likely point at mistakes in it.
+func (t *TypeSystem) Warnings() []Warning {
+	var warnings []Warning
+
+	derived := t.derivedRelations()
+	resolved := map[string]resolvedUserTypes{}
+
+	for _, objectType := range slices.Sorted(maps.Keys(t.relations)) {
+		for _, relationName := range slices.Sorted(maps.Keys(t.relations[objectType])) {
+			relation := t.relations[objectType][relationName]
+
+			warnings = append(warnings, t.unreachableTupleToUsersets(objectType, relationName)...)
+
+			userTypes, known := t.resolvableUserTypes(objectType, relationName, map[string]struct{}{}, resolved)
+			if known && len(userTypes) == 0 {
+				warnings = append(warnings, Warning{
+					Kind:       WarningUnresolvableRelation,
+					ObjectType: objectType,
+					Relation:   relationName,
+					Message:    "no user can ever have this relation",
+				})
+			}
+
+			for _, ref := range relation.GetTypeInfo().GetDirectlyRelatedUserTypes() {
+				from := derived[tuple.ToObjectRelationString(objectType, relationName)]
+				if ref.GetWildcard() == nil || len(from) == 0 {
+					continue
+				}
+				warnings = append(warnings, Warning{
+					Kind:       WarningBroadWildcard,
+					ObjectType: objectType,
+					Relation:   relationName,
+					Message: fmt.Sprintf("'%s' also grants every %s the relations derived from this one: %s",
+						tuple.TypedPublicWildcard(ref.GetType()), ref.GetType(), strings.Join(from, ", ")),
+				})
+			}
+		}
+	}
+
+	return warnings
+}
+
+// unreachableTupleToUsersets returns a warning for every tuple to userset rewrite of the relation going through a
+// type that does not define its computed relation.
+func (t *TypeSystem) unreachableTupleToUsersets(objectType, relation string) []Warning {
+	var warnings []Warning
+	for _, ttu := range t.ttuRelations[objectType][relation] {
+		tupleset := ttu.GetTupleset().GetRelation()
+		computed := ttu.GetComputedUserset().GetRelation()
+
+		seen := map[string]struct{}{}
+		for _, ref := range t.relations[objectType][tupleset].GetTypeInfo().GetDirectlyRelatedUserTypes() {
+			tuplesetType := ref.GetType()
+			if _, ok := seen[tuplesetType]; ok {
+				continue
+			}
+			seen[tuplesetType] = struct{}{}
+
+			if _, ok := t.relations[tuplesetType][computed]; !ok {
+				warnings = append(warnings, Warning{
+					Kind:       WarningUnreachableRelation,
+					ObjectType: objectType,
+					Relation:   relation,
+					Message: fmt.Sprintf("'%s from %s' can never be satisfied through '%s', which does not define '%s'",
+						computed, tupleset, tuplesetType, computed),
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// resolvedUserTypes is a result of resolvableUserTypes, memoized by 'type#relation'.
+type resolvedUserTypes struct {
+	userTypes map[string]struct{}
+	known     bool
+}
+
+// resolvableUserTypes returns the user types, such as 'user' or 'group#member', that may have the relation of the
+// object type. known is false if the result is a lower bound, because resolving it went through a cycle.
+//
+// The results are memoized in resolved, so that the relations shared by several rewrites are resolved once. The
+// relations resolving through a cycle do so whatever relation they are reached from, so that their results are
+// memoized as unknown too. The returned user types must not be modified.
+func (t *TypeSystem) resolvableUserTypes(objectType, relation string, visiting map[string]struct{}, resolved map[string]resolvedUserTypes) (map[string]struct{}, bool) {
+	key := tuple.ToObjectRelationString(objectType, relation)
+	if result, ok := resolved[key]; ok {
+		return result.userTypes, result.known
+	}
+	if _, ok := visiting[key]; ok {
+		return nil, false
+	}
+	visiting[key] = struct{}{}
+	defer delete(visiting, key)
+
+	r, ok := t.relations[objectType][relation]
+	if !ok {
+		return nil, true
+	}
+	userTypes, known := t.rewriteUserTypes(objectType, r, r.GetRewrite(), visiting, resolved)
+	resolved[key] = resolvedUserTypes{userTypes: userTypes, known: known}
+	return userTypes, known
+}
+
+func (t *TypeSystem) rewriteUserTypes(objectType string, relation *openfgav1.Relation, rewrite *openfgav1.Userset, visiting map[string]struct{}, resolved map[string]resolvedUserTypes) (map[string]struct{}, bool) {
+	userTypes := map[string]struct{}{}
+	known := true
+
+	switch rw := rewrite.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		for _, ref := range relation.GetTypeInfo().GetDirectlyRelatedUserTypes() {
+			if ref.GetRelation() == "" {
+				userTypes[ref.GetType()] = struct{}{}
+				continue
+			}
+			userTypes[tuple.ToObjectRelationString(ref.GetType(), ref.GetRelation())] = struct{}{}
+			nested, nestedKnown := t.resolvableUserTypes(ref.GetType(), ref.GetRelation(), visiting, resolved)
+			maps.Copy(userTypes, nested)
+			known = known && nestedKnown
+		}
+	case *openfgav1.Userset_ComputedUserset:
+		return t.resolvableUserTypes(objectType, rw.ComputedUserset.GetRelation(), visiting, resolved)
+	case *openfgav1.Userset_TupleToUserset:
+		tupleset := rw.TupleToUserset.GetTupleset().GetRelation()
+		computed := rw.TupleToUserset.GetComputedUserset().GetRelation()
+		for _, ref := range t.relations[objectType][tupleset].GetTypeInfo().GetDirectlyRelatedUserTypes() {
+			nested, nestedKnown := t.resolvableUserTypes(ref.GetType(), computed, visiting, resolved)
+			maps.Copy(userTypes, nested)
+			known = known && nestedKnown
+		}
+	case *openfgav1.Userset_Union:
+		for _, child := range rw.Union.GetChild() {
+			nested, nestedKnown := t.rewriteUserTypes(objectType, relation, child, visiting, resolved)
+			maps.Copy(userTypes, nested)
+			known = known && nestedKnown
+		}
+	case *openfgav1.Userset_Intersection:
+		first := true
+		for _, child := range rw.Intersection.GetChild() {
+			nested, nestedKnown := t.rewriteUserTypes(objectType, relation, child, visiting, resolved)
+			if !nestedKnown {
+				// The child may resolve to more user types than nested, so it cannot narrow the intersection.
+				known = false
+				continue
+			}
+			if first {
+				// nested may be memoized, it is narrowed in a copy
+				userTypes = maps.Clone(nested)
+				first = false
+				continue
+			}
+			maps.DeleteFunc(userTypes, func(userType string, _ struct{}) bool {
+				_, ok := nested[userType]
+				return !ok
+			})
+		}
+		if first {
+			return userTypes, false
+		}
+	case *openfgav1.Userset_Difference:
+		base := rw.Difference.GetBase()
+		if usersetsEqual(base, rw.Difference.GetSubtract()) {
+			return userTypes, true
+		}
+		return t.rewriteUserTypes(objectType, relation, base, visiting, resolved)
+	}
+
+	return userTypes, known
+}
+
+// usersetsEqual reports whether a and b are the same direct, computed or tuple to userset rewrite.
+func usersetsEqual(a, b *openfgav1.Userset) bool {
+	switch {
+	case a.GetThis() != nil:
+		return b.GetThis() != nil
+	case a.GetComputedUserset() != nil:
+		return b.GetComputedUserset() != nil && a.GetComputedUserset().GetRelation() == b.GetComputedUserset().GetRelation()
+	case a.GetTupleToUserset() != nil:
+		return b.GetTupleToUserset() != nil &&
+			a.GetTupleToUserset().GetTupleset().GetRelation() == b.GetTupleToUserset().GetTupleset().GetRelation() &&
+			a.GetTupleToUserset().GetComputedUserset().GetRelation() == b.GetTupleToUserset().GetComputedUserset().GetRelation()
+	default:
+		return false
+	}
+}
+
+// derivedRelations returns, for every 'type#relation', the sorted relations whose rewrite refers to it through a
+// computed userset, a tuple to userset or a directly related userset.
+func (t *TypeSystem) derivedRelations() map[string][]string {
+	derived := map[string][]string{}
+	add := func(from, to string) {
+		if !slices.Contains(derived[from], to) {
+			derived[from] = append(derived[from], to)
+		}
+	}
+
+	for objectType, relations := range t.relations {
+		for relationName, relation := range relations {
+			to := tuple.ToObjectRelationString(objectType, relationName)
+
+			for _, ref := range relation.GetTypeInfo().GetDirectlyRelatedUserTypes() {
+				if ref.GetRelation() != "" {
+					add(tuple.ToObjectRelationString(ref.GetType(), ref.GetRelation()), to)
+				}
+			}
+
+			walkUserset(relation.GetRewrite(), func(rewrite *openfgav1.Userset) {
+				switch rw := rewrite.GetUserset().(type) {
+				case *openfgav1.Userset_ComputedUserset:
+					if rw.ComputedUserset.GetRelation() != relationName {
+						add(tuple.ToObjectRelationString(objectType, rw.ComputedUserset.GetRelation()), to)
+					}
+				case *openfgav1.Userset_TupleToUserset:
+					tupleset := rw.TupleToUserset.GetTupleset().GetRelation()
+					for _, ref := range relations[tupleset].GetTypeInfo().GetDirectlyRelatedUserTypes() {
+						add(tuple.ToObjectRelationString(ref.GetType(), rw.TupleToUserset.GetComputedUserset().GetRelation()), to)
+					}
+				}
+			})
+		}
+	}
+
+	for from := range derived {
+		slices.Sort(derived[from])
+	}
+	return derived
+}
+
+// walkUserset calls fn on rewrite and every rewrite nested in it.
+func walkUserset(rewrite *openfgav1.Userset, fn func(*openfgav1.Userset)) {
+	fn(rewrite)
+
+	switch rw := rewrite.GetUserset().(type) {
+	case *openfgav1.Userset_Union:
+		for _, child := range rw.Union.GetChild() {
+			walkUserset(child, fn)
+		}
+	case *openfgav1.Userset_Intersection:
+		for _, child := range rw.Intersection.GetChild() {
+			walkUserset(child, fn)
+		}
+	case *openfgav1.Userset_Difference:
+		walkUserset(rw.Difference.GetBase(), fn)
+		walkUserset(rw.Difference.GetSubtract(), fn)
+	}
+}
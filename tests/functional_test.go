@@ -709,7 +709,9 @@ func GRPCReadTest(t *testing.T, client openfgav1.OpenFGAServiceClient) {
 			response, err := client.Read(context.Background(), test.input)
 			if test.err != nil {
 				require.Error(t, err)
-				assert.Equal(t, test.err, err)
+				// the details of the errors, e.g. their reason, are not compared
+				assert.Equal(t, status.Code(test.err), status.Code(err))
+				assert.Equal(t, status.Convert(test.err).Message(), status.Convert(err).Message())
 			} else {
 				require.NoError(t, err)
 				test.validate(t, response)
@@ -874,7 +876,9 @@ func GRPCReadChangesTest(t *testing.T, client openfgav1.OpenFGAServiceClient) {
 			response, err := client.ReadChanges(context.Background(), test.input)
 			if test.err != nil {
 				require.Error(t, err)
-				assert.Equal(t, test.err, err)
+				// the details of the errors, e.g. their reason, are not compared
+				assert.Equal(t, status.Code(test.err), status.Code(err))
+				assert.Equal(t, status.Convert(test.err).Message(), status.Convert(err).Message())
 			} else {
 				require.NoError(t, err)
 				test.validate(t, response)